@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/csvlinter/csvlinter/internal/cache"
+
+	"github.com/urfave/cli/v2"
+)
+
+var cacheCommand = &cli.Command{
+	Name:  "cache",
+	Usage: "Manage the validation result cache",
+	Subcommands: []*cli.Command{
+		{
+			Name:   "clean",
+			Usage:  "Remove all cached results",
+			Action: cacheCleanAction,
+		},
+		{
+			Name:   "dir",
+			Usage:  "Print the cache directory",
+			Action: cacheDirAction,
+		},
+	},
+}
+
+func cacheCleanAction(c *cli.Context) error {
+	dir, err := cache.DefaultDir()
+	if err != nil {
+		return cli.Exit(fmt.Sprintf("Error: %v", err), 1)
+	}
+	removed, err := cache.New(dir).Clean()
+	if err != nil {
+		return cli.Exit(fmt.Sprintf("Error: %v", err), 1)
+	}
+	fmt.Fprintf(c.App.Writer, "Removed %d cached result(s) from %s\n", removed, dir)
+	return nil
+}
+
+func cacheDirAction(c *cli.Context) error {
+	dir, err := cache.DefaultDir()
+	if err != nil {
+		return cli.Exit(fmt.Sprintf("Error: %v", err), 1)
+	}
+	fmt.Fprintln(c.App.Writer, dir)
+	return nil
+}
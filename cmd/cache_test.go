@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/cache"
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+func TestValidateCommand_Cache(t *testing.T) {
+	t.Setenv(cache.EnvDir, t.TempDir())
+	csvPath := filepath.Join(t.TempDir(), "data.csv")
+	if err := os.WriteFile(csvPath, []byte("id,name\n1,Alice\n2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	validate := func(args ...string) validator.Results {
+		t.Helper()
+		stdout, stderr, code := runApp(t, append([]string{"validate", "--format", "json"}, args...)...)
+		if code != 1 {
+			t.Fatalf("want exit 1, got %d; stderr=%s", code, stderr)
+		}
+		var res validator.Results
+		if err := json.Unmarshal([]byte(stdout), &res); err != nil {
+			t.Fatalf("invalid json output: %v\nstdout=%s", err, stdout)
+		}
+		return res
+	}
+
+	if res := validate(csvPath); res.Cached {
+		t.Fatal("first run should not be cached")
+	}
+	res := validate(csvPath)
+	if !res.Cached {
+		t.Error("second run should be served from the cache")
+	}
+	if len(res.Errors) != 1 || res.File != csvPath {
+		t.Errorf("cached results differ from the original: %+v", res)
+	}
+	if res := validate("--no-cache", csvPath); res.Cached {
+		t.Error("--no-cache should bypass the cache")
+	}
+	if res := validate("--fail-fast", csvPath); res.Cached {
+		t.Error("changing options should miss the cache")
+	}
+
+	stdout, _, code := runApp(t, "cache", "clean")
+	if code != 0 || !strings.HasPrefix(stdout, "Removed 2 cached result(s)") {
+		t.Errorf("unexpected cache clean output (exit %d): %q", code, stdout)
+	}
+	if res := validate(csvPath); res.Cached {
+		t.Error("run after cache clean should not be cached")
+	}
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/csvlinter/csvlinter/internal/codegen"
+	"github.com/csvlinter/csvlinter/internal/parser"
+	"github.com/csvlinter/csvlinter/internal/schema"
+	"github.com/csvlinter/csvlinter/pkg/csvlinter"
+
+	"github.com/urfave/cli/v2"
+)
+
+var codegenCommand = &cli.Command{
+	Name:      "codegen",
+	Usage:     "Print a Go struct or TypeScript interface for the rows of a CSV file",
+	ArgsUsage: "[file]",
+	Description: "Fields follow the file's header, or the schema's properties in name order when only --schema is given. " +
+		"Types come from the schema given with --schema, else the schema resolved for the file, else a schema inferred from its first rows.",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "lang",
+			Value: "go",
+			Usage: "Output language: " + strings.Join(codegen.Languages, ", "),
+		},
+		&cli.StringFlag{
+			Name:    "schema",
+			Aliases: []string{"s"},
+			Usage:   "Path to JSON Schema file (default: resolved for the file, else inferred)",
+		},
+		&cli.StringFlag{
+			Name:  "name",
+			Usage: "Type name (default: from the file name, e.g. Orders for orders.csv)",
+		},
+		&cli.StringFlag{
+			Name:  "package",
+			Value: "model",
+			Usage: "Go package name",
+		},
+		&cli.StringFlag{
+			Name:    "delimiter",
+			Aliases: []string{"d"},
+			Usage:   "Delimiter character, or whitespace for columns separated by runs of spaces and tabs (default: tab for .tsv files, comma otherwise)",
+		},
+	},
+	Action: codegenAction,
+}
+
+func codegenAction(c *cli.Context) error {
+	path := c.Args().First()
+	schemaPath := c.String("schema")
+	if path == "" && schemaPath == "" {
+		return cli.Exit("Error: a CSV file or --schema is required", 1)
+	}
+
+	var headers []string
+	var sample [][]string
+	if path != "" {
+		delimiter := c.String("delimiter")
+		if delimiter == "" {
+			delimiter = parser.DelimiterFor(path)
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return cli.Exit(fmt.Sprintf("Error: Cannot open file '%s': %v", path, err), 1)
+		}
+		defer f.Close()
+		if headers, sample, _, err = parser.ReadSampleFromReader(f, delimiter, csvlinter.DefaultInferSchemaMaxRows); err != nil {
+			return cli.Exit(fmt.Sprintf("Error: Cannot read '%s': %v", path, err), 1)
+		}
+		if schemaPath == "" {
+			schemaPath = schema.ResolveSchema(path)
+		}
+	}
+
+	var schemaJSON []byte
+	var err error
+	if schemaPath != "" {
+		if schemaJSON, err = schema.ReadFile(schemaPath); err != nil {
+			return cli.Exit(fmt.Sprintf("Error: Cannot read schema: %v", err), 1)
+		}
+	} else if schemaJSON, err = schema.Infer(headers, sample); err != nil {
+		return cli.Exit(fmt.Sprintf("Error: %v", err), 1)
+	}
+	desc, err := schema.Describe(schemaJSON)
+	if err != nil {
+		return cli.Exit(fmt.Sprintf("Error: %v", err), 1)
+	}
+	if headers == nil {
+		headers = desc.Names
+	}
+
+	name := c.String("name")
+	if name == "" {
+		name = "Row"
+		if path != "" {
+			name = codegen.TypeName(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
+		}
+	}
+	src, err := codegen.Generate(headers, desc, codegen.Options{
+		Language: c.String("lang"),
+		Name:     name,
+		Package:  c.String("package"),
+	})
+	if err != nil {
+		return cli.Exit(fmt.Sprintf("Error: %v", err), 1)
+	}
+	fmt.Fprint(c.App.Writer, src)
+	return nil
+}
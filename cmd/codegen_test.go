@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCodegenCommand(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"daily_orders.csv": "id,total\n1,9.50\n",
+		"rows.schema.json": `{"required":["sku"],"properties":{"sku":{"type":"string"},"qty":{"type":"integer"}}}`,
+	})
+
+	// Without a schema the types are inferred from the file
+	stdout, _, code := runApp(t, "codegen", filepath.Join(dir, "daily_orders.csv"))
+	if code != 0 || !strings.Contains(stdout, "type DailyOrders struct") || !strings.Contains(stdout, "ID    int64") {
+		t.Errorf("unexpected Go output (exit %d):\n%s", code, stdout)
+	}
+
+	// A schema alone lists its properties in name order
+	stdout, _, code = runApp(t, "codegen", "--lang", "typescript", "--schema", filepath.Join(dir, "rows.schema.json"))
+	if code != 0 || !strings.Contains(stdout, "export interface Row {\n  qty?: number;\n  sku: string;\n}") {
+		t.Errorf("unexpected TypeScript output (exit %d):\n%s", code, stdout)
+	}
+
+	if _, _, code := runApp(t, "codegen"); code != 1 {
+		t.Errorf("want exit 1 without a file or schema, got %d", code)
+	}
+}
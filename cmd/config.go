@@ -0,0 +1,325 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/csvlinter/csvlinter/internal/config"
+	"github.com/csvlinter/csvlinter/internal/parser"
+
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+)
+
+var configCommand = &cli.Command{
+	Name:  "config",
+	Usage: "Inspect the configuration",
+	Subcommands: []*cli.Command{
+		{
+			Name:  "show",
+			Usage: "Print the settings validate would use, merged from the config file, the environment and flags, and where each comes from",
+			Description: "Takes the same flags as validate. Values from the config file are shown as written there; " +
+				"flags and environment variables that override them are marked.",
+			Flags: append([]cli.Flag{
+				&cli.StringFlag{
+					Name:  "for",
+					Usage: "Also resolve the schema and delimiter validate would use for this CSV file",
+				},
+				&cli.BoolFlag{
+					Name:  "json",
+					Usage: "Print the settings as a JSON array of key, value and source",
+				},
+			}, validateCommand.Flags...),
+			Action: configShowAction,
+		},
+	},
+}
+
+// configKeys maps validate flags to the config keys they override.
+var configKeys = map[string]string{
+	"target":              "target",
+	"file-schema":         "file_schema",
+	"profile":             "drift.profile",
+	"theme":               "theme.name",
+	"lang":                "lang",
+	"quote":               "quote",
+	"escape":              "escape",
+	"quoting":             "quoting",
+	"sep-line":            "sep_line",
+	"short-rows":          "ragged.short",
+	"long-rows":           "ragged.long",
+	"require-data":        "require_data",
+	"chunked":             "chunked",
+	"empty-values":        "empty.default",
+	"quoted-empty":        "empty.quoted",
+	"redact-mode":         "redact.mode",
+	"redact-column":       "redact.columns",
+	"notify-webhook":      "notify.webhook",
+	"metrics-out":         "metrics.out",
+	"metrics-pushgateway": "metrics.pushgateway",
+}
+
+// setting is one value of the effective configuration and where it came from.
+type setting struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+func configShowAction(c *cli.Context) error {
+	cfg, err := loadConfigFor(c, c.String("for"))
+	if err != nil {
+		return cli.Exit(fmt.Sprintf("Error: %v", err), 1)
+	}
+	var args []string
+	if lineage := c.Lineage(); len(lineage) > 1 {
+		args = lineage[1].Args().Slice()
+	}
+
+	var settings []setting
+	add := func(key, value, source string) {
+		settings = append(settings, setting{Key: key, Value: value, Source: source})
+	}
+	csvPath := c.String("for")
+	switch {
+	case cfg.Path == "":
+		add("config", "none", "no config file up to the project root")
+	case c.IsSet("config"):
+		add("config", cfg.Path, "flag --config")
+	case csvPath != "":
+		add("config", strings.Join(cfg.Files, ", "), "found from the directory of "+csvPath)
+	default:
+		add("config", strings.Join(cfg.Files, ", "), "found from the working directory")
+	}
+
+	switch {
+	case c.IsSet("schema"):
+		add("schema", strings.Join(c.StringSlice("schema"), ", "), flagSource(c, args, "schema"))
+	case csvPath == "":
+		add("schema", "per file", "resolved next to each file (use --for to resolve one)")
+	case schemaResolver(cfg).Resolve(csvPath) == "":
+		add("schema", "none", "no schema found for "+csvPath)
+	default:
+		add("schema", schemaResolver(cfg).Resolve(csvPath), "found for "+csvPath)
+	}
+	switch {
+	case c.IsSet("delimiter"):
+		add("delimiter", strconv.Quote(c.String("delimiter")), flagSource(c, args, "delimiter"))
+	case csvPath == "":
+		add("delimiter", "per file", "default by file extension (use --for to resolve one)")
+	default:
+		add("delimiter", strconv.Quote(parser.DelimiterFor(csvPath)), "default for "+filepath.Ext(csvPath)+" files")
+	}
+
+	// Flags overriding config keys take the place of the keys
+	overrides := make(map[string]string)
+	for _, f := range validateCommand.Flags {
+		name := f.Names()[0]
+		if key, ok := configKeys[name]; ok && c.IsSet(name) {
+			overrides[key] = name
+		}
+	}
+	typeFlags := flagTypes(c)
+	entries, err := configEntries(cfg)
+	if err != nil {
+		return cli.Exit(fmt.Sprintf("Error: %v", err), 1)
+	}
+	// With nested config files, each key comes from the deepest file setting it
+	files := make(map[string]string)
+	for _, path := range cfg.Files {
+		layer, err := config.Load(path)
+		if err != nil {
+			return cli.Exit(fmt.Sprintf("Error: %v", err), 1)
+		}
+		layerEntries, err := configEntries(layer)
+		if err != nil {
+			return cli.Exit(fmt.Sprintf("Error: %v", err), 1)
+		}
+		for _, e := range layerEntries {
+			files[e.Key] = path
+		}
+	}
+	for _, e := range entries {
+		if name, ok := overrides[e.Key]; ok {
+			add(e.Key, flagValue(c, name), flagSource(c, args, name)+" (overrides "+files[e.Key]+")")
+			delete(overrides, e.Key)
+			continue
+		}
+		if t, ok := typeFlags[e.Key]; ok {
+			add(e.Key, t, flagSource(c, args, "types")+" (overrides "+files[e.Key]+")")
+			delete(typeFlags, e.Key)
+			continue
+		}
+		add(e.Key, e.Value, "config "+files[e.Key])
+	}
+
+	for _, f := range validateCommand.Flags {
+		name := f.Names()[0]
+		if !c.IsSet(name) || name == "config" || name == "schema" || name == "delimiter" {
+			continue
+		}
+		if name == "types" {
+			keys := make([]string, 0, len(typeFlags))
+			for key := range typeFlags {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				add(key, typeFlags[key], flagSource(c, args, name))
+			}
+			continue
+		}
+		key, isConfigKey := configKeys[name]
+		if !isConfigKey {
+			add(name, flagValue(c, name), flagSource(c, args, name))
+		} else if _, pending := overrides[key]; pending {
+			add(key, flagValue(c, name), flagSource(c, args, name))
+		}
+	}
+
+	if c.Bool("json") {
+		enc := json.NewEncoder(c.App.Writer)
+		enc.SetIndent("", "  ")
+		return enc.Encode(settings)
+	}
+	w := tabwriter.NewWriter(c.App.Writer, 0, 0, 2, ' ', 0)
+	for _, s := range settings {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", s.Key, s.Value, s.Source)
+	}
+	return w.Flush()
+}
+
+// configEntries lists the values set in a config file as dotted keys in the file's
+// order, with lists and rules written as flow YAML. Unset and zero values are left out.
+func configEntries(cfg *config.Config) ([]setting, error) {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	var out []setting
+	var walk func(prefix string, n *yaml.Node) error
+	walk = func(prefix string, n *yaml.Node) error {
+		switch {
+		case n.Kind == yaml.MappingNode && len(n.Content) > 0:
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				key := n.Content[i].Value
+				if prefix != "" {
+					key = prefix + "." + key
+				}
+				if err := walk(key, n.Content[i+1]); err != nil {
+					return err
+				}
+			}
+		case n.Kind == yaml.SequenceNode && len(n.Content) > 0:
+			prune(n)
+			n.Style = yaml.FlowStyle
+			b, err := yaml.Marshal(n)
+			if err != nil {
+				return err
+			}
+			out = append(out, setting{Key: prefix, Value: strings.TrimSpace(string(b))})
+		case n.Kind == yaml.ScalarNode && !zeroScalar(n):
+			out = append(out, setting{Key: prefix, Value: n.Value})
+		}
+		return nil
+	}
+	if len(doc.Content) > 0 {
+		if err := walk("", doc.Content[0]); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// prune drops the zero fields of the mappings in n, such as the defaults of rules.
+func prune(n *yaml.Node) {
+	if n.Kind == yaml.MappingNode {
+		var kept []*yaml.Node
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if v := n.Content[i+1]; v.Kind != yaml.ScalarNode || !zeroScalar(v) {
+				kept = append(kept, n.Content[i], v)
+			}
+		}
+		n.Content = kept
+	}
+	for _, child := range n.Content {
+		prune(child)
+	}
+}
+
+// zeroScalar reports whether a marshaled scalar is the zero value of its type.
+func zeroScalar(n *yaml.Node) bool {
+	switch n.Tag {
+	case "!!null":
+		return true
+	case "!!str":
+		return n.Value == ""
+	case "!!int", "!!float":
+		return n.Value == "0"
+	case "!!bool":
+		return n.Value == "false"
+	}
+	return false
+}
+
+// flagTypes returns the column types set by --types, as types.<column> keys.
+func flagTypes(c *cli.Context) map[string]string {
+	out := make(map[string]string)
+	if spec := c.String("types"); spec != "" {
+		for _, item := range strings.Split(spec, ",") {
+			if i := strings.LastIndex(item, ":"); i > 0 {
+				out["types."+strings.TrimSpace(item[:i])] = strings.TrimSpace(item[i+1:])
+			}
+		}
+	}
+	return out
+}
+
+// flagValue formats the value of a validate flag.
+func flagValue(c *cli.Context, name string) string {
+	for _, f := range validateCommand.Flags {
+		if _, ok := f.(*cli.StringSliceFlag); ok && f.Names()[0] == name {
+			return strings.Join(c.StringSlice(name), ", ")
+		}
+	}
+	return fmt.Sprint(c.Value(name))
+}
+
+// flagSource tells whether a set flag came from the command line or from one of its
+// environment variables.
+func flagSource(c *cli.Context, args []string, name string) string {
+	for _, f := range validateCommand.Flags {
+		if f.Names()[0] != name {
+			continue
+		}
+		for _, alias := range f.Names() {
+			for _, arg := range args {
+				trimmed := strings.TrimLeft(arg, "-")
+				if arg == "--" {
+					break
+				}
+				if strings.HasPrefix(arg, "-") && (trimmed == alias || strings.HasPrefix(trimmed, alias+"=")) {
+					return "flag --" + name
+				}
+			}
+		}
+		if ef, ok := f.(cli.DocGenerationFlag); ok {
+			for _, env := range ef.GetEnvVars() {
+				if _, set := os.LookupEnv(env); set {
+					return "env " + env
+				}
+			}
+		}
+	}
+	return "flag --" + name
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+)
+
+func TestConfigShowCommand(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		".csvlinter.yml":          "target: postgres\nschemas: [rules.schema.json]\ntypes:\n  age: int\nragged:\n  short: pad\nmetrics:\n  out: csvlinter.prom\n",
+		"data/orders.tsv":         "id\tage\n1\t30\n",
+		"data/orders.schema.json": `{"type":"object"}`,
+	})
+	config := filepath.Join(dir, ".csvlinter.yml")
+
+	stdout, _, code := runApp(t, "config", "show", "--json", "--config", config, "--for", filepath.Join(dir, "data", "orders.tsv"),
+		"--target", "bigquery", "--types", "id:int", "--fail-fast")
+	if code != 0 {
+		t.Fatalf("want exit 0, got %d", code)
+	}
+	var settings []setting
+	if err := json.Unmarshal([]byte(stdout), &settings); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+	}
+	got := make(map[string]setting)
+	for _, s := range settings {
+		got[s.Key] = s
+	}
+	want := map[string]setting{
+		"config":       {"config", config, "flag --config"},
+		"schema":       {"schema", filepath.Join(dir, "data", "orders.schema.json"), "found for " + filepath.Join(dir, "data", "orders.tsv")},
+		"delimiter":    {"delimiter", `"\t"`, "default for .tsv files"},
+		"schemas":      {"schemas", "[rules.schema.json]", "config " + config},
+		"target":       {"target", "bigquery", "flag --target (overrides " + config + ")"},
+		"types.age":    {"types.age", "int", "config " + config},
+		"types.id":     {"types.id", "int", "flag --types"},
+		"ragged.short": {"ragged.short", "pad", "config " + config},
+		"fail-fast":    {"fail-fast", "true", "flag --fail-fast"},
+		"metrics.out":  {"metrics.out", "csvlinter.prom", "config " + config},
+	}
+	for key, w := range want {
+		if got[key] != w {
+			t.Errorf("%s: got %+v, want %+v", key, got[key], w)
+		}
+	}
+	if len(settings) != len(want) {
+		t.Errorf("want %d settings, got %+v", len(want), settings)
+	}
+}
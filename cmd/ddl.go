@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/csvlinter/csvlinter/internal/ddl"
+	"github.com/csvlinter/csvlinter/internal/parser"
+	"github.com/csvlinter/csvlinter/internal/schema"
+	"github.com/csvlinter/csvlinter/pkg/csvlinter"
+
+	"github.com/urfave/cli/v2"
+)
+
+var ddlCommand = &cli.Command{
+	Name:      "ddl",
+	Usage:     "Print a CREATE TABLE statement for a CSV file",
+	ArgsUsage: "<file>",
+	Description: "Columns follow the file's header. Types, NOT NULL and the primary key (x-primaryKey) come from " +
+		"the schema given with --schema, else the schema resolved for the file, else a schema inferred from its first rows.",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "dialect",
+			Value: "postgres",
+			Usage: "SQL dialect: " + strings.Join(ddl.Dialects(), ", "),
+		},
+		&cli.StringFlag{
+			Name:    "schema",
+			Aliases: []string{"s"},
+			Usage:   "Path to JSON Schema file (default: resolved for the file, else inferred)",
+		},
+		&cli.StringFlag{
+			Name:  "table",
+			Usage: "Table name (default: the file name without extension)",
+		},
+		&cli.StringFlag{
+			Name:    "delimiter",
+			Aliases: []string{"d"},
+			Usage:   "Delimiter character, or whitespace for columns separated by runs of spaces and tabs (default: tab for .tsv files, comma otherwise)",
+		},
+	},
+	Action: ddlAction,
+}
+
+func ddlAction(c *cli.Context) error {
+	path := c.Args().First()
+	if path == "" {
+		return cli.Exit("Error: CSV file path is required", 1)
+	}
+	delimiter := c.String("delimiter")
+	if delimiter == "" {
+		delimiter = parser.DelimiterFor(path)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return cli.Exit(fmt.Sprintf("Error: Cannot open file '%s': %v", path, err), 1)
+	}
+	defer f.Close()
+	headers, sample, _, err := parser.ReadSampleFromReader(f, delimiter, csvlinter.DefaultInferSchemaMaxRows)
+	if err != nil {
+		return cli.Exit(fmt.Sprintf("Error: Cannot read '%s': %v", path, err), 1)
+	}
+
+	var schemaJSON []byte
+	schemaPath := c.String("schema")
+	if schemaPath == "" {
+		schemaPath = schema.ResolveSchema(path)
+	}
+	if schemaPath != "" {
+		if schemaJSON, err = schema.ReadFile(schemaPath); err != nil {
+			return cli.Exit(fmt.Sprintf("Error: Cannot read schema: %v", err), 1)
+		}
+	} else if schemaJSON, err = schema.Infer(headers, sample); err != nil {
+		return cli.Exit(fmt.Sprintf("Error: %v", err), 1)
+	}
+
+	table := c.String("table")
+	if table == "" {
+		table = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	statement, err := ddl.Generate(c.String("dialect"), table, headers, schemaJSON)
+	if err != nil {
+		return cli.Exit(fmt.Sprintf("Error: %v", err), 1)
+	}
+	fmt.Fprint(c.App.Writer, statement)
+	return nil
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDDLCommand(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"orders.csv":         "id,total,placed\n1,9.50,2024-01-02\n2,12,2024-01-03\n",
+		"orders.schema.json": `{"type":"object","required":["id"],"x-primaryKey":["id"],"properties":{"id":{"type":"integer"},"total":{"type":"number"}}}`,
+		"plain.csv":          "id,placed\n1,2024-01-02\n2,2024-01-03\n",
+	})
+
+	// The resolved schema types id and total; placed is not described
+	stdout, _, code := runApp(t, "ddl", filepath.Join(dir, "orders.csv"))
+	if code != 0 {
+		t.Fatalf("want exit 0, got %d", code)
+	}
+	for _, want := range []string{`CREATE TABLE "orders"`, `"id" BIGINT NOT NULL`, `"total" DOUBLE PRECISION`, `"placed" TEXT`, `PRIMARY KEY ("id")`} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("want %q in output:\n%s", want, stdout)
+		}
+	}
+
+	// Without a schema the types are inferred from the data
+	stdout, _, code = runApp(t, "ddl", "--dialect", "snowflake", "--table", "events", filepath.Join(dir, "plain.csv"))
+	if code != 0 || !strings.Contains(stdout, `CREATE TABLE "events"`) || !strings.Contains(stdout, `"id" NUMBER(38,0) NOT NULL`) || !strings.Contains(stdout, `"placed" DATE`) {
+		t.Errorf("unexpected inferred DDL (exit %d):\n%s", code, stdout)
+	}
+
+	if _, _, code := runApp(t, "ddl", "--dialect", "oracle", filepath.Join(dir, "plain.csv")); code != 1 {
+		t.Errorf("want exit 1 for an unknown dialect, got %d", code)
+	}
+}
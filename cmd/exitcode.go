@@ -0,0 +1,29 @@
+package cmd
+
+import "github.com/csvlinter/csvlinter/pkg/csvlinter"
+
+// Exit codes of validate and stream, so wrapper scripts can branch on why a run failed
+// without parsing its output. The JSON error document's code tells failures apart further.
+const (
+	exitValid     = 0 // Every file is valid
+	exitInvalid   = 1 // The data has errors, or cannot be parsed as CSV; also internal errors
+	exitUsage     = 2 // Bad flag, argument or option value, or an unreadable config or manifest
+	exitSchema    = 3 // Schema missing, unreadable or invalid
+	exitIO        = 4 // Input cannot be opened or read, or the report cannot be written
+	exitCancelled = 5 // Validation was interrupted or cancelled before it finished
+)
+
+// exitCode returns the exit code of an operational failure with code.
+func exitCode(code csvlinter.ErrorCode) int {
+	switch code {
+	case csvlinter.CodeInvalidArgument, csvlinter.CodeConfigInvalid, csvlinter.CodeManifestInvalid:
+		return exitUsage
+	case csvlinter.CodeSchemaNotFound, csvlinter.CodeSchemaInvalid:
+		return exitSchema
+	case csvlinter.CodeFileNotFound, csvlinter.CodeFileUnreadable, csvlinter.CodeOutputFailed:
+		return exitIO
+	case csvlinter.CodeCancelled:
+		return exitCancelled
+	}
+	return exitInvalid
+}
@@ -0,0 +1,287 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/csvlinter/csvlinter/internal/charset"
+	"github.com/csvlinter/csvlinter/internal/parser"
+	"github.com/csvlinter/csvlinter/internal/transform"
+
+	"github.com/urfave/cli/v2"
+)
+
+var fixCommand = &cli.Command{
+	Name:      "fix",
+	Usage:     "Write a CSV file with the config's transforms applied to its values, trailing delimiters removed, CR-only line endings converted and, optionally, its encoding repaired or repeated headers dropped",
+	ArgsUsage: "[file]",
+	Description: "Applies the transforms configured in .csvlinter.yml (trim, upper, lower, strip_currency, date), " +
+		"drops the empty last column of lines ending with a delimiter, converts CR-only line endings to line feeds, " +
+		"re-encodes the file as UTF-8 with --fix-encoding, drops data rows repeating the header with --drop-repeated-headers, " +
+		"and writes the file to --output or stdout, with untouched rows and fields copied byte for byte. Reads STDIN when no file (or -) is given.",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    "output",
+			Aliases: []string{"o"},
+			Usage:   "Write the fixed file here instead of stdout",
+		},
+		&cli.StringFlag{
+			Name:    "delimiter",
+			Aliases: []string{"d"},
+			Usage:   "Delimiter character, or whitespace for columns separated by runs of spaces and tabs (default: tab for .tsv files, comma otherwise)",
+		},
+		&cli.StringFlag{
+			Name:  "quote",
+			Usage: "Quote character of the file, e.g. \"'\", or none for files that never quote; the file is written with double quotes",
+		},
+		&cli.StringFlag{
+			Name:  "escape",
+			Usage: "Character that escapes delimiters, quotes and line breaks in fields, e.g. '\\'; the file is written with quotes instead",
+		},
+		&cli.StringFlag{
+			Name:  "quoting",
+			Usage: "Which fields to quote: minimal (the default, only fields that need quotes), all or nonnumeric",
+		},
+		&cli.BoolFlag{
+			Name:  "fix-encoding",
+			Usage: "Decode the file from --encoding and write valid UTF-8, replacing undecodable bytes with U+FFFD",
+		},
+		&cli.StringFlag{
+			Name:  "encoding",
+			Usage: "Source encoding for --fix-encoding: auto (the default, from a byte order mark or the bytes), utf-8, utf-16le, utf-16be, latin1 or windows-1252",
+			Value: charset.Auto,
+		},
+		&cli.BoolFlag{
+			Name:  "drop-repeated-headers",
+			Usage: "Drop data rows that repeat the header, as where exported files were concatenated",
+		},
+		&cli.StringFlag{
+			Name:  "config",
+			Usage: "Path to a config file. If not set, the .csvlinter.yml files from the CSV file's directory up to the project root are merged, deeper ones winning",
+		},
+	},
+	Action: fixAction,
+}
+
+func fixAction(c *cli.Context) error {
+	cfg, err := loadConfigFor(c, c.Args().First())
+	if err != nil {
+		return cli.Exit(fmt.Sprintf("Error: %v", err), 1)
+	}
+	var t *transform.Transformer
+	if len(cfg.Transforms) > 0 {
+		if t, err = transform.New(cfg.Transforms); err != nil {
+			return cli.Exit(fmt.Sprintf("Error: invalid transform: %v", err), 1)
+		}
+	}
+
+	quoting := cfg.Quoting
+	if c.IsSet("quoting") {
+		quoting = c.String("quoting")
+	}
+	if quoting != "" {
+		if err := parser.CheckQuoting(quoting); err != nil {
+			return cli.Exit(fmt.Sprintf("Error: %v", err), 1)
+		}
+	}
+
+	quote, escape := cfg.Quote, cfg.Escape
+	if c.IsSet("quote") {
+		quote = c.String("quote")
+	}
+	if c.IsSet("escape") {
+		escape = c.String("escape")
+	}
+
+	path := c.Args().First()
+	var input io.Reader = os.Stdin
+	if path != "" && path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return cli.Exit(fmt.Sprintf("Error: Cannot open file '%s': %v", path, err), 1)
+		}
+		defer f.Close()
+		input = f
+	}
+	var decoder *charset.Decoder
+	if c.Bool("fix-encoding") {
+		if decoder, err = charset.NewDecoder(input, c.String("encoding")); err != nil {
+			return cli.Exit(fmt.Sprintf("Error: %v", err), 1)
+		}
+		input = decoder
+	} else if c.IsSet("encoding") {
+		return cli.Exit("Error: --encoding requires --fix-encoding", 1)
+	}
+	delimiter := c.String("delimiter")
+	if delimiter == "" {
+		delimiter = parser.DelimiterFor(path)
+	}
+	dialect, err := parser.NewDialect(delimiter, quote, escape)
+	if err != nil {
+		return cli.Exit(fmt.Sprintf("Error: %v", err), 1)
+	}
+
+	out := c.App.Writer
+	if o := c.String("output"); o != "" {
+		f, err := os.Create(o)
+		if err != nil {
+			return cli.Exit(fmt.Sprintf("Error: Cannot create '%s': %v", o, err), 1)
+		}
+		defer f.Close()
+		out = f
+	}
+	if err := fixCSV(input, out, dialect, t, quoting, c.Bool("drop-repeated-headers"), decoder != nil || quote != "" || escape != ""); err != nil {
+		if errors.Is(err, parser.ErrInvalidUTF8) && decoder == nil {
+			return cli.Exit(fmt.Sprintf("Error: %v (--fix-encoding repairs it)", err), 1)
+		}
+		return cli.Exit(fmt.Sprintf("Error: %v", err), 1)
+	}
+	if decoder != nil {
+		fmt.Fprintf(c.App.ErrWriter, "Decoded as %s; %d undecodable byte sequence(s) replaced with U+FFFD\n",
+			decoder.Encoding(), decoder.Replacements())
+	}
+	return nil
+}
+
+// errNothingToFix is returned for files that neither transforms nor the removal of a
+// trailing delimiter would change, unless their encoding is repaired, repeated headers
+// dropped or CR-only line endings converted.
+var errNothingToFix = errors.New("no transforms or quoting policy configured (add a transforms section to .csvlinter.yml), no trailing delimiter to remove, and no --fix-encoding or --drop-repeated-headers")
+
+// fixCSV copies the CSV in r to w with t, if set, applied to every data row. A header
+// ending with an empty field, in a column no row has a value in, is taken as a trailing
+// delimiter, which is removed from the header and from every row whose last value is
+// empty. With dropHeaders, rows repeating
+// the header are left out. Everything else is copied byte for byte: only changed fields
+// are rewritten, quoted if they were or need to be, and rows keep their line endings.
+// Given a quoting policy, every field is instead quoted by it (only where needed for
+// parser.QuoteConsistent, which that is by construction) and lines end with a line feed,
+// as they do when CR-only files are converted and files in another dialect written with
+// double quotes. With rewrite, the file is written even when nothing else changes it, as
+// when r was decoded to UTF-8 or is in another dialect.
+func fixCSV(r io.Reader, w io.Writer, d parser.Dialect, t *transform.Transformer, quoting string, dropHeaders, rewrite bool) error {
+	rec := &recorder{r: r}
+	p, err := parser.NewParserWithDialect(rec, d)
+	if err != nil {
+		return err
+	}
+	p.KeepRaw()
+	headers, err := p.ReadHeaders()
+	if err != nil {
+		return err
+	}
+	width := len(headers)
+	trailing := width > 1 && headers[width-1] == ""
+	if !trailing {
+		rec.stop()
+	} else {
+		// The header's empty last field is a trailing delimiter only if no row has a
+		// value there, so the file is read through once before it is fixed
+		if trailing, err = emptyLastColumn(p, width); err != nil {
+			return err
+		}
+		if p, err = parser.NewParserWithDialect(bytes.NewReader(rec.buf.Bytes()), d); err != nil {
+			return err
+		}
+		p.KeepRaw()
+		if headers, err = p.ReadHeaders(); err != nil {
+			return err
+		}
+	}
+	if t == nil && !trailing && quoting == "" && !dropHeaders && !rewrite && !p.CROnly() {
+		return errNothingToFix
+	}
+	// Files written in another dialect or with other line endings have every row changed
+	normalize := quoting != "" || p.CROnly() || d.Quote != 0 || d.Escape != 0
+	if quoting == "" || quoting == parser.QuoteConsistent {
+		quoting = parser.QuoteMinimal
+	}
+	original := headers
+	if trailing {
+		headers = headers[:width-1]
+	}
+	if t != nil {
+		t.Start(headers)
+	}
+	pw := parser.NewWriter(w, d)
+	write := func(raw []byte, read, fields []string) error {
+		if normalize {
+			return pw.WriteRaw([]byte(parser.FormatRecord(fields, parser.DelimiterRune(d.Delimiter), quoting) + "\n"))
+		}
+		return pw.Write(raw, read, fields)
+	}
+	if err := write(p.Raw(), original, headers); err != nil {
+		return err
+	}
+	for {
+		row, err := p.ReadRow()
+		if err == io.EOF {
+			if !normalize {
+				// Blank lines at the end of the file
+				if err := pw.WriteRaw(p.Raw()); err != nil {
+					return err
+				}
+			}
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if dropHeaders && parser.RepeatsHeader(row.Data, original) {
+			continue
+		}
+		read := slices.Clone(row.Data)
+		if trailing && len(row.Data) == width && row.Data[width-1] == "" {
+			row.Data = row.Data[:width-1]
+		}
+		if t != nil {
+			t.Row(row.Data)
+		}
+		if err := write(row.Raw, read, row.Data); err != nil {
+			return err
+		}
+	}
+	return pw.Flush()
+}
+
+// emptyLastColumn reads the rest of p and reports whether the last of width fields is
+// empty on every row that has them all.
+func emptyLastColumn(p *parser.Parser, width int) (bool, error) {
+	empty := true
+	for {
+		row, err := p.ReadRow()
+		if err == io.EOF {
+			return empty, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if len(row.Data) == width && row.Data[width-1] != "" {
+			empty = false
+		}
+	}
+}
+
+// recorder keeps what is read from r until stopped, so that it can be read again.
+type recorder struct {
+	r       io.Reader
+	buf     bytes.Buffer
+	stopped bool
+}
+
+func (rec *recorder) Read(b []byte) (int, error) {
+	n, err := rec.r.Read(b)
+	if !rec.stopped {
+		rec.buf.Write(b[:n])
+	}
+	return n, err
+}
+
+func (rec *recorder) stop() {
+	rec.stopped = true
+	rec.buf = bytes.Buffer{}
+}
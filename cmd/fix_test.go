@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+const transformConfig = `transforms:
+  price: [trim, strip_currency]
+  country: [trim, upper]
+  day: ["date:DD/MM/YYYY->YYYY-MM-DD"]
+`
+
+func TestValidateCommand_Transforms(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		".csvlinter.yml":     transformConfig,
+		"orders.csv":         "price,country,day\n$ 9.50 , de,31/12/2024\n€3,FR,12-31-2024\n",
+		"orders.schema.json": `{"properties":{"price":{"type":"number"},"country":{"type":"string","pattern":"^[A-Z]{2}$"},"day":{"type":"string","format":"date"}}}`,
+	})
+	config := filepath.Join(dir, ".csvlinter.yml")
+
+	// Transformed values pass; the date in the wrong pattern is reported as it is
+	stdout, _, code := runApp(t, "validate", "--format", "json", "--no-cache", "--config", config, filepath.Join(dir, "orders.csv"))
+	var res validator.Results
+	if err := json.Unmarshal([]byte(stdout), &res); err != nil {
+		t.Fatalf("invalid json output: %v\nstdout=%s", err, stdout)
+	}
+	if code != 1 || len(res.Errors) != 1 || res.Errors[0].Field != "day" || res.Errors[0].Value != "12-31-2024" || res.Errors[0].Line != 3 {
+		t.Errorf("want only the unconverted date on line 3 reported, got %+v", res.Errors)
+	}
+
+	writeTree(t, dir, map[string]string{"bad.yml": "transforms:\n  price: [titlecase]\n"})
+	stdout, _, _ = runApp(t, "validate", "--format", "json", "--config", filepath.Join(dir, "bad.yml"), filepath.Join(dir, "orders.csv"))
+	var doc errorDocument
+	if err := json.Unmarshal([]byte(stdout), &doc); err != nil || doc.Error.Code != "INVALID_ARGUMENT" {
+		t.Errorf("want an INVALID_ARGUMENT error for an unknown step, got %s", stdout)
+	}
+}
+
+func TestFixCommand(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		".csvlinter.yml": transformConfig,
+		"orders.csv":     "price,country,day,note\n$ 9.50 , de,31/12/2024,\"a, b\"\n",
+	})
+	config := filepath.Join(dir, ".csvlinter.yml")
+	want := "price,country,day,note\n9.50,DE,2024-12-31,\"a, b\"\n"
+
+	stdout, _, code := runApp(t, "fix", "--config", config, filepath.Join(dir, "orders.csv"))
+	if code != 0 || stdout != want {
+		t.Errorf("want the transformed file on stdout (exit 0), got %d:\n%s", code, stdout)
+	}
+
+	out := filepath.Join(dir, "fixed.csv")
+	if _, _, code := runApp(t, "fix", "--config", config, "-o", out, filepath.Join(dir, "orders.csv")); code != 0 {
+		t.Fatalf("want exit 0, got %d", code)
+	}
+	if data, err := os.ReadFile(out); err != nil || string(data) != want {
+		t.Errorf("want the transformed file in --output, got %q (%v)", data, err)
+	}
+
+	writeTree(t, dir, map[string]string{"empty.yml": "target: postgres\n"})
+	if _, _, code := runApp(t, "fix", "--config", filepath.Join(dir, "empty.yml"), filepath.Join(dir, "orders.csv")); code != 1 {
+		t.Errorf("want exit 1 without transforms, got %d", code)
+	}
+
+	// Without transforms, fix still removes a trailing delimiter
+	writeTree(t, dir, map[string]string{"trailing.csv": "id,name,\n1,a,\n2,\"b,c\",\n"})
+	stdout, _, code = runApp(t, "fix", "--config", filepath.Join(dir, "empty.yml"), filepath.Join(dir, "trailing.csv"))
+	if want := "id,name\n1,a\n2,\"b,c\"\n"; code != 0 || stdout != want {
+		t.Errorf("want the trailing delimiter removed, got %d:\n%s", code, stdout)
+	}
+	// A last column with an empty name but values in it is data, and left alone
+	writeTree(t, dir, map[string]string{"unnamed.csv": "\xef\xbb\xbfx,y,\n1, 2 ,\n3,4,5\n"})
+	stdout, _, code = runApp(t, "fix", "--config", filepath.Join(dir, "empty.yml"), filepath.Join(dir, "unnamed.csv"))
+	if code != 1 || stdout != "" {
+		t.Errorf("want nothing to fix in a column with values, got %d:\n%q", code, stdout)
+	}
+	stdout, _, code = runApp(t, "fix", "--config", config, filepath.Join(dir, "unnamed.csv"))
+	if want := "\xef\xbb\xbfx,y,\n1, 2 ,\n3,4,5\n"; code != 0 || stdout != want {
+		t.Errorf("want the unnamed column kept, got %d:\n%q", code, stdout)
+	}
+
+	// Untouched rows and fields keep their quoting and line endings
+	writeTree(t, dir, map[string]string{"crlf.csv": "price,country,day,note\r\n\"1\", de ,31/12/2024,\"x\"\r\n\r\n\"2\",FR,2024-12-31,y\n"})
+	stdout, _, code = runApp(t, "fix", "--config", config, filepath.Join(dir, "crlf.csv"))
+	if want := "price,country,day,note\r\n\"1\",DE,2024-12-31,\"x\"\r\n\r\n\"2\",FR,2024-12-31,y\n"; code != 0 || stdout != want {
+		t.Errorf("want only the transformed fields changed, got %d:\n%q", code, stdout)
+	}
+
+	stdout, _, code = runApp(t, "fix", "--config", filepath.Join(dir, "empty.yml"), "--quoting", "nonnumeric", filepath.Join(dir, "trailing.csv"))
+	if want := "\"id\",\"name\"\n1,\"a\"\n2,\"b,c\"\n"; code != 0 || stdout != want {
+		t.Errorf("want non-numeric fields quoted, got %d:\n%s", code, stdout)
+	}
+	if _, _, code := runApp(t, "fix", "--quoting", "some", filepath.Join(dir, "trailing.csv")); code != 1 {
+		t.Errorf("want exit 1 for an unknown quoting policy, got %d", code)
+	}
+
+	// CR-only line endings are converted even when nothing else changes
+	writeTree(t, dir, map[string]string{"mac.csv": "id,name\r1,a\r"})
+	stdout, _, code = runApp(t, "fix", "--config", filepath.Join(dir, "empty.yml"), filepath.Join(dir, "mac.csv"))
+	if want := "id,name\n1,a\n"; code != 0 || stdout != want {
+		t.Errorf("want CR-only line endings converted, got %d:\n%q", code, stdout)
+	}
+
+	// Escaped delimiters are written as quoted fields
+	writeTree(t, dir, map[string]string{"escaped.csv": "id,path\n1,C:\\\\tmp\\, old\n"})
+	stdout, _, code = runApp(t, "fix", "--config", filepath.Join(dir, "empty.yml"), "--escape", `\`, filepath.Join(dir, "escaped.csv"))
+	if want := "id,path\n1,\"C:\\tmp, old\"\n"; code != 0 || stdout != want {
+		t.Errorf("want the escaped delimiter quoted, got %d:\n%q", code, stdout)
+	}
+
+	// Single-quoted fields are written with double quotes
+	writeTree(t, dir, map[string]string{"single.csv": "id,name\n1,'Smith, John'\n"})
+	stdout, _, code = runApp(t, "fix", "--config", filepath.Join(dir, "empty.yml"), "--quote", "'", filepath.Join(dir, "single.csv"))
+	if want := "id,name\n1,\"Smith, John\"\n"; code != 0 || stdout != want {
+		t.Errorf("want the single quotes converted, got %d:\n%q", code, stdout)
+	}
+
+	// Headers repeated by concatenation are dropped on request, trailing delimiter and all
+	writeTree(t, dir, map[string]string{"concat.csv": "id,name,\n1,a,\nid,name,\n2,b,\n"})
+	stdout, _, code = runApp(t, "fix", "--config", filepath.Join(dir, "empty.yml"), "--drop-repeated-headers", filepath.Join(dir, "concat.csv"))
+	if want := "id,name\n1,a\n2,b\n"; code != 0 || stdout != want {
+		t.Errorf("want the repeated header dropped, got %d:\n%s", code, stdout)
+	}
+}
+
+func TestFixCommand_Encoding(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		".csvlinter.yml": "target: postgres\n",
+		"latin.csv":      "name,city\nJos\xe9,Z\xfcrich\n",
+		"broken.csv":     "name,city\nJos\xc3\xa9,Z\xfcrich\n",
+	})
+	config := filepath.Join(dir, ".csvlinter.yml")
+
+	// Without --fix-encoding the invalid file is an error
+	if _, _, code := runApp(t, "fix", "--config", config, filepath.Join(dir, "latin.csv")); code != 1 {
+		t.Errorf("want exit 1 for invalid UTF-8, got %d", code)
+	}
+
+	stdout, stderr, code := runApp(t, "fix", "--config", config, "--fix-encoding", filepath.Join(dir, "latin.csv"))
+	if code != 0 || stdout != "name,city\nJosé,Zürich\n" || !strings.Contains(stderr, "Decoded as windows-1252; 0 undecodable") {
+		t.Errorf("want the file decoded as windows-1252, got %d: %q %s", code, stdout, stderr)
+	}
+
+	// Mostly UTF-8: the stray byte is replaced and counted
+	stdout, stderr, code = runApp(t, "fix", "--config", config, "--fix-encoding", filepath.Join(dir, "broken.csv"))
+	if code != 0 || stdout != "name,city\nJosé,Z�rich\n" || !strings.Contains(stderr, "Decoded as utf-8; 1 undecodable") {
+		t.Errorf("want the invalid byte replaced, got %d: %q %s", code, stdout, stderr)
+	}
+
+	stdout, _, _ = runApp(t, "fix", "--config", config, "--fix-encoding", "--encoding", "latin1", filepath.Join(dir, "broken.csv"))
+	if stdout != "name,city\nJosÃ©,Zürich\n" {
+		t.Errorf("want the given encoding used, got %q", stdout)
+	}
+	if _, _, code := runApp(t, "fix", "--fix-encoding", "--encoding", "ebcdic", filepath.Join(dir, "latin.csv")); code != 1 {
+		t.Errorf("want exit 1 for an unknown encoding, got %d", code)
+	}
+}
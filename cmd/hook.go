@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/csvlinter/csvlinter/internal/gitutil"
+
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// hookMarker identifies pre-commit hooks written by csvlinter, which may be overwritten
+// without --force.
+const hookMarker = "# Installed by csvlinter hook install"
+
+// preCommitConfig is the pre-commit framework config file at the repository root.
+const preCommitConfig = ".pre-commit-config.yaml"
+
+// preCommitRepo and preCommitHook are the parts of a pre-commit framework config that
+// csvlinter writes.
+type preCommitRepo struct {
+	Repo  string          `yaml:"repo"`
+	Hooks []preCommitHook `yaml:"hooks"`
+}
+
+type preCommitHook struct {
+	ID       string `yaml:"id"`
+	Name     string `yaml:"name"`
+	Entry    string `yaml:"entry"`
+	Language string `yaml:"language"`
+	Files    string `yaml:"files"`
+}
+
+var hookCommand = &cli.Command{
+	Name:  "hook",
+	Usage: "Manage git hook integration",
+	Subcommands: []*cli.Command{
+		{
+			Name:      "install",
+			Usage:     "Install a git pre-commit hook that validates staged CSV files",
+			ArgsUsage: "[-- validate flags...]",
+			Description: "Writes .git/hooks/pre-commit running 'csvlinter validate --staged', or with " +
+				"--pre-commit-framework adds a csvlinter hook to .pre-commit-config.yaml. Arguments after -- " +
+				"are passed to validate, e.g. csvlinter hook install -- --exclude 'fixtures/'",
+			Flags: []cli.Flag{
+				&cli.BoolFlag{
+					Name:  "force",
+					Usage: "Overwrite an existing pre-commit hook not written by csvlinter",
+				},
+				&cli.BoolFlag{
+					Name:  "pre-commit-framework",
+					Usage: "Add a hook to .pre-commit-config.yaml (https://pre-commit.com) instead of writing .git/hooks/pre-commit",
+				},
+			},
+			Action: hookInstallAction,
+		},
+	},
+}
+
+func hookInstallAction(c *cli.Context) error {
+	args := c.Args().Slice()
+	var path string
+	var err error
+	if c.Bool("pre-commit-framework") {
+		path, err = installPreCommitConfig(args)
+	} else {
+		path, err = installGitHook(args, c.Bool("force"))
+	}
+	if err != nil {
+		return cli.Exit(fmt.Sprintf("Error: %v", err), 1)
+	}
+	fmt.Fprintf(c.App.Writer, "Installed csvlinter pre-commit hook in %s\n", path)
+	return nil
+}
+
+// installGitHook writes the repository's pre-commit hook and returns its path.
+func installGitHook(args []string, force bool) (string, error) {
+	hooksDir, err := gitutil.HooksDir(".")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(hooksDir, "pre-commit")
+	existing, err := os.ReadFile(path)
+	if err == nil && !force && !strings.Contains(string(existing), hookMarker) {
+		return "", fmt.Errorf("a pre-commit hook already exists at %s (use --force to overwrite)", path)
+	}
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("failed to read existing hook: %w", err)
+	}
+
+	command := validateCommandLine("--staged", args)
+	script := "#!/bin/sh\n" + hookMarker + "; delete this file to disable.\n" +
+		"# Validates the CSV files staged for commit.\n" +
+		"exec " + command + "\n"
+	if err := os.MkdirAll(hooksDir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create hooks directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		return "", fmt.Errorf("failed to write hook: %w", err)
+	}
+	// WriteFile keeps the mode of an existing file
+	if err := os.Chmod(path, 0o755); err != nil {
+		return "", fmt.Errorf("failed to make hook executable: %w", err)
+	}
+	return path, nil
+}
+
+// installPreCommitConfig adds a local csvlinter hook to the pre-commit framework config at
+// the repository root, creating the file if needed, and returns its path.
+func installPreCommitConfig(args []string) (string, error) {
+	root, err := gitutil.RelativeRoot(".")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(root, preCommitConfig)
+
+	var doc yaml.Node
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return "", fmt.Errorf("failed to read %s: %w", preCommitConfig, err)
+	default:
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return "", fmt.Errorf("failed to parse %s: %w", preCommitConfig, err)
+		}
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	top := doc.Content[0]
+	if top.Kind != yaml.MappingNode {
+		return "", fmt.Errorf("%s: expected a mapping at the top level", preCommitConfig)
+	}
+
+	repos := mappingValue(top, "repos")
+	if repos == nil {
+		repos = &yaml.Node{Kind: yaml.SequenceNode}
+		top.Content = append(top.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "repos"}, repos)
+	}
+	if hasPreCommitHook(repos, "csvlinter") {
+		return "", fmt.Errorf("%s already has a csvlinter hook", path)
+	}
+
+	hook := preCommitHook{
+		ID:       "csvlinter",
+		Name:     "csvlinter",
+		Entry:    validateCommandLine("", args),
+		Language: "system",
+		Files:    `\.(csv|tsv)$`,
+	}
+	var repo yaml.Node
+	if err := repo.Encode(preCommitRepo{Repo: "local", Hooks: []preCommitHook{hook}}); err != nil {
+		return "", err
+	}
+	repos.Content = append(repos.Content, &repo)
+
+	out, err := yaml.Marshal(&doc)
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, out, 0o644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", preCommitConfig, err)
+	}
+	return path, nil
+}
+
+// mappingValue returns the value node for key in a YAML mapping, or nil.
+func mappingValue(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// hasPreCommitHook reports whether any repo in repos defines a hook with id.
+func hasPreCommitHook(repos *yaml.Node, id string) bool {
+	for _, repo := range repos.Content {
+		if repo.Kind != yaml.MappingNode {
+			continue
+		}
+		hooks := mappingValue(repo, "hooks")
+		if hooks == nil {
+			continue
+		}
+		for _, h := range hooks.Content {
+			if h.Kind == yaml.MappingNode {
+				if v := mappingValue(h, "id"); v != nil && v.Value == id {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// validateCommandLine builds the csvlinter validate invocation run by a hook.
+func validateCommandLine(mode string, args []string) string {
+	command := "csvlinter validate"
+	if mode != "" {
+		command += " " + mode
+	}
+	for _, a := range args {
+		command += " " + shellQuote(a)
+	}
+	return command
+}
+
+// shellQuote quotes s for a POSIX shell when it contains anything but safe characters.
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./=:,", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHookInstall(t *testing.T) {
+	dir := inGitRepo(t)
+	hookPath := filepath.Join(dir, ".git", "hooks", "pre-commit")
+
+	stdout, stderr, code := runApp(t, "hook", "install", "--", "--exclude", "fixtures/*.csv")
+	if code != 0 {
+		t.Fatalf("want exit 0, got %d; stderr=%s", code, stderr)
+	}
+	if !strings.Contains(stdout, "pre-commit") {
+		t.Errorf("unexpected output: %q", stdout)
+	}
+	script, err := os.ReadFile(hookPath)
+	if err != nil {
+		t.Fatalf("read hook: %v", err)
+	}
+	if !strings.Contains(string(script), "exec csvlinter validate --staged --exclude 'fixtures/*.csv'\n") {
+		t.Errorf("unexpected hook script:\n%s", script)
+	}
+	if info, _ := os.Stat(hookPath); info.Mode().Perm()&0o100 == 0 {
+		t.Errorf("hook is not executable: %v", info.Mode())
+	}
+
+	// Reinstalling over our own hook is fine; a foreign hook needs --force
+	if _, _, code := runApp(t, "hook", "install"); code != 0 {
+		t.Errorf("want reinstall to succeed, got exit %d", code)
+	}
+	if err := os.WriteFile(hookPath, []byte("#!/bin/sh\nmake lint\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, code := runApp(t, "hook", "install"); code != 1 {
+		t.Errorf("want refusal to overwrite a foreign hook, got exit %d", code)
+	}
+	if script, _ := os.ReadFile(hookPath); !strings.Contains(string(script), "make lint") {
+		t.Errorf("foreign hook was overwritten:\n%s", script)
+	}
+	if _, _, code := runApp(t, "hook", "install", "--force"); code != 0 {
+		t.Errorf("want --force to overwrite, got exit %d", code)
+	}
+}
+
+func TestHookInstall_PreCommitFramework(t *testing.T) {
+	dir := inGitRepo(t)
+	configPath := filepath.Join(dir, ".pre-commit-config.yaml")
+	existing := "# shared hooks\nrepos:\n  - repo: https://github.com/pre-commit/pre-commit-hooks\n    rev: v4.5.0\n    hooks:\n      - id: end-of-file-fixer\n"
+	if err := os.WriteFile(configPath, []byte(existing), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir("sub", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir("sub"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, stderr, code := runApp(t, "hook", "install", "--pre-commit-framework", "--", "--fail-fast"); code != 0 {
+		t.Fatalf("want exit 0, got %d; stderr=%s", code, stderr)
+	}
+	config, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"# shared hooks", "end-of-file-fixer", "repo: local", "id: csvlinter", "entry: csvlinter validate --fail-fast", "language: system"} {
+		if !strings.Contains(string(config), want) {
+			t.Errorf("expected %q in config:\n%s", want, config)
+		}
+	}
+	if _, _, code := runApp(t, "hook", "install", "--pre-commit-framework"); code != 1 {
+		t.Errorf("want a second install to be refused, got exit %d", code)
+	}
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/csvlinter/csvlinter/internal/reporter"
+
+	"github.com/urfave/cli/v2"
+)
+
+var integrationCommand = &cli.Command{
+	Name:  "integration",
+	Usage: "Print editor configuration that reads csvlinter's compact output",
+	Subcommands: []*cli.Command{
+		{
+			Name:   "vscode",
+			Usage:  "Print a VS Code tasks.json with a csvlinter problem matcher",
+			Action: integrationVSCodeAction,
+		},
+		{
+			Name:   "vim",
+			Usage:  "Print Vim settings (makeprg and errorformat) for :make",
+			Action: integrationVimAction,
+		},
+	},
+}
+
+// vscodeTasks is the subset of the VS Code tasks.json schema csvlinter writes.
+type vscodeTasks struct {
+	Version string       `json:"version"`
+	Tasks   []vscodeTask `json:"tasks"`
+}
+
+type vscodeTask struct {
+	Label          string               `json:"label"`
+	Type           string               `json:"type"`
+	Command        string               `json:"command"`
+	Args           []string             `json:"args"`
+	Group          string               `json:"group"`
+	ProblemMatcher vscodeProblemMatcher `json:"problemMatcher"`
+}
+
+type vscodeProblemMatcher struct {
+	Owner        string        `json:"owner"`
+	Source       string        `json:"source"`
+	FileLocation []string      `json:"fileLocation"`
+	Pattern      vscodePattern `json:"pattern"`
+}
+
+type vscodePattern struct {
+	Regexp   string `json:"regexp"`
+	File     int    `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Severity int    `json:"severity"`
+	Code     int    `json:"code"`
+	Message  int    `json:"message"`
+}
+
+func integrationVSCodeAction(c *cli.Context) error {
+	// Group numbers follow the submatches documented on reporter.CompactPattern
+	matcher := vscodeProblemMatcher{
+		Owner:        "csvlinter",
+		Source:       "csvlinter",
+		FileLocation: []string{"autoDetect", "${workspaceFolder}"},
+		Pattern: vscodePattern{
+			Regexp:   reporter.CompactPattern,
+			File:     1,
+			Line:     2,
+			Column:   3,
+			Severity: 4,
+			Code:     5,
+			Message:  6,
+		},
+	}
+	tasks := vscodeTasks{
+		Version: "2.0.0",
+		Tasks: []vscodeTask{
+			{
+				Label:          "csvlinter: validate current file",
+				Type:           "shell",
+				Command:        "csvlinter",
+				Args:           []string{"validate", "--format", "compact", "${file}"},
+				Group:          "test",
+				ProblemMatcher: matcher,
+			},
+			{
+				Label:          "csvlinter: validate workspace",
+				Type:           "shell",
+				Command:        "csvlinter",
+				Args:           []string{"validate", "--format", "compact", "."},
+				Group:          "test",
+				ProblemMatcher: matcher,
+			},
+		},
+	}
+	b, err := json.MarshalIndent(tasks, "", "  ")
+	if err != nil {
+		return cli.Exit(fmt.Sprintf("Error: %v", err), 1)
+	}
+	fmt.Fprintln(c.App.Writer, string(b))
+	return nil
+}
+
+func integrationVimAction(c *cli.Context) error {
+	fmt.Fprintln(c.App.Writer, `" csvlinter: run :make to validate the current file into the quickfix list`)
+	fmt.Fprintln(c.App.Writer, `let &l:makeprg = 'csvlinter validate --format compact %'`)
+	fmt.Fprintf(c.App.Writer, "let &l:errorformat = '%s'\n", strings.ReplaceAll(reporter.CompactVimErrorformat(), "'", "''"))
+	return nil
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestIntegrationVSCode(t *testing.T) {
+	stdout, _, code := runApp(t, "integration", "vscode")
+	if code != 0 {
+		t.Fatalf("want exit 0, got %d", code)
+	}
+	var tasks vscodeTasks
+	if err := json.Unmarshal([]byte(stdout), &tasks); err != nil {
+		t.Fatalf("invalid tasks.json: %v\n%s", err, stdout)
+	}
+	if len(tasks.Tasks) == 0 {
+		t.Fatal("expected at least one task")
+	}
+
+	// The problem matcher must understand what validate --format compact prints
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "data.csv")
+	if err := os.WriteFile(csvPath, []byte("id,name\n1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out, _, _ := runApp(t, "validate", "--format", "compact", csvPath)
+	p := tasks.Tasks[0].ProblemMatcher.Pattern
+	m := regexp.MustCompile(p.Regexp).FindStringSubmatch(strings.TrimSpace(out))
+	if m == nil {
+		t.Fatalf("problem matcher does not match %q", out)
+	}
+	if m[p.File] != csvPath || m[p.Line] != "2" || m[p.Severity] != "error" || m[p.Code] != "STR001" {
+		t.Errorf("unexpected captures: %q", m)
+	}
+}
+
+func TestIntegrationVim(t *testing.T) {
+	stdout, _, code := runApp(t, "integration", "vim")
+	if code != 0 {
+		t.Fatalf("want exit 0, got %d", code)
+	}
+	for _, want := range []string{"makeprg = 'csvlinter validate --format compact %'", "errorformat = '%f:%l:%c %trror %m,"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q in:\n%s", want, stdout)
+		}
+	}
+}
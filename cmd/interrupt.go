@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/urfave/cli/v2"
+)
+
+// interruptOnSignal returns a channel closed on the first SIGINT or SIGTERM, for
+// Options.Interrupt, and a function to stop listening. Validation then stops and the
+// report of what was validated is still written, so a run killed by a CI job timeout
+// leaves it behind; a second signal terminates the process as usual.
+func interruptOnSignal(c *cli.Context) (<-chan struct{}, func()) {
+	ctx, stop := signal.NotifyContext(c.Context, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	return ctx.Done(), stop
+}
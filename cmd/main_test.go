@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/cache"
+)
+
+// TestMain points the result cache at a throwaway directory so tests never read or
+// write the user's cache.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "csvlinter-cache-")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv(cache.EnvDir, dir)
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/csvlinter/csvlinter/internal/parser"
+	"github.com/csvlinter/csvlinter/internal/profile"
+	"github.com/csvlinter/csvlinter/internal/transform"
+	"github.com/csvlinter/csvlinter/internal/validator"
+
+	"github.com/urfave/cli/v2"
+)
+
+var profileCommand = &cli.Command{
+	Name:      "profile",
+	Usage:     "Record the null rate, mean and distinct count of each column as a baseline for validate --profile",
+	ArgsUsage: "[file]",
+	Description: "Profiles rows as validation sees them, after the config's transforms; rows with the wrong number " +
+		"of fields are left out. Writes JSON to --save or stdout. Reads STDIN when no file (or -) is given.",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "save",
+			Usage: "Write the profile to this file instead of stdout",
+		},
+		&cli.StringFlag{
+			Name:    "delimiter",
+			Aliases: []string{"d"},
+			Usage:   "Delimiter character, or whitespace for columns separated by runs of spaces and tabs (default: tab for .tsv files, comma otherwise)",
+		},
+		&cli.StringFlag{
+			Name:  "config",
+			Usage: "Path to a config file. If not set, the .csvlinter.yml files from the CSV file's directory up to the project root are merged, deeper ones winning",
+		},
+	},
+	Action: profileAction,
+}
+
+func profileAction(c *cli.Context) error {
+	cfg, err := loadConfigFor(c, c.Args().First())
+	if err != nil {
+		return cli.Exit(fmt.Sprintf("Error: %v", err), 1)
+	}
+	var rowTransform validator.Transform
+	if len(cfg.Transforms) > 0 {
+		t, err := transform.New(cfg.Transforms)
+		if err != nil {
+			return cli.Exit(fmt.Sprintf("Error: invalid transform: %v", err), 1)
+		}
+		rowTransform = t
+	}
+
+	path := c.Args().First()
+	var input io.Reader = os.Stdin
+	name := "STDIN"
+	if path != "" && path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return cli.Exit(fmt.Sprintf("Error: Cannot open file '%s': %v", path, err), 1)
+		}
+		defer f.Close()
+		input, name = f, path
+	}
+	delimiter := c.String("delimiter")
+	if delimiter == "" {
+		delimiter = parser.DelimiterFor(path)
+	}
+
+	collector := profile.NewCollector()
+	_, err = validator.NewWithOptions(input, validator.Options{
+		Name:      name,
+		Delimiter: delimiter,
+		Checks:    []validator.Check{collector},
+		Transform: rowTransform,
+	}).Validate()
+	if err != nil {
+		return cli.Exit(fmt.Sprintf("Error: %v", err), 1)
+	}
+	p := collector.Profile()
+
+	if save := c.String("save"); save != "" {
+		if err := p.Save(save); err != nil {
+			return cli.Exit(fmt.Sprintf("Error: Cannot write profile '%s': %v", save, err), 1)
+		}
+		return nil
+	}
+	enc := json.NewEncoder(c.App.Writer)
+	enc.SetIndent("", "  ")
+	return enc.Encode(p)
+}
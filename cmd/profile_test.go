@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/profile"
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+func TestProfileCommand(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"base.csv":  "id,amount\n1,10\n2,12\n3,11\n4,9\n",
+		"today.csv": "id,amount\n1,10000\n2,12000\n3,11000\n4,9000\n",
+		"lint.yml": `drift:
+  profile: base.profile.json
+  mean: 1000
+`,
+	})
+	baseline := filepath.Join(dir, "base.profile.json")
+
+	stdout, _, code := runApp(t, "profile", filepath.Join(dir, "base.csv"))
+	var p profile.Profile
+	if err := json.Unmarshal([]byte(stdout), &p); err != nil || code != 0 {
+		t.Fatalf("want a JSON profile on stdout, got %d: %s", code, stdout)
+	}
+	if p.Rows != 4 || len(p.Columns) != 2 || p.Columns[1].Mean == nil || *p.Columns[1].Mean != 10.5 {
+		t.Errorf("want the profile of base.csv, got %+v", p)
+	}
+	if _, _, code := runApp(t, "profile", "--save", baseline, filepath.Join(dir, "base.csv")); code != 0 {
+		t.Fatalf("want exit 0 for --save, got %d", code)
+	}
+
+	// Drift is a warning; the file stays valid
+	stdout, _, code = runApp(t, "validate", "--format", "json", "--no-cache", "--profile", baseline, filepath.Join(dir, "today.csv"))
+	var res validator.Results
+	if err := json.Unmarshal([]byte(stdout), &res); err != nil {
+		t.Fatalf("invalid json output: %v\nstdout=%s", err, stdout)
+	}
+	if code != 0 || !res.Valid || len(res.Warnings) != 1 || res.Warnings[0].RuleID != "DAT005" || res.Warnings[0].Field != "amount" ||
+		!strings.Contains(res.Warnings[0].Message, "mean of amount drifted from 10.5 to 10500") {
+		t.Errorf("want one drift warning for amount, got %d: %+v", code, res.Warnings)
+	}
+
+	// The config's profile is resolved next to it, with its thresholds
+	stdout, _, _ = runApp(t, "validate", "--format", "json", "--no-cache", "--config", filepath.Join(dir, "lint.yml"), filepath.Join(dir, "today.csv"))
+	res = validator.Results{}
+	if err := json.Unmarshal([]byte(stdout), &res); err != nil || len(res.Warnings) != 0 {
+		t.Errorf("want no drift within the configured mean threshold, got %s", stdout)
+	}
+
+	stdout, _, code = runApp(t, "validate", "--format", "json", "--profile", filepath.Join(dir, "missing.json"), filepath.Join(dir, "today.csv"))
+	if code != 2 || !strings.Contains(stdout, `"INVALID_ARGUMENT"`) {
+		t.Errorf("want INVALID_ARGUMENT for a missing profile, got %d: %s", code, stdout)
+	}
+}
@@ -0,0 +1,18 @@
+package cmd
+
+import (
+	"github.com/csvlinter/csvlinter/internal/reporter"
+
+	"github.com/urfave/cli/v2"
+)
+
+var reportSchemaCommand = &cli.Command{
+	Name:  "report-schema",
+	Usage: "Print the JSON Schema of the --format json report",
+	Description: "The schema covers single-file and multi-file reports and operational error documents at report_schema_version " +
+		reporter.SchemaVersion + ", for validating reports and generating client types.",
+	Action: func(c *cli.Context) error {
+		_, err := c.App.Writer.Write(reporter.ReportSchema())
+		return err
+	},
+}
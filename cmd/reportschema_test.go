@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+func TestReportSchemaCommand(t *testing.T) {
+	stdout, _, code := runApp(t, "report-schema")
+	if code != 0 {
+		t.Fatalf("want exit 0, got %d", code)
+	}
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource("report.schema.json", strings.NewReader(stdout)); err != nil {
+		t.Fatalf("report-schema printed an invalid schema: %v", err)
+	}
+	s, err := compiler.Compile("report.schema.json")
+	if err != nil {
+		t.Fatalf("report-schema printed an invalid schema: %v", err)
+	}
+
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"good.csv": "id,name\n1,a\n",
+		"bad.csv":  "id,name\n1,a,extra\n2\n",
+	})
+	reports := map[string][]string{
+		"single": {"validate", "--format", "json", filepath.Join(dir, "bad.csv")},
+		"batch":  {"validate", "--format", "json", filepath.Join(dir, "good.csv"), filepath.Join(dir, "bad.csv")},
+		"error":  {"validate", "--format", "json", filepath.Join(dir, "missing.csv")},
+	}
+	for name, args := range reports {
+		out, _, _ := runApp(t, args...)
+		var doc interface{}
+		if err := json.Unmarshal([]byte(out), &doc); err != nil {
+			t.Fatalf("%s: output is not JSON: %v\n%s", name, err, out)
+		}
+		if err := s.Validate(doc); err != nil {
+			t.Errorf("%s report does not match the report schema: %v\n%s", name, err, out)
+		}
+	}
+}
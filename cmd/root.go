@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/urfave/cli/v2"
@@ -9,17 +10,50 @@ import (
 // Version is set at build time via -ldflags (e.g. goreleaser sets it from the Git tag).
 var Version = "dev"
 
-// Execute runs the CLI application
-func Execute() error {
-	app := &cli.App{
+// Commit and BuildDate are set at build time via -ldflags. When empty, the version
+// command reads them from the version control information in the binary, if any.
+var (
+	Commit    = ""
+	BuildDate = ""
+)
+
+// newApp builds the CLI application with all commands registered.
+func newApp() *cli.App {
+	return &cli.App{
 		Name:        "csvlinter",
 		Usage:       "A modern, streaming-first CSV validator with JSON Schema support",
 		Description: "Validates structure, content, and encoding of CSV files — built for CI, CLI, and editor integration",
-		Version:     Version,
+		Version:     versionString(),
 		Commands: []*cli.Command{
 			validateCommand,
+			fixCommand,
+			profileCommand,
+			cacheCommand,
+			configCommand,
+			hookCommand,
+			integrationCommand,
+			streamCommand,
+			ddlCommand,
+			codegenCommand,
+			reportSchemaCommand,
+			rulesCommand,
+			schemaCommand,
+			versionCommand,
+		},
+		// An unknown command is a usage error, not the exit code 3 of schema errors
+		CommandNotFound: func(c *cli.Context, command string) {
+			fmt.Fprintf(c.App.ErrWriter, "No help topic for '%v'\n", command)
+			err := cli.Exit("", exitUsage)
+			if c.App.ExitErrHandler != nil {
+				c.App.ExitErrHandler(c, err)
+				return
+			}
+			cli.HandleExitCoder(err)
 		},
 	}
+}
 
-	return app.Run(os.Args)
+// Execute runs the CLI application
+func Execute() error {
+	return newApp().Run(os.Args)
 }
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/csvlinter/csvlinter/internal/i18n"
+	"github.com/csvlinter/csvlinter/internal/rules"
+
+	"github.com/urfave/cli/v2"
+)
+
+var rulesCommand = &cli.Command{
+	Name:  "rules",
+	Usage: "List the rules behind findings with their IDs, types and descriptions",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    "lang",
+			Usage:   "Language of the descriptions: en (the default), de, fr or ja; IDs and types are never translated",
+			EnvVars: []string{"CSVLINTER_LANG"},
+		},
+		&cli.BoolFlag{
+			Name:  "json",
+			Usage: "Print the rules as a JSON array of id, type and description",
+		},
+	},
+	Action: rulesAction,
+}
+
+func rulesAction(c *cli.Context) error {
+	catalog, err := i18n.Lookup(c.String("lang"))
+	if err != nil {
+		return cli.Exit(fmt.Sprintf("Error: %v", err), 1)
+	}
+	all := rules.All()
+	for i, r := range all {
+		all[i].Description = catalog.Rule(r.ID, r.Description)
+	}
+	if c.Bool("json") {
+		enc := json.NewEncoder(c.App.Writer)
+		enc.SetIndent("", "  ")
+		return enc.Encode(all)
+	}
+	w := tabwriter.NewWriter(c.App.Writer, 0, 0, 2, ' ', 0)
+	for _, r := range all {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", r.ID, r.Type, r.Description)
+	}
+	return w.Flush()
+}
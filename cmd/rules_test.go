@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRulesCommand(t *testing.T) {
+	stdout, _, code := runApp(t, "rules")
+	if code != 0 || !strings.Contains(stdout, "STR001  structure  Row has a different number of fields than the header\n") {
+		t.Errorf("want the rule catalog, got %d: %s", code, stdout)
+	}
+
+	stdout, _, code = runApp(t, "rules", "--lang", "de", "--json")
+	var list []struct{ ID, Type, Description string }
+	if err := json.Unmarshal([]byte(stdout), &list); err != nil || code != 0 || len(list) == 0 {
+		t.Fatalf("want a JSON list, got %d, %v: %s", code, err, stdout)
+	}
+	if list[0].ID != "DAT001" || list[0].Type != "data" || !strings.HasPrefix(list[0].Description, "Gruppe von Zeilen") {
+		t.Errorf("want German descriptions with English IDs and types, got %+v", list[0])
+	}
+
+	if _, _, code = runApp(t, "rules", "--lang", "xx"); code != 1 {
+		t.Errorf("want an unknown language rejected, got %d", code)
+	}
+}
+
+func TestValidateCommand_Lang(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"users.csv": "id,name\n1\n",
+		"lint.yml":  "lang: ja\n",
+	})
+
+	stdout, _, code := runApp(t, "validate", "--lang", "de", filepath.Join(dir, "users.csv"))
+	if code != 1 || !strings.Contains(stdout, "Zeile 2 (row): Spaltenanzahl stimmt nicht: erwartet 2, erhalten 1 [structure]") {
+		t.Errorf("want a German report, got %d: %s", code, stdout)
+	}
+
+	stdout, _, _ = runApp(t, "validate", "--config", filepath.Join(dir, "lint.yml"), filepath.Join(dir, "users.csv"))
+	if !strings.Contains(stdout, "ステータス: ✗ 無効\n") {
+		t.Errorf("want the config's language, got %s", stdout)
+	}
+
+	stdout, _, _ = runApp(t, "validate", "--lang", "fr", "--format", "json", filepath.Join(dir, "users.csv"))
+	if !strings.Contains(stdout, `"message": "column count mismatch: expected 2, got 1"`) {
+		t.Errorf("want English JSON, got %s", stdout)
+	}
+
+	stdout, _, code = runApp(t, "validate", "--lang", "xx", "--format", "json", filepath.Join(dir, "users.csv"))
+	if code != 2 || !strings.Contains(stdout, `"INVALID_ARGUMENT"`) {
+		t.Errorf("want INVALID_ARGUMENT for an unknown language, got %d: %s", code, stdout)
+	}
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/urfave/cli/v2"
+)
+
+// runApp runs the full CLI with args (without the program name) and returns what it
+// wrote to stdout and stderr together with the exit code.
+func runApp(t *testing.T, args ...string) (stdout, stderr string, exitCode int) {
+	t.Helper()
+
+	var out, errOut bytes.Buffer
+	app := newApp()
+	app.Writer = &out
+	app.ErrWriter = &errOut
+	app.ExitErrHandler = func(c *cli.Context, err error) {
+		if err != nil {
+			if ec, ok := err.(cli.ExitCoder); ok {
+				exitCode = ec.ExitCode()
+			} else {
+				exitCode = 1
+			}
+		}
+	}
+	_ = app.Run(append([]string{"csvlinter"}, args...))
+	return out.String(), errOut.String(), exitCode
+}
@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/csvlinter/csvlinter/internal/schema"
+
+	"github.com/urfave/cli/v2"
+)
+
+var schemaCommand = &cli.Command{
+	Name:  "schema",
+	Usage: "Work with row schemas",
+	Subcommands: []*cli.Command{
+		{
+			Name:      "diff",
+			Usage:     "Report breaking and non-breaking changes between two versions of a schema",
+			ArgsUsage: "<old.schema.json> <new.schema.json>",
+			Description: "A change is breaking when rows valid under the old schema may be invalid under the new one, " +
+				"e.g. a newly required column, a narrowed type or a removed enum value. Exits 1 when there are breaking changes.",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    "format",
+					Aliases: []string{"f"},
+					Value:   "pretty",
+					Usage:   "Output format: pretty or json",
+				},
+			},
+			Action: schemaDiffAction,
+		},
+		{
+			Name:      "bundle",
+			Usage:     "Inline the files a schema's $refs point to into one self-contained schema",
+			ArgsUsage: "<schema.json>",
+			Description: "Embeds every file referenced with $ref, directly or through other files, under $defs " +
+				"and points the references there, for systems that cannot read the referenced files. Flags go before the schema.",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    "out",
+					Aliases: []string{"o"},
+					Usage:   "Write the bundled schema here instead of stdout",
+				},
+				&cli.StringFlag{
+					Name:  "schema-root",
+					Usage: "Directory the referenced files must be in",
+				},
+			},
+			Action: schemaBundleAction,
+		},
+		{
+			Name:      "lint",
+			Usage:     "Flag common problems of schemas for CSV files",
+			ArgsUsage: "<schema.json>",
+			Description: "Reports property names in no header naming convention, patterns without ^ and $, " +
+				"types no CSV value can have, such as boolean, and a missing additionalProperties. Exits 1 when there are issues.",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    "format",
+					Aliases: []string{"f"},
+					Value:   "pretty",
+					Usage:   "Output format: pretty or json",
+				},
+			},
+			Action: schemaLintAction,
+		},
+	},
+}
+
+func schemaLintAction(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return cli.Exit("Error: schema lint needs one schema file", 1)
+	}
+	format := c.String("format")
+	if format != "pretty" && format != "json" {
+		return cli.Exit(fmt.Sprintf("Error: invalid format '%s' (use pretty or json)", format), 1)
+	}
+	path := c.Args().First()
+	data, err := schema.ReadFile(path)
+	if err != nil {
+		return cli.Exit(fmt.Sprintf("Error: Cannot read schema '%s': %v", path, err), 1)
+	}
+	issues, err := schema.Lint(data)
+	if err != nil {
+		return cli.Exit(fmt.Sprintf("Error: %v", err), 1)
+	}
+
+	w := c.App.Writer
+	if format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(struct {
+			Issues []schema.Issue `json:"issues"`
+		}{append([]schema.Issue{}, issues...)}); err != nil {
+			return err
+		}
+	} else if len(issues) == 0 {
+		fmt.Fprintln(w, "No issues")
+	} else {
+		fmt.Fprintf(w, "Issues (%d):\n", len(issues))
+		for _, i := range issues {
+			if i.Column == "" {
+				fmt.Fprintf(w, "  - [%s] %s\n", i.Check, i.Message)
+			} else {
+				fmt.Fprintf(w, "  - [%s] %s: %s\n", i.Check, i.Column, i.Message)
+			}
+		}
+	}
+	if len(issues) > 0 {
+		return cli.Exit("", 1)
+	}
+	return nil
+}
+
+func schemaBundleAction(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return cli.Exit("Error: schema bundle needs one schema file, after any flags", 1)
+	}
+	var opts []schema.Option
+	if root := c.String("schema-root"); root != "" {
+		opts = append(opts, schema.WithRoot(root))
+	}
+	bundled, err := schema.Bundle(c.Args().First(), opts...)
+	if err != nil {
+		return cli.Exit(fmt.Sprintf("Error: %v", err), 1)
+	}
+	if out := c.String("out"); out != "" {
+		if err := os.WriteFile(out, bundled, 0644); err != nil {
+			return cli.Exit(fmt.Sprintf("Error: Cannot write '%s': %v", out, err), 1)
+		}
+		return nil
+	}
+	_, err = c.App.Writer.Write(bundled)
+	return err
+}
+
+// schemaDiffDocument is the JSON output of schema diff.
+type schemaDiffDocument struct {
+	Breaking    []schema.Change `json:"breaking"`
+	NonBreaking []schema.Change `json:"non_breaking"`
+}
+
+func schemaDiffAction(c *cli.Context) error {
+	if c.NArg() != 2 {
+		return cli.Exit("Error: schema diff needs the old and the new schema file", 1)
+	}
+	format := c.String("format")
+	if format != "pretty" && format != "json" {
+		return cli.Exit(fmt.Sprintf("Error: invalid format '%s' (use pretty or json)", format), 1)
+	}
+	var docs [2][]byte
+	for i, path := range c.Args().Slice() {
+		data, err := schema.ReadFile(path)
+		if err != nil {
+			return cli.Exit(fmt.Sprintf("Error: Cannot read schema '%s': %v", path, err), 1)
+		}
+		docs[i] = data
+	}
+	changes, err := schema.Diff(docs[0], docs[1])
+	if err != nil {
+		return cli.Exit(fmt.Sprintf("Error: %v", err), 1)
+	}
+	doc := schemaDiffDocument{Breaking: []schema.Change{}, NonBreaking: []schema.Change{}}
+	for _, ch := range changes {
+		if ch.Breaking {
+			doc.Breaking = append(doc.Breaking, ch)
+		} else {
+			doc.NonBreaking = append(doc.NonBreaking, ch)
+		}
+	}
+
+	w := c.App.Writer
+	if format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(doc); err != nil {
+			return err
+		}
+	} else {
+		if len(changes) == 0 {
+			fmt.Fprintln(w, "No changes")
+		}
+		for _, group := range []struct {
+			title   string
+			changes []schema.Change
+		}{{"Breaking changes", doc.Breaking}, {"Non-breaking changes", doc.NonBreaking}} {
+			if len(group.changes) == 0 {
+				continue
+			}
+			fmt.Fprintf(w, "%s (%d):\n", group.title, len(group.changes))
+			for _, ch := range group.changes {
+				if ch.Column == "" {
+					fmt.Fprintf(w, "  - %s\n", ch.Message)
+				} else {
+					fmt.Fprintf(w, "  - %s: %s\n", ch.Column, ch.Message)
+				}
+			}
+		}
+	}
+	if len(doc.Breaking) > 0 {
+		return cli.Exit("", 1)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSchemaDiffCommand(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"v1.schema.json": `{"required":["id"],"properties":{"id":{"type":"integer"},"status":{"enum":["open","pending"]}}}`,
+		"v2.schema.json": `{"required":["id"],"properties":{"id":{"type":"integer"},"status":{"enum":["open","pending","closed"]},"note":{"type":"string"}}}`,
+		"v3.schema.json": `{"required":["id","note"],"properties":{"id":{"type":"integer"},"status":{"enum":["open"]},"note":{"type":"string"}}}`,
+	})
+	v1, v2, v3 := filepath.Join(dir, "v1.schema.json"), filepath.Join(dir, "v2.schema.json"), filepath.Join(dir, "v3.schema.json")
+
+	stdout, _, code := runApp(t, "schema", "diff", v1, v2)
+	if code != 0 || !strings.Contains(stdout, "Non-breaking changes (2):\n  - note: column added\n  - status: allowed value \"closed\" added\n") {
+		t.Errorf("want exit 0 with two non-breaking changes, got %d:\n%s", code, stdout)
+	}
+
+	stdout, _, code = runApp(t, "schema", "diff", "--format", "json", v2, v3)
+	var doc struct {
+		Breaking    []map[string]any `json:"breaking"`
+		NonBreaking []map[string]any `json:"non_breaking"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &doc); err != nil {
+		t.Fatalf("invalid json output: %v\nstdout=%s", err, stdout)
+	}
+	if code != 1 || len(doc.Breaking) != 3 || len(doc.NonBreaking) != 0 || doc.Breaking[0]["column"] != "note" {
+		t.Errorf("want exit 1 with three breaking changes, got %d: %+v", code, doc)
+	}
+
+	if stdout, _, code := runApp(t, "schema", "diff", v1, v1); code != 0 || stdout != "No changes\n" {
+		t.Errorf("want no changes, got %d: %q", code, stdout)
+	}
+	if _, _, code := runApp(t, "schema", "diff", v1); code != 1 {
+		t.Errorf("want exit 1 without a second schema, got %d", code)
+	}
+}
+
+func TestSchemaBundleCommand(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"orders.schema.json": `{"type":"object","properties":{"country":{"$ref":"types.json#/$defs/country"}}}`,
+		"types.json":         `{"$defs":{"country":{"type":"string","pattern":"^[A-Z]{2}$"}}}`,
+		"orders.csv":         "country\nDE\nGermany\n",
+	})
+	out := filepath.Join(dir, "bundled.json")
+	if _, _, code := runApp(t, "schema", "bundle", "--out", out, filepath.Join(dir, "orders.schema.json")); code != 0 {
+		t.Fatalf("want exit 0, got %d", code)
+	}
+
+	// The bundled schema works without the referenced file
+	writeTree(t, dir, map[string]string{"types.json": `{}`})
+	stdout, _, code := runApp(t, "validate", "--format", "json", "--no-cache", "--schema", out, filepath.Join(dir, "orders.csv"))
+	if code != 1 || !strings.Contains(stdout, `"line_number": 3`) {
+		t.Errorf("want line 3 rejected by the bundled pattern, got %d: %s", code, stdout)
+	}
+
+	stdout, _, code = runApp(t, "schema", "bundle", out)
+	if code != 0 || !strings.Contains(stdout, `"$ref": "#/$defs/types/$defs/country"`) {
+		t.Errorf("want a bundle of a bundle unchanged on stdout, got %d: %s", code, stdout)
+	}
+	if _, _, code := runApp(t, "schema", "bundle", out, "--out", "x.json"); code != 1 {
+		t.Errorf("want exit 1 for flags after the schema, got %d", code)
+	}
+}
+
+func TestSchemaLintCommand(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"loose.schema.json":  `{"properties":{"code":{"type":"string","pattern":"[A-Z]{3}"},"paid":{"type":"boolean"}}}`,
+		"strict.schema.json": `{"additionalProperties":false,"properties":{"code":{"type":"string","pattern":"^[A-Z]{3}$"}}}`,
+	})
+
+	stdout, _, code := runApp(t, "schema", "lint", filepath.Join(dir, "loose.schema.json"))
+	if code != 1 || !strings.HasPrefix(stdout, "Issues (3):\n  - [additional-properties] additionalProperties is not set") ||
+		!strings.Contains(stdout, "  - [pattern] code: pattern [A-Z]{3} is not anchored") {
+		t.Errorf("want exit 1 with three issues, got %d:\n%s", code, stdout)
+	}
+
+	stdout, _, _ = runApp(t, "schema", "lint", "--format", "json", filepath.Join(dir, "loose.schema.json"))
+	var doc struct {
+		Issues []map[string]string `json:"issues"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &doc); err != nil || len(doc.Issues) != 3 || doc.Issues[2]["check"] != "type" {
+		t.Errorf("want three issues in JSON, got %s (%v)", stdout, err)
+	}
+
+	if stdout, _, code := runApp(t, "schema", "lint", filepath.Join(dir, "strict.schema.json")); code != 0 || stdout != "No issues\n" {
+		t.Errorf("want no issues, got %d: %q", code, stdout)
+	}
+}
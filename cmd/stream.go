@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/csvlinter/csvlinter/pkg/csvlinter"
+
+	"github.com/urfave/cli/v2"
+)
+
+var streamCommand = &cli.Command{
+	Name:      "stream",
+	Usage:     "Validate CSV records as they arrive and print findings as NDJSON",
+	ArgsUsage: "[file] (default: STDIN)",
+	Description: "Reads records from STDIN, or from a file, and writes one JSON finding per line as soon as it is " +
+		"found, for validating streaming ingestion. With --follow, a file is read like tail -f until interrupted. " +
+		"The exit code is 1 when any error was found.",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    "schema",
+			Aliases: []string{"s"},
+			Usage:   "Path to JSON Schema file every record is validated against",
+		},
+		&cli.StringFlag{
+			Name:    "delimiter",
+			Aliases: []string{"d"},
+			Value:   ",",
+			Usage:   "Delimiter character, or whitespace for columns separated by runs of spaces and tabs",
+		},
+		&cli.StringFlag{
+			Name:  "header",
+			Usage: "Column names, separated by the delimiter, for streams without a header line; record line numbers then count this header as line 1",
+		},
+		&cli.BoolFlag{
+			Name:    "follow",
+			Aliases: []string{"F"},
+			Usage:   "Keep reading the file as it grows, like tail -f, until interrupted (SIGINT/SIGTERM)",
+		},
+		&cli.DurationFlag{
+			Name:   "poll-interval",
+			Value:  250 * time.Millisecond,
+			Usage:  "How often --follow checks the file for new data",
+			Hidden: true,
+		},
+	},
+	Action: streamAction,
+}
+
+func streamAction(c *cli.Context) error {
+	name := c.Args().First()
+	fromStdin := name == "" || name == "-"
+	var input io.Reader = os.Stdin
+	if !fromStdin {
+		f, err := os.Open(name)
+		if err != nil {
+			code := csvlinter.CodeFileUnreadable
+			if errors.Is(err, fs.ErrNotExist) {
+				code = csvlinter.CodeFileNotFound
+			}
+			return exitError(c, "json", code, fmt.Sprintf("Error: Cannot open file '%s': %v", name, err))
+		}
+		defer f.Close()
+		input = f
+		if c.Bool("follow") {
+			// Stop following on interrupt; validation then finishes normally
+			ctx, stop := signal.NotifyContext(c.Context, os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			input = &followReader{ctx: ctx, r: f, interval: c.Duration("poll-interval")}
+		}
+	} else if c.Bool("follow") {
+		return exitError(c, "json", csvlinter.CodeInvalidArgument, "Error: --follow needs a file; STDIN is always read until it closes")
+	}
+	if header := c.String("header"); header != "" {
+		input = io.MultiReader(strings.NewReader(header+"\n"), input)
+	}
+
+	opts := csvlinter.Options{
+		Delimiter:      c.String("delimiter"),
+		SchemaPath:     c.String("schema"),
+		MaxValueLength: csvlinter.DefaultMaxValueLength,
+	}
+	if !fromStdin {
+		opts.Filename = name
+	}
+
+	enc := json.NewEncoder(c.App.Writer)
+	results, err := csvlinter.NewValidator(input, opts).ValidateStream(c.Context, func(f csvlinter.Finding) error {
+		return enc.Encode(f)
+	})
+	if err != nil {
+		return exitError(c, "json", csvlinter.CodeOf(err), err.Error())
+	}
+	if !results.Valid {
+		return cli.Exit("", exitInvalid)
+	}
+	return nil
+}
+
+// followReader reads a file like tail -f: at the end of the file it waits for more data
+// instead of returning io.EOF, until ctx is done.
+type followReader struct {
+	ctx      context.Context
+	r        io.Reader
+	interval time.Duration
+}
+
+func (f *followReader) Read(p []byte) (int, error) {
+	for {
+		n, err := f.r.Read(p)
+		if n > 0 {
+			return n, nil
+		}
+		if err != io.EOF {
+			return 0, err
+		}
+		select {
+		case <-f.ctx.Done():
+			return 0, io.EOF
+		case <-time.After(f.interval):
+		}
+	}
+}
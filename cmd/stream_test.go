@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/csvlinter/csvlinter/pkg/csvlinter"
+)
+
+// withStdin makes os.Stdin read content for the rest of the test.
+func withStdin(t *testing.T, content string) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := os.Stdin
+	os.Stdin = r
+	t.Cleanup(func() {
+		os.Stdin = old
+		r.Close()
+	})
+	go func() {
+		defer w.Close()
+		fmt.Fprint(w, content)
+	}()
+}
+
+// ndjsonFindings decodes one finding per output line.
+func ndjsonFindings(t *testing.T, out string) []csvlinter.Finding {
+	t.Helper()
+	var findings []csvlinter.Finding
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		if line == "" {
+			continue
+		}
+		var f csvlinter.Finding
+		if err := json.Unmarshal([]byte(line), &f); err != nil {
+			t.Fatalf("line %q is not a JSON finding: %v", line, err)
+		}
+		findings = append(findings, f)
+	}
+	return findings
+}
+
+func TestStreamCommand_Stdin(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"schema.json": `{"type":"object","properties":{"id":{"type":"integer"}}}`,
+	})
+	withStdin(t, "id,name\n1,a\nx,b\n3\n4,d\n")
+
+	out, _, code := runApp(t, "stream", "--schema", filepath.Join(dir, "schema.json"))
+	if code != 1 {
+		t.Fatalf("exit code = %d, want 1; output:\n%s", code, out)
+	}
+	findings := ndjsonFindings(t, out)
+	if len(findings) != 2 {
+		t.Fatalf("got %d findings, want 2:\n%s", len(findings), out)
+	}
+	if f := findings[0]; f.Severity != csvlinter.SeverityError || f.Line != 3 || f.Type != "schema" {
+		t.Errorf("first finding = %+v, want schema error on line 3", f)
+	}
+	if f := findings[1]; f.Line != 4 || f.Type != "structure" {
+		t.Errorf("second finding = %+v, want structure error on line 4", f)
+	}
+}
+
+func TestStreamCommand_HeaderFlag(t *testing.T) {
+	withStdin(t, "1;a\n2;b\n")
+
+	out, _, code := runApp(t, "stream", "--delimiter", ";", "--header", "id;name")
+	if code != 0 || out != "" {
+		t.Fatalf("exit code = %d, output %q; want 0 and no findings", code, out)
+	}
+}
+
+func TestStreamCommand_FollowNeedsFile(t *testing.T) {
+	out, _, code := runApp(t, "stream", "--follow")
+	if code != 2 || !strings.Contains(out, string(csvlinter.CodeInvalidArgument)) {
+		t.Errorf("exit code = %d, output %q; want INVALID_ARGUMENT", code, out)
+	}
+}
+
+func TestFollowReader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feed.csv")
+	if err := os.WriteFile(path, []byte("id\n1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &followReader{ctx: ctx, r: f, interval: time.Millisecond}
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		w, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+		if err == nil {
+			fmt.Fprint(w, "2\n")
+			w.Close()
+		}
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "id\n1\n2\n" {
+		t.Errorf("read %q, want the appended row too", data)
+	}
+}
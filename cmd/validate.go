@@ -1,48 +1,202 @@
 package cmd
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
-	"github.com/csvlinter/csvlinter/pkg/csvlinter"
+	"github.com/csvlinter/csvlinter/internal/cache"
+	"github.com/csvlinter/csvlinter/internal/config"
+	"github.com/csvlinter/csvlinter/internal/discover"
+	"github.com/csvlinter/csvlinter/internal/gitutil"
+	"github.com/csvlinter/csvlinter/internal/metrics"
+	"github.com/csvlinter/csvlinter/internal/notify"
+	"github.com/csvlinter/csvlinter/internal/patch"
+	"github.com/csvlinter/csvlinter/internal/redact"
+	"github.com/csvlinter/csvlinter/internal/reporter"
 	"github.com/csvlinter/csvlinter/internal/schema"
+	"github.com/csvlinter/csvlinter/internal/validator"
+	"github.com/csvlinter/csvlinter/pkg/csvlinter"
 
 	"github.com/urfave/cli/v2"
 )
 
 var validateCommand = &cli.Command{
 	Name:      "validate",
-	Usage:     "Validate a CSV file or STDIN against structure and optional schema",
-	ArgsUsage: "<csv-file or - for STDIN>",
+	Usage:     "Validate CSV files, directories or STDIN against structure and optional schema",
+	ArgsUsage: "<csv-file|directory>... or - for STDIN",
 	Flags: []cli.Flag{
-		&cli.StringFlag{
+		&cli.StringSliceFlag{
 			Name:    "schema",
 			Aliases: []string{"s"},
-			Usage:   "Path to JSON Schema file. If not set, will look for <csv>.schema.json or csvlinter.schema.json in the same or parent directories (see docs)",
+			Usage:   "Path to JSON Schema file; repeat to validate against several schemas (errors then name their schema). If not set, will look for <csv>.schema.json or csvlinter.schema.json in the same or parent directories (see docs)",
+		},
+		&cli.StringFlag{
+			Name:  "schema-root",
+			Usage: "Directory that schemas' $refs must stay in; relative $refs resolve against the referencing schema file",
 		},
 		&cli.StringFlag{
 			Name:    "output",
 			Aliases: []string{"o"},
-			Usage:   "Output file for structured validation results",
+			Usage:   "Output file for structured validation results (written in the first --format), or a template naming one per file, e.g. 'reports/{{.Name}}.json'",
 		},
-		&cli.StringFlag{
+		&cli.StringSliceFlag{
 			Name:    "format",
 			Aliases: []string{"f"},
-			Value:   "pretty",
-			Usage:   "Output format (pretty, json)",
+			Value:   cli.NewStringSlice("pretty"),
+			Usage:   "Output format (pretty, json, compact, sarif); repeat to render several, the first goes to --output and the rest to stdout",
+		},
+		&cli.BoolFlag{
+			Name:  "tee",
+			Usage: "When --output or --output-dir is set, also print the report to stdout",
+		},
+		&cli.StringFlag{
+			Name:  "output-dir",
+			Usage: "Write the JSON report to this directory as index.json plus parts of --chunk-size findings, instead of one document",
+		},
+		&cli.IntFlag{
+			Name:  "chunk-size",
+			Value: csvlinter.DefaultChunkSize,
+			Usage: "Findings per part file with --output-dir",
+		},
+		&cli.IntFlag{
+			Name:    "jobs",
+			Aliases: []string{"j"},
+			Value:   1,
+			Usage:   "Files validated at once when validating several; the report lists them by name all the same",
+		},
+		&cli.BoolFlag{
+			Name:  "stream-results",
+			Usage: "When validating several files, print each file's report as soon as it is validated, then the summary; json is printed as JSON Lines, and sarif cannot be streamed",
+		},
+		&cli.StringFlag{
+			Name:  "write-patch",
+			Usage: "Write the fixes of findings whose correction is known (whitespace, case, quoting, field counts) to this file as a unified diff against the CSV files",
+		},
+		&cli.StringFlag{
+			Name:  "theme",
+			Usage: "Colors and symbols of pretty and compact output: classic, minimal or emoji-free (ASCII only); overrides the config's theme name",
+		},
+		&cli.StringFlag{
+			Name:    "lang",
+			Usage:   "Language of pretty output and rule descriptions: en (the default), de, fr or ja; json and compact output stay in English",
+			EnvVars: []string{"CSVLINTER_LANG"},
 		},
 		&cli.StringFlag{
 			Name:    "delimiter",
 			Aliases: []string{"d"},
 			Value:   ",",
-			Usage:   "Delimiter character (defaults to comma)",
+			Usage:   "Delimiter character, or whitespace for columns separated by runs of spaces and tabs (defaults to tab for .tsv/.tab, pipe for .psv, comma otherwise)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "include",
+			Usage: "Glob for files to validate when a directory is given (default: *.csv, *.tsv, *.tab, *.psv); repeatable",
+		},
+		&cli.StringSliceFlag{
+			Name:  "exclude",
+			Usage: "Glob for files or directories to skip when a directory is given; repeatable",
+		},
+		&cli.StringFlag{
+			Name:  "ignore-file",
+			Usage: "gitignore-style file listing paths to skip when a directory is given, besides any .csvlinterignore files",
+		},
+		&cli.BoolFlag{
+			Name:  "staged",
+			Usage: "Validate the CSV files staged in git (git diff --cached) instead of paths; used by the pre-commit hook",
+		},
+		&cli.StringFlag{
+			Name:  "changed-since",
+			Usage: "Validate the CSV files changed in git since this ref (e.g. origin/main) instead of paths",
+		},
+		&cli.StringSliceFlag{
+			Name:  "sorted-by",
+			Usage: "Require COLUMN to be sorted; COLUMN[:asc|:desc][:unique], e.g. timestamp or id:desc:unique; repeatable (adds to rules.sorted in the config)",
+		},
+		&cli.StringFlag{
+			Name:  "target",
+			Usage: "Check that the file will load into a database: postgres, bigquery or snowflake (overrides target in the config)",
+		},
+		&cli.StringFlag{
+			Name:  "producer-schema",
+			Usage: "With --consumer-schema: validate against both sides of a data contract and report which side rejects the file",
+		},
+		&cli.StringFlag{
+			Name:  "consumer-schema",
+			Usage: "Schema of the consuming side of a data contract (see --producer-schema)",
+		},
+		&cli.StringFlag{
+			Name:  "file-schema",
+			Usage: "Also validate each whole file, as a JSON array of row objects, against this schema (minItems, uniqueItems, contains, ...)",
+		},
+		&cli.StringFlag{
+			Name:  "manifest",
+			Usage: "JSON manifest listing the batch's files with row counts and SHA-256 hashes; listed files are validated and checked against it",
+		},
+		&cli.StringFlag{
+			Name:  "profile",
+			Usage: "Baseline profile written by 'csvlinter profile'; columns whose null rate, mean or distinct count drift from it are reported as warnings",
+		},
+		&cli.BoolFlag{
+			Name:  "fingerprint",
+			Usage: "Include the file's SHA-256 and a schema-aware content fingerprint in the results",
+		},
+		&cli.DurationFlag{
+			Name:  "time-budget",
+			Usage: "Validate rows for at most this long (e.g. 30s), then report partial coverage",
+		},
+		&cli.IntFlag{
+			Name:  "samples",
+			Usage: "Include up to N example rows per failing rule in the JSON report",
+		},
+		&cli.BoolFlag{
+			Name:  "breakdown",
+			Usage: "Summarize errors per column and per rule in the report",
+		},
+		&cli.BoolFlag{
+			Name:  "dedupe-errors",
+			Usage: "Report findings with the same rule, field and message once, with their count and first and last lines",
+		},
+		&cli.BoolFlag{
+			Name:  "timings",
+			Usage: "Print the time spent reading, checking UTF-8, parsing, validating and reporting to stderr, and include it in the JSON report",
+		},
+		&cli.IntFlag{
+			Name:  "max-encoding-errors",
+			Usage: "Report up to N rows with invalid UTF-8, skipping them, before stopping (default: stop at the first)",
+		},
+		&cli.Int64Flag{
+			Name:  "seed",
+			Usage: "Pick --samples rows at random among all failing rows with this seed, instead of the first ones",
+		},
+		&cli.StringSliceFlag{
+			Name:  "sample-column",
+			Usage: "Only keep this column in sample rows; repeatable (all columns when not set)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "include-columns",
+			Usage: "Add the values of these columns in the finding's row, such as an ID, to each finding about a row; comma-separated or repeatable, * for the whole row",
+		},
+		&cli.IntFlag{
+			Name:  "context",
+			Usage: "In pretty output, show N rows before and after each failing row, below the header",
 		},
 		&cli.BoolFlag{
 			Name:    "fail-fast",
 			Aliases: []string{"ff"},
 			Usage:   "Stop after first error",
 		},
+		&cli.BoolFlag{
+			Name:    "quiet",
+			Aliases: []string{"q"},
+			Usage:   "Do not print the one-line summary to stderr",
+		},
 		&cli.Int64Flag{
 			Name:   "max-size",
 			Value:  10 * 1024 * 1024, // 10MB default
@@ -50,8 +204,13 @@ var validateCommand = &cli.Command{
 			Hidden: true,
 		},
 		&cli.StringFlag{
-			Name:  "filename",
-			Usage: "Logical filename to use for schema resolution and reporting when reading from STDIN",
+			Name:    "stdin-name",
+			Aliases: []string{"filename"},
+			Usage:   "Path to report STDIN as, and to resolve its schema, config and delimiter from, as if the piped content came from that file",
+		},
+		&cli.BoolFlag{
+			Name:  "stdin-schema-from-cwd",
+			Usage: "For STDIN without --stdin-name, look for csvlinter.schema.json in the working directory and its parents up to the project root",
 		},
 		&cli.BoolFlag{
 			Name:  "infer-schema",
@@ -61,86 +220,909 @@ var validateCommand = &cli.Command{
 			Name:  "infer-schema-output",
 			Usage: "When using --infer-schema, write the inferred schema to this path",
 		},
+		&cli.StringFlag{
+			Name:  "like",
+			Usage: "Known-good CSV file to validate against without a schema: files must have its header, in order, its delimiter and values of the rough types of its columns",
+		},
+		&cli.StringFlag{
+			Name:  "empty-values",
+			Usage: "How empty cells reach the schema: string (\"\", the default), missing (left out, so only required fails) or null",
+		},
+		&cli.StringFlag{
+			Name:  "types",
+			Usage: "Convert column values to these types before schema validation, instead of the schema's types, e.g. \"age:int,price:float,active:bool\" (int, float, bool or string); adds to the config's types",
+		},
+		&cli.BoolFlag{
+			Name:  "quoted-empty",
+			Usage: "Keep quoted empty cells (\"\") as empty strings, so --empty-values only applies to cells with nothing between the delimiters",
+		},
+		&cli.StringFlag{
+			Name:  "short-rows",
+			Usage: "Rows with fewer fields than the header: error (the default), warn (pad and report a warning) or pad with empty values",
+		},
+		&cli.StringFlag{
+			Name:  "long-rows",
+			Usage: "Rows with more fields than the header: error (the default), warn (ignore the extra values and report a warning) or ignore",
+		},
+		&cli.BoolFlag{
+			Name:  "require-data",
+			Usage: "Fail files with a header but no data rows, which are otherwise reported as a warning",
+		},
+		&cli.BoolFlag{
+			Name:  "chunked",
+			Usage: "Files concatenate chunks that each start with the header: skip the repeated headers, and report reordered ones and empty chunks",
+		},
+		&cli.StringFlag{
+			Name:  "quote",
+			Usage: "Quote character of fields, e.g. \"'\" for single quotes, or none for files that never quote (default: \")",
+		},
+		&cli.StringFlag{
+			Name:  "escape",
+			Usage: "Character that escapes delimiters, quotes and line breaks in fields, e.g. '\\' for producers that write a\\,b instead of quoting",
+		},
+		&cli.StringFlag{
+			Name:  "quoting",
+			Usage: "Warn about fields quoted against a policy: minimal, all, nonnumeric or consistent (each column always or never quoted)",
+		},
+		&cli.StringFlag{
+			Name:  "sep-line",
+			Usage: "Excel \"sep=;\" first lines, which set the delimiter unless --delimiter is given: allow (the default), warn or error",
+		},
+		&cli.BoolFlag{
+			Name:  "redact-values",
+			Usage: "Hide cell values in reports (all formats), keeping a short prefix and the length",
+		},
+		&cli.StringFlag{
+			Name:  "redact-mode",
+			Usage: "How to redact values: mask (prefix and length) or hash (short SHA-256 and length); implies --redact-values",
+		},
+		&cli.StringSliceFlag{
+			Name:  "redact-column",
+			Usage: "Only redact values of this column; repeatable; implies --redact-values",
+		},
+		&cli.IntFlag{
+			Name:  "max-value-length",
+			Value: csvlinter.DefaultMaxValueLength,
+			Usage: "Truncate values in reports to this many characters (0 = no limit)",
+		},
+		&cli.BoolFlag{
+			Name:  "no-cache",
+			Usage: "Always validate, without reading or writing the result cache",
+		},
+		&cli.StringFlag{
+			Name:  "config",
+			Usage: "Path to a config file. If not set, the .csvlinter.yml files from the CSV file's directory up to the project root are merged, deeper ones winning",
+		},
+		&cli.StringFlag{
+			Name:    "notify-webhook",
+			Usage:   "POST a JSON summary of the results to this URL (Slack, Teams or generic webhook) after validation",
+			EnvVars: []string{"CSVLINTER_NOTIFY_WEBHOOK"},
+		},
+		&cli.StringFlag{
+			Name:  "metrics-out",
+			Usage: "Write Prometheus metrics (rows, errors per rule, duration) to this textfile",
+		},
+		&cli.StringFlag{
+			Name:    "metrics-pushgateway",
+			Usage:   "Push Prometheus metrics to this Pushgateway URL",
+			EnvVars: []string{"CSVLINTER_METRICS_PUSHGATEWAY"},
+		},
 	},
-	Action: validateAction,
+	Action:       validateAction,
+	OnUsageError: validateUsageError,
+}
+
+// errorDocument is written to stdout for operational failures when JSON output is selected.
+type errorDocument struct {
+	Error errorBody `json:"error"`
+}
+
+type errorBody struct {
+	Code    csvlinter.ErrorCode `json:"code"`
+	Message string              `json:"message"`
 }
 
-func exitError(c *cli.Context, format, msg string) error {
+// exitError reports an operational failure. With JSON output selected it writes a
+// structured error document to stdout so wrappers can branch on the code instead of
+// parsing the message; otherwise the message goes to stderr.
+func exitError(c *cli.Context, format string, code csvlinter.ErrorCode, msg string) error {
 	if format == "json" {
-		fmt.Fprintf(c.App.Writer, `{"errors":[{"line_number":1,"message":%q}]}`+"\n", msg)
-		return cli.Exit("", 1)
+		b, _ := json.Marshal(errorDocument{Error: errorBody{Code: code, Message: msg}})
+		fmt.Fprintln(c.App.Writer, string(b))
+		return cli.Exit("", exitCode(code))
+	}
+	return cli.Exit(msg, exitCode(code))
+}
+
+// validateUsageError handles flag parsing failures. These happen before any flag value
+// is available, so the requested format is recovered from the raw arguments.
+func validateUsageError(c *cli.Context, err error, _ bool) error {
+	var args []string
+	if lineage := c.Lineage(); len(lineage) > 1 {
+		args = lineage[1].Args().Slice()
+	}
+	if firstFormatArg(args) == "json" {
+		return exitError(c, "json", csvlinter.CodeInvalidArgument, err.Error())
+	}
+	return exitError(c, "pretty", csvlinter.CodeInvalidArgument, "Error: "+err.Error())
+}
+
+// firstFormatArg returns the value of the first --format/-f argument, if any.
+func firstFormatArg(args []string) string {
+	for i, arg := range args {
+		for _, name := range []string{"--format", "-format", "-f", "--f"} {
+			if arg == name && i+1 < len(args) {
+				return args[i+1]
+			}
+			if strings.HasPrefix(arg, name+"=") {
+				return strings.TrimPrefix(arg, name+"=")
+			}
+		}
+	}
+	return ""
+}
+
+// loadConfig loads the file given by --config, or those discovered from the working directory.
+func loadConfig(c *cli.Context) (*config.Config, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	return config.LoadFor(c.String("config"), wd)
+}
+
+// loadConfigFor loads the file given by --config, or those discovered from the directory
+// of the CSV file at path; STDIN uses the working directory's.
+func loadConfigFor(c *cli.Context, path string) (*config.Config, error) {
+	if c.IsSet("config") || path == "" || path == "-" {
+		return loadConfig(c)
 	}
-	return cli.Exit(msg, 1)
+	return config.LoadFor("", filepath.Dir(path))
+}
+
+// schemaResolver returns the resolver of schemas for files without --schema, set up by
+// the config's schema_lookup, root_markers, schema_symlinks and schema_ignore_case.
+func schemaResolver(cfg *config.Config) schema.Resolver {
+	return schema.Resolver{
+		Patterns:    cfg.SchemaLookup,
+		RootMarkers: cfg.RootMarkers,
+		Symlinks:    cfg.Symlinks,
+		IgnoreCase:  cfg.IgnoreCase,
+	}
+}
+
+// fileOptions returns the options of each file of a multi-file run, built from the
+// config files of its directory when they differ from the working directory's.
+func fileOptions(c *cli.Context, base *config.Config, formats []string) csvlinter.FileOptionsFunc {
+	if c.IsSet("config") {
+		return nil
+	}
+	byDir := make(map[string]csvlinter.Options)
+	return func(path string, opts csvlinter.Options) (csvlinter.Options, error) {
+		dir := filepath.Dir(path)
+		if o, ok := byDir[dir]; ok {
+			return o, nil
+		}
+		cfg, err := config.LoadFor("", dir)
+		if err != nil {
+			return opts, &csvlinter.OpError{Code: csvlinter.CodeConfigInvalid, Message: fmt.Sprintf("Error: %v", err), Err: err}
+		}
+		if !slices.Equal(cfg.Files, base.Files) {
+			o, err := lintOptions(c, cfg, formats)
+			if err != nil {
+				return opts, &csvlinter.OpError{Code: csvlinter.CodeInvalidArgument, Message: fmt.Sprintf("Error: %s: %v", cfg.Path, err), Err: err}
+			}
+			// Fields set for the whole run rather than from config are kept
+			o.FileOptions = opts.FileOptions
+			o.Open = opts.Open
+			o.Interrupt = opts.Interrupt
+			opts = o
+		}
+		byDir[dir] = opts
+		return opts, nil
+	}
+}
+
+// notifyWebhook posts a results summary when a webhook is configured. Delivery failures
+// are reported on stderr but never change the validation outcome.
+func notifyWebhook(c *cli.Context, cfg *config.Config, results ...*validator.Results) {
+	url := cfg.Notify.Webhook
+	if c.IsSet("notify-webhook") {
+		url = c.String("notify-webhook")
+	}
+	if url == "" || len(results) == 0 {
+		return
+	}
+	payload := notify.NewPayload(notify.DefaultTopErrors, results...)
+	if err := notify.Send(c.Context, url, payload); err != nil {
+		fmt.Fprintf(c.App.ErrWriter, "Warning: webhook notification failed: %v\n", err)
+	}
+}
+
+// exportMetrics writes and/or pushes Prometheus metrics when configured. Like
+// notifications, export failures are warnings only.
+func exportMetrics(c *cli.Context, cfg *config.Config, results ...*validator.Results) {
+	if len(results) == 0 {
+		return
+	}
+	out := cfg.Metrics.Out
+	if c.IsSet("metrics-out") {
+		out = c.String("metrics-out")
+	}
+	gateway := cfg.Metrics.Pushgateway
+	if c.IsSet("metrics-pushgateway") {
+		gateway = c.String("metrics-pushgateway")
+	}
+	now := time.Now()
+	if out != "" {
+		if err := metrics.WriteFile(out, now, results...); err != nil {
+			fmt.Fprintf(c.App.ErrWriter, "Warning: %v\n", err)
+		}
+	}
+	if gateway != "" {
+		if err := metrics.Push(c.Context, gateway, now, results...); err != nil {
+			fmt.Fprintf(c.App.ErrWriter, "Warning: metrics push failed: %v\n", err)
+		}
+	}
+}
+
+// writePatch writes the fixes of the findings of results to the --write-patch file as a
+// unified diff, empty when there are none, and says how many it wrote on stderr.
+func writePatch(c *cli.Context, results ...*validator.Results) error {
+	path := c.String("write-patch")
+	if path == "" {
+		return nil
+	}
+	var out bytes.Buffer
+	fixed, files := 0, 0
+	for _, r := range results {
+		var fixes []validator.Fix
+		for _, list := range [][]validator.Finding{r.Errors, r.Warnings} {
+			for _, f := range list {
+				if f.Fix != nil {
+					fixes = append(fixes, *f.Fix)
+				}
+			}
+		}
+		if len(fixes) == 0 {
+			continue
+		}
+		data, err := os.ReadFile(r.File)
+		if err != nil {
+			return fmt.Errorf("cannot read %s for the patch: %w", r.File, err)
+		}
+		n, err := patch.Write(&out, r.File, data, fixes)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			fixed += n
+			files++
+		}
+	}
+	if err := os.WriteFile(path, out.Bytes(), 0644); err != nil {
+		return fmt.Errorf("cannot write patch: %w", err)
+	}
+	if !c.Bool("quiet") {
+		fmt.Fprintf(c.App.ErrWriter, "Wrote %d fix(es) for %d file(s) to %s\n", fixed, files, path)
+	}
+	return nil
+}
+
+// printTimings writes the phase timings of a file, or the total of a run, to stderr.
+func printTimings(c *cli.Context, label string, t *validator.Timings) {
+	if !c.Bool("timings") {
+		return
+	}
+	if t == nil {
+		fmt.Fprintf(c.App.ErrWriter, "Timings for %s: not measured\n", label)
+		return
+	}
+	ms := func(f float64) string { return strconv.FormatFloat(f, 'f', -1, 64) + "ms" }
+	fmt.Fprintf(c.App.ErrWriter, "Timings for %s: read %s, utf8 %s, parse %s, structure %s, schema %s, checks %s",
+		label, ms(t.Read), ms(t.UTF8), ms(t.Parse), ms(t.Structure), ms(t.Schema), ms(t.Checks))
+	if t.Report > 0 {
+		fmt.Fprintf(c.App.ErrWriter, ", report %s", ms(t.Report))
+	}
+	fmt.Fprintln(c.App.ErrWriter)
+}
+
+// printSummary writes a one-line outcome, such as "invalid: 2 errors, 1 warning in
+// 1.2s", to stderr, so people reading CI logs get it whatever the report format. A run
+// that stopped before the end is "partial", whatever it found so far.
+func printSummary(c *cli.Context, valid, partial bool, files, invalid, errors, warnings int, duration string) {
+	if c.Bool("quiet") {
+		return
+	}
+	outcome := "valid"
+	switch {
+	case partial:
+		outcome = "partial"
+	case !valid:
+		outcome = "invalid"
+	}
+	var parts []string
+	if files > 0 {
+		if invalid > 0 {
+			parts = append(parts, fmt.Sprintf("%d of %s", invalid, plural(files, "file")))
+		} else {
+			parts = append(parts, plural(files, "file"))
+		}
+	}
+	parts = append(parts, plural(errors, "error"), plural(warnings, "warning"))
+	line := outcome + ": " + strings.Join(parts, ", ")
+	if d, err := time.ParseDuration(duration); err == nil {
+		if d < time.Second {
+			d = d.Round(time.Millisecond)
+		} else {
+			d = d.Round(100 * time.Millisecond)
+		}
+		line += " in " + d.String()
+	}
+	fmt.Fprintln(c.App.ErrWriter, line)
+}
+
+// plural renders a count with its noun, e.g. "1 error" or "2 errors".
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// lintOptions builds the library options shared by single-file and multi-file runs.
+// The delimiter is left empty unless set explicitly, so it defaults per file extension.
+func lintOptions(c *cli.Context, cfg *config.Config, formats []string) (csvlinter.Options, error) {
+	opts := csvlinter.Options{
+		FailFast:             c.Bool("fail-fast"),
+		Format:               formats[0],
+		ExtraFormats:         formats[1:],
+		Output:               c.String("output"),
+		Tee:                  c.Bool("tee"),
+		OutputDir:            c.String("output-dir"),
+		ChunkSize:            c.Int("chunk-size"),
+		Jobs:                 c.Int("jobs"),
+		StreamResults:        c.Bool("stream-results"),
+		Theme:                theme(c, cfg),
+		MessageTemplates:     cfg.Messages,
+		SchemaPath:           primarySchema(c),
+		AdditionalSchemas:    additionalSchemas(c, cfg),
+		DiscriminatorColumn:  cfg.Discriminator.Column,
+		DiscriminatorSchemas: cfg.DiscriminatorSchemaPaths(),
+		GroupRules:           groupRules(cfg),
+		ExpressionRules:      expressionRules(cfg),
+		OutlierRules:         outlierRules(cfg),
+		GeoRules:             geoRules(cfg),
+		UnitRules:            unitRules(cfg),
+		ScriptRules:          scriptRules(cfg),
+		RequiredIfRules:      requiredIfRules(cfg),
+		UniqueRules:          uniqueRules(cfg),
+		CompletenessRules:    completenessRules(cfg),
+		Envelope: csvlinter.Envelope{
+			HeaderPrefix:   cfg.Envelope.Header,
+			TrailerPrefix:  cfg.Envelope.Trailer,
+			CountField:     cfg.Envelope.CountField,
+			ChecksumField:  cfg.Envelope.ChecksumField,
+			ChecksumColumn: cfg.Envelope.ChecksumColumn,
+		},
+		InferSchema:       c.Bool("infer-schema"),
+		InferSchemaOutput: c.String("infer-schema-output"),
+		Like:              c.String("like"),
+	}
+	if c.IsSet("delimiter") {
+		opts.Delimiter = c.String("delimiter")
+	}
+	if !c.Bool("no-cache") {
+		// Without a usable cache directory, validation simply runs uncached
+		opts.CacheDir, _ = cache.DefaultDir()
+	}
+	opts.RedactValues, opts.RedactColumns = redactOptions(c, cfg)
+	opts.Target = cfg.Target
+	opts.Transforms = cfg.Transforms
+	opts.Formats = cfg.Formats
+	opts.EmptyValues, opts.EmptyColumns = cfg.Empty.Default, cfg.Empty.Columns
+	if c.IsSet("empty-values") {
+		opts.EmptyValues = c.String("empty-values")
+	}
+	opts.QuotedEmpty = cfg.Empty.Quoted || c.Bool("quoted-empty")
+	opts.ShortRows, opts.LongRows = cfg.Ragged.Short, cfg.Ragged.Long
+	if c.IsSet("short-rows") {
+		opts.ShortRows = c.String("short-rows")
+	}
+	if c.IsSet("long-rows") {
+		opts.LongRows = c.String("long-rows")
+	}
+	opts.RequireData = cfg.RequireData || c.Bool("require-data")
+	opts.Chunked = cfg.Chunked || c.Bool("chunked")
+	opts.Lang = cfg.Lang
+	if c.IsSet("lang") {
+		opts.Lang = c.String("lang")
+	}
+	opts.Quote = cfg.Quote
+	if c.IsSet("quote") {
+		opts.Quote = c.String("quote")
+	}
+	opts.Escape = cfg.Escape
+	if c.IsSet("escape") {
+		opts.Escape = c.String("escape")
+	}
+	opts.Quoting = cfg.Quoting
+	if c.IsSet("quoting") {
+		opts.Quoting = c.String("quoting")
+	}
+	opts.SepLine = cfg.SepLine
+	if c.IsSet("sep-line") {
+		opts.SepLine = c.String("sep-line")
+	}
+	if c.IsSet("target") {
+		opts.Target = c.String("target")
+	}
+	opts.Manifest = c.String("manifest")
+	opts.SchemaRoot = c.String("schema-root")
+	opts.SchemaLookup = cfg.SchemaLookup
+	opts.RootMarkers = cfg.RootMarkers
+	opts.SchemaSymlinks = cfg.Symlinks
+	opts.SchemaIgnoreCase = cfg.IgnoreCase
+	opts.ProducerSchema = c.String("producer-schema")
+	opts.ConsumerSchema = c.String("consumer-schema")
+	opts.FileSchema = cfg.FileSchemaPath()
+	if c.IsSet("file-schema") {
+		opts.FileSchema = c.String("file-schema")
+	}
+	opts.Profile = cfg.ProfilePath()
+	if c.IsSet("profile") {
+		opts.Profile = c.String("profile")
+	}
+	opts.Drift = csvlinter.DriftThresholds{NullRate: cfg.Drift.NullRate, Mean: cfg.Drift.Mean, Distinct: cfg.Drift.Distinct}
+	opts.Fingerprint = c.Bool("fingerprint")
+	opts.SampleRows = c.Int("samples")
+	opts.TimeBudget = c.Duration("time-budget")
+	opts.SampleColumns = c.StringSlice("sample-column")
+	opts.IncludeColumns = c.StringSlice("include-columns")
+	opts.Seed = c.Int64("seed")
+	opts.Breakdown = c.Bool("breakdown")
+	opts.DedupeErrors = c.Bool("dedupe-errors")
+	if c.IsSet("write-patch") {
+		if opts.DedupeErrors {
+			return opts, fmt.Errorf("--write-patch cannot be combined with --dedupe-errors, which keeps the fix of the first finding of each group only")
+		}
+		if c.Args().First() == "-" {
+			return opts, fmt.Errorf("--write-patch needs files: STDIN cannot be patched")
+		}
+		opts.Fixes = true
+	}
+	opts.Timings = c.Bool("timings")
+	opts.MaxEncodingErrors = c.Int("max-encoding-errors")
+	opts.ContextRows = c.Int("context")
+	opts.MaxValueLength = c.Int("max-value-length")
+	var err error
+	if opts.SortRules, err = sortRules(c, cfg); err != nil {
+		return opts, err
+	}
+	if opts.ColumnTypes, err = columnTypes(c, cfg); err != nil {
+		return opts, err
+	}
+	return opts, nil
+}
+
+// columnTypes combines the config's types with --types, which wins for the columns in
+// both.
+func columnTypes(c *cli.Context, cfg *config.Config) (map[string]string, error) {
+	spec := c.String("types")
+	if spec == "" {
+		return cfg.Types, nil
+	}
+	out := make(map[string]string, len(cfg.Types))
+	for column, t := range cfg.Types {
+		out[column] = t
+	}
+	for _, item := range strings.Split(spec, ",") {
+		i := strings.LastIndex(item, ":")
+		if i <= 0 {
+			return nil, fmt.Errorf("--types %s: want column:type, got '%s'", spec, item)
+		}
+		out[strings.TrimSpace(item[:i])] = strings.TrimSpace(item[i+1:])
+	}
+	return out, nil
+}
+
+// primarySchema is the first --schema, if any.
+func primarySchema(c *cli.Context) string {
+	if schemas := c.StringSlice("schema"); len(schemas) > 0 {
+		return schemas[0]
+	}
+	return ""
+}
+
+// additionalSchemas are the --schema flags after the first, followed by the config's schemas.
+func additionalSchemas(c *cli.Context, cfg *config.Config) []string {
+	var extra []string
+	if schemas := c.StringSlice("schema"); len(schemas) > 1 {
+		extra = append(extra, schemas[1:]...)
+	}
+	return append(extra, cfg.SchemaPaths()...)
+}
+
+// groupRules converts the config's group rules to library options.
+func groupRules(cfg *config.Config) []csvlinter.GroupRule {
+	var out []csvlinter.GroupRule
+	for _, g := range cfg.Rules.Groups {
+		min, max := g.Bounds()
+		out = append(out, csvlinter.GroupRule{By: g.By, Where: g.Where, Min: min, Max: max})
+	}
+	return out
+}
+
+// expressionRules converts the config's expression rules to library options.
+func expressionRules(cfg *config.Config) []csvlinter.ExpressionRule {
+	var out []csvlinter.ExpressionRule
+	for _, e := range cfg.Rules.Expressions {
+		out = append(out, csvlinter.ExpressionRule{Assert: e.Assert, Tolerance: e.Tolerance})
+	}
+	return out
+}
+
+// outlierRules converts the config's outlier rules to library options.
+func outlierRules(cfg *config.Config) []csvlinter.OutlierRule {
+	var out []csvlinter.OutlierRule
+	for _, o := range cfg.Rules.Outliers {
+		out = append(out, csvlinter.OutlierRule{Method: o.Method, Threshold: o.Threshold, Columns: o.Columns})
+	}
+	return out
+}
+
+// geoRules converts the config's geo rules to library options.
+func geoRules(cfg *config.Config) []csvlinter.GeoRule {
+	var out []csvlinter.GeoRule
+	for _, g := range cfg.Rules.Geo {
+		out = append(out, csvlinter.GeoRule{Latitude: g.Latitude, Longitude: g.Longitude, WKT: g.WKT, GeoJSON: g.GeoJSON})
+	}
+	return out
+}
+
+// unitRules converts the config's unit rules to library options.
+func unitRules(cfg *config.Config) []csvlinter.UnitRule {
+	var out []csvlinter.UnitRule
+	for _, u := range cfg.Rules.Units {
+		out = append(out, csvlinter.UnitRule{Column: u.Column, Units: u.Units, Minimum: u.Minimum, Maximum: u.Maximum})
+	}
+	return out
+}
+
+// scriptRules converts the config's script rules to library options.
+func scriptRules(cfg *config.Config) []csvlinter.ScriptRule {
+	var out []csvlinter.ScriptRule
+	for _, s := range cfg.Rules.Scripts {
+		out = append(out, csvlinter.ScriptRule{Columns: s.Columns, Scripts: s.Scripts, NoEmoji: s.NoEmoji})
+	}
+	return out
+}
+
+// requiredIfRules converts the config's required_if rules to library options.
+func requiredIfRules(cfg *config.Config) []csvlinter.RequiredIfRule {
+	var out []csvlinter.RequiredIfRule
+	for _, r := range cfg.Rules.RequiredIf {
+		out = append(out, csvlinter.RequiredIfRule{When: r.When, Require: r.Require})
+	}
+	return out
+}
+
+// completenessRules converts the config's completeness rules to library options.
+func completenessRules(cfg *config.Config) []csvlinter.CompletenessRule {
+	var out []csvlinter.CompletenessRule
+	for _, r := range cfg.Rules.Completeness {
+		out = append(out, csvlinter.CompletenessRule{Column: r.Column, MinPercent: r.MinPercent})
+	}
+	return out
+}
+
+// theme converts the config's theme to library options, with --theme picking the base.
+func theme(c *cli.Context, cfg *config.Config) csvlinter.Theme {
+	t := cfg.Theme
+	if c.IsSet("theme") {
+		t.Name = c.String("theme")
+	}
+	return csvlinter.Theme{
+		Name: t.Name, Error: t.Error, Warning: t.Warning, Success: t.Success, Heading: t.Heading,
+		Pass: t.Pass, Fail: t.Fail, Bar: t.Bar,
+	}
+}
+
+// uniqueRules converts the config's unique rules to library options.
+func uniqueRules(cfg *config.Config) []csvlinter.UniqueRule {
+	var out []csvlinter.UniqueRule
+	for _, r := range cfg.Rules.Unique {
+		out = append(out, csvlinter.UniqueRule{Columns: r.Columns, Method: r.Method, MemoryKeys: r.MemoryKeys, ExpectedKeys: r.ExpectedKeys})
+	}
+	return out
+}
+
+// sortRules combines the config's sorted rules with the --sorted-by flags.
+func sortRules(c *cli.Context, cfg *config.Config) ([]csvlinter.SortRule, error) {
+	var out []csvlinter.SortRule
+	for _, r := range cfg.Rules.Sorted {
+		rule, err := sortRule(r.Column, r.Order, r.Unique)
+		if err != nil {
+			return nil, fmt.Errorf("config rules.sorted: %w", err)
+		}
+		out = append(out, rule)
+	}
+	for _, spec := range c.StringSlice("sorted-by") {
+		parts := strings.Split(spec, ":")
+		var order string
+		var unique bool
+		for _, p := range parts[1:] {
+			switch p {
+			case "asc", "desc":
+				order = p
+			case "unique":
+				unique = true
+			default:
+				return nil, fmt.Errorf("--sorted-by %s: unknown modifier '%s' (use asc, desc or unique)", spec, p)
+			}
+		}
+		rule, err := sortRule(parts[0], order, unique)
+		if err != nil {
+			return nil, fmt.Errorf("--sorted-by %s: %w", spec, err)
+		}
+		out = append(out, rule)
+	}
+	return out, nil
+}
+
+func sortRule(column, order string, unique bool) (csvlinter.SortRule, error) {
+	if column == "" {
+		return csvlinter.SortRule{}, fmt.Errorf("column is required")
+	}
+	switch order {
+	case "", "asc", "desc":
+	default:
+		return csvlinter.SortRule{}, fmt.Errorf("order must be asc or desc, got '%s'", order)
+	}
+	return csvlinter.SortRule{Column: column, Descending: order == "desc", Unique: unique}, nil
+}
+
+// redactOptions combines the redact flags with the config; flags win field by field.
+func redactOptions(c *cli.Context, cfg *config.Config) (string, []string) {
+	enabled := cfg.Redact.Enabled() || c.Bool("redact-values") || c.IsSet("redact-mode") || c.IsSet("redact-column")
+	if !enabled {
+		return "", nil
+	}
+	mode, columns := cfg.Redact.Mode, cfg.Redact.Columns
+	if c.IsSet("redact-mode") {
+		mode = c.String("redact-mode")
+	}
+	if c.IsSet("redact-column") {
+		columns = c.StringSlice("redact-column")
+	}
+	if mode == "" {
+		mode = redact.ModeMask
+	}
+	return mode, columns
+}
+
+// exitStatus maps the validation outcome to the command's exit. A run that stopped before
+// the end, interrupted or out of time budget, exits as cancelled, whatever its partial
+// report says.
+func exitStatus(format string, valid, partial bool) error {
+	code, msg := exitInvalid, "validation failed"
+	switch {
+	case partial:
+		code, msg = exitCancelled, "validation stopped before the end; the report is partial"
+	case valid:
+		return nil
+	}
+	if format == "json" {
+		return cli.Exit("", code)
+	}
+	return cli.Exit(msg, code)
+}
+
+// isMultiFile reports whether the arguments call for a multi-file run: several paths, a
+// single directory, or a manifest.
+func isMultiFile(c *cli.Context) bool {
+	if gitMode(c) || c.IsSet("manifest") || c.NArg() > 1 {
+		return true
+	}
+	info, err := os.Stat(c.Args().First())
+	return err == nil && info.IsDir()
+}
+
+// collectFiles expands directory arguments into the files they contain. Files named
+// explicitly are always validated, whatever the include and exclude patterns say.
+func collectFiles(c *cli.Context, cfg *config.Config) ([]string, error) {
+	opts := discover.Options{
+		Include:     c.StringSlice("include"),
+		Exclude:     c.StringSlice("exclude"),
+		IgnoreFile:  c.String("ignore-file"),
+		RootMarkers: cfg.RootMarkers,
+	}
+	if gitMode(c) {
+		return gitFiles(c, opts)
+	}
+	var files []string
+	for _, arg := range c.Args().Slice() {
+		if arg == "-" {
+			return nil, fmt.Errorf("STDIN (-) cannot be combined with other paths")
+		}
+		info, err := os.Stat(arg)
+		if err != nil || !info.IsDir() {
+			files = append(files, arg)
+			continue
+		}
+		found, err := discover.Files(arg, opts)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, found...)
+	}
+	return files, nil
+}
+
+// gitMode reports whether git, rather than the arguments, selects the files to validate.
+func gitMode(c *cli.Context) bool {
+	return c.Bool("staged") || c.IsSet("changed-since")
+}
+
+// gitFiles lists the staged or changed files that match opts, relative to the working
+// directory.
+func gitFiles(c *cli.Context, opts discover.Options) ([]string, error) {
+	if c.Bool("staged") && c.IsSet("changed-since") {
+		return nil, fmt.Errorf("--staged and --changed-since cannot be combined")
+	}
+	if c.NArg() > 0 {
+		return nil, fmt.Errorf("--staged and --changed-since cannot be combined with paths")
+	}
+	root, err := gitutil.RelativeRoot(".")
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	if c.Bool("staged") {
+		files, err = gitutil.StagedFiles(".")
+	} else {
+		files, err = gitutil.ChangedFiles(".", c.String("changed-since"))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return discover.Filter(root, files, opts)
+}
+
+// validateFiles runs a multi-file validation and reports all files together.
+func validateFiles(c *cli.Context, cfg *config.Config, formats []string) error {
+	format := formats[0]
+	files, err := collectFiles(c, cfg)
+	if err != nil {
+		return exitError(c, format, csvlinter.CodeInvalidArgument, fmt.Sprintf("Error: %v", err))
+	}
+	opts, err := lintOptions(c, cfg, formats)
+	if err != nil {
+		return exitError(c, format, csvlinter.CodeInvalidArgument, fmt.Sprintf("Error: %v", err))
+	}
+	opts.FileOptions = fileOptions(c, cfg, formats)
+	if c.Bool("staged") {
+		// Validate what is about to be committed, not the working tree
+		opts.Open = func(path string) (io.ReadCloser, error) {
+			return gitutil.OpenStaged(".", path)
+		}
+	}
+	interrupt, stop := interruptOnSignal(c)
+	defer stop()
+	opts.Interrupt = interrupt
+	batch, err := csvlinter.LintFiles(files, opts, c.App.Writer)
+	if err != nil {
+		return exitError(c, format, csvlinter.CodeOf(err), err.Error())
+	}
+	for _, r := range batch.Files {
+		printTimings(c, r.File, r.Timings)
+	}
+	printTimings(c, "all files", batch.Timings)
+	if err := writePatch(c, batch.Files...); err != nil {
+		return exitError(c, format, csvlinter.CodeOutputFailed, fmt.Sprintf("Error: %v", err))
+	}
+	printSummary(c, batch.Valid, batch.Partial, batch.TotalFiles, batch.InvalidFiles, batch.TotalErrors, batch.TotalWarnings, batch.Duration)
+	notifyWebhook(c, cfg, batch.Files...)
+	exportMetrics(c, cfg, batch.Files...)
+	return exitStatus(format, batch.Valid, batch.Partial)
 }
 
 func validateAction(c *cli.Context) error {
-	if c.NArg() < 1 {
-		return exitError(c, c.String("format"), "Error: CSV file path or - for STDIN is required")
+	formats := c.StringSlice("format")
+	if c.IsSet("output-dir") && !c.IsSet("format") {
+		// Chunked reports are JSON; the pretty default does not apply
+		formats = []string{"json"}
+	}
+	format := formats[0]
+	if c.NArg() < 1 && !gitMode(c) && !c.IsSet("manifest") {
+		return exitError(c, format, csvlinter.CodeInvalidArgument, "Error: CSV file path or - for STDIN is required")
+	}
+	for _, f := range formats {
+		if !reporter.IsSupported(f) {
+			return exitError(c, format, csvlinter.CodeInvalidArgument, "Error: Format must be "+reporter.FormatList())
+		}
+	}
+
+	cfg, err := loadConfig(c)
+	if err != nil {
+		return exitError(c, format, csvlinter.CodeConfigInvalid, fmt.Sprintf("Error: %v", err))
+	}
+
+	if isMultiFile(c) {
+		return validateFiles(c, cfg, formats)
 	}
 
 	csvPath := c.Args().Get(0)
-	format := c.String("format")
-	delimiter := c.String("delimiter")
 	maxSize := c.Int64("max-size")
-	filename := c.String("filename")
+	filename := c.String("stdin-name")
 
 	var input io.Reader
-	var name string
+	var name string // Empty for unnamed STDIN, which the linter reports as STDIN and looks up no schema for
 
 	if csvPath == "-" {
 		input = io.LimitReader(os.Stdin, maxSize)
 		if filename != "" {
 			name = filename
-		} else {
-			name = "STDIN"
+			if cfg, err = loadConfigFor(c, filename); err != nil {
+				return exitError(c, format, csvlinter.CodeConfigInvalid, fmt.Sprintf("Error: %v", err))
+			}
 		}
 	} else {
 		file, err := os.Open(csvPath)
 		if err != nil {
-			return exitError(c, format, fmt.Sprintf("Error: Cannot open file '%s': %v", csvPath, err))
+			code := csvlinter.CodeFileUnreadable
+			if os.IsNotExist(err) {
+				code = csvlinter.CodeFileNotFound
+			}
+			return exitError(c, format, code, fmt.Sprintf("Error: Cannot open file '%s': %v", csvPath, err))
 		}
 		defer file.Close()
 		input = file
 		name = csvPath
+		if cfg, err = loadConfigFor(c, csvPath); err != nil {
+			return exitError(c, format, csvlinter.CodeConfigInvalid, fmt.Sprintf("Error: %v", err))
+		}
 	}
 
-	schemaPath := c.String("schema")
+	schemaPath := primarySchema(c)
 	if schemaPath == "" && !c.Bool("infer-schema") {
 		if csvPath == "-" && filename != "" {
-			schemaPath = schema.ResolveSchema(filename)
+			schemaPath = schemaResolver(cfg).Resolve(filename)
+		} else if csvPath == "-" && c.Bool("stdin-schema-from-cwd") {
+			wd, err := os.Getwd()
+			if err != nil {
+				return exitError(c, format, csvlinter.CodeInvalidArgument, fmt.Sprintf("Error: %v", err))
+			}
+			schemaPath = schemaResolver(cfg).ResolveDir(wd)
 		} else if csvPath != "-" {
-			schemaPath = schema.ResolveSchema(csvPath)
+			schemaPath = schemaResolver(cfg).Resolve(csvPath)
 		}
 	}
 	if schemaPath != "" {
 		if _, err := os.Stat(schemaPath); os.IsNotExist(err) {
-			return exitError(c, format, fmt.Sprintf("Error: Schema file '%s' does not exist", schemaPath))
+			return exitError(c, format, csvlinter.CodeSchemaNotFound, fmt.Sprintf("Error: Schema file '%s' does not exist", schemaPath))
 		}
 	}
 
-	if format != "pretty" && format != "json" {
-		return cli.Exit("Error: Format must be 'pretty' or 'json'", 1)
-	}
-
-	opts := csvlinter.Options{
-		Delimiter:          delimiter,
-		FailFast:           c.Bool("fail-fast"),
-		Format:             format,
-		Output:             c.String("output"),
-		Filename:           name,
-		SchemaPath:         schemaPath,
-		InferSchema:        c.Bool("infer-schema"),
-		InferSchemaOutput:  c.String("infer-schema-output"),
+	opts, err := lintOptions(c, cfg, formats)
+	if err != nil {
+		return exitError(c, format, csvlinter.CodeInvalidArgument, fmt.Sprintf("Error: %v", err))
 	}
+	opts.Filename = name
+	opts.SchemaPath = schemaPath
+	interrupt, stop := interruptOnSignal(c)
+	defer stop()
+	opts.Interrupt = interrupt
 	results, err := csvlinter.LintAdvanced(input, opts, c.App.Writer)
 	if err != nil {
-		return exitError(c, format, err.Error())
+		return exitError(c, format, csvlinter.CodeOf(err), err.Error())
 	}
-	if results != nil && !results.Valid {
-		if format == "json" {
-			return cli.Exit("", 1)
-		}
-		return cli.Exit("validation failed", 1)
+	printTimings(c, results.File, results.Timings)
+	if err := writePatch(c, results); err != nil {
+		return exitError(c, format, csvlinter.CodeOutputFailed, fmt.Sprintf("Error: %v", err))
 	}
-	return nil
+	printSummary(c, results.Valid, results.Partial, 0, 0, results.ErrorCount(), results.WarningCount(), results.Duration)
+	notifyWebhook(c, cfg, results)
+	exportMetrics(c, cfg, results)
+	return exitStatus(format, results.Valid, results.Partial)
 }
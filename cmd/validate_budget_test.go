@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+func TestValidateCommand_TimeBudget(t *testing.T) {
+	dir := t.TempDir()
+	content := "id\n" + strings.Repeat("1\n", 100)
+	writeTree(t, dir, map[string]string{"big.csv": content})
+
+	// A budget this small runs out before the first row; the partial run exits as cancelled
+	stdout, _, code := runApp(t, "validate", "--format", "json", "--no-cache", "--time-budget", "1ns", filepath.Join(dir, "big.csv"))
+	if code != exitCancelled {
+		t.Fatalf("want exit %d, got %d", exitCancelled, code)
+	}
+	var res validator.Results
+	if err := json.Unmarshal([]byte(stdout), &res); err != nil {
+		t.Fatalf("invalid json output: %v\nstdout=%s", err, stdout)
+	}
+	if !res.Partial || res.Coverage == nil || res.Coverage.TimeBudget != "1ns" || res.Coverage.TotalBytes != int64(len(content)) {
+		t.Errorf("want partial coverage of the whole file size, got %+v", res.Coverage)
+	}
+
+	stdout, stderr, _ := runApp(t, "validate", "--no-cache", "--time-budget", "1ns", filepath.Join(dir, "big.csv"))
+	if !strings.Contains(stdout, "Coverage: partial, stopped after the 1ns time budget") {
+		t.Errorf("want a coverage line in pretty output, got:\n%s", stdout)
+	}
+	if !strings.HasPrefix(stderr, "partial: 0 errors") {
+		t.Errorf("want the partial run explained on stderr, got:\n%s", stderr)
+	}
+
+	// A budget that does not run out leaves the exit code to the data
+	if _, _, code := runApp(t, "validate", "--no-cache", "--time-budget", "1m", filepath.Join(dir, "big.csv")); code != exitValid {
+		t.Errorf("want exit %d within the budget, got %d", exitValid, code)
+	}
+}
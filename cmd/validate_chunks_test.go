@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateCommand_OutputDir(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"bad.csv": "id,name\n1\n2\n3\n"})
+	out := filepath.Join(dir, "reports")
+
+	// --output-dir writes JSON without --format, and nothing to stdout
+	stdout, _, code := runApp(t, "validate", "--no-cache", "--output-dir", out, "--chunk-size", "2", filepath.Join(dir, "bad.csv"))
+	if code != 1 || stdout != "" {
+		t.Fatalf("want exit 1 and no stdout, got %d:\n%s", code, stdout)
+	}
+	data, err := os.ReadFile(filepath.Join(out, "index.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var index struct {
+		TotalErrors int `json:"total_errors"`
+		Parts       []struct {
+			Path   string `json:"path"`
+			Errors int    `json:"errors"`
+		} `json:"parts"`
+	}
+	if err := json.Unmarshal(data, &index); err != nil {
+		t.Fatalf("invalid index: %v\n%s", err, data)
+	}
+	if index.TotalErrors != 3 || len(index.Parts) != 2 || index.Parts[1].Errors != 1 {
+		t.Errorf("want 3 errors in two parts, got %s", data)
+	}
+	for _, p := range index.Parts {
+		if _, err := os.Stat(filepath.Join(out, p.Path)); err != nil {
+			t.Errorf("part listed in the index is missing: %v", err)
+		}
+	}
+
+	if _, _, code := runApp(t, "validate", "--format", "pretty", "--output-dir", out, filepath.Join(dir, "bad.csv")); code != 2 {
+		t.Errorf("want exit 2 for a pretty chunked report, got %d", code)
+	}
+	stdout, _, _ = runApp(t, "validate", "--format", "json", "--output", filepath.Join(dir, "r.json"), "--output-dir", out, filepath.Join(dir, "bad.csv"))
+	var doc errorDocument
+	if err := json.Unmarshal([]byte(stdout), &doc); err != nil || doc.Error.Code != "INVALID_ARGUMENT" {
+		t.Errorf("want an INVALID_ARGUMENT error document, got %s", stdout)
+	}
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateCommand_CompactFormat(t *testing.T) {
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "users.csv")
+	if err := os.WriteFile(csvPath, []byte("id,email\n1,a@example.com\n2,nope\n3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	schema := `{"type":"object","properties":{"id":{"type":"integer"},"email":{"type":"string","format":"email"}}}`
+	if err := os.WriteFile(filepath.Join(dir, "users.schema.json"), []byte(schema), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, _, code := runApp(t, "validate", "--format", "compact", csvPath)
+	if code != 1 {
+		t.Fatalf("want exit 1, got %d", code)
+	}
+	lines := strings.Split(strings.TrimSuffix(stdout, "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("want one line per finding, got:\n%s", stdout)
+	}
+	if !strings.HasPrefix(lines[0], csvPath+":3:2 error SCH001 email: ") {
+		t.Errorf("unexpected format finding: %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], csvPath+":4 error STR001 ") {
+		t.Errorf("unexpected structure finding: %q", lines[1])
+	}
+}
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateCommand_Context(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"users.csv": "id,email\n1,a@example.com\n2,b@example.com\n3\n4,d@example.com\n5,e@example.com\n"})
+
+	stdout, _, code := runApp(t, "validate", "--context", "1", "--redact-column", "email", filepath.Join(dir, "users.csv"))
+	if code != 1 {
+		t.Fatalf("want exit 1, got %d", code)
+	}
+	want := "         | id,email\n" +
+		"       3 | 2,b@*** (13 chars)\n" +
+		"     > 4 | 3\n" +
+		"       5 | 4,d@*** (13 chars)\n"
+	if !strings.Contains(stdout, want) {
+		t.Errorf("want the redacted rows around line 4, got:\n%s", stdout)
+	}
+	if strings.Contains(stdout, "example.com") {
+		t.Errorf("context rows leaked redacted values:\n%s", stdout)
+	}
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+func TestValidateCommand_DedupeErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"users.csv":         "id,age\nx,30\ny,31\n3,old\nz,33\n",
+		"users.schema.json": `{"type":"object","properties":{"id":{"type":"integer"},"age":{"type":"integer"}}}`,
+	})
+	csvPath := filepath.Join(dir, "users.csv")
+
+	stdout, _, code := runApp(t, "validate", "--format", "json", "--no-cache", "--dedupe-errors", csvPath)
+	if code != 1 {
+		t.Fatalf("want exit 1, got %d", code)
+	}
+	var res validator.Results
+	if err := json.Unmarshal([]byte(stdout), &res); err != nil {
+		t.Fatalf("invalid json output: %v\nstdout=%s", err, stdout)
+	}
+	if len(res.Errors) != 2 {
+		t.Fatalf("want 2 rolled up errors, got %+v", res.Errors)
+	}
+	if e := res.Errors[0]; e.Field != "id" || e.Line != 2 || e.LastLine != 5 || e.Occurrences != 3 || e.Value != "x" {
+		t.Errorf("want the id errors rolled up from line 2 to 5, got %+v", e)
+	}
+	if e := res.Errors[1]; e.Field != "age" || e.Occurrences != 0 {
+		t.Errorf("want the single age error as is, got %+v", e)
+	}
+
+	stdout, _, _ = runApp(t, "validate", "--no-cache", "--dedupe-errors", csvPath)
+	for _, want := range []string{"Errors (4, 2 distinct):", "1. Lines 2-5 (3 times) (id)", "Found 4 error(s)"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("want %q in pretty output, got:\n%s", want, stdout)
+		}
+	}
+}
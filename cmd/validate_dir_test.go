@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+}
+
+func batchFiles(t *testing.T, stdout string) (validator.Batch, []string) {
+	t.Helper()
+	var batch validator.Batch
+	if err := json.Unmarshal([]byte(stdout), &batch); err != nil {
+		t.Fatalf("invalid json output: %v\nstdout=%s", err, stdout)
+	}
+	var names []string
+	for _, f := range batch.Files {
+		names = append(names, filepath.ToSlash(f.File))
+	}
+	return batch, names
+}
+
+func TestValidateCommand_Directory(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"a.csv":                "id,name\n1,Alice\n",
+		"b.tsv":                "id\tname\n1\tBob\n",
+		"notes.txt":            "not csv",
+		"nested/c.csv":         "id,name\n1,Carl\n",
+		"nested/c.schema.json": `{"type":"object","properties":{"id":{"type":"integer"}}}`,
+		"tmp/scratch.csv":      "id,name\n1\n",
+		"vendor/d.csv":         "id,name\n1,Dora\n",
+	})
+	rel := func(name string) string { return filepath.ToSlash(filepath.Join(root, name)) }
+
+	t.Run("recurses with default includes", func(t *testing.T) {
+		stdout, stderr, code := runApp(t, "validate", "--format", "json", root)
+		if code != 1 {
+			t.Fatalf("want exit 1 for the ragged scratch file, got %d; stderr=%s", code, stderr)
+		}
+		batch, names := batchFiles(t, stdout)
+		want := []string{rel("a.csv"), rel("b.tsv"), rel("nested/c.csv"), rel("tmp/scratch.csv"), rel("vendor/d.csv")}
+		if len(names) != len(want) {
+			t.Fatalf("want files %v, got %v", want, names)
+		}
+		for i := range want {
+			if names[i] != want[i] {
+				t.Errorf("file %d: want %s, got %s", i, want[i], names[i])
+			}
+		}
+		if !batch.Files[1].Valid {
+			t.Errorf("expected .tsv to default to tab delimiter, got %v", batch.Files[1].Errors)
+		}
+		if !batch.Files[2].SchemaUsed {
+			t.Errorf("expected colocated schema to apply to nested/c.csv")
+		}
+		if batch.InvalidFiles != 1 {
+			t.Errorf("want 1 invalid file, got %d", batch.InvalidFiles)
+		}
+	})
+
+	t.Run("include and exclude", func(t *testing.T) {
+		stdout, _, code := runApp(t, "validate", "--format", "json", "--include", "*.csv", "--exclude", "tmp", "--exclude", "vendor/", root)
+		if code != 0 {
+			t.Fatalf("want exit 0, got %d", code)
+		}
+		_, names := batchFiles(t, stdout)
+		if len(names) != 2 || names[0] != rel("a.csv") || names[1] != rel("nested/c.csv") {
+			t.Errorf("unexpected files: %v", names)
+		}
+	})
+
+	t.Run("ignore file", func(t *testing.T) {
+		ignore := filepath.Join(t.TempDir(), "ignore")
+		if err := os.WriteFile(ignore, []byte("# scratch data\ntmp/\n/vendor\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		stdout, _, code := runApp(t, "validate", "--format", "json", "--ignore-file", ignore, root)
+		if code != 0 {
+			t.Fatalf("want exit 0, got %d", code)
+		}
+		_, names := batchFiles(t, stdout)
+		if len(names) != 3 {
+			t.Errorf("want 3 files, got %v", names)
+		}
+	})
+
+	t.Run("multiple paths", func(t *testing.T) {
+		stdout, _, code := runApp(t, "validate", "--format", "json", filepath.Join(root, "a.csv"), filepath.Join(root, "nested"))
+		if code != 0 {
+			t.Fatalf("want exit 0, got %d", code)
+		}
+		_, names := batchFiles(t, stdout)
+		if len(names) != 2 {
+			t.Errorf("want 2 files, got %v", names)
+		}
+	})
+
+	t.Run("jobs and streamed results", func(t *testing.T) {
+		stdout, _, code := runApp(t, "validate", "--format", "json", "--jobs", "4", root)
+		batch, names := batchFiles(t, stdout)
+		if code != 1 || len(names) != 5 || names[0] != rel("a.csv") || !batch.Files[2].SchemaUsed {
+			t.Errorf("want the report of one job at a time, got %d: %v", code, names)
+		}
+
+		stdout, _, code = runApp(t, "validate", "--format", "json", "--jobs", "4", "--stream-results", root)
+		lines := strings.Split(strings.TrimSuffix(stdout, "\n"), "\n")
+		if code != 1 || len(lines) != 6 || !strings.Contains(lines[5], `"invalid_files":1`) {
+			t.Errorf("want a JSON line per file and the totals, got %d:\n%s", code, stdout)
+		}
+	})
+
+	t.Run("report per file", func(t *testing.T) {
+		out := t.TempDir()
+		_, _, code := runApp(t, "validate", "--format", "json", "--output", filepath.Join(out, "{{.Name}}.json"), filepath.Join(root, "a.csv"), filepath.Join(root, "tmp"))
+		if code != 1 {
+			t.Errorf("want exit 1 for the ragged scratch file, got %d", code)
+		}
+		var scratch validator.Results
+		data, err := os.ReadFile(filepath.Join(out, "scratch.json"))
+		if err != nil || json.Unmarshal(data, &scratch) != nil || scratch.Valid {
+			t.Errorf("want the scratch file's own report, got %s (%v)", data, err)
+		}
+		if _, err := os.Stat(filepath.Join(out, "a.json")); err != nil {
+			t.Errorf("want a report for a.csv: %v", err)
+		}
+
+		stdout, _, code := runApp(t, "validate", "--format", "json", "--output", "{{.Nope}}.json", root)
+		var doc errorDocument
+		if code != 2 || json.Unmarshal([]byte(stdout), &doc) != nil || doc.Error.Code != "INVALID_ARGUMENT" {
+			t.Errorf("want INVALID_ARGUMENT for an unknown template field, got %d: %s", code, stdout)
+		}
+	})
+
+	t.Run("STDIN cannot be mixed with paths", func(t *testing.T) {
+		stdout, _, code := runApp(t, "validate", "--format", "json", "-", root)
+		if code != 2 {
+			t.Fatalf("want exit 2, got %d", code)
+		}
+		var doc errorDocument
+		if err := json.Unmarshal([]byte(stdout), &doc); err != nil {
+			t.Fatalf("invalid json output: %v\nstdout=%s", err, stdout)
+		}
+		if doc.Error.Code != "INVALID_ARGUMENT" {
+			t.Errorf("want INVALID_ARGUMENT, got %s", doc.Error.Code)
+		}
+	})
+}
+
+func TestValidateCommand_NestedConfig(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		".git/HEAD":                "",
+		".csvlinter.yml":           "ragged:\n  short: warn\n",
+		"a.csv":                    "id,name\n1\n",
+		"finance/.csvlinter.yml":   "ragged:\n  short: error\n",
+		"finance/b.csv":            "id,name\n1\n",
+		"finance/q1/c.csv":         "id,name\n1\n",
+		"marketing/d.csv":          "id,name\n1\n",
+		"marketing/.csvlinter.yml": "quoting: all\n",
+	})
+
+	stdout, _, code := runApp(t, "validate", "--format", "json", "--no-cache", root)
+	batch, names := batchFiles(t, stdout)
+	if code != 1 || len(names) != 4 {
+		t.Fatalf("want 4 files and exit 1, got %d: %v", code, names)
+	}
+	for _, f := range batch.Files {
+		finance := strings.Contains(filepath.ToSlash(f.File), "/finance/")
+		if f.Valid == finance || (!finance && len(f.Warnings) == 0) {
+			t.Errorf("%s: want short rows to fail only under finance, got valid=%v, %d warning(s)", f.File, f.Valid, len(f.Warnings))
+		}
+	}
+
+	// The nearest config also applies to a single file
+	_, _, code = runApp(t, "validate", "--format", "json", "--no-cache", filepath.Join(root, "finance", "q1", "c.csv"))
+	if code != 1 {
+		t.Errorf("want the finance config to fail a single file under it, got exit %d", code)
+	}
+	_, _, code = runApp(t, "validate", "--format", "json", "--no-cache", filepath.Join(root, "a.csv"))
+	if code != 0 {
+		t.Errorf("want the root config to only warn, got exit %d", code)
+	}
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+func TestValidateCommand_MaxEncodingErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"users.csv": "id,name\n1,\xff\n2,ok\n3,\xc3(\n"})
+	file := filepath.Join(dir, "users.csv")
+
+	run := func(args ...string) validator.Results {
+		t.Helper()
+		stdout, _, code := runApp(t, append(append([]string{"validate", "--format", "json", "--no-cache"}, args...), file)...)
+		if code != 1 {
+			t.Fatalf("want exit code 1, got %d", code)
+		}
+		var results validator.Results
+		if err := json.Unmarshal([]byte(stdout), &results); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+		}
+		return results
+	}
+
+	results := run()
+	if len(results.Errors) != 1 || results.Errors[0].Message != "invalid UTF-8 encoding at byte 10: ff" || results.Errors[0].Field != "name" {
+		t.Errorf("want the first invalid byte located, got %+v", results.Errors)
+	}
+
+	results = run("--max-encoding-errors", "5")
+	if len(results.Errors) != 2 || results.Errors[1].Line != 4 || results.Errors[1].Value != "c3" {
+		t.Errorf("want both invalid rows, got %+v", results.Errors)
+	}
+
+	stdout, _, _ := runApp(t, "validate", "--format", "json", "--max-encoding-errors", "-1", file)
+	if !strings.Contains(stdout, `"INVALID_ARGUMENT"`) {
+		t.Errorf("want INVALID_ARGUMENT for a negative limit, got %s", stdout)
+	}
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/pkg/csvlinter"
+	"github.com/urfave/cli/v2"
+)
+
+func TestValidateCommand_ErrorDocument(t *testing.T) {
+	dir := t.TempDir()
+	validCSV := filepath.Join(dir, "valid.csv")
+	if err := os.WriteFile(validCSV, []byte("id\n1\n"), 0o644); err != nil {
+		t.Fatalf("write csv: %v", err)
+	}
+	badSchema := filepath.Join(dir, "bad.schema.json")
+	if err := os.WriteFile(badSchema, []byte(`{"type": 12}`), 0o644); err != nil {
+		t.Fatalf("write schema: %v", err)
+	}
+
+	cases := []struct {
+		name string
+		args []string
+		code csvlinter.ErrorCode
+		exit int
+	}{
+		{"missing argument", []string{"validate", "--format", "json"}, csvlinter.CodeInvalidArgument, 2},
+		{"file not found", []string{"validate", "--format", "json", filepath.Join(dir, "nope.csv")}, csvlinter.CodeFileNotFound, 4},
+		{"schema not found", []string{"validate", "--format", "json", "--schema", filepath.Join(dir, "nope.json"), validCSV}, csvlinter.CodeSchemaNotFound, 3},
+		{"schema invalid", []string{"validate", "--format", "json", "--schema", badSchema, validCSV}, csvlinter.CodeSchemaInvalid, 3},
+		{"unsupported format", []string{"validate", "--format", "json", "--format", "xml", validCSV}, csvlinter.CodeInvalidArgument, 2},
+		{"unknown flag", []string{"validate", "--format", "json", "--no-such-flag", validCSV}, csvlinter.CodeInvalidArgument, 2},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			stdout, _, code := runApp(t, tc.args...)
+			if code != tc.exit {
+				t.Errorf("expected exit %d, got %d", tc.exit, code)
+			}
+			var doc errorDocument
+			if err := json.Unmarshal([]byte(stdout), &doc); err != nil {
+				t.Fatalf("expected JSON error document, got %q: %v", stdout, err)
+			}
+			if doc.Error.Code != tc.code {
+				t.Errorf("expected code %s, got %s (%s)", tc.code, doc.Error.Code, doc.Error.Message)
+			}
+			if doc.Error.Message == "" {
+				t.Errorf("expected a message")
+			}
+		})
+	}
+}
+
+func TestExitCodes(t *testing.T) {
+	dir := t.TempDir()
+	validCSV := filepath.Join(dir, "valid.csv")
+	if err := os.WriteFile(validCSV, []byte("id\n1\n"), 0o644); err != nil {
+		t.Fatalf("write csv: %v", err)
+	}
+	invalidCSV := filepath.Join(dir, "invalid.csv")
+	if err := os.WriteFile(invalidCSV, []byte("id,name\n1\n"), 0o644); err != nil {
+		t.Fatalf("write csv: %v", err)
+	}
+
+	cases := []struct {
+		name string
+		args []string
+		exit int
+	}{
+		{"valid", []string{"validate", validCSV}, exitValid},
+		{"invalid data", []string{"validate", invalidCSV}, exitInvalid},
+		{"unknown flag", []string{"validate", "--no-such-flag", validCSV}, exitUsage},
+		{"unknown command", []string{"no-such-command"}, exitUsage},
+		{"schema not found", []string{"validate", "--schema", filepath.Join(dir, "nope.json"), validCSV}, exitSchema},
+		{"file not found", []string{"validate", filepath.Join(dir, "nope.csv")}, exitIO},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, code := runApp(t, tc.args...)
+			if code != tc.exit {
+				t.Errorf("expected exit %d, got %d", tc.exit, code)
+			}
+		})
+	}
+}
+
+func TestExitStatus_Interrupted(t *testing.T) {
+	for _, valid := range []bool{true, false} {
+		err := exitStatus("pretty", valid, true)
+		exitErr, ok := err.(cli.ExitCoder)
+		if !ok || exitErr.ExitCode() != exitCancelled {
+			t.Errorf("want exit %d for an interrupted run (valid %t), got %v", exitCancelled, valid, err)
+		}
+	}
+}
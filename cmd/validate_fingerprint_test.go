@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+func TestValidateCommand_Fingerprint(t *testing.T) {
+	dir := t.TempDir()
+	content := "id,name\n1,alice\n2,bob\n"
+	writeTree(t, dir, map[string]string{
+		"people.csv": content,
+		"people.tsv": "name\tid\nalice\t1\nbob\t2\n",
+	})
+	sum := sha256.Sum256([]byte(content))
+
+	results := func(name string) validator.Results {
+		t.Helper()
+		stdout, _, code := runApp(t, "validate", "--format", "json", "--fingerprint", "--no-cache", filepath.Join(dir, name))
+		if code != 0 {
+			t.Fatalf("want exit 0, got %d", code)
+		}
+		var res validator.Results
+		if err := json.Unmarshal([]byte(stdout), &res); err != nil {
+			t.Fatalf("invalid json output: %v\nstdout=%s", err, stdout)
+		}
+		return res
+	}
+	csv, tsv := results("people.csv"), results("people.tsv")
+	if csv.SHA256 != hex.EncodeToString(sum[:]) {
+		t.Errorf("want sha256 %x, got %s", sum, csv.SHA256)
+	}
+	if csv.Fingerprint == "" || csv.Fingerprint != tsv.Fingerprint {
+		t.Errorf("want equal fingerprints for the same rows, got %q and %q", csv.Fingerprint, tsv.Fingerprint)
+	}
+
+	stdout, _, _ := runApp(t, "validate", "--fingerprint", filepath.Join(dir, "people.csv"))
+	if !strings.Contains(stdout, "SHA-256: "+csv.SHA256) || !strings.Contains(stdout, "Fingerprint: "+csv.Fingerprint) {
+		t.Errorf("want hashes in pretty output:\n%s", stdout)
+	}
+
+	stdout, _, _ = runApp(t, "validate", "--format", "json", filepath.Join(dir, "people.csv"))
+	if strings.Contains(stdout, "sha256") {
+		t.Errorf("want no hashes without --fingerprint:\n%s", stdout)
+	}
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// inGitRepo creates a git repository with an initial commit and makes it the working
+// directory for the rest of the test.
+func inGitRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	gitCmd(t, "init", "-q")
+	gitCmd(t, "config", "user.email", "test@example.com")
+	gitCmd(t, "config", "user.name", "test")
+	gitCmd(t, "commit", "-q", "--allow-empty", "-m", "init")
+	return dir
+}
+
+func gitCmd(t *testing.T, args ...string) {
+	t.Helper()
+	if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func TestValidateCommand_Staged(t *testing.T) {
+	inGitRepo(t)
+	writeTree(t, ".", map[string]string{
+		"good.csv":       "id,name\n1,Alice\n",
+		"data/bad.csv":   "id,name\n1\n",
+		"unstaged.csv":   "id,name\n1\n",
+		"fixtures/x.csv": "id,name\n1\n",
+		"notes.txt":      "text",
+	})
+	gitCmd(t, "add", "good.csv", "data/bad.csv", "fixtures/x.csv", "notes.txt")
+
+	stdout, stderr, code := runApp(t, "validate", "--staged", "--format", "json", "--exclude", "fixtures/")
+	if code != 1 {
+		t.Fatalf("want exit 1 for the staged bad.csv, got %d; stderr=%s", code, stderr)
+	}
+	_, names := batchFiles(t, stdout)
+	if len(names) != 2 || names[0] != "data/bad.csv" || names[1] != "good.csv" {
+		t.Errorf("want only staged CSV files, got %v", names)
+	}
+
+	gitCmd(t, "reset", "-q", "data/bad.csv")
+	if _, stderr, code := runApp(t, "validate", "--staged", "--exclude", "fixtures/"); code != 0 {
+		t.Errorf("want exit 0 once bad.csv is unstaged, got %d; stderr=%s", code, stderr)
+	}
+}
+
+func TestValidateCommand_StagedContent(t *testing.T) {
+	inGitRepo(t)
+	writeTree(t, ".", map[string]string{"data/a.csv": "id,name\n1\n", "b.csv": "id,name\n1,Alice\n"})
+	gitCmd(t, "add", ".")
+	// Fixing a.csv without staging the fix, and breaking b.csv likewise, changes nothing
+	writeTree(t, ".", map[string]string{"data/a.csv": "id,name\n1,Alice\n", "b.csv": "id,name\n1\n"})
+
+	stdout, stderr, code := runApp(t, "validate", "--staged", "--format", "json", "--no-cache")
+	if code != 1 {
+		t.Fatalf("want exit 1 for the staged a.csv, got %d; stderr=%s", code, stderr)
+	}
+	batch, _ := batchFiles(t, stdout)
+	for _, f := range batch.Files {
+		if f.Valid != (f.File == "b.csv") {
+			t.Errorf("%s: want the staged content validated, got valid=%v", f.File, f.Valid)
+		}
+	}
+}
+
+func TestValidateCommand_ChangedSince(t *testing.T) {
+	inGitRepo(t)
+	writeTree(t, ".", map[string]string{"old.csv": "id,name\n1\n", "keep.csv": "id,name\n1,Alice\n"})
+	gitCmd(t, "add", ".")
+	gitCmd(t, "commit", "-q", "-m", "data")
+	gitCmd(t, "tag", "v1")
+	writeTree(t, ".", map[string]string{"new.csv": "id,name\n1,Bob\n", "keep.csv": "id,name\n2,Carol\n"})
+	gitCmd(t, "add", "new.csv")
+	gitCmd(t, "commit", "-q", "-m", "more")
+
+	stdout, stderr, code := runApp(t, "validate", "--changed-since", "v1", "--format", "json")
+	if code != 0 {
+		t.Fatalf("want exit 0 (the invalid old.csv is unchanged), got %d; stderr=%s", code, stderr)
+	}
+	_, names := batchFiles(t, stdout)
+	if len(names) != 2 || names[0] != "keep.csv" || names[1] != "new.csv" {
+		t.Errorf("want committed and uncommitted changes since v1, got %v", names)
+	}
+
+	stdout, _, code = runApp(t, "validate", "--changed-since", "no-such-ref", "--format", "json")
+	if code != 2 || !strings.Contains(stdout, "INVALID_ARGUMENT") {
+		t.Errorf("want INVALID_ARGUMENT for an unknown ref, got exit %d: %s", code, stdout)
+	}
+}
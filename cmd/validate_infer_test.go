@@ -177,3 +177,40 @@ func TestValidateCommand_InferSchema(t *testing.T) {
 		}
 	})
 }
+
+func TestValidateCommand_Like(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"golden.csv": "id;name;joined\n1;Ana;2024-01-15\n2;Bo;\n",
+		"good.csv":   "id;name;joined\n7;Cy;2024-03-01\n8;;\n",
+		"bad.csv":    "id;joined;name\nx;2024-03-01;Cy\n",
+		"comma.csv":  "id,name,joined\n7,Cy,2024-03-01\n",
+	})
+	golden := filepath.Join(dir, "golden.csv")
+	run := func(file string) (validator.Results, int) {
+		t.Helper()
+		stdout, _, code := runApp(t, "validate", "--format", "json", "--no-cache", "--like", golden, filepath.Join(dir, file))
+		var res validator.Results
+		if err := json.Unmarshal([]byte(stdout), &res); err != nil {
+			t.Fatalf("invalid json output: %v\nstdout=%s", err, stdout)
+		}
+		return res, code
+	}
+
+	if res, code := run("good.csv"); code != 0 || !res.Valid {
+		t.Errorf("want a file like the golden one valid, got %d: %+v", code, res.Errors)
+	}
+	res, code := run("bad.csv")
+	if code != 1 || len(res.Errors) != 2 || res.Errors[0].RuleID != "STR013" || res.Errors[1].RuleID != "SCH002" || res.Errors[1].Field != "id" {
+		t.Errorf("want the column order and the id type reported, got %d: %+v", code, res.Errors)
+	}
+	res, _ = run("comma.csv")
+	if len(res.Errors) != 1 || res.Errors[0].Message != `the file is delimited by ",", not ";" like `+golden {
+		t.Errorf("want the other delimiter reported once, got %+v", res.Errors)
+	}
+
+	_, _, code = runApp(t, "validate", "--like", filepath.Join(dir, "missing.csv"), filepath.Join(dir, "good.csv"))
+	if code == 0 {
+		t.Error("want a missing like file to fail")
+	}
+}
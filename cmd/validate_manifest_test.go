@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+func TestValidateCommand_Manifest(t *testing.T) {
+	dir := t.TempDir()
+	orders := "id,amount\n1,10\n2,20\n"
+	sum := sha256.Sum256([]byte(orders))
+	writeTree(t, dir, map[string]string{
+		"batch/orders.csv":    orders,
+		"batch/customers.csv": "id\n1\n",
+		"batch/manifest.json": fmt.Sprintf(`{"files":[
+			{"name":"orders.csv","rows":2,"sha256":"%s"},
+			{"name":"customers.csv","rows":5},
+			{"name":"refunds.csv"}
+		]}`, hex.EncodeToString(sum[:])),
+	})
+	manifestPath := filepath.Join(dir, "batch", "manifest.json")
+
+	for name, args := range map[string][]string{
+		"with a directory": {"validate", "--format", "json", "--manifest", manifestPath, filepath.Join(dir, "batch")},
+		"manifest only":    {"validate", "--format", "json", "--manifest", manifestPath},
+	} {
+		t.Run(name, func(t *testing.T) {
+			stdout, _, code := runApp(t, args...)
+			if code != 1 {
+				t.Fatalf("want exit 1, got %d", code)
+			}
+			var batch validator.Batch
+			if err := json.Unmarshal([]byte(stdout), &batch); err != nil {
+				t.Fatalf("invalid json output: %v\nstdout=%s", err, stdout)
+			}
+			rules := map[string]string{}
+			for _, f := range batch.Files {
+				for _, e := range f.Errors {
+					rules[filepath.Base(f.File)] += e.RuleID
+				}
+			}
+			want := map[string]string{"customers.csv": "MAN003", "refunds.csv": "MAN001"}
+			if batch.TotalFiles != 3 || len(rules) != 2 || rules["customers.csv"] != want["customers.csv"] || rules["refunds.csv"] != want["refunds.csv"] {
+				t.Errorf("want %v over 3 files, got %v over %d", want, rules, batch.TotalFiles)
+			}
+		})
+	}
+
+	stdout, _, code := runApp(t, "validate", "--format", "json", "--manifest", filepath.Join(dir, "nope.json"))
+	if code != 2 || !json.Valid([]byte(stdout)) || !strings.Contains(stdout, "MANIFEST_INVALID") {
+		t.Errorf("want MANIFEST_INVALID, got %d: %s", code, stdout)
+	}
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateCommand_MessageTemplates(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"people.csv":         "email,age\nalice-at-example.com,abc\n",
+		"people.schema.json": `{"type":"object","properties":{"age":{"type":"integer"}}}`,
+		"lint.yml":           "messages:\n  SCH002: \"{{.Field}} must be {{.Expected}}, not {{.Value}}; see https://runbooks.example.com/{{.RuleID}}\"\n",
+		"bad.yml":            "messages:\n  SCH002: \"{{.Field\"\n",
+	})
+
+	stdout, _, code := runApp(t, "validate", "--no-cache", "--config", filepath.Join(dir, "lint.yml"), filepath.Join(dir, "people.csv"))
+	if code != 1 || !strings.Contains(stdout, "Line 2 (age): age must be integer, not abc; see https://runbooks.example.com/SCH002 (value: \"abc\") [schema]") {
+		t.Errorf("want the templated message, got %d: %s", code, stdout)
+	}
+
+	// Values embedded by templates are redacted like those of built-in messages
+	stdout, _, _ = runApp(t, "validate", "--no-cache", "--redact-values", "--format", "json", "--config", filepath.Join(dir, "lint.yml"), filepath.Join(dir, "people.csv"))
+	if strings.Contains(stdout, "abc") || !strings.Contains(stdout, `"message": "age must be integer, not *** (3 chars); see`) {
+		t.Errorf("want the templated value redacted, got %s", stdout)
+	}
+
+	stdout, _, code = runApp(t, "validate", "--format", "json", "--config", filepath.Join(dir, "bad.yml"), filepath.Join(dir, "people.csv"))
+	if code != 2 || !strings.Contains(stdout, `"INVALID_ARGUMENT"`) {
+		t.Errorf("want INVALID_ARGUMENT for a broken template, got %d: %s", code, stdout)
+	}
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateCommand_MetricsOut(t *testing.T) {
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "data.csv")
+	if err := os.WriteFile(csvPath, []byte("id,name\n1,Alice\n2,Bob,extra\n"), 0o644); err != nil {
+		t.Fatalf("write csv: %v", err)
+	}
+	promPath := filepath.Join(dir, "csvlinter.prom")
+
+	_, _, code := runApp(t, "validate", "--metrics-out", promPath, csvPath)
+	if code != 1 {
+		t.Errorf("expected exit 1, got %d", code)
+	}
+	content, err := os.ReadFile(promPath)
+	if err != nil {
+		t.Fatalf("expected metrics file: %v", err)
+	}
+	for _, want := range []string{
+		"csvlinter_rows_total{file=\"" + csvPath + "\"} 2",
+		"csvlinter_errors_total{file=\"" + csvPath + "\",rule=\"STR001\"} 1",
+		"csvlinter_valid{file=\"" + csvPath + "\"} 0",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("expected metrics to contain %q\n%s", want, content)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+func TestValidateCommand_MultipleSchemas(t *testing.T) {
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "orders.csv")
+	structural := filepath.Join(dir, "orders.schema.json")
+	business := filepath.Join(dir, "rules", "business.schema.json")
+	writeTree(t, dir, map[string]string{
+		"orders.csv":                 "id,amount\n1,50\n2,5000\n",
+		"orders.schema.json":         `{"type":"object","properties":{"id":{"type":"integer"},"amount":{"type":"number"}}}`,
+		"rules/business.schema.json": `{"type":"object","properties":{"amount":{"type":"number","maximum":1000}}}`,
+	})
+
+	check := func(stdout string) {
+		t.Helper()
+		var res validator.Results
+		if err := json.Unmarshal([]byte(stdout), &res); err != nil {
+			t.Fatalf("invalid json output: %v\nstdout=%s", err, stdout)
+		}
+		if len(res.Errors) != 1 || res.Errors[0].Schema != business || res.Errors[0].Line != 3 {
+			t.Errorf("want one error from the business schema, got %+v", res.Errors)
+		}
+	}
+
+	t.Run("repeated --schema", func(t *testing.T) {
+		stdout, _, code := runApp(t, "validate", "--format", "json", "--schema", structural, "--schema", business, csvPath)
+		if code != 1 {
+			t.Fatalf("want exit 1, got %d", code)
+		}
+		check(stdout)
+	})
+
+	t.Run("config schemas add to the resolved schema", func(t *testing.T) {
+		configPath := filepath.Join(dir, ".csvlinter.yml")
+		if err := os.WriteFile(configPath, []byte("schemas:\n  - rules/business.schema.json\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		stdout, _, code := runApp(t, "validate", "--format", "json", "--config", configPath, csvPath)
+		if code != 1 {
+			t.Fatalf("want exit 1, got %d", code)
+		}
+		check(stdout)
+
+		stdout, _, _ = runApp(t, "validate", "--config", configPath, csvPath)
+		if !strings.Contains(stdout, "[schema: "+business+"]") {
+			t.Errorf("want the schema named in pretty output:\n%s", stdout)
+		}
+	})
+}
+
+func TestValidateCommand_Discriminator(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"export.csv":                 "type,amount\nsale,10\nrefund,-5\nrefund,5\n",
+		"schemas/refund.schema.json": `{"type":"object","properties":{"amount":{"type":"number","maximum":0}}}`,
+		".csvlinter.yml":             "discriminator:\n  column: type\n  schemas:\n    refund: schemas/refund.schema.json\n",
+	})
+
+	stdout, _, code := runApp(t, "validate", "--format", "json", "--config", filepath.Join(dir, ".csvlinter.yml"), filepath.Join(dir, "export.csv"))
+	if code != 1 {
+		t.Fatalf("want exit 1, got %d", code)
+	}
+	var res validator.Results
+	if err := json.Unmarshal([]byte(stdout), &res); err != nil {
+		t.Fatalf("invalid json output: %v\nstdout=%s", err, stdout)
+	}
+	want := filepath.Join(dir, "schemas", "refund.schema.json")
+	if len(res.Errors) != 1 || res.Errors[0].Line != 4 || res.Errors[0].Schema != want {
+		t.Errorf("want one refund schema error on line 4, got %+v", res.Errors)
+	}
+}
+
+func TestValidateCommand_Contract(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"producer.schema.json": `{"properties":{"status":{"enum":["open","pending","closed"]}}}`,
+		"consumer.schema.json": `{"properties":{"status":{"enum":["open","closed"]}}}`,
+		"orders.csv":           "status\nopen\npending\n",
+	})
+	args := []string{"validate", "--producer-schema", filepath.Join(dir, "producer.schema.json"),
+		"--consumer-schema", filepath.Join(dir, "consumer.schema.json"), filepath.Join(dir, "orders.csv")}
+
+	stdout, _, code := runApp(t, args...)
+	if code != 1 || !strings.Contains(stdout, "Contract: consumer rejects the file (1 error(s)); producer accepts it") ||
+		!strings.Contains(stdout, "[schema: consumer]") {
+		t.Errorf("want the consumer named as the rejecting side, got %d:\n%s", code, stdout)
+	}
+
+	stdout, _, _ = runApp(t, append([]string{"validate", "--format", "json"}, args[1:]...)...)
+	var res validator.Results
+	if err := json.Unmarshal([]byte(stdout), &res); err != nil {
+		t.Fatalf("invalid json output: %v\nstdout=%s", err, stdout)
+	}
+	if res.Contract == nil || res.Contract.RejectedBy != "consumer" || res.Contract.ConsumerErrors != 1 {
+		t.Errorf("want a contract rejected by the consumer, got %+v", res.Contract)
+	}
+}
+
+func TestValidateCommand_FileSchema(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"users.schema.json": `{"properties":{"id":{"type":"integer"}}}`,
+		"dataset.json": `{"type":"array","minItems":1,"uniqueItems":true,
+			"contains":{"properties":{"role":{"const":"admin"}},"required":["role"]},
+			"items":{"properties":{"id":{"type":"integer"},"role":{"type":"string"}}}}`,
+		"users.csv": "id,role\n1,user\n2,user\n1,user\n",
+	})
+	args := []string{"validate", "--format", "json", "--schema", filepath.Join(dir, "users.schema.json"),
+		"--file-schema", filepath.Join(dir, "dataset.json"), filepath.Join(dir, "users.csv")}
+
+	stdout, _, code := runApp(t, args...)
+	if code != 1 {
+		t.Fatalf("want exit 1, got %d: %s", code, stdout)
+	}
+	var res validator.Results
+	if err := json.Unmarshal([]byte(stdout), &res); err != nil {
+		t.Fatalf("invalid json output: %v\nstdout=%s", err, stdout)
+	}
+	for _, e := range res.Errors {
+		if e.Line != 1 || e.Schema != filepath.Join(dir, "dataset.json") {
+			t.Errorf("want file-level findings on line 1 naming the file schema, got %+v", e)
+		}
+	}
+	if len(res.Errors) != 2 || !strings.Contains(stdout, "are equal") || !strings.Contains(stdout, "rows matching contains") {
+		t.Errorf("want uniqueItems and contains findings, got %+v", res.Errors)
+	}
+
+	stdout, _, _ = runApp(t, "validate", "--format", "json", "--file-schema", filepath.Join(dir, "missing.json"), filepath.Join(dir, "users.csv"))
+	if !strings.Contains(stdout, `"SCHEMA_NOT_FOUND"`) {
+		t.Errorf("want SCHEMA_NOT_FOUND for a missing file schema, got %s", stdout)
+	}
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/notify"
+)
+
+func TestValidateCommand_NotifyWebhook(t *testing.T) {
+	received := make(chan notify.Payload, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p notify.Payload
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			t.Errorf("decode payload: %v", err)
+		}
+		received <- p
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "data.csv")
+	if err := os.WriteFile(csvPath, []byte("id,name\n1,Alice\n2\n"), 0o644); err != nil {
+		t.Fatalf("write csv: %v", err)
+	}
+
+	t.Run("flag", func(t *testing.T) {
+		_, _, code := runApp(t, "validate", "--format", "json", "--notify-webhook", srv.URL, csvPath)
+		if code != 1 {
+			t.Errorf("expected exit 1 for invalid file, got %d", code)
+		}
+		p := <-received
+		if p.Status != "invalid" || p.ErrorCount != 1 || !strings.HasSuffix(p.File, "data.csv") {
+			t.Errorf("unexpected payload: %+v", p)
+		}
+	})
+
+	t.Run("config file", func(t *testing.T) {
+		cfgPath := filepath.Join(dir, "notify.yml")
+		if err := os.WriteFile(cfgPath, []byte("notify:\n  webhook: "+srv.URL+"\n"), 0o644); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+		runApp(t, "validate", "--format", "json", "--config", cfgPath, csvPath)
+		if p := <-received; p.TotalRows != 2 {
+			t.Errorf("expected 2 rows in payload, got %+v", p)
+		}
+	})
+
+	t.Run("delivery failure does not change exit code", func(t *testing.T) {
+		validPath := filepath.Join(dir, "valid.csv")
+		if err := os.WriteFile(validPath, []byte("id\n1\n"), 0o644); err != nil {
+			t.Fatalf("write csv: %v", err)
+		}
+		_, stderr, code := runApp(t, "validate", "--notify-webhook", "http://127.0.0.1:1/hook", validPath)
+		if code != 0 {
+			t.Errorf("expected exit 0, got %d", code)
+		}
+		if !strings.Contains(stderr, "webhook notification failed") {
+			t.Errorf("expected warning on stderr, got %q", stderr)
+		}
+	})
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateCommand_WritePatch(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"in/people.csv":         "sep=,\nid,status,name\n\"1\",Active,ann\n2,closed, bob \n3,closed\n",
+		"in/people.schema.json": `{"type":"object","properties":{"status":{"enum":["active","closed"]},"name":{"type":"string","pattern":"^[a-z]+$"}}}`,
+	})
+	wd, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	_, stderr, code := runApp(t, "validate", "--no-cache", "--quoting", "minimal", "--write-patch", "fixes.diff", "in/people.csv")
+	if code != 1 || !strings.Contains(stderr, "Wrote 4 fix(es) for 1 file(s) to fixes.diff") {
+		t.Errorf("want the fixes counted, got %d: %s", code, stderr)
+	}
+	diff, err := os.ReadFile("fixes.diff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "--- a/in/people.csv\n+++ b/in/people.csv\n@@ -1,5 +1,5 @@\n sep=,\n id,status,name\n" +
+		"-\"1\",Active,ann\n-2,closed, bob \n-3,closed\n+1,active,ann\n+2,closed,bob\n+3,closed,\n"
+	if string(diff) != want {
+		t.Errorf("diff:\n%s\nwant:\n%s", diff, want)
+	}
+
+	// SARIF reports carry the fixes without --write-patch
+	stdout, _, _ := runApp(t, "validate", "--no-cache", "--format", "sarif", "in/people.csv")
+	if !strings.Contains(stdout, `"version": "2.1.0"`) || !strings.Contains(stdout, `"insertedContent": {`) || !strings.Contains(stdout, `"text": "active"`) {
+		t.Errorf("want a SARIF log with fixes, got %s", stdout)
+	}
+
+	for _, args := range [][]string{
+		{"--dedupe-errors", "in/people.csv"},
+		{"-"},
+	} {
+		stdout, _, code := runApp(t, append([]string{"validate", "--format", "json", "--write-patch", "other.diff"}, args...)...)
+		if code != 2 || !strings.Contains(stdout, `"INVALID_ARGUMENT"`) {
+			t.Errorf("%v: want INVALID_ARGUMENT, got %d: %s", args, code, stdout)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "other.diff")); !os.IsNotExist(err) {
+		t.Errorf("want no patch written for invalid arguments, got %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+func TestValidateCommand_RaggedRows(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"users.csv":      "id,name,email\n1,a\n2,b,b@example.com,x\n",
+		".csvlinter.yml": "ragged:\n  short: pad\n  long: warn\n",
+	})
+	file := filepath.Join(dir, "users.csv")
+
+	stdout, _, code := runApp(t, "validate", "--format", "json", "--no-cache", "--config", filepath.Join(dir, ".csvlinter.yml"), file)
+	var results validator.Results
+	if err := json.Unmarshal([]byte(stdout), &results); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+	}
+	if code != 0 || len(results.Errors) != 0 || len(results.Warnings) != 1 || results.Warnings[0].RuleID != "STR006" {
+		t.Errorf("want a padded short row and a long row warning, got %d: %s", code, stdout)
+	}
+
+	stdout, _, code = runApp(t, "validate", "--format", "json", "--no-cache", "--config", filepath.Join(dir, ".csvlinter.yml"), "--short-rows", "error", file)
+	if code != 1 || !strings.Contains(stdout, `"STR001"`) {
+		t.Errorf("want --short-rows to override the config, got %d: %s", code, stdout)
+	}
+
+	stdout, _, code = runApp(t, "validate", "--format", "json", "--long-rows", "pad", file)
+	if code != 2 || !strings.Contains(stdout, `"INVALID_ARGUMENT"`) {
+		t.Errorf("want INVALID_ARGUMENT for pad on long rows, got %d: %s", code, stdout)
+	}
+}
+
+func TestValidateCommand_RequireData(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"empty.csv":             "id,name\n",
+		"strict/empty.csv":      "id,name\n",
+		"strict/.csvlinter.yml": "require_data: true\n",
+	})
+	file := filepath.Join(dir, "empty.csv")
+
+	stdout, _, code := runApp(t, "validate", "--format", "json", "--no-cache", file)
+	if code != 0 || !strings.Contains(stdout, `"STR009"`) || !strings.Contains(stdout, `"severity": "warning"`) {
+		t.Errorf("want a header-only file to pass with a warning, got %d: %s", code, stdout)
+	}
+	stdout, _, code = runApp(t, "validate", "--format", "json", "--no-cache", "--require-data", file)
+	if code != 1 || !strings.Contains(stdout, `"STR009"`) || !strings.Contains(stdout, `"severity": "error"`) {
+		t.Errorf("want --require-data to fail it, got %d: %s", code, stdout)
+	}
+	stdout, _, code = runApp(t, "validate", "--format", "json", "--no-cache", filepath.Join(dir, "strict", "empty.csv"))
+	if code != 1 || !strings.Contains(stdout, `"STR009"`) {
+		t.Errorf("want require_data in the config to fail it, got %d: %s", code, stdout)
+	}
+}
+
+func TestValidateCommand_Chunked(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"q1.csv": "id,name\n1,a\nid,name\n2,b\n"})
+	file := filepath.Join(dir, "q1.csv")
+
+	stdout, _, code := runApp(t, "validate", "--format", "json", "--no-cache", file)
+	if code != 1 || !strings.Contains(stdout, `"STR011"`) {
+		t.Errorf("want the repeated header reported without --chunked, got %d: %s", code, stdout)
+	}
+	stdout, _, code = runApp(t, "validate", "--format", "json", "--no-cache", "--chunked", file)
+	if code != 0 || strings.Contains(stdout, `"STR011"`) || !strings.Contains(stdout, `"total_rows": 2`) {
+		t.Errorf("want the chunk header skipped with --chunked, got %d: %s", code, stdout)
+	}
+}
+
+func TestValidateCommand_Escape(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"people.csv": "id,name\n1,Smith\\, John\n"})
+	file := filepath.Join(dir, "people.csv")
+
+	stdout, _, code := runApp(t, "validate", "--format", "json", "--no-cache", file)
+	if code != 1 || !strings.Contains(stdout, `"STR001"`) {
+		t.Errorf("want the escaped delimiter read as one without --escape, got %d: %s", code, stdout)
+	}
+	stdout, _, code = runApp(t, "validate", "--format", "json", "--no-cache", "--escape", `\`, file)
+	if code != 0 {
+		t.Errorf("want the escaped delimiter read with --escape, got %d: %s", code, stdout)
+	}
+	stdout, _, code = runApp(t, "validate", "--format", "json", "--no-cache", "--escape", ",", file)
+	if code == 0 || !strings.Contains(stdout, "INVALID_ARGUMENT") {
+		t.Errorf("want an escape equal to the delimiter rejected, got %d: %s", code, stdout)
+	}
+}
+
+func TestValidateCommand_Quote(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"people.csv": "id,name\n1,'Smith, John'\n"})
+	file := filepath.Join(dir, "people.csv")
+
+	stdout, _, code := runApp(t, "validate", "--format", "json", "--no-cache", file)
+	if code != 1 || !strings.Contains(stdout, `"STR001"`) {
+		t.Errorf("want the single-quoted delimiter split without --quote, got %d: %s", code, stdout)
+	}
+	stdout, _, code = runApp(t, "validate", "--format", "json", "--no-cache", "--quote", "'", file)
+	if code != 0 {
+		t.Errorf("want the single-quoted field read with --quote, got %d: %s", code, stdout)
+	}
+	writeTree(t, dir, map[string]string{"sizes.csv": "id,size\n1,5\" pipe\n"})
+	stdout, _, code = runApp(t, "validate", "--format", "json", "--no-cache", "--quote", "none", filepath.Join(dir, "sizes.csv"))
+	if code != 0 {
+		t.Errorf("want the bare quote read with --quote none, got %d: %s", code, stdout)
+	}
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateCommand_RedactValues(t *testing.T) {
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "people.csv")
+	if err := os.WriteFile(csvPath, []byte("email,age\nalice-at-example.com,abc\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	schema := `{"type":"object","properties":{"email":{"type":"string","format":"email"},"age":{"type":"integer"}}}`
+	if err := os.WriteFile(filepath.Join(dir, "people.schema.json"), []byte(schema), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, _, _ := runApp(t, "validate", "--no-cache", "--format", "json", csvPath)
+	if !strings.Contains(stdout, "alice-at-example.com") {
+		t.Fatalf("expected the raw value without redaction:\n%s", stdout)
+	}
+
+	for _, format := range []string{"json", "pretty"} {
+		stdout, _, code := runApp(t, "validate", "--redact-values", "--format", format, csvPath)
+		if code != 1 {
+			t.Fatalf("want exit 1, got %d", code)
+		}
+		if strings.Contains(stdout, "alice-at-example.com") || strings.Contains(stdout, "abc") {
+			t.Errorf("%s output leaks values:\n%s", format, stdout)
+		}
+		if !strings.Contains(stdout, "al*** (20 chars)") {
+			t.Errorf("%s output should keep prefix and length:\n%s", format, stdout)
+		}
+	}
+
+	t.Run("columns from config", func(t *testing.T) {
+		configPath := filepath.Join(dir, "redact.yml")
+		if err := os.WriteFile(configPath, []byte("redact:\n  mode: hash\n  columns: [email]\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		stdout, _, _ := runApp(t, "validate", "--config", configPath, "--format", "json", csvPath)
+		if strings.Contains(stdout, "alice-at-example.com") || !strings.Contains(stdout, "sha256:") {
+			t.Errorf("expected email hashed:\n%s", stdout)
+		}
+		if !strings.Contains(stdout, `"value": "abc"`) {
+			t.Errorf("expected age kept as is:\n%s", stdout)
+		}
+	})
+
+	t.Run("invalid mode", func(t *testing.T) {
+		stdout, _, code := runApp(t, "validate", "--redact-mode", "scramble", "--format", "json", csvPath)
+		if code != 2 || !strings.Contains(stdout, "INVALID_ARGUMENT") {
+			t.Errorf("want INVALID_ARGUMENT, got exit %d: %s", code, stdout)
+		}
+	})
+}
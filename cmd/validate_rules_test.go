@@ -0,0 +1,321 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+func TestValidateCommand_GroupRules(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"orders.csv": "order_id,line_type,sku\n1,header,\n1,item,A\n2,item,B\n",
+		".csvlinter.yml": `rules:
+  groups:
+    - by: order_id
+      where: {line_type: header}
+      count: 1
+`,
+	})
+
+	stdout, _, code := runApp(t, "validate", "--format", "json", "--config", filepath.Join(dir, ".csvlinter.yml"), filepath.Join(dir, "orders.csv"))
+	if code != 1 {
+		t.Fatalf("want exit 1, got %d", code)
+	}
+	var res validator.Results
+	if err := json.Unmarshal([]byte(stdout), &res); err != nil {
+		t.Fatalf("invalid json output: %v\nstdout=%s", err, stdout)
+	}
+	if len(res.Errors) != 1 || res.Errors[0].RuleID != "DAT001" || res.Errors[0].Value != "2" || res.Errors[0].Type != "data" {
+		t.Errorf("want one group error for order 2, got %+v", res.Errors)
+	}
+}
+
+func TestValidateCommand_ExpressionRules(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"orders.csv": "quantity,unit_price,total\n2,4.99,9.98\n3,5,14\n",
+		".csvlinter.yml": `rules:
+  expressions:
+    - assert: total == quantity * unit_price
+      tolerance: 0.005
+`,
+		"bad.yml": "rules:\n  expressions:\n    - assert: total = 1\n",
+	})
+
+	stdout, _, code := runApp(t, "validate", "--format", "json", "--config", filepath.Join(dir, ".csvlinter.yml"), filepath.Join(dir, "orders.csv"))
+	if code != 1 {
+		t.Fatalf("want exit 1, got %d", code)
+	}
+	var res validator.Results
+	if err := json.Unmarshal([]byte(stdout), &res); err != nil {
+		t.Fatalf("invalid json output: %v\nstdout=%s", err, stdout)
+	}
+	if len(res.Errors) != 1 || res.Errors[0].RuleID != "DAT003" || res.Errors[0].Line != 3 ||
+		!strings.Contains(res.Errors[0].Message, "total is 14, quantity * unit_price is 15") {
+		t.Errorf("want one failed assertion on line 3 with both sides, got %+v", res.Errors)
+	}
+
+	stdout, _, _ = runApp(t, "validate", "--format", "json", "--config", filepath.Join(dir, "bad.yml"), filepath.Join(dir, "orders.csv"))
+	if !strings.Contains(stdout, `"INVALID_ARGUMENT"`) {
+		t.Errorf("want an INVALID_ARGUMENT error for an invalid assertion, got %s", stdout)
+	}
+}
+
+func TestValidateCommand_OutlierRules(t *testing.T) {
+	dir := t.TempDir()
+	var data strings.Builder
+	data.WriteString("sku,weight_kg\n")
+	for i, w := range []string{"2.1", "1.9", "2.4", "2.0", "2.2", "1.8", "2150", "2.3", "2.0", "1.9", "2.1"} {
+		data.WriteString(string(rune('A'+i)) + "," + w + "\n")
+	}
+	writeTree(t, dir, map[string]string{
+		"parcels.csv": data.String(),
+		".csvlinter.yml": `rules:
+  outliers:
+    - method: zscore
+      columns: [weight_kg]
+`,
+		"bad.yml": "rules:\n  outliers:\n    - method: median\n",
+	})
+
+	stdout, _, code := runApp(t, "validate", "--format", "json", "--config", filepath.Join(dir, ".csvlinter.yml"), filepath.Join(dir, "parcels.csv"))
+	if code != 0 {
+		t.Fatalf("want exit 0 for warnings only, got %d", code)
+	}
+	var res validator.Results
+	if err := json.Unmarshal([]byte(stdout), &res); err != nil {
+		t.Fatalf("invalid json output: %v\nstdout=%s", err, stdout)
+	}
+	if !res.Valid || len(res.Warnings) != 1 || res.Warnings[0].RuleID != "DAT004" || res.Warnings[0].Line != 8 {
+		t.Errorf("want one outlier warning on line 8, got %+v", res.Warnings)
+	}
+
+	stdout, _, _ = runApp(t, "validate", "--format", "json", "--config", filepath.Join(dir, "bad.yml"), filepath.Join(dir, "parcels.csv"))
+	if !strings.Contains(stdout, `"INVALID_ARGUMENT"`) {
+		t.Errorf("want an INVALID_ARGUMENT error for an unknown method, got %s", stdout)
+	}
+}
+
+func TestValidateCommand_GeoRules(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"stores.csv": "id,lat,lon,area\n" +
+			"1,48.85,2.35,\"{\"\"type\"\":\"\"Point\"\",\"\"coordinates\"\":[2.35,48.85]}\"\n" +
+			"2,48.85,200,\n" +
+			"3,,2.35,\"{\"\"type\"\":\"\"Point\"\"}\"\n",
+		".csvlinter.yml": `rules:
+  geo:
+    - latitude: lat
+      longitude: lon
+      geojson: area
+`,
+		"bad.yml": "rules:\n  geo:\n    - latitude: lat\n",
+	})
+
+	stdout, _, code := runApp(t, "validate", "--format", "json", "--config", filepath.Join(dir, ".csvlinter.yml"), filepath.Join(dir, "stores.csv"))
+	if code != 1 {
+		t.Fatalf("want exit 1, got %d: %s", code, stdout)
+	}
+	var res validator.Results
+	if err := json.Unmarshal([]byte(stdout), &res); err != nil {
+		t.Fatalf("invalid json output: %v\nstdout=%s", err, stdout)
+	}
+	var got []string
+	for _, e := range res.Errors {
+		got = append(got, fmt.Sprintf("%d:%s:%s", e.Line, e.Field, e.RuleID))
+	}
+	if want := "3:lon:DAT006 4:lat:DAT006 4:area:DAT006"; strings.Join(got, " ") != want {
+		t.Errorf("want errors %s, got %s", want, strings.Join(got, " "))
+	}
+
+	stdout, _, _ = runApp(t, "validate", "--format", "json", "--config", filepath.Join(dir, "bad.yml"), filepath.Join(dir, "stores.csv"))
+	if !strings.Contains(stdout, `"INVALID_ARGUMENT"`) {
+		t.Errorf("want an INVALID_ARGUMENT error for a latitude without longitude, got %s", stdout)
+	}
+}
+
+func TestValidateCommand_UnitRules(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"parcels.csv": "id,weight\n1,10.5 kg\n2,12 lb\n3,80 kg\n",
+		".csvlinter.yml": `rules:
+  units:
+    - column: weight
+      units: [kg]
+      maximum: 50
+`,
+	})
+
+	stdout, _, code := runApp(t, "validate", "--format", "json", "--config", filepath.Join(dir, ".csvlinter.yml"), filepath.Join(dir, "parcels.csv"))
+	if code != 1 {
+		t.Fatalf("want exit 1, got %d: %s", code, stdout)
+	}
+	var res validator.Results
+	if err := json.Unmarshal([]byte(stdout), &res); err != nil {
+		t.Fatalf("invalid json output: %v\nstdout=%s", err, stdout)
+	}
+	if len(res.Errors) != 2 || res.Errors[0].Line != 3 || res.Errors[1].Line != 4 || res.Errors[1].RuleID != "DAT007" {
+		t.Errorf("want unit errors on lines 3 and 4, got %+v", res.Errors)
+	}
+}
+
+func TestValidateCommand_UniqueRules(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"users.csv": "id,email\n1,ana@example.com\n2,bo@example.com\n3,ana@example.com\n",
+		".csvlinter.yml": `rules:
+  unique:
+    - columns: [email]
+      memory_keys: 1
+`,
+	})
+
+	stdout, _, code := runApp(t, "validate", "--format", "json", "--config", filepath.Join(dir, ".csvlinter.yml"), filepath.Join(dir, "users.csv"))
+	if code != 1 {
+		t.Fatalf("want exit 1, got %d: %s", code, stdout)
+	}
+	var res validator.Results
+	if err := json.Unmarshal([]byte(stdout), &res); err != nil {
+		t.Fatalf("invalid json output: %v\nstdout=%s", err, stdout)
+	}
+	if len(res.Errors) != 1 || res.Errors[0].Line != 4 || res.Errors[0].Message != "email value repeats line 2" || res.Errors[0].RuleID != "DAT010" {
+		t.Errorf("want the spilled email repeated on line 4, got %+v", res.Errors)
+	}
+
+	writeTree(t, dir, map[string]string{".csvlinter.yml": "rules:\n  unique:\n    - columns: [email]\n      method: bloom\n"})
+	stdout, _, code = runApp(t, "validate", "--format", "json", "--no-cache", "--config", filepath.Join(dir, ".csvlinter.yml"), filepath.Join(dir, "users.csv"))
+	res = validator.Results{}
+	if err := json.Unmarshal([]byte(stdout), &res); err != nil || code != 1 || len(res.Errors) != 1 || res.Errors[0].Line != 4 {
+		t.Errorf("want the bloom method to find the email repeated on line 4, got %d: %s", code, stdout)
+	}
+
+	writeTree(t, dir, map[string]string{".csvlinter.yml": "rules:\n  unique:\n    - columns: [email]\n      method: hash\n"})
+	stdout, _, code = runApp(t, "validate", "--format", "json", "--no-cache", "--config", filepath.Join(dir, ".csvlinter.yml"), filepath.Join(dir, "users.csv"))
+	if code != 2 || !strings.Contains(stdout, `"INVALID_ARGUMENT"`) {
+		t.Errorf("want INVALID_ARGUMENT for an unknown method, got %d: %s", code, stdout)
+	}
+}
+
+func TestValidateCommand_PrimaryKey(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"orders.csv":  "order_id,region,qty\n8812,eu,3\n8813,us,x\n",
+		"orders.json": `{"x-primaryKey":["order_id","region"],"properties":{"qty":{"type":"integer"}}}`,
+	})
+
+	stdout, _, code := runApp(t, "validate", "--format", "json", "--schema", filepath.Join(dir, "orders.json"), filepath.Join(dir, "orders.csv"))
+	var res validator.Results
+	if err := json.Unmarshal([]byte(stdout), &res); err != nil || code != 1 {
+		t.Fatalf("want exit 1 with a json report, got %d: %s", code, stdout)
+	}
+	if len(res.Errors) != 1 || res.Errors[0].Key != "(8813, us)" {
+		t.Errorf("want the composite primary key on the qty error, got %+v", res.Errors)
+	}
+
+	stdout, _, _ = runApp(t, "validate", "--format", "pretty", "--schema", filepath.Join(dir, "orders.json"), filepath.Join(dir, "orders.csv"))
+	if !strings.Contains(stdout, "Key: (order_id, region) (8813, us)") {
+		t.Errorf("want the key below the finding, got %s", stdout)
+	}
+
+	// Without x-primaryKey, the first unique rule gives the key
+	writeTree(t, dir, map[string]string{
+		"orders.csv":     "order_id,region,qty\n8812,eu,3\n8813,us,x\n8812,us,4\n",
+		"orders.json":    `{"properties":{"qty":{"type":"integer"}}}`,
+		".csvlinter.yml": "rules:\n  unique:\n    - columns: [order_id]\n",
+	})
+	stdout, _, _ = runApp(t, "validate", "--format", "json", "--no-cache", "--config", filepath.Join(dir, ".csvlinter.yml"), "--schema", filepath.Join(dir, "orders.json"), filepath.Join(dir, "orders.csv"))
+	res = validator.Results{}
+	if err := json.Unmarshal([]byte(stdout), &res); err != nil || len(res.Errors) != 2 {
+		t.Fatalf("want two errors, got %s", stdout)
+	}
+	if res.Errors[0].Key != "8813" || res.Errors[1].Key != "8812" || len(res.KeyColumns) != 1 || res.KeyColumns[0] != "order_id" {
+		t.Errorf("want the unique rule's order_id as the key, got %+v", res)
+	}
+}
+
+func TestValidateCommand_SortedBy(t *testing.T) {
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "events.csv")
+	writeTree(t, dir, map[string]string{
+		"events.csv": "id,timestamp\n1,2024-01-01T00:00:00Z\n2,2024-01-03T00:00:00Z\n3,2024-01-02T00:00:00Z\n",
+	})
+
+	stdout, _, code := runApp(t, "validate", "--format", "json", "--sorted-by", "timestamp", csvPath)
+	if code != 1 {
+		t.Fatalf("want exit 1, got %d", code)
+	}
+	var res validator.Results
+	if err := json.Unmarshal([]byte(stdout), &res); err != nil {
+		t.Fatalf("invalid json output: %v\nstdout=%s", err, stdout)
+	}
+	if len(res.Errors) != 1 || res.Errors[0].RuleID != "DAT002" || res.Errors[0].Line != 4 || res.Errors[0].Column != 2 {
+		t.Errorf("want one ordering error on line 4, got %+v", res.Errors)
+	}
+
+	if _, _, code := runApp(t, "validate", "--sorted-by", "id:asc:unique", csvPath); code != 0 {
+		t.Errorf("want exit 0 for unique ascending ids, got %d", code)
+	}
+
+	stdout, _, code = runApp(t, "validate", "--format", "json", "--sorted-by", "id:backwards", csvPath)
+	if code != 2 || !strings.Contains(stdout, "INVALID_ARGUMENT") {
+		t.Errorf("want INVALID_ARGUMENT for an unknown modifier, got %d: %s", code, stdout)
+	}
+}
+
+func TestValidateCommand_Envelope(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"feed.csv": "id,amount\n1,10\n2,5\nTRAILER,12345\n",
+		".csvlinter.yml": `envelope:
+  trailer: TRAILER
+  count_field: 2
+`,
+	})
+
+	stdout, _, code := runApp(t, "validate", "--format", "json", "--config", filepath.Join(dir, ".csvlinter.yml"), filepath.Join(dir, "feed.csv"))
+	if code != 1 {
+		t.Fatalf("want exit 1, got %d", code)
+	}
+	var res validator.Results
+	if err := json.Unmarshal([]byte(stdout), &res); err != nil {
+		t.Fatalf("invalid json output: %v\nstdout=%s", err, stdout)
+	}
+	if res.TotalRows != 2 || len(res.Errors) != 1 || res.Errors[0].RuleID != "STR003" || res.Errors[0].Message != "trailer declares 12345 rows, found 2" {
+		t.Errorf("want one trailer count error, got %+v", res)
+	}
+}
+
+func TestValidateCommand_Target(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"export.csv":     "id,created\n1,2024-02-13\n2,13/02/2024\n",
+		".csvlinter.yml": "target: bigquery\n",
+	})
+	csvPath := filepath.Join(dir, "export.csv")
+	config := filepath.Join(dir, ".csvlinter.yml")
+
+	stdout, _, code := runApp(t, "validate", "--format", "json", "--config", config, csvPath)
+	if code != 1 {
+		t.Fatalf("want exit 1, got %d", code)
+	}
+	var res validator.Results
+	if err := json.Unmarshal([]byte(stdout), &res); err != nil {
+		t.Fatalf("invalid json output: %v\nstdout=%s", err, stdout)
+	}
+	if len(res.Errors) != 1 || res.Errors[0].RuleID != "LOD004" || res.Errors[0].Type != "load" || res.Errors[0].Line != 3 {
+		t.Errorf("want one date error on line 3, got %+v", res.Errors)
+	}
+
+	// The flag wins over the config; MDY cannot read 13/02/2024 either, but ISO passes
+	if _, _, code := runApp(t, "validate", "--config", config, "--target", "postgres", csvPath); code != 1 {
+		t.Errorf("want exit 1 for postgres, got %d", code)
+	}
+	if stdout, _, code := runApp(t, "validate", "--format", "json", "--target", "oracle", csvPath); code != 2 || !strings.Contains(stdout, "INVALID_ARGUMENT") {
+		t.Errorf("want INVALID_ARGUMENT for an unknown target, got %d %s", code, stdout)
+	}
+}
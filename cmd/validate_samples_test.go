@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+func TestValidateCommand_Samples(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"users.csv":         "id,email,age\n1,a@example.com,30\nx,bad,31\ny,b@example.com,old\nz,c@example.com,33\n",
+		"users.schema.json": `{"type":"object","properties":{"id":{"type":"integer"},"email":{"type":"string","format":"email"},"age":{"type":"integer"}}}`,
+	})
+
+	stdout, _, code := runApp(t, "validate", "--format", "json", "--samples", "2", "--sample-column", "id", "--sample-column", "email", filepath.Join(dir, "users.csv"))
+	if code != 1 {
+		t.Fatalf("want exit 1, got %d", code)
+	}
+	var res validator.Results
+	if err := json.Unmarshal([]byte(stdout), &res); err != nil {
+		t.Fatalf("invalid json output: %v\nstdout=%s", err, stdout)
+	}
+	ids := res.Samples["SCH002"]
+	if len(ids) != 2 || ids[0].LineNumber != 3 || ids[1].LineNumber != 4 || ids[0].Values["email"] != "bad" || ids[0].Values["age"] != "" {
+		t.Errorf("want the first two type failures with id and email only, got %+v", ids)
+	}
+	if emails := res.Samples["SCH001"]; len(emails) != 1 || emails[0].Values["id"] != "x" {
+		t.Errorf("want one email format sample, got %+v", emails)
+	}
+}
+
+func TestValidateCommand_SeedAndRun(t *testing.T) {
+	dir := t.TempDir()
+	var rows strings.Builder
+	rows.WriteString("id\n")
+	for i := 0; i < 100; i++ {
+		rows.WriteString("x\n")
+	}
+	writeTree(t, dir, map[string]string{
+		"ids.csv":         rows.String(),
+		"ids.schema.json": `{"type":"object","properties":{"id":{"type":"integer"}}}`,
+		".csvlinter.yml":  "types:\n  id: int\n",
+	})
+	run := func(args ...string) validator.Results {
+		t.Helper()
+		stdout, _, _ := runApp(t, append([]string{"validate", "--format", "json", "--no-cache", "--config", filepath.Join(dir, ".csvlinter.yml")}, append(args, filepath.Join(dir, "ids.csv"))...)...)
+		var res validator.Results
+		if err := json.Unmarshal([]byte(stdout), &res); err != nil {
+			t.Fatalf("invalid json output: %v\nstdout=%s", err, stdout)
+		}
+		return res
+	}
+
+	res := run("--samples", "2", "--seed", "7")
+	samples := res.Samples["SCH002"]
+	if len(samples) != 2 || samples[0].LineNumber == 2 && samples[1].LineNumber == 3 {
+		t.Errorf("want two samples picked by the seed, got %+v", samples)
+	}
+	again := run("--samples", "2", "--seed", "7").Samples["SCH002"]
+	if len(again) != 2 || again[0].LineNumber != samples[0].LineNumber || again[1].LineNumber != samples[1].LineNumber {
+		t.Errorf("want the same samples from the same seed, got %+v and %+v", samples, again)
+	}
+
+	// The seed and the options merged from the config are recorded
+	var options map[string]any
+	if res.Run == nil || res.Run.Seed != 7 || json.Unmarshal(res.Run.Options, &options) != nil {
+		t.Fatalf("want the seed and options recorded, got %+v", res.Run)
+	}
+	if options["sample_rows"] != float64(2) || options["column_types"] == nil || options["schemas"] == nil {
+		t.Errorf("want the effective options recorded, got %v", options)
+	}
+}
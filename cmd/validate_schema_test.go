@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"testing"
 
 	"github.com/csvlinter/csvlinter/internal/validator"
@@ -158,3 +159,212 @@ func runValidateAndAssertJSON(t *testing.T, csvPath string, wantExit int, assert
 
 	assertFn(res)
 }
+
+func TestValidateCommand_Formats(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"payees.csv":         "name,iban\nAda,DE89370400440532013000\nBob,DE89370400440532013001\n",
+		"payees.schema.json": `{"type":"object","properties":{"iban":{"type":"string","format":"iban"}}}`,
+		"lint.yml":           "formats: [iban]\n",
+		"unknown.yml":        "formats: [isbn-99]\n",
+	})
+	csvPath := filepath.Join(dir, "payees.csv")
+
+	// Without the format enabled, the schema library ignores it
+	stdout, _, code := runApp(t, "validate", "--format", "json", csvPath)
+	if code != 0 {
+		t.Fatalf("want exit 0 without formats enabled, got %d: %s", code, stdout)
+	}
+
+	stdout, _, code = runApp(t, "validate", "--format", "json", "--config", filepath.Join(dir, "lint.yml"), csvPath)
+	if code != 1 {
+		t.Fatalf("want exit 1 for a bad IBAN, got %d: %s", code, stdout)
+	}
+	var res validator.Results
+	if err := json.Unmarshal([]byte(stdout), &res); err != nil {
+		t.Fatalf("invalid json output: %v\nstdout=%s", err, stdout)
+	}
+	if len(res.Errors) != 1 || res.Errors[0].Line != 3 || res.Errors[0].Field != "iban" {
+		t.Errorf("want one iban error on line 3, got %+v", res.Errors)
+	}
+
+	stdout, _, _ = runApp(t, "validate", "--format", "json", "--config", filepath.Join(dir, "unknown.yml"), csvPath)
+	if !bytes.Contains([]byte(stdout), []byte(`"INVALID_ARGUMENT"`)) {
+		t.Errorf("want an INVALID_ARGUMENT error for an unknown format, got %s", stdout)
+	}
+}
+
+func TestValidateCommand_SchemaRefs(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"data/orders.csv":         "id,country\n1,DE\n2,Germany\n",
+		"data/orders.schema.json": `{"type":"object","properties":{"id":{"type":"integer"},"country":{"$ref":"../shared/types.json#/$defs/country"}}}`,
+		"shared/types.json":       `{"$defs":{"country":{"type":"string","pattern":"^[A-Z]{2}$"}}}`,
+	})
+	csvPath := filepath.Join(dir, "data", "orders.csv")
+
+	// The sibling file is found from the schema's directory, not the working directory
+	stdout, _, code := runApp(t, "validate", "--format", "json", "--no-cache", csvPath)
+	var res validator.Results
+	if err := json.Unmarshal([]byte(stdout), &res); err != nil {
+		t.Fatalf("invalid json output: %v\nstdout=%s", err, stdout)
+	}
+	if code != 1 || len(res.Errors) != 1 || res.Errors[0].Field != "country" || res.Errors[0].Line != 3 {
+		t.Errorf("want the referenced pattern to reject line 3, got %d: %+v", code, res.Errors)
+	}
+
+	stdout, _, _ = runApp(t, "validate", "--format", "json", "--no-cache", "--schema-root", dir, csvPath)
+	if !bytes.Contains([]byte(stdout), []byte(`"line_number": 3`)) {
+		t.Errorf("want references inside the root resolved, got %s", stdout)
+	}
+	stdout, _, _ = runApp(t, "validate", "--format", "json", "--no-cache", "--schema-root", filepath.Join(dir, "data"), csvPath)
+	if !bytes.Contains([]byte(stdout), []byte(`"SCHEMA_INVALID"`)) || !bytes.Contains([]byte(stdout), []byte("outside the schema root")) {
+		t.Errorf("want a reference outside the root rejected, got %s", stdout)
+	}
+}
+
+func TestValidateCommand_EmptyValues(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"orders.csv":         "id,qty,note\n1,,\n2,3,hi\n",
+		"orders.schema.json": `{"type":"object","required":["id","qty"],"properties":{"qty":{"type":"integer"},"note":{"type":"string","minLength":1}}}`,
+		"lint.yml":           "empty:\n  default: missing\n  columns:\n    qty: string\n",
+	})
+	csvPath := filepath.Join(dir, "orders.csv")
+	rules := func(args ...string) []string {
+		t.Helper()
+		stdout, _, _ := runApp(t, append([]string{"validate", "--format", "json"}, append(args, csvPath)...)...)
+		var res validator.Results
+		if err := json.Unmarshal([]byte(stdout), &res); err != nil {
+			t.Fatalf("invalid json output: %v\nstdout=%s", err, stdout)
+		}
+		var out []string
+		for _, e := range res.Errors {
+			out = append(out, e.RuleID)
+		}
+		sort.Strings(out)
+		return out
+	}
+
+	// By default the empty qty fails its type and the empty note its length
+	if got := rules(); fmt.Sprint(got) != "[SCH002 SCH007]" {
+		t.Errorf("default: got %v", got)
+	}
+	// Left out, only the required qty fails
+	if got := rules("--empty-values", "missing"); fmt.Sprint(got) != "[SCH003]" {
+		t.Errorf("missing: got %v", got)
+	}
+	// The config leaves out the note but keeps qty as a string
+	if got := rules("--config", filepath.Join(dir, "lint.yml")); fmt.Sprint(got) != "[SCH002]" {
+		t.Errorf("config: got %v", got)
+	}
+	// Quoted, the empty qty is an explicit empty string again
+	writeTree(t, dir, map[string]string{"orders.csv": "id,qty,note\n1,\"\",\n2,3,hi\n"})
+	if got := rules("--empty-values", "missing", "--quoted-empty"); fmt.Sprint(got) != "[SCH002]" {
+		t.Errorf("quoted: got %v", got)
+	}
+	stdout, _, _ := runApp(t, "validate", "--format", "json", "--empty-values", "blank", csvPath)
+	if !bytes.Contains([]byte(stdout), []byte(`"INVALID_ARGUMENT"`)) {
+		t.Errorf("want an INVALID_ARGUMENT error for an unknown mode, got %s", stdout)
+	}
+}
+
+func TestValidateCommand_Types(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"people.csv":         "age,active\n42,true\n-1,no\n",
+		"people.schema.json": `{"type":"object","properties":{"age":{"anyOf":[{"type":"integer","minimum":0},{"type":"null"}]},"active":{"type":"boolean"}}}`,
+		"lint.yml":           "types:\n  age: int\n",
+	})
+	csvPath := filepath.Join(dir, "people.csv")
+	lines := func(args ...string) string {
+		t.Helper()
+		stdout, _, _ := runApp(t, append([]string{"validate", "--format", "json", "--no-cache"}, append(args, csvPath)...)...)
+		var res validator.Results
+		if err := json.Unmarshal([]byte(stdout), &res); err != nil {
+			t.Fatalf("invalid json output: %v\nstdout=%s", err, stdout)
+		}
+		seen := map[string]bool{}
+		var out []string
+		for _, e := range res.Errors {
+			if key := fmt.Sprintf("%d:%s", e.Line, e.Field); !seen[key] {
+				seen[key] = true
+				out = append(out, key)
+			}
+		}
+		sort.Strings(out)
+		return fmt.Sprint(out)
+	}
+
+	// age converts through anyOf; booleans only convert with types
+	if got := lines(); got != "[2:active 3:active 3:age]" {
+		t.Errorf("schema types: got %s", got)
+	}
+	// --types adds active to the config's types
+	if got := lines("--config", filepath.Join(dir, "lint.yml"), "--types", "active:bool"); got != "[3:active 3:age]" {
+		t.Errorf("with types: got %s", got)
+	}
+	stdout, _, _ := runApp(t, "validate", "--format", "json", "--types", "age:date", csvPath)
+	if !bytes.Contains([]byte(stdout), []byte(`"INVALID_ARGUMENT"`)) {
+		t.Errorf("want an INVALID_ARGUMENT error for an unknown type, got %s", stdout)
+	}
+	stdout, _, _ = runApp(t, "validate", "--format", "json", "--types", "age", csvPath)
+	if !bytes.Contains([]byte(stdout), []byte(`"INVALID_ARGUMENT"`)) {
+		t.Errorf("want an INVALID_ARGUMENT error without a type, got %s", stdout)
+	}
+}
+
+func TestValidateCommand_StdinName(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		".git/HEAD":               "",
+		"data/.csvlinter.yml":     "ragged:\n  short: warn\n",
+		"data/orders.schema.json": `{"type":"object","properties":{"id":{"type":"integer"}}}`,
+	})
+	name := filepath.Join(dir, "data", "orders.tsv")
+	withStdin(t, "id\tqty\nx\t1\n2\n")
+
+	stdout, _, code := runApp(t, "validate", "--format", "json", "--stdin-name", name, "-")
+	var res validator.Results
+	if err := json.Unmarshal([]byte(stdout), &res); err != nil {
+		t.Fatalf("invalid json output: %v\nstdout=%s", err, stdout)
+	}
+	// Tab-separated like a .tsv file, checked by the schema and config next to it
+	if code != 1 || res.File != name || !res.SchemaUsed || len(res.Errors) != 1 || res.Errors[0].Field != "id" || len(res.Warnings) != 1 {
+		t.Errorf("want the file reported and validated as %s, got %d: %+v", name, code, res)
+	}
+}
+
+func TestValidateCommand_StdinSchemaFromCwd(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"package.json":          "{}",
+		"csvlinter.schema.json": `{"type":"object","properties":{"id":{"type":"integer"}}}`,
+		"exports/README":        "",
+	})
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(filepath.Join(dir, "exports")); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	run := func(args ...string) (validator.Results, int) {
+		t.Helper()
+		withStdin(t, "id\nx\n")
+		stdout, _, code := runApp(t, append(append([]string{"validate", "--format", "json"}, args...), "-")...)
+		var res validator.Results
+		if err := json.Unmarshal([]byte(stdout), &res); err != nil {
+			t.Fatalf("invalid json output: %v\nstdout=%s", err, stdout)
+		}
+		return res, code
+	}
+	if res, code := run(); code != 0 || res.SchemaUsed {
+		t.Errorf("want no schema for STDIN by default, got %d: %+v", code, res)
+	}
+	if res, code := run("--stdin-schema-from-cwd"); code != 1 || !res.SchemaUsed || len(res.Errors) != 1 {
+		t.Errorf("want the project schema found from the working directory, got %d: %+v", code, res)
+	}
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+func TestValidateCommand_SepLine(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"users.csv":         "sep=;\r\nid;name\r\n1;a\r\nx;b\r\n",
+		"users.schema.json": `{"type":"object","properties":{"id":{"type":"integer"}}}`,
+	})
+	file := filepath.Join(dir, "users.csv")
+
+	run := func(args ...string) (validator.Results, int) {
+		t.Helper()
+		stdout, _, code := runApp(t, append(append([]string{"validate", "--format", "json", "--no-cache"}, args...), file)...)
+		var results validator.Results
+		if err := json.Unmarshal([]byte(stdout), &results); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+		}
+		return results, code
+	}
+
+	// The sep= line sets the delimiter and is not data; the header is line 2
+	results, code := run()
+	if code != 1 || results.TotalRows != 2 || len(results.Errors) != 1 || results.Errors[0].Line != 4 || len(results.Warnings) != 0 {
+		t.Fatalf("want one error on line 4, got %d: %+v", code, results)
+	}
+
+	results, _ = run("--sep-line", "warn")
+	if len(results.Warnings) != 1 || results.Warnings[0].RuleID != "STR004" || results.Warnings[0].Line != 1 {
+		t.Errorf("want an STR004 warning on line 1, got %+v", results.Warnings)
+	}
+
+	results, _ = run("--sep-line", "error")
+	if len(results.Errors) != 2 || results.Errors[0].RuleID != "STR004" {
+		t.Errorf("want an STR004 error, got %+v", results.Errors)
+	}
+
+	// An explicit delimiter wins, but the line is still skipped
+	results, _ = run("--delimiter", ",")
+	if len(results.Errors) != 0 || results.TotalRows != 2 {
+		t.Errorf("want the whole row as one column with --delimiter, got %+v", results)
+	}
+
+	stdout, _, code := runApp(t, "validate", "--format", "json", "--sep-line", "strict", file)
+	if code != 2 || !strings.Contains(stdout, `"INVALID_ARGUMENT"`) {
+		t.Errorf("want INVALID_ARGUMENT for an unknown mode, got %d: %s", code, stdout)
+	}
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+func TestValidateCommand_Summary(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"good.csv":          "id,age\n1,30\n",
+		"users.csv":         "id,age\nx,30\ny,31\n",
+		"users.schema.json": `{"type":"object","properties":{"id":{"type":"integer"},"age":{"type":"integer"}}}`,
+	})
+	usersPath := filepath.Join(dir, "users.csv")
+
+	_, stderr, code := runApp(t, "validate", "--format", "json", "--no-cache", usersPath)
+	if code != 1 || !regexp.MustCompile(`^invalid: 2 errors, 0 warnings in \S+\n$`).MatchString(stderr) {
+		t.Errorf("want a one-line summary on stderr, got %d: %q", code, stderr)
+	}
+	_, stderr, _ = runApp(t, "validate", "--format", "json", "--no-cache", "--dedupe-errors", "--quiet", usersPath)
+	if stderr != "" {
+		t.Errorf("want nothing on stderr with --quiet, got %q", stderr)
+	}
+	_, stderr, code = runApp(t, "validate", "--format", "compact", "--no-cache", filepath.Join(dir, "good.csv"), usersPath)
+	if code != 1 || !regexp.MustCompile(`^invalid: 1 of 2 files, 2 errors, 0 warnings in \S+\n$`).MatchString(stderr) {
+		t.Errorf("want a summary of the batch, got %d: %q", code, stderr)
+	}
+}
@@ -108,15 +108,33 @@ invalid,Jane Doe,jane.doe@example.com`)
 		{
 			name:         "Non-existent CSV file",
 			args:         []string{"non-existent.csv"},
-			expectedExit: 1,
+			expectedExit: 4,
 			expectError:  true,
 		},
 		{
 			name:         "Non-existent schema file",
 			args:         []string{"--schema", "non-existent.json", validCSVPath},
-			expectedExit: 1,
+			expectedExit: 3,
 			expectError:  true,
 		},
+		{
+			name:         "JSON to output file with pretty on stdout",
+			args:         []string{"--schema", schemaPath, "--format", "json", "--format", "pretty", "--output", filepath.Join(tempDir, "report.json"), validCSVPath},
+			expectedExit: 0,
+			assertOutput: func(t *testing.T, output string) {
+				if !strings.Contains(output, "CSV Validation Results") {
+					t.Errorf("Expected pretty output on stdout, got: %s", output)
+				}
+				content, err := os.ReadFile(filepath.Join(tempDir, "report.json"))
+				if err != nil {
+					t.Fatalf("Expected report file: %v", err)
+				}
+				var results validator.Results
+				if err := json.Unmarshal(content, &results); err != nil {
+					t.Fatalf("Expected JSON report file: %v", err)
+				}
+			},
+		},
 		{
 			name:         "STDIN input with JSON output",
 			args:         []string{"--format", "json", "-"},
@@ -152,7 +170,7 @@ invalid,Jane Doe,jane.doe@example.com`)
 		{
 			name:         "Non-existent CSV file with JSON format emits valid JSON",
 			args:         []string{"--format", "json", "no-such-file.csv"},
-			expectedExit: 1,
+			expectedExit: 4,
 			assertOutput: func(t *testing.T, output string) {
 				output = strings.TrimSpace(output)
 				if output == "" {
@@ -167,7 +185,7 @@ invalid,Jane Doe,jane.doe@example.com`)
 		{
 			name:         "Non-existent schema with JSON format emits valid JSON",
 			args:         []string{"--format", "json", "--schema", "no-such-schema.json", validCSVPath},
-			expectedExit: 1,
+			expectedExit: 3,
 			assertOutput: func(t *testing.T, output string) {
 				output = strings.TrimSpace(output)
 				if output == "" {
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateCommand_Theme(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"users.csv":         "id\nx\n",
+		"users.schema.json": `{"type":"object","properties":{"id":{"type":"integer"}}}`,
+		"lint.yml":          "theme:\n  name: emoji-free\n  fail: FAIL\n",
+	})
+
+	stdout, _, code := runApp(t, "validate", "--config", filepath.Join(dir, "lint.yml"), filepath.Join(dir, "users.csv"))
+	if code != 1 || !strings.Contains(stdout, "Status: FAIL INVALID\n") || strings.ContainsAny(stdout, "✓✗") {
+		t.Errorf("want the config's ASCII theme, got %d: %s", code, stdout)
+	}
+
+	stdout, _, _ = runApp(t, "validate", "--theme", "minimal", filepath.Join(dir, "users.csv"))
+	if !strings.Contains(stdout, "Status: INVALID\n") {
+		t.Errorf("want no status symbol with the minimal theme, got %s", stdout)
+	}
+
+	writeTree(t, dir, map[string]string{"lint.yml": "theme:\n  error: purple\n"})
+	stdout, _, code = runApp(t, "validate", "--format", "json", "--config", filepath.Join(dir, "lint.yml"), filepath.Join(dir, "users.csv"))
+	if code != 2 || !strings.Contains(stdout, `"INVALID_ARGUMENT"`) {
+		t.Errorf("want INVALID_ARGUMENT for an unknown color, got %d: %s", code, stdout)
+	}
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+func TestValidateCommand_Timings(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"a.csv": "id\n1\n", "b.csv": "id\n2\n"})
+
+	stdout, stderr, code := runApp(t, "validate", "--format", "json", "--no-cache", "--timings", filepath.Join(dir, "a.csv"))
+	var results validator.Results
+	if err := json.Unmarshal([]byte(stdout), &results); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+	}
+	if code != 0 || results.Timings == nil || !strings.Contains(stderr, "Timings for "+filepath.Join(dir, "a.csv")+": read ") ||
+		!strings.Contains(stderr, ", report ") {
+		t.Errorf("want timings in the report and on stderr, got %d: %s\n%s", code, stdout, stderr)
+	}
+
+	_, stderr, _ = runApp(t, "validate", "--no-cache", "--timings", filepath.Join(dir, "a.csv"), filepath.Join(dir, "b.csv"))
+	if strings.Count(stderr, "Timings for ") != 3 || !strings.Contains(stderr, "Timings for all files: ") {
+		t.Errorf("want a line per file and a total, got %s", stderr)
+	}
+
+	if _, stderr, _ = runApp(t, "validate", "--no-cache", filepath.Join(dir, "a.csv")); strings.Contains(stderr, "Timings") {
+		t.Errorf("want no timings unless requested, got %s", stderr)
+	}
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+func TestValidateCommand_MaxValueLength(t *testing.T) {
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "notes.csv")
+	note := strings.Repeat("x", 300)
+	if err := os.WriteFile(csvPath, []byte("id,note\n1,"+note+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	schema := `{"type":"object","properties":{"note":{"type":"string","maxLength":10}}}`
+	if err := os.WriteFile(filepath.Join(dir, "notes.schema.json"), []byte(schema), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	noteError := func(args ...string) validator.Finding {
+		t.Helper()
+		stdout, _, _ := runApp(t, append([]string{"validate", "--format", "json"}, append(args, csvPath)...)...)
+		var res validator.Results
+		if err := json.Unmarshal([]byte(stdout), &res); err != nil {
+			t.Fatalf("invalid json output: %v\nstdout=%s", err, stdout)
+		}
+		if len(res.Errors) != 1 {
+			t.Fatalf("want 1 error, got %v", res.Errors)
+		}
+		return res.Errors[0]
+	}
+
+	if e := noteError(); len([]rune(e.Value)) != 257 || !e.ValueTruncated {
+		t.Errorf("want the default limit of 256 characters plus an ellipsis, got %d (truncated=%t)", len([]rune(e.Value)), e.ValueTruncated)
+	}
+	if e := noteError("--max-value-length", "5"); e.Value != "xxxxx…" || !e.ValueTruncated {
+		t.Errorf("want value cut to 5 characters, got %+v", e)
+	}
+	if e := noteError("--max-value-length", "0"); e.Value != note || e.ValueTruncated {
+		t.Errorf("want the full value with no limit, got %d characters", len(e.Value))
+	}
+	stdout, _, _ := runApp(t, "validate", "--format", "json", "--max-value-length", "-1", csvPath)
+	if !strings.Contains(stdout, `"INVALID_ARGUMENT"`) {
+		t.Errorf("want INVALID_ARGUMENT for a negative limit, got %s", stdout)
+	}
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+
+	"github.com/csvlinter/csvlinter/internal/formats"
+	"github.com/csvlinter/csvlinter/internal/reporter"
+	"github.com/csvlinter/csvlinter/internal/schema"
+
+	"github.com/urfave/cli/v2"
+)
+
+var versionCommand = &cli.Command{
+	Name:  "version",
+	Usage: "Print the version, build metadata and supported schema drafts and formats",
+	Description: "Include the output in bug reports, or pin a CI image's capabilities with --format json: " +
+		"the version, commit and build date, Go version, JSON Schema drafts, report formats and schema formats.",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    "format",
+			Aliases: []string{"f"},
+			Value:   "pretty",
+			Usage:   "Output format (pretty or json)",
+		},
+	},
+	Action: versionAction,
+}
+
+// buildInfo is the version command's JSON output.
+type buildInfo struct {
+	Version             string   `json:"version"`
+	Commit              string   `json:"commit,omitempty"`
+	Modified            bool     `json:"modified,omitempty"` // Built from a tree with uncommitted changes
+	BuildDate           string   `json:"build_date,omitempty"`
+	GoVersion           string   `json:"go_version"`
+	Platform            string   `json:"platform"`
+	SchemaDrafts        []string `json:"schema_drafts"`
+	DefaultSchemaDraft  string   `json:"default_schema_draft"`
+	ReportFormats       []string `json:"report_formats"`
+	ReportSchemaVersion string   `json:"report_schema_version"`
+	Formats             []string `json:"formats"` // Registered formats a config can enable for schemas' "format" keyword
+}
+
+// currentBuild collects the build metadata: the values set with -ldflags, or else the
+// version control information Go stamps into binaries built from a checkout.
+func currentBuild() buildInfo {
+	info := buildInfo{
+		Version:             Version,
+		Commit:              Commit,
+		BuildDate:           BuildDate,
+		GoVersion:           runtime.Version(),
+		Platform:            runtime.GOOS + "/" + runtime.GOARCH,
+		SchemaDrafts:        schema.Drafts,
+		DefaultSchemaDraft:  schema.DefaultDraft,
+		ReportFormats:       reporter.Formats,
+		ReportSchemaVersion: reporter.SchemaVersion,
+		Formats:             formats.Names(),
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok && info.Commit == "" {
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				info.Commit = s.Value
+			case "vcs.time":
+				if info.BuildDate == "" {
+					info.BuildDate = s.Value
+				}
+			case "vcs.modified":
+				info.Modified = s.Value == "true"
+			}
+		}
+	}
+	return info
+}
+
+// versionString is the short version of --version: the version and, when known, the
+// abbreviated commit.
+func versionString() string {
+	info := currentBuild()
+	if info.Commit == "" {
+		return info.Version
+	}
+	return fmt.Sprintf("%s (commit %s)", info.Version, info.Commit[:min(len(info.Commit), 12)])
+}
+
+func versionAction(c *cli.Context) error {
+	info := currentBuild()
+	switch c.String("format") {
+	case "json":
+		out, err := json.MarshalIndent(info, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(c.App.Writer, "%s\n", out)
+		return err
+	case "pretty":
+		_, err := fmt.Fprint(c.App.Writer, prettyBuild(info))
+		return err
+	}
+	return fmt.Errorf("format must be 'pretty' or 'json', got '%s'", c.String("format"))
+}
+
+// prettyBuild renders the build metadata for bug reports.
+func prettyBuild(info buildInfo) string {
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("csvlinter %s\n", info.Version))
+	commit := info.Commit
+	switch {
+	case commit == "":
+		commit = "unknown"
+	case info.Modified:
+		commit += " (modified)"
+	}
+	sb.WriteString(fmt.Sprintf("Commit:         %s\n", commit))
+	date := info.BuildDate
+	if date == "" {
+		date = "unknown"
+	}
+	sb.WriteString(fmt.Sprintf("Build date:     %s\n", date))
+	sb.WriteString(fmt.Sprintf("Go version:     %s\n", info.GoVersion))
+	sb.WriteString(fmt.Sprintf("Platform:       %s\n", info.Platform))
+	sb.WriteString(fmt.Sprintf("Schema drafts:  %s (default %s)\n", strings.Join(info.SchemaDrafts, ", "), info.DefaultSchemaDraft))
+	sb.WriteString(fmt.Sprintf("Report formats: %s (report schema %s)\n", strings.Join(info.ReportFormats, ", "), info.ReportSchemaVersion))
+	sb.WriteString(fmt.Sprintf("Formats:        %s\n", strings.Join(info.Formats, ", ")))
+	return sb.String()
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"encoding/json"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestVersionCommand(t *testing.T) {
+	stdout, _, code := runApp(t, "version", "--format", "json")
+	if code != 0 {
+		t.Fatalf("want exit 0, got %d: %s", code, stdout)
+	}
+	var info buildInfo
+	if err := json.Unmarshal([]byte(stdout), &info); err != nil {
+		t.Fatalf("invalid json output: %v\nstdout=%s", err, stdout)
+	}
+	if info.Version != Version || info.GoVersion != runtime.Version() || info.DefaultSchemaDraft != "2020-12" ||
+		!slices.Contains(info.SchemaDrafts, "draft-07") || !slices.Contains(info.ReportFormats, "compact") || !slices.Contains(info.Formats, "iban") {
+		t.Errorf("want the build's capabilities, got %+v", info)
+	}
+
+	stdout, _, code = runApp(t, "version")
+	if code != 0 || !strings.HasPrefix(stdout, "csvlinter "+Version+"\n") || !strings.Contains(stdout, "Schema drafts:  draft-04, ") {
+		t.Errorf("want the pretty version, got %d: %s", code, stdout)
+	}
+
+	if _, _, code = runApp(t, "version", "--format", "xml"); code == 0 {
+		t.Error("want an unknown format to fail")
+	}
+}
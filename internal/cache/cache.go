@@ -0,0 +1,134 @@
+// Package cache stores validation results keyed by the content they were computed from,
+// so unchanged files can skip validation on repeated runs.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+// EnvDir overrides the default cache directory.
+const EnvDir = "CSVLINTER_CACHE_DIR"
+
+// version is mixed into every key; bump it when cached results change shape or meaning.
+const version = "8"
+
+// DefaultDir returns $CSVLINTER_CACHE_DIR, or csvlinter under the user cache directory
+// (~/.cache/csvlinter on Linux).
+func DefaultDir() (string, error) {
+	if dir := os.Getenv(EnvDir); dir != "" {
+		return dir, nil
+	}
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("no cache directory: %w", err)
+	}
+	return filepath.Join(base, "csvlinter"), nil
+}
+
+// Cache is a directory of results, one JSON file per key.
+type Cache struct {
+	dir string
+}
+
+// New returns a cache rooted at dir. The directory is created on first Put.
+func New(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+// Dir returns the cache directory.
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// Key hashes the input content together with the schema hash and the options that
+// affect results (any JSON-encodable value). The reader is consumed.
+func Key(content io.Reader, schemaHash string, options any) (string, error) {
+	opts, err := json.Marshal(options)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "v%s\x00%s\x00%s\x00", version, schemaHash, opts)
+	if _, err := io.Copy(h, content); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Get returns the results stored under key. Unreadable or corrupt entries count as misses.
+func (c *Cache) Get(key string) (*validator.Results, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	var results validator.Results
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, false
+	}
+	return &results, true
+}
+
+// Put stores results under key, replacing any previous entry atomically.
+func (c *Cache) Put(key string, results *validator.Results) error {
+	data, err := json.Marshal(results)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(c.dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path(key)); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	return nil
+}
+
+// Clean removes every cached entry and returns how many were removed. A missing cache
+// directory is not an error.
+func (c *Cache) Clean() (int, error) {
+	entries, err := os.ReadDir(c.dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+	removed := 0
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		if err := os.Remove(filepath.Join(c.dir, e.Name())); err != nil {
+			return removed, fmt.Errorf("failed to remove cache entry: %w", err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+func TestKey(t *testing.T) {
+	key := func(content, schemaHash string, opts any) string {
+		t.Helper()
+		k, err := Key(strings.NewReader(content), schemaHash, opts)
+		if err != nil {
+			t.Fatalf("Key failed: %v", err)
+		}
+		return k
+	}
+	base := key("id\n1\n", "abc", map[string]string{"delimiter": ","})
+	if base != key("id\n1\n", "abc", map[string]string{"delimiter": ","}) {
+		t.Error("Expected identical inputs to give identical keys")
+	}
+	for name, k := range map[string]string{
+		"content": key("id\n2\n", "abc", map[string]string{"delimiter": ","}),
+		"schema":  key("id\n1\n", "def", map[string]string{"delimiter": ","}),
+		"options": key("id\n1\n", "abc", map[string]string{"delimiter": ";"}),
+	} {
+		if k == base {
+			t.Errorf("Expected a different key when %s changes", name)
+		}
+	}
+}
+
+func TestCache(t *testing.T) {
+	c := New(t.TempDir())
+	if _, ok := c.Get("missing"); ok {
+		t.Fatal("Expected a miss on an empty cache")
+	}
+
+	results := &validator.Results{
+		File:      "data.csv",
+		TotalRows: 2,
+		Errors:    []validator.Finding{{Severity: validator.SeverityError, Location: validator.Location{Line: 2}, Message: "bad", Type: "structure"}},
+		Warnings:  []validator.Finding{},
+		Duration:  "1ms",
+	}
+	if err := c.Put("k1", results); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	got, ok := c.Get("k1")
+	if !ok {
+		t.Fatal("Expected a hit after Put")
+	}
+	if got.File != "data.csv" || got.TotalRows != 2 || len(got.Errors) != 1 {
+		t.Errorf("Unexpected cached results: %+v", got)
+	}
+
+	if err := c.Put("k2", results); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	removed, err := c.Clean()
+	if err != nil {
+		t.Fatalf("Clean failed: %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("Expected 2 entries removed, got %d", removed)
+	}
+	if _, ok := c.Get("k1"); ok {
+		t.Error("Expected a miss after Clean")
+	}
+}
+
+func TestCleanMissingDir(t *testing.T) {
+	removed, err := New(t.TempDir() + "/nope").Clean()
+	if err != nil || removed != 0 {
+		t.Errorf("Expected nothing to clean, got %d, %v", removed, err)
+	}
+}
@@ -0,0 +1,228 @@
+// Package charset decodes text in common source encodings to UTF-8, so files exported by
+// tools that do not write UTF-8 can be repaired.
+package charset
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Encodings.
+const (
+	UTF8        = "utf-8"
+	UTF16LE     = "utf-16le"
+	UTF16BE     = "utf-16be"
+	Latin1      = "latin1"
+	Windows1252 = "windows-1252"
+)
+
+// Auto detects the encoding from the start of the input.
+const Auto = "auto"
+
+// aliases maps accepted spellings to encodings.
+var aliases = map[string]string{
+	"utf-8": UTF8, "utf8": UTF8,
+	"utf-16le": UTF16LE, "utf16le": UTF16LE,
+	"utf-16be": UTF16BE, "utf16be": UTF16BE,
+	"latin1": Latin1, "latin-1": Latin1, "iso-8859-1": Latin1,
+	"windows-1252": Windows1252, "cp1252": Windows1252,
+}
+
+// boms are the byte order marks of the encodings that have one.
+var boms = map[string][]byte{
+	UTF8:    {0xef, 0xbb, 0xbf},
+	UTF16LE: {0xff, 0xfe},
+	UTF16BE: {0xfe, 0xff},
+}
+
+// sampleSize is how much of the input Detect looks at.
+const sampleSize = 64 * 1024
+
+// Lookup returns the encoding named by name, Auto for "" and "auto".
+func Lookup(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || name == Auto {
+		return Auto, nil
+	}
+	if enc, ok := aliases[name]; ok {
+		return enc, nil
+	}
+	return "", fmt.Errorf("unknown encoding '%s' (use auto, %s, %s, %s, %s or %s)", name, UTF8, UTF16LE, UTF16BE, Latin1, Windows1252)
+}
+
+// Detect guesses the encoding of a sample from the start of the input: from a byte order
+// mark, from NUL bytes in every other position for UTF-16, and otherwise UTF-8 unless
+// high bytes never form a UTF-8 sequence, which is what single-byte encodings look like.
+func Detect(sample []byte) string {
+	for _, enc := range []string{UTF8, UTF16LE, UTF16BE} {
+		if bytes.HasPrefix(sample, boms[enc]) {
+			return enc
+		}
+	}
+	var even, odd int
+	for i, b := range sample {
+		if b == 0 && i%2 == 0 {
+			even++
+		} else if b == 0 {
+			odd++
+		}
+	}
+	switch pairs := len(sample) / 2; {
+	case pairs > 0 && odd > pairs/4 && even == 0:
+		return UTF16LE
+	case pairs > 0 && even > pairs/4 && odd == 0:
+		return UTF16BE
+	}
+	multibyte, invalid := 0, 0
+	for i := 0; i < len(sample); {
+		r, size := utf8.DecodeRune(sample[i:])
+		switch {
+		case r == utf8.RuneError && size == 1 && !utf8.FullRune(sample[i:]):
+			// Cut off by the end of the sample
+			size = len(sample) - i
+		case r == utf8.RuneError && size == 1:
+			invalid++
+		case size > 1:
+			multibyte++
+		}
+		i += size
+	}
+	if invalid > 0 && multibyte == 0 {
+		return Windows1252
+	}
+	return UTF8
+}
+
+// Decoder reads text in a source encoding as UTF-8. Bytes that cannot be decoded are
+// replaced by U+FFFD, and a leading byte order mark is dropped.
+type Decoder struct {
+	r        *bufio.Reader
+	encoding string
+	pending  []byte
+	replaced int
+	err      error
+}
+
+// NewDecoder returns a decoder of r from encoding, which Lookup must accept; Auto detects
+// it from the start of r.
+func NewDecoder(r io.Reader, encoding string) (*Decoder, error) {
+	encoding, err := Lookup(encoding)
+	if err != nil {
+		return nil, err
+	}
+	br := bufio.NewReaderSize(r, sampleSize)
+	if encoding == Auto {
+		// A short input is all there is to look at; read errors surface on Read
+		sample, _ := br.Peek(sampleSize)
+		encoding = Detect(sample)
+	}
+	if bom := boms[encoding]; bom != nil {
+		if start, _ := br.Peek(len(bom)); bytes.Equal(start, bom) {
+			br.Discard(len(bom))
+		}
+	}
+	return &Decoder{r: br, encoding: encoding}, nil
+}
+
+// Encoding returns the source encoding, as detected when the decoder was created with Auto.
+func (d *Decoder) Encoding() string {
+	return d.encoding
+}
+
+// Replacements returns how many U+FFFD replaced undecodable bytes so far.
+func (d *Decoder) Replacements() int {
+	return d.replaced
+}
+
+func (d *Decoder) Read(p []byte) (int, error) {
+	for len(d.pending) < len(p) && d.err == nil {
+		r, ok, err := d.next()
+		if err != nil {
+			d.err = err
+			break
+		}
+		if !ok {
+			d.replaced++
+			r = utf8.RuneError
+		}
+		d.pending = utf8.AppendRune(d.pending, r)
+	}
+	n := copy(p, d.pending)
+	d.pending = d.pending[n:]
+	if n == 0 && d.err != nil {
+		return 0, d.err
+	}
+	return n, nil
+}
+
+// next decodes one character, reporting false for undecodable bytes.
+func (d *Decoder) next() (rune, bool, error) {
+	switch d.encoding {
+	case UTF16LE, UTF16BE:
+		u, ok, err := d.unit()
+		if err != nil || !ok {
+			return 0, ok, err
+		}
+		if u < 0xd800 || u > 0xdfff {
+			return rune(u), true, nil
+		}
+		if u > 0xdbff {
+			// Low surrogate without a high one
+			return 0, false, nil
+		}
+		if next, err := d.r.Peek(2); err != nil || d.order(next) < 0xdc00 || d.order(next) > 0xdfff {
+			// High surrogate without a low one; what follows is decoded on its own
+			return 0, false, nil
+		}
+		low, _, _ := d.unit()
+		return 0x10000 + (rune(u)-0xd800)<<10 + rune(low) - 0xdc00, true, nil
+	case Latin1, Windows1252:
+		b, err := d.r.ReadByte()
+		if err != nil {
+			return 0, false, err
+		}
+		if d.encoding == Windows1252 && b >= 0x80 && b < 0xa0 {
+			r := windows1252[b-0x80]
+			return r, r != 0, nil
+		}
+		return rune(b), true, nil
+	default:
+		r, size, err := d.r.ReadRune()
+		if err != nil {
+			return 0, false, err
+		}
+		return r, r != utf8.RuneError || size > 1, nil
+	}
+}
+
+// unit reads a UTF-16 code unit; a lone last byte is undecodable.
+func (d *Decoder) unit() (uint16, bool, error) {
+	var b [2]byte
+	n, err := io.ReadFull(d.r, b[:])
+	if err == io.ErrUnexpectedEOF {
+		return 0, false, nil
+	}
+	if err != nil || n < 2 {
+		return 0, false, err
+	}
+	return d.order(b[:]), true, nil
+}
+
+// order reads a code unit from two bytes in the decoder's byte order.
+func (d *Decoder) order(b []byte) uint16 {
+	if d.encoding == UTF16BE {
+		return uint16(b[0])<<8 | uint16(b[1])
+	}
+	return uint16(b[1])<<8 | uint16(b[0])
+}
+
+// windows1252 maps the bytes 0x80 to 0x9f, where windows-1252 differs from Latin-1; 0
+// marks the five undefined bytes.
+var windows1252 = [32]rune{
+	'€', 0, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0, 'Ž', 0,
+	0, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0, 'ž', 'Ÿ',
+}
@@ -0,0 +1,60 @@
+package charset
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestDecoder(t *testing.T) {
+	tests := []struct {
+		name, encoding, input, want string
+		replaced                    int
+	}{
+		{"utf-8 repaired", UTF8, "caf\xc3\xa9,\xff\xfe,\xef\xbf\xbd\n", "café,��,�\n", 2},
+		{"utf-8 bom dropped", Auto, "\xef\xbb\xbfa,b\n", "a,b\n", 0},
+		{"latin1", Latin1, "caf\xe9,\x80\n", "café,\u0080\n", 0},
+		{"windows-1252", Windows1252, "\x80 5,\x93q\x94,\x81\n", "€ 5,“q”,�\n", 1},
+		{"utf-16le", Auto, "\xff\xfea\x00,\x00\xe9\x00=\xd8\x00\xde\n\x00", "a,é😀\n", 0},
+		{"utf-16be lone surrogate", UTF16BE, "\x00a\xdc\x00\x00b\x00", "a�b�", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := NewDecoder(iotest.OneByteReader(strings.NewReader(tt.input)), tt.encoding)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := io.ReadAll(d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want || d.Replacements() != tt.replaced {
+				t.Errorf("got %q with %d replacements, want %q with %d", got, d.Replacements(), tt.want, tt.replaced)
+			}
+		})
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := map[string]string{
+		"a,b\n":                 UTF8,
+		"caf\xc3\xa9\n":         UTF8,
+		"caf\xc3\xa9,\xff\n":    UTF8,
+		"caf\xe9,na\xefve\n":    Windows1252,
+		"ab\xc3":                UTF8,
+		"\xfe\xff\x00a":         UTF16BE,
+		"a\x00,\x00b\x00\n\x00": UTF16LE,
+	}
+	for input, want := range tests {
+		if got := Detect([]byte(input)); got != want {
+			t.Errorf("%q: got %s, want %s", input, got, want)
+		}
+	}
+	if _, err := Lookup("ebcdic"); err == nil {
+		t.Error("want an unknown encoding rejected")
+	}
+	if enc, _ := Lookup("CP1252"); enc != Windows1252 {
+		t.Errorf("want cp1252 as an alias, got %s", enc)
+	}
+}
@@ -0,0 +1,124 @@
+package checks
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+
+	"github.com/csvlinter/csvlinter/internal/rules"
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+// DefaultBloomKeys is how many keys a unique check's Bloom filter is sized for by default,
+// about 12 MB of memory; set the expected keys for larger inputs.
+const DefaultBloomKeys = 10_000_000
+
+// bloomFalsePositives is the share of new keys the filter mistakes for seen ones when it
+// holds the expected number of keys.
+const bloomFalsePositives = 0.01
+
+// bloomFilter is a set of 128-bit hashes that may report keys it never saw, but never
+// misses one it did.
+type bloomFilter struct {
+	bits   []uint64
+	size   uint64 // Number of bits
+	hashes int
+}
+
+// newBloomFilter returns a filter sized for n keys at bloomFalsePositives.
+func newBloomFilter(n int) *bloomFilter {
+	size := uint64(math.Ceil(-float64(n) * math.Log(bloomFalsePositives) / (math.Ln2 * math.Ln2)))
+	size = max(size, 64)
+	hashes := max(1, int(math.Round(float64(size)/float64(n)*math.Ln2)))
+	return &bloomFilter{bits: make([]uint64, (size+63)/64), size: size, hashes: hashes}
+}
+
+// add adds key and reports whether it may have been added before.
+func (b *bloomFilter) add(key [16]byte) bool {
+	// Double hashing: the two halves of the key give every probe position
+	h1, h2 := binary.BigEndian.Uint64(key[:8]), binary.BigEndian.Uint64(key[8:])
+	seen := true
+	for i := 0; i < b.hashes; i++ {
+		bit := (h1 + uint64(i)*h2) % b.size
+		word, mask := bit/64, uint64(1)<<(bit%64)
+		if b.bits[word]&mask == 0 {
+			seen = false
+			b.bits[word] |= mask
+		}
+	}
+	return seen
+}
+
+// startBloom sets up Bloom filter mode, when configured, for a new input.
+func (u *Unique) startBloom() []validator.Finding {
+	if u.expected == 0 {
+		return nil
+	}
+	u.bloom = newBloomFilter(u.expected)
+	u.candidates = make(map[[16]byte]bool)
+	f, err := os.CreateTemp(u.dir, "csvlinter-unique-*")
+	if err != nil {
+		u.indexes = nil
+		return []validator.Finding{{Severity: validator.SeverityError, Location: validator.Location{Line: u.line}, Message: fmt.Sprintf("unique rule cannot store keys on disk: %v", err), Type: "data", RuleID: rules.Unique}}
+	}
+	u.keyFile, u.keyOut = f, bufio.NewWriter(f)
+	return nil
+}
+
+// bloomRow stores the row's key and marks it as a candidate when it may be a repeat.
+func (u *Unique) bloomRow(lineNumber int, key [16]byte) []validator.Finding {
+	if u.bloom.add(key) {
+		u.candidates[key] = true
+		u.deferred = true
+	}
+	var buf [uniqueEntrySize]byte
+	copy(buf[:16], key[:])
+	binary.BigEndian.PutUint64(buf[16:], uint64(lineNumber))
+	if _, err := u.keyOut.Write(buf[:]); err != nil {
+		u.indexes = nil
+		return []validator.Finding{{Severity: validator.SeverityError, Location: validator.Location{Line: lineNumber}, Message: fmt.Sprintf("unique rule cannot store keys on disk: %v", err), Type: "data", RuleID: rules.Unique}}
+	}
+	return nil
+}
+
+// verify reads the stored keys back and reports the rows whose candidate key an earlier
+// row has; candidates seen only once were false positives of the filter.
+func (u *Unique) verify() []validator.Finding {
+	if len(u.candidates) == 0 {
+		return nil
+	}
+	failed := func(err error) []validator.Finding {
+		return []validator.Finding{{Severity: validator.SeverityError, Location: validator.Location{Line: u.line}, Message: fmt.Sprintf("unique rule cannot read stored keys: %v", err), Type: "data", RuleID: rules.Unique}}
+	}
+	if err := u.keyOut.Flush(); err != nil {
+		return failed(err)
+	}
+	if _, err := u.keyFile.Seek(0, io.SeekStart); err != nil {
+		return failed(err)
+	}
+	first := make(map[[16]byte]int, len(u.candidates))
+	var errs []validator.Finding
+	r := &runReader{r: bufio.NewReader(u.keyFile)}
+	for {
+		ok, err := r.next()
+		if err != nil {
+			return failed(err)
+		}
+		if !ok {
+			break
+		}
+		if !u.candidates[r.entry.hash] {
+			continue
+		}
+		line, seen := first[r.entry.hash]
+		if !seen {
+			first[r.entry.hash] = r.entry.line
+			continue
+		}
+		errs = append(errs, u.storedRepeat(r.entry.line, line))
+	}
+	return errs
+}
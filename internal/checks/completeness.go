@@ -0,0 +1,86 @@
+package checks
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/csvlinter/csvlinter/internal/rules"
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+// Completeness asserts that a column is non-empty in at least a share of the rows, for
+// sources too messy to require it on every row. Values of only whitespace are empty. The
+// measured share is reported once, on the header, at the end of the input.
+type Completeness struct {
+	column     string
+	minPercent float64
+
+	line  int // Line of the header
+	index int // Field index of column; -1 when missing
+	rows  int
+	empty int
+}
+
+// NewCompleteness returns a check that column is non-empty in at least minPercent percent
+// of the rows, from 0 to 100.
+func NewCompleteness(column string, minPercent float64) (*Completeness, error) {
+	if column == "" {
+		return nil, fmt.Errorf("column is required")
+	}
+	if minPercent < 0 || minPercent > 100 || math.IsNaN(minPercent) {
+		return nil, fmt.Errorf("minimum percentage %s is not between 0 and 100", formatNumber(minPercent))
+	}
+	return &Completeness{column: column, minPercent: minPercent}, nil
+}
+
+// Start locates the column.
+func (c *Completeness) Start(headerLine int, headers []string) []validator.Finding {
+	c.line, c.rows, c.empty = headerLine, 0, 0
+	c.index = slices.Index(headers, c.column)
+	if c.index < 0 {
+		return []validator.Finding{{
+			Severity: validator.SeverityError,
+			Location: validator.Location{Line: headerLine},
+			Field:    c.column,
+			Message:  fmt.Sprintf("completeness column '%s' not found in header", c.column),
+			Type:     "data",
+			RuleID:   rules.Completeness,
+		}}
+	}
+	return nil
+}
+
+// Row counts the row and whether the column is empty.
+func (c *Completeness) Row(lineNumber int, fields []string) []validator.Finding {
+	if c.index < 0 {
+		return nil
+	}
+	c.rows++
+	if strings.TrimSpace(fields[c.index]) == "" {
+		c.empty++
+	}
+	return nil
+}
+
+// Finish reports the column when too few rows fill it. Inputs without rows are complete.
+func (c *Completeness) Finish() []validator.Finding {
+	if c.index < 0 || c.rows == 0 {
+		return nil
+	}
+	filled := c.rows - c.empty
+	percent := float64(filled) / float64(c.rows) * 100
+	if percent >= c.minPercent {
+		return nil
+	}
+	return []validator.Finding{{
+		Severity: validator.SeverityError,
+		Location: validator.Location{Line: c.line, Column: c.index + 1},
+		Field:    c.column,
+		Message: fmt.Sprintf("%s is non-empty in %s%% of rows (%d of %d), below the required %s%%",
+			c.column, formatNumber(math.Floor(percent*100)/100), filled, c.rows, formatNumber(c.minPercent)),
+		Type:   "data",
+		RuleID: rules.Completeness,
+	}}
+}
@@ -0,0 +1,57 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+func TestCompleteness(t *testing.T) {
+	input := "id,email\n1,a@example.com\n2,\n3, \n4,d@example.com\n5,e@example.com\n6,f@example.com\n"
+	validate := func(check *Completeness) []validator.Finding {
+		results, err := validator.NewWithOptions(strings.NewReader(input), validator.Options{
+			Delimiter: ",",
+			Checks:    []validator.Check{check},
+		}).Validate()
+		if err != nil {
+			t.Fatalf("Validate failed: %v", err)
+		}
+		return results.Errors
+	}
+
+	check, _ := NewCompleteness("email", 99)
+	errs := validate(check)
+	if len(errs) != 1 || errs[0].Line != 1 || errs[0].Column != 2 || errs[0].RuleID != "DAT011" ||
+		errs[0].Message != "email is non-empty in 66.66% of rows (4 of 6), below the required 99%" {
+		t.Errorf("expected email to be incomplete, got %+v", errs)
+	}
+
+	check, _ = NewCompleteness("email", 66.5)
+	if errs := validate(check); len(errs) != 0 {
+		t.Errorf("expected 66.5%% to be met, got %+v", errs)
+	}
+
+	check, _ = NewCompleteness("phone", 50)
+	if errs := validate(check); len(errs) != 1 || errs[0].Field != "phone" {
+		t.Errorf("expected a missing phone column, got %+v", errs)
+	}
+
+	// Findings about the column are on the header, after a header record here
+	check, _ = NewCompleteness("email", 99)
+	results, err := validator.NewWithOptions(strings.NewReader("HDR,2024\n"+input), validator.Options{
+		Delimiter: ",",
+		Envelope:  &validator.Envelope{HeaderPrefix: "HDR"},
+		Checks:    []validator.Check{check},
+	}).Validate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errs := results.Errors; len(errs) != 1 || errs[0].Line != 2 || errs[0].Column != 2 {
+		t.Errorf("expected email to be incomplete on line 2, got %+v", errs)
+	}
+
+	if _, err := NewCompleteness("email", 101); err == nil {
+		t.Error("expected a percentage above 100 to be rejected")
+	}
+}
@@ -0,0 +1,69 @@
+package checks
+
+import (
+	"slices"
+
+	"github.com/csvlinter/csvlinter/internal/rules"
+	"github.com/csvlinter/csvlinter/internal/schema"
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+// Dataset validates the whole file as one JSON array of row objects against a dataset
+// schema when the input ends. It keeps every row object in memory.
+type Dataset struct {
+	dataset *schema.Dataset
+	label   string
+
+	line    int // Line of the header
+	headers []string
+	rows    []interface{}
+	lines   []int // Line number of each row
+}
+
+// NewDataset returns a check against d; label, when set, is copied to the Schema field of
+// its findings.
+func NewDataset(d *schema.Dataset, label string) *Dataset {
+	return &Dataset{dataset: d, label: label}
+}
+
+// Start resets the collected rows.
+func (d *Dataset) Start(headerLine int, headers []string) []validator.Finding {
+	d.line, d.headers = headerLine, headers
+	d.rows, d.lines = nil, nil
+	return nil
+}
+
+// Row adds the row's object to the dataset.
+func (d *Dataset) Row(lineNumber int, fields []string) []validator.Finding {
+	d.rows = append(d.rows, d.dataset.RowObject(d.headers, fields))
+	d.lines = append(d.lines, lineNumber)
+	return nil
+}
+
+// Finish validates the dataset. Findings about the file as a whole are reported on the
+// header.
+func (d *Dataset) Finish() []validator.Finding {
+	found, err := d.dataset.Validate(d.rows)
+	if err != nil {
+		return []validator.Finding{{Severity: validator.SeverityError, Location: validator.Location{Line: d.line}, Message: err.Error(), Type: "schema", RuleID: rules.SchemaOther, Schema: d.label}}
+	}
+	var errs []validator.Finding
+	for _, f := range found {
+		e := validator.Finding{
+			Severity: validator.SeverityError,
+			Location: validator.Location{Line: d.line},
+			Field:    f.Field,
+			Message:  f.Message,
+			Value:    f.Value,
+			Type:     "schema",
+			RuleID:   rules.ForSchemaKeyword(f.Keyword),
+			Schema:   d.label,
+		}
+		if f.Row >= 0 {
+			e.Line = d.lines[f.Row]
+			e.Column = slices.Index(d.headers, f.Field) + 1
+		}
+		errs = append(errs, e)
+	}
+	return errs
+}
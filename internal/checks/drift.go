@@ -0,0 +1,54 @@
+package checks
+
+import (
+	"slices"
+
+	dataprofile "github.com/csvlinter/csvlinter/internal/profile"
+	"github.com/csvlinter/csvlinter/internal/rules"
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+// Drift warns about columns whose distribution deviates from a baseline profile, such as
+// a null rate that jumped or a mean off by a factor of a thousand.
+type Drift struct {
+	baseline   *dataprofile.Profile
+	thresholds dataprofile.Thresholds
+	collector  *dataprofile.Collector
+	line       int // Line of the header
+	headers    []string
+}
+
+// NewDrift returns a check comparing the input to baseline.
+func NewDrift(baseline *dataprofile.Profile, thresholds dataprofile.Thresholds) *Drift {
+	return &Drift{baseline: baseline, thresholds: thresholds, collector: dataprofile.NewCollector()}
+}
+
+// Advisory makes drift warnings.
+func (d *Drift) Advisory() {}
+
+// Start begins profiling the input.
+func (d *Drift) Start(headerLine int, headers []string) []validator.Finding {
+	d.line, d.headers = headerLine, headers
+	return d.collector.Start(headerLine, headers)
+}
+
+// Row adds the row to the input's dataprofile.
+func (d *Drift) Row(lineNumber int, fields []string) []validator.Finding {
+	return d.collector.Row(lineNumber, fields)
+}
+
+// Finish compares the input's profile to the baseline, reporting drift on the header.
+func (d *Drift) Finish() []validator.Finding {
+	var errs []validator.Finding
+	for _, drift := range dataprofile.Compare(d.baseline, d.collector.Profile(), d.thresholds) {
+		errs = append(errs, validator.Finding{
+			Severity: validator.SeverityError,
+			Location: validator.Location{Line: d.line, Column: slices.Index(d.headers, drift.Column) + 1},
+			Field:    drift.Column,
+			Message:  drift.Message,
+			Type:     "data",
+			RuleID:   rules.Drift,
+		})
+	}
+	return errs
+}
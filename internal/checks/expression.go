@@ -0,0 +1,98 @@
+package checks
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/csvlinter/csvlinter/internal/expr"
+	"github.com/csvlinter/csvlinter/internal/rules"
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+// Expression asserts a comparison between expressions over the columns of each row, such
+// as "total == quantity * unit_price", with a tolerance for equality. Rows with an empty
+// or non-numeric value in one of the columns, or a division by zero, are skipped.
+type Expression struct {
+	assertion *expr.Assertion
+	tolerance float64
+
+	indexes map[string]int
+}
+
+// NewExpression parses the assertion and returns a check for it.
+func NewExpression(assertion string, tolerance float64) (*Expression, error) {
+	a, err := expr.Parse(assertion)
+	if err != nil {
+		return nil, err
+	}
+	return &Expression{assertion: a, tolerance: tolerance}, nil
+}
+
+// Start locates the columns; a missing column disables the check.
+func (e *Expression) Start(headerLine int, headers []string) []validator.Finding {
+	e.indexes = make(map[string]int, len(e.assertion.Columns()))
+	for _, c := range e.assertion.Columns() {
+		e.indexes[c] = -1
+		for i, h := range headers {
+			if h == c {
+				e.indexes[c] = i
+				break
+			}
+		}
+		if e.indexes[c] < 0 {
+			e.indexes = nil
+			return []validator.Finding{{
+				Severity: validator.SeverityError,
+				Location: validator.Location{Line: headerLine},
+				Field:    c,
+				Message:  fmt.Sprintf("column '%s' of assertion %s not found in header", c, e.source()),
+				Type:     "data",
+				RuleID:   rules.Expression,
+			}}
+		}
+	}
+	return nil
+}
+
+// Row evaluates the assertion on the row's values.
+func (e *Expression) Row(lineNumber int, fields []string) []validator.Finding {
+	if e.indexes == nil {
+		return nil
+	}
+	left, right, holds, ok := e.assertion.Eval(func(column string) (float64, bool) {
+		v, err := strconv.ParseFloat(strings.TrimSpace(fields[e.indexes[column]]), 64)
+		return v, err == nil
+	}, e.tolerance)
+	if !ok || holds {
+		return nil
+	}
+	message := fmt.Sprintf("assertion %s failed: %s is %s, %s is %s", e.source(),
+		e.assertion.Left, formatNumber(left), e.assertion.Right, formatNumber(right))
+	if e.tolerance > 0 {
+		message += fmt.Sprintf(" (tolerance %s)", formatNumber(e.tolerance))
+	}
+	err := validator.Finding{Severity: validator.SeverityError, Location: validator.Location{Line: lineNumber}, Message: message, Type: "data", RuleID: rules.Expression}
+	if c, single := e.assertion.SingleColumn(); single {
+		err.Field = c
+		err.Column = e.indexes[c] + 1
+		err.Value = fields[e.indexes[c]]
+	}
+	return []validator.Finding{err}
+}
+
+// Finish has nothing to add; the assertion is checked row by row.
+func (e *Expression) Finish() []validator.Finding {
+	return nil
+}
+
+func (e *Expression) source() string {
+	return e.assertion.Left + " " + e.assertion.Op + " " + e.assertion.Right
+}
+
+// formatNumber prints a float without exponent or trailing zeros, rounded to hide
+// floating-point noise such as 0.30000000000000004.
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(math.Round(f*1e9)/1e9, 'f', -1, 64)
+}
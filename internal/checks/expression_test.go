@@ -0,0 +1,57 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+func TestExpression(t *testing.T) {
+	validate := func(input string, check validator.Check) []validator.Finding {
+		t.Helper()
+		results, err := validator.NewWithOptions(strings.NewReader(input), validator.Options{
+			Delimiter: ",",
+			Checks:    []validator.Check{check},
+		}).Validate()
+		if err != nil {
+			t.Fatalf("Validate failed: %v", err)
+		}
+		return results.Errors
+	}
+
+	check, err := NewExpression("total == quantity * unit_price", 0.01)
+	if err != nil {
+		t.Fatalf("NewExpression failed: %v", err)
+	}
+	// Within tolerance, wrong, empty and non-numeric rows
+	input := "quantity,unit_price,total\n3,3.33,9.99\n3,3.33,10.5\n2,,4\nx,1,1\n"
+	errs := validate(input, check)
+	want := "assertion total == quantity * unit_price failed: total is 10.5, quantity * unit_price is 9.99 (tolerance 0.01)"
+	if len(errs) != 1 || errs[0].Line != 3 || errs[0].Message != want || errs[0].Field != "total" ||
+		errs[0].Column != 3 || errs[0].Value != "10.5" || errs[0].RuleID != "DAT003" {
+		t.Errorf("Expected one failed assertion on line 3, got %+v", errs)
+	}
+
+	// Floating-point noise is not shown
+	check, _ = NewExpression("total == quantity * unit_price", 0)
+	if errs := validate("quantity,unit_price,total\n3,0.1,0.4\n", check); len(errs) != 1 || !strings.Contains(errs[0].Message, "quantity * unit_price is 0.3") {
+		t.Errorf("Expected the computed side rounded, got %+v", errs)
+	}
+
+	// A computed left side has no single field
+	check, _ = NewExpression("quantity * unit_price <= 5", 0)
+	if errs := validate(input, check); len(errs) != 2 || errs[0].Field != "" || errs[0].Column != 0 {
+		t.Errorf("Expected two rows over the limit without a field, got %+v", errs)
+	}
+
+	check, _ = NewExpression("total == net + tax", 0)
+	errs = validate(input, check)
+	if len(errs) != 1 || errs[0].Line != 1 || errs[0].Field != "net" {
+		t.Errorf("Expected the missing column reported once, got %+v", errs)
+	}
+
+	if _, err := NewExpression("total = 1", 0); err == nil {
+		t.Error("Expected an error for an invalid assertion")
+	}
+}
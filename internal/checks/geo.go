@@ -0,0 +1,120 @@
+package checks
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/csvlinter/csvlinter/internal/geo"
+	"github.com/csvlinter/csvlinter/internal/rules"
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+// Geo checks geographic columns: a latitude within ±90 and a longitude within ±180 that
+// are both set or both empty, and geometries written as WKT or GeoJSON. Empty geometries
+// are skipped.
+type Geo struct {
+	latitude, longitude, wkt, geoJSON string
+
+	indexes map[string]int // Configured column -> field index
+}
+
+// NewGeo returns a check of the given columns; unused columns are empty. Latitude and
+// longitude go together.
+func NewGeo(latitude, longitude, wkt, geoJSON string) (*Geo, error) {
+	if (latitude == "") != (longitude == "") {
+		return nil, fmt.Errorf("latitude and longitude columns must be set together")
+	}
+	if latitude == "" && wkt == "" && geoJSON == "" {
+		return nil, fmt.Errorf("need latitude and longitude, wkt or geojson columns")
+	}
+	return &Geo{latitude: latitude, longitude: longitude, wkt: wkt, geoJSON: geoJSON}, nil
+}
+
+// Start locates the columns and reports those missing from the header, whose checks
+// are then skipped.
+func (g *Geo) Start(headerLine int, headers []string) []validator.Finding {
+	g.indexes = make(map[string]int)
+	var errs []validator.Finding
+	for _, c := range []string{g.latitude, g.longitude, g.wkt, g.geoJSON} {
+		if c == "" {
+			continue
+		}
+		i := slices.Index(headers, c)
+		if i < 0 {
+			errs = append(errs, validator.Finding{
+				Severity: validator.SeverityError,
+				Location: validator.Location{Line: headerLine},
+				Field:    c,
+				Message:  fmt.Sprintf("geo column '%s' not found in header", c),
+				Type:     "data",
+				RuleID:   rules.Geo,
+			})
+			continue
+		}
+		g.indexes[c] = i
+	}
+	return errs
+}
+
+// Row checks the row's coordinates and geometries.
+func (g *Geo) Row(lineNumber int, fields []string) []validator.Finding {
+	var errs []validator.Finding
+	report := func(column, format string, args ...any) {
+		i := g.indexes[column]
+		errs = append(errs, validator.Finding{
+			Severity: validator.SeverityError,
+			Location: validator.Location{Line: lineNumber, Column: i + 1},
+			Field:    column,
+			Message:  fmt.Sprintf(format, args...),
+			Value:    fields[i],
+			Type:     "data",
+			RuleID:   rules.Geo,
+		})
+	}
+
+	lat, latOK := g.indexes[g.latitude]
+	lon, lonOK := g.indexes[g.longitude]
+	if latOK && lonOK {
+		latValue, lonValue := strings.TrimSpace(fields[lat]), strings.TrimSpace(fields[lon])
+		switch {
+		case latValue == "" && lonValue != "":
+			report(g.latitude, "%s is empty but %s is set; coordinates must be both set or both empty", g.latitude, g.longitude)
+		case latValue != "" && lonValue == "":
+			report(g.longitude, "%s is empty but %s is set; coordinates must be both set or both empty", g.longitude, g.latitude)
+		case latValue != "":
+			for _, c := range []struct {
+				column, value string
+				limit         float64
+			}{{g.latitude, latValue, 90}, {g.longitude, lonValue, 180}} {
+				v, err := strconv.ParseFloat(c.value, 64)
+				if err != nil || math.IsNaN(v) {
+					report(c.column, "%s %s is not a number", c.column, c.value)
+				} else if math.Abs(v) > c.limit {
+					report(c.column, "%s %s is outside -%s to %s", c.column, c.value, formatNumber(c.limit), formatNumber(c.limit))
+				}
+			}
+		}
+	}
+
+	for _, c := range []struct {
+		column, syntax string
+		validate       func(string) error
+	}{{g.wkt, "WKT", geo.ValidateWKT}, {g.geoJSON, "GeoJSON", geo.ValidateGeoJSON}} {
+		i, ok := g.indexes[c.column]
+		if !ok || strings.TrimSpace(fields[i]) == "" {
+			continue
+		}
+		if err := c.validate(strings.TrimSpace(fields[i])); err != nil {
+			report(c.column, "%s is not valid %s: %v", c.column, c.syntax, err)
+		}
+	}
+	return errs
+}
+
+// Finish has nothing more to report.
+func (g *Geo) Finish() []validator.Finding {
+	return nil
+}
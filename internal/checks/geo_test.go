@@ -0,0 +1,68 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+func TestGeo(t *testing.T) {
+	check, err := NewGeo("lat", "lon", "shape", "")
+	if err != nil {
+		t.Fatalf("NewGeo failed: %v", err)
+	}
+	input := "id,lat,lon,shape\n" +
+		"1,52.52,13.40,POINT (13.4 52.52)\n" +
+		"2,,,\n" +
+		"3,91,13.40,\n" +
+		"4,52.52,-181,\n" +
+		"5,52.52,,\n" +
+		"6,north,13.40,\n" +
+		"7,52.52,13.40,\"LINESTRING (1 2)\"\n"
+	results, err := validator.NewWithOptions(strings.NewReader(input), validator.Options{
+		Delimiter: ",",
+		Checks:    []validator.Check{check},
+	}).Validate()
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	want := []struct {
+		line  int
+		field string
+		msg   string
+	}{
+		{4, "lat", "lat 91 is outside -90 to 90"},
+		{5, "lon", "lon -181 is outside -180 to 180"},
+		{6, "lon", "lon is empty but lat is set"},
+		{7, "lat", "lat north is not a number"},
+		{8, "shape", "shape is not valid WKT: expected at least 2 items"},
+	}
+	if len(results.Errors) != len(want) {
+		t.Fatalf("expected %d errors, got %+v", len(want), results.Errors)
+	}
+	for i, w := range want {
+		e := results.Errors[i]
+		if e.Line != w.line || e.Field != w.field || !strings.HasPrefix(e.Message, w.msg) || e.RuleID != "DAT006" {
+			t.Errorf("error %d: expected line %d %s %q, got %+v", i, w.line, w.field, w.msg, e)
+		}
+	}
+}
+
+func TestGeoConfiguration(t *testing.T) {
+	if _, err := NewGeo("lat", "", "", ""); err == nil {
+		t.Error("expected an error for a latitude without longitude")
+	}
+	if _, err := NewGeo("", "", "", ""); err == nil {
+		t.Error("expected an error without columns")
+	}
+	check, _ := NewGeo("", "", "", "geometry")
+	errs := check.Start(1, []string{"id", "shape"})
+	if len(errs) != 1 || errs[0].Field != "geometry" || errs[0].Line != 1 {
+		t.Errorf("expected a missing column error, got %+v", errs)
+	}
+	if errs := check.Row(2, []string{"1", "{}"}); len(errs) != 0 {
+		t.Errorf("expected the missing column to be skipped, got %+v", errs)
+	}
+}
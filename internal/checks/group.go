@@ -0,0 +1,152 @@
+// Package checks implements validations across rows (validator.Check), configured through
+// the rules section of the config file or the library options.
+package checks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/csvlinter/csvlinter/internal/rules"
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+// Group asserts, for each group of rows sharing the value of the By column, how many of
+// its rows match Where. Rows of a group need not be adjacent.
+type Group struct {
+	by    string
+	where map[string]string
+	min   int
+	max   int // -1 for no upper bound
+
+	byIndex    int
+	whereIndex map[int]string
+	groups     map[string]*groupState
+	ok         bool
+}
+
+type groupState struct {
+	firstLine int
+	matched   int
+}
+
+// NewGroup returns a check that each group has between min and max rows (max -1 for no
+// upper bound) whose columns equal the values in where. An empty where counts every row.
+func NewGroup(by string, where map[string]string, min, max int) *Group {
+	return &Group{by: by, where: where, min: min, max: max}
+}
+
+// Start locates the group and filter columns; a missing column disables the check.
+func (g *Group) Start(headerLine int, headers []string) []validator.Finding {
+	g.groups = make(map[string]*groupState)
+	g.whereIndex = make(map[int]string, len(g.where))
+	index := func(name string) int {
+		for i, h := range headers {
+			if h == name {
+				return i
+			}
+		}
+		return -1
+	}
+
+	var errs []validator.Finding
+	missing := func(column string) {
+		errs = append(errs, validator.Finding{
+			Severity: validator.SeverityError,
+			Location: validator.Location{Line: headerLine},
+			Field:    column,
+			Message:  fmt.Sprintf("group rule column '%s' not found in header", column),
+			Type:     "data",
+			RuleID:   rules.GroupCount,
+		})
+	}
+	if g.byIndex = index(g.by); g.byIndex < 0 {
+		missing(g.by)
+	}
+	for _, column := range sortedKeys(g.where) {
+		i := index(column)
+		if i < 0 {
+			missing(column)
+			continue
+		}
+		g.whereIndex[i] = g.where[column]
+	}
+	g.ok = len(errs) == 0
+	return errs
+}
+
+// Row counts the row in its group.
+func (g *Group) Row(lineNumber int, fields []string) []validator.Finding {
+	if !g.ok {
+		return nil
+	}
+	key := fields[g.byIndex]
+	state, seen := g.groups[key]
+	if !seen {
+		state = &groupState{firstLine: lineNumber}
+		g.groups[key] = state
+	}
+	for i, want := range g.whereIndex {
+		if fields[i] != want {
+			return nil
+		}
+	}
+	state.matched++
+	return nil
+}
+
+// Finish reports every group whose count is out of bounds, at the group's first row.
+func (g *Group) Finish() []validator.Finding {
+	if !g.ok {
+		return nil
+	}
+	var errs []validator.Finding
+	for key, state := range g.groups {
+		if state.matched >= g.min && (g.max < 0 || state.matched <= g.max) {
+			continue
+		}
+		errs = append(errs, validator.Finding{
+			Severity: validator.SeverityError,
+			Location: validator.Location{Line: state.firstLine, Column: g.byIndex + 1},
+			Field:    g.by,
+			Message:  fmt.Sprintf("group %s=%s has %d %s, expected %s", g.by, key, state.matched, g.describeRows(), g.describeBounds()),
+			Value:    key,
+			Type:     "data",
+			RuleID:   rules.GroupCount,
+		})
+	}
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Line < errs[j].Line })
+	return errs
+}
+
+func (g *Group) describeRows() string {
+	if len(g.where) == 0 {
+		return "rows"
+	}
+	conditions := make([]string, 0, len(g.where))
+	for _, column := range sortedKeys(g.where) {
+		conditions = append(conditions, column+"="+g.where[column])
+	}
+	return "rows with " + strings.Join(conditions, ", ")
+}
+
+func (g *Group) describeBounds() string {
+	switch {
+	case g.min == g.max:
+		return fmt.Sprintf("exactly %d", g.min)
+	case g.max < 0:
+		return fmt.Sprintf("at least %d", g.min)
+	case g.min == 0:
+		return fmt.Sprintf("at most %d", g.max)
+	}
+	return fmt.Sprintf("between %d and %d", g.min, g.max)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,53 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+func TestGroup(t *testing.T) {
+	input := "order_id,line_type,sku\n" +
+		"1,header,\n" +
+		"1,item,A\n" +
+		"2,item,B\n" + // no header
+		"3,header,\n" +
+		"3,header,\n" + // two headers
+		"1,item,C\n"
+
+	validate := func(check validator.Check) *validator.Results {
+		t.Helper()
+		results, err := validator.NewWithOptions(strings.NewReader(input), validator.Options{
+			Name:      "orders.csv",
+			Delimiter: ",",
+			Checks:    []validator.Check{check},
+		}).Validate()
+		if err != nil {
+			t.Fatalf("Validate failed: %v", err)
+		}
+		return results
+	}
+
+	results := validate(NewGroup("order_id", map[string]string{"line_type": "header"}, 1, 1))
+	if len(results.Errors) != 2 {
+		t.Fatalf("Expected 2 errors, got %+v", results.Errors)
+	}
+	if e := results.Errors[0]; e.Line != 4 || e.Value != "2" || e.Column != 1 ||
+		e.Message != "group order_id=2 has 0 rows with line_type=header, expected exactly 1" {
+		t.Errorf("Unexpected error for the group without a header: %+v", e)
+	}
+	if e := results.Errors[1]; e.Line != 5 || e.Value != "3" {
+		t.Errorf("Unexpected error for the group with two headers: %+v", e)
+	}
+
+	results = validate(NewGroup("order_id", nil, 0, 2))
+	if len(results.Errors) != 1 || results.Errors[0].Value != "1" || !strings.Contains(results.Errors[0].Message, "has 3 rows, expected at most 2") {
+		t.Errorf("Expected only order 1 to exceed two rows, got %+v", results.Errors)
+	}
+
+	results = validate(NewGroup("invoice_id", map[string]string{"kind": "x"}, 1, -1))
+	if len(results.Errors) != 2 || results.Errors[0].Field != "invoice_id" || results.Errors[1].Field != "kind" {
+		t.Errorf("Expected missing column errors, got %+v", results.Errors)
+	}
+}
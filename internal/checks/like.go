@@ -0,0 +1,96 @@
+package checks
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/csvlinter/csvlinter/internal/parser"
+	"github.com/csvlinter/csvlinter/internal/rules"
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+// Like holds a file to the structure of a known-good file: its delimiter, and its header
+// column for column and in order. Differences are reported once, on the header; the
+// types of the columns are left to a schema inferred from the known-good file.
+type Like struct {
+	name      string   // The known-good file, as given
+	delimiter string   // Its delimiter
+	headers   []string // Its header
+	reading   string   // Delimiter the file is read with
+}
+
+// NewLike returns a check against the known-good file name, delimited by delimiter and
+// with headers, of a file read with the delimiter reading.
+func NewLike(name, delimiter string, headers []string, reading string) *Like {
+	return &Like{name: name, delimiter: delimiter, headers: headers, reading: reading}
+}
+
+// Start compares the header with the known-good file's.
+func (l *Like) Start(headerLine int, headers []string) []validator.Finding {
+	delimiter := l.reading
+	if len(headers) == 1 && len(l.headers) > 1 {
+		// A header read as one column is likely split by another delimiter
+		if guess := parser.GuessDelimiter(headers[0]); guess != "" {
+			delimiter = guess
+		}
+	}
+	if delimiter != l.delimiter {
+		return []validator.Finding{l.finding(headerLine, validator.Finding{
+			Message:    fmt.Sprintf("the file is delimited by %q, not %q like %s", delimiter, l.delimiter, l.name),
+			Expected:   fmt.Sprintf("%q", l.delimiter),
+			Actual:     fmt.Sprintf("%q", delimiter),
+			Suggestion: "write the file with the delimiter of the --like file",
+		})}
+	}
+	if slices.Equal(headers, l.headers) {
+		return nil
+	}
+	var findings []validator.Finding
+	for _, h := range l.headers {
+		if !slices.Contains(headers, h) {
+			findings = append(findings, l.finding(headerLine, validator.Finding{
+				Field:   h,
+				Message: fmt.Sprintf("column '%s' of %s is missing", h, l.name),
+			}))
+		}
+	}
+	for i, h := range headers {
+		if !slices.Contains(l.headers, h) {
+			findings = append(findings, l.finding(headerLine, validator.Finding{
+				Location: validator.Location{Column: i + 1},
+				Field:    h,
+				Message:  fmt.Sprintf("column '%s' is not in %s", h, l.name),
+				Value:    h,
+			}))
+		}
+	}
+	if len(findings) == 0 {
+		// The same columns, in another order or repeated
+		findings = append(findings, l.finding(headerLine, validator.Finding{
+			Message:  fmt.Sprintf("columns are not in the order of %s: %s", l.name, strings.Join(l.headers, ", ")),
+			Expected: strings.Join(l.headers, ", "),
+			Actual:   strings.Join(headers, ", "),
+		}))
+	}
+	return findings
+}
+
+// finding completes f as an error about the header, on line.
+func (l *Like) finding(line int, f validator.Finding) validator.Finding {
+	f.Severity = validator.SeverityError
+	f.Line = line
+	f.Type = "structure"
+	f.RuleID = rules.Like
+	return f
+}
+
+// Row checks nothing; rows are compared by the schema of the known-good file.
+func (l *Like) Row(lineNumber int, fields []string) []validator.Finding {
+	return nil
+}
+
+// Finish reports nothing more.
+func (l *Like) Finish() []validator.Finding {
+	return nil
+}
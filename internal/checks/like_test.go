@@ -0,0 +1,42 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+func TestLike(t *testing.T) {
+	validate := func(input, delimiter string) []validator.Finding {
+		results, err := validator.NewWithOptions(strings.NewReader(input), validator.Options{
+			Delimiter: delimiter,
+			Checks:    []validator.Check{NewLike("golden.csv", ",", []string{"id", "name", "email"}, delimiter)},
+		}).Validate()
+		if err != nil {
+			t.Fatalf("Validate failed: %v", err)
+		}
+		return results.Errors
+	}
+
+	if errs := validate("id,name,email\n1,a,b\n", ","); len(errs) != 0 {
+		t.Errorf("expected the same header to pass, got %+v", errs)
+	}
+	errs := validate("id,email,phone\n1,b,c\n", ",")
+	if len(errs) != 2 || errs[0].Message != "column 'name' of golden.csv is missing" ||
+		errs[1].Message != "column 'phone' is not in golden.csv" || errs[1].Column != 3 || errs[1].RuleID != "STR013" {
+		t.Errorf("expected a missing and an extra column, got %+v", errs)
+	}
+	errs = validate("id,email,name\n1,b,a\n", ",")
+	if len(errs) != 1 || errs[0].Expected != "id, name, email" || errs[0].Actual != "id, email, name" {
+		t.Errorf("expected the columns out of order, got %+v", errs)
+	}
+	errs = validate("id;name;email\n1;a;b\n", ",")
+	if len(errs) != 1 || errs[0].Message != `the file is delimited by ";", not "," like golden.csv` {
+		t.Errorf("expected the other delimiter reported once, got %+v", errs)
+	}
+	errs = validate("id;name;email\n1;a;b\n", ";")
+	if len(errs) != 1 || errs[0].Actual != `";"` {
+		t.Errorf("expected the delimiter read with reported, got %+v", errs)
+	}
+}
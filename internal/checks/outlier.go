@@ -0,0 +1,197 @@
+package checks
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/csvlinter/csvlinter/internal/rules"
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+// Outlier detection methods.
+const (
+	OutlierIQR    = "iqr"
+	OutlierZScore = "zscore"
+)
+
+// DefaultOutlierThresholds are used when a threshold is not set: values beyond 1.5
+// interquartile ranges outside the middle half, or 3 standard deviations from the mean.
+var DefaultOutlierThresholds = map[string]float64{OutlierIQR: 1.5, OutlierZScore: 3}
+
+// minOutlierValues is the fewest numbers a column needs before it is checked; statistics
+// over fewer values flag too much.
+const minOutlierValues = 10
+
+// Outliers warns about numeric values far from the rest of their column, such as a weight
+// in grams among kilograms. It keeps every number of the checked columns until the end of
+// the input.
+type Outliers struct {
+	method    string
+	threshold float64
+	columns   []string // Checked columns; every numeric column when empty
+
+	headers []string
+	values  map[int][]outlierValue // Field index -> numbers, for columns still checked
+}
+
+type outlierValue struct {
+	line  int
+	value float64
+	raw   string
+}
+
+// NewOutliers returns a check using method (OutlierIQR or OutlierZScore) with the given
+// threshold, 0 for the method's default.
+func NewOutliers(method string, threshold float64, columns []string) (*Outliers, error) {
+	if method == "" {
+		method = OutlierIQR
+	}
+	def, ok := DefaultOutlierThresholds[method]
+	if !ok {
+		return nil, fmt.Errorf("unknown outlier method '%s' (use %s or %s)", method, OutlierIQR, OutlierZScore)
+	}
+	if threshold < 0 {
+		return nil, fmt.Errorf("outlier threshold must not be negative")
+	}
+	if threshold == 0 {
+		threshold = def
+	}
+	return &Outliers{method: method, threshold: threshold, columns: columns}, nil
+}
+
+// Advisory makes outliers warnings.
+func (o *Outliers) Advisory() {}
+
+// Start picks the checked columns and reports configured columns missing from the header.
+func (o *Outliers) Start(headerLine int, headers []string) []validator.Finding {
+	o.headers = headers
+	o.values = make(map[int][]outlierValue)
+	if len(o.columns) == 0 {
+		for i := range headers {
+			o.values[i] = nil
+		}
+		return nil
+	}
+	var errs []validator.Finding
+	for _, c := range o.columns {
+		i := slices.Index(headers, c)
+		if i < 0 {
+			errs = append(errs, validator.Finding{
+				Severity: validator.SeverityError,
+				Location: validator.Location{Line: headerLine},
+				Field:    c,
+				Message:  fmt.Sprintf("outlier column '%s' not found in header", c),
+				Type:     "data",
+				RuleID:   rules.Outlier,
+			})
+			continue
+		}
+		o.values[i] = nil
+	}
+	return errs
+}
+
+// Row collects the row's numbers. Without configured columns, a column stops being
+// checked at its first value that is not a number.
+func (o *Outliers) Row(lineNumber int, fields []string) []validator.Finding {
+	for i, list := range o.values {
+		raw := strings.TrimSpace(fields[i])
+		if raw == "" {
+			continue
+		}
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil || math.IsInf(v, 0) || math.IsNaN(v) {
+			if len(o.columns) == 0 {
+				delete(o.values, i)
+			}
+			continue
+		}
+		o.values[i] = append(list, outlierValue{line: lineNumber, value: v, raw: fields[i]})
+	}
+	return nil
+}
+
+// Finish computes each column's bounds and reports the values outside them.
+func (o *Outliers) Finish() []validator.Finding {
+	var errs []validator.Finding
+	for i, list := range o.values {
+		if len(list) < minOutlierValues {
+			continue
+		}
+		var outside func(float64) bool
+		var describe func(v float64) string
+		switch o.method {
+		case OutlierZScore:
+			mean, sd := meanStdDev(list)
+			if sd == 0 {
+				continue
+			}
+			outside = func(v float64) bool { return math.Abs(v-mean)/sd > o.threshold }
+			describe = func(v float64) string {
+				return fmt.Sprintf("z-score %s (mean %s, standard deviation %s, threshold %s)",
+					formatNumber(math.Round((v-mean)/sd*100)/100), formatNumber(math.Round(mean*1000)/1000),
+					formatNumber(math.Round(sd*1000)/1000), formatNumber(o.threshold))
+			}
+		default:
+			q1, q3 := quartiles(list)
+			iqr := q3 - q1
+			if iqr == 0 {
+				// Half the column is a single value; any other value would be flagged
+				continue
+			}
+			low, high := q1-o.threshold*iqr, q3+o.threshold*iqr
+			outside = func(v float64) bool { return v < low || v > high }
+			describe = func(float64) string {
+				return fmt.Sprintf("outside %s to %s (quartiles %s and %s, %s interquartile ranges)",
+					formatNumber(low), formatNumber(high), formatNumber(q1), formatNumber(q3), formatNumber(o.threshold))
+			}
+		}
+		for _, v := range list {
+			if outside(v.value) {
+				errs = append(errs, validator.Finding{
+					Severity: validator.SeverityError,
+					Location: validator.Location{Line: v.line, Column: i + 1},
+					Field:    o.headers[i],
+					Message:  fmt.Sprintf("%s %s is an outlier: %s", o.headers[i], v.raw, describe(v.value)),
+					Value:    v.raw,
+					Type:     "data",
+					RuleID:   rules.Outlier,
+				})
+			}
+		}
+	}
+	return errs
+}
+
+// meanStdDev returns the mean and population standard deviation of the values.
+func meanStdDev(list []outlierValue) (mean, sd float64) {
+	for _, v := range list {
+		mean += v.value
+	}
+	mean /= float64(len(list))
+	for _, v := range list {
+		sd += (v.value - mean) * (v.value - mean)
+	}
+	return mean, math.Sqrt(sd / float64(len(list)))
+}
+
+// quartiles returns the first and third quartiles, interpolating between values.
+func quartiles(list []outlierValue) (q1, q3 float64) {
+	sorted := make([]float64, len(list))
+	for i, v := range list {
+		sorted[i] = v.value
+	}
+	slices.Sort(sorted)
+	quantile := func(q float64) float64 {
+		pos := q * float64(len(sorted)-1)
+		lower := int(pos)
+		if lower+1 >= len(sorted) {
+			return sorted[lower]
+		}
+		return sorted[lower] + (pos-float64(lower))*(sorted[lower+1]-sorted[lower])
+	}
+	return quantile(0.25), quantile(0.75)
+}
@@ -0,0 +1,71 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+func TestOutliers(t *testing.T) {
+	validate := func(input string, check validator.Check) *validator.Results {
+		t.Helper()
+		results, err := validator.NewWithOptions(strings.NewReader(input), validator.Options{
+			Delimiter: ",",
+			Checks:    []validator.Check{check},
+		}).Validate()
+		if err != nil {
+			t.Fatalf("Validate failed: %v", err)
+		}
+		return results
+	}
+
+	// Weights in kilograms with one in grams; the name column is not numeric
+	var b strings.Builder
+	b.WriteString("name,weight\n")
+	for _, w := range []string{"1.2", "1.5", "1.1", "1.4", "1300", "1.3", "1.6", "1.2", "1.5", "1.4", "1.3"} {
+		b.WriteString("item," + w + "\n")
+	}
+	input := b.String()
+
+	for _, method := range []string{"", OutlierIQR, OutlierZScore} {
+		check, err := NewOutliers(method, 0, nil)
+		if err != nil {
+			t.Fatalf("NewOutliers(%q) failed: %v", method, err)
+		}
+		results := validate(input, check)
+		if !results.Valid || len(results.Errors) != 0 {
+			t.Errorf("%q: expected outliers to leave the file valid, got %+v", method, results.Errors)
+		}
+		w := results.Warnings
+		if len(w) != 1 || w[0].Line != 6 || w[0].Field != "weight" || w[0].Column != 2 ||
+			w[0].Value != "1300" || w[0].RuleID != "DAT004" || !strings.Contains(w[0].Message, "weight 1300 is an outlier") {
+			t.Errorf("%q: expected one warning for line 6, got %+v", method, w)
+		}
+	}
+
+	check, _ := NewOutliers(OutlierIQR, 0, nil)
+	if w := validate(input, check).Warnings; !strings.Contains(w[0].Message, "(quartiles 1.25 and 1.5, 1.5 interquartile ranges)") {
+		t.Errorf("Expected the quartiles in the message, got %q", w[0].Message)
+	}
+
+	// Too few values to judge
+	check, _ = NewOutliers(OutlierZScore, 0, nil)
+	if w := validate("weight\n1\n1\n1000\n", check).Warnings; len(w) != 0 {
+		t.Errorf("Expected short columns to be skipped, got %+v", w)
+	}
+
+	// Configured columns are checked even with other values mixed in, and must exist
+	check, _ = NewOutliers(OutlierIQR, 0, []string{"weight", "height"})
+	w := validate(strings.Replace(input, "item,1.1", "item,n/a", 1), check).Warnings
+	if len(w) != 2 || w[0].Line != 1 || w[0].Field != "height" || w[1].Value != "1300" {
+		t.Errorf("Expected a missing column and the outlier, got %+v", w)
+	}
+
+	if _, err := NewOutliers("median", 0, nil); err == nil {
+		t.Error("Expected an error for an unknown method")
+	}
+	if _, err := NewOutliers(OutlierZScore, -1, nil); err == nil {
+		t.Error("Expected an error for a negative threshold")
+	}
+}
@@ -0,0 +1,112 @@
+package checks
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/csvlinter/csvlinter/internal/rules"
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+// RequiredIf requires columns to be non-empty on rows matching a condition, such as a
+// tracking number and carrier on rows whose status is "shipped". Each empty required
+// column is reported on its own.
+type RequiredIf struct {
+	when    map[string]string
+	require []string
+
+	whenIndex    map[int]string
+	requireIndex []int // -1 for required columns missing from the header
+	condition    string
+}
+
+// NewRequiredIf returns a check that the require columns are set on rows whose columns
+// equal the values in when.
+func NewRequiredIf(when map[string]string, require []string) (*RequiredIf, error) {
+	if len(when) == 0 || len(require) == 0 {
+		return nil, fmt.Errorf("need when and require columns")
+	}
+	return &RequiredIf{when: when, require: require}, nil
+}
+
+// Start locates the columns. A missing condition column disables the check; a missing
+// required column fails every matching row.
+func (r *RequiredIf) Start(headerLine int, headers []string) []validator.Finding {
+	r.whenIndex = make(map[int]string, len(r.when))
+	var errs []validator.Finding
+	var parts []string
+	for column, value := range r.when {
+		parts = append(parts, fmt.Sprintf("%s is %s", column, value))
+		i := slices.Index(headers, column)
+		if i < 0 {
+			errs = append(errs, r.headerError(headerLine, column))
+			continue
+		}
+		r.whenIndex[i] = value
+	}
+	sort.Strings(parts)
+	r.condition = strings.Join(parts, " and ")
+	if len(errs) > 0 {
+		r.whenIndex = nil
+		return errs
+	}
+	r.requireIndex = make([]int, len(r.require))
+	for j, column := range r.require {
+		r.requireIndex[j] = slices.Index(headers, column)
+		if r.requireIndex[j] < 0 {
+			errs = append(errs, r.headerError(headerLine, column))
+		}
+	}
+	return errs
+}
+
+func (r *RequiredIf) headerError(line int, column string) validator.Finding {
+	return validator.Finding{
+		Severity: validator.SeverityError,
+		Location: validator.Location{Line: line},
+		Field:    column,
+		Message:  fmt.Sprintf("required_if column '%s' not found in header", column),
+		Type:     "data",
+		RuleID:   rules.RequiredIf,
+	}
+}
+
+// Row reports the required columns that are empty on a matching row.
+func (r *RequiredIf) Row(lineNumber int, fields []string) []validator.Finding {
+	if r.whenIndex == nil {
+		return nil
+	}
+	for i, value := range r.whenIndex {
+		if fields[i] != value {
+			return nil
+		}
+	}
+	var errs []validator.Finding
+	for j, column := range r.require {
+		i := r.requireIndex[j]
+		if i >= 0 && strings.TrimSpace(fields[i]) != "" {
+			continue
+		}
+		err := validator.Finding{
+			Severity:   validator.SeverityError,
+			Location:   validator.Location{Line: lineNumber},
+			Field:      column,
+			Message:    fmt.Sprintf("%s is required when %s", column, r.condition),
+			Suggestion: fmt.Sprintf("fill in %s", column),
+			Type:       "data",
+			RuleID:     rules.RequiredIf,
+		}
+		if i >= 0 {
+			err.Column = i + 1
+		}
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// Finish has nothing more to report.
+func (r *RequiredIf) Finish() []validator.Finding {
+	return nil
+}
@@ -0,0 +1,61 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+func TestRequiredIf(t *testing.T) {
+	check, err := NewRequiredIf(map[string]string{"status": "shipped"}, []string{"tracking_number", "carrier"})
+	if err != nil {
+		t.Fatalf("NewRequiredIf failed: %v", err)
+	}
+	input := "id,status,tracking_number,carrier\n" +
+		"1,shipped,1Z999,UPS\n" +
+		"2,pending,,\n" +
+		"3,shipped,,UPS\n" +
+		"4,shipped, ,\n"
+	results, err := validator.NewWithOptions(strings.NewReader(input), validator.Options{
+		Delimiter: ",",
+		Checks:    []validator.Check{check},
+	}).Validate()
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	want := []struct {
+		line   int
+		field  string
+		column int
+	}{{4, "tracking_number", 3}, {5, "tracking_number", 3}, {5, "carrier", 4}}
+	if len(results.Errors) != len(want) {
+		t.Fatalf("expected %d errors, got %+v", len(want), results.Errors)
+	}
+	for i, w := range want {
+		e := results.Errors[i]
+		if e.Line != w.line || e.Field != w.field || e.Column != w.column || e.RuleID != "DAT009" ||
+			e.Message != w.field+" is required when status is shipped" {
+			t.Errorf("error %d: expected %s on line %d, got %+v", i, w.field, w.line, e)
+		}
+	}
+}
+
+func TestRequiredIfMissingColumns(t *testing.T) {
+	check, _ := NewRequiredIf(map[string]string{"status": "shipped"}, []string{"carrier"})
+	if errs := check.Start(1, []string{"id", "status"}); len(errs) != 1 || errs[0].Field != "carrier" {
+		t.Fatalf("expected a missing carrier column, got %+v", errs)
+	}
+	if errs := check.Row(2, []string{"1", "shipped"}); len(errs) != 1 || errs[0].Column != 0 {
+		t.Errorf("expected the missing required column to fail matching rows, got %+v", errs)
+	}
+	if errs := check.Start(1, []string{"id", "carrier"}); len(errs) != 1 || errs[0].Field != "status" {
+		t.Fatalf("expected a missing status column, got %+v", errs)
+	}
+	if errs := check.Row(2, []string{"1", ""}); len(errs) != 0 {
+		t.Errorf("expected a missing condition column to disable the check, got %+v", errs)
+	}
+	if _, err := NewRequiredIf(nil, []string{"carrier"}); err == nil {
+		t.Error("expected an error without a condition")
+	}
+}
@@ -0,0 +1,137 @@
+package checks
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/csvlinter/csvlinter/internal/rules"
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+// emoji are the ranges of emoji and pictographs, including regional indicators, and
+// variation selector 16, which asks for emoji presentation.
+var emoji = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x2600, Hi: 0x27bf, Stride: 1},
+		{Lo: 0x2b50, Hi: 0x2b55, Stride: 1},
+		{Lo: 0xfe0f, Hi: 0xfe0f, Stride: 1},
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x1f000, Hi: 0x1faff, Stride: 1},
+	},
+}
+
+// Scripts checks that free-text columns are written in the allowed Unicode scripts, such
+// as Latin for a catalog that must be in a Latin-alphabet language, and optionally that
+// they contain no emoji. Digits, punctuation and other characters shared by all scripts
+// are always allowed.
+type Scripts struct {
+	columns []string
+	scripts []string
+	noEmoji bool
+
+	indexes map[string]int // Column -> field index, for the columns found in the header
+}
+
+// NewScripts returns a check of columns. Scripts are Unicode script names, such as Latin,
+// Cyrillic or Han; with none, only noEmoji is checked.
+func NewScripts(columns, scripts []string, noEmoji bool) (*Scripts, error) {
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("columns are required")
+	}
+	if len(scripts) == 0 && !noEmoji {
+		return nil, fmt.Errorf("need scripts or no_emoji")
+	}
+	for _, s := range scripts {
+		if unicode.Scripts[s] == nil {
+			return nil, fmt.Errorf("unknown script '%s' (use Unicode script names such as Latin, Cyrillic or Han)", s)
+		}
+	}
+	return &Scripts{columns: columns, scripts: scripts, noEmoji: noEmoji}, nil
+}
+
+// Start locates the columns and reports those missing from the header.
+func (s *Scripts) Start(headerLine int, headers []string) []validator.Finding {
+	s.indexes = make(map[string]int, len(s.columns))
+	var errs []validator.Finding
+	for _, c := range s.columns {
+		i := slices.Index(headers, c)
+		if i < 0 {
+			errs = append(errs, validator.Finding{
+				Severity: validator.SeverityError,
+				Location: validator.Location{Line: headerLine},
+				Field:    c,
+				Message:  fmt.Sprintf("script column '%s' not found in header", c),
+				Type:     "data",
+				RuleID:   rules.Script,
+			})
+			continue
+		}
+		s.indexes[c] = i
+	}
+	return errs
+}
+
+// Row reports, per column, the first character outside the allowed scripts or the first
+// emoji.
+func (s *Scripts) Row(lineNumber int, fields []string) []validator.Finding {
+	var errs []validator.Finding
+	for _, c := range s.columns {
+		i, ok := s.indexes[c]
+		if !ok {
+			continue
+		}
+		if message := s.check(fields[i]); message != "" {
+			errs = append(errs, validator.Finding{
+				Severity: validator.SeverityError,
+				Location: validator.Location{Line: lineNumber, Column: i + 1},
+				Field:    c,
+				Message:  c + " " + message,
+				Value:    fields[i],
+				Type:     "data",
+				RuleID:   rules.Script,
+			})
+		}
+	}
+	return errs
+}
+
+// check returns what is wrong with value, or "".
+func (s *Scripts) check(value string) string {
+	for _, r := range value {
+		if s.noEmoji && unicode.Is(emoji, r) {
+			return fmt.Sprintf("contains emoji %q", r)
+		}
+		if len(s.scripts) == 0 || unicode.In(r, unicode.Common, unicode.Inherited) {
+			continue
+		}
+		allowed := false
+		for _, name := range s.scripts {
+			if unicode.Is(unicode.Scripts[name], r) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Sprintf("contains %s character %q; allowed scripts: %s", scriptOf(r), r, strings.Join(s.scripts, ", "))
+		}
+	}
+	return ""
+}
+
+// scriptOf names the script of r.
+func scriptOf(r rune) string {
+	for name, table := range unicode.Scripts {
+		if unicode.Is(table, r) {
+			return name
+		}
+	}
+	return "unassigned"
+}
+
+// Finish has nothing more to report.
+func (s *Scripts) Finish() []validator.Finding {
+	return nil
+}
@@ -0,0 +1,47 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+func TestScripts(t *testing.T) {
+	check, err := NewScripts([]string{"title", "notes"}, []string{"Latin"}, true)
+	if err != nil {
+		t.Fatalf("NewScripts failed: %v", err)
+	}
+	input := "sku,title,notes\n" +
+		"A,Crème brûlée – 2 × 100 g,\n" +
+		"B,Борщ,\n" +
+		"C,Cake 🎂,Läuft\n" +
+		"D,Tea,緑茶\n"
+	results, err := validator.NewWithOptions(strings.NewReader(input), validator.Options{
+		Delimiter: ",",
+		Checks:    []validator.Check{check},
+	}).Validate()
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	want := []string{
+		"title contains Cyrillic character 'Б'; allowed scripts: Latin",
+		"title contains emoji '🎂'",
+		"notes contains Han character '緑'; allowed scripts: Latin",
+	}
+	if len(results.Errors) != len(want) {
+		t.Fatalf("expected %d errors, got %+v", len(want), results.Errors)
+	}
+	for i, w := range want {
+		if e := results.Errors[i]; e.Message != w || e.RuleID != "DAT008" {
+			t.Errorf("error %d: expected %q, got %+v", i, w, e)
+		}
+	}
+
+	if _, err := NewScripts([]string{"title"}, []string{"Klingon"}, false); err == nil || !strings.Contains(err.Error(), "unknown script 'Klingon'") {
+		t.Errorf("expected an unknown script error, got %v", err)
+	}
+	if _, err := NewScripts([]string{"title"}, nil, false); err == nil {
+		t.Error("expected an error without scripts or no_emoji")
+	}
+}
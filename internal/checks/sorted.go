@@ -0,0 +1,120 @@
+package checks
+
+import (
+	"cmp"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/csvlinter/csvlinter/internal/rules"
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+// timeLayouts are tried, in order, to compare values as timestamps.
+var timeLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02"}
+
+// Sorted asserts that a column is in ascending or descending order, optionally without
+// repeated values. Values compare as numbers when both are numbers, as timestamps when
+// both are timestamps, and as strings otherwise. Empty values are skipped.
+type Sorted struct {
+	column     string
+	descending bool
+	unique     bool
+
+	index    int
+	previous string
+}
+
+// NewSorted returns a check that column is sorted ascending, or descending when
+// descending is set, with strictly increasing (decreasing) values when unique is set.
+func NewSorted(column string, descending, unique bool) *Sorted {
+	return &Sorted{column: column, descending: descending, unique: unique}
+}
+
+// Start locates the column; a missing column disables the check.
+func (s *Sorted) Start(headerLine int, headers []string) []validator.Finding {
+	s.index = -1
+	s.previous = ""
+	for i, h := range headers {
+		if h == s.column {
+			s.index = i
+			return nil
+		}
+	}
+	return []validator.Finding{{
+		Severity: validator.SeverityError,
+		Location: validator.Location{Line: headerLine},
+		Field:    s.column,
+		Message:  fmt.Sprintf("sorted column '%s' not found in header", s.column),
+		Type:     "data",
+		RuleID:   rules.Sorted,
+	}}
+}
+
+// Row compares the row's value with the previous non-empty value.
+func (s *Sorted) Row(lineNumber int, fields []string) []validator.Finding {
+	if s.index < 0 {
+		return nil
+	}
+	value := fields[s.index]
+	if value == "" {
+		return nil
+	}
+	previous := s.previous
+	s.previous = value
+	if previous == "" {
+		return nil
+	}
+
+	order := compareValues(previous, value)
+	if s.descending {
+		order = -order
+	}
+	var message string
+	switch {
+	case order > 0:
+		message = fmt.Sprintf("%s is not in %s order: %s after %s", s.column, s.order(), value, previous)
+	case order == 0 && s.unique:
+		message = fmt.Sprintf("%s repeats %s in a column that must be unique and in %s order", s.column, value, s.order())
+	default:
+		return nil
+	}
+	return []validator.Finding{{
+		Severity: validator.SeverityError,
+		Location: validator.Location{Line: lineNumber, Column: s.index + 1},
+		Field:    s.column,
+		Message:  message,
+		Value:    value,
+		Type:     "data",
+		RuleID:   rules.Sorted,
+	}}
+}
+
+// Finish has nothing to add; order is checked row by row.
+func (s *Sorted) Finish() []validator.Finding {
+	return nil
+}
+
+func (s *Sorted) order() string {
+	if s.descending {
+		return "descending"
+	}
+	return "ascending"
+}
+
+// compareValues returns -1, 0 or 1 as a sorts before, equal to or after b.
+func compareValues(a, b string) int {
+	if x, err := strconv.ParseFloat(a, 64); err == nil {
+		if y, err := strconv.ParseFloat(b, 64); err == nil {
+			return cmp.Compare(x, y)
+		}
+	}
+	for _, layout := range timeLayouts {
+		x, errA := time.Parse(layout, a)
+		y, errB := time.Parse(layout, b)
+		if errA == nil && errB == nil {
+			return x.Compare(y)
+		}
+	}
+	return cmp.Compare(a, b)
+}
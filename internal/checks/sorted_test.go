@@ -0,0 +1,45 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+func TestSorted(t *testing.T) {
+	validate := func(input string, check validator.Check) []validator.Finding {
+		t.Helper()
+		results, err := validator.NewWithOptions(strings.NewReader(input), validator.Options{
+			Delimiter: ",",
+			Checks:    []validator.Check{check},
+		}).Validate()
+		if err != nil {
+			t.Fatalf("Validate failed: %v", err)
+		}
+		return results.Errors
+	}
+
+	// 9 < 10 numerically though not as strings; the empty value is skipped
+	numbers := "id\n2\n9\n\n10\n10\n3\n"
+	errs := validate(numbers, NewSorted("id", false, false))
+	if len(errs) != 1 || errs[0].Line != 6 || errs[0].Value != "3" || errs[0].Message != "id is not in ascending order: 3 after 10" {
+		t.Errorf("Expected one ordering error on line 6, got %+v", errs)
+	}
+	errs = validate(numbers, NewSorted("id", false, true))
+	if len(errs) != 2 || errs[0].Line != 5 || !strings.Contains(errs[0].Message, "repeats 10") {
+		t.Errorf("Expected the repeated 10 to be reported when unique, got %+v", errs)
+	}
+
+	// Offsets make the string order differ from the time order
+	times := "ts\n2024-01-01T12:00:00+02:00\n2024-01-01T11:00:00Z\n2024-01-01T10:00:00Z\n"
+	if errs := validate(times, NewSorted("ts", false, false)); len(errs) != 1 || errs[0].Line != 4 {
+		t.Errorf("Expected timestamps compared as times, got %+v", errs)
+	}
+	if errs := validate("name\nc\nb\na\n", NewSorted("name", true, true)); len(errs) != 0 {
+		t.Errorf("Expected descending strings to pass, got %+v", errs)
+	}
+	if errs := validate("name\na\n", NewSorted("ts", false, false)); len(errs) != 1 || errs[0].Field != "ts" {
+		t.Errorf("Expected a missing column error, got %+v", errs)
+	}
+}
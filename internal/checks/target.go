@@ -0,0 +1,195 @@
+package checks
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/csvlinter/csvlinter/internal/rules"
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+// profile holds the constraints a database puts on a CSV file loaded with its bulk loader
+// (COPY, bq load, COPY INTO).
+type profile struct {
+	title         string
+	maxIdentifier int            // Longest column name in bytes
+	identifier    *regexp.Regexp // Column names must match; nil allows any non-empty name
+	identifierMsg string         // Explains identifier
+	reserved      []string       // Reserved column name prefixes, compared case-insensitively
+	foldCase      bool           // Column names are case-insensitive, so "ID" and "id" collide
+	maxField      int            // Largest field in bytes
+	nul           bool           // NUL bytes are rejected
+	dateLayouts   []string       // Accepted date and timestamp forms for date-like values
+	dateMsg       string         // Describes dateLayouts
+}
+
+// isoLayouts are the ISO 8601 dates and timestamps every target accepts. Fractional
+// seconds are accepted after the seconds by time.Parse.
+var isoLayouts = []string{
+	"2006-1-2",
+	"2006-1-2 15:04:05",
+	"2006-1-2T15:04:05",
+	"2006-1-2 15:04:05Z07:00",
+	"2006-1-2T15:04:05Z07:00",
+	"2006-1-2 15:04:05-07",
+	"2006-1-2T15:04:05-07",
+}
+
+// mdyLayouts are US month/day/year dates, read by loaders that default to MDY order.
+var mdyLayouts = []string{"1/2/2006", "1/2/2006 15:04:05"}
+
+var profiles = map[string]profile{
+	"postgres": {
+		title:         "PostgreSQL",
+		maxIdentifier: 63,
+		maxField:      1 << 30,
+		nul:           true,
+		dateLayouts:   append(append([]string{"2006-1-2 15:04"}, isoLayouts...), mdyLayouts...),
+		dateMsg:       "ISO 8601 or MM/DD/YYYY (DateStyle ISO, MDY)",
+	},
+	"bigquery": {
+		title:         "BigQuery",
+		maxIdentifier: 300,
+		identifier:    regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`),
+		identifierMsg: "must start with a letter or underscore and contain only letters, digits and underscores",
+		reserved:      []string{"_TABLE_", "_FILE_", "_PARTITION", "_ROW_TIMESTAMP", "__ROOT__", "_COLIDENTIFIER"},
+		foldCase:      true,
+		maxField:      100 << 20,
+		nul:           true,
+		dateLayouts:   isoLayouts,
+		dateMsg:       "YYYY-MM-DD[ HH:MM:SS[.ffffff][zone]]",
+	},
+	"snowflake": {
+		title:         "Snowflake",
+		maxIdentifier: 255,
+		maxField:      16 << 20,
+		dateLayouts:   append(append([]string{}, isoLayouts...), mdyLayouts...),
+		dateMsg:       "ISO 8601 or MM/DD/YYYY (DATE_INPUT_FORMAT AUTO)",
+	},
+}
+
+// dateLike matches values that look like a date (three numbers, one of them a four-digit
+// year), so that only those are held to the target's date formats.
+var dateLike = regexp.MustCompile(`^(\d{4}[-/.]\d{1,2}[-/.]\d{1,2}|\d{1,2}[-/.]\d{1,2}[-/.]\d{4})([ T]\d.*)?$`)
+
+// Targets returns the names of the supported targets, sorted.
+func Targets() []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Target checks that a file will load into a database: column names are valid
+// identifiers, fields fit and contain no characters the loader rejects, and date-like
+// values are in a form the loader parses.
+type Target struct {
+	p profile
+
+	headers []string
+}
+
+// NewTarget returns the check for the named target (see Targets).
+func NewTarget(name string) (*Target, error) {
+	p, ok := profiles[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown target %q (supported: %s)", name, strings.Join(Targets(), ", "))
+	}
+	return &Target{p: p}, nil
+}
+
+// Start checks the column names.
+func (t *Target) Start(headerLine int, headers []string) []validator.Finding {
+	t.headers = headers
+	var errs []validator.Finding
+	report := func(i int, message string) {
+		errs = append(errs, validator.Finding{
+			Severity: validator.SeverityError,
+			Location: validator.Location{Line: headerLine, Column: i + 1},
+			Field:    headers[i],
+			Message:  fmt.Sprintf("%s: %s", t.p.title, message),
+			Value:    headers[i],
+			Type:     "load",
+			RuleID:   rules.LoadIdentifier,
+		})
+	}
+	seen := make(map[string]int, len(headers))
+	for i, h := range headers {
+		switch {
+		case h == "":
+			report(i, fmt.Sprintf("column %d has an empty name", i+1))
+			continue
+		case len(h) > t.p.maxIdentifier:
+			report(i, fmt.Sprintf("column name is %d bytes, longer than the %d allowed", len(h), t.p.maxIdentifier))
+		case t.p.identifier != nil && !t.p.identifier.MatchString(h):
+			report(i, fmt.Sprintf("column name '%s' %s", h, t.p.identifierMsg))
+		}
+		for _, prefix := range t.p.reserved {
+			if strings.HasPrefix(strings.ToUpper(h), prefix) {
+				report(i, fmt.Sprintf("column name '%s' uses the reserved prefix %s", h, prefix))
+			}
+		}
+		key := h
+		if t.p.foldCase {
+			key = strings.ToLower(h)
+		}
+		if first, dup := seen[key]; dup {
+			report(i, fmt.Sprintf("column name '%s' duplicates column %d", h, first+1))
+		} else {
+			seen[key] = i
+		}
+	}
+	return errs
+}
+
+// Row checks each field's size, characters and, for date-like values, format.
+func (t *Target) Row(lineNumber int, fields []string) []validator.Finding {
+	var errs []validator.Finding
+	for i, value := range fields {
+		var message, rule string
+		switch {
+		case len(value) > t.p.maxField:
+			message, rule = fmt.Sprintf("field is %d bytes, larger than the %d allowed", len(value), t.p.maxField), rules.LoadFieldSize
+		case t.p.nul && strings.IndexByte(value, 0) >= 0:
+			message, rule = "field contains a NUL byte, which the loader rejects", rules.LoadCharacter
+		case dateLike.MatchString(value) && !parses(value, t.p.dateLayouts):
+			message, rule = fmt.Sprintf("date '%s' is not in a format the loader accepts: %s", value, t.p.dateMsg), rules.LoadDate
+		default:
+			continue
+		}
+		e := validator.Finding{
+			Severity: validator.SeverityError,
+			Location: validator.Location{Line: lineNumber, Column: i + 1},
+			Message:  fmt.Sprintf("%s: %s", t.p.title, message),
+			Type:     "load",
+			RuleID:   rule,
+		}
+		if i < len(t.headers) {
+			e.Field = t.headers[i]
+		}
+		if rule == rules.LoadDate {
+			e.Value = value
+		}
+		errs = append(errs, e)
+	}
+	return errs
+}
+
+// Finish has nothing to add; every constraint is checked row by row.
+func (t *Target) Finish() []validator.Finding {
+	return nil
+}
+
+func parses(value string, layouts []string) bool {
+	for _, layout := range layouts {
+		if _, err := time.Parse(layout, value); err == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/rules"
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+func TestTarget(t *testing.T) {
+	validate := func(input, target string) []validator.Finding {
+		t.Helper()
+		check, err := NewTarget(target)
+		if err != nil {
+			t.Fatalf("NewTarget(%q) failed: %v", target, err)
+		}
+		results, err := validator.NewWithOptions(strings.NewReader(input), validator.Options{
+			Delimiter: ",",
+			Checks:    []validator.Check{check},
+		}).Validate()
+		if err != nil {
+			t.Fatalf("Validate failed: %v", err)
+		}
+		return results.Errors
+	}
+	ruleIDs := func(errs []validator.Finding) string {
+		ids := make([]string, len(errs))
+		for i, e := range errs {
+			ids[i] = e.RuleID
+		}
+		return strings.Join(ids, ",")
+	}
+
+	// Quoted identifiers allow spaces in PostgreSQL, not in BigQuery; BigQuery names are case-insensitive
+	headers := "Order ID,id,ID\n1,2,3\n"
+	if errs := validate(headers, "postgres"); len(errs) != 0 {
+		t.Errorf("Expected PostgreSQL to accept the headers, got %+v", errs)
+	}
+	errs := validate(headers, "bigquery")
+	if len(errs) != 2 || errs[0].Column != 1 || errs[1].Column != 3 || ruleIDs(errs) != "LOD001,LOD001" {
+		t.Errorf("Expected the space and the case-insensitive duplicate to be reported, got %+v", errs)
+	}
+	if errs := validate("_TABLE_x,"+strings.Repeat("a", 64)+"\n1,2\n", "postgres"); len(errs) != 1 || errs[0].Column != 2 {
+		t.Errorf("Expected only the 64-byte name to be reported for PostgreSQL, got %+v", errs)
+	}
+	if errs := validate("_table_x\n1\n", "bigquery"); len(errs) != 1 || !strings.Contains(errs[0].Message, "reserved prefix") {
+		t.Errorf("Expected the reserved prefix to be reported for BigQuery, got %+v", errs)
+	}
+
+	// 13/02/2024 cannot be MDY; BigQuery only takes ISO dates
+	dates := "d\n2024-02-13\n2024-02-13 10:00:00.123+01:00\n02/13/2024\n13/02/2024\n1.5.2\n"
+	if errs := validate(dates, "postgres"); len(errs) != 1 || errs[0].Line != 5 || errs[0].RuleID != rules.LoadDate {
+		t.Errorf("Expected only 13/02/2024 to be rejected by PostgreSQL, got %+v", errs)
+	}
+	if errs := validate(dates, "bigquery"); ruleIDs(errs) != "LOD004,LOD004" || errs[0].Line != 4 {
+		t.Errorf("Expected both slash dates to be rejected by BigQuery, got %+v", errs)
+	}
+
+	if errs := validate("a,b\nx\x00y,ok\n", "postgres"); len(errs) != 1 || errs[0].RuleID != rules.LoadCharacter || errs[0].Field != "a" {
+		t.Errorf("Expected the NUL byte to be reported, got %+v", errs)
+	}
+	if errs := validate("a,b\nx\x00y,ok\n", "snowflake"); len(errs) != 0 {
+		t.Errorf("Expected Snowflake to accept the NUL byte, got %+v", errs)
+	}
+
+	if _, err := NewTarget("oracle"); err == nil || !strings.Contains(err.Error(), "bigquery, postgres, snowflake") {
+		t.Errorf("Expected an unknown target error listing the targets, got %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package checks
+
+import (
+	"fmt"
+
+	"github.com/csvlinter/csvlinter/internal/rules"
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+// TrailingDelimiter warns about files whose every line ends with a delimiter, which reads
+// as an empty, unnamed last column. The pattern is reported once for the file, at the end
+// of the input, rather than as a problem on every row.
+type TrailingDelimiter struct {
+	line     int // Line of the header
+	width    int
+	trailing bool // The header and every row so far end with an empty field
+}
+
+// NewTrailingDelimiter returns a trailing delimiter check.
+func NewTrailingDelimiter() *TrailingDelimiter {
+	return &TrailingDelimiter{}
+}
+
+// Advisory makes trailing delimiters warnings.
+func (t *TrailingDelimiter) Advisory() {}
+
+// Start looks for an empty last header.
+func (t *TrailingDelimiter) Start(headerLine int, headers []string) []validator.Finding {
+	t.line, t.width = headerLine, len(headers)
+	t.trailing = len(headers) > 1 && headers[len(headers)-1] == ""
+	return nil
+}
+
+// Row rules out the pattern at the first row with a last value.
+func (t *TrailingDelimiter) Row(lineNumber int, fields []string) []validator.Finding {
+	if t.trailing && fields[t.width-1] != "" {
+		t.trailing = false
+	}
+	return nil
+}
+
+// Finish reports the trailing delimiter when every line had one.
+func (t *TrailingDelimiter) Finish() []validator.Finding {
+	if !t.trailing {
+		return nil
+	}
+	return []validator.Finding{{
+		Severity:   validator.SeverityError,
+		Location:   validator.Location{Line: t.line, Column: t.width},
+		Message:    fmt.Sprintf("every line ends with a delimiter, which adds an empty, unnamed column %d (csvlinter fix removes it)", t.width),
+		Suggestion: "remove the delimiter at the end of every line, e.g. with csvlinter fix",
+		Type:       "structure",
+		RuleID:     rules.TrailingDelim,
+	}}
+}
@@ -0,0 +1,36 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+func TestTrailingDelimiter(t *testing.T) {
+	validate := func(input string) *validator.Results {
+		results, err := validator.NewWithOptions(strings.NewReader(input), validator.Options{
+			Delimiter: ",",
+			Checks:    []validator.Check{NewTrailingDelimiter()},
+		}).Validate()
+		if err != nil {
+			t.Fatalf("Validate failed: %v", err)
+		}
+		return results
+	}
+
+	results := validate("id,name,\n1,a,\n2,b,\n")
+	if w := results.Warnings; len(w) != 1 || w[0].Line != 1 || w[0].Column != 3 || w[0].RuleID != "STR007" {
+		t.Errorf("expected one trailing delimiter warning, got %+v", w)
+	}
+	if !results.Valid {
+		t.Error("expected a trailing delimiter to leave the file valid")
+	}
+
+	if w := validate("id,name,\n1,a,\n2,b,x\n").Warnings; len(w) != 0 {
+		t.Errorf("expected a last value to rule out the pattern, got %+v", w)
+	}
+	if w := validate("id,name\n1,\n").Warnings; len(w) != 0 {
+		t.Errorf("expected a named last column to be left alone, got %+v", w)
+	}
+}
@@ -0,0 +1,359 @@
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"container/heap"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/csvlinter/csvlinter/internal/rules"
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+// Unique check methods.
+const (
+	UniqueSort  = "sort"  // Keys in memory, spilled to sorted files when they do not fit
+	UniqueBloom = "bloom" // A Bloom filter in memory, candidates verified against keys on disk
+)
+
+// DefaultUniqueMemoryKeys is how many keys Unique keeps in memory, about 100 MB, before it
+// spills them to disk.
+const DefaultUniqueMemoryKeys = 1 << 21
+
+// uniqueEntry is a key's 128-bit hash and the line it was first seen on.
+type uniqueEntry struct {
+	hash [16]byte
+	line int
+}
+
+const uniqueEntrySize = 16 + 8
+
+// Unique asserts that no two rows share a key: the values of the given columns, or the
+// whole row, as uniqueItems would for the file as an array of rows. Keys are kept as
+// 128-bit hashes. Repeats of a key still in memory are reported as the rows are read;
+// once more keys than fit in memory have been seen, they are spilled to sorted files in
+// the temporary directory, and repeats across spilled keys are found by merging the files
+// at the end of the input. In Bloom filter mode (see NewUniqueBloom) only the keys that
+// may repeat are kept in memory.
+type Unique struct {
+	columns  []string // Key columns; the whole row when empty
+	maxKeys  int
+	expected int    // Keys the Bloom filter is sized for; 0 without a Bloom filter
+	dir      string // Directory for spilled keys; os.TempDir when empty
+
+	line    int // Line of the header, where errors about the rule are reported
+	indexes []int
+	keys    map[[16]byte]int // Hash -> first line, for keys not yet spilled
+	runs    []string         // Files of spilled keys, sorted by hash
+
+	bloom      *bloomFilter
+	keyFile    *os.File          // Every key and its line, in input order
+	keyOut     *bufio.Writer     // Buffers keyFile
+	candidates map[[16]byte]bool // Keys the Bloom filter may have seen before
+
+	deferred bool // Finish may report the row last given to Row
+}
+
+// NewUnique returns a check that the columns' values are unique across rows; with no
+// columns whole rows must be unique. maxKeys bounds the keys kept in memory, 0 for
+// DefaultUniqueMemoryKeys; dir is where keys are spilled, "" for the temporary directory.
+func NewUnique(columns []string, maxKeys int, dir string) (*Unique, error) {
+	if maxKeys < 0 {
+		return nil, fmt.Errorf("maximum keys in memory must not be negative")
+	}
+	if maxKeys == 0 {
+		maxKeys = DefaultUniqueMemoryKeys
+	}
+	return &Unique{columns: columns, maxKeys: maxKeys, dir: dir}, nil
+}
+
+// NewUniqueBloom returns a unique check for inputs with more keys than memory holds, such
+// as hundreds of millions of rows. A Bloom filter sized for expectedKeys, 0 for
+// DefaultBloomKeys, remembers the keys seen at about 10 bits each, and every key is
+// appended to a file in dir; rows whose key the filter may have seen are candidates,
+// verified against the file at the end of the input. More keys than expected only make
+// the filter less precise and the candidates more numerous.
+func NewUniqueBloom(columns []string, expectedKeys int, dir string) (*Unique, error) {
+	if expectedKeys < 0 {
+		return nil, fmt.Errorf("expected keys must not be negative")
+	}
+	if expectedKeys == 0 {
+		expectedKeys = DefaultBloomKeys
+	}
+	return &Unique{columns: columns, expected: expectedKeys, dir: dir}, nil
+}
+
+// Start locates the key columns; a missing column disables the check.
+func (u *Unique) Start(headerLine int, headers []string) []validator.Finding {
+	u.Close()
+	u.line = headerLine
+	u.keys = make(map[[16]byte]int)
+	u.indexes = u.indexes[:0]
+	if len(u.columns) == 0 {
+		for i := range headers {
+			u.indexes = append(u.indexes, i)
+		}
+		return u.startBloom()
+	}
+	for _, c := range u.columns {
+		i := slices.Index(headers, c)
+		if i < 0 {
+			u.indexes = nil
+			return []validator.Finding{{
+				Severity: validator.SeverityError,
+				Location: validator.Location{Line: headerLine},
+				Field:    c,
+				Message:  fmt.Sprintf("unique column '%s' not found in header", c),
+				Type:     "data",
+				RuleID:   rules.Unique,
+			}}
+		}
+		u.indexes = append(u.indexes, i)
+	}
+	return u.startBloom()
+}
+
+// Row reports a row whose key is in memory, and remembers the key otherwise.
+func (u *Unique) Row(lineNumber int, fields []string) []validator.Finding {
+	u.deferred = false
+	if u.indexes == nil {
+		return nil
+	}
+	h := fnv.New128a()
+	var length [8]byte
+	for _, i := range u.indexes {
+		// Length prefixes keep ("a,b", "c") and ("a", "b,c") apart
+		binary.BigEndian.PutUint64(length[:], uint64(len(fields[i])))
+		h.Write(length[:])
+		h.Write([]byte(fields[i]))
+	}
+	var key [16]byte
+	h.Sum(key[:0])
+	if u.bloom != nil {
+		return u.bloomRow(lineNumber, key)
+	}
+	if first, ok := u.keys[key]; ok {
+		return []validator.Finding{u.repeat(lineNumber, first, fields)}
+	}
+	u.keys[key] = lineNumber
+	// Rows read with keys on disk may repeat one of them; those read before cannot
+	u.deferred = len(u.runs) > 0
+	if len(u.keys) >= u.maxKeys {
+		if err := u.spill(); err != nil {
+			// Without the spilled keys repeats can no longer be told: report it once and stop
+			u.Close()
+			u.indexes, u.keys, u.deferred = nil, nil, false
+			return []validator.Finding{{Severity: validator.SeverityError, Location: validator.Location{Line: lineNumber}, Message: err.Error(), Type: "data", RuleID: rules.Unique}}
+		}
+	}
+	return nil
+}
+
+// Deferred reports whether Finish may report the row last given to Row: a candidate of
+// the Bloom filter, or a row read once keys were spilled.
+func (u *Unique) Deferred() bool {
+	return u.deferred
+}
+
+// uniqueSuggestion tells how to fix a repeated row.
+const uniqueSuggestion = "remove the duplicate row, or correct its key"
+
+// repeat builds the finding for a row repeating the key of line first.
+func (u *Unique) repeat(line, first int, fields []string) validator.Finding {
+	e := validator.Finding{Severity: validator.SeverityError, Location: validator.Location{Line: line}, Type: "data", RuleID: rules.Unique, Suggestion: uniqueSuggestion}
+	switch {
+	case len(u.columns) == 0:
+		e.Message = fmt.Sprintf("row repeats line %d", first)
+	case len(u.columns) == 1:
+		i := u.indexes[0]
+		e.Column, e.Field, e.Value = i+1, u.columns[0], fields[i]
+		e.Message = fmt.Sprintf("%s %s repeats line %d", u.columns[0], fields[i], first)
+	default:
+		values := make([]string, len(u.indexes))
+		for j, i := range u.indexes {
+			values[j] = fields[i]
+		}
+		e.Message = fmt.Sprintf("(%s) (%s) repeats line %d", strings.Join(u.columns, ", "), strings.Join(values, ", "), first)
+	}
+	return e
+}
+
+// spill writes the keys in memory to a sorted run file and forgets them. A run that cannot
+// be written is removed.
+func (u *Unique) spill() error {
+	entries := make([]uniqueEntry, 0, len(u.keys))
+	for hash, line := range u.keys {
+		entries = append(entries, uniqueEntry{hash, line})
+	}
+	sort.Slice(entries, func(i, j int) bool { return bytes.Compare(entries[i].hash[:], entries[j].hash[:]) < 0 })
+
+	f, err := os.CreateTemp(u.dir, "csvlinter-unique-*")
+	if err != nil {
+		return fmt.Errorf("unique rule cannot spill keys to disk: %w", err)
+	}
+	w := bufio.NewWriter(f)
+	var buf [uniqueEntrySize]byte
+	for _, e := range entries {
+		copy(buf[:16], e.hash[:])
+		binary.BigEndian.PutUint64(buf[16:], uint64(e.line))
+		w.Write(buf[:])
+	}
+	err = w.Flush()
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("unique rule cannot spill keys to disk: %w", err)
+	}
+	u.runs = append(u.runs, f.Name())
+	u.keys = make(map[[16]byte]int)
+	return nil
+}
+
+// Finish merges the spilled keys, if any, or verifies the Bloom filter's candidates, and
+// reports the repeats found on disk. Those findings carry no values: the rows were read
+// long before. The validator fills in their offset, key and columns, as the rows were
+// Deferred.
+func (u *Unique) Finish() []validator.Finding {
+	defer u.Close()
+	if u.bloom != nil {
+		return u.verify()
+	}
+	if len(u.runs) == 0 {
+		return nil
+	}
+	if err := u.spill(); err != nil {
+		return []validator.Finding{{Severity: validator.SeverityError, Location: validator.Location{Line: u.line}, Message: err.Error(), Type: "data", RuleID: rules.Unique}}
+	}
+	var errs []validator.Finding
+	err := mergeRuns(u.runs, func(line, first int) {
+		errs = append(errs, u.storedRepeat(line, first))
+	})
+	if err != nil {
+		errs = append(errs, validator.Finding{Severity: validator.SeverityError, Location: validator.Location{Line: u.line}, Message: fmt.Sprintf("unique rule cannot read spilled keys: %v", err), Type: "data", RuleID: rules.Unique})
+	}
+	return errs
+}
+
+// storedRepeat builds the finding for a repeat found among keys stored on disk, whose
+// values are no longer known.
+func (u *Unique) storedRepeat(line, first int) validator.Finding {
+	e := validator.Finding{Severity: validator.SeverityError, Location: validator.Location{Line: line}, Type: "data", RuleID: rules.Unique, Suggestion: uniqueSuggestion}
+	switch len(u.columns) {
+	case 0:
+		e.Message = fmt.Sprintf("row repeats line %d", first)
+	case 1:
+		e.Column, e.Field = u.indexes[0]+1, u.columns[0]
+		e.Message = fmt.Sprintf("%s value repeats line %d", u.columns[0], first)
+	default:
+		e.Message = fmt.Sprintf("(%s) key repeats line %d", strings.Join(u.columns, ", "), first)
+	}
+	return e
+}
+
+// Close removes the spilled keys. Validation closes checks even when it stops early.
+func (u *Unique) Close() error {
+	for _, name := range u.runs {
+		os.Remove(name)
+	}
+	u.runs = nil
+	if u.keyFile != nil {
+		u.keyFile.Close()
+		os.Remove(u.keyFile.Name())
+		u.keyFile = nil
+	}
+	return nil
+}
+
+// mergeRuns merges sorted run files and calls repeat for every entry whose hash an entry
+// with a smaller line already has.
+func mergeRuns(runs []string, repeat func(line, first int)) error {
+	h := &runHeap{}
+	for _, name := range runs {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r := &runReader{r: bufio.NewReader(f)}
+		if ok, err := r.next(); err != nil {
+			return err
+		} else if ok {
+			h.readers = append(h.readers, r)
+		}
+	}
+	heap.Init(h)
+
+	var group []int // Lines of the current hash
+	var current [16]byte
+	flush := func() {
+		slices.Sort(group)
+		for _, line := range group[min(1, len(group)):] {
+			repeat(line, group[0])
+		}
+		group = group[:0]
+	}
+	for h.Len() > 0 {
+		r := h.readers[0]
+		if len(group) > 0 && r.entry.hash != current {
+			flush()
+		}
+		current = r.entry.hash
+		group = append(group, r.entry.line)
+		ok, err := r.next()
+		if err != nil {
+			return err
+		}
+		if ok {
+			heap.Fix(h, 0)
+		} else {
+			heap.Pop(h)
+		}
+	}
+	flush()
+	return nil
+}
+
+// runReader reads the entries of a run file.
+type runReader struct {
+	r     *bufio.Reader
+	entry uniqueEntry
+}
+
+// next reads the next entry, reporting false at the end of the run.
+func (r *runReader) next() (bool, error) {
+	var buf [uniqueEntrySize]byte
+	if _, err := io.ReadFull(r.r, buf[:]); err == io.EOF {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	copy(r.entry.hash[:], buf[:16])
+	r.entry.line = int(binary.BigEndian.Uint64(buf[16:]))
+	return true, nil
+}
+
+// runHeap orders run readers by their current hash.
+type runHeap struct {
+	readers []*runReader
+}
+
+func (h *runHeap) Len() int { return len(h.readers) }
+func (h *runHeap) Less(i, j int) bool {
+	return bytes.Compare(h.readers[i].entry.hash[:], h.readers[j].entry.hash[:]) < 0
+}
+func (h *runHeap) Swap(i, j int) { h.readers[i], h.readers[j] = h.readers[j], h.readers[i] }
+func (h *runHeap) Push(x any)    { h.readers = append(h.readers, x.(*runReader)) }
+func (h *runHeap) Pop() any {
+	r := h.readers[len(h.readers)-1]
+	h.readers = h.readers[:len(h.readers)-1]
+	return r
+}
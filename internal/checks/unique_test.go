@@ -0,0 +1,174 @@
+package checks
+
+import (
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+func validateUnique(t *testing.T, check *Unique, input string, failFast bool) []validator.Finding {
+	t.Helper()
+	results, err := validator.NewWithOptions(strings.NewReader(input), validator.Options{
+		Delimiter: ",",
+		Checks:    []validator.Check{check},
+		FailFast:  failFast,
+	}).Validate()
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	return results.Errors
+}
+
+func TestUnique(t *testing.T) {
+	input := "id,name\n1,a\n2,b\n1,c\n3,b\n1,a\n"
+
+	check, _ := NewUnique([]string{"id"}, 0, "")
+	errs := validateUnique(t, check, input, false)
+	if len(errs) != 2 || errs[0].Line != 4 || errs[0].Message != "id 1 repeats line 2" ||
+		errs[0].Column != 1 || errs[0].Value != "1" || errs[0].RuleID != "DAT010" || errs[1].Line != 6 {
+		t.Errorf("expected ids repeated on lines 4 and 6, got %+v", errs)
+	}
+
+	check, _ = NewUnique([]string{"id", "name"}, 0, "")
+	errs = validateUnique(t, check, input, false)
+	if len(errs) != 1 || errs[0].Message != "(id, name) (1, a) repeats line 2" {
+		t.Errorf("expected one repeated key, got %+v", errs)
+	}
+
+	check, _ = NewUnique(nil, 0, "")
+	errs = validateUnique(t, check, "a,b\n\"x,y\",z\nx,\"y,z\"\n\"x,y\",z\n", false)
+	if len(errs) != 1 || errs[0].Line != 4 || errs[0].Message != "row repeats line 2" {
+		t.Errorf("expected only the identical row to repeat, got %+v", errs)
+	}
+
+	check, _ = NewUnique([]string{"sku"}, 0, "")
+	if errs := check.Start(1, []string{"id"}); len(errs) != 1 || errs[0].Field != "sku" {
+		t.Errorf("expected a missing sku column, got %+v", errs)
+	}
+	if _, err := NewUnique(nil, -1, ""); err == nil {
+		t.Error("expected negative memory keys to be rejected")
+	}
+}
+
+func TestUniqueSpillsToDisk(t *testing.T) {
+	dir := t.TempDir()
+	check, _ := NewUnique([]string{"id"}, 2, dir)
+	// Two keys fit in memory: 1 and 2 are spilled before their repeats are read, which
+	// are then found by merging and reported without their values
+	input := "id\n1\n2\n3\n3\n1\n4\n2\n1\n"
+	errs := validateUnique(t, check, input, false)
+	var got []string
+	for _, e := range errs {
+		got = append(got, e.Message+" @"+strconv.Itoa(e.Line))
+	}
+	sort.Strings(got)
+	want := []string{"id 3 repeats line 4 @5", "id value repeats line 2 @6", "id value repeats line 2 @9", "id value repeats line 3 @8"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("expected spilled keys to be removed, found %d files", len(entries))
+	}
+
+	// Stopping early skips Finish, but the spilled keys are still removed
+	check, _ = NewUnique([]string{"id"}, 1, dir)
+	validateUnique(t, check, input, true)
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("expected spilled keys to be removed after fail fast, found %d files", len(entries))
+	}
+}
+
+func TestUniqueSpillFails(t *testing.T) {
+	// Keys that cannot be spilled are reported once, and the check stops
+	check, _ := NewUnique([]string{"id"}, 1, filepath.Join(t.TempDir(), "missing"))
+	errs := validateUnique(t, check, "id\n1\n2\n1\n2\n", false)
+	if len(errs) != 1 || errs[0].Line != 2 || !strings.Contains(errs[0].Message, "cannot spill keys") {
+		t.Errorf("expected one spill error on line 2, got %+v", errs)
+	}
+}
+
+func TestUniqueBloom(t *testing.T) {
+	dir := t.TempDir()
+	// A filter sized for 2 keys flags most of 200 as candidates; verification drops them
+	var input strings.Builder
+	input.WriteString("id,name\n")
+	for i := 0; i < 200; i++ {
+		fmt.Fprintf(&input, "%d,n%d\n", i, i)
+	}
+	input.WriteString("7,x\n150,y\n7,z\n")
+	check, _ := NewUniqueBloom([]string{"id"}, 2, dir)
+	errs := validateUnique(t, check, input.String(), false)
+	want := []string{"202: id value repeats line 9", "203: id value repeats line 152", "204: id value repeats line 9"}
+	var got []string
+	for _, e := range errs {
+		got = append(got, strconv.Itoa(e.Line)+": "+e.Message)
+	}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("expected stored keys to be removed, found %d files", len(entries))
+	}
+	if _, err := NewUniqueBloom(nil, -1, ""); err == nil {
+		t.Error("expected negative expected keys to be rejected")
+	}
+}
+
+func TestBloomFilter(t *testing.T) {
+	b := newBloomFilter(10_000)
+	key := func(i int) [16]byte {
+		var k [16]byte
+		h := fnv.New128a()
+		fmt.Fprint(h, i)
+		h.Sum(k[:0])
+		return k
+	}
+	for i := 0; i < 10_000; i++ {
+		b.add(key(i))
+	}
+	for i := 0; i < 10_000; i++ {
+		if !b.add(key(i)) {
+			t.Fatalf("key %d was added but not found", i)
+		}
+	}
+	positives := 0
+	for i := 10_000; i < 11_000; i++ {
+		if b.add(key(i)) {
+			positives++
+		}
+	}
+	// New keys also fill the filter, so allow more than the nominal 1%
+	if positives > 30 {
+		t.Errorf("expected about 1%% false positives, got %d of 1000", positives)
+	}
+}
+
+func TestUniqueLocatesRepeatsFoundOnDisk(t *testing.T) {
+	// Repeats found by merging spilled keys, or by verifying Bloom filter candidates, carry
+	// the offset, key and included columns of their row like those found as rows are read
+	input := "id,name\n1,a\n2,b\n1,c\n"
+	sorted, _ := NewUnique([]string{"id"}, 1, t.TempDir())
+	bloom, _ := NewUniqueBloom([]string{"id"}, 1, t.TempDir())
+	for _, check := range []*Unique{sorted, bloom} {
+		results, err := validator.NewWithOptions(strings.NewReader(input), validator.Options{
+			Delimiter:      ",",
+			Checks:         []validator.Check{check},
+			KeyColumns:     []string{"id"},
+			IncludeColumns: []string{"name"},
+		}).Validate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		errs := results.Errors
+		if len(errs) != 1 || errs[0].Line != 4 || errs[0].ByteOffset != 16 || errs[0].Key != "1" || errs[0].Columns["name"] != "c" {
+			t.Errorf("expected line 4 at byte 16 with key 1 and name c, got %+v", errs)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package checks
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/csvlinter/csvlinter/internal/rules"
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+// unitValue matches a number with a unit before or after it, such as "USD 10.50", "€5",
+// "10.5 kg" or "1,200m". Units are runs of anything but digits, spaces, signs, dots and
+// commas.
+var unitValue = regexp.MustCompile(`^(?:([^\d\s+\-.,]+)\s*)?([+-]?(?:\d{1,3}(?:,\d{3})+|\d+)(?:\.\d+)?|[+-]?\.\d+)(?:\s*([^\d\s+\-.,]+))?$`)
+
+// Units checks a column of numbers written with a currency or unit: each non-empty value
+// must parse as a number with exactly one unit, the unit must be allowed and the number
+// must be within the bounds.
+type Units struct {
+	column   string
+	units    []string // Allowed units; any unit when empty
+	min, max *float64
+
+	index int
+}
+
+// NewUnits returns a check of column. Units are compared exactly, so "kg" and "KG" differ.
+func NewUnits(column string, units []string, min, max *float64) (*Units, error) {
+	if column == "" {
+		return nil, fmt.Errorf("column is required")
+	}
+	if min != nil && max != nil && *min > *max {
+		return nil, fmt.Errorf("minimum %s is above maximum %s", formatNumber(*min), formatNumber(*max))
+	}
+	return &Units{column: column, units: units, min: min, max: max}, nil
+}
+
+// ParseUnit splits a value such as "USD 10.50" or "10.5 kg" into its number and unit.
+func ParseUnit(value string) (number float64, unit string, ok bool) {
+	m := unitValue.FindStringSubmatch(strings.TrimSpace(value))
+	if m == nil || (m[1] == "") == (m[3] == "") {
+		return 0, "", false
+	}
+	f, err := strconv.ParseFloat(strings.ReplaceAll(m[2], ",", ""), 64)
+	if err != nil {
+		return 0, "", false
+	}
+	return f, m[1] + m[3], true
+}
+
+// Start locates the column; a missing column disables the check.
+func (u *Units) Start(headerLine int, headers []string) []validator.Finding {
+	u.index = slices.Index(headers, u.column)
+	if u.index >= 0 {
+		return nil
+	}
+	return []validator.Finding{{
+		Severity: validator.SeverityError,
+		Location: validator.Location{Line: headerLine},
+		Field:    u.column,
+		Message:  fmt.Sprintf("unit column '%s' not found in header", u.column),
+		Type:     "data",
+		RuleID:   rules.Unit,
+	}}
+}
+
+// Row parses the row's value and checks its unit and bounds.
+func (u *Units) Row(lineNumber int, fields []string) []validator.Finding {
+	if u.index < 0 || strings.TrimSpace(fields[u.index]) == "" {
+		return nil
+	}
+	value := fields[u.index]
+	var message string
+	n, unit, ok := ParseUnit(value)
+	switch {
+	case !ok:
+		message = fmt.Sprintf("%s '%s' is not a number with a unit", u.column, value)
+	case len(u.units) > 0 && !slices.Contains(u.units, unit):
+		message = fmt.Sprintf("%s '%s' has unit %s, want %s", u.column, value, unit, strings.Join(u.units, ", "))
+	case u.min != nil && n < *u.min:
+		message = fmt.Sprintf("%s '%s' is below the minimum %s", u.column, value, formatNumber(*u.min))
+	case u.max != nil && n > *u.max:
+		message = fmt.Sprintf("%s '%s' is above the maximum %s", u.column, value, formatNumber(*u.max))
+	default:
+		return nil
+	}
+	return []validator.Finding{{
+		Severity: validator.SeverityError,
+		Location: validator.Location{Line: lineNumber, Column: u.index + 1},
+		Field:    u.column,
+		Message:  message,
+		Value:    value,
+		Type:     "data",
+		RuleID:   rules.Unit,
+	}}
+}
+
+// Finish has nothing more to report.
+func (u *Units) Finish() []validator.Finding {
+	return nil
+}
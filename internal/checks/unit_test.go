@@ -0,0 +1,68 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+func TestParseUnit(t *testing.T) {
+	tests := []struct {
+		value  string
+		number float64
+		unit   string
+		ok     bool
+	}{
+		{"USD 10.50", 10.5, "USD", true},
+		{"€5", 5, "€", true},
+		{"10.5 kg", 10.5, "kg", true},
+		{"1,200m", 1200, "m", true},
+		{"-3 °C", -3, "°C", true},
+		{" .5 l ", 0.5, "l", true},
+		{"10.50", 0, "", false},
+		{"USD 10 EUR", 0, "", false},
+		{"USD ten", 0, "", false},
+		{"1,20 kg", 0, "", false},
+	}
+	for _, tt := range tests {
+		n, unit, ok := ParseUnit(tt.value)
+		if ok != tt.ok || ok && (n != tt.number || unit != tt.unit) {
+			t.Errorf("ParseUnit(%q) = %v, %q, %v; want %v, %q, %v", tt.value, n, unit, ok, tt.number, tt.unit, tt.ok)
+		}
+	}
+}
+
+func TestUnits(t *testing.T) {
+	min, max := 0.0, 1000.0
+	check, err := NewUnits("price", []string{"USD", "EUR"}, &min, &max)
+	if err != nil {
+		t.Fatalf("NewUnits failed: %v", err)
+	}
+	input := "sku,price\nA,USD 10.50\nB,\nC,GBP 3\nD,EUR 1500\nE,10\nF,USD -1\n"
+	results, err := validator.NewWithOptions(strings.NewReader(input), validator.Options{
+		Delimiter: ",",
+		Checks:    []validator.Check{check},
+	}).Validate()
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	want := []string{
+		"price 'GBP 3' has unit GBP, want USD, EUR",
+		"price 'EUR 1500' is above the maximum 1000",
+		"price '10' is not a number with a unit",
+		"price 'USD -1' is below the minimum 0",
+	}
+	if len(results.Errors) != len(want) {
+		t.Fatalf("expected %d errors, got %+v", len(want), results.Errors)
+	}
+	for i, w := range want {
+		if e := results.Errors[i]; e.Message != w || e.RuleID != "DAT007" || e.Field != "price" {
+			t.Errorf("error %d: expected %q, got %+v", i, w, e)
+		}
+	}
+
+	if _, err := NewUnits("price", nil, &max, &min); err == nil {
+		t.Error("expected an error for a minimum above the maximum")
+	}
+}
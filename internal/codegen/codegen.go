@@ -0,0 +1,164 @@
+// Package codegen generates Go structs and TypeScript interfaces for CSV rows from their
+// JSON Schema.
+package codegen
+
+import (
+	"fmt"
+	"go/format"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/csvlinter/csvlinter/internal/schema"
+)
+
+// Languages are the supported output languages.
+var Languages = []string{"go", "typescript"}
+
+// Options names the generated type.
+type Options struct {
+	Language string // "go" or "typescript" ("ts")
+	Name     string // Type name, e.g. "Order"
+	Package  string // Go package name
+}
+
+// Generate returns a type with one field per column, in the given order. Field types
+// come from the schema (see schema.Describe); columns it does not describe are strings.
+func Generate(columns []string, desc *schema.Description, opts Options) (string, error) {
+	switch strings.ToLower(opts.Language) {
+	case "go":
+		return goStruct(columns, desc, opts)
+	case "typescript", "ts":
+		return tsInterface(columns, desc, opts), nil
+	}
+	return "", fmt.Errorf("unknown language %q (supported: %s)", opts.Language, strings.Join(Languages, ", "))
+}
+
+// goInitialisms are written in capitals in Go identifiers.
+var goInitialisms = map[string]bool{
+	"api": true, "id": true, "ip": true, "json": true, "sku": true, "sql": true,
+	"uri": true, "url": true, "utc": true, "uuid": true, "vat": true,
+}
+
+// goStruct renders a struct with csv and json tags. Optional columns and columns that
+// allow null become pointers, except strings, where empty already means absent.
+func goStruct(columns []string, desc *schema.Description, opts Options) (string, error) {
+	var b strings.Builder
+	b.WriteString("// Code generated by csvlinter codegen; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", opts.Package)
+	usesTime := false
+	var fields strings.Builder
+	used := make(map[string]bool, len(columns))
+	for _, name := range columns {
+		c := desc.Column(name)
+		var typ string
+		switch {
+		case c.Type == "integer":
+			typ = "int64"
+		case c.Type == "number":
+			typ = "float64"
+		case c.Type == "boolean":
+			typ = "bool"
+		case c.Format == "date-time":
+			typ = "time.Time"
+			usesTime = true
+		default:
+			typ = "string"
+		}
+		optional := !c.Required || c.Nullable
+		if optional && typ != "string" {
+			typ = "*" + typ
+		}
+		jsonTag := name
+		if optional {
+			jsonTag += ",omitempty"
+		}
+		field := unique(TypeName(name), used)
+		if c.Format != "" && c.Format != "date-time" {
+			fmt.Fprintf(&fields, "\t// Format: %s\n", c.Format)
+		}
+		fmt.Fprintf(&fields, "\t%s %s `csv:%s json:%s`\n", field, typ, strconv.Quote(name), strconv.Quote(jsonTag))
+	}
+	if usesTime {
+		b.WriteString("import \"time\"\n\n")
+	}
+	fmt.Fprintf(&b, "// %s is a row of the CSV file.\n", opts.Name)
+	fmt.Fprintf(&b, "type %s struct {\n%s}\n", opts.Name, fields.String())
+	src, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return "", fmt.Errorf("formatting Go source: %w", err)
+	}
+	return string(src), nil
+}
+
+// TypeName turns a name into an exported identifier, valid in Go and TypeScript:
+// "order_id" becomes OrderID and "2nd line" X2ndLine.
+func TypeName(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var b strings.Builder
+	for _, w := range words {
+		if goInitialisms[strings.ToLower(w)] {
+			b.WriteString(strings.ToUpper(w))
+			continue
+		}
+		r := []rune(w)
+		b.WriteString(string(unicode.ToUpper(r[0])) + string(r[1:]))
+	}
+	id := b.String()
+	if id == "" {
+		return "Field"
+	}
+	if first := []rune(id)[0]; !unicode.IsLetter(first) || !unicode.IsUpper(first) {
+		id = "X" + id
+	}
+	return id
+}
+
+// unique returns id, or id with a number appended when it is already used.
+func unique(id string, used map[string]bool) string {
+	candidate := id
+	for n := 2; used[candidate]; n++ {
+		candidate = id + strconv.Itoa(n)
+	}
+	used[candidate] = true
+	return candidate
+}
+
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// tsInterface renders an exported interface. Optional columns are optional properties and
+// columns that allow null also accept null.
+func tsInterface(columns []string, desc *schema.Description, opts Options) string {
+	var b strings.Builder
+	b.WriteString("// Code generated by csvlinter codegen; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "/** A row of the CSV file. */\nexport interface %s {\n", opts.Name)
+	for _, name := range columns {
+		c := desc.Column(name)
+		typ := "string"
+		switch c.Type {
+		case "integer", "number":
+			typ = "number"
+		case "boolean":
+			typ = "boolean"
+		}
+		if c.Nullable {
+			typ += " | null"
+		}
+		key := name
+		if !tsIdentifier.MatchString(name) {
+			key = strconv.Quote(name)
+		}
+		if !c.Required {
+			key += "?"
+		}
+		if c.Format != "" {
+			fmt.Fprintf(&b, "  /** Format: %s */\n", c.Format)
+		}
+		fmt.Fprintf(&b, "  %s: %s;\n", key, typ)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
@@ -0,0 +1,76 @@
+package codegen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/schema"
+)
+
+func describe(t *testing.T, schemaJSON string) *schema.Description {
+	t.Helper()
+	d, err := schema.Describe([]byte(schemaJSON))
+	if err != nil {
+		t.Fatalf("Describe failed: %v", err)
+	}
+	return d
+}
+
+func TestGenerateGo(t *testing.T) {
+	desc := describe(t, `{
+  "required": ["order_id", "placed"],
+  "properties": {
+    "order_id": {"type": "integer"},
+    "total": {"type": "number"},
+    "placed": {"type": "string", "format": "date-time"},
+    "email": {"type": "string", "format": "email"}
+  }
+}`)
+	got, err := Generate([]string{"order_id", "total", "placed", "email", "order id"}, desc, Options{Language: "go", Name: "Order", Package: "model"})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	want := "// Code generated by csvlinter codegen; DO NOT EDIT.\n\npackage model\n\nimport \"time\"\n\n" +
+		"// Order is a row of the CSV file.\ntype Order struct {\n" +
+		"\tOrderID int64     `csv:\"order_id\" json:\"order_id\"`\n" +
+		"\tTotal   *float64  `csv:\"total\" json:\"total,omitempty\"`\n" +
+		"\tPlaced  time.Time `csv:\"placed\" json:\"placed\"`\n" +
+		"\t// Format: email\n" +
+		"\tEmail    string `csv:\"email\" json:\"email,omitempty\"`\n" +
+		"\tOrderID2 string `csv:\"order id\" json:\"order id,omitempty\"`\n" +
+		"}\n"
+	if got != want {
+		t.Errorf("Generate go =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGenerateTypeScript(t *testing.T) {
+	desc := describe(t, `{"required": ["id"], "properties": {"id": {"type": "integer"}, "note": {"type": ["string", "null"]}}}`)
+	got, err := Generate([]string{"id", "note", "unit price"}, desc, Options{Language: "ts", Name: "Row"})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	for _, want := range []string{"export interface Row {", "  id: number;", "  note?: string | null;", `  "unit price"?: string;`} {
+		if !strings.Contains(got, want+"\n") {
+			t.Errorf("Expected %q in\n%s", want, got)
+		}
+	}
+
+	if _, err := Generate(nil, desc, Options{Language: "rust"}); err == nil {
+		t.Error("Expected an error for an unknown language")
+	}
+}
+
+func TestTypeName(t *testing.T) {
+	cases := map[string]string{
+		"order_id":    "OrderID",
+		"2nd line":    "X2ndLine",
+		"daily-sales": "DailySales",
+		"--":          "Field",
+	}
+	for in, want := range cases {
+		if got := TypeName(in); got != want {
+			t.Errorf("TypeName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,386 @@
+// Package config loads project-level csvlinter settings from a .csvlinter.yml file.
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/csvlinter/csvlinter/internal/project"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileNames are the config file names looked up, in order, in each directory.
+var FileNames = []string{".csvlinter.yml", ".csvlinter.yaml"}
+
+// Config holds the settings read from a config file. Command-line flags take
+// precedence over every value here.
+type Config struct {
+	Schemas       []string            `yaml:"schemas"`            // Extra schemas every file is also validated against, relative to the config file
+	FileSchema    string              `yaml:"file_schema"`        // Schema for each whole file as an array of row objects, relative to the config file
+	SchemaLookup  []string            `yaml:"schema_lookup"`      // Where to look for the schema of a CSV file, e.g. schemas/{name}.json, before <name>.schema.json
+	RootMarkers   []string            `yaml:"root_markers"`       // Files or directories marking the project root, where the searches for schemas and .csvlinterignore files stop, e.g. go.mod
+	Symlinks      string              `yaml:"schema_symlinks"`    // Paths of files under symlinked directories that schema searches walk up: both, link or real
+	IgnoreCase    bool                `yaml:"schema_ignore_case"` // Find schema files whose names differ from the looked-up ones in case only
+	Discriminator Discriminator       `yaml:"discriminator"`
+	Rules         Rules               `yaml:"rules"`
+	Envelope      Envelope            `yaml:"envelope"`
+	Target        string              `yaml:"target"`     // Database files must load into: postgres, bigquery or snowflake
+	Transforms    map[string][]string `yaml:"transforms"` // Column -> steps rewriting its values before validation
+	Formats       []string            `yaml:"formats"`    // Registered formats asserted by the "format" keyword of schemas, e.g. iban
+	Types         map[string]string   `yaml:"types"`      // Column -> type values are converted to before schema validation: int, float, bool or string
+	SepLine       string              `yaml:"sep_line"`   // Excel "sep=;" first lines: allow, warn or error
+	Quote         string              `yaml:"quote"`      // Quote character, e.g. "'", or none for files that never quote
+	Escape        string              `yaml:"escape"`     // Character escaping delimiters, quotes and line breaks in fields, e.g. "\\"
+	Quoting       string              `yaml:"quoting"`    // Quoting policy checked by validate and written by fix: minimal, all, nonnumeric or consistent
+	Ragged        Ragged              `yaml:"ragged"`
+	RequireData   bool                `yaml:"require_data"` // Files with a header but no data rows fail instead of warning
+	Chunked       bool                `yaml:"chunked"`      // Files concatenate chunks that each start with the header
+	Notify        Notify              `yaml:"notify"`
+	Metrics       Metrics             `yaml:"metrics"`
+	Redact        Redact              `yaml:"redact"`
+	Drift         Drift               `yaml:"drift"`
+	Empty         Empty               `yaml:"empty"`
+	Theme         Theme               `yaml:"theme"`
+	Lang          string              `yaml:"lang"`     // Language of pretty output: en (the default), de, fr or ja
+	Messages      map[string]string   `yaml:"messages"` // Rule ID -> template replacing the message of its findings, e.g. to link a runbook
+
+	// Path is the file the config was loaded from, the deepest one for nested files;
+	// empty for the zero config.
+	Path string `yaml:"-"`
+	// Files are the files merged into the config, the outermost first.
+	Files []string `yaml:"-"`
+}
+
+// Discriminator validates rows against a schema chosen by the value of a column, for
+// files that mix record types.
+type Discriminator struct {
+	Column  string            `yaml:"column"`  // Column holding the record type, e.g. "type"
+	Schemas map[string]string `yaml:"schemas"` // Record type -> schema path, relative to the config file
+}
+
+// Ragged configures rows whose number of fields differs from the header's.
+type Ragged struct {
+	Short string `yaml:"short"` // Fewer fields: error (default), warn or pad
+	Long  string `yaml:"long"`  // More fields: error (default), warn or ignore
+}
+
+// Envelope configures header and trailer records around the data, recognized by their
+// first field.
+type Envelope struct {
+	Header         string `yaml:"header"`          // First field of the header record before the column header
+	Trailer        string `yaml:"trailer"`         // First field of the trailer record after the data
+	CountField     int    `yaml:"count_field"`     // 1-based trailer field declaring the number of data rows
+	ChecksumField  int    `yaml:"checksum_field"`  // 1-based trailer field declaring the sum of checksum_column
+	ChecksumColumn string `yaml:"checksum_column"` // Column summed for checksum_field
+}
+
+// Rules configures checks across rows.
+type Rules struct {
+	Groups       []GroupRule        `yaml:"groups"`
+	Sorted       []SortRule         `yaml:"sorted"`
+	Expressions  []ExpressionRule   `yaml:"expressions"`
+	Outliers     []OutlierRule      `yaml:"outliers"`
+	Geo          []GeoRule          `yaml:"geo"`
+	Units        []UnitRule         `yaml:"units"`
+	Scripts      []ScriptRule       `yaml:"scripts"`
+	RequiredIf   []RequiredIfRule   `yaml:"required_if"`
+	Unique       []UniqueRule       `yaml:"unique"`
+	Completeness []CompletenessRule `yaml:"completeness"`
+}
+
+// ExpressionRule asserts a comparison between expressions over the columns of each row.
+type ExpressionRule struct {
+	Assert    string  `yaml:"assert"`    // e.g. "total == quantity * unit_price"
+	Tolerance float64 `yaml:"tolerance"` // Largest difference still counted as equal
+}
+
+// OutlierRule reports numeric values far from the rest of their column as warnings.
+type OutlierRule struct {
+	Method    string   `yaml:"method"`    // "iqr" (default) or "zscore"
+	Threshold float64  `yaml:"threshold"` // Interquartile ranges or standard deviations (0 = 1.5 or 3)
+	Columns   []string `yaml:"columns"`   // Checked columns; every numeric column when empty
+}
+
+// GeoRule checks coordinate and geometry columns.
+type GeoRule struct {
+	Latitude  string `yaml:"latitude"`  // With longitude: within ±90, and set exactly when longitude is
+	Longitude string `yaml:"longitude"` // Within ±180
+	WKT       string `yaml:"wkt"`       // Column of WKT geometries, e.g. POINT (30 10)
+	GeoJSON   string `yaml:"geojson"`   // Column of GeoJSON geometries or features
+}
+
+// UnitRule checks a column of numbers written with a currency or unit, such as "USD 10.50"
+// or "10.5 kg".
+type UnitRule struct {
+	Column  string   `yaml:"column"`
+	Units   []string `yaml:"units"`   // Allowed units; any unit when empty
+	Minimum *float64 `yaml:"minimum"` // Smallest allowed number, whatever the unit
+	Maximum *float64 `yaml:"maximum"`
+}
+
+// ScriptRule checks that free-text columns are written in the allowed scripts.
+type ScriptRule struct {
+	Columns []string `yaml:"columns"`
+	Scripts []string `yaml:"scripts"`  // Unicode script names, e.g. Latin
+	NoEmoji bool     `yaml:"no_emoji"` // Reject emoji
+}
+
+// RequiredIfRule requires columns on the rows matching a condition.
+type RequiredIfRule struct {
+	When    map[string]string `yaml:"when"`    // Column -> value, e.g. {status: shipped}
+	Require []string          `yaml:"require"` // Columns that must not be empty on matching rows
+}
+
+// UniqueRule asserts that a key does not repeat across rows.
+type UniqueRule struct {
+	Columns      []string `yaml:"columns"`       // Key columns; whole rows when empty
+	Method       string   `yaml:"method"`        // sort (default) or bloom
+	MemoryKeys   int      `yaml:"memory_keys"`   // Keys kept in memory before spilling to disk, 0 for the default
+	ExpectedKeys int      `yaml:"expected_keys"` // Keys the bloom method's filter is sized for, 0 for the default
+}
+
+// CompletenessRule asserts that a column is non-empty in a share of the rows.
+type CompletenessRule struct {
+	Column     string  `yaml:"column"`
+	MinPercent float64 `yaml:"min_percent"` // e.g. 99
+}
+
+// SortRule asserts that a column is in order.
+type SortRule struct {
+	Column string `yaml:"column"`
+	Order  string `yaml:"order"`  // "asc" (default) or "desc"
+	Unique bool   `yaml:"unique"` // Consecutive values must differ
+}
+
+// GroupRule asserts how many rows of each group, formed by rows sharing the value of By,
+// match Where. Count sets Min and Max together; without any bound at least one row of
+// each group must match.
+type GroupRule struct {
+	By    string            `yaml:"by"`    // Key column, e.g. order_id
+	Where map[string]string `yaml:"where"` // Column -> value a row must have to be counted; all rows when empty
+	Count *int              `yaml:"count"`
+	Min   *int              `yaml:"min"`
+	Max   *int              `yaml:"max"`
+}
+
+// Bounds returns the minimum and maximum matching rows per group; max is -1 when there is
+// no upper bound.
+func (g GroupRule) Bounds() (min, max int) {
+	if g.Count != nil {
+		return *g.Count, *g.Count
+	}
+	if g.Min == nil && g.Max == nil {
+		return 1, -1
+	}
+	min, max = 0, -1
+	if g.Min != nil {
+		min = *g.Min
+	}
+	if g.Max != nil {
+		max = *g.Max
+	}
+	return min, max
+}
+
+// Notify configures post-validation notifications.
+type Notify struct {
+	Webhook string `yaml:"webhook"` // URL that receives a JSON summary after each run
+}
+
+// Theme customizes the colors and symbols of pretty and compact output.
+type Theme struct {
+	Name    string `yaml:"name"`  // classic (default), minimal or emoji-free
+	Error   string `yaml:"error"` // Colors: names such as red or "bold red", none, or SGR parameters such as "1;31"
+	Warning string `yaml:"warning"`
+	Success string `yaml:"success"`
+	Heading string `yaml:"heading"`
+	Pass    string `yaml:"pass"` // Symbols
+	Fail    string `yaml:"fail"`
+	Bar     string `yaml:"bar"`
+}
+
+// Metrics configures Prometheus metrics export.
+type Metrics struct {
+	Out         string `yaml:"out"`         // Textfile path for the node_exporter textfile collector
+	Pushgateway string `yaml:"pushgateway"` // Pushgateway base URL
+}
+
+// SchemaPaths returns Schemas with relative paths resolved against the config file's
+// directory.
+func (c *Config) SchemaPaths() []string {
+	paths := make([]string, len(c.Schemas))
+	for i, p := range c.Schemas {
+		paths[i] = c.resolve(p)
+	}
+	return paths
+}
+
+// DiscriminatorSchemaPaths returns Discriminator.Schemas with relative paths resolved
+// against the config file's directory, or nil when none are configured.
+func (c *Config) DiscriminatorSchemaPaths() map[string]string {
+	if len(c.Discriminator.Schemas) == 0 {
+		return nil
+	}
+	paths := make(map[string]string, len(c.Discriminator.Schemas))
+	for value, p := range c.Discriminator.Schemas {
+		paths[value] = c.resolve(p)
+	}
+	return paths
+}
+
+// FileSchemaPath returns FileSchema resolved against the config file's directory, or ""
+// when none is configured.
+func (c *Config) FileSchemaPath() string {
+	if c.FileSchema == "" {
+		return ""
+	}
+	return c.resolve(c.FileSchema)
+}
+
+// resolve makes a path from the config file relative to the file's directory.
+func (c *Config) resolve(p string) string {
+	if !filepath.IsAbs(p) && c.Path != "" {
+		return filepath.Join(filepath.Dir(c.Path), p)
+	}
+	return p
+}
+
+// Redact configures redaction of cell values in reports. Setting either field turns
+// redaction on.
+type Redact struct {
+	Mode    string   `yaml:"mode"`    // "mask" (default) or "hash"
+	Columns []string `yaml:"columns"` // Only redact these columns; all when empty
+}
+
+// Enabled reports whether the config asks for redaction.
+func (r Redact) Enabled() bool {
+	return r.Mode != "" || len(r.Columns) > 0
+}
+
+// Empty sets how empty cells reach schemas: as "" (string), left out of the row
+// (missing) or as null.
+type Empty struct {
+	Default string            `yaml:"default"` // string (default), missing or null
+	Columns map[string]string `yaml:"columns"` // Column -> mode, overriding default
+	Quoted  bool              `yaml:"quoted"`  // Quoted empty cells ("") stay empty strings, whatever the mode
+}
+
+// Drift compares files to a baseline profile and sets how far they may deviate from it.
+type Drift struct {
+	Profile  string  `yaml:"profile"`   // Baseline profile, relative to the config file
+	NullRate float64 `yaml:"null_rate"` // Allowed change of the null rate, e.g. 0.05 for 5 points
+	Mean     float64 `yaml:"mean"`      // Allowed change of the mean relative to the baseline
+	Distinct float64 `yaml:"distinct"`  // Allowed change of the distinct count relative to the baseline
+}
+
+// ProfilePath returns the drift profile path resolved against the config file, or "".
+func (c *Config) ProfilePath() string {
+	if c.Drift.Profile == "" {
+		return ""
+	}
+	return c.resolve(c.Drift.Profile)
+}
+
+// Load reads and parses the config file at path.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config file '%s': %w", path, err)
+	}
+	cfg.Path = path
+	cfg.Files = []string{path}
+	return &cfg, nil
+}
+
+// Find looks for a config file in dir and its parents, stopping at the project root
+// (a directory containing .git or package.json) or the system root.
+// Returns the config path if found, or an empty string if not found.
+func Find(dir string) string {
+	for {
+		if path := fileIn(dir); path != "" {
+			return path
+		}
+		if project.IsRoot(dir, nil) {
+			return ""
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir { // system root
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// fileIn returns the config file in dir, or "".
+func fileIn(dir string) string {
+	for _, name := range FileNames {
+		candidate := filepath.Join(dir, name)
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// FindAll returns the config files in dir and its parents up to the project root, the
+// outermost first. The root is marked by project.DefaultRootMarkers: root_markers is
+// read from the files found, so it cannot stop the search for them.
+func FindAll(dir string) []string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil
+	}
+	var found []string
+	for dir = abs; ; {
+		if path := fileIn(dir); path != "" {
+			found = append([]string{path}, found...)
+		}
+		parent := filepath.Dir(dir)
+		if project.IsRoot(dir, nil) || parent == dir {
+			return found
+		}
+		dir = parent
+	}
+}
+
+// LoadFor loads the config file at path. When path is empty it loads the config files
+// from dir up to the project root instead, with the settings of deeper files overriding
+// those of outer ones, like .editorconfig: keys a file sets replace the outer value, and
+// map entries such as types are merged. It returns an empty config when no file is
+// found.
+func LoadFor(path, dir string) (*Config, error) {
+	if path != "" {
+		return Load(path)
+	}
+	cfg := &Config{}
+	for _, p := range FindAll(dir) {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+		// Paths set so far stay relative to the file that set them
+		cfg.resolvePaths()
+		if err := yaml.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse config file '%s': %w", p, err)
+		}
+		cfg.Path = p
+		cfg.Files = append(cfg.Files, p)
+	}
+	return cfg, nil
+}
+
+// resolvePaths resolves the relative paths of the config against its file's directory.
+func (c *Config) resolvePaths() {
+	c.Schemas = c.SchemaPaths()
+	c.FileSchema = c.FileSchemaPath()
+	if paths := c.DiscriminatorSchemaPaths(); paths != nil {
+		c.Discriminator.Schemas = paths
+	}
+	c.Drift.Profile = c.ProfilePath()
+}
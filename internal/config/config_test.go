@@ -0,0 +1,227 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".csvlinter.yml")
+	writeFile(t, path, "notify:\n  webhook: https://hooks.example.com/abc\n")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Notify.Webhook != "https://hooks.example.com/abc" {
+		t.Errorf("Expected webhook to be loaded, got %q", cfg.Notify.Webhook)
+	}
+	if cfg.Path != path {
+		t.Errorf("Expected Path %q, got %q", path, cfg.Path)
+	}
+}
+
+func TestLoadRedact(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".csvlinter.yml")
+	writeFile(t, path, "redact:\n  columns: [email, ssn]\n")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !cfg.Redact.Enabled() || len(cfg.Redact.Columns) != 2 {
+		t.Errorf("Expected redaction of two columns, got %+v", cfg.Redact)
+	}
+	if (Redact{}).Enabled() {
+		t.Error("Expected the zero Redact to be disabled")
+	}
+}
+
+func TestSchemaPaths(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".csvlinter.yml")
+	writeFile(t, path, "schemas:\n  - schemas/business.schema.json\n  - /abs/strict.schema.json\n")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	got := cfg.SchemaPaths()
+	want := []string{filepath.Join(dir, "schemas", "business.schema.json"), "/abs/strict.schema.json"}
+	if len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestDiscriminatorSchemaPaths(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".csvlinter.yml")
+	writeFile(t, path, "discriminator:\n  column: type\n  schemas:\n    refund: schemas/refund.schema.json\n")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Discriminator.Column != "type" {
+		t.Errorf("Expected column type, got %q", cfg.Discriminator.Column)
+	}
+	got := cfg.DiscriminatorSchemaPaths()
+	if want := filepath.Join(dir, "schemas", "refund.schema.json"); len(got) != 1 || got["refund"] != want {
+		t.Errorf("Expected refund -> %s, got %v", want, got)
+	}
+	if (&Config{}).DiscriminatorSchemaPaths() != nil {
+		t.Error("Expected no paths without a discriminator")
+	}
+}
+
+func TestProfilePath(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".csvlinter.yml")
+	writeFile(t, path, "drift:\n  profile: profiles/orders.profile.json\n  null_rate: 0.1\n")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if want := filepath.Join(dir, "profiles", "orders.profile.json"); cfg.ProfilePath() != want || cfg.Drift.NullRate != 0.1 {
+		t.Errorf("Expected %s with null rate 0.1, got %s and %+v", want, cfg.ProfilePath(), cfg.Drift)
+	}
+	if (&Config{}).ProfilePath() != "" {
+		t.Error("Expected no profile path without a drift section")
+	}
+}
+
+func TestGroupRuleBounds(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".csvlinter.yml")
+	writeFile(t, path, `rules:
+  groups:
+    - by: order_id
+      where: {line_type: header}
+      count: 1
+    - by: order_id
+      max: 50
+    - by: order_id
+`)
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	groups := cfg.Rules.Groups
+	if len(groups) != 3 || groups[0].Where["line_type"] != "header" {
+		t.Fatalf("Unexpected group rules: %+v", groups)
+	}
+	want := [][2]int{{1, 1}, {0, 50}, {1, -1}}
+	for i, g := range groups {
+		if min, max := g.Bounds(); min != want[i][0] || max != want[i][1] {
+			t.Errorf("rule %d: Bounds() = %d, %d, want %v", i, min, max, want[i])
+		}
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".csvlinter.yml")
+	writeFile(t, path, "notify: [unclosed\n")
+	if _, err := Load(path); err == nil {
+		t.Error("Expected parse error, got none")
+	}
+}
+
+func TestFind(t *testing.T) {
+	t.Run("found in parent directory", func(t *testing.T) {
+		base := t.TempDir()
+		writeFile(t, filepath.Join(base, ".csvlinter.yml"), "")
+		nested := filepath.Join(base, "a", "b")
+		if err := os.MkdirAll(nested, 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if got := Find(nested); got != filepath.Join(base, ".csvlinter.yml") {
+			t.Errorf("Expected config in base dir, got %q", got)
+		}
+	})
+
+	t.Run("search stops at project root", func(t *testing.T) {
+		base := t.TempDir()
+		writeFile(t, filepath.Join(base, ".csvlinter.yml"), "")
+		proj := filepath.Join(base, "project")
+		if err := os.MkdirAll(filepath.Join(proj, ".git"), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if got := Find(proj); got != "" {
+			t.Errorf("Expected no config beyond the project root, got %q", got)
+		}
+	})
+
+	t.Run("LoadFor without a file returns empty config", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.Mkdir(filepath.Join(dir, ".git"), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		cfg, err := LoadFor("", dir)
+		if err != nil {
+			t.Fatalf("LoadFor failed: %v", err)
+		}
+		if cfg.Path != "" || cfg.Notify.Webhook != "" {
+			t.Errorf("Expected empty config, got %+v", cfg)
+		}
+	})
+}
+
+func TestLoadForNested(t *testing.T) {
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, ".git"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	writeFile(t, filepath.Join(root, ".csvlinter.yml"),
+		"schemas: [shared.schema.json]\nquoting: minimal\ntarget: postgres\ntypes:\n  id: int\n  age: int\nragged:\n  short: warn\n  long: warn\n")
+	finance := filepath.Join(root, "finance")
+	writeFile(t, filepath.Join(finance, ".csvlinter.yml"),
+		"file_schema: ledger.dataset.json\nquoting: all\ntypes:\n  age: float\nragged:\n  short: error\n")
+	nested := filepath.Join(finance, "q1")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	cfg, err := LoadFor("", nested)
+	if err != nil {
+		t.Fatalf("LoadFor failed: %v", err)
+	}
+	want := []string{filepath.Join(root, ".csvlinter.yml"), filepath.Join(finance, ".csvlinter.yml")}
+	if !slices.Equal(cfg.Files, want) || cfg.Path != want[1] {
+		t.Errorf("Expected files %v, got %v (path %q)", want, cfg.Files, cfg.Path)
+	}
+	if cfg.Quoting != "all" || cfg.Target != "postgres" {
+		t.Errorf("Expected the deeper quoting and the outer target, got %q and %q", cfg.Quoting, cfg.Target)
+	}
+	if cfg.Types["id"] != "int" || cfg.Types["age"] != "float" {
+		t.Errorf("Expected merged types, got %v", cfg.Types)
+	}
+	if cfg.Ragged.Short != "error" || cfg.Ragged.Long != "warn" {
+		t.Errorf("Expected ragged.short overridden and ragged.long kept, got %+v", cfg.Ragged)
+	}
+	if got := cfg.SchemaPaths(); !slices.Equal(got, []string{filepath.Join(root, "shared.schema.json")}) {
+		t.Errorf("Expected the outer schema relative to its file, got %v", got)
+	}
+	if got := cfg.FileSchemaPath(); got != filepath.Join(finance, "ledger.dataset.json") {
+		t.Errorf("Expected the file schema relative to the deeper file, got %q", got)
+	}
+
+	outer, err := LoadFor("", root)
+	if err != nil {
+		t.Fatalf("LoadFor failed: %v", err)
+	}
+	if outer.Quoting != "minimal" || len(outer.Files) != 1 {
+		t.Errorf("Expected only the root config outside finance, got %+v", outer)
+	}
+}
@@ -0,0 +1,142 @@
+// Package ddl generates CREATE TABLE statements for CSV files from their JSON Schema.
+package ddl
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/csvlinter/csvlinter/internal/schema"
+)
+
+// dialect holds a database's spelling of the column types and identifiers.
+type dialect struct {
+	quote     func(name string) string
+	integer   string
+	number    string
+	boolean   string
+	text      string
+	varchar   string // With %d for maxLength
+	date      string
+	timestamp string // For format date-time
+	time      string
+	keySuffix string // After PRIMARY KEY (...), for databases that do not enforce keys
+}
+
+var dialects = map[string]dialect{
+	"postgres": {
+		quote:     quoteWith(`"`),
+		integer:   "BIGINT",
+		number:    "DOUBLE PRECISION",
+		boolean:   "BOOLEAN",
+		text:      "TEXT",
+		varchar:   "VARCHAR(%d)",
+		date:      "DATE",
+		timestamp: "TIMESTAMPTZ",
+		time:      "TIME",
+	},
+	"bigquery": {
+		quote:     quoteWith("`"),
+		integer:   "INT64",
+		number:    "FLOAT64",
+		boolean:   "BOOL",
+		text:      "STRING",
+		varchar:   "STRING(%d)",
+		date:      "DATE",
+		timestamp: "TIMESTAMP",
+		time:      "TIME",
+		keySuffix: " NOT ENFORCED",
+	},
+	"snowflake": {
+		quote:     quoteWith(`"`),
+		integer:   "NUMBER(38,0)",
+		number:    "FLOAT",
+		boolean:   "BOOLEAN",
+		text:      "VARCHAR",
+		varchar:   "VARCHAR(%d)",
+		date:      "DATE",
+		timestamp: "TIMESTAMP_TZ",
+		time:      "TIME",
+	},
+}
+
+// quoteWith returns a function quoting identifiers with q, doubling q inside them.
+func quoteWith(q string) func(string) string {
+	return func(name string) string {
+		return q + strings.ReplaceAll(name, q, q+q) + q
+	}
+}
+
+// Dialects returns the supported dialect names, sorted.
+func Dialects() []string {
+	names := make([]string, 0, len(dialects))
+	for name := range dialects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Generate returns a CREATE TABLE statement for table with columns in the given order.
+// Column types come from the schema's properties: integer, number, boolean and string,
+// with the date, date-time and time formats and maxLength refining strings. A column
+// that is required and does not allow null is NOT NULL; x-primaryKey names the primary
+// key columns. Columns the schema does not describe are text.
+func Generate(dialectName, table string, columns []string, schemaJSON []byte) (string, error) {
+	d, ok := dialects[strings.ToLower(dialectName)]
+	if !ok {
+		return "", fmt.Errorf("unknown dialect %q (supported: %s)", dialectName, strings.Join(Dialects(), ", "))
+	}
+	desc, err := schema.Describe(schemaJSON)
+	if err != nil {
+		return "", err
+	}
+	for _, name := range desc.PrimaryKey {
+		if !slices.Contains(columns, name) {
+			return "", fmt.Errorf("x-primaryKey column '%s' is not in the header", name)
+		}
+	}
+
+	lines := make([]string, 0, len(columns)+1)
+	for _, name := range columns {
+		c := desc.Column(name)
+		line := "  " + d.quote(name) + " " + d.column(c)
+		if c.Required && !c.Nullable {
+			line += " NOT NULL"
+		}
+		lines = append(lines, line)
+	}
+	if key := desc.PrimaryKey; len(key) > 0 {
+		quoted := make([]string, len(key))
+		for i, name := range key {
+			quoted[i] = d.quote(name)
+		}
+		lines = append(lines, "  PRIMARY KEY ("+strings.Join(quoted, ", ")+")"+d.keySuffix)
+	}
+	return "CREATE TABLE " + d.quote(table) + " (\n" + strings.Join(lines, ",\n") + "\n);\n", nil
+}
+
+// column returns the SQL type for a column.
+func (d dialect) column(c schema.Column) string {
+	switch c.Type {
+	case "integer":
+		return d.integer
+	case "number":
+		return d.number
+	case "boolean":
+		return d.boolean
+	}
+	switch c.Format {
+	case "date":
+		return d.date
+	case "date-time":
+		return d.timestamp
+	case "time":
+		return d.time
+	}
+	if c.MaxLength > 0 {
+		return fmt.Sprintf(d.varchar, c.MaxLength)
+	}
+	return d.text
+}
@@ -0,0 +1,64 @@
+package ddl
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	schemaJSON := []byte(`{
+  "type": "object",
+  "required": ["id", "email", "note"],
+  "x-primaryKey": "id",
+  "properties": {
+    "id": {"type": "integer"},
+    "email": {"type": "string", "maxLength": 254},
+    "amount": {"type": "number"},
+    "active": {"type": "boolean"},
+    "created": {"type": "string", "format": "date-time"},
+    "note": {"type": ["string", "null"]},
+    "code": {"type": ["integer", "string"]}
+  }
+}`)
+	columns := []string{"id", "email", "amount", "active", "created", "note", "code", `say "hi"`}
+
+	got, err := Generate("postgres", "users", columns, schemaJSON)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	want := `CREATE TABLE "users" (
+  "id" BIGINT NOT NULL,
+  "email" VARCHAR(254) NOT NULL,
+  "amount" DOUBLE PRECISION,
+  "active" BOOLEAN,
+  "created" TIMESTAMPTZ,
+  "note" TEXT,
+  "code" TEXT,
+  "say ""hi""" TEXT,
+  PRIMARY KEY ("id")
+);
+`
+	if got != want {
+		t.Errorf("Generate postgres =\n%s\nwant\n%s", got, want)
+	}
+
+	got, err = Generate("bigquery", "users", columns[:2], schemaJSON)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if !strings.Contains(got, "`id` INT64 NOT NULL") || !strings.Contains(got, "PRIMARY KEY (`id`) NOT ENFORCED") {
+		t.Errorf("Unexpected BigQuery DDL:\n%s", got)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	if _, err := Generate("oracle", "t", []string{"a"}, []byte(`{}`)); err == nil || !strings.Contains(err.Error(), "bigquery, postgres, snowflake") {
+		t.Errorf("Expected an unknown dialect error listing the dialects, got %v", err)
+	}
+	if _, err := Generate("postgres", "t", []string{"a"}, []byte(`{"x-primaryKey": ["a", "b"]}`)); err == nil || !strings.Contains(err.Error(), "'b'") {
+		t.Errorf("Expected an error for a key column missing from the header, got %v", err)
+	}
+	if _, err := Generate("postgres", "t", []string{"a"}, []byte(`{"properties": {"a": {"type": 1}}}`)); err == nil {
+		t.Error("Expected an error for an invalid type")
+	}
+}
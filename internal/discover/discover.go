@@ -0,0 +1,178 @@
+// Package discover finds the CSV files to validate when csvlinter is given directories.
+package discover
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultInclude are the patterns used when Options.Include is empty: the extensions
+// parser.DelimiterFor knows.
+var DefaultInclude = []string{"*.csv", "*.tsv", "*.tab", "*.psv"}
+
+// IgnoreFileName is the gitignore-style file picked up from the walked directories and
+// their parents up to the project root, so generated or vendored files are skipped
+// without flags. Its patterns are relative to its directory, and deeper files win.
+const IgnoreFileName = ".csvlinterignore"
+
+// Options filters the files found under a directory.
+type Options struct {
+	Include     []string // Glob patterns a file must match (DefaultInclude when empty)
+	Exclude     []string // Glob patterns that skip files and whole directories; a trailing / matches directories only
+	IgnoreFile  string   // Optional gitignore-style file; its patterns are relative to the walked root
+	RootMarkers []string // Files or directories marking the project root, where the search for ignore files stops (project.DefaultRootMarkers when empty)
+}
+
+// Files walks root and returns the matching regular files, sorted. Patterns without a /
+// match base names at any depth; patterns with a / match paths relative to root.
+func Files(root string, opts Options) ([]string, error) {
+	m, err := newMatcher(root, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == root {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			if m.skipDir(rel) {
+				return filepath.SkipDir
+			}
+			return m.ignores.load(path)
+		}
+		if !d.Type().IsRegular() && !isSymlinkToFile(path, d) {
+			return nil
+		}
+		if !m.keepFile(rel) {
+			return nil
+		}
+		files = append(files, path)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// Filter applies the same include, exclude and ignore rules as Files to rels, a list of
+// slash-separated paths relative to root (e.g. from git), and returns the kept paths
+// joined to root. A file is also dropped when one of its parent directories is excluded.
+func Filter(root string, rels []string, opts Options) ([]string, error) {
+	m, err := newMatcher(root, opts)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, rel := range rels {
+		// Ignore files are loaded top-down, so deeper ones come after their parents'
+		parts := strings.Split(rel, "/")
+		dir := root
+		for _, part := range parts[:len(parts)-1] {
+			dir = filepath.Join(dir, part)
+			if err := m.ignores.load(dir); err != nil {
+				return nil, err
+			}
+		}
+		if !m.keepFile(rel) {
+			continue
+		}
+		skip := false
+		for dir := path.Dir(rel); dir != "."; dir = path.Dir(dir) {
+			if m.skipDir(dir) {
+				skip = true
+				break
+			}
+		}
+		if !skip {
+			files = append(files, filepath.Join(root, filepath.FromSlash(rel)))
+		}
+	}
+	return files, nil
+}
+
+// matcher holds the compiled Options and the ignore files found so far.
+type matcher struct {
+	include []string
+	exclude []string
+	ignore  *IgnoreMatcher
+	ignores *ignoreFiles
+}
+
+// newMatcher compiles opts for the files under root, loading the ignore files of root
+// and its parents.
+func newMatcher(root string, opts Options) (*matcher, error) {
+	ignores, err := newIgnoreFiles(root, opts.RootMarkers)
+	if err != nil {
+		return nil, err
+	}
+	m := &matcher{include: opts.Include, exclude: opts.Exclude, ignores: ignores}
+	if len(m.include) == 0 {
+		m.include = DefaultInclude
+	}
+	if opts.IgnoreFile != "" {
+		ignore, err := LoadIgnoreFile(opts.IgnoreFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ignore file: %w", err)
+		}
+		m.ignore = ignore
+	}
+	return m, nil
+}
+
+func (m *matcher) skipDir(rel string) bool {
+	return excluded(m.exclude, rel, true) || m.ignore.Match(rel, true) || m.ignores.match(rel, true)
+}
+
+func (m *matcher) keepFile(rel string) bool {
+	return matchAny(m.include, rel) && !excluded(m.exclude, rel, false) && !m.ignore.Match(rel, false) && !m.ignores.match(rel, false)
+}
+
+func matchAny(patterns []string, rel string) bool {
+	for _, p := range patterns {
+		if matchGlob(p, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+func excluded(patterns []string, rel string, isDir bool) bool {
+	for _, p := range patterns {
+		if strings.HasSuffix(p, "/") {
+			if !isDir {
+				continue
+			}
+			p = strings.TrimSuffix(p, "/")
+		}
+		if matchGlob(p, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+func isSymlinkToFile(path string, d fs.DirEntry) bool {
+	if d.Type()&fs.ModeSymlink == 0 {
+		return false
+	}
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
@@ -0,0 +1,184 @@
+package discover
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func makeTree(t *testing.T, files ...string) string {
+	t.Helper()
+	root := t.TempDir()
+	for _, f := range files {
+		p := filepath.Join(root, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(p, []byte("id\n1\n"), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	return root
+}
+
+func relAll(t *testing.T, root string, paths []string) []string {
+	t.Helper()
+	out := make([]string, len(paths))
+	for i, p := range paths {
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			t.Fatalf("rel: %v", err)
+		}
+		out[i] = filepath.ToSlash(rel)
+	}
+	return out
+}
+
+func TestFiles(t *testing.T) {
+	root := makeTree(t,
+		"a.csv", "b.tsv", "notes.txt",
+		"sub/c.csv", "sub/deep/d.csv", "sub/g.psv",
+		"vendor/e.csv", "tmp/f.csv", "tmp/h.tab",
+	)
+
+	cases := []struct {
+		name string
+		opts Options
+		want []string
+	}{
+		{"default include", Options{}, []string{"a.csv", "b.tsv", "sub/c.csv", "sub/deep/d.csv", "sub/g.psv", "tmp/f.csv", "tmp/h.tab", "vendor/e.csv"}},
+		{"include by base name", Options{Include: []string{"*.tsv"}}, []string{"b.tsv"}},
+		{"include by path", Options{Include: []string{"sub/**/*.csv"}}, []string{"sub/c.csv", "sub/deep/d.csv"}},
+		{"exclude directory", Options{Exclude: []string{"vendor", "tmp"}}, []string{"a.csv", "b.tsv", "sub/c.csv", "sub/deep/d.csv", "sub/g.psv"}},
+		{"exclude files", Options{Exclude: []string{"sub/**/d.csv", "*.tsv"}}, []string{"a.csv", "sub/c.csv", "sub/g.psv", "tmp/f.csv", "tmp/h.tab", "vendor/e.csv"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			files, err := Files(root, tc.opts)
+			if err != nil {
+				t.Fatalf("Files failed: %v", err)
+			}
+			if got := relAll(t, root, files); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	t.Run("ignore file", func(t *testing.T) {
+		ignorePath := filepath.Join(t.TempDir(), "ignore")
+		content := "# generated data\nvendor/\n/tmp\n*.tsv\nsub/deep/*\n!sub/deep/d.csv\n"
+		if err := os.WriteFile(ignorePath, []byte(content), 0o644); err != nil {
+			t.Fatalf("write ignore: %v", err)
+		}
+		files, err := Files(root, Options{IgnoreFile: ignorePath})
+		if err != nil {
+			t.Fatalf("Files failed: %v", err)
+		}
+		want := []string{"a.csv", "sub/c.csv", "sub/deep/d.csv", "sub/g.psv"}
+		if got := relAll(t, root, files); !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+}
+
+func TestFilter(t *testing.T) {
+	rels := []string{"a.csv", "b.tsv", "notes.txt", "sub/c.csv", "vendor/lib/e.csv"}
+	files, err := Filter("root", rels, Options{Exclude: []string{"vendor/"}})
+	if err != nil {
+		t.Fatalf("Filter failed: %v", err)
+	}
+	want := []string{filepath.Join("root", "a.csv"), filepath.Join("root", "b.tsv"), filepath.Join("root", "sub", "c.csv")}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("got %v, want %v", files, want)
+	}
+}
+
+func TestIgnoreMatcher(t *testing.T) {
+	m, err := ParseIgnore(strings.NewReader("*.bak\nbuild/\n/root.csv\ndocs/**/draft-?.csv\n"))
+	if err != nil {
+		t.Fatalf("ParseIgnore failed: %v", err)
+	}
+	cases := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"x.bak", false, true},
+		{"a/b/x.bak", false, true},
+		{"build", true, true},
+		{"build", false, false},
+		{"root.csv", false, true},
+		{"nested/root.csv", false, false},
+		{"docs/a/b/draft-1.csv", false, true},
+		{"docs/draft-1.csv", false, true},
+		{"docs/draft-10.csv", false, false},
+	}
+	for _, tc := range cases {
+		if got := m.Match(tc.path, tc.isDir); got != tc.want {
+			t.Errorf("Match(%q, %v) = %v, want %v", tc.path, tc.isDir, got, tc.want)
+		}
+	}
+}
+
+func TestIgnoreFileName(t *testing.T) {
+	project := makeTree(t,
+		".git/HEAD", "data/a.csv", "data/generated/b.csv", "data/vendor/c.csv",
+		"data/sub/d.csv", "data/sub/keep.csv",
+	)
+	for dir, content := range map[string]string{
+		"":         "generated/\n",
+		"data":     "/vendor\n",
+		"data/sub": "*.csv\n!keep.csv\n",
+	} {
+		if err := os.WriteFile(filepath.Join(project, dir, IgnoreFileName), []byte(content), 0o644); err != nil {
+			t.Fatalf("write ignore: %v", err)
+		}
+	}
+
+	root := filepath.Join(project, "data")
+	files, err := Files(root, Options{})
+	if err != nil {
+		t.Fatalf("Files failed: %v", err)
+	}
+	want := []string{"a.csv", "sub/keep.csv"}
+	if got := relAll(t, root, files); !reflect.DeepEqual(got, want) {
+		t.Errorf("Files: got %v, want %v", got, want)
+	}
+
+	rels := []string{"data/a.csv", "data/generated/b.csv", "data/vendor/c.csv", "data/sub/d.csv", "data/sub/keep.csv"}
+	files, err = Filter(project, rels, Options{})
+	if err != nil {
+		t.Fatalf("Filter failed: %v", err)
+	}
+	want = []string{"data/a.csv", "data/sub/keep.csv"}
+	if got := relAll(t, project, files); !reflect.DeepEqual(got, want) {
+		t.Errorf("Filter: got %v, want %v", got, want)
+	}
+}
+
+func TestIgnoreFileRootMarkers(t *testing.T) {
+	project := makeTree(t, ".git/HEAD", "data/go.mod", "data/a.csv", "data/generated/b.csv")
+	if err := os.WriteFile(filepath.Join(project, IgnoreFileName), []byte("generated/\n"), 0o644); err != nil {
+		t.Fatalf("write ignore: %v", err)
+	}
+
+	root := filepath.Join(project, "data")
+	files, err := Files(root, Options{})
+	if err != nil {
+		t.Fatalf("Files failed: %v", err)
+	}
+	if got, want := relAll(t, root, files), []string{"a.csv"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("default markers: got %v, want %v", got, want)
+	}
+
+	// go.mod marks data as the root, so the ignore file above it no longer applies
+	files, err = Files(root, Options{RootMarkers: []string{"go.mod"}})
+	if err != nil {
+		t.Fatalf("Files failed: %v", err)
+	}
+	if got, want := relAll(t, root, files), []string{"a.csv", "generated/b.csv"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("go.mod marker: got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,220 @@
+package discover
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/csvlinter/csvlinter/internal/project"
+)
+
+// IgnoreMatcher matches slash-separated paths against gitignore-style patterns.
+type IgnoreMatcher struct {
+	patterns []ignorePattern
+}
+
+type ignorePattern struct {
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// ParseIgnore reads gitignore-style patterns from r: blank lines and lines starting with
+// # are skipped, a leading ! re-includes, a trailing / matches directories only, and a
+// pattern containing a / is anchored to the ignore file's directory.
+func ParseIgnore(r io.Reader) (*IgnoreMatcher, error) {
+	m := &IgnoreMatcher{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var p ignorePattern
+		if strings.HasPrefix(line, "!") {
+			p.negate = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, `\`) {
+			line = line[1:] // escaped leading # or !
+		}
+		if strings.HasSuffix(line, "/") {
+			p.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+		anchored := strings.Contains(line, "/")
+		line = strings.TrimPrefix(line, "/")
+		if !anchored {
+			line = "**/" + line
+		}
+		re, err := globRegexp(line)
+		if err != nil {
+			return nil, err
+		}
+		p.re = re
+		m.patterns = append(m.patterns, p)
+	}
+	return m, scanner.Err()
+}
+
+// LoadIgnoreFile parses the ignore file at path.
+func LoadIgnoreFile(path string) (*IgnoreMatcher, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseIgnore(f)
+}
+
+// Match reports whether rel, a slash-separated path relative to the ignore file's
+// directory, is ignored. The last matching pattern wins, as in gitignore.
+func (m *IgnoreMatcher) Match(rel string, isDir bool) bool {
+	ignored, _ := m.match(rel, isDir)
+	return ignored
+}
+
+// match is Match that also reports whether any pattern matched.
+func (m *IgnoreMatcher) match(rel string, isDir bool) (ignored, matched bool) {
+	if m == nil {
+		return false, false
+	}
+	for _, p := range m.patterns {
+		if p.dirOnly && !isDir {
+			continue
+		}
+		if p.re.MatchString(rel) {
+			ignored, matched = !p.negate, true
+		}
+	}
+	return ignored, matched
+}
+
+// ignoreFiles are the IgnoreFileName files that apply under a root, each matching paths
+// relative to its own directory.
+type ignoreFiles struct {
+	root   string   // Absolute slash-separated walked root
+	dirs   []string // Absolute slash-separated directories of files, parents first
+	files  []*IgnoreMatcher
+	loaded map[string]bool // Directories already looked at
+}
+
+// newIgnoreFiles loads the ignore files of root and of its parents up to the project
+// root, marked by one of markers.
+func newIgnoreFiles(root string, markers []string) (*ignoreFiles, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	f := &ignoreFiles{root: filepath.ToSlash(abs), loaded: make(map[string]bool)}
+	chain := []string{abs}
+	for dir := abs; !project.IsRoot(dir, markers); {
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+		chain = append(chain, dir)
+	}
+	for i := len(chain) - 1; i >= 0; i-- {
+		if err := f.load(chain[i]); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// load adds the ignore file of dir, if it has one and was not looked at yet.
+func (f *ignoreFiles) load(dir string) error {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	key := filepath.ToSlash(abs)
+	if f.loaded[key] {
+		return nil
+	}
+	f.loaded[key] = true
+	m, err := LoadIgnoreFile(filepath.Join(abs, IgnoreFileName))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filepath.Join(abs, IgnoreFileName), err)
+	}
+	f.dirs = append(f.dirs, key)
+	f.files = append(f.files, m)
+	return nil
+}
+
+// match reports whether rel, relative to the root, is ignored by the ignore files of its
+// directories; the deepest file with a matching pattern decides.
+func (f *ignoreFiles) match(rel string, isDir bool) bool {
+	p := path.Join(f.root, rel)
+	ignored := false
+	for i, dir := range f.dirs {
+		if !strings.HasPrefix(p, strings.TrimSuffix(dir, "/")+"/") {
+			continue
+		}
+		if ign, ok := f.files[i].match(strings.TrimPrefix(p, strings.TrimSuffix(dir, "/")+"/"), isDir); ok {
+			ignored = ign
+		}
+	}
+	return ignored
+}
+
+// globRegexp compiles a slash-separated glob where * and ? never cross a /, and ** matches
+// any number of path segments.
+func globRegexp(glob string) (*regexp.Regexp, error) {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			sb.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case c == '*':
+			sb.WriteString("[^/]*")
+		case c == '?':
+			sb.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[i:], ']')
+			if end < 0 {
+				sb.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + class + "]")
+			i += end
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.Compile(sb.String())
+}
+
+// matchGlob matches a user-supplied --include/--exclude pattern. Patterns without a /
+// match the base name at any depth; patterns with a / match the whole relative path.
+func matchGlob(pattern, rel string) bool {
+	target := rel
+	if !strings.Contains(pattern, "/") {
+		target = path.Base(rel)
+	}
+	re, err := globRegexp(pattern)
+	if err != nil {
+		return false
+	}
+	return re.MatchString(target)
+}
@@ -0,0 +1,281 @@
+// Package expr parses and evaluates arithmetic assertions over the columns of a row, such
+// as "total == quantity * unit_price".
+package expr
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Assertion compares two arithmetic expressions.
+type Assertion struct {
+	Left, Right string // Source text of each side
+	Op          string // ==, !=, <, <=, > or >=
+
+	left, right node
+	columns     []string
+}
+
+// Values looks up the number in a column; ok is false when the row has no usable value.
+type Values func(column string) (value float64, ok bool)
+
+// node is a parsed arithmetic expression.
+type node interface {
+	eval(values Values) (float64, bool)
+}
+
+type number float64
+
+func (n number) eval(Values) (float64, bool) { return float64(n), true }
+
+type column string
+
+func (c column) eval(values Values) (float64, bool) { return values(string(c)) }
+
+type negate struct{ x node }
+
+func (n negate) eval(values Values) (float64, bool) {
+	x, ok := n.x.eval(values)
+	return -x, ok
+}
+
+type binary struct {
+	op   byte
+	x, y node
+}
+
+func (b binary) eval(values Values) (float64, bool) {
+	x, ok := b.x.eval(values)
+	if !ok {
+		return 0, false
+	}
+	y, ok := b.y.eval(values)
+	if !ok {
+		return 0, false
+	}
+	switch b.op {
+	case '+':
+		return x + y, true
+	case '-':
+		return x - y, true
+	case '*':
+		return x * y, true
+	}
+	if y == 0 {
+		return 0, false
+	}
+	return x / y, true
+}
+
+// comparisons are the supported operators, two-character ones first.
+var comparisons = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+// Parse parses an assertion: two expressions of numbers, column names, + - * / and
+// parentheses, joined by a comparison. Column names that are not identifiers are written
+// in backticks, e.g. `unit price`.
+func Parse(src string) (*Assertion, error) {
+	p := &parser{src: src}
+	a := &Assertion{}
+	var err error
+	if a.left, err = p.sum(); err != nil {
+		return nil, err
+	}
+	a.Left = strings.TrimSpace(src[:p.pos])
+	p.space()
+	for _, op := range comparisons {
+		if strings.HasPrefix(src[p.pos:], op) {
+			a.Op = op
+			p.pos += len(op)
+			break
+		}
+	}
+	if a.Op == "" {
+		return nil, p.errorf("expected a comparison (%s)", strings.Join(comparisons, ", "))
+	}
+	start := p.pos
+	if a.right, err = p.sum(); err != nil {
+		return nil, err
+	}
+	a.Right = strings.TrimSpace(src[start:p.pos])
+	p.space()
+	if p.pos < len(src) {
+		return nil, p.errorf("unexpected %q", src[p.pos:])
+	}
+	a.columns = p.columns
+	return a, nil
+}
+
+// Columns returns the columns the assertion reads, in order of appearance.
+func (a *Assertion) Columns() []string {
+	return a.columns
+}
+
+// Eval evaluates both sides and reports whether the comparison holds, with differences up
+// to tolerance counting as equal. ok is false when a side cannot be evaluated, e.g.
+// because a value is missing or a division is by zero.
+func (a *Assertion) Eval(values Values, tolerance float64) (left, right float64, holds, ok bool) {
+	if left, ok = a.left.eval(values); !ok {
+		return 0, 0, false, false
+	}
+	if right, ok = a.right.eval(values); !ok {
+		return 0, 0, false, false
+	}
+	equal := math.Abs(left-right) <= tolerance
+	switch a.Op {
+	case "==":
+		holds = equal
+	case "!=":
+		holds = !equal
+	case "<":
+		holds = left < right && !equal
+	case "<=":
+		holds = left < right || equal
+	case ">":
+		holds = left > right && !equal
+	case ">=":
+		holds = left > right || equal
+	}
+	return left, right, holds, true
+}
+
+// SingleColumn returns the column when the left side is just a column name.
+func (a *Assertion) SingleColumn() (string, bool) {
+	c, ok := a.left.(column)
+	return string(c), ok
+}
+
+type parser struct {
+	src     string
+	pos     int
+	columns []string
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("expression %q at offset %d: %s", p.src, p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) space() {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// peek returns the next non-space byte, or 0 at the end.
+func (p *parser) peek() byte {
+	p.space()
+	if p.pos < len(p.src) {
+		return p.src[p.pos]
+	}
+	return 0
+}
+
+func (p *parser) sum() (node, error) {
+	x, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek()
+		if op != '+' && op != '-' {
+			return x, nil
+		}
+		p.pos++
+		y, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		x = binary{op, x, y}
+	}
+}
+
+func (p *parser) term() (node, error) {
+	x, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek()
+		if op != '*' && op != '/' {
+			return x, nil
+		}
+		p.pos++
+		y, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		x = binary{op, x, y}
+	}
+}
+
+func (p *parser) unary() (node, error) {
+	if p.peek() == '-' {
+		p.pos++
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return negate{x}, nil
+	}
+	return p.primary()
+}
+
+func (p *parser) primary() (node, error) {
+	switch c := p.peek(); {
+	case c == '(':
+		p.pos++
+		x, err := p.sum()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ')' {
+			return nil, p.errorf("expected )")
+		}
+		p.pos++
+		return x, nil
+	case c == '`':
+		end := strings.IndexByte(p.src[p.pos+1:], '`')
+		if end < 0 {
+			return nil, p.errorf("unterminated column name")
+		}
+		name := p.src[p.pos+1 : p.pos+1+end]
+		p.pos += end + 2
+		return p.column(name), nil
+	case c >= '0' && c <= '9' || c == '.':
+		start := p.pos
+		for p.pos < len(p.src) && (p.src[p.pos] >= '0' && p.src[p.pos] <= '9' || p.src[p.pos] == '.') {
+			p.pos++
+		}
+		v, err := strconv.ParseFloat(p.src[start:p.pos], 64)
+		if err != nil {
+			return nil, p.errorf("invalid number %q", p.src[start:p.pos])
+		}
+		return number(v), nil
+	case identStart(c):
+		start := p.pos
+		for p.pos < len(p.src) && (identStart(p.src[p.pos]) || p.src[p.pos] >= '0' && p.src[p.pos] <= '9') {
+			p.pos++
+		}
+		return p.column(p.src[start:p.pos]), nil
+	case c == 0:
+		return nil, p.errorf("unexpected end")
+	default:
+		return nil, p.errorf("unexpected %q", string(c))
+	}
+}
+
+// identStart reports whether c can start a bare column name.
+func identStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func (p *parser) column(name string) node {
+	for _, c := range p.columns {
+		if c == name {
+			return column(name)
+		}
+	}
+	p.columns = append(p.columns, name)
+	return column(name)
+}
@@ -0,0 +1,69 @@
+package expr
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseEval(t *testing.T) {
+	row := map[string]float64{"total": 31, "quantity": 3, "unit_price": 10, "unit price": 10, "discount": 0}
+	values := func(c string) (float64, bool) {
+		v, ok := row[c]
+		return v, ok
+	}
+	tests := []struct {
+		src       string
+		tolerance float64
+		holds     bool
+	}{
+		{"total == quantity * unit_price", 0, false},
+		{"total == quantity * unit_price", 1, true},
+		{"total == quantity * `unit price` + 1", 0, true},
+		{"total - 1 == (quantity) * unit_price", 0, true},
+		{"total >= quantity * unit_price", 0, true},
+		{"total > 31", 0, false},
+		{"total <= 30.5", 0.5, true},
+		{"total < 31", 0.1, false},
+		{"-total != -31", 0, false},
+		{"1 + 2 * 3 == 7", 0, true},
+		{"total / 2 / 2 == 7.75", 0, true},
+	}
+	for _, tt := range tests {
+		a, err := Parse(tt.src)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.src, err)
+		}
+		if _, _, holds, ok := a.Eval(values, tt.tolerance); !ok || holds != tt.holds {
+			t.Errorf("%s (tolerance %v): want holds=%v, got %v (ok %v)", tt.src, tt.tolerance, tt.holds, holds, ok)
+		}
+	}
+
+	a, _ := Parse("total==quantity*unit_price")
+	left, right, _, _ := a.Eval(values, 0)
+	if a.Left != "total" || a.Op != "==" || a.Right != "quantity*unit_price" || left != 31 || right != 30 {
+		t.Errorf("unexpected sides %q %q %q = %v, %v", a.Left, a.Op, a.Right, left, right)
+	}
+	if !reflect.DeepEqual(a.Columns(), []string{"total", "quantity", "unit_price"}) {
+		t.Errorf("unexpected columns %v", a.Columns())
+	}
+	if c, ok := a.SingleColumn(); !ok || c != "total" {
+		t.Errorf("want total as the single left column, got %q", c)
+	}
+
+	// Missing values and division by zero cannot be evaluated
+	for _, src := range []string{"missing == 1", "total / discount == 1"} {
+		a, _ := Parse(src)
+		if _, _, _, ok := a.Eval(values, 0); ok {
+			t.Errorf("%s: want no result", src)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, src := range []string{"", "total", "total = 1", "total == ", "(a == b", "a == b)", "`a == 1", "a == 1.2.3", "a == b c"} {
+		if _, err := Parse(src); err == nil || !strings.Contains(err.Error(), "expression") {
+			t.Errorf("%q: want a parse error, got %v", src, err)
+		}
+	}
+}
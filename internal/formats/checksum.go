@@ -0,0 +1,118 @@
+package formats
+
+import "strings"
+
+// isCreditCard accepts payment card numbers of 12 to 19 digits with a valid Luhn check
+// digit. Spaces and hyphens between digit groups are ignored.
+func isCreditCard(v string) bool {
+	digits := stripSeparators(v)
+	return len(digits) >= 12 && len(digits) <= 19 && allDigits(digits) && luhn(digits)
+}
+
+// luhn reports whether the last digit of digits is its Luhn check digit.
+func luhn(digits string) bool {
+	sum := 0
+	for i := 0; i < len(digits); i++ {
+		d := int(digits[len(digits)-1-i] - '0')
+		if i%2 == 1 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+// isISBN accepts ISBN-10 and ISBN-13 with valid check digits, such as 0-306-40615-2 and
+// 978-0-306-40615-7. Spaces and hyphens are ignored.
+func isISBN(v string) bool {
+	s := stripSeparators(v)
+	switch len(s) {
+	case 10:
+		sum := 0
+		for i := 0; i < 10; i++ {
+			var d int
+			switch c := s[i]; {
+			case c >= '0' && c <= '9':
+				d = int(c - '0')
+			case (c == 'X' || c == 'x') && i == 9:
+				d = 10
+			default:
+				return false
+			}
+			sum += (10 - i) * d
+		}
+		return sum%11 == 0
+	case 13:
+		return (strings.HasPrefix(s, "978") || strings.HasPrefix(s, "979")) && isEAN(s)
+	}
+	return false
+}
+
+// isEAN accepts EAN-8, UPC-A (12 digits) and EAN-13 barcodes with a valid check digit.
+func isEAN(v string) bool {
+	if len(v) != 8 && len(v) != 12 && len(v) != 13 || !allDigits(v) {
+		return false
+	}
+	// Weights alternate 3 and 1 from the digit before the check digit
+	sum := 0
+	for i := 0; i < len(v); i++ {
+		d := int(v[len(v)-1-i] - '0')
+		if i%2 == 1 {
+			d *= 3
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+// vinValues are the transliterated values of the letters allowed in VINs; I, O and Q
+// are not used.
+var vinValues = map[byte]int{
+	'A': 1, 'B': 2, 'C': 3, 'D': 4, 'E': 5, 'F': 6, 'G': 7, 'H': 8,
+	'J': 1, 'K': 2, 'L': 3, 'M': 4, 'N': 5, 'P': 7, 'R': 9,
+	'S': 2, 'T': 3, 'U': 4, 'V': 5, 'W': 6, 'X': 7, 'Y': 8, 'Z': 9,
+}
+
+var vinWeights = [17]int{8, 7, 6, 5, 4, 3, 2, 10, 0, 9, 8, 7, 6, 5, 4, 3, 2}
+
+// isVIN accepts 17-character vehicle identification numbers in upper case with a valid
+// check digit in position 9, as required in North America. VINs from other regions may
+// not carry a check digit.
+func isVIN(v string) bool {
+	if len(v) != 17 {
+		return false
+	}
+	sum := 0
+	for i := 0; i < 17; i++ {
+		c := v[i]
+		n, ok := vinValues[c]
+		if c >= '0' && c <= '9' {
+			n, ok = int(c-'0'), true
+		}
+		if !ok {
+			return false
+		}
+		sum += n * vinWeights[i]
+	}
+	check := byte('0' + sum%11)
+	if sum%11 == 10 {
+		check = 'X'
+	}
+	return v[8] == check
+}
+
+// stripSeparators removes the spaces and hyphens used to group digits.
+func stripSeparators(v string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(v)
+}
+
+func allDigits(v string) bool {
+	for i := 0; i < len(v); i++ {
+		if v[i] < '0' || v[i] > '9' {
+			return false
+		}
+	}
+	return v != ""
+}
@@ -0,0 +1,142 @@
+// Package formats is a registry of JSON Schema "format" validators for business
+// identifiers the schema library does not check, such as IBANs, ISBNs and E.164 phone
+// numbers.
+// Formats are only asserted when a run enables them by name.
+package formats
+
+import (
+	"fmt"
+	"math/big"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Func reports whether a string value has the format.
+type Func func(value string) bool
+
+var (
+	mu       sync.RWMutex
+	registry = map[string]Func{
+		"uuid":         isUUID,
+		"e164-phone":   isE164,
+		"iban":         isIBAN,
+		"country-code": isCountryCode,
+		"url":          isURL,
+		"credit-card":  isCreditCard,
+		"isbn":         isISBN,
+		"ean":          isEAN,
+		"vin":          isVIN,
+	}
+)
+
+// Register adds a format, or replaces the one with the same name. Registered formats
+// apply to the runs that enable them.
+func Register(name string, f Func) {
+	mu.Lock()
+	defer mu.Unlock()
+	registry[name] = f
+}
+
+// Names returns the registered formats, sorted.
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Lookup returns the validators of the named formats, or an error naming the first
+// unknown one.
+func Lookup(names []string) (map[string]Func, error) {
+	mu.RLock()
+	out := make(map[string]Func, len(names))
+	var unknown string
+	for _, name := range names {
+		f, ok := registry[name]
+		if !ok {
+			unknown = name
+			break
+		}
+		out[name] = f
+	}
+	mu.RUnlock()
+	if unknown != "" {
+		return nil, fmt.Errorf("unknown format '%s' (registered: %s)", unknown, strings.Join(Names(), ", "))
+	}
+	return out, nil
+}
+
+var (
+	uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	e164Pattern = regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`)
+	ibanPattern = regexp.MustCompile(`^[A-Z]{2}[0-9]{2}[A-Z0-9]{11,30}$`)
+)
+
+// isUUID accepts the 8-4-4-4-12 hex form of RFC 4122.
+func isUUID(v string) bool {
+	return uuidPattern.MatchString(v)
+}
+
+// isE164 accepts international phone numbers such as +14155552671: a plus, a country
+// code and at most 15 digits in total.
+func isE164(v string) bool {
+	return e164Pattern.MatchString(v)
+}
+
+// isIBAN checks the shape and the ISO 7064 mod-97 check digits of an IBAN written
+// without spaces, such as DE89370400440532013000.
+func isIBAN(v string) bool {
+	if !ibanPattern.MatchString(v) {
+		return false
+	}
+	if n, ok := ibanLengths[v[:2]]; ok && len(v) != n {
+		return false
+	}
+	// Move the country code and check digits to the end, and replace letters by 10..35
+	var digits strings.Builder
+	for _, r := range v[4:] + v[:4] {
+		if r >= 'A' && r <= 'Z' {
+			fmt.Fprintf(&digits, "%d", r-'A'+10)
+		} else {
+			digits.WriteRune(r)
+		}
+	}
+	n, _ := new(big.Int).SetString(digits.String(), 10)
+	return new(big.Int).Mod(n, big.NewInt(97)).Int64() == 1
+}
+
+// ibanLengths are the IBAN lengths of common countries; others only get the generic
+// length range.
+var ibanLengths = map[string]int{
+	"AT": 20, "BE": 16, "BG": 22, "CH": 21, "CY": 28, "CZ": 24, "DE": 22, "DK": 18, "EE": 20, "ES": 24,
+	"FI": 18, "FR": 27, "GB": 22, "GR": 27, "HR": 21, "HU": 28, "IE": 22, "IS": 26, "IT": 27, "LI": 21,
+	"LT": 20, "LU": 20, "LV": 21, "MT": 31, "NL": 18, "NO": 15, "PL": 28, "PT": 25, "RO": 24, "SE": 24,
+	"SI": 19, "SK": 24,
+}
+
+// isCountryCode accepts ISO 3166-1 alpha-2 codes in upper case.
+func isCountryCode(v string) bool {
+	return len(v) == 2 && strings.Contains(countryCodes, " "+v+" ")
+}
+
+// countryCodes are the officially assigned ISO 3166-1 alpha-2 codes.
+const countryCodes = " AD AE AF AG AI AL AM AO AQ AR AS AT AU AW AX AZ BA BB BD BE BF BG BH BI BJ BL BM BN BO BQ BR BS BT BV BW BY BZ " +
+	"CA CC CD CF CG CH CI CK CL CM CN CO CR CU CV CW CX CY CZ DE DJ DK DM DO DZ EC EE EG EH ER ES ET FI FJ FK FM FO FR " +
+	"GA GB GD GE GF GG GH GI GL GM GN GP GQ GR GS GT GU GW GY HK HM HN HR HT HU ID IE IL IM IN IO IQ IR IS IT JE JM JO JP " +
+	"KE KG KH KI KM KN KP KR KW KY KZ LA LB LC LI LK LR LS LT LU LV LY MA MC MD ME MF MG MH MK ML MM MN MO MP MQ MR MS MT " +
+	"MU MV MW MX MY MZ NA NC NE NF NG NI NL NO NP NR NU NZ OM PA PE PF PG PH PK PL PM PN PR PS PT PW PY QA RE RO RS RU RW " +
+	"SA SB SC SD SE SG SH SI SJ SK SL SM SN SO SR SS ST SV SX SY SZ TC TD TF TG TH TJ TK TL TM TN TO TR TT TV TW TZ UA UG " +
+	"UM US UY UZ VA VC VE VG VI VN VU WF WS YE YT ZA ZM ZW "
+
+// isURL accepts absolute http and https URLs with a host.
+func isURL(v string) bool {
+	u, err := url.Parse(v)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
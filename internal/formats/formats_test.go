@@ -0,0 +1,53 @@
+package formats
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuiltinFormats(t *testing.T) {
+	tests := []struct {
+		format  string
+		valid   []string
+		invalid []string
+	}{
+		{"uuid", []string{"123e4567-e89b-12d3-a456-426614174000"}, []string{"123e4567e89b12d3a456426614174000", "not-a-uuid"}},
+		{"e164-phone", []string{"+14155552671", "+442071838750"}, []string{"14155552671", "+0123", "+1 415 555 2671"}},
+		{"iban", []string{"DE89370400440532013000", "GB82WEST12345698765432", "NL91ABNA0417164300"}, []string{"DE89370400440532013001", "DE8937040044053201300", "de89370400440532013000"}},
+		{"country-code", []string{"US", "DE", "JP"}, []string{"us", "XX", "USA", ""}},
+		{"url", []string{"https://example.com/a?b=c", "http://localhost:8080"}, []string{"example.com", "ftp://example.com", "https://"}},
+		{"credit-card", []string{"4111111111111111", "4111 1111 1111 1111", "5500-0000-0000-0004"}, []string{"4111111111111112", "4111", "4111a11111111111"}},
+		{"isbn", []string{"0-306-40615-2", "080442957X", "978-0-306-40615-7", "9791090636071"}, []string{"0-306-40615-3", "9780306406158", "1230306406157", "03064061"}},
+		{"ean", []string{"4006381333931", "036000291452", "96385074"}, []string{"4006381333932", "40063813339", "400638133393a"}},
+		{"vin", []string{"1M8GDM9AXKP042788", "11111111111111111"}, []string{"1M8GDM9A1KP042788", "1M8GDM9AXKP04278", "IM8GDM9AXKP042788"}},
+	}
+	for _, tt := range tests {
+		funcs, err := Lookup([]string{tt.format})
+		if err != nil {
+			t.Fatalf("Lookup(%s): %v", tt.format, err)
+		}
+		f := funcs[tt.format]
+		for _, v := range tt.valid {
+			if !f(v) {
+				t.Errorf("%s: expected %q to be valid", tt.format, v)
+			}
+		}
+		for _, v := range tt.invalid {
+			if f(v) {
+				t.Errorf("%s: expected %q to be invalid", tt.format, v)
+			}
+		}
+	}
+}
+
+func TestRegisterAndLookup(t *testing.T) {
+	Register("test-upper", func(v string) bool { return v == strings.ToUpper(v) })
+	funcs, err := Lookup([]string{"test-upper", "iban"})
+	if err != nil || len(funcs) != 2 || !funcs["test-upper"]("AB") || funcs["test-upper"]("ab") {
+		t.Errorf("Expected the registered format, got %v, %v", funcs, err)
+	}
+	_, err = Lookup([]string{"iban", "isbn-13"})
+	if err == nil || !strings.Contains(err.Error(), "unknown format 'isbn-13'") || !strings.Contains(err.Error(), "test-upper") {
+		t.Errorf("Expected an unknown format error listing the registered ones, got %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package geo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateWKT(t *testing.T) {
+	valid := []string{
+		"POINT (30 10)",
+		"point(30 10)",
+		"POINT Z (1 2 3)",
+		"POINTZM (1 2 3 4)",
+		"POINT EMPTY",
+		"SRID=4326;POINT(-71.06 42.36)",
+		"LINESTRING (30 10, 10 30, 40 40)",
+		"POLYGON ((30 10, 40 40, 20 40, 10 20, 30 10), (20 30, 35 35, 30 20, 20 30))",
+		"MULTIPOINT ((10 40), (40 30))",
+		"MULTIPOINT (10 40, 40 30)",
+		"MULTILINESTRING ((10 10, 20 20), (40 40, 30 30))",
+		"MULTIPOLYGON (((30 20, 45 40, 10 40, 30 20)), ((15 5, 40 10, 10 20, 15 5)))",
+		"GEOMETRYCOLLECTION (POINT (40 10), LINESTRING (10 10, 20 20))",
+		"POINT (1.5e2 -3)",
+	}
+	for _, s := range valid {
+		if err := ValidateWKT(s); err != nil {
+			t.Errorf("ValidateWKT(%q): %v", s, err)
+		}
+	}
+	invalid := map[string]string{
+		"":                                "expected a geometry type",
+		"CIRCLE (1 2)":                    "unknown geometry type 'CIRCLE'",
+		"POINT (30)":                      "expected 2 to 4 coordinates, got 1",
+		"POINT (30 10":                    "expected ')'",
+		"POINT (30 10) x":                 "unexpected",
+		"LINESTRING (30 10)":              "at least 2 items",
+		"POLYGON ((30 10, 40 40, 30 10))": "at least 4 items",
+		"LINESTRING (1 2, 3 4 5)":         "expected 2 coordinates, got 3",
+		"POINT (1-2 3)":                   "invalid number '1-2'",
+		"SRID=x;POINT (1 2)":              "SRID 'x' is not an integer",
+	}
+	for s, want := range invalid {
+		if err := ValidateWKT(s); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ValidateWKT(%q) = %v, want an error containing %q", s, err, want)
+		}
+	}
+}
+
+func TestValidateGeoJSON(t *testing.T) {
+	valid := []string{
+		`{"type":"Point","coordinates":[30,10]}`,
+		`{"type":"LineString","coordinates":[[30,10],[10,30,5]]}`,
+		`{"type":"Polygon","coordinates":[[[30,10],[40,40],[20,40],[30,10]]]}`,
+		`{"type":"MultiPolygon","coordinates":[[[[30,10],[40,40],[20,40],[30,10]]]]}`,
+		`{"type":"GeometryCollection","geometries":[{"type":"Point","coordinates":[1,2]}]}`,
+		`{"type":"Feature","geometry":null,"properties":{}}`,
+		`{"type":"FeatureCollection","features":[{"type":"Feature","geometry":{"type":"Point","coordinates":[1,2]}}]}`,
+	}
+	for _, s := range valid {
+		if err := ValidateGeoJSON(s); err != nil {
+			t.Errorf("ValidateGeoJSON(%s): %v", s, err)
+		}
+	}
+	invalid := map[string]string{
+		`{"type":"Point"`:                                                                "not JSON",
+		`{"type":"Circle","coordinates":[1,2]}`:                                          "unknown type 'Circle'",
+		`{"type":"Point","coordinates":[1]}`:                                             "at least 2 numbers",
+		`{"type":"Point"}`:                                                               "has no coordinates",
+		`{"type":"LineString","coordinates":[[1,2]]}`:                                    "at least 2 positions",
+		`{"type":"Polygon","coordinates":[[[1,2],[3,4],[5,6],[7,8]]]}`:                   "not closed",
+		`{"type":"Feature","geometry":{"type":"Feature","geometry":null}}`:               "expected a geometry",
+		`{"type":"FeatureCollection","features":[{"type":"Point","coordinates":[1,2]}]}`: "feature 1",
+	}
+	for s, want := range invalid {
+		if err := ValidateGeoJSON(s); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ValidateGeoJSON(%s) = %v, want an error containing %q", s, err, want)
+		}
+	}
+}
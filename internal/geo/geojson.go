@@ -0,0 +1,134 @@
+package geo
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ValidateGeoJSON returns an error describing the first problem of a GeoJSON geometry,
+// Feature or FeatureCollection (RFC 7946), such as {"type":"Point","coordinates":[30,10]}.
+func ValidateGeoJSON(s string) error {
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return fmt.Errorf("not JSON: %v", err)
+	}
+	return geoJSONObject(v, "")
+}
+
+// geoJSONObject checks an object whose type is want, or any GeoJSON type when want is "".
+func geoJSONObject(v interface{}, want string) error {
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("expected an object")
+	}
+	typ, _ := obj["type"].(string)
+	_, hasCoordinates := geometryTypes[typ]
+	isGeometry := hasCoordinates || typ == "GeometryCollection"
+	if want != "" && typ != want && !(want == "geometry" && isGeometry) {
+		return fmt.Errorf("expected a %s, got type '%s'", want, typ)
+	}
+	switch typ {
+	case "Feature":
+		g, ok := obj["geometry"]
+		if !ok {
+			return fmt.Errorf("Feature has no geometry member")
+		}
+		if g == nil {
+			return nil
+		}
+		return geoJSONObject(g, "geometry")
+	case "FeatureCollection":
+		features, ok := obj["features"].([]interface{})
+		if !ok {
+			return fmt.Errorf("FeatureCollection has no features array")
+		}
+		for i, f := range features {
+			if err := geoJSONObject(f, "Feature"); err != nil {
+				return fmt.Errorf("feature %d: %w", i+1, err)
+			}
+		}
+		return nil
+	case "GeometryCollection":
+		geometries, ok := obj["geometries"].([]interface{})
+		if !ok {
+			return fmt.Errorf("GeometryCollection has no geometries array")
+		}
+		for i, g := range geometries {
+			if err := geoJSONObject(g, "geometry"); err != nil {
+				return fmt.Errorf("geometry %d: %w", i+1, err)
+			}
+		}
+		return nil
+	}
+	depth, ok := geometryTypes[typ]
+	if !ok {
+		return fmt.Errorf("unknown type '%s'", typ)
+	}
+	coordinates, ok := obj["coordinates"]
+	if !ok {
+		return fmt.Errorf("%s has no coordinates", typ)
+	}
+	if err := coordinateArray(coordinates, depth, typ); err != nil {
+		return fmt.Errorf("%s coordinates: %w", typ, err)
+	}
+	return nil
+}
+
+// geometryTypes are the geometry types with coordinates, and how deeply their positions
+// are nested: 0 for a single position.
+var geometryTypes = map[string]int{
+	"Point":           0,
+	"MultiPoint":      1,
+	"LineString":      1,
+	"MultiLineString": 2,
+	"Polygon":         2,
+	"MultiPolygon":    3,
+}
+
+// coordinateArray checks the positions nested depth arrays deep in v, and the minimum
+// sizes of line strings and polygon rings.
+func coordinateArray(v interface{}, depth int, typ string) error {
+	if depth == 0 {
+		return position(v)
+	}
+	list, ok := v.([]interface{})
+	if !ok {
+		return fmt.Errorf("expected an array")
+	}
+	// The innermost arrays of line strings and rings have a minimum length
+	if depth == 1 {
+		switch typ {
+		case "LineString", "MultiLineString":
+			if len(list) < 2 {
+				return fmt.Errorf("line string needs at least 2 positions, got %d", len(list))
+			}
+		case "Polygon", "MultiPolygon":
+			if len(list) < 4 {
+				return fmt.Errorf("linear ring needs at least 4 positions, got %d", len(list))
+			}
+		}
+	}
+	for _, item := range list {
+		if err := coordinateArray(item, depth-1, typ); err != nil {
+			return err
+		}
+	}
+	if depth == 1 && (typ == "Polygon" || typ == "MultiPolygon") && fmt.Sprint(list[0]) != fmt.Sprint(list[len(list)-1]) {
+		return fmt.Errorf("linear ring is not closed")
+	}
+	return nil
+}
+
+// position checks an array of two or more numbers.
+func position(v interface{}) error {
+	list, ok := v.([]interface{})
+	if !ok || len(list) < 2 {
+		return fmt.Errorf("position must be an array of at least 2 numbers")
+	}
+	for _, n := range list {
+		if _, ok := n.(float64); !ok {
+			return fmt.Errorf("position must be an array of at least 2 numbers")
+		}
+	}
+	return nil
+}
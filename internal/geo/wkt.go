@@ -0,0 +1,212 @@
+// Package geo checks the syntax of geometries written as WKT or GeoJSON.
+package geo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ValidateWKT returns an error describing the first syntax error of a WKT geometry, such
+// as POINT (30 10) or POLYGON ((30 10, 40 40, 20 40, 30 10)). The EWKT SRID prefix, Z, M
+// and ZM coordinates and EMPTY geometries are accepted.
+func ValidateWKT(s string) error {
+	p := &wktParser{s: s}
+	if upper := strings.ToUpper(s); strings.HasPrefix(upper, "SRID=") {
+		i := strings.IndexByte(s, ';')
+		if i < 0 {
+			return fmt.Errorf("SRID prefix needs a ';'")
+		}
+		if _, err := strconv.Atoi(s[5:i]); err != nil {
+			return fmt.Errorf("SRID '%s' is not an integer", s[5:i])
+		}
+		p.pos = i + 1
+	}
+	if err := p.geometry(); err != nil {
+		return err
+	}
+	if p.skipSpace(); p.pos < len(p.s) {
+		return p.errorf("unexpected '%s' after the geometry", p.s[p.pos:])
+	}
+	return nil
+}
+
+type wktParser struct {
+	s    string
+	pos  int
+	dims int // Coordinates per position: 2 to 4, 0 until known
+}
+
+func (p *wktParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%s at position %d", fmt.Sprintf(format, args...), p.pos+1)
+}
+
+func (p *wktParser) skipSpace() {
+	for p.pos < len(p.s) && strings.IndexByte(" \t\r\n", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// word reads a keyword in upper case, or "" when the next token is not a word.
+func (p *wktParser) word() string {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.s) && (p.s[p.pos] >= 'A' && p.s[p.pos] <= 'Z' || p.s[p.pos] >= 'a' && p.s[p.pos] <= 'z') {
+		p.pos++
+	}
+	return strings.ToUpper(p.s[start:p.pos])
+}
+
+// accept reports whether the next token is c, consuming it when it is.
+func (p *wktParser) accept(c byte) bool {
+	p.skipSpace()
+	if p.pos < len(p.s) && p.s[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *wktParser) expect(c byte) error {
+	if !p.accept(c) {
+		return p.errorf("expected '%c'", c)
+	}
+	return nil
+}
+
+// geometry reads a tagged geometry.
+func (p *wktParser) geometry() error {
+	tag := p.word()
+	if tag == "" {
+		return p.errorf("expected a geometry type")
+	}
+	// A dimension modifier may be attached (POINTZ) or written apart (POINT Z)
+	modifier := ""
+	for _, m := range []string{"ZM", "Z", "M"} {
+		if _, known := wktBodies[tag]; !known && strings.HasSuffix(tag, m) {
+			tag, modifier = strings.TrimSuffix(tag, m), m
+		}
+	}
+	body, ok := wktBodies[tag]
+	if !ok {
+		return p.errorf("unknown geometry type '%s'", tag+modifier)
+	}
+	save := p.pos
+	if modifier == "" {
+		if modifier = p.word(); modifier != "Z" && modifier != "M" && modifier != "ZM" {
+			modifier, p.pos = "", save
+		}
+	}
+	p.dims = map[string]int{"Z": 3, "M": 3, "ZM": 4}[modifier]
+	save = p.pos
+	if p.word() == "EMPTY" {
+		return nil
+	}
+	p.pos = save
+	return body(p)
+}
+
+// wktBodies read the parenthesized part of each geometry type.
+var wktBodies map[string]func(*wktParser) error
+
+func init() {
+	wktBodies = map[string]func(*wktParser) error{
+		"POINT":              func(p *wktParser) error { return p.list(1, 1, (*wktParser).position) },
+		"LINESTRING":         lineString,
+		"POLYGON":            polygon,
+		"MULTIPOINT":         func(p *wktParser) error { return p.list(1, 0, multiPointMember) },
+		"MULTILINESTRING":    func(p *wktParser) error { return p.list(1, 0, lineString) },
+		"MULTIPOLYGON":       func(p *wktParser) error { return p.list(1, 0, polygon) },
+		"GEOMETRYCOLLECTION": func(p *wktParser) error { return p.list(1, 0, (*wktParser).geometry) },
+	}
+}
+
+func lineString(p *wktParser) error {
+	return p.list(2, 0, (*wktParser).position)
+}
+
+// polygon reads rings of at least four positions.
+func polygon(p *wktParser) error {
+	return p.list(1, 0, func(p *wktParser) error { return p.list(4, 0, (*wktParser).position) })
+}
+
+// multiPointMember reads a point of a MULTIPOINT, with or without parentheses.
+func multiPointMember(p *wktParser) error {
+	if p.accept('(') {
+		if err := p.position(); err != nil {
+			return err
+		}
+		return p.expect(')')
+	}
+	return p.position()
+}
+
+// list reads a parenthesized, comma-separated list of at least min and, unless max is 0,
+// at most max items.
+func (p *wktParser) list(min, max int, item func(*wktParser) error) error {
+	if err := p.expect('('); err != nil {
+		return err
+	}
+	n := 0
+	for {
+		if err := item(p); err != nil {
+			return err
+		}
+		n++
+		if !p.accept(',') {
+			break
+		}
+	}
+	if err := p.expect(')'); err != nil {
+		return err
+	}
+	if n < min || max > 0 && n > max {
+		return p.errorf("expected %s, got %d", countRange(min, max), n)
+	}
+	return nil
+}
+
+func isNumber(s string) bool {
+	_, err := strconv.ParseFloat(s, 64)
+	return err == nil
+}
+
+func countRange(min, max int) string {
+	switch {
+	case min == max:
+		return fmt.Sprintf("%d items", min)
+	case max == 0:
+		return fmt.Sprintf("at least %d items", min)
+	}
+	return fmt.Sprintf("%d to %d items", min, max)
+}
+
+// position reads the coordinates of one position; every position of a geometry has the
+// same number of coordinates.
+func (p *wktParser) position() error {
+	n := 0
+	for {
+		p.skipSpace()
+		start := p.pos
+		for p.pos < len(p.s) && strings.IndexByte("0123456789+-.eE", p.s[p.pos]) >= 0 {
+			p.pos++
+		}
+		if start == p.pos {
+			break
+		}
+		if token := p.s[start:p.pos]; !isNumber(token) {
+			p.pos = start
+			return p.errorf("invalid number '%s'", token)
+		}
+		n++
+	}
+	switch {
+	case n < 2 || n > 4:
+		return p.errorf("expected 2 to 4 coordinates, got %d", n)
+	case p.dims == 0:
+		p.dims = n
+	case n != p.dims:
+		return p.errorf("expected %d coordinates, got %d", p.dims, n)
+	}
+	return nil
+}
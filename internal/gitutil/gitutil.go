@@ -0,0 +1,136 @@
+// Package gitutil asks git about the repository csvlinter runs in.
+package gitutil
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// run executes git in dir and returns its standard output.
+func run(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("git %s: %s", args[0], msg)
+	}
+	return stdout.String(), nil
+}
+
+// RelativeRoot returns the path from dir to the root of its working tree, such as "."
+// or "../..". Joining it with a path from git keeps the result relative to dir.
+func RelativeRoot(dir string) (string, error) {
+	out, err := run(dir, "rev-parse", "--show-cdup")
+	if err != nil {
+		return "", err
+	}
+	cdup := strings.TrimSpace(out)
+	if cdup == "" {
+		return ".", nil
+	}
+	return filepath.Clean(filepath.FromSlash(cdup)), nil
+}
+
+// HooksDir returns the hooks directory of the repository containing dir, honoring
+// core.hooksPath and worktrees.
+func HooksDir(dir string) (string, error) {
+	out, err := run(dir, "rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", err
+	}
+	hooks := filepath.FromSlash(strings.TrimSpace(out))
+	if !filepath.IsAbs(hooks) {
+		hooks = filepath.Join(dir, hooks)
+	}
+	return hooks, nil
+}
+
+// StagedFiles returns the files added, copied, modified or renamed in the index, as
+// slash-separated paths relative to the top level. Deleted files are left out.
+func StagedFiles(dir string) ([]string, error) {
+	out, err := run(dir, "diff", "--cached", "--name-only", "--diff-filter=ACMR", "-z")
+	if err != nil {
+		return nil, err
+	}
+	return splitNul(out), nil
+}
+
+// OpenStaged opens the version of path, relative to dir, that is staged in the index,
+// which may differ from the working tree. A path with nothing staged is an
+// fs.ErrNotExist error.
+func OpenStaged(dir, path string) (io.ReadCloser, error) {
+	if filepath.IsAbs(path) {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return nil, err
+		}
+		if path, err = filepath.Rel(abs, path); err != nil {
+			return nil, err
+		}
+	}
+	// "./" makes git read the path relative to dir rather than to the top level
+	spec := ":./" + filepath.ToSlash(filepath.Clean(path))
+	id, err := run(dir, "rev-parse", "--verify", "--quiet", spec)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: path, Err: fs.ErrNotExist}
+	}
+	cmd := exec.Command("git", "-C", dir, "cat-file", "blob", strings.TrimSpace(id))
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("git cat-file: %w", err)
+	}
+	return &blob{ReadCloser: out, cmd: cmd}, nil
+}
+
+// blob streams a staged file from git cat-file.
+type blob struct {
+	io.ReadCloser
+	cmd *exec.Cmd
+}
+
+// Close stops reading and waits for git. The blob was found already, so git only fails
+// here when it was stopped early.
+func (b *blob) Close() error {
+	err := b.ReadCloser.Close()
+	b.cmd.Wait()
+	return err
+}
+
+// ChangedFiles returns the files added, copied, modified or renamed since ref, as
+// slash-separated paths relative to the top level. Changes are taken from the merge base
+// of ref and HEAD to the working tree, so on a branch only the branch's own changes,
+// committed or not, are listed.
+func ChangedFiles(dir, ref string) ([]string, error) {
+	base, err := run(dir, "merge-base", ref, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	out, err := run(dir, "diff", "--name-only", "--diff-filter=ACMR", "-z", strings.TrimSpace(base), "--")
+	if err != nil {
+		return nil, err
+	}
+	return splitNul(out), nil
+}
+
+func splitNul(out string) []string {
+	var files []string
+	for _, f := range strings.Split(out, "\x00") {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	return files
+}
@@ -0,0 +1,147 @@
+package gitutil
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// initRepo creates a repository with one commit and returns its directory.
+func initRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	git(t, dir, "init", "-q")
+	git(t, dir, "config", "user.email", "test@example.com")
+	git(t, dir, "config", "user.name", "test")
+	writeFile(t, dir, "README", "readme")
+	git(t, dir, "add", ".")
+	git(t, dir, "commit", "-q", "-m", "init")
+	return dir
+}
+
+func git(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	if _, err := run(dir, args...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	p := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOpenStaged(t *testing.T) {
+	dir := initRepo(t)
+	writeFile(t, dir, "data/a.csv", "id\nstaged\n")
+	git(t, dir, "add", "data/a.csv")
+	writeFile(t, dir, "data/a.csv", "id\nworking tree\n")
+	writeFile(t, dir, "data/new.csv", "id\n")
+
+	for _, path := range []string{"a.csv", "../data/a.csv", filepath.Join(dir, "data", "a.csv")} {
+		r, err := OpenStaged(filepath.Join(dir, "data"), path)
+		if err != nil {
+			t.Fatalf("%s: OpenStaged failed: %v", path, err)
+		}
+		got, err := io.ReadAll(r)
+		r.Close()
+		if err != nil || string(got) != "id\nstaged\n" {
+			t.Errorf("%s: want the staged content, got %q (%v)", path, got, err)
+		}
+	}
+	if _, err := OpenStaged(dir, "data/new.csv"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("want fs.ErrNotExist for an unstaged file, got %v", err)
+	}
+}
+
+func TestStagedFiles(t *testing.T) {
+	dir := initRepo(t)
+	writeFile(t, dir, "data/a.csv", "id\n1\n")
+	writeFile(t, dir, "b.csv", "id\n2\n")
+	writeFile(t, dir, "unstaged.csv", "id\n3\n")
+	git(t, dir, "add", "data/a.csv", "b.csv")
+	git(t, dir, "rm", "-q", "README")
+
+	files, err := StagedFiles(filepath.Join(dir, "data"))
+	if err != nil {
+		t.Fatalf("StagedFiles failed: %v", err)
+	}
+	if len(files) != 2 || files[0] != "b.csv" || files[1] != "data/a.csv" {
+		t.Errorf("Unexpected staged files: %v", files)
+	}
+}
+
+func TestChangedFiles(t *testing.T) {
+	dir := initRepo(t)
+	writeFile(t, dir, "old.csv", "id\n1\n")
+	git(t, dir, "add", ".")
+	git(t, dir, "commit", "-q", "-m", "old")
+	git(t, dir, "branch", "-q", "base")
+
+	git(t, dir, "checkout", "-q", "-b", "feature")
+	writeFile(t, dir, "committed.csv", "id\n1\n")
+	git(t, dir, "add", ".")
+	git(t, dir, "commit", "-q", "-m", "feature")
+	writeFile(t, dir, "old.csv", "id\n2\n")
+
+	// A commit on base after the branch point is not a change of the branch
+	git(t, dir, "checkout", "-q", "base")
+	writeFile(t, dir, "other.csv", "id\n1\n")
+	git(t, dir, "add", "other.csv")
+	git(t, dir, "commit", "-q", "-m", "other")
+	git(t, dir, "checkout", "-q", "feature")
+
+	files, err := ChangedFiles(dir, "base")
+	if err != nil {
+		t.Fatalf("ChangedFiles failed: %v", err)
+	}
+	if len(files) != 2 || files[0] != "committed.csv" || files[1] != "old.csv" {
+		t.Errorf("Unexpected changed files: %v", files)
+	}
+
+	if _, err := ChangedFiles(dir, "no-such-ref"); err == nil {
+		t.Error("Expected an error for an unknown ref")
+	}
+}
+
+func TestRelativeRootAndHooksDir(t *testing.T) {
+	dir := initRepo(t)
+	sub := filepath.Join(dir, "a", "b")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for d, want := range map[string]string{dir: ".", sub: filepath.Join("..", "..")} {
+		root, err := RelativeRoot(d)
+		if err != nil {
+			t.Fatalf("RelativeRoot failed: %v", err)
+		}
+		if root != want {
+			t.Errorf("RelativeRoot(%s) = %s, want %s", d, root, want)
+		}
+	}
+
+	hooks, err := HooksDir(dir)
+	if err != nil {
+		t.Fatalf("HooksDir failed: %v", err)
+	}
+	if hooks != filepath.Join(dir, ".git", "hooks") {
+		t.Errorf("Unexpected hooks dir: %s", hooks)
+	}
+
+	if _, err := RelativeRoot(t.TempDir()); err == nil {
+		t.Error("Expected an error outside a repository")
+	}
+}
@@ -0,0 +1,183 @@
+// Package i18n translates the human-readable parts of reports: the labels of pretty
+// output, finding messages and rule descriptions. Machine formats stay in English, and
+// rule IDs are never translated.
+//
+// Catalogs are JSON files in catalogs/, one per language, mapping English texts to their
+// translation. English texts are the Go format strings the reporter and the validators
+// use, such as "Total Rows: %d"; translations refer to the values with {1}, {2}, ... in
+// the order of the verbs, so they can be reordered. Finding messages are matched against
+// the English formats, so a message built as "column count mismatch: expected 3, got 2"
+// is translated by the entry for "column count mismatch: expected %d, got %d". Texts
+// without an entry are left in English.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// English is the language of the source texts, which needs no catalog.
+const English = "en"
+
+// Languages lists the languages reports can be rendered in.
+var Languages = []string{English, "de", "fr", "ja"}
+
+//go:embed catalogs/*.json
+var catalogFiles embed.FS
+
+// catalogFile is the content of a catalog file.
+type catalogFile struct {
+	Messages map[string]string `json:"messages"` // English text or format -> translation
+	Rules    map[string]string `json:"rules"`    // Rule ID -> translated description
+}
+
+// Catalog renders texts in one language. The nil Catalog, like English, returns texts
+// unchanged.
+type Catalog struct {
+	lang     string
+	messages map[string]string
+	patterns []pattern
+	rules    map[string]string
+}
+
+// pattern matches finding messages built from an English format.
+type pattern struct {
+	format string
+	re     *regexp.Regexp
+}
+
+// verb matches the fmt verbs of a format, and %% which is not one.
+var verb = regexp.MustCompile(`%[-+# 0]*[0-9]*(?:\.[0-9]+)?[a-zA-Z%]`)
+
+// Lookup returns the catalog of lang, one of Languages; "" is English.
+func Lookup(lang string) (*Catalog, error) {
+	if lang == "" || lang == English {
+		return nil, nil
+	}
+	data, err := catalogFiles.ReadFile("catalogs/" + lang + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("unknown language '%s': use %s", lang, strings.Join(Languages, ", "))
+	}
+	var f catalogFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("catalog %s: %w", lang, err)
+	}
+	c := &Catalog{lang: lang, messages: f.Messages, rules: f.Rules}
+	for format := range f.Messages {
+		if verb.MatchString(strings.ReplaceAll(format, "%%", "")) {
+			c.patterns = append(c.patterns, pattern{format, compile(format)})
+		}
+	}
+	// Longer formats are more specific, so they are tried first
+	sort.Slice(c.patterns, func(i, j int) bool {
+		a, b := c.patterns[i].format, c.patterns[j].format
+		if len(a) != len(b) {
+			return len(a) > len(b)
+		}
+		return a < b
+	})
+	return c, nil
+}
+
+// compile turns a format into a regular expression matching what it prints, capturing
+// the values of its verbs.
+func compile(format string) *regexp.Regexp {
+	var sb strings.Builder
+	sb.WriteString("^")
+	last := 0
+	for _, loc := range verb.FindAllStringIndex(format, -1) {
+		sb.WriteString(regexp.QuoteMeta(format[last:loc[0]]))
+		switch v := format[loc[0]:loc[1]]; v[len(v)-1] {
+		case '%':
+			sb.WriteString("%")
+		case 'd':
+			sb.WriteString(`(-?[0-9]+)`)
+		case 'q':
+			sb.WriteString(`("(?:[^"\\]|\\.)*")`)
+		default:
+			sb.WriteString(`(.*?)`)
+		}
+		last = loc[1]
+	}
+	sb.WriteString(regexp.QuoteMeta(format[last:]))
+	sb.WriteString("$")
+	return regexp.MustCompile(sb.String())
+}
+
+// Lang returns the catalog's language.
+func (c *Catalog) Lang() string {
+	if c == nil {
+		return English
+	}
+	return c.lang
+}
+
+// Sprintf formats args by format, in the catalog's language when it translates format.
+func (c *Catalog) Sprintf(format string, args ...any) string {
+	translation, ok := c.translation(format)
+	if !ok {
+		return fmt.Sprintf(format, args...)
+	}
+	values := make([]string, 0, len(args))
+	for _, v := range verb.FindAllString(format, -1) {
+		if v == "%%" || len(values) == len(args) {
+			continue
+		}
+		values = append(values, fmt.Sprintf(v, args[len(values)]))
+	}
+	return fill(translation, values)
+}
+
+// Message translates a finding message built from one of the catalog's formats, or
+// returns it unchanged.
+func (c *Catalog) Message(message string) string {
+	if c == nil {
+		return message
+	}
+	if translation, ok := c.messages[message]; ok {
+		return translation
+	}
+	for _, p := range c.patterns {
+		if m := p.re.FindStringSubmatch(message); m != nil {
+			return fill(c.messages[p.format], m[1:])
+		}
+	}
+	return message
+}
+
+// Rule returns the description of rule id in the catalog's language, or description,
+// the English one, when it has none.
+func (c *Catalog) Rule(id, description string) string {
+	if c == nil {
+		return description
+	}
+	if d, ok := c.rules[id]; ok {
+		return d
+	}
+	return description
+}
+
+func (c *Catalog) translation(format string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	t, ok := c.messages[format]
+	return t, ok
+}
+
+// fill replaces the placeholders {1}, {2}, ... of a translation with values.
+func fill(translation string, values []string) string {
+	if len(values) == 0 {
+		return translation
+	}
+	pairs := make([]string, 0, 2*len(values))
+	for i, v := range values {
+		pairs = append(pairs, "{"+strconv.Itoa(i+1)+"}", v)
+	}
+	return strings.NewReplacer(pairs...).Replace(translation)
+}
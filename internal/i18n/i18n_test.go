@@ -0,0 +1,110 @@
+package i18n
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/rules"
+)
+
+func TestCatalogs(t *testing.T) {
+	placeholder := regexp.MustCompile(`\{[0-9]+\}`)
+	for _, lang := range Languages[1:] {
+		c, err := Lookup(lang)
+		if err != nil {
+			t.Fatalf("Lookup(%s) failed: %v", lang, err)
+		}
+		for format, translation := range c.messages {
+			verbs := 0
+			for _, v := range verb.FindAllString(format, -1) {
+				if v != "%%" {
+					verbs++
+				}
+			}
+			var want, got []string
+			for i := 1; i <= verbs; i++ {
+				want = append(want, "{"+strconv.Itoa(i)+"}")
+			}
+			for _, p := range placeholder.FindAllString(translation, -1) {
+				if !slices.Contains(got, p) {
+					got = append(got, p)
+				}
+			}
+			sort.Strings(got)
+			if strings.Join(got, "") != strings.Join(want, "") {
+				t.Errorf("%s: %q translates %d values with placeholders %v", lang, format, verbs, got)
+			}
+		}
+		for _, r := range rules.All() {
+			if _, ok := c.rules[r.ID]; !ok {
+				t.Errorf("%s: no description of rule %s", lang, r.ID)
+			}
+		}
+		if len(c.rules) != len(rules.All()) {
+			t.Errorf("%s: %d rule descriptions for %d rules", lang, len(c.rules), len(rules.All()))
+		}
+	}
+	// Every catalog translates the same texts
+	de, _ := Lookup("de")
+	for _, lang := range Languages[2:] {
+		c, _ := Lookup(lang)
+		for format := range de.messages {
+			if _, ok := c.messages[format]; !ok {
+				t.Errorf("%s: no translation of %q", lang, format)
+			}
+		}
+		if len(c.messages) != len(de.messages) {
+			t.Errorf("%s: %d messages, de has %d", lang, len(c.messages), len(de.messages))
+		}
+	}
+	if _, err := Lookup("xx"); err == nil || !strings.Contains(err.Error(), "de, fr, ja") {
+		t.Errorf("expected an unknown language to list the languages, got %v", err)
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	de, err := Lookup("de")
+	if err != nil {
+		t.Fatalf("Lookup failed: %v", err)
+	}
+	if got := de.Sprintf("Lines %d-%d (%d times)", 2, 9, 3); got != "Zeilen 2-9 (3-mal)" {
+		t.Errorf("Sprintf = %q", got)
+	}
+	if got := de.Sprintf(" (%.1f%% of the input, ~%d rows in total)", 12.34, 500); got != " (12.3 % der Eingabe, insgesamt ~500 Zeilen)" {
+		t.Errorf("Sprintf with %%%% = %q", got)
+	}
+	if got := de.Sprintf("not in the catalog: %d", 1); got != "not in the catalog: 1" {
+		t.Errorf("untranslated Sprintf = %q", got)
+	}
+
+	ja, _ := Lookup("ja")
+	messages := map[string]string{
+		"column count mismatch: expected 3, got 2":   "列数が一致しません: 期待値 3、実際 2",
+		"expected integer, but got string":           "integer が必要ですが、string でした",
+		"end_date is required when status == closed": "status == closed の場合、end_date は必須です",
+		"email value repeats line 4":                 "email の値が 4 行目と重複しています",
+		"id 7 repeats line 4":                        "id 7 が 4 行目と重複しています",
+		"value must be one of \"a\", \"b\"":          "値は \"a\", \"b\" のいずれかでなければなりません",
+		"something else entirely":                    "something else entirely",
+	}
+	for in, want := range messages {
+		if got := ja.Message(in); got != want {
+			t.Errorf("Message(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	var en *Catalog
+	if got := en.Message("row repeats line 2"); got != "row repeats line 2" {
+		t.Errorf("English Message = %q", got)
+	}
+	if got := en.Rule(rules.ColumnCount, "desc"); got != "desc" || en.Lang() != English {
+		t.Errorf("English Rule = %q, Lang = %q", got, en.Lang())
+	}
+	if got := ja.Rule(rules.ColumnCount, "desc"); got == "desc" {
+		t.Error("expected a Japanese description")
+	}
+}
@@ -0,0 +1,171 @@
+// Package manifest reads batch manifests, which list the files delivered together with
+// their expected row counts and SHA-256 hashes, and checks validation results against them.
+package manifest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/csvlinter/csvlinter/internal/rules"
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+// Entry is one file listed in a manifest. Rows and SHA256 are only checked when present.
+type Entry struct {
+	Name   string `json:"name"`             // Path relative to the manifest's directory, or absolute
+	Rows   *int   `json:"rows,omitempty"`   // Expected number of data rows
+	SHA256 string `json:"sha256,omitempty"` // Expected hex SHA-256 of the file's bytes
+}
+
+// Manifest lists the files of a batch.
+type Manifest struct {
+	Files []Entry `json:"files"`
+
+	dir string
+}
+
+// Load reads a manifest: a JSON object with a "files" array, or the array itself.
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	var m Manifest
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(data, &m.Files)
+	} else {
+		err = json.Unmarshal(data, &m)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse manifest '%s': %w", path, err)
+	}
+	for i, e := range m.Files {
+		if e.Name == "" {
+			return nil, fmt.Errorf("manifest '%s': entry %d has no name", path, i+1)
+		}
+	}
+	m.dir = filepath.Dir(path)
+	return &m, nil
+}
+
+// Path returns the path of e, resolving relative names against the manifest's directory.
+func (m *Manifest) Path(e Entry) string {
+	if filepath.IsAbs(e.Name) {
+		return e.Name
+	}
+	return filepath.Join(m.dir, filepath.FromSlash(e.Name))
+}
+
+// Missing returns a failed result for each listed file that does not exist.
+func (m *Manifest) Missing() []*validator.Results {
+	var out []*validator.Results
+	for _, e := range m.Files {
+		path := m.Path(e)
+		if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+			out = append(out, &validator.Results{
+				File:     path,
+				Errors:   []validator.Finding{{Severity: validator.SeverityError, Location: validator.Location{Line: 1}, Message: "file listed in the manifest is missing", Type: "manifest", RuleID: rules.ManifestMissing}},
+				Warnings: []validator.Finding{},
+			})
+		}
+	}
+	return out
+}
+
+// Present returns the paths of the listed files that are not missing: those that exist,
+// and those that cannot be checked, which then fail to open as unreadable.
+func (m *Manifest) Present() []string {
+	var out []string
+	for _, e := range m.Files {
+		path := m.Path(e)
+		if _, err := os.Stat(path); !errors.Is(err, fs.ErrNotExist) {
+			out = append(out, path)
+		}
+	}
+	return out
+}
+
+// Check compares each result with its manifest entry, adding errors for hash and row
+// count mismatches, and warns about validated files the manifest does not list. Results
+// of files that could not be read are left alone.
+func (m *Manifest) Check(results []*validator.Results) {
+	entries := make(map[string]Entry, len(m.Files))
+	for _, e := range m.Files {
+		entries[key(m.Path(e))] = e
+	}
+	for _, r := range results {
+		e, listed := entries[key(r.File)]
+		if !listed {
+			r.Warnings = append(r.Warnings, validator.Finding{Severity: validator.SeverityWarning, Location: validator.Location{Line: 1}, Message: "file is not listed in the manifest", Type: "manifest", RuleID: rules.ManifestUnlisted})
+			r.SortFindings()
+			continue
+		}
+		if unreadable(r) {
+			continue
+		}
+		if e.SHA256 != "" {
+			sum, err := hashFile(r.File)
+			if err == nil && !strings.EqualFold(sum, e.SHA256) {
+				r.Errors = append(r.Errors, validator.Finding{
+					Severity: validator.SeverityError,
+					Location: validator.Location{Line: 1},
+					Message:  fmt.Sprintf("SHA-256 %s does not match the manifest, %s", sum, e.SHA256),
+					Type:     "manifest",
+					RuleID:   rules.ManifestHash,
+				})
+			}
+		}
+		if e.Rows != nil && *e.Rows != r.TotalRows {
+			r.Errors = append(r.Errors, validator.Finding{
+				Severity: validator.SeverityError,
+				Location: validator.Location{Line: 1},
+				Message:  fmt.Sprintf("manifest declares %d rows, found %d", *e.Rows, r.TotalRows),
+				Type:     "manifest",
+				RuleID:   rules.ManifestRows,
+			})
+		}
+		r.Valid = len(r.Errors) == 0
+		r.SortFindings()
+	}
+}
+
+// unreadable reports whether r records a file that could not be opened or parsed.
+func unreadable(r *validator.Results) bool {
+	for _, e := range r.Errors {
+		if e.Type == "file" {
+			return true
+		}
+	}
+	return false
+}
+
+// key identifies a file path independent of how it was spelled.
+func key(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
+
+// hashFile returns the hex SHA-256 of the file at path.
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
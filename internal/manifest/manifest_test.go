@@ -0,0 +1,86 @@
+package manifest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/rules"
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	object := filepath.Join(dir, "manifest.json")
+	if err := os.WriteFile(object, []byte(`{"files":[{"name":"a.csv","rows":2},{"name":"sub/b.csv"}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m, err := Load(object)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(m.Files) != 2 || *m.Files[0].Rows != 2 || m.Path(m.Files[1]) != filepath.Join(dir, "sub", "b.csv") {
+		t.Errorf("Unexpected manifest: %+v", m.Files)
+	}
+
+	array := filepath.Join(dir, "list.json")
+	if err := os.WriteFile(array, []byte(`[{"name":"a.csv"}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if m, err := Load(array); err != nil || len(m.Files) != 1 {
+		t.Errorf("Expected a bare array to load, got %v, %v", m, err)
+	}
+
+	unnamed := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(unnamed, []byte(`{"files":[{"rows":1}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(unnamed); err == nil {
+		t.Error("Expected an error for an entry without a name")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	dir := t.TempDir()
+	content := []byte("id\n1\n2\n")
+	sum := sha256.Sum256(content)
+	for _, name := range []string{"good.csv", "bad.csv", "extra.csv"} {
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	two, three := 2, 3
+	m := &Manifest{dir: dir, Files: []Entry{
+		{Name: "good.csv", Rows: &two, SHA256: hex.EncodeToString(sum[:])},
+		{Name: "bad.csv", Rows: &three, SHA256: "00"},
+		{Name: "gone.csv"},
+		{Name: "good.csv/not-a-dir.csv"},
+	}}
+
+	results := func(name string) *validator.Results {
+		return &validator.Results{File: filepath.Join(dir, name), TotalRows: 2, Valid: true}
+	}
+	good, bad, extra := results("good.csv"), results("bad.csv"), results("extra.csv")
+	m.Check([]*validator.Results{good, bad, extra})
+
+	if !good.Valid || len(good.Errors) != 0 {
+		t.Errorf("Expected good.csv to match the manifest, got %+v", good.Errors)
+	}
+	if bad.Valid || len(bad.Errors) != 2 || bad.Errors[0].RuleID != rules.ManifestHash || bad.Errors[1].RuleID != rules.ManifestRows {
+		t.Errorf("Expected hash and row errors for bad.csv, got %+v", bad.Errors)
+	}
+	if !extra.Valid || len(extra.Warnings) != 1 || extra.Warnings[0].RuleID != rules.ManifestUnlisted {
+		t.Errorf("Expected an unlisted warning for extra.csv, got %+v", extra)
+	}
+
+	missing := m.Missing()
+	if len(missing) != 1 || missing[0].File != filepath.Join(dir, "gone.csv") || missing[0].Errors[0].RuleID != rules.ManifestMissing {
+		t.Errorf("Expected gone.csv to be missing, got %+v", missing)
+	}
+	// A file that cannot be checked is not missing, but left to fail as unreadable
+	if present := m.Present(); len(present) != 3 || present[2] != filepath.Join(dir, "good.csv", "not-a-dir.csv") {
+		t.Errorf("Expected two present files and one to fail reading, got %v", present)
+	}
+}
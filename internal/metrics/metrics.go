@@ -0,0 +1,173 @@
+// Package metrics exports validation results in the Prometheus text exposition format,
+// either as a node_exporter textfile or pushed to a Pushgateway.
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+// Job is the Pushgateway job name metrics are grouped under.
+const Job = "csvlinter"
+
+// Timeout bounds a single Pushgateway request.
+const Timeout = 10 * time.Second
+
+// Write renders metrics for each results set to w.
+func Write(w io.Writer, now time.Time, results ...*validator.Results) error {
+	var buf bytes.Buffer
+
+	family(&buf, "csvlinter_rows_total", "counter", "Data rows scanned per file.")
+	for _, r := range results {
+		sample(&buf, "csvlinter_rows_total", float64(r.TotalRows), "file", r.File)
+	}
+
+	family(&buf, "csvlinter_errors_total", "counter", "Validation errors per file and rule.")
+	for _, r := range results {
+		for _, c := range countByRule(r.Errors) {
+			sample(&buf, "csvlinter_errors_total", float64(c.count), "file", r.File, "rule", c.rule)
+		}
+	}
+
+	family(&buf, "csvlinter_warnings_total", "counter", "Validation warnings per file and rule.")
+	for _, r := range results {
+		for _, c := range countByRule(r.Warnings) {
+			sample(&buf, "csvlinter_warnings_total", float64(c.count), "file", r.File, "rule", c.rule)
+		}
+	}
+
+	family(&buf, "csvlinter_duration_seconds", "gauge", "Wall time spent validating the file.")
+	for _, r := range results {
+		d, _ := time.ParseDuration(r.Duration)
+		sample(&buf, "csvlinter_duration_seconds", d.Seconds(), "file", r.File)
+	}
+
+	family(&buf, "csvlinter_valid", "gauge", "1 if the file passed validation, 0 otherwise.")
+	for _, r := range results {
+		valid := 0.0
+		if r.Valid {
+			valid = 1
+		}
+		sample(&buf, "csvlinter_valid", valid, "file", r.File)
+	}
+
+	family(&buf, "csvlinter_last_run_timestamp_seconds", "gauge", "Unix time of the validation run.")
+	sample(&buf, "csvlinter_last_run_timestamp_seconds", float64(now.Unix()))
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// WriteFile writes metrics to path atomically (temp file + rename) so a node_exporter
+// textfile collector never reads a partially written file.
+func WriteFile(path string, now time.Time, results ...*validator.Results) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create metrics file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := Write(tmp, now, results...); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write metrics file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write metrics file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write metrics file: %w", err)
+	}
+	return nil
+}
+
+// Push replaces the csvlinter job's metrics on the Pushgateway at gatewayURL.
+func Push(ctx context.Context, gatewayURL string, now time.Time, results ...*validator.Results) error {
+	var body bytes.Buffer
+	if err := Write(&body, now, results...); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	defer cancel()
+	endpoint := strings.TrimSuffix(gatewayURL, "/") + "/metrics/job/" + url.PathEscape(Job)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint, &body)
+	if err != nil {
+		return fmt.Errorf("invalid pushgateway URL: %w", err)
+	}
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("pushgateway request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("pushgateway returned %s", resp.Status)
+	}
+	return nil
+}
+
+type ruleCount struct {
+	rule  string
+	count int
+}
+
+// countByRule counts the findings of each rule, with the occurrences of deduplicated
+// ones, sorted by rule for stable output.
+func countByRule(findings []validator.Finding) []ruleCount {
+	counts := make(map[string]int)
+	for _, f := range findings {
+		counts[ruleLabel(f.RuleID, f.Type)] += max(f.Occurrences, 1)
+	}
+	out := make([]ruleCount, 0, len(counts))
+	for rule, count := range counts {
+		out = append(out, ruleCount{rule, count})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].rule < out[j].rule })
+	return out
+}
+
+func family(buf *bytes.Buffer, name, kind, help string) {
+	fmt.Fprintf(buf, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// sample writes one metric line; labels are alternating name/value pairs.
+func sample(buf *bytes.Buffer, name string, value float64, labels ...string) {
+	buf.WriteString(name)
+	if len(labels) > 0 {
+		buf.WriteByte('{')
+		for i := 0; i < len(labels); i += 2 {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			fmt.Fprintf(buf, "%s=\"%s\"", labels[i], escapeLabel(labels[i+1]))
+		}
+		buf.WriteByte('}')
+	}
+	fmt.Fprintf(buf, " %g\n", value)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
+
+// ruleLabel is the rule ID, or the finding type for findings without one.
+func ruleLabel(rule, findingType string) string {
+	if rule != "" {
+		return rule
+	}
+	return findingType
+}
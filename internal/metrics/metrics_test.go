@@ -0,0 +1,113 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+var now = time.Unix(1700000000, 0)
+
+func sampleResults() *validator.Results {
+	return &validator.Results{
+		File:      `data/"odd".csv`,
+		TotalRows: 42,
+		Errors: []validator.Finding{
+			{Severity: validator.SeverityError, Location: validator.Location{Line: 2}, Message: "column count mismatch", Type: "structure"},
+			{Severity: validator.SeverityError, Location: validator.Location{Line: 3}, Field: "email", Message: "invalid", Type: "schema"},
+			{Severity: validator.SeverityError, Location: validator.Location{Line: 4}, Field: "email", Message: "invalid", Type: "schema"},
+		},
+		Duration: "1.5s",
+		Valid:    false,
+	}
+}
+
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, now, sampleResults()); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	out := buf.String()
+
+	expected := []string{
+		"# TYPE csvlinter_rows_total counter",
+		`csvlinter_rows_total{file="data/\"odd\".csv"} 42`,
+		`csvlinter_errors_total{file="data/\"odd\".csv",rule="schema"} 2`,
+		`csvlinter_errors_total{file="data/\"odd\".csv",rule="structure"} 1`,
+		`csvlinter_duration_seconds{file="data/\"odd\".csv"} 1.5`,
+		`csvlinter_valid{file="data/\"odd\".csv"} 0`,
+		"csvlinter_last_run_timestamp_seconds 1.7e+09",
+	}
+	for _, line := range expected {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("Expected output to contain %q\n%s", line, out)
+		}
+	}
+	if strings.Index(out, `rule="schema"`) > strings.Index(out, `rule="structure"`) {
+		t.Errorf("Expected rules sorted for stable output")
+	}
+}
+
+func TestWriteDeduped(t *testing.T) {
+	results := &validator.Results{
+		File: "ragged.csv",
+		Errors: []validator.Finding{
+			{Severity: validator.SeverityError, Location: validator.Location{Line: 2}, Message: "too few fields", Type: "structure", RuleID: "STR001", Occurrences: 3, LastLine: 4},
+		},
+		Duration: "1ms",
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, now, results); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if want := `csvlinter_errors_total{file="ragged.csv",rule="STR001"} 3`; !strings.Contains(buf.String(), want+"\n") {
+		t.Errorf("Expected deduplicated findings counted by occurrence, %q in\n%s", want, buf.String())
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "csvlinter.prom")
+	if err := WriteFile(path, now, sampleResults()); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read metrics file: %v", err)
+	}
+	if !strings.Contains(string(content), "csvlinter_rows_total") {
+		t.Errorf("Expected metrics in file, got %s", content)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("Expected temp file to be cleaned up, found %d entries", len(entries))
+	}
+}
+
+func TestPush(t *testing.T) {
+	var method, path, body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+	}))
+	defer srv.Close()
+
+	if err := Push(context.Background(), srv.URL+"/", now, sampleResults()); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+	if method != http.MethodPut || path != "/metrics/job/csvlinter" {
+		t.Errorf("Expected PUT /metrics/job/csvlinter, got %s %s", method, path)
+	}
+	if !strings.Contains(body, "csvlinter_errors_total") {
+		t.Errorf("Expected metrics body, got %q", body)
+	}
+}
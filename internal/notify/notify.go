@@ -0,0 +1,118 @@
+// Package notify posts validation summaries to chat and generic webhooks.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+// DefaultTopErrors is the number of most frequent errors included in a payload.
+const DefaultTopErrors = 5
+
+// Timeout bounds a single webhook request.
+const Timeout = 10 * time.Second
+
+// Payload is the JSON document posted to the webhook. Text carries a one-line summary
+// so Slack and Teams incoming webhooks render it directly; the remaining fields are
+// for generic receivers.
+type Payload struct {
+	Text         string     `json:"text"`
+	File         string     `json:"file"`
+	Status       string     `json:"status"`
+	TotalRows    int        `json:"total_rows"`
+	ErrorCount   int        `json:"error_count"`
+	WarningCount int        `json:"warning_count"`
+	Duration     string     `json:"duration"`
+	TopErrors    []TopError `json:"top_errors,omitempty"`
+}
+
+// TopError is an error message grouped by field with its number of occurrences.
+type TopError struct {
+	Field     string `json:"field,omitempty"`
+	Message   string `json:"message"`
+	Count     int    `json:"count"`
+	FirstLine int    `json:"first_line"`
+}
+
+// NewPayload summarizes the results of a run, keeping at most maxTop grouped errors.
+// A multi-file run is summarized as a whole.
+func NewPayload(maxTop int, results ...*validator.Results) Payload {
+	p := Payload{Status: "valid"}
+	var errs []validator.Finding
+	var duration time.Duration
+	for _, r := range results {
+		if !r.Valid {
+			p.Status = "invalid"
+		}
+		p.TotalRows += r.TotalRows
+		p.ErrorCount += r.ErrorCount()
+		p.WarningCount += r.WarningCount()
+		d, _ := time.ParseDuration(r.Duration)
+		duration += d
+		errs = append(errs, r.Errors...)
+	}
+	p.Duration = duration.String()
+	p.TopErrors = topErrors(errs, maxTop)
+	if len(results) == 1 {
+		p.File = results[0].File
+	} else {
+		p.File = fmt.Sprintf("%d files", len(results))
+	}
+	p.Text = fmt.Sprintf("csvlinter: %s is %s (%d rows, %d error(s), %d warning(s))",
+		p.File, p.Status, p.TotalRows, p.ErrorCount, p.WarningCount)
+	return p
+}
+
+// topErrors groups errors by (field, message) and returns the most frequent first.
+func topErrors(errs []validator.Finding, maxTop int) []TopError {
+	type key struct{ field, message string }
+	index := make(map[key]int)
+	var groups []TopError
+	for _, e := range errs {
+		k := key{e.Field, e.Message}
+		if i, ok := index[k]; ok {
+			groups[i].Count += max(e.Occurrences, 1)
+			continue
+		}
+		index[k] = len(groups)
+		groups = append(groups, TopError{Field: e.Field, Message: e.Message, Count: max(e.Occurrences, 1), FirstLine: e.Line})
+	}
+	sort.SliceStable(groups, func(i, j int) bool { return groups[i].Count > groups[j].Count })
+	if len(groups) > maxTop {
+		groups = groups[:maxTop]
+	}
+	return groups
+}
+
+// Send posts payload as JSON to url. Non-2xx responses are reported as errors.
+func Send(ctx context.Context, url string, payload Payload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
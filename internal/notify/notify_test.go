@@ -0,0 +1,108 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+func sampleResults() *validator.Results {
+	return &validator.Results{
+		File:      "orders.csv",
+		TotalRows: 10,
+		Errors: []validator.Finding{
+			{Severity: validator.SeverityError, Location: validator.Location{Line: 2}, Field: "email", Message: "invalid email", Type: "schema"},
+			{Severity: validator.SeverityError, Location: validator.Location{Line: 5}, Field: "id", Message: "expected integer", Type: "schema"},
+			{Severity: validator.SeverityError, Location: validator.Location{Line: 7}, Field: "email", Message: "invalid email", Type: "schema"},
+		},
+		Duration: "3ms",
+		Valid:    false,
+	}
+}
+
+func TestNewPayload(t *testing.T) {
+	p := NewPayload(1, sampleResults())
+
+	if p.Status != "invalid" || p.ErrorCount != 3 || p.TotalRows != 10 {
+		t.Errorf("Unexpected summary: %+v", p)
+	}
+	if len(p.TopErrors) != 1 {
+		t.Fatalf("Expected top errors capped at 1, got %d", len(p.TopErrors))
+	}
+	top := p.TopErrors[0]
+	if top.Field != "email" || top.Count != 2 || top.FirstLine != 2 {
+		t.Errorf("Expected email error grouped twice from line 2, got %+v", top)
+	}
+	if !strings.Contains(p.Text, "orders.csv is invalid") {
+		t.Errorf("Expected summary text, got %q", p.Text)
+	}
+}
+
+func TestNewPayloadDeduped(t *testing.T) {
+	results := sampleResults()
+	results.Errors[0].Occurrences = 2
+	results.Errors[0].LastLine = 7
+	results.Errors = results.Errors[:2]
+	results.Warnings = []validator.Finding{{Severity: validator.SeverityWarning, Location: validator.Location{Line: 1}, Message: "sep line", Type: "structure", Occurrences: 2}}
+
+	p := NewPayload(5, results)
+	if p.ErrorCount != 3 || p.WarningCount != 2 {
+		t.Errorf("Expected occurrences counted, got %+v", p)
+	}
+	if top := p.TopErrors[0]; top.Field != "email" || top.Count != 2 {
+		t.Errorf("Expected the deduplicated email error counted twice, got %+v", top)
+	}
+	if !strings.Contains(p.Text, "3 error(s), 2 warning(s)") {
+		t.Errorf("Expected occurrences in the summary text, got %q", p.Text)
+	}
+}
+
+func TestSend(t *testing.T) {
+	t.Run("posts JSON payload", func(t *testing.T) {
+		var got Payload
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				t.Errorf("Expected POST, got %s", r.Method)
+			}
+			if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+				t.Errorf("Expected JSON content type, got %q", ct)
+			}
+			if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+				t.Errorf("Failed to decode payload: %v", err)
+			}
+		}))
+		defer srv.Close()
+
+		if err := Send(context.Background(), srv.URL, NewPayload(DefaultTopErrors, sampleResults())); err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+		if got.File != "orders.csv" || got.ErrorCount != 3 {
+			t.Errorf("Unexpected payload received: %+v", got)
+		}
+	})
+
+	t.Run("non-2xx response is an error", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		}))
+		defer srv.Close()
+
+		err := Send(context.Background(), srv.URL, NewPayload(DefaultTopErrors, sampleResults()))
+		if err == nil || !strings.Contains(err.Error(), "403") {
+			t.Errorf("Expected 403 error, got %v", err)
+		}
+	})
+}
+
+func TestNewPayloadMultipleFiles(t *testing.T) {
+	valid := &validator.Results{File: "ok.csv", TotalRows: 5, Duration: "2ms", Valid: true}
+	p := NewPayload(DefaultTopErrors, sampleResults(), valid)
+	if p.File != "2 files" || p.Status != "invalid" || p.TotalRows != 15 || p.Duration != "5ms" {
+		t.Errorf("Unexpected aggregate payload: %+v", p)
+	}
+}
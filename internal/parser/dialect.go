@@ -0,0 +1,127 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NoQuote is the Dialect.Quote of input whose fields are never quoted, so quote
+// characters are read as any other.
+const NoQuote rune = -1
+
+// Dialect is how the fields of records are delimited, quoted and escaped. The zero
+// Dialect, apart from the delimiter, is RFC 4180 CSV as encoding/csv reads it.
+type Dialect struct {
+	Delimiter string // Field delimiter, or Whitespace
+	Quote     rune   // Quote character; '"' when 0, none when NoQuote
+	Escape    rune   // Character escaping delimiters, quotes, line breaks and itself in fields; none when 0
+}
+
+// NewDialect returns the dialect of delimiter, quote ("" for '"', or "none" for input
+// that never quotes) and escape ("" for none), as users give them. Quote and escape
+// characters are single ASCII characters other than the delimiter and line breaks.
+func NewDialect(delimiter, quote, escape string) (Dialect, error) {
+	if delimiter == "" {
+		return Dialect{}, fmt.Errorf("delimiter cannot be empty")
+	}
+	d := Dialect{Delimiter: delimiter}
+	check := func(name, ch string) (rune, error) {
+		if len(ch) != 1 || strings.ContainsAny(ch, "\r\n") || rune(ch[0]) == DelimiterRune(delimiter) {
+			return 0, fmt.Errorf("invalid %s %q: must be one ASCII character other than the delimiter or a line break", name, ch)
+		}
+		return rune(ch[0]), nil
+	}
+	var err error
+	switch quote {
+	case "":
+	case "none":
+		d.Quote = NoQuote
+	default:
+		if d.Quote, err = check("quote", quote); err != nil {
+			return Dialect{}, err
+		}
+	}
+	if escape != "" {
+		if d.Escape, err = check("escape", escape); err != nil {
+			return Dialect{}, err
+		}
+		if d.Escape == d.quote() {
+			return Dialect{}, fmt.Errorf("invalid escape %q: must differ from the quote", escape)
+		}
+	}
+	return d, nil
+}
+
+// quote returns the quote character, or NoQuote.
+func (d Dialect) quote() rune {
+	if d.Quote == 0 {
+		return '"'
+	}
+	return d.Quote
+}
+
+// standard reports whether encoding/csv reads the dialect.
+func (d Dialect) standard() bool {
+	return d.quote() == '"' && d.Escape == 0
+}
+
+// NeedsQuotes reports whether field must be quoted, or escaped, to be read back as it is:
+// it contains the delimiter, the quote or escape character or a line break, or, as
+// encoding/csv reads it, starts with a space. Whitespace-delimited fields also need
+// quotes when empty or holding a tab.
+func (d Dialect) NeedsQuotes(field string) bool {
+	switch {
+	case d.Delimiter == Whitespace && (field == "" || strings.ContainsRune(field, '\t')):
+		return true
+	case d.standard():
+		return NeedsQuotes(field, DelimiterRune(d.Delimiter))
+	}
+	return strings.ContainsRune(field, DelimiterRune(d.Delimiter)) || strings.ContainsAny(field, "\r\n") ||
+		d.Quote != NoQuote && strings.ContainsRune(field, d.quote()) || d.Escape != 0 && strings.ContainsRune(field, d.Escape)
+}
+
+// QuoteField returns field as written in a quoted field, with the quote and escape
+// characters in it doubled or escaped. It returns false when fields cannot be quoted.
+func (d Dialect) QuoteField(field string) (string, bool) {
+	q := d.quote()
+	if q == NoQuote {
+		return "", false
+	}
+	if d.Escape != 0 {
+		field = strings.ReplaceAll(field, string(d.Escape), string(d.Escape)+string(d.Escape))
+	}
+	return string(q) + strings.ReplaceAll(field, string(q), string(q)+string(q)) + string(q), true
+}
+
+// EscapeField returns field as written in an unquoted field, with the escape character
+// escaping the characters that would otherwise need quotes. It returns false when field
+// needs quotes and there is no escape character.
+func (d Dialect) EscapeField(field string) (string, bool) {
+	if !d.NeedsQuotes(field) {
+		return field, true
+	}
+	if d.Escape == 0 || field == "" {
+		return "", false
+	}
+	var sb strings.Builder
+	for _, r := range field {
+		if r == d.Escape || r == DelimiterRune(d.Delimiter) || r == '\r' || r == '\n' || d.Quote != NoQuote && r == d.quote() ||
+			d.Delimiter == Whitespace && r == '\t' {
+			sb.WriteRune(d.Escape)
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String(), true
+}
+
+// FormatField returns field as written in the dialect: quoted when quote is set or it
+// needs quotes, and escaped instead where fields cannot be quoted. It returns false when
+// field can be written neither way.
+func (d Dialect) FormatField(field string, quote bool) (string, bool) {
+	if quote || d.NeedsQuotes(field) {
+		if text, ok := d.QuoteField(field); ok {
+			return text, true
+		}
+	}
+	return d.EscapeField(field)
+}
@@ -0,0 +1,56 @@
+package parser
+
+import "testing"
+
+func TestNewDialect(t *testing.T) {
+	tests := []struct {
+		delimiter, quote, escape string
+		want                     Dialect
+		wantErr                  bool
+	}{
+		{",", "", "", Dialect{Delimiter: ","}, false},
+		{";", "'", `\`, Dialect{Delimiter: ";", Quote: '\'', Escape: '\\'}, false},
+		{"\t", "none", "", Dialect{Delimiter: "\t", Quote: NoQuote}, false},
+		{"", "", "", Dialect{}, true},
+		{",", ",", "", Dialect{}, true},
+		{",", "''", "", Dialect{}, true},
+		{",", "", "\n", Dialect{}, true},
+		{",", "'", "'", Dialect{}, true},
+		{Whitespace, "", " ", Dialect{}, true},
+	}
+	for _, tt := range tests {
+		got, err := NewDialect(tt.delimiter, tt.quote, tt.escape)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("NewDialect(%q, %q, %q) = %+v, %v", tt.delimiter, tt.quote, tt.escape, got, err)
+		}
+	}
+}
+
+func TestDialectFields(t *testing.T) {
+	tests := []struct {
+		dialect     Dialect
+		field       string
+		quoted      string // "" when it cannot be quoted
+		escaped     string // "" when it cannot be escaped
+		needsQuotes bool
+	}{
+		{Dialect{Delimiter: ","}, `a "b"`, `"a ""b"""`, "", true},
+		{Dialect{Delimiter: ","}, "plain", `"plain"`, "plain", false},
+		{Dialect{Delimiter: ",", Quote: '\''}, "it's", `'it''s'`, "", true},
+		{Dialect{Delimiter: ",", Quote: '\''}, `say "hi"`, `'say "hi"'`, `say "hi"`, false},
+		{Dialect{Delimiter: ",", Quote: NoQuote, Escape: '\\'}, `a,b\c`, "", `a\,b\\c`, true},
+		{Dialect{Delimiter: ",", Escape: '\\'}, `C:\tmp`, `"C:\\tmp"`, `C:\\tmp`, true},
+		{Dialect{Delimiter: Whitespace}, "", `""`, "", true},
+	}
+	for _, tt := range tests {
+		if got := tt.dialect.NeedsQuotes(tt.field); got != tt.needsQuotes {
+			t.Errorf("%+v NeedsQuotes(%q) = %v", tt.dialect, tt.field, got)
+		}
+		if got, ok := tt.dialect.QuoteField(tt.field); got != tt.quoted || ok != (tt.quoted != "") {
+			t.Errorf("%+v QuoteField(%q) = %q, %v", tt.dialect, tt.field, got, ok)
+		}
+		if got, ok := tt.dialect.EscapeField(tt.field); got != tt.escaped || ok != (tt.escaped != "") {
+			t.Errorf("%+v EscapeField(%q) = %q, %v", tt.dialect, tt.field, got, ok)
+		}
+	}
+}
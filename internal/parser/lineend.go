@@ -0,0 +1,74 @@
+package parser
+
+import (
+	"bytes"
+	"io"
+)
+
+// maxLineEndingProbe bounds how much of the input is read looking for its first line ending.
+const maxLineEndingProbe = 64 << 10
+
+// detectCROnly reads r up to its first line ending and reports whether that is a carriage
+// return alone, as in files from classic Mac OS, which encoding/csv would read as one
+// giant record. The returned reader replays the bytes read and the rest of r, with the
+// carriage returns of a CR-only input turned into line feeds, so offsets do not move.
+func detectCROnly(r io.Reader) (io.Reader, bool) {
+	r, crOnly := probeCROnly(r)
+	if crOnly {
+		return crReader{r}, true
+	}
+	return r, false
+}
+
+// probeCROnly is detectCROnly without turning carriage returns into line feeds.
+func probeCROnly(r io.Reader) (io.Reader, bool) {
+	var head []byte
+	chunk := make([]byte, 4096)
+	crOnly := false
+	var err error
+	for len(head) < maxLineEndingProbe {
+		var n int
+		n, err = r.Read(chunk)
+		head = append(head, chunk[:n]...)
+		if i := bytes.IndexAny(head, "\r\n"); i >= 0 && (head[i] == '\n' || i+1 < len(head)) {
+			crOnly = head[i] == '\r' && head[i+1] != '\n'
+			break
+		} else if i >= 0 && err != nil {
+			crOnly = err == io.EOF // A carriage return ends the input
+			break
+		}
+		if err != nil {
+			break
+		}
+	}
+	rest := r
+	if err != nil {
+		rest = failedReader{err}
+	}
+	return io.MultiReader(bytes.NewReader(head), rest), crOnly
+}
+
+// failedReader returns err from every read, after an error ended reading ahead.
+type failedReader struct{ err error }
+
+func (f failedReader) Read([]byte) (int, error) { return 0, f.err }
+
+// crReader turns every carriage return read from r into a line feed.
+type crReader struct{ r io.Reader }
+
+func (c crReader) Read(b []byte) (int, error) {
+	n, err := c.r.Read(b)
+	for i, ch := range b[:n] {
+		if ch == '\r' {
+			b[i] = '\n'
+		}
+	}
+	return n, err
+}
+
+// newSampleReader returns a reader of the records of r in dialect d, which reads the line
+// endings of a CR-only input as line breaks.
+func newSampleReader(r io.Reader, d Dialect) records {
+	r, _ = detectCROnly(r)
+	return newReader(r, d)
+}
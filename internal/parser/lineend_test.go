@@ -0,0 +1,75 @@
+package parser
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestDetectCROnly(t *testing.T) {
+	long := strings.Repeat("x", maxLineEndingProbe)
+	for _, tc := range []struct {
+		input string
+		want  bool
+	}{
+		{"a,b\r1,2\r", true},
+		{"a,b\r", true},
+		{"a,b\r\n1,2\r\n", false},
+		{"a,b\n1,2\r3,4\n", false},
+		{"a,b", false},
+		{"", false},
+		{long + "\r1\r", false}, // No line ending within the probe
+	} {
+		// One byte at a time, a CRLF is split across reads
+		var in io.Reader = strings.NewReader(tc.input)
+		if len(tc.input) < 100 {
+			in = iotest.OneByteReader(in)
+		}
+		r, crOnly := detectCROnly(in)
+		if crOnly != tc.want {
+			t.Errorf("%.20q: got %v, want %v", tc.input, crOnly, tc.want)
+		}
+		data, err := io.ReadAll(r)
+		want := tc.input
+		if tc.want {
+			want = strings.ReplaceAll(want, "\r", "\n")
+		}
+		if err != nil || string(data) != want {
+			t.Errorf("%.20q: replayed %.20q (%v)", tc.input, data, err)
+		}
+	}
+
+	// A read error after the bytes read is kept
+	_, crOnly := detectCROnly(iotest.TimeoutReader(strings.NewReader("a,b")))
+	if crOnly {
+		t.Error("want no CR-only input from a failing reader")
+	}
+}
+
+func TestParserCROnly(t *testing.T) {
+	p, err := NewParser(strings.NewReader("id,note\r1,\"two\rlines\"\r2,b\r"), ",")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.CROnly() {
+		t.Fatal("want the input detected as CR-only")
+	}
+	if _, err := p.ReadHeaders(); err != nil {
+		t.Fatal(err)
+	}
+	var rows []*Row
+	for {
+		row, err := p.ReadRow()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) != 2 || rows[0].Data[1] != "two\nlines" || rows[1].LineNumber != 3 || rows[1].Offset != 22 {
+		t.Errorf("want two rows with byte offsets kept, got %+v %+v", *rows[0], *rows[len(rows)-1])
+	}
+}
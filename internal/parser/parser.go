@@ -2,42 +2,72 @@ package parser
 
 import (
 	"bytes"
-	"encoding/csv"
 	"errors"
 	"fmt"
 	"io"
 	"math"
+	"path/filepath"
+	"strings"
+	"time"
 	"unicode/utf8"
 )
 
 // ErrInvalidUTF8 is returned when a row or header contains invalid UTF-8.
 var ErrInvalidUTF8 = errors.New("invalid UTF-8 encoding")
 
-// EncodingError wraps ErrInvalidUTF8 with line context for reporting.
+// ErrEmptyInput is returned when the input has no header row.
+var ErrEmptyInput = errors.New("empty input: no headers found")
+
+// EncodingError wraps ErrInvalidUTF8 with the location of the first invalid bytes of a
+// record, for reporting.
 type EncodingError struct {
-	LineNumber int
+	LineNumber int    // Record line, as numbered in findings
+	Column     int    // 1-based field holding the invalid bytes; 0 when unknown
+	Offset     int64  // Input offset of the first invalid byte, when Bytes is set
+	Line       int    // 1-based physical line of the first invalid byte, when Bytes is set
+	Bytes      []byte // Up to 8 invalid bytes from Offset; nil when the location is unknown
 	Err        error
 }
 
 func (e *EncodingError) Error() string {
-	return fmt.Sprintf("line %d: %v", e.LineNumber, e.Err)
+	return fmt.Sprintf("line %d: %s", e.LineNumber, e.Describe())
+}
+
+// Describe returns the error without the line number, with the offset and hex bytes of
+// the invalid sequence when known, e.g. "invalid UTF-8 encoding at byte 1042: ff fe".
+func (e *EncodingError) Describe() string {
+	if len(e.Bytes) == 0 {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%v at byte %d: % x", e.Err, e.Offset, e.Bytes)
 }
 
 func (e *EncodingError) Unwrap() error { return e.Err }
 
 // Parser represents a streaming CSV parser that reads from the input without buffering the entire file.
 type Parser struct {
-	reader     *csv.Reader
+	input      io.Reader
+	reader     records
 	lineNumber int
 	headers    []string
-	delimiter  rune
+	dialect    Dialect
+	raw        *recorder // Set by KeepRaw
+	quotes     bool      // Set by TrackQuotes
+	last       []byte    // Raw bytes consumed by the last read, when kept
+	utf8       *utf8Scanner
+	crOnly     bool  // Lines end with a carriage return alone
+	preamble   int   // Lines before the input, set by CountPreamble
+	base       int64 // Offset in the file of the input's first byte, set by CountPreamble
 }
 
 // Row represents a single CSV row with metadata
 type Row struct {
 	LineNumber int
+	Offset     int64 // Offset in the file where the record starts, blank lines skipped before it included
 	Data       []string
 	Headers    []string
+	Quoted     []bool // Whether each field was quoted in the input; nil unless the parser tracks quotes
+	Raw        []byte // The record byte for byte as it is in the input, from Offset, line ending included; nil unless the parser keeps raw records
 }
 
 // IsEmpty checks if all fields in the row are empty
@@ -56,6 +86,25 @@ func (r *Row) IsEmpty() bool {
 	return true
 }
 
+// RepeatsHeader reports whether fields repeat the names of headers position by position,
+// ignoring case, surrounding whitespace and a byte order mark, as the header of a second
+// file does where exported files were concatenated. A header without names is never
+// repeated.
+func RepeatsHeader(fields, headers []string) bool {
+	if len(fields) != len(headers) {
+		return false
+	}
+	named := false
+	for i, h := range headers {
+		h = strings.TrimSpace(strings.TrimPrefix(h, "\ufeff"))
+		if !strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(fields[i], "\ufeff")), h) {
+			return false
+		}
+		named = named || h != ""
+	}
+	return named
+}
+
 func validUTF8Strings(ss []string) bool {
 	for _, s := range ss {
 		if !utf8.ValidString(s) {
@@ -65,19 +114,240 @@ func validUTF8Strings(ss []string) bool {
 	return true
 }
 
+// extensionDelimiters maps file extensions to their conventional delimiter.
+var extensionDelimiters = map[string]string{
+	".tsv": "\t",
+	".tab": "\t",
+	".psv": "|",
+}
+
+// DelimiterFor returns the conventional delimiter for path's extension: a tab for .tsv and
+// .tab, a pipe for .psv, and a comma otherwise.
+func DelimiterFor(path string) string {
+	if d, ok := extensionDelimiters[strings.ToLower(filepath.Ext(path))]; ok {
+		return d
+	}
+	return ","
+}
+
 // NewParser creates a new streaming CSV parser that reads directly from input without loading the entire file into memory.
 func NewParser(input io.Reader, delimiter string) (*Parser, error) {
-	if delimiter == "" {
+	return NewParserWithDialect(input, Dialect{Delimiter: delimiter})
+}
+
+// NewParserWithDialect creates a streaming parser of input in dialect d, for files with
+// another quote character, no quoting or escaped delimiters.
+func NewParserWithDialect(input io.Reader, d Dialect) (*Parser, error) {
+	if d.Delimiter == "" {
 		return nil, fmt.Errorf("delimiter cannot be empty")
 	}
-	reader := csv.NewReader(input)
-	reader.Comma = rune(delimiter[0])
-	reader.FieldsPerRecord = -1
+	// Carriage returns are turned into line feeds above the scanner, and any recorder, so
+	// raw records keep them
+	input, crOnly := probeCROnly(input)
+	scanner := newUTF8Scanner(input)
+	if crOnly {
+		scanner.newline = '\r'
+	}
+	p := &Parser{
+		input:   scanner,
+		dialect: d,
+		utf8:    scanner,
+		crOnly:  crOnly,
+	}
+	p.reader = p.newRecords(scanner)
+	return p, nil
+}
 
-	return &Parser{
-		reader:    reader,
-		delimiter: rune(delimiter[0]),
-	}, nil
+// newRecords returns the reader of the records of r, the input or a recorder of it.
+func (p *Parser) newRecords(r io.Reader) records {
+	if p.crOnly {
+		r = crReader{r}
+	}
+	return newReader(r, p.dialect)
+}
+
+// CROnly reports whether the lines of the input end with a carriage return alone, as in
+// files from classic Mac OS. The parser reads them as line breaks, and carriage returns
+// in quoted values as line feeds.
+func (p *Parser) CROnly() bool {
+	return p.crOnly
+}
+
+// KeepRaw makes the parser keep the bytes of each record as they are in the input, for
+// reproducing them exactly: in Row.Raw, and from Raw for the header and for records that
+// fail to parse. It must be called before anything is read.
+func (p *Parser) KeepRaw() {
+	if p.raw == nil {
+		p.raw = &recorder{r: p.input}
+		p.reader = p.newRecords(p.raw)
+	}
+}
+
+// TrackQuotes makes ReadRow report which fields were quoted, telling a quoted empty field
+// ("") from one with nothing between the delimiters. It keeps the raw bytes of records, as
+// KeepRaw does, and must be called before anything is read.
+func (p *Parser) TrackQuotes() {
+	p.KeepRaw()
+	p.quotes = true
+}
+
+// Raw returns the bytes of the input consumed by the last read, exactly: the header, the
+// record of the row just read, blank lines skipped before it included, or the input read
+// up to where a record failed to parse or held invalid UTF-8. At the end of the input, it
+// holds the blank lines after the last record, if any. It is nil before anything is read
+// and unless the parser keeps raw records.
+func (p *Parser) Raw() []byte {
+	return p.last
+}
+
+// consume forgets the raw bytes before the reader's offset, keeping them as the last
+// read's, when raw records are kept.
+func (p *Parser) consume() []byte {
+	p.last = nil
+	if p.raw != nil {
+		p.last = p.raw.record(p.reader.InputOffset())
+	}
+	return p.last
+}
+
+// TimeUTF8 makes the parser measure the time spent checking that the input is UTF-8, as
+// reported by UTF8Time.
+func (p *Parser) TimeUTF8() {
+	p.utf8.timed = true
+}
+
+// UTF8Time returns the time spent checking UTF-8 since TimeUTF8 was called.
+func (p *Parser) UTF8Time() time.Duration {
+	return p.utf8.elapsed
+}
+
+// encodingError returns the error for the record just read when it holds invalid UTF-8,
+// located by the scanner, or nil.
+func (p *Parser) encodingError(record []string, line int) *EncodingError {
+	f, ok := p.utf8.take(p.reader.InputOffset())
+	if !ok {
+		return nil
+	}
+	e := &EncodingError{LineNumber: line, Offset: p.base + f.offset, Line: p.preamble + f.line, Bytes: f.bytes, Err: ErrInvalidUTF8}
+	for i, field := range record {
+		if !utf8.ValidString(field) {
+			e.Column = i + 1
+			break
+		}
+	}
+	return e
+}
+
+// CountPreamble locates what is read after the lines and size bytes of the input
+// consumed before the parser, such as a sep= line: lines are numbered, and offsets
+// counted, from the start of the file. It must be called before anything is read.
+func (p *Parser) CountPreamble(lines int, size int64) {
+	p.lineNumber += lines
+	p.preamble = lines
+	p.base = size
+}
+
+// recorder keeps the bytes read from r that the CSV reader has not yet consumed as records.
+type recorder struct {
+	r      io.Reader
+	buf    []byte
+	offset int64 // Input offset of buf[0]
+}
+
+func (r *recorder) Read(b []byte) (int, error) {
+	n, err := r.r.Read(b)
+	r.buf = append(r.buf, b[:n]...)
+	return n, err
+}
+
+// record returns the raw bytes of the input up to end and forgets them.
+func (r *recorder) record(end int64) []byte {
+	n := int(end - r.offset)
+	raw := append([]byte(nil), r.buf[:n]...)
+	r.buf = append(r.buf[:0], r.buf[n:]...)
+	r.offset = end
+	return raw
+}
+
+// FieldSpans returns where each field of a raw record is, quotes included, as start and
+// end offsets in raw; the line ending is not part of the last field. raw is a record the
+// CSV reader accepted, possibly preceded by the blank lines it skipped. Of whitespace-
+// delimited input, the whitespace around fields is not part of them, and escaped
+// characters are skipped over.
+func FieldSpans(raw []byte, d Dialect) [][2]int {
+	i := 0
+	for i < len(raw) && (raw[i] == '\n' || raw[i] == '\r') {
+		i++
+	}
+	whitespace := d.Delimiter == Whitespace
+	sep := byte(DelimiterRune(d.Delimiter))
+	quote, escape := int(d.quote()), -1
+	if d.Escape != 0 {
+		escape = int(d.Escape)
+	}
+	var spans [][2]int
+	for {
+		for whitespace && i < len(raw) && isBlank(raw[i]) {
+			i++
+		}
+		start := i
+		if i < len(raw) && int(raw[i]) == quote {
+			// Skip to the closing quote; doubled quotes are escaped quotes
+			for i++; i < len(raw); i++ {
+				if int(raw[i]) == escape {
+					i++
+					continue
+				}
+				if int(raw[i]) == quote {
+					if i+1 < len(raw) && int(raw[i+1]) == quote {
+						i++
+						continue
+					}
+					i++
+					break
+				}
+			}
+		}
+		for i < len(raw) && raw[i] != sep && raw[i] != '\n' && !(whitespace && isBlank(raw[i])) {
+			if int(raw[i]) == escape && i+1 < len(raw) {
+				i++
+			}
+			i++
+		}
+		end := i
+		if end > start && raw[end-1] == '\r' && (end == len(raw) || raw[end] == '\n') {
+			end--
+		}
+		if whitespace {
+			for i < len(raw) && isBlank(raw[i]) {
+				i++
+			}
+			if len(spans) > 0 && start == end && (i >= len(raw) || raw[i] == '\n' || raw[i] == '\r') {
+				// Trailing whitespace, not an empty field
+				return spans
+			}
+			if i < len(raw) && raw[i] == '\r' && (i+1 == len(raw) || raw[i+1] == '\n') {
+				i++
+			}
+		}
+		spans = append(spans, [2]int{start, end})
+		if i >= len(raw) || raw[i] == '\n' {
+			return spans
+		}
+		if !whitespace {
+			i++
+		}
+	}
+}
+
+// quotedFields reports which fields of a raw record start with a quote.
+func quotedFields(raw []byte, d Dialect) []bool {
+	spans := FieldSpans(raw, d)
+	quoted := make([]bool, len(spans))
+	for i, span := range spans {
+		quoted[i] = span[0] < len(raw) && rune(raw[span[0]]) == d.quote()
+	}
+	return quoted
 }
 
 // Close is a no-op since we don't own the reader
@@ -88,14 +358,15 @@ func (p *Parser) Close() error {
 // ReadHeaders reads and returns the header row, validating UTF-8.
 func (p *Parser) ReadHeaders() ([]string, error) {
 	headers, err := p.reader.Read()
+	p.consume()
 	if err != nil {
 		if err == io.EOF {
-			return nil, fmt.Errorf("empty input: no headers found")
+			return nil, ErrEmptyInput
 		}
 		return nil, fmt.Errorf("failed to read headers: %w", err)
 	}
-	if !validUTF8Strings(headers) {
-		return nil, &EncodingError{LineNumber: 1, Err: ErrInvalidUTF8}
+	if e := p.encodingError(headers, 1); e != nil {
+		return nil, e
 	}
 	p.lineNumber++
 	p.headers = headers
@@ -104,24 +375,70 @@ func (p *Parser) ReadHeaders() ([]string, error) {
 
 // ReadRow reads the next row from the CSV file and validates UTF-8 per record.
 func (p *Parser) ReadRow() (*Row, error) {
+	offset := p.reader.InputOffset()
 	record, err := p.reader.Read()
+	raw := p.consume()
 	if err == io.EOF {
 		return nil, io.EOF
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to read row %d: %w", p.lineNumber+1, err)
 	}
-	if !validUTF8Strings(record) {
-		return nil, &EncodingError{LineNumber: p.lineNumber + 1, Err: ErrInvalidUTF8}
+	var quoted []bool
+	if p.quotes {
+		quoted = quotedFields(raw, p.dialect)
 	}
+	// The record is consumed either way, so reading can go on after invalid UTF-8
 	p.lineNumber++
+	if e := p.encodingError(record, p.lineNumber); e != nil {
+		return nil, e
+	}
 	return &Row{
 		LineNumber: p.lineNumber,
+		Offset:     p.base + offset,
 		Data:       record,
 		Headers:    p.headers,
+		Quoted:     quoted,
+		Raw:        raw,
 	}, nil
 }
 
+// Ending tells how the input ends at the record just read.
+type Ending int
+
+const (
+	NotAtEnd     Ending = iota // The record just read is not the last of the input
+	EndsLine                   // The input ends with a line ending
+	EndsMidLine                // The input ends without a line ending
+	EndsInQuotes               // The input ends inside a quoted field
+)
+
+// Ending tells whether the record just read, or the error just returned, reached the end
+// of the input, and how the input ends there. A file cut off mid-row ends without a line
+// ending, or inside a quoted field when its quotes do not pair up. A record ending with a
+// line ending is only known to be the last once reading on returns io.EOF.
+func (p *Parser) Ending() Ending {
+	s := p.utf8
+	switch {
+	case !s.eof || p.reader.InputOffset() < s.offset:
+		return NotAtEnd
+	case p.endsInQuotes():
+		return EndsInQuotes
+	case s.last != '\n' && s.last != '\r':
+		return EndsMidLine
+	}
+	return EndsLine
+}
+
+// endsInQuotes reports whether the input read ends inside a quoted field. Of the
+// dialects encoding/csv reads, whose quotes pair up, its quotes are counted.
+func (p *Parser) endsInQuotes() bool {
+	if t, ok := p.reader.(*tokenizer); ok {
+		return t.quoted
+	}
+	return p.utf8.openQuote
+}
+
 // GetLineNumber returns the current line number
 func (p *Parser) GetLineNumber() int {
 	return p.lineNumber
@@ -168,30 +485,33 @@ func computeSampleK(totalRows, maxRows int) int {
 // produce different-sized samples. Callers that require a consistent sample
 // size should use ReadSampleFromBytes instead.
 func ReadSampleFromReader(r io.Reader, delimiter string, maxRows int) (headers []string, sample [][]string, replay io.Reader, err error) {
-	if delimiter == "" {
+	return ReadSampleWithDialect(r, Dialect{Delimiter: delimiter}, maxRows)
+}
+
+// ReadSampleWithDialect is ReadSampleFromReader for input in dialect d.
+func ReadSampleWithDialect(r io.Reader, d Dialect, maxRows int) (headers []string, sample [][]string, replay io.Reader, err error) {
+	if d.Delimiter == "" {
 		return nil, nil, nil, fmt.Errorf("delimiter cannot be empty")
 	}
 
 	if rs, ok := r.(io.ReadSeeker); ok {
-		return readSampleSeekable(rs, delimiter, maxRows)
+		return readSampleSeekable(rs, d, maxRows)
 	}
-	return readSampleStream(r, delimiter, maxRows)
+	return readSampleStream(r, d, maxRows)
 }
 
 // readSampleSeekable handles the seekable (file) case for ReadSampleFromReader.
-func readSampleSeekable(rs io.ReadSeeker, delimiter string, maxRows int) (headers []string, sample [][]string, replay io.Reader, err error) {
+func readSampleSeekable(rs io.ReadSeeker, d Dialect, maxRows int) (headers []string, sample [][]string, replay io.Reader, err error) {
 	startPos, err := rs.Seek(0, io.SeekCurrent)
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("seek: %w", err)
 	}
 
 	// Pass 1: count non-empty data rows.
-	rdCount := csv.NewReader(rs)
-	rdCount.Comma = rune(delimiter[0])
-	rdCount.FieldsPerRecord = -1
+	rdCount := newSampleReader(rs, d)
 	if _, err = rdCount.Read(); err != nil { // skip header
 		if err == io.EOF {
-			return nil, nil, nil, fmt.Errorf("empty input: no headers found")
+			return nil, nil, nil, ErrEmptyInput
 		}
 		return nil, nil, nil, fmt.Errorf("failed to read headers: %w", err)
 	}
@@ -217,9 +537,7 @@ func readSampleSeekable(rs io.ReadSeeker, delimiter string, maxRows int) (header
 
 	// Pass 2: read exactly k rows.
 	k := computeSampleK(totalRows, maxRows)
-	rdSample := csv.NewReader(rs)
-	rdSample.Comma = rune(delimiter[0])
-	rdSample.FieldsPerRecord = -1
+	rdSample := newSampleReader(rs, d)
 	headers, err = rdSample.Read()
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("failed to read headers: %w", err)
@@ -255,16 +573,14 @@ func readSampleSeekable(rs io.ReadSeeker, delimiter string, maxRows int) (header
 
 // readSampleStream handles non-seekable readers (STDIN, pipes) for
 // ReadSampleFromReader.
-func readSampleStream(r io.Reader, delimiter string, maxRows int) (headers []string, sample [][]string, replay io.Reader, err error) {
+func readSampleStream(r io.Reader, d Dialect, maxRows int) (headers []string, sample [][]string, replay io.Reader, err error) {
 	var captureBuf bytes.Buffer
 	tee := io.TeeReader(r, &captureBuf)
-	rd := csv.NewReader(tee)
-	rd.Comma = rune(delimiter[0])
-	rd.FieldsPerRecord = -1
+	rd := newSampleReader(tee, d)
 	headers, err = rd.Read()
 	if err != nil {
 		if err == io.EOF {
-			return nil, nil, nil, fmt.Errorf("empty input: no headers found")
+			return nil, nil, nil, ErrEmptyInput
 		}
 		return nil, nil, nil, fmt.Errorf("failed to read headers: %w", err)
 	}
@@ -306,13 +622,11 @@ func ReadSampleFromBytes(b []byte, delimiter string, maxRows int) (headers []str
 	if delimiter == "" {
 		return nil, nil, fmt.Errorf("delimiter cannot be empty")
 	}
-	rd := csv.NewReader(bytes.NewReader(b))
-	rd.Comma = rune(delimiter[0])
-	rd.FieldsPerRecord = -1
+	rd := newSampleReader(bytes.NewReader(b), Dialect{Delimiter: delimiter})
 	headers, err = rd.Read()
 	if err != nil {
 		if err == io.EOF {
-			return nil, nil, fmt.Errorf("empty input: no headers found")
+			return nil, nil, ErrEmptyInput
 		}
 		return nil, nil, fmt.Errorf("failed to read headers: %w", err)
 	}
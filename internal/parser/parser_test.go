@@ -2,10 +2,14 @@ package parser
 
 import (
 	"bytes"
+	"encoding/csv"
+	"errors"
 	"fmt"
 	"io"
+	"slices"
 	"strings"
 	"testing"
+	"testing/iotest"
 )
 
 func TestParser(t *testing.T) {
@@ -173,6 +177,124 @@ func TestParserWithEmptyRows(t *testing.T) {
 	}
 }
 
+func TestParserTrackQuotes(t *testing.T) {
+	// A large input makes records straddle the CSV reader's buffer
+	var b strings.Builder
+	b.WriteString("id,note,qty\r\n")
+	want := [][]bool{}
+	for i := 0; i < 2000; i++ {
+		switch i % 4 {
+		case 0:
+			b.WriteString("1,\"\",\n")
+			want = append(want, []bool{false, true, false})
+		case 1:
+			b.WriteString("\n\"2\",\"multi\nline, \"\"quoted\"\"\",3\r\n")
+			want = append(want, []bool{true, true, false})
+		case 2:
+			b.WriteString(",,\"\"\n")
+			want = append(want, []bool{false, false, true})
+		default:
+			b.WriteString("4,a b,\"5\"\n")
+			want = append(want, []bool{false, false, true})
+		}
+	}
+	p, err := NewParser(strings.NewReader(b.String()), ",")
+	if err != nil {
+		t.Fatalf("NewParser: %v", err)
+	}
+	p.TrackQuotes()
+	if _, err := p.ReadHeaders(); err != nil {
+		t.Fatalf("ReadHeaders: %v", err)
+	}
+	for i, w := range want {
+		row, err := p.ReadRow()
+		if err != nil {
+			t.Fatalf("row %d: %v", i+1, err)
+		}
+		if fmt.Sprint(row.Quoted) != fmt.Sprint(w) {
+			t.Fatalf("row %d %q: expected quoted %v, got %v", i+1, row.Data, w, row.Quoted)
+		}
+	}
+	if _, err := p.ReadRow(); err != io.EOF {
+		t.Errorf("expected EOF, got %v", err)
+	}
+
+	// Without tracking, rows carry no quote information
+	p, _ = NewParser(strings.NewReader("a\n\"\"\n"), ",")
+	p.ReadHeaders()
+	if row, _ := p.ReadRow(); row.Quoted != nil {
+		t.Errorf("expected no quote information, got %v", row.Quoted)
+	}
+}
+
+func TestParserKeepRaw(t *testing.T) {
+	for _, input := range []string{
+		"id,name\r\n1,\"a\r\nb\"\r\n\r\n2,c\r\n\n",
+		"id,name\r1,\"a\rb\"\r2,c", // CR-only line endings are kept as they are
+		"id,name\n1,\xff\n2,\"open\n3,d\n",
+	} {
+		p, err := NewParser(strings.NewReader(input), ",")
+		if err != nil {
+			t.Fatal(err)
+		}
+		p.KeepRaw()
+		if p.Raw() != nil {
+			t.Errorf("%q: want no raw bytes before reading", input)
+		}
+		if _, err := p.ReadHeaders(); err != nil {
+			t.Fatal(err)
+		}
+		var b strings.Builder
+		b.Write(p.Raw())
+		for {
+			row, err := p.ReadRow()
+			b.Write(p.Raw())
+			if err == io.EOF {
+				break
+			}
+			if row != nil && (string(row.Raw) != string(p.Raw()) || row.Quoted != nil) {
+				t.Errorf("%q: row %d has raw %q and quoted %v, want %q and none", input, row.LineNumber, row.Raw, row.Quoted, p.Raw())
+			}
+			if err != nil && !errors.As(err, new(*EncodingError)) {
+				break // Reading stops at a parse error
+			}
+		}
+		// The raw bytes of the reads make up the input, an unterminated quote reading to its end
+		if got := b.String(); got != input {
+			t.Errorf("%q: raw bytes %q", input, got)
+		}
+	}
+
+	// The failing record's bytes are kept
+	p, _ := NewParser(strings.NewReader("a,b\n1,\"x\"y\n"), ",")
+	p.KeepRaw()
+	p.ReadHeaders()
+	if _, err := p.ReadRow(); err == nil || string(p.Raw()) != "1,\"x\"y\n" {
+		t.Errorf("want the raw bytes of the malformed record, got %q (%v)", p.Raw(), err)
+	}
+}
+
+func TestFieldSpans(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want []string
+	}{
+		{"a,b,c\n", []string{"a", "b", "c"}},
+		{"a,,\r\n", []string{"a", "", ""}},
+		{"\n\"x,\"\"y\"\"\", z \n", []string{`"x,""y"""`, " z "}},
+		{"\"multi\nline\",2", []string{"\"multi\nline\"", "2"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, span := range FieldSpans([]byte(tt.raw), Dialect{Delimiter: ","}) {
+			got = append(got, tt.raw[span[0]:span[1]])
+		}
+		if fmt.Sprintf("%q", got) != fmt.Sprintf("%q", tt.want) {
+			t.Errorf("%q: expected %q, got %q", tt.raw, tt.want, got)
+		}
+	}
+}
+
 func TestParserWithLargeInput(t *testing.T) {
 	// Create a large input (>4KB to test buffering)
 	var buf bytes.Buffer
@@ -524,3 +646,157 @@ func TestReadSampleFromBytes(t *testing.T) {
 		}
 	})
 }
+
+func TestReadSepPreamble(t *testing.T) {
+	tests := []struct {
+		input, line, delimiter, rest string
+	}{
+		{"sep=;\na;b\n", "sep=;", ";", "a;b\n"},
+		{"SEP=|\r\na|b\n", "SEP=|", "|", "a|b\n"},
+		{"sep=;\ra;b\r", "sep=;", ";", "a;b\r"},
+		{"\xef\xbb\xbfsep=\t\na\tb\n", "sep=\t", "\t", "a\tb\n"},
+		{"sep=;", "sep=;", ";", ""},
+		{"sep=,x\n", "", "", "sep=,x\n"},
+		{"separator\n", "", "", "separator\n"},
+		{"\xef\xbb\xbfa,b\n", "", "", "\xef\xbb\xbfa,b\n"},
+		{"a", "", "", "a"},
+	}
+	for _, tt := range tests {
+		for _, seekable := range []bool{true, false} {
+			var r io.Reader = strings.NewReader(tt.input)
+			if !seekable {
+				r = io.MultiReader(r)
+			}
+			line, delimiter, n, rest, err := ReadSepPreamble(r)
+			if err != nil {
+				t.Fatalf("%q: %v", tt.input, err)
+			}
+			data, _ := io.ReadAll(rest)
+			if line != tt.line || delimiter != tt.delimiter || string(data) != tt.rest || n != len(tt.input)-len(tt.rest) {
+				t.Errorf("%q (seekable %v): got %q, %q, %d bytes, rest %q; want %q, %q, rest %q",
+					tt.input, seekable, line, delimiter, n, data, tt.line, tt.delimiter, tt.rest)
+			}
+			if seekable && rest != r {
+				t.Errorf("%q: want the seekable reader back", tt.input)
+			}
+		}
+	}
+}
+
+func TestFormatRecord(t *testing.T) {
+	fields := []string{"1", "a", "", "x,y", `say "hi"`, " lead"}
+	tests := map[string]string{
+		QuoteMinimal:    `1,a,,"x,y","say ""hi"""," lead"`,
+		QuoteAll:        `"1","a","","x,y","say ""hi"""," lead"`,
+		QuoteNonNumeric: `1,"a",,"x,y","say ""hi"""," lead"`,
+	}
+	for policy, want := range tests {
+		if got := FormatRecord(fields, ',', policy); got != want {
+			t.Errorf("%s: got %s, want %s", policy, got, want)
+		}
+	}
+	if got := FormatRecord([]string{""}, ',', QuoteMinimal); got != `""` {
+		t.Errorf("want a single empty field quoted, got %s", got)
+	}
+	if err := CheckQuoting("some"); err == nil {
+		t.Error("want an unknown policy rejected")
+	}
+}
+
+func TestParserEncodingErrors(t *testing.T) {
+	input := "id,name\n1,ok\n2,caf\xc3\n3,\"multi\nline \xff\xfe\"\n4,fine\n5,\xe2\x82\xac\n"
+	// One byte per read splits the euro sign, which must not be reported
+	for _, r := range []io.Reader{strings.NewReader(input), iotest.OneByteReader(strings.NewReader(input))} {
+		p, err := NewParser(r, ",")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := p.ReadHeaders(); err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		var lines []int
+		for {
+			row, err := p.ReadRow()
+			if err == io.EOF {
+				break
+			}
+			var encErr *EncodingError
+			if errors.As(err, &encErr) {
+				got = append(got, fmt.Sprintf("%d:%d:%d %s", encErr.LineNumber, encErr.Line, encErr.Column, encErr.Describe()))
+				continue
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			lines = append(lines, row.LineNumber)
+		}
+		want := []string{
+			"3:3:2 invalid UTF-8 encoding at byte 18: c3",
+			"4:5:2 invalid UTF-8 encoding at byte 34: ff fe",
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("got %q, want %q", got, want)
+		}
+		if !slices.Equal(lines, []int{2, 5, 6}) {
+			t.Errorf("want reading to go on after invalid rows, got lines %v", lines)
+		}
+	}
+}
+
+func TestParserEnding(t *testing.T) {
+	for _, tc := range []struct {
+		input string
+		want  []Ending // After the header, each row, and io.EOF or the error ending the input
+	}{
+		{"a,b\n1,2\n", []Ending{NotAtEnd, NotAtEnd, EndsLine}},
+		{"a,b\r\n1,2\r\n", []Ending{NotAtEnd, NotAtEnd, EndsLine}},
+		{"a,b\n1,2", []Ending{NotAtEnd, EndsMidLine, EndsMidLine}},
+		{"a,b\n1,\"x\"\"y\"", []Ending{NotAtEnd, EndsMidLine, EndsMidLine}},
+		{"a,b\n1,\"cut", []Ending{NotAtEnd, EndsInQuotes}},
+		{"a,b\n1,\"cut\n", []Ending{NotAtEnd, EndsInQuotes}},
+	} {
+		p, err := NewParser(strings.NewReader(tc.input), ",")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := p.ReadHeaders(); err != nil {
+			t.Fatal(err)
+		}
+		got := []Ending{p.Ending()}
+		for {
+			_, err := p.ReadRow()
+			got = append(got, p.Ending())
+			if err != nil && err != io.EOF && !errors.Is(err, csv.ErrQuote) {
+				t.Fatal(err)
+			}
+			if err != nil {
+				break
+			}
+		}
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("%q: got %v, want %v", tc.input, got, tc.want)
+		}
+	}
+}
+
+func TestRepeatsHeader(t *testing.T) {
+	headers := []string{"\ufeffid", "Name", "email"}
+	for _, tc := range []struct {
+		fields []string
+		want   bool
+	}{
+		{[]string{"id", "Name", "email"}, true},
+		{[]string{"\ufeffID", " name ", "EMAIL"}, true},
+		{[]string{"id", "email", "Name"}, false},
+		{[]string{"id", "Name"}, false},
+		{[]string{"1", "Name", "email"}, false},
+	} {
+		if got := RepeatsHeader(tc.fields, headers); got != tc.want {
+			t.Errorf("RepeatsHeader(%q) = %v, want %v", tc.fields, got, tc.want)
+		}
+	}
+	if RepeatsHeader([]string{"", ""}, []string{"", ""}) {
+		t.Error("want a header without names never repeated")
+	}
+}
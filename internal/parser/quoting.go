@@ -0,0 +1,75 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Quoting policies: which fields are quoted.
+const (
+	QuoteMinimal    = "minimal"    // Only fields that need quotes
+	QuoteAll        = "all"        // Every field
+	QuoteNonNumeric = "nonnumeric" // Every field that is not a number
+	QuoteConsistent = "consistent" // Each column either always or never, apart from fields that need quotes; only checked, not written
+)
+
+// CheckQuoting returns an error for an unknown policy.
+func CheckQuoting(policy string) error {
+	switch policy {
+	case QuoteMinimal, QuoteAll, QuoteNonNumeric, QuoteConsistent:
+		return nil
+	}
+	return fmt.Errorf("unknown quoting policy '%s' (use %s, %s, %s or %s)", policy, QuoteMinimal, QuoteAll, QuoteNonNumeric, QuoteConsistent)
+}
+
+// NeedsQuotes reports whether field must be quoted to be read back as it is: it contains
+// the delimiter, a quote or a line break, or starts with a space, as encoding/csv decides.
+func NeedsQuotes(field string, delimiter rune) bool {
+	return strings.ContainsRune(field, delimiter) || strings.ContainsAny(field, "\"\r\n") ||
+		strings.HasPrefix(field, " ") || strings.HasPrefix(field, "\t")
+}
+
+// IsNumeric reports whether field is a number, as QuoteNonNumeric sees it.
+func IsNumeric(field string) bool {
+	_, err := strconv.ParseFloat(field, 64)
+	return err == nil
+}
+
+// WantsQuotes reports whether policy quotes field when writing it. Empty fields are only
+// quoted by QuoteAll.
+func WantsQuotes(policy, field string, delimiter rune) bool {
+	switch {
+	case NeedsQuotes(field, delimiter) || policy == QuoteAll:
+		return true
+	case policy == QuoteNonNumeric:
+		return field != "" && !IsNumeric(field)
+	}
+	return false
+}
+
+// Quote returns field in quotes, with its quotes doubled.
+func Quote(field string) string {
+	return `"` + strings.ReplaceAll(field, `"`, `""`) + `"`
+}
+
+// FormatRecord formats fields as one line of CSV, without the line ending, quoting the
+// fields policy wants quoted. A record of one empty field is quoted so it is not a blank
+// line.
+func FormatRecord(fields []string, delimiter rune, policy string) string {
+	if len(fields) == 1 && fields[0] == "" {
+		return `""`
+	}
+	var sb strings.Builder
+	for i, f := range fields {
+		if i > 0 {
+			sb.WriteRune(delimiter)
+		}
+		if WantsQuotes(policy, f, delimiter) {
+			sb.WriteString(Quote(f))
+		} else {
+			sb.WriteString(f)
+		}
+	}
+	return sb.String()
+}
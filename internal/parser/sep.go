@@ -0,0 +1,59 @@
+package parser
+
+import (
+	"bytes"
+	"io"
+)
+
+// maxSepLine is the longest sep= line: a byte order mark, "sep=", the delimiter and CRLF.
+const maxSepLine = 3 + 4 + 1 + 2
+
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// ReadSepPreamble detects the "sep=;" line Excel writes before the header to declare the
+// delimiter. It returns the line without its line ending, "" when the input does not start
+// with one, its delimiter, the number of bytes it takes up with its byte order mark and
+// line ending, and a reader positioned after the line. Seekable readers are repositioned
+// and returned as they are, so files stay files; other readers are wrapped.
+func ReadSepPreamble(r io.Reader) (line, delimiter string, n int, rest io.Reader, err error) {
+	seeker, seekable := r.(io.Seeker)
+	var start int64
+	if seekable {
+		if start, err = seeker.Seek(0, io.SeekCurrent); err != nil {
+			seekable = false
+		}
+	}
+	buf := make([]byte, maxSepLine)
+	read, err := io.ReadFull(r, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", "", 0, nil, err
+	}
+	buf = buf[:read]
+
+	consumed := 0
+	b := bytes.TrimPrefix(buf, utf8BOM)
+	bom := len(buf) - len(b)
+	if len(b) >= 5 && bytes.EqualFold(b[:4], []byte("sep=")) && b[4] < 0x80 && b[4] != '\r' && b[4] != '\n' {
+		switch end := b[5:]; {
+		case len(end) == 0:
+			consumed = bom + 5
+		case end[0] == '\n':
+			consumed = bom + 6
+		case len(end) >= 2 && end[0] == '\r' && end[1] == '\n':
+			consumed = bom + 7
+		case end[0] == '\r':
+			consumed = bom + 6
+		}
+	}
+	if consumed > 0 {
+		line, delimiter = string(buf[bom:bom+5]), string(b[4])
+	}
+
+	if seekable {
+		if _, err := seeker.Seek(start+int64(consumed), io.SeekStart); err != nil {
+			return "", "", 0, nil, err
+		}
+		return line, delimiter, consumed, r, nil
+	}
+	return line, delimiter, consumed, io.MultiReader(bytes.NewReader(buf[consumed:]), r), nil
+}
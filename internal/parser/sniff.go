@@ -0,0 +1,58 @@
+package parser
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// sniffDelimiters are the delimiters GuessDelimiter picks from, in order of preference.
+var sniffDelimiters = []rune{',', ';', '\t', '|'}
+
+// GuessDelimiter returns the delimiter line, a header, is most likely split by: the one of
+// comma, semicolon, tab and pipe it has most of outside double quotes, or "" when it has
+// none of them.
+func GuessDelimiter(line string) string {
+	counts := make(map[rune]int, len(sniffDelimiters))
+	quoted := false
+	for _, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		} else if !quoted {
+			counts[r]++
+		}
+	}
+	best := ""
+	most := 0
+	for _, d := range sniffDelimiters {
+		if counts[d] > most {
+			best, most = string(d), counts[d]
+		}
+	}
+	return best
+}
+
+// SniffDelimiter reads r up to its first line ending and guesses its delimiter from that
+// line with GuessDelimiter. The returned reader replays the bytes read and the rest of r.
+func SniffDelimiter(r io.Reader) (string, io.Reader) {
+	var head []byte
+	chunk := make([]byte, 4096)
+	var err error
+	for len(head) < maxLineEndingProbe {
+		var n int
+		n, err = r.Read(chunk)
+		head = append(head, chunk[:n]...)
+		if bytes.ContainsAny(head, "\r\n") || err != nil {
+			break
+		}
+	}
+	rest := r
+	if err != nil {
+		rest = failedReader{err}
+	}
+	line := string(head)
+	if i := strings.IndexAny(line, "\r\n"); i >= 0 {
+		line = line[:i]
+	}
+	return GuessDelimiter(line), io.MultiReader(bytes.NewReader(head), rest)
+}
@@ -0,0 +1,28 @@
+package parser
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestSniffDelimiter(t *testing.T) {
+	for input, want := range map[string]string{
+		"id,name,email\n1,a,b\n":   ",",
+		"id;name;\"a,b\"\r\n1;2;3": ";",
+		"id\tname\n":               "\t",
+		"id|name|x,y\n":            "|",
+		"\"a;b\",c\n":              ",",
+		"id\n1\n":                  "",
+		"":                         "",
+	} {
+		d, r := SniffDelimiter(iotest.OneByteReader(strings.NewReader(input)))
+		if d != want {
+			t.Errorf("%q: got %q, want %q", input, d, want)
+		}
+		if data, err := io.ReadAll(r); err != nil || string(data) != input {
+			t.Errorf("%q: replayed %q (%v)", input, data, err)
+		}
+	}
+}
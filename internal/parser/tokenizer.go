@@ -0,0 +1,203 @@
+package parser
+
+import (
+	"bufio"
+	"encoding/csv"
+	"io"
+	"strings"
+)
+
+// records reads the records of the input, as encoding/csv does.
+type records interface {
+	Read() ([]string, error)
+	InputOffset() int64
+}
+
+// tokenizer reads the records of dialects encoding/csv does not read: whitespace-delimited
+// fields, fields quoted with another quote character or never quoted, and fields escaping delimiters, quotes, line
+// breaks and the escape character itself with an escape character, e.g. a\,b for "a,b",
+// as producers that do not quote write them. Quoted fields are otherwise read as
+// encoding/csv reads them, and errors are the *csv.ParseError values it returns, naming
+// the dialect's quote character when it is not ".
+type tokenizer struct {
+	r          *bufio.Reader
+	sep        byte
+	whitespace bool
+	quote      int   // Quote character, -1 for none
+	escape     int   // Escape character, -1 for none
+	offset     int64 // Input offset of the next byte
+	line       int   // 1-based line of the next byte
+	column     int   // 1-based column of the last byte read
+	quoted     bool  // The input ended inside a quoted field
+}
+
+func newTokenizer(r io.Reader, d Dialect) *tokenizer {
+	t := &tokenizer{
+		r:          bufio.NewReader(r),
+		sep:        byte(DelimiterRune(d.Delimiter)),
+		whitespace: d.Delimiter == Whitespace,
+		quote:      int(d.quote()),
+		escape:     -1,
+		line:       1,
+	}
+	if d.Escape != 0 {
+		t.escape = int(d.Escape)
+	}
+	return t
+}
+
+// InputOffset returns the input offset of the end of the record just read.
+func (t *tokenizer) InputOffset() int64 {
+	return t.offset
+}
+
+func (t *tokenizer) next() (byte, error) {
+	c, err := t.r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	t.offset++
+	t.column++
+	if c == '\n' {
+		t.line++
+		t.column = 0
+	}
+	return c, nil
+}
+
+// lineEnd consumes the rest of the line ending c starts, if it starts one. A carriage
+// return only ends a line before a line feed or the end of the input.
+func (t *tokenizer) lineEnd(c byte) bool {
+	if c == '\n' {
+		return true
+	}
+	if c != '\r' {
+		return false
+	}
+	b, err := t.r.Peek(1)
+	if err != nil {
+		return true
+	}
+	if b[0] == '\n' {
+		t.next()
+		return true
+	}
+	return false
+}
+
+// separates reports whether c ends a field.
+func (t *tokenizer) separates(c byte) bool {
+	if t.whitespace {
+		return isBlank(c)
+	}
+	return c == t.sep
+}
+
+func (t *tokenizer) parseError(startLine int, err error) error {
+	if t.quote != '"' {
+		err = &quoteError{err: err, quote: byte(t.quote)}
+	}
+	return &csv.ParseError{StartLine: startLine, Line: t.line, Column: t.column, Err: err}
+}
+
+// quoteError is csv.ErrQuote or csv.ErrBareQuote for a dialect quoting with another
+// character, which its message names in place of ".
+type quoteError struct {
+	err   error
+	quote byte
+}
+
+func (e *quoteError) Error() string {
+	return strings.Replace(e.err.Error(), `"`, string(e.quote), 1)
+}
+
+func (e *quoteError) Unwrap() error {
+	return e.err
+}
+
+// Read reads the next record, skipping blank lines.
+func (t *tokenizer) Read() ([]string, error) {
+	var record []string
+	var field []byte
+	startLine := 0 // Line of the record's first byte, 0 until it is read
+	fieldStart := true
+	quoted, closed := false, false // Inside a quoted field; after the closing quote of one
+	end := func() []string {
+		// Whitespace after the last field is not a field of its own
+		if !(t.whitespace && fieldStart && len(record) > 0) {
+			record = append(record, string(field))
+		}
+		return record
+	}
+	for {
+		c, err := t.next()
+		if err == io.EOF {
+			switch {
+			case quoted:
+				t.quoted = true
+				return nil, t.parseError(startLine, csv.ErrQuote)
+			case startLine == 0:
+				return nil, io.EOF
+			}
+			return end(), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if startLine == 0 {
+			if c == '\n' || c == '\r' && t.lineEnd(c) {
+				continue
+			}
+			startLine = t.line
+		}
+
+		switch {
+		case quoted:
+			switch {
+			case int(c) == t.escape:
+				if c, err = t.next(); err == nil {
+					field = append(field, c)
+				}
+			case int(c) == t.quote:
+				if b, err := t.r.Peek(1); err == nil && b[0] == c {
+					t.next()
+					field = append(field, c)
+				} else {
+					quoted, closed = false, true
+				}
+			case c == '\r' && t.lineEnd(c):
+				field = append(field, '\n')
+			default:
+				field = append(field, c)
+			}
+		case t.whitespace && fieldStart && isBlank(c):
+			// Whitespace before a field
+		case t.separates(c):
+			record = append(record, string(field))
+			field, fieldStart, closed = nil, true, false
+		case t.lineEnd(c):
+			return end(), nil
+		case closed:
+			return nil, t.parseError(startLine, csv.ErrQuote)
+		case int(c) == t.escape:
+			fieldStart = false
+			c, err = t.next()
+			if err != nil {
+				field = append(field, byte(t.escape)) // An escape character ending the input is kept
+				continue
+			}
+			if c == '\r' && t.lineEnd(c) {
+				c = '\n'
+			}
+			field = append(field, c)
+		case int(c) == t.quote:
+			if !fieldStart {
+				return nil, t.parseError(startLine, csv.ErrBareQuote)
+			}
+			quoted, fieldStart = true, false
+		default:
+			field = append(field, c)
+			fieldStart = false
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package parser
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestTokenizerEscape(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		delimiter string
+		want      string
+	}{
+		{"escaped delimiter", `a\,b,c` + "\n", ",", `[["a,b" "c"]]`},
+		{"escaped escape and quote", `a\\,\"b\"` + "\n", ",", `[["a\\" "\"b\""]]`},
+		{"escaped line break", "a\\\nb,c\r\nd,e", ",", `[["a\nb" "c"] ["d" "e"]]`},
+		{"quoted fields", `"x,y","say ""hi"" \"now\""` + "\n\n1,\"\"\n", ",", `[["x,y" "say \"hi\" \"now\""] ["1" ""]]`},
+		{"escape ending the input", `a,b\`, ",", `[["a" "b\\"]]`},
+		{"whitespace", "  a\\ b \t c  \"\"  \n", Whitespace, `[["a b" "c" ""]]`},
+		{"whitespace trailing", "a   b  \r\n", Whitespace, `[["a" "b"]]`},
+	}
+	for _, tt := range tests {
+		tk := newTokenizer(strings.NewReader(tt.input), Dialect{Delimiter: tt.delimiter, Escape: '\\'})
+		var got [][]string
+		for {
+			record, err := tk.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("%s: %v", tt.name, err)
+			}
+			got = append(got, record)
+		}
+		if fmt.Sprintf("%q", got) != tt.want {
+			t.Errorf("%s: got %q, want %s", tt.name, got, tt.want)
+		}
+		if tk.InputOffset() != int64(len(tt.input)) {
+			t.Errorf("%s: offset %d, want %d", tt.name, tk.InputOffset(), len(tt.input))
+		}
+	}
+}
+
+func TestTokenizerQuote(t *testing.T) {
+	tests := []struct {
+		name  string
+		quote rune
+		input string
+		want  string
+	}{
+		{"single quotes", '\'', `'a,b','it''s',"x"` + "\n", `[["a,b" "it's" "\"x\""]]`},
+		{"no quotes", NoQuote, `5" pipe,"a"` + "\n" + `'b',c`, `[["5\" pipe" "\"a\""] ["'b'" "c"]]`},
+	}
+	for _, tt := range tests {
+		tk := newTokenizer(strings.NewReader(tt.input), Dialect{Delimiter: ",", Quote: tt.quote})
+		var got [][]string
+		for {
+			record, err := tk.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("%s: %v", tt.name, err)
+			}
+			got = append(got, record)
+		}
+		if fmt.Sprintf("%q", got) != tt.want {
+			t.Errorf("%s: got %q, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTokenizerErrors(t *testing.T) {
+	tests := []struct {
+		input  string
+		err    error
+		line   int
+		column int
+	}{
+		{"a,b\"c\n", csv.ErrBareQuote, 1, 4},
+		{"a,\"b\"c\n", csv.ErrQuote, 1, 6},
+		{"a,b\n1,\"open\nmore", csv.ErrQuote, 3, 4},
+	}
+	for _, tt := range tests {
+		tk := newTokenizer(strings.NewReader(tt.input), Dialect{Delimiter: ",", Escape: '\\'})
+		var err error
+		for err == nil {
+			_, err = tk.Read()
+		}
+		var parseErr *csv.ParseError
+		if !errors.As(err, &parseErr) || !errors.Is(err, tt.err) || parseErr.Line != tt.line || parseErr.Column != tt.column {
+			t.Errorf("%q: got %v, want %v on line %d, column %d", tt.input, err, tt.err, tt.line, tt.column)
+		}
+	}
+}
+
+func TestTokenizerQuoteErrors(t *testing.T) {
+	tk := newTokenizer(strings.NewReader("a,b'c\n"), Dialect{Delimiter: ",", Quote: '\''})
+	_, err := tk.Read()
+	if !errors.Is(err, csv.ErrBareQuote) || !strings.Contains(err.Error(), "bare ' in non-quoted-field") {
+		t.Errorf("got %v, want a bare ' error", err)
+	}
+}
+
+func TestParserEscape(t *testing.T) {
+	input := "id,path\n1,C:\\\\data\\, old\n2,\"x\\\"y\"\n3,\"open"
+	d := Dialect{Delimiter: ",", Escape: '\\'}
+	p, err := NewParserWithDialect(strings.NewReader(input), d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.TrackQuotes()
+	if _, err := p.ReadHeaders(); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for {
+		row, err := p.ReadRow()
+		if err != nil {
+			if !errors.Is(err, csv.ErrQuote) || p.Ending() != EndsInQuotes {
+				t.Errorf("want the unterminated quote at the end of the input, got %v and %v", err, p.Ending())
+			}
+			break
+		}
+		var spans []string
+		for _, span := range FieldSpans(row.Raw, d) {
+			spans = append(spans, string(row.Raw[span[0]:span[1]]))
+		}
+		got = append(got, fmt.Sprintf("%q %q %v", row.Data, spans, row.Quoted))
+	}
+	want := []string{
+		`["1" "C:\\data, old"] ["1" "C:\\\\data\\, old"] [false false]`,
+		`["2" "x\"y"] ["2" "\"x\\\"y\""] [false true]`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestReadSampleWithDialect(t *testing.T) {
+	headers, sample, _, err := ReadSampleWithDialect(strings.NewReader("a,b\n1\\,5,2\n"), Dialect{Delimiter: ",", Escape: '\\'}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprintf("%q %q", headers, sample); got != `["a" "b"] [["1,5" "2"]]` {
+		t.Errorf("got %s", got)
+	}
+}
@@ -0,0 +1,124 @@
+package parser
+
+import (
+	"bytes"
+	"io"
+	"time"
+	"unicode/utf8"
+)
+
+// maxFaultBytes bounds the invalid bytes kept for a fault.
+const maxFaultBytes = 8
+
+// utf8Fault is a run of invalid UTF-8 bytes in the input.
+type utf8Fault struct {
+	offset int64 // Input offset of the first invalid byte
+	end    int64 // Input offset after the last invalid byte
+	line   int   // 1-based physical line of the first invalid byte
+	bytes  []byte
+}
+
+// utf8Scanner validates the UTF-8 of the input as it streams through, and records where
+// invalid bytes are until the records holding them are read. A sequence split across
+// reads is checked once the rest of it arrives.
+type utf8Scanner struct {
+	r       io.Reader
+	offset  int64 // Input offset of the next byte read, after carry
+	line    int
+	newline byte   // Byte ending lines: a line feed, or a carriage return in CR-only input
+	carry   []byte // Incomplete sequence at the end of the last read
+	faults  []utf8Fault
+	timed   bool
+	elapsed time.Duration
+
+	eof       bool // The input has been read to its end
+	last      byte // Last byte of the input read so far
+	openQuote bool // The input read so far has an odd number of quotes
+}
+
+func newUTF8Scanner(r io.Reader) *utf8Scanner {
+	return &utf8Scanner{r: r, line: 1, newline: '\n'}
+}
+
+func (s *utf8Scanner) Read(b []byte) (int, error) {
+	n, err := s.r.Read(b)
+	if n > 0 {
+		s.last = b[n-1]
+		if bytes.Count(b[:n], []byte{'"'})%2 == 1 {
+			s.openQuote = !s.openQuote
+		}
+	}
+	s.eof = s.eof || err == io.EOF
+	if s.timed {
+		start := time.Now()
+		defer func() { s.elapsed += time.Since(start) }()
+	}
+	s.scan(b[:n], err != nil)
+	return n, err
+}
+
+// scan checks chunk, which continues the carried bytes. At the end of the input an
+// incomplete sequence is invalid.
+func (s *utf8Scanner) scan(chunk []byte, end bool) {
+	if len(s.carry) == 0 && utf8.Valid(chunk) {
+		s.line += bytes.Count(chunk, []byte{s.newline})
+		s.offset += int64(len(chunk))
+		return
+	}
+	data := chunk
+	start := s.offset
+	if len(s.carry) > 0 {
+		data = append(s.carry, chunk...)
+		start -= int64(len(s.carry))
+		s.carry = nil
+	}
+	for i := 0; i < len(data); {
+		c := data[i]
+		if c < utf8.RuneSelf {
+			if c == s.newline {
+				s.line++
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRune(data[i:])
+		if r == utf8.RuneError && size == 1 {
+			if !end && !utf8.FullRune(data[i:]) {
+				s.carry = append([]byte(nil), data[i:]...)
+				break
+			}
+			s.fault(start+int64(i), c)
+		}
+		i += size
+	}
+	s.offset = start + int64(len(data))
+}
+
+// fault records an invalid byte, extending the last fault when it directly follows it.
+func (s *utf8Scanner) fault(offset int64, b byte) {
+	if n := len(s.faults); n > 0 {
+		last := &s.faults[n-1]
+		if last.end == offset {
+			if len(last.bytes) < maxFaultBytes {
+				last.bytes = append(last.bytes, b)
+			}
+			last.end++
+			return
+		}
+	}
+	s.faults = append(s.faults, utf8Fault{offset: offset, end: offset + 1, line: s.line, bytes: []byte{b}})
+}
+
+// take forgets the faults before the input offset end and returns the first of them.
+func (s *utf8Scanner) take(end int64) (utf8Fault, bool) {
+	i := 0
+	for i < len(s.faults) && s.faults[i].offset < end {
+		i++
+	}
+	if i == 0 {
+		return utf8Fault{}, false
+	}
+	first := s.faults[0]
+	s.faults = append(s.faults[:0], s.faults[i:]...)
+	return first, true
+}
@@ -0,0 +1,39 @@
+package parser
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// Whitespace is the delimiter of whitespace-delimited input, whose fields are separated by
+// any run of spaces and tabs, as in the space-aligned columns some instruments export.
+// Spaces and tabs before the first field and after the last are ignored, so fields can
+// only be empty, or hold whitespace, when they are quoted.
+const Whitespace = "whitespace"
+
+// DelimiterRune returns the character fields are separated by with delimiter: a space for
+// Whitespace, and the first character of delimiter otherwise.
+func DelimiterRune(delimiter string) rune {
+	if delimiter == Whitespace {
+		return ' '
+	}
+	return rune(delimiter[0])
+}
+
+// newReader returns a reader of the records of r in dialect d, with any number of fields
+// per record. Whitespace-delimited input is read by the tokenizer, which knows a quoted
+// empty last field from trailing whitespace.
+func newReader(r io.Reader, d Dialect) records {
+	if !d.standard() || d.Delimiter == Whitespace {
+		return newTokenizer(r, d)
+	}
+	rd := csv.NewReader(r)
+	rd.Comma = DelimiterRune(d.Delimiter)
+	rd.FieldsPerRecord = -1
+	return rd
+}
+
+// isBlank reports whether ch separates the fields of whitespace-delimited input.
+func isBlank(ch byte) bool {
+	return ch == ' ' || ch == '\t'
+}
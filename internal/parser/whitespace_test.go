@@ -0,0 +1,55 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestParserWhitespace(t *testing.T) {
+	input := "  time   temp\tnote\n0.0    21.5\t\"warm up\"  \r\n\n0.5 \t 21.7   \"\"\n1.0\t22.0\tok\n"
+	p, err := NewParser(strings.NewReader(input), Whitespace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.TrackQuotes()
+	headers, err := p.ReadHeaders()
+	if err != nil || fmt.Sprintf("%q", headers) != `["time" "temp" "note"]` {
+		t.Fatalf("headers %q (%v)", headers, err)
+	}
+	var got []string
+	for {
+		row, err := p.ReadRow()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		var spans []string
+		for _, span := range FieldSpans(row.Raw, Dialect{Delimiter: Whitespace}) {
+			spans = append(spans, string(row.Raw[span[0]:span[1]]))
+		}
+		got = append(got, fmt.Sprintf("%d %q %q %v", row.LineNumber, row.Data, spans, row.Quoted))
+	}
+	want := []string{
+		`2 ["0.0" "21.5" "warm up"] ["0.0" "21.5" "\"warm up\""] [false false true]`,
+		// A quoted empty last field is a field, unlike trailing whitespace
+		`3 ["0.5" "21.7" ""] ["0.5" "21.7" "\"\""] [false false true]`,
+		`4 ["1.0" "22.0" "ok"] ["1.0" "22.0" "ok"] [false false false]`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestReadSampleWhitespace(t *testing.T) {
+	headers, sample, err := ReadSampleFromBytes([]byte("a b\n1\t\t2\n3  \"4 5\"\n"), Whitespace, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprintf("%q %q", headers, sample); got != `["a" "b"] [["1" "2"] ["3" "4 5"]]` {
+		t.Errorf("got %s", got)
+	}
+}
@@ -0,0 +1,104 @@
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// Writer writes records back as the parser read them, byte for byte, rewriting only the
+// fields that changed. Records left alone keep their quoting, the whitespace around their
+// fields and their line endings, so a diff of the output shows nothing but the changes.
+type Writer struct {
+	w *bufio.Writer
+	d Dialect
+}
+
+// NewWriter returns a writer of records in dialect d to w.
+func NewWriter(w io.Writer, d Dialect) *Writer {
+	return &Writer{w: bufio.NewWriter(w), d: d}
+}
+
+// Write writes the record read as raw, whose fields were original, with fields instead.
+// Changed fields are quoted if they were or need to be, in the dialect's quote, and
+// escaped where it has no quote. Fields past the end of original are appended after a
+// delimiter and fields past the end of fields are dropped, with the delimiters before
+// them; the blank lines before the record and its line ending are kept.
+func (w *Writer) Write(raw []byte, original, fields []string) error {
+	if slices.Equal(original, fields) {
+		return w.WriteRaw(raw)
+	}
+	spans := FieldSpans(raw, w.d)
+	var prefix, suffix []byte
+	if len(spans) == len(original) {
+		prefix, suffix = raw[:spans[0][0]], raw[spans[len(spans)-1][1]:]
+	} else {
+		// Spans that do not match the fields: format the record whole
+		spans, suffix = nil, lineEnding(raw)
+	}
+	out := append([]byte(nil), prefix...)
+	for i, field := range fields {
+		if i < len(spans) {
+			if i > 0 {
+				out = append(out, raw[spans[i-1][1]:spans[i][0]]...)
+			}
+			text := raw[spans[i][0]:spans[i][1]]
+			if field == original[i] {
+				out = append(out, text...)
+				continue
+			}
+			quoted := len(text) > 0 && rune(text[0]) == w.d.quote()
+			formatted, err := w.format(field, quoted || len(fields) == 1 && field == "")
+			if err != nil {
+				return err
+			}
+			out = append(out, formatted...)
+			continue
+		}
+		if i > 0 {
+			out = append(out, string(DelimiterRune(w.d.Delimiter))...)
+		}
+		formatted, err := w.format(field, len(fields) == 1 && field == "")
+		if err != nil {
+			return err
+		}
+		out = append(out, formatted...)
+	}
+	out = append(out, suffix...)
+	_, err := w.w.Write(out)
+	return err
+}
+
+// format returns field as written in the dialect, quoted when quote is set.
+func (w *Writer) format(field string, quote bool) (string, error) {
+	text, ok := w.d.FormatField(field, quote)
+	if !ok {
+		return "", fmt.Errorf("cannot write %q: it needs quotes the dialect does not have, or an escape character", field)
+	}
+	return text, nil
+}
+
+// WriteRaw writes raw as it is, as for a record that did not change or the blank lines at
+// the end of the input.
+func (w *Writer) WriteRaw(raw []byte) error {
+	_, err := w.w.Write(raw)
+	return err
+}
+
+// Flush writes any buffered data to the underlying writer.
+func (w *Writer) Flush() error {
+	return w.w.Flush()
+}
+
+// lineEnding returns the line ending at the end of raw, if any.
+func lineEnding(raw []byte) []byte {
+	n := len(raw)
+	if n > 0 && raw[n-1] == '\n' {
+		n--
+	}
+	if n > 0 && raw[n-1] == '\r' {
+		n--
+	}
+	return raw[n:]
+}
@@ -0,0 +1,103 @@
+package parser
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestWriterRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		d      Dialect
+		input  string
+		change func(fields []string) []string
+		want   string
+	}{
+		{
+			name:   "untouched",
+			d:      Dialect{Delimiter: ","},
+			input:  "id,name\r\n\"1\", bob \n\n2,\"a \"\"b\"\"\"\r\n\n",
+			change: func(f []string) []string { return f },
+			want:   "id,name\r\n\"1\", bob \n\n2,\"a \"\"b\"\"\"\r\n\n",
+		},
+		{
+			name:  "changed fields keep their quoting",
+			d:     Dialect{Delimiter: ","},
+			input: "id,name,note\r\n\"1\", bob ,x\r\n\"2\",\" ann \",\"y\"\r\n",
+			change: func(f []string) []string {
+				f[1] = strings.TrimSpace(f[1])
+				return f
+			},
+			want: "id,name,note\r\n\"1\",bob,x\r\n\"2\",\"ann\",\"y\"\r\n",
+		},
+		{
+			name:   "dropped and added fields",
+			d:      Dialect{Delimiter: ";"},
+			input:  "a;b;\n1;\"x;y\";\n",
+			change: func(f []string) []string { return append(f[:2:2], "z;") },
+			want:   "a;b;\"z;\"\n1;\"x;y\";\"z;\"\n",
+		},
+		{
+			name:   "whitespace keeps alignment",
+			d:      Dialect{Delimiter: Whitespace},
+			input:  "time   temp\n0.0    21.5\n",
+			change: func(f []string) []string { return append(f, "") },
+			want:   "time   temp \"\"\n0.0    21.5 \"\"\n",
+		},
+		{
+			name:   "escaped",
+			d:      Dialect{Delimiter: ",", Quote: NoQuote, Escape: '\\'},
+			input:  "a,b\n1,x\\,y\n",
+			change: func(f []string) []string { f[0] += ",0"; return f },
+			want:   "a\\,0,b\n1\\,0,x\\,y\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewParserWithDialect(strings.NewReader(tt.input), tt.d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p.KeepRaw()
+			var buf bytes.Buffer
+			w := NewWriter(&buf, tt.d)
+			headers, err := p.ReadHeaders()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := w.Write(p.Raw(), headers, tt.change(append([]string(nil), headers...))); err != nil {
+				t.Fatal(err)
+			}
+			for {
+				row, err := p.ReadRow()
+				if err == io.EOF {
+					if err := w.WriteRaw(p.Raw()); err != nil {
+						t.Fatal(err)
+					}
+					break
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := w.Write(row.Raw, row.Data, tt.change(append([]string(nil), row.Data...))); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := w.Flush(); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("got %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestWriterCannotWrite(t *testing.T) {
+	w := NewWriter(io.Discard, Dialect{Delimiter: ",", Quote: NoQuote})
+	if err := w.Write([]byte("a,b\n"), []string{"a", "b"}, []string{"a,", "b"}); err == nil {
+		t.Error("want an error for a field needing quotes in a dialect without them")
+	}
+}
@@ -0,0 +1,178 @@
+// Package patch turns the fixes of findings into unified diffs, which patch(1) and
+// git apply apply to the files they were found in.
+package patch
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+// Context is the number of unchanged lines around each change of a diff.
+const Context = 3
+
+// usable returns the fixes that can be applied to data together, in file order: fixes
+// outside data, repeated, or overlapping one at a lower offset are left out.
+func usable(data []byte, fixes []validator.Fix) []validator.Fix {
+	sorted := append([]validator.Fix(nil), fixes...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Offset < sorted[j].Offset })
+	var out []validator.Fix
+	end := int64(0)
+	for _, f := range sorted {
+		if f.Offset < end || f.Length < 0 || f.Offset+int64(f.Length) > int64(len(data)) {
+			continue
+		}
+		if n := len(out); n > 0 && out[n-1] == f {
+			continue
+		}
+		out = append(out, f)
+		end = f.Offset + int64(f.Length)
+	}
+	return out
+}
+
+// apply returns data, which starts at offset base of the file, with fixes in file order
+// applied.
+func apply(data []byte, base int64, fixes []validator.Fix) []byte {
+	var out bytes.Buffer
+	last := int64(0)
+	for _, f := range fixes {
+		start := f.Offset - base
+		out.Write(data[last:start])
+		out.WriteString(f.Text)
+		last = start + int64(f.Length)
+	}
+	out.Write(data[last:])
+	return out.Bytes()
+}
+
+// change replaces the lines from first to last of the original with lines.
+type change struct {
+	first, last int
+	lines       [][]byte
+}
+
+// Write writes the unified diff from data, the content of the file at path, to data with
+// fixes applied, with Context lines around each change, and returns the number of fixes
+// applied. It writes nothing when none applies.
+func Write(w io.Writer, path string, data []byte, fixes []validator.Fix) (int, error) {
+	applied := usable(data, fixes)
+	if len(applied) == 0 || len(data) == 0 {
+		return 0, nil
+	}
+	lines := splitLines(data)
+	starts := make([]int64, len(lines)+1)
+	for i, l := range lines {
+		starts[i+1] = starts[i] + int64(len(l))
+	}
+	// lineAt returns the line holding offset; the end of the data is on the last line
+	lineAt := func(offset int64) int {
+		i := sort.Search(len(lines), func(i int) bool { return starts[i+1] > offset })
+		return min(i, len(lines)-1)
+	}
+
+	// Fixes on the same or touching lines make one change
+	var changes []change
+	var group []validator.Fix
+	flush := func() {
+		first := lineAt(group[0].Offset)
+		last := lineAt(group[len(group)-1].Offset + int64(max(group[len(group)-1].Length-1, 0)))
+		text := apply(data[starts[first]:starts[last+1]], starts[first], group)
+		changes = append(changes, change{first: first, last: last, lines: splitLines(text)})
+		group = nil
+	}
+	for _, f := range applied {
+		if len(group) > 0 {
+			prev := group[len(group)-1]
+			if lineAt(f.Offset) > lineAt(prev.Offset+int64(max(prev.Length-1, 0)))+1 {
+				flush()
+			}
+		}
+		group = append(group, f)
+	}
+	flush()
+
+	var out bytes.Buffer
+	name := filepath.ToSlash(path)
+	fmt.Fprintf(&out, "--- a/%s\n+++ b/%s\n", name, name)
+	delta := 0 // Lines added so far less lines removed
+	for i := 0; i < len(changes); {
+		// Changes whose context touches make one hunk
+		j := i + 1
+		for j < len(changes) && changes[j].first-changes[j-1].last-1 <= 2*Context {
+			j++
+		}
+		from := max(changes[i].first-Context, 0)
+		to := min(changes[j-1].last+Context, len(lines)-1)
+		var body bytes.Buffer
+		oldCount, newCount := 0, 0
+		at := from
+		for _, c := range changes[i:j] {
+			for ; at < c.first; at++ {
+				writeLine(&body, ' ', lines[at])
+				oldCount++
+				newCount++
+			}
+			for ; at <= c.last; at++ {
+				writeLine(&body, '-', lines[at])
+				oldCount++
+			}
+			for _, l := range c.lines {
+				writeLine(&body, '+', l)
+				newCount++
+			}
+		}
+		for ; at <= to; at++ {
+			writeLine(&body, ' ', lines[at])
+			oldCount++
+			newCount++
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(from+1, oldCount), hunkRange(from+1+delta, newCount))
+		out.Write(body.Bytes())
+		delta += newCount - oldCount
+		i = j
+	}
+	if _, err := w.Write(out.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(applied), nil
+}
+
+// splitLines splits data after each line feed, keeping the line endings.
+func splitLines(data []byte) [][]byte {
+	var lines [][]byte
+	for len(data) > 0 {
+		i := bytes.IndexByte(data, '\n') + 1
+		if i == 0 {
+			i = len(data)
+		}
+		lines = append(lines, data[:i])
+		data = data[i:]
+	}
+	return lines
+}
+
+// writeLine writes a line of a hunk, marking a last line without a line feed as diff does.
+func writeLine(w *bytes.Buffer, prefix byte, line []byte) {
+	w.WriteByte(prefix)
+	w.Write(line)
+	if !bytes.HasSuffix(line, []byte("\n")) {
+		w.WriteString("\n\\ No newline at end of file\n")
+	}
+}
+
+// hunkRange formats the start and length of one side of a hunk; empty sides start at the
+// line before them.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start-1)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
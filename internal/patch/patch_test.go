@@ -0,0 +1,37 @@
+package patch
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+func TestWrite(t *testing.T) {
+	data := "id,name\n1, a\n2,b\n3,c\n4,d\n5,e\n6,f\n7,g\n8,h\n9,i\n10,\"j\""
+	at := func(s string) int64 { return int64(strings.Index(data, s)) }
+	fixes := []validator.Fix{
+		{Description: "remove the quotes", Offset: at(`"j"`), Length: 3, Text: "j"},
+		{Description: "trim", Offset: at(" a"), Length: 2, Text: "a"},
+		{Description: "trim", Offset: at(" a"), Length: 2, Text: "a"}, // Repeated
+		{Description: "overlapping", Offset: at(" a") + 1, Length: 1, Text: "x"},
+		{Description: "add 1 empty field(s)", Offset: at("2,b") + 3, Text: ","},
+		{Description: "outside", Offset: int64(len(data)), Length: 1},
+	}
+	var sb strings.Builder
+	n, err := Write(&sb, "data/in.csv", []byte(data), fixes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "--- a/data/in.csv\n+++ b/data/in.csv\n" +
+		"@@ -1,6 +1,6 @@\n id,name\n-1, a\n-2,b\n+1,a\n+2,b,\n 3,c\n 4,d\n 5,e\n" +
+		"@@ -8,4 +8,4 @@\n 7,g\n 8,h\n 9,i\n-10,\"j\"\n\\ No newline at end of file\n+10,j\n\\ No newline at end of file\n"
+	if n != 3 || sb.String() != want {
+		t.Errorf("applied %d fixes, diff:\n%s\nwant 3 and:\n%s", n, sb.String(), want)
+	}
+
+	sb.Reset()
+	if n, err := Write(&sb, "in.csv", []byte(data), nil); n != 0 || err != nil || sb.Len() != 0 {
+		t.Errorf("without fixes: got %d, %v, %q", n, err, sb.String())
+	}
+}
@@ -0,0 +1,244 @@
+// Package profile summarizes the value distribution of each column of a CSV file, and
+// compares a file's profile to a stored baseline to detect drift.
+package profile
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+// Version is the profile_version written to profiles; Load rejects other versions.
+const Version = 1
+
+// maxDistinct bounds the distinct values counted per column. Columns with more, such as
+// identifiers, are marked as capped and their distinct counts are not compared.
+const maxDistinct = 1_000_000
+
+// Profile is the value distribution of a file's columns.
+type Profile struct {
+	Version int      `json:"profile_version"`
+	Rows    int      `json:"rows"`
+	Columns []Column `json:"columns"`
+}
+
+// Column summarizes the values of one column.
+type Column struct {
+	Name           string   `json:"name"`
+	NullRate       float64  `json:"null_rate"`                 // Share of rows with an empty value, 0 to 1
+	Mean           *float64 `json:"mean,omitempty"`            // Set when every non-empty value is a number
+	Distinct       int      `json:"distinct"`                  // Distinct non-empty values
+	DistinctCapped bool     `json:"distinct_capped,omitempty"` // More distinct values than were counted
+}
+
+// Load reads a profile written by Save.
+func Load(path string) (*Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profile: %w", err)
+	}
+	var p Profile
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse profile '%s': %w", path, err)
+	}
+	if p.Version != Version {
+		return nil, fmt.Errorf("profile '%s' has profile_version %d, want %d", path, p.Version, Version)
+	}
+	return &p, nil
+}
+
+// Save writes p as indented JSON to path.
+func (p *Profile) Save(path string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// Collector builds the profile of the rows it sees. It is a validator.Check without
+// findings, so profiles describe rows exactly as validation sees them.
+type Collector struct {
+	headers []string
+	rows    int
+	columns []columnStats
+}
+
+type columnStats struct {
+	empty    int
+	sum      float64
+	numbers  int
+	numeric  bool // No non-empty value so far failed to parse as a number
+	distinct map[uint64]struct{}
+	capped   bool
+}
+
+// NewCollector returns an empty collector.
+func NewCollector() *Collector {
+	return &Collector{}
+}
+
+// Start resets the collector for a file with the given header.
+func (c *Collector) Start(headerLine int, headers []string) []validator.Finding {
+	c.headers = headers
+	c.rows = 0
+	c.columns = make([]columnStats, len(headers))
+	for i := range c.columns {
+		c.columns[i] = columnStats{numeric: true, distinct: make(map[uint64]struct{})}
+	}
+	return nil
+}
+
+// Row adds a data row to the statistics.
+func (c *Collector) Row(lineNumber int, fields []string) []validator.Finding {
+	c.rows++
+	for i := range c.columns {
+		s := &c.columns[i]
+		v := strings.TrimSpace(fields[i])
+		if v == "" {
+			s.empty++
+			continue
+		}
+		if !s.capped {
+			h := fnv.New64a()
+			h.Write([]byte(v))
+			s.distinct[h.Sum64()] = struct{}{}
+			if len(s.distinct) > maxDistinct {
+				s.capped = true
+			}
+		}
+		if s.numeric {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+				s.numeric = false
+				continue
+			}
+			s.sum += f
+			s.numbers++
+		}
+	}
+	return nil
+}
+
+// Finish has nothing to report.
+func (c *Collector) Finish() []validator.Finding {
+	return nil
+}
+
+// Profile returns the profile of the rows seen so far.
+func (c *Collector) Profile() *Profile {
+	p := &Profile{Version: Version, Rows: c.rows, Columns: make([]Column, len(c.headers))}
+	for i, h := range c.headers {
+		s := c.columns[i]
+		col := Column{Name: h, Distinct: min(len(s.distinct), maxDistinct), DistinctCapped: s.capped}
+		if c.rows > 0 {
+			col.NullRate = float64(s.empty) / float64(c.rows)
+		}
+		if s.numeric && s.numbers > 0 {
+			mean := s.sum / float64(s.numbers)
+			col.Mean = &mean
+		}
+		p.Columns[i] = col
+	}
+	return p
+}
+
+// Thresholds are the largest deviations from a baseline that are not drift.
+type Thresholds struct {
+	NullRate float64 // Change of the null rate in percentage points divided by 100, e.g. 0.05
+	Mean     float64 // Change of the mean relative to the baseline, e.g. 0.2 for 20%
+	Distinct float64 // Change of the distinct count relative to the baseline
+}
+
+// DefaultThresholds apply to thresholds left at zero.
+var DefaultThresholds = Thresholds{NullRate: 0.05, Mean: 0.2, Distinct: 0.5}
+
+// withDefaults fills zero thresholds from DefaultThresholds.
+func (t Thresholds) withDefaults() Thresholds {
+	if t.NullRate == 0 {
+		t.NullRate = DefaultThresholds.NullRate
+	}
+	if t.Mean == 0 {
+		t.Mean = DefaultThresholds.Mean
+	}
+	if t.Distinct == 0 {
+		t.Distinct = DefaultThresholds.Distinct
+	}
+	return t
+}
+
+// Drift is a column that deviates from the baseline.
+type Drift struct {
+	Column  string
+	Message string
+}
+
+// Compare reports the columns of current that deviate from baseline beyond t, and the
+// baseline's columns missing from current. A current profile without rows has no
+// distribution to compare.
+func Compare(baseline, current *Profile, t Thresholds) []Drift {
+	t = t.withDefaults()
+	byName := make(map[string]Column, len(current.Columns))
+	for _, c := range current.Columns {
+		byName[c.Name] = c
+	}
+	var out []Drift
+	for _, b := range baseline.Columns {
+		c, ok := byName[b.Name]
+		if !ok {
+			out = append(out, Drift{b.Name, fmt.Sprintf("column '%s' from the profile is missing", b.Name)})
+			continue
+		}
+		if current.Rows == 0 {
+			continue
+		}
+		if math.Abs(c.NullRate-b.NullRate) > t.NullRate {
+			out = append(out, Drift{b.Name, fmt.Sprintf("null rate of %s drifted from %s to %s (threshold %s points)",
+				b.Name, percent(b.NullRate), percent(c.NullRate), number(t.NullRate*100))})
+		}
+		if b.Mean != nil && c.Mean != nil {
+			if msg, drifted := relative(*b.Mean, *c.Mean, t.Mean); drifted {
+				out = append(out, Drift{b.Name, fmt.Sprintf("mean of %s drifted from %s to %s (%s)",
+					b.Name, number(*b.Mean), number(*c.Mean), msg)})
+			}
+		}
+		if !b.DistinctCapped && !c.DistinctCapped {
+			if msg, drifted := relative(float64(b.Distinct), float64(c.Distinct), t.Distinct); drifted {
+				out = append(out, Drift{b.Name, fmt.Sprintf("distinct values of %s drifted from %d to %d (%s)",
+					b.Name, b.Distinct, c.Distinct, msg)})
+			}
+		}
+	}
+	return out
+}
+
+// relative reports whether current differs from baseline by more than threshold, relative
+// to baseline, and describes the change.
+func relative(baseline, current, threshold float64) (string, bool) {
+	limit := "threshold " + percent(threshold)
+	if baseline == 0 {
+		return limit, current != 0
+	}
+	change := (current - baseline) / math.Abs(baseline)
+	sign := ""
+	if change > 0 {
+		sign = "+"
+	}
+	return fmt.Sprintf("%s%s, %s", sign, percent(change), limit), math.Abs(change) > threshold
+}
+
+// percent formats a share as a percentage with at most one decimal.
+func percent(f float64) string {
+	return number(math.Round(f*1000)/10) + "%"
+}
+
+// number formats a float without exponent, rounded to at most three decimals.
+func number(f float64) string {
+	return strconv.FormatFloat(math.Round(f*1000)/1000, 'f', -1, 64)
+}
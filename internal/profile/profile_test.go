@@ -0,0 +1,96 @@
+package profile
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+func profileOf(t *testing.T, input string) *Profile {
+	t.Helper()
+	c := NewCollector()
+	if _, err := validator.NewWithOptions(strings.NewReader(input), validator.Options{
+		Delimiter: ",",
+		Checks:    []validator.Check{c},
+	}).Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	return c.Profile()
+}
+
+func TestCollector(t *testing.T) {
+	// The short row is not part of the profile
+	p := profileOf(t, "id,email,amount\n1,a@x,10\n2,,12\n3,c@x,n/a\n4,a@x, 14 \n5\n")
+	if p.Version != Version || p.Rows != 4 || len(p.Columns) != 3 {
+		t.Fatalf("Expected 4 rows and 3 columns, got %+v", p)
+	}
+	id, email, amount := p.Columns[0], p.Columns[1], p.Columns[2]
+	if id.Mean == nil || *id.Mean != 2.5 || id.Distinct != 4 || id.NullRate != 0 {
+		t.Errorf("Unexpected id column %+v", id)
+	}
+	if email.Mean != nil || email.Distinct != 2 || email.NullRate != 0.25 {
+		t.Errorf("Unexpected email column %+v", email)
+	}
+	if amount.Mean != nil {
+		t.Errorf("Expected no mean for a column with text, got %v", *amount.Mean)
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.profile.json")
+	p := profileOf(t, "a,b\n1,x\n2,y\n")
+	if err := p.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if loaded.Rows != 2 || len(loaded.Columns) != 2 || *loaded.Columns[0].Mean != 1.5 || loaded.Columns[1].Mean != nil {
+		t.Errorf("Expected the saved profile back, got %+v", loaded)
+	}
+
+	p.Version = 2
+	_ = p.Save(path)
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "profile_version 2") {
+		t.Errorf("Expected an error for another profile version, got %v", err)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	baseline := profileOf(t, "id,email,amount,country\n1,a@x,10,US\n2,b@x,12,US\n3,,11,DE\n4,d@x,9,US\n")
+
+	if d := Compare(baseline, baseline, Thresholds{}); len(d) != 0 {
+		t.Errorf("Expected no drift against itself, got %+v", d)
+	}
+
+	current := profileOf(t, "id,email,amount,country\n1,,10000,US\n2,,12000,FR\n3,,11000,DE\n4,d@x,9000,IT\n5,e@x,9000,ES\n")
+	drift := Compare(baseline, current, Thresholds{})
+	want := []string{
+		"null rate of email drifted from 25% to 60% (threshold 5 points)",
+		"mean of amount drifted from 10.5 to 10200 (+97042.9%, threshold 20%)",
+		"distinct values of country drifted from 2 to 5 (+150%, threshold 50%)",
+	}
+	if len(drift) != len(want) {
+		t.Fatalf("Expected %d drifts, got %+v", len(want), drift)
+	}
+	for i, w := range want {
+		if drift[i].Message != w {
+			t.Errorf("Drift %d: expected %q, got %q", i, w, drift[i].Message)
+		}
+	}
+
+	// Looser thresholds, and a missing column
+	current = profileOf(t, "id,email,amount\n1,,10000\n2,,12000\n3,,11000\n4,d@x,9000\n5,e@x,9000\n")
+	drift = Compare(baseline, current, Thresholds{NullRate: 0.5, Mean: 1000})
+	if len(drift) != 1 || drift[0].Column != "country" || drift[0].Message != "column 'country' from the profile is missing" {
+		t.Errorf("Expected only the missing column, got %+v", drift)
+	}
+
+	// Without rows there is no distribution to compare
+	if d := Compare(baseline, profileOf(t, "id,email,amount,country\n"), Thresholds{}); len(d) != 0 {
+		t.Errorf("Expected no drift for an empty file, got %+v", d)
+	}
+}
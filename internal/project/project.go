@@ -0,0 +1,31 @@
+// Package project finds project roots, where the upward searches for config files, ignore
+// files and schemas stop.
+package project
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// DefaultRootMarkers are the files and directories marking a project root when none are
+// configured.
+var DefaultRootMarkers = []string{".git", "package.json"}
+
+// Markers returns markers, or DefaultRootMarkers when it is empty.
+func Markers(markers []string) []string {
+	if len(markers) == 0 {
+		return DefaultRootMarkers
+	}
+	return markers
+}
+
+// IsRoot reports whether dir holds one of markers, file or directory, or of
+// DefaultRootMarkers when markers is empty.
+func IsRoot(dir string, markers []string) bool {
+	for _, marker := range Markers(markers) {
+		if _, err := os.Stat(filepath.Join(dir, marker)); err == nil {
+			return true
+		}
+	}
+	return false
+}
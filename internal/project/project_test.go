@@ -0,0 +1,31 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsRoot(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module x\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sub := filepath.Join(dir, "sub")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if !IsRoot(dir, nil) {
+		t.Error("Expected a .git directory to mark the root by default")
+	}
+	if !IsRoot(dir, []string{"go.mod"}) || IsRoot(dir, []string{"pyproject.toml"}) {
+		t.Error("Expected configured markers to replace the defaults")
+	}
+	if IsRoot(sub, nil) {
+		t.Error("Expected a directory without markers not to be a root")
+	}
+}
@@ -0,0 +1,160 @@
+// Package redact hides cell values in validation results so reports can be shared (CI logs,
+// uploaded reports) without leaking the data being validated.
+package redact
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+const (
+	ModeMask = "mask" // Keep a short prefix and the length: "jo*** (16 chars)"
+	ModeHash = "hash" // Replace with a short SHA-256 digest and the length, so equal values stay recognizable
+)
+
+// prefixMinLength is the shortest value whose prefix is kept by ModeMask; shorter values
+// (codes, small numbers) would be mostly revealed by it.
+const prefixMinLength = 8
+
+// Redactor rewrites values in results.
+type Redactor struct {
+	mode    string
+	columns map[string]bool // nil redacts every column
+}
+
+// New returns a Redactor for mode (ModeMask when empty). When columns is non-empty only
+// values of those columns are redacted.
+func New(mode string, columns []string) (*Redactor, error) {
+	if mode == "" {
+		mode = ModeMask
+	}
+	if mode != ModeMask && mode != ModeHash {
+		return nil, fmt.Errorf("redaction mode must be '%s' or '%s', got '%s'", ModeMask, ModeHash, mode)
+	}
+	r := &Redactor{mode: mode}
+	if len(columns) > 0 {
+		r.columns = make(map[string]bool, len(columns))
+		for _, c := range columns {
+			r.columns[c] = true
+		}
+	}
+	return r, nil
+}
+
+// Value returns the redacted form of v.
+func (r *Redactor) Value(v string) string {
+	n := utf8.RuneCountInString(v)
+	switch r.mode {
+	case ModeHash:
+		sum := sha256.Sum256([]byte(v))
+		return fmt.Sprintf("sha256:%s (%d chars)", hex.EncodeToString(sum[:6]), n)
+	default:
+		prefix := ""
+		if n >= prefixMinLength {
+			prefix = string([]rune(v)[:2])
+		}
+		return fmt.Sprintf("%s*** (%d chars)", prefix, n)
+	}
+}
+
+// Results redacts the values of errors and warnings, their columns and keys, samples and
+// context rows in place, including copies of the value embedded in messages, and drops
+// the fixes of findings whose value it redacts.
+func (r *Redactor) Results(results *validator.Results) {
+	for _, list := range [][]validator.Finding{results.Errors, results.Warnings} {
+		for i := range list {
+			f := &list[i]
+			value := f.Value
+			f.Message, f.Value = r.finding(f.Field, f.Message, f.Value)
+			if f.Value != value {
+				// The fix would give the value away
+				f.Fix = nil
+			}
+			for column, value := range f.Columns {
+				_, f.Columns[column] = r.finding(column, "", value)
+			}
+			if f.Key != "" && r.redactsKey(results.KeyColumns) {
+				f.Key = r.Value(f.Key)
+			}
+		}
+	}
+	for _, list := range results.Samples {
+		for _, sample := range list {
+			for column, value := range sample.Values {
+				_, sample.Values[column] = r.finding(column, "", value)
+			}
+		}
+	}
+	if c := results.Context; c != nil {
+		for _, fields := range c.Rows {
+			for i, value := range fields {
+				_, fields[i] = r.finding(c.Column(i), "", value)
+			}
+		}
+	}
+}
+
+// redactsKey reports whether the values of a key of columns are redacted: all values are,
+// or one of its columns is.
+func (r *Redactor) redactsKey(columns []string) bool {
+	if r.columns == nil {
+		return true
+	}
+	for _, c := range columns {
+		if r.columns[c] {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Redactor) finding(field, message, value string) (string, string) {
+	if value == "" || (r.columns != nil && !r.columns[field]) {
+		return message, value
+	}
+	redacted := r.Value(value)
+	return redactIn(message, value, redacted), redacted
+}
+
+// redactIn replaces value in message with redacted where the message quotes it, as
+// 'value', "value" or %q would. A message that does not quote it gets its first occurrence
+// as a whole word replaced, so a short value such as "1" hits neither parts of words nor
+// the line numbers that follow it.
+func redactIn(message, value, redacted string) string {
+	replaced := message
+	for _, q := range []string{"'", `"`} {
+		replaced = strings.ReplaceAll(replaced, q+value+q, q+redacted+q)
+	}
+	if quoted := strconv.Quote(value); quoted != `"`+value+`"` {
+		replaced = strings.ReplaceAll(replaced, quoted, strconv.Quote(redacted))
+	}
+	if replaced != message {
+		return replaced
+	}
+	for from := 0; ; {
+		i := strings.Index(message[from:], value)
+		if i < 0 {
+			return message
+		}
+		start, end := from+i, from+i+len(value)
+		before, _ := utf8.DecodeLastRuneInString(message[:start])
+		after, _ := utf8.DecodeRuneInString(message[end:])
+		if !isWordRune(before) && !isWordRune(after) {
+			return message[:start] + redacted + message[end:]
+		}
+		from = start + 1
+	}
+}
+
+// isWordRune reports whether r is part of a word or number; utf8.RuneError, as decoded
+// at either end of a string, is not.
+func isWordRune(r rune) bool {
+	return r != utf8.RuneError && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
@@ -0,0 +1,152 @@
+package redact
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+func TestValue(t *testing.T) {
+	mask, _ := New("", nil)
+	hash, _ := New(ModeHash, nil)
+
+	cases := []struct {
+		r    *Redactor
+		in   string
+		want string
+	}{
+		{mask, "john@example.com", "jo*** (16 chars)"},
+		{mask, "1234", "*** (4 chars)"},
+		{mask, "ünïcødé!", "ün*** (8 chars)"},
+		{hash, "1234", "sha256:03ac674216f3 (4 chars)"},
+	}
+	for _, tc := range cases {
+		if got := tc.r.Value(tc.in); got != tc.want {
+			t.Errorf("Value(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestNewInvalidMode(t *testing.T) {
+	if _, err := New("scramble", nil); err == nil {
+		t.Error("Expected an error for an unknown mode")
+	}
+}
+
+func TestResults(t *testing.T) {
+	results := &validator.Results{
+		Errors: []validator.Finding{
+			{Severity: validator.SeverityError, Field: "email", Value: "not-an-email", Message: "'not-an-email' is not valid 'email'", Type: "schema"},
+			{Severity: validator.SeverityError, Field: "age", Value: "abc", Message: "expected integer, got abc", Type: "schema"},
+			{Severity: validator.SeverityError, Message: "wrong number of fields", Type: "structure"},
+		},
+		Warnings: []validator.Finding{{Severity: validator.SeverityWarning, Field: "email", Value: "x@y", Message: "suspicious x@y"}},
+	}
+	r, _ := New(ModeMask, []string{"email"})
+	r.Results(results)
+
+	email := results.Errors[0]
+	if strings.Contains(email.Value, "not-an-email") || strings.Contains(email.Message, "not-an-email") {
+		t.Errorf("Expected email value redacted everywhere, got %+v", email)
+	}
+	if email.Message != "'no*** (12 chars)' is not valid 'email'" {
+		t.Errorf("Unexpected message: %q", email.Message)
+	}
+	if results.Errors[1].Value != "abc" {
+		t.Errorf("Expected columns outside the list to be kept, got %+v", results.Errors[1])
+	}
+	if results.Errors[2].Message != "wrong number of fields" {
+		t.Errorf("Expected findings without a value untouched")
+	}
+	if results.Warnings[0].Value != "*** (3 chars)" || results.Warnings[0].Message != "suspicious *** (3 chars)" {
+		t.Errorf("Expected warning redacted, got %+v", results.Warnings[0])
+	}
+}
+
+func TestResultsShortValue(t *testing.T) {
+	results := &validator.Results{
+		Errors: []validator.Finding{
+			{Severity: validator.SeverityError, Field: "id", Value: "x", Message: "expected integer, but got string", Type: "schema"},
+			{Severity: validator.SeverityError, Field: "email", Value: "a", Message: "'a' is not valid 'email'", Type: "schema"},
+			{Severity: validator.SeverityError, Field: "id", Value: "1", Message: "id 1 repeats line 1", Type: "data"},
+			{Severity: validator.SeverityError, Field: "name", Value: "é", Message: `contains "é" twice`, Type: "data"},
+		},
+	}
+	r, _ := New(ModeMask, nil)
+	r.Results(results)
+
+	want := []string{
+		"expected integer, but got string",
+		"'*** (1 chars)' is not valid 'email'",
+		"id *** (1 chars) repeats line 1",
+		`contains "*** (1 chars)" twice`,
+	}
+	for i, w := range want {
+		if got := results.Errors[i].Message; got != w {
+			t.Errorf("finding %d: want message %q, got %q", i, w, got)
+		}
+		if results.Errors[i].Value != "*** (1 chars)" {
+			t.Errorf("finding %d: want the value redacted, got %q", i, results.Errors[i].Value)
+		}
+	}
+}
+
+func TestResultsSamples(t *testing.T) {
+	results := &validator.Results{
+		Samples: map[string][]validator.Sample{
+			"SCH001": {{LineNumber: 2, Values: map[string]string{"email": "john@example.com", "id": "7"}}},
+		},
+	}
+	r, _ := New(ModeMask, []string{"email"})
+	r.Results(results)
+	values := results.Samples["SCH001"][0].Values
+	if values["email"] != "jo*** (16 chars)" || values["id"] != "7" {
+		t.Errorf("Expected only the email sample value redacted, got %v", values)
+	}
+}
+
+func TestResultsColumns(t *testing.T) {
+	results := &validator.Results{Errors: []validator.Finding{
+		{Field: "id", Message: "bad id", Columns: map[string]string{"email": "john@example.com", "id": "7"}},
+	}}
+	r, _ := New(ModeMask, []string{"email"})
+	r.Results(results)
+	if got := results.Errors[0].Columns; got["email"] != "jo*** (16 chars)" || got["id"] != "7" {
+		t.Errorf("Expected only the email column value redacted, got %v", got)
+	}
+}
+
+func TestResultsKey(t *testing.T) {
+	results := &validator.Results{
+		KeyColumns: []string{"email", "id"},
+		Errors:     []validator.Finding{{Field: "id", Message: "bad id", Key: "(john@example.com, 7)"}},
+	}
+	r, _ := New(ModeMask, []string{"id"})
+	r.Results(results)
+	if got := results.Errors[0].Key; got != "(j*** (21 chars)" {
+		t.Errorf("Expected the key redacted with one of its columns, got %q", got)
+	}
+
+	results.KeyColumns, results.Errors[0].Key = []string{"id"}, "7"
+	r, _ = New(ModeMask, []string{"email"})
+	r.Results(results)
+	if got := results.Errors[0].Key; got != "7" {
+		t.Errorf("Expected the key of other columns kept, got %q", got)
+	}
+}
+
+func TestResultsContext(t *testing.T) {
+	results := &validator.Results{
+		Context: &validator.RowContext{
+			Header:   []string{"id", "email"},
+			Rows:     map[int][]string{2: {"7", "john@example.com", "extra"}},
+			Snippets: map[int][]int{2: {2}},
+		},
+	}
+	r, _ := New(ModeMask, []string{"email"})
+	r.Results(results)
+	if got := results.Context.Rows[2]; got[0] != "7" || got[1] != "jo*** (16 chars)" || got[2] != "extra" {
+		t.Errorf("Expected only the email context value redacted, got %v", got)
+	}
+}
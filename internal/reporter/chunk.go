@@ -0,0 +1,135 @@
+package reporter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+// IndexFile is the name of the index written to the directory of a chunked report.
+const IndexFile = "index.json"
+
+// chunkIndex is the index of a chunked report: the batch totals, a summary of each file
+// without its findings, and the parts holding the findings.
+type chunkIndex struct {
+	Version   string        `json:"report_schema_version"`
+	ChunkSize int           `json:"chunk_size"`
+	Files     []fileSummary `json:"files"` // Shadows Batch.Files
+	*validator.Batch
+	Parts []chunkPart `json:"parts"`
+}
+
+// chunkPart describes one part file of a chunked report.
+type chunkPart struct {
+	Path     string `json:"path"` // Relative to the index
+	Errors   int    `json:"errors"`
+	Warnings int    `json:"warnings"`
+}
+
+// fileSummary is a file's results without its findings, which are in the parts.
+type fileSummary struct {
+	*validator.Results
+	// Shadow the embedded lists so the summary omits them
+	Errors   []validator.Finding `json:"errors,omitempty"`
+	Warnings []validator.Finding `json:"warnings,omitempty"`
+
+	ErrorCount   int `json:"error_count"`
+	WarningCount int `json:"warning_count"`
+}
+
+// partDocument is the content of a part file: the findings of consecutive files, in
+// report order. A file's findings can continue in the next part.
+type partDocument struct {
+	Version string         `json:"report_schema_version"`
+	Part    int            `json:"part"` // 1-based
+	Files   []fileFindings `json:"files"`
+}
+
+type fileFindings struct {
+	File     string              `json:"file"`
+	Errors   []validator.Finding `json:"errors"`
+	Warnings []validator.Finding `json:"warnings"`
+}
+
+// writeChunks writes batch as a JSON report split into parts of at most size findings,
+// plus an index, to dir. Findings keep report order: a file's errors, then its warnings.
+func writeChunks(dir string, size int, batch *validator.Batch) error {
+	if size <= 0 {
+		return fmt.Errorf("chunk size must be positive, got %d", size)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	index := chunkIndex{Version: SchemaVersion, ChunkSize: size, Parts: []chunkPart{}, Files: []fileSummary{}, Batch: batch}
+	var part *partDocument
+	count := 0
+	flush := func() error {
+		if part == nil {
+			return nil
+		}
+		name := fmt.Sprintf("part-%05d.json", part.Part)
+		p := chunkPart{Path: name}
+		for _, f := range part.Files {
+			p.Errors += len(f.Errors)
+			p.Warnings += len(f.Warnings)
+		}
+		if err := writeJSONFile(filepath.Join(dir, name), part); err != nil {
+			return err
+		}
+		index.Parts = append(index.Parts, p)
+		part, count = nil, 0
+		return nil
+	}
+	// current returns the findings of file in the open part, opening a part if needed
+	current := func(file string) (*fileFindings, error) {
+		if count == size {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+		if part == nil {
+			part = &partDocument{Version: SchemaVersion, Part: len(index.Parts) + 1}
+		}
+		if n := len(part.Files); n == 0 || part.Files[n-1].File != file {
+			part.Files = append(part.Files, fileFindings{File: file, Errors: []validator.Finding{}, Warnings: []validator.Finding{}})
+		}
+		count++
+		return &part.Files[len(part.Files)-1], nil
+	}
+
+	for _, r := range batch.Files {
+		index.Files = append(index.Files, fileSummary{Results: r, ErrorCount: r.ErrorCount(), WarningCount: r.WarningCount()})
+		for _, e := range r.Errors {
+			f, err := current(r.File)
+			if err != nil {
+				return err
+			}
+			f.Errors = append(f.Errors, e)
+		}
+		for _, w := range r.Warnings {
+			f, err := current(r.File)
+			if err != nil {
+				return err
+			}
+			f.Warnings = append(f.Warnings, w)
+		}
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+	return writeJSONFile(filepath.Join(dir, IndexFile), index)
+}
+
+func writeJSONFile(path string, v any) error {
+	output, err := marshalJSON(v)
+	if err != nil {
+		return fmt.Errorf("failed to format output: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(output), 0644); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	return nil
+}
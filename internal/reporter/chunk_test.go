@@ -0,0 +1,135 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+func TestReporterChunks(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "reports")
+	a := &validator.Results{
+		File:      "a.csv",
+		TotalRows: 3,
+		Errors: []validator.Finding{
+			{Severity: validator.SeverityError, Location: validator.Location{Line: 2}, Message: "one", Type: "structure", RuleID: "STR001"},
+			{Severity: validator.SeverityError, Location: validator.Location{Line: 3}, Message: "two", Type: "structure", RuleID: "STR001"},
+			{Severity: validator.SeverityError, Location: validator.Location{Line: 4}, Message: "three", Type: "structure", RuleID: "STR001"},
+		},
+		Warnings: []validator.Finding{{Severity: validator.SeverityWarning, Location: validator.Location{Line: 1}, Message: "warn", Type: "manifest", RuleID: "MAN004"}},
+	}
+	b := &validator.Results{File: "b.csv", TotalRows: 1, Errors: []validator.Finding{{Severity: validator.SeverityError, Location: validator.Location{Line: 2}, Message: "four", Type: "data", RuleID: "DAT001"}}}
+	c := &validator.Results{File: "c.csv", TotalRows: 1, Valid: true}
+	batch := validator.NewBatch([]*validator.Results{c, b, a}, 0)
+
+	var stdout bytes.Buffer
+	r := NewWithDestinations(Destination{Format: "json", Dir: dir, ChunkSize: 2})
+	if err := r.ReportBatch(batch, &stdout); err != nil {
+		t.Fatalf("ReportBatch failed: %v", err)
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("chunked report also went to the writer:\n%s", stdout.String())
+	}
+
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource("report.schema.json", bytes.NewReader(ReportSchema())); err != nil {
+		t.Fatal(err)
+	}
+	s := compiler.MustCompile("report.schema.json")
+	read := func(name string, v any) {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("missing %s: %v", name, err)
+		}
+		var doc interface{}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			t.Fatalf("%s is not JSON: %v", name, err)
+		}
+		if err := s.Validate(doc); err != nil {
+			t.Errorf("%s does not match the report schema: %v\n%s", name, err, data)
+		}
+		if err := json.Unmarshal(data, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var index struct {
+		ChunkSize   int `json:"chunk_size"`
+		TotalErrors int `json:"total_errors"`
+		Files       []struct {
+			File       string              `json:"file"`
+			Errors     []validator.Finding `json:"errors"`
+			ErrorCount int                 `json:"error_count"`
+		} `json:"files"`
+		Parts []chunkPart `json:"parts"`
+	}
+	read(IndexFile, &index)
+	if index.ChunkSize != 2 || index.TotalErrors != 4 || len(index.Files) != 3 {
+		t.Fatalf("unexpected index: %+v", index)
+	}
+	if index.Files[0].File != "a.csv" || index.Files[0].ErrorCount != 3 || index.Files[0].Errors != nil {
+		t.Errorf("want a summary of a.csv without its findings first, got %+v", index.Files[0])
+	}
+	// Five findings in parts of two; a.csv's warning follows its errors
+	want := []chunkPart{{"part-00001.json", 2, 0}, {"part-00002.json", 1, 1}, {"part-00003.json", 1, 0}}
+	if len(index.Parts) != len(want) {
+		t.Fatalf("want parts %v, got %v", want, index.Parts)
+	}
+	for i := range want {
+		if index.Parts[i] != want[i] {
+			t.Errorf("part %d: want %v, got %v", i+1, want[i], index.Parts[i])
+		}
+	}
+
+	var part partDocument
+	read("part-00002.json", &part)
+	if part.Part != 2 || len(part.Files) != 1 || part.Files[0].File != "a.csv" ||
+		part.Files[0].Errors[0].Message != "three" || part.Files[0].Warnings[0].Message != "warn" {
+		t.Errorf("unexpected second part: %+v", part)
+	}
+	part = partDocument{}
+	read("part-00003.json", &part)
+	if len(part.Files) != 1 || part.Files[0].File != "b.csv" || len(part.Files[0].Warnings) != 0 {
+		t.Errorf("unexpected third part: %+v", part)
+	}
+
+	if err := NewWithDestinations(Destination{Format: "pretty", Dir: dir, ChunkSize: 2}).ReportBatch(batch, &stdout); err == nil {
+		t.Error("want an error for a chunked pretty report")
+	}
+}
+
+func TestReporterChunksDeduped(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "reports")
+	a := &validator.Results{
+		File:     "a.csv",
+		Errors:   []validator.Finding{{Severity: validator.SeverityError, Location: validator.Location{Line: 2}, Message: "too few fields", Type: "structure", RuleID: "STR001", Occurrences: 3, LastLine: 4}},
+		Warnings: []validator.Finding{{Severity: validator.SeverityWarning, Location: validator.Location{Line: 1}, Message: "warn", Type: "manifest", RuleID: "MAN004", Occurrences: 2}},
+	}
+	if err := NewWithDestinations(Destination{Format: "json", Dir: dir, ChunkSize: 10}).ReportBatch(validator.NewBatch([]*validator.Results{a}, 0), &bytes.Buffer{}); err != nil {
+		t.Fatalf("ReportBatch failed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, IndexFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var index struct {
+		TotalErrors int `json:"total_errors"`
+		Files       []struct {
+			ErrorCount   int `json:"error_count"`
+			WarningCount int `json:"warning_count"`
+		} `json:"files"`
+	}
+	if err := json.Unmarshal(data, &index); err != nil {
+		t.Fatal(err)
+	}
+	if index.TotalErrors != 3 || len(index.Files) != 1 || index.Files[0].ErrorCount != 3 || index.Files[0].WarningCount != 2 {
+		t.Errorf("want the file's counts to match the totals, got %s", data)
+	}
+}
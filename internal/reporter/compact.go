@@ -0,0 +1,86 @@
+package reporter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+// Compact lines are laid out as "<file>:<line>[:<column>] <severity>[ <rule>] <message>".
+// Editor integrations (csvlinter integration) are generated from the values below, so
+// change them together with writeCompactLine.
+const (
+	CompactError   = "error"
+	CompactWarning = "warning"
+)
+
+// CompactPattern matches one compact line. Submatches: 1 file, 2 line, 3 column (may be
+// empty), 4 severity, 5 rule (may be empty), 6 message. It is valid in both Go and
+// JavaScript, for VS Code problem matchers.
+const CompactPattern = `^(.*?):(\d+)(?::(\d+))? (` + CompactError + `|` + CompactWarning + `)(?: ([A-Z]+\d+))? (.*)$`
+
+// CompactVimErrorformat returns a Vim 'errorformat' for compact lines, with and without
+// a column. %t takes the severity from its first letter.
+func CompactVimErrorformat() string {
+	var formats []string
+	for _, severity := range []string{CompactError, CompactWarning} {
+		rest := severity[1:]
+		formats = append(formats,
+			"%f:%l:%c %t"+rest+" %m",
+			"%f:%l %t"+rest+" %m",
+		)
+	}
+	return strings.Join(formats, ",")
+}
+
+// formatCompact renders one line per finding, which grep and editor quickfix lists
+// understand. Valid results produce no output.
+func formatCompact(results *validator.Results, t Theme, color bool) string {
+	var sb strings.Builder
+	if !color {
+		t.Error, t.Warning = "", ""
+	}
+	for _, e := range results.Errors {
+		writeCompactLine(&sb, results.File, e.Line, e.Column, CompactError, e.RuleID, e.Field, withRollup(withSchema(e.Message, e.Schema), e.LastLine, e.Occurrences), t.Error)
+	}
+	for _, w := range results.Warnings {
+		writeCompactLine(&sb, results.File, w.Line, w.Column, CompactWarning, w.RuleID, w.Field, withRollup(withSchema(w.Message, w.Schema), w.LastLine, w.Occurrences), t.Warning)
+	}
+	return sb.String()
+}
+
+// withSchema appends the schema label of a finding from a multi-schema run.
+func withSchema(message, schema string) string {
+	if schema == "" {
+		return message
+	}
+	return message + " (" + schema + ")"
+}
+
+// withRollup appends how many findings were rolled up into one by Dedupe, and the line
+// of the last; the line of the first starts the compact line.
+func withRollup(message string, last, occurrences int) string {
+	if occurrences == 0 {
+		return message
+	}
+	return fmt.Sprintf("%s (%d times, through line %d)", message, occurrences, last)
+}
+
+// writeCompactLine writes a finding, its severity in the SGR color code ("" for none).
+func writeCompactLine(sb *strings.Builder, file string, line, column int, severity, rule, field, message, code string) {
+	sb.WriteString(fmt.Sprintf("%s:%d", file, line))
+	if column > 0 {
+		sb.WriteString(fmt.Sprintf(":%d", column))
+	}
+	sb.WriteString(" ")
+	paint(sb, code, severity, true)
+	if rule != "" {
+		sb.WriteString(" " + rule)
+	}
+	if field != "" && field != "row" {
+		sb.WriteString(" " + field + ":")
+	}
+	// Keep one finding per line whatever the message contains
+	sb.WriteString(" " + strings.ReplaceAll(message, "\n", " ") + "\n")
+}
@@ -0,0 +1,79 @@
+package reporter
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+func TestReporterCompact(t *testing.T) {
+	results := &validator.Results{
+		File: "data.csv",
+		Errors: []validator.Finding{
+			{Severity: validator.SeverityError, Location: validator.Location{Line: 42, Column: 3}, Field: "email", Message: "'x' is not valid 'email'", Type: "schema", RuleID: "SCH001"},
+			{Severity: validator.SeverityError, Location: validator.Location{Line: 43}, Field: "row", Message: "column count mismatch: expected 3, got 2", Type: "structure", RuleID: "STR001"},
+		},
+		Warnings: []validator.Finding{{Severity: validator.SeverityWarning, Location: validator.Location{Line: 44, Column: 1}, Message: "multi\nline", Type: "schema"}},
+	}
+	var buf bytes.Buffer
+	if err := New("compact", "").Report(results, &buf); err != nil {
+		t.Fatalf("Report failed: %v", err)
+	}
+	want := "data.csv:42:3 error SCH001 email: 'x' is not valid 'email'\n" +
+		"data.csv:43 error STR001 column count mismatch: expected 3, got 2\n" +
+		"data.csv:44:1 warning multi line\n"
+	if buf.String() != want {
+		t.Errorf("Unexpected compact output:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := New("compact", "").Report(&validator.Results{File: "ok.csv", Valid: true}, &buf); err != nil {
+		t.Fatalf("Report failed: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected no output for valid results, got %q", buf.String())
+	}
+}
+
+func TestCompactPatternMatchesOutput(t *testing.T) {
+	results := &validator.Results{
+		File: "dir/data.csv",
+		Errors: []validator.Finding{
+			{Severity: validator.SeverityError, Location: validator.Location{Line: 42, Column: 3}, Field: "email", Message: "'x' is not valid 'email'", RuleID: "SCH001"},
+			{Severity: validator.SeverityError, Location: validator.Location{Line: 7}, Message: "file could not be read"},
+		},
+		Warnings: []validator.Finding{{Severity: validator.SeverityWarning, Location: validator.Location{Line: 9, Column: 1}, Message: "odd", RuleID: "STR001"}},
+	}
+	want := [][]string{
+		{"dir/data.csv", "42", "3", "error", "SCH001", "email: 'x' is not valid 'email'"},
+		{"dir/data.csv", "7", "", "error", "", "file could not be read"},
+		{"dir/data.csv", "9", "1", "warning", "STR001", "odd"},
+	}
+
+	re := regexp.MustCompile(CompactPattern)
+	lines := bytes.Split(bytes.TrimSuffix([]byte(formatCompact(results, Themes[DefaultTheme], false)), []byte("\n")), []byte("\n"))
+	if len(lines) != len(want) {
+		t.Fatalf("Expected %d lines, got %d", len(want), len(lines))
+	}
+	for i, line := range lines {
+		m := re.FindStringSubmatch(string(line))
+		if m == nil {
+			t.Errorf("CompactPattern does not match %q", line)
+			continue
+		}
+		for g, w := range want[i] {
+			if m[g+1] != w {
+				t.Errorf("Line %q: group %d = %q, want %q", line, g+1, m[g+1], w)
+			}
+		}
+	}
+}
+
+func TestCompactVimErrorformat(t *testing.T) {
+	want := "%f:%l:%c %trror %m,%f:%l %trror %m,%f:%l:%c %tarning %m,%f:%l %tarning %m"
+	if got := CompactVimErrorformat(); got != want {
+		t.Errorf("CompactVimErrorformat() = %q, want %q", got, want)
+	}
+}
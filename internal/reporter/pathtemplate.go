@@ -0,0 +1,117 @@
+package reporter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+// PathName is what a Destination.Path template is executed with, once per validated file.
+type PathName struct {
+	Path   string // The file as reported, e.g. "data/orders.csv"
+	Dir    string // Its directory, "data"
+	Base   string // Its name, "orders.csv"
+	Name   string // Its name without the extension, "orders"
+	Ext    string // Its extension, ".csv"
+	Format string // The destination's format, e.g. "json"
+}
+
+// IsPathTemplate reports whether path is a template naming a report file per validated
+// file, such as "reports/{{.Name}}.json", rather than the path of one report.
+func IsPathTemplate(path string) bool {
+	return strings.Contains(path, "{{")
+}
+
+// CheckPath returns an error if path is a template that does not parse or execute.
+func CheckPath(path string) error {
+	if !IsPathTemplate(path) {
+		return nil
+	}
+	tmpl, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+	_, err = pathFor(tmpl, "data/orders.csv", "json")
+	return err
+}
+
+// parsePath parses a path template.
+func parsePath(path string) (*template.Template, error) {
+	tmpl, err := template.New("output").Option("missingkey=error").Parse(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid output path template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// pathFor executes tmpl for the report of file in format.
+func pathFor(tmpl *template.Template, file, format string) (string, error) {
+	base := filepath.Base(file)
+	ext := filepath.Ext(base)
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, PathName{
+		Path:   file,
+		Dir:    filepath.Dir(file),
+		Base:   base,
+		Name:   strings.TrimSuffix(base, ext),
+		Ext:    ext,
+		Format: format,
+	}); err != nil {
+		return "", fmt.Errorf("invalid output path template: %w", err)
+	}
+	if sb.Len() == 0 {
+		return "", fmt.Errorf("output path template gives an empty path for %s", file)
+	}
+	return sb.String(), nil
+}
+
+// writePerFile writes the report of each file of batch in dest's format to the file the
+// template dest.Path names for it, creating directories as needed. Two files given the
+// same report file are an error, rather than one report overwriting the other.
+func (r *Reporter) writePerFile(dest Destination, batch *validator.Batch) error {
+	tmpl, err := parsePath(dest.Path)
+	if err != nil {
+		return err
+	}
+	// Name every report first, so a clash leaves no report written
+	paths := make([]string, len(batch.Files))
+	files := make(map[string]string, len(batch.Files))
+	for i, results := range batch.Files {
+		if paths[i], err = pathFor(tmpl, results.File, dest.Format); err != nil {
+			return err
+		}
+		key := filepath.Clean(paths[i])
+		if other, ok := files[key]; ok {
+			return fmt.Errorf("the reports of %s and %s would both be written to %s; add more of the file's path to the output template, e.g. {{.Dir}}", other, results.File, paths[i])
+		}
+		files[key] = results.File
+	}
+	for i, results := range batch.Files {
+		if err := r.writeFileReport(paths[i], results, dest.Format); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeFileReport writes the report of one file in format to path.
+func (r *Reporter) writeFileReport(path string, results *validator.Results, format string) error {
+	// Files never receive ANSI colors, whatever the terminal is
+	output, err := r.format(results, format, false)
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to write output file: %w", err)
+		}
+	}
+	if err := os.WriteFile(path, []byte(output), 0644); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+func TestPathTemplate(t *testing.T) {
+	dir := t.TempDir()
+	batch := validator.NewBatch([]*validator.Results{
+		{File: "in/orders.csv", TotalRows: 2, Valid: true},
+		{File: "in/customers.tsv", TotalRows: 1, Errors: []validator.Finding{
+			{Severity: validator.SeverityError, Location: validator.Location{Line: 2}, Message: "bad", Type: "data", RuleID: "DAT001"},
+		}},
+	}, 0)
+
+	var stdout bytes.Buffer
+	r := New("json", filepath.Join(dir, "reports", "{{.Name}}{{.Ext}}.{{.Format}}"))
+	if err := r.ReportBatch(batch, &stdout); err != nil {
+		t.Fatalf("ReportBatch failed: %v", err)
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("per-file reports also went to the writer:\n%s", stdout.String())
+	}
+	for _, name := range []string{"orders.csv.json", "customers.tsv.json"} {
+		data, err := os.ReadFile(filepath.Join(dir, "reports", name))
+		var results validator.Results
+		if err != nil || json.Unmarshal(data, &results) != nil || !strings.HasPrefix(name, filepath.Base(results.File)) {
+			t.Errorf("%s: want the file's own report, got %s (%v)", name, data, err)
+		}
+	}
+
+	clash := New("json", filepath.Join(dir, "clash", "{{.Dir}}.json"))
+	if err := clash.ReportBatch(batch, &stdout); err == nil || !strings.Contains(err.Error(), "would both be written") {
+		t.Errorf("want an error for two reports in one file, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "clash")); !os.IsNotExist(err) {
+		t.Errorf("want no report written when names clash, got %v", err)
+	}
+
+	for path, ok := range map[string]bool{"out.json": true, "{{.Name}}.json": true, "{{.Nope}}.json": false, "{{.Name": false} {
+		if err := CheckPath(path); (err == nil) != ok {
+			t.Errorf("CheckPath(%q) = %v", path, err)
+		}
+	}
+}
@@ -1,187 +1,579 @@
 package reporter
 
 import (
+	_ "embed"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strings"
+	"unicode"
 
+	"github.com/csvlinter/csvlinter/internal/i18n"
 	"github.com/csvlinter/csvlinter/internal/validator"
 
 	"github.com/mattn/go-isatty"
 )
 
+// SchemaVersion is written as report_schema_version at the top of JSON reports, and
+// names report.schema.json. Its major version changes when fields are renamed, removed or
+// change meaning, its minor version when fields are added.
+const SchemaVersion = "1.5"
+
+// reportSchema is the JSON Schema of JSON reports at SchemaVersion.
+//
+//go:embed report.schema.json
+var reportSchema []byte
+
+// ReportSchema returns the JSON Schema describing JSON reports, operational error
+// documents included.
+func ReportSchema() []byte {
+	return reportSchema
+}
+
+// Formats lists the output formats the reporter can render.
+var Formats = []string{"pretty", "json", "compact", "sarif"}
+
+// IsSupported reports whether format is one of Formats.
+func IsSupported(format string) bool {
+	for _, f := range Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatList renders Formats for messages, e.g. "'pretty', 'json' or 'compact'".
+func FormatList() string {
+	quoted := make([]string, len(Formats))
+	for i, f := range Formats {
+		quoted[i] = "'" + f + "'"
+	}
+	if len(quoted) == 1 {
+		return quoted[0]
+	}
+	return strings.Join(quoted[:len(quoted)-1], ", ") + " or " + quoted[len(quoted)-1]
+}
+
+// Destination pairs an output format with where the rendered report is written.
+type Destination struct {
+	Format string // Output format, one of Formats
+	Path   string // Output file path, or a template naming one per validated file (see PathName); empty writes to the writer passed to Report
+	Tee    bool   // When Path or Dir is set, also write the report to the writer passed to Report
+
+	// Dir, when set instead of Path, receives a JSON report split into parts of at most
+	// ChunkSize findings and an index file listing them. Format must be "json".
+	Dir       string
+	ChunkSize int
+}
+
 // Reporter handles output formatting
 type Reporter struct {
-	format     string
-	outputPath string
-	isTerminal bool
+	destinations []Destination
+	theme        Theme
+	catalog      *i18n.Catalog
 }
 
-// New creates a new reporter
+// New creates a new reporter that renders a single format to outputPath, or to the
+// writer passed to Report when outputPath is empty.
 func New(format, outputPath string) *Reporter {
-	return &Reporter{
-		format:     format,
-		outputPath: outputPath,
-		isTerminal: isatty.IsTerminal(os.Stdout.Fd()),
-	}
+	return NewWithDestinations(Destination{Format: format, Path: outputPath})
 }
 
-// Report outputs the validation results
+// NewWithDestinations creates a reporter that renders the results once per destination,
+// so a run can e.g. save JSON to a file while printing pretty output to the terminal.
+func NewWithDestinations(destinations ...Destination) *Reporter {
+	return &Reporter{destinations: destinations, theme: Themes[DefaultTheme]}
+}
+
+// WithTheme sets the colors and symbols of pretty and compact output.
+func (r *Reporter) WithTheme(t Theme) *Reporter {
+	r.theme = t
+	return r
+}
+
+// WithCatalog renders pretty output in the catalog's language. JSON and compact output
+// stay in English.
+func (r *Reporter) WithCatalog(c *i18n.Catalog) *Reporter {
+	r.catalog = c
+	return r
+}
+
+// Report outputs the validation results to every destination. Destinations without a
+// Path, and destinations with Tee set, are written to writer (os.Stdout when nil).
 func (r *Reporter) Report(results *validator.Results, writer io.Writer) error {
 	if results == nil {
 		return fmt.Errorf("results cannot be nil")
 	}
+	return r.emit(writer, func(format string, color bool) (string, error) {
+		return r.format(results, format, color)
+	}, func() *validator.Batch {
+		batch := validator.NewBatch([]*validator.Results{results}, 0)
+		batch.Duration = results.Duration
+		return batch
+	})
+}
+
+// ReportBatch outputs a multi-file run to every destination: one section per file
+// followed by an overall summary.
+func (r *Reporter) ReportBatch(batch *validator.Batch, writer io.Writer) error {
+	if batch == nil {
+		return fmt.Errorf("results cannot be nil")
+	}
+	return r.emit(writer, func(format string, color bool) (string, error) {
+		return r.formatBatch(batch, format, color)
+	}, func() *validator.Batch { return batch })
+}
 
+// renderFunc renders a report in format; color enables ANSI escapes.
+type renderFunc func(format string, color bool) (string, error)
+
+// emit renders once per destination and writes the output where it belongs. batch
+// returns the run as a batch for chunked destinations.
+func (r *Reporter) emit(writer io.Writer, render renderFunc, batch func() *validator.Batch) error {
+	if writer == nil {
+		writer = os.Stdout
+	}
+	for _, dest := range r.destinations {
+		if err := r.write(dest, writer, render, batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// write renders a single destination.
+func (r *Reporter) write(dest Destination, writer io.Writer, render renderFunc, batch func() *validator.Batch) error {
+	if dest.Dir != "" {
+		if dest.Format != "json" {
+			return fmt.Errorf("chunked reports are only written in json format, not %s", dest.Format)
+		}
+		if err := writeChunks(dest.Dir, dest.ChunkSize, withFileLists(batch())); err != nil {
+			return err
+		}
+		if !dest.Tee {
+			return nil
+		}
+	} else if IsPathTemplate(dest.Path) {
+		if err := r.writePerFile(dest, batch()); err != nil {
+			return err
+		}
+		if !dest.Tee {
+			return nil
+		}
+	} else if dest.Path != "" {
+		// Files never receive ANSI colors, whatever the terminal is
+		output, err := render(dest.Format, false)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(dest.Path, []byte(output), 0644); err != nil {
+			return fmt.Errorf("failed to write output file: %w", err)
+		}
+		if !dest.Tee {
+			return nil
+		}
+	}
+
+	output, err := render(dest.Format, isTerminal(writer))
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprint(writer, output); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	return nil
+}
+
+// format renders results in the given format. color enables ANSI escapes in pretty output.
+func (r *Reporter) format(results *validator.Results, format string, color bool) (string, error) {
 	var output string
 	var err error
 
-	switch r.format {
+	switch format {
 	case "json":
-		output, err = r.formatJSON(results)
+		output, err = marshalJSON(struct {
+			Version string `json:"report_schema_version"`
+			*validator.Results
+		}{SchemaVersion, withLists(results)})
 	case "pretty":
-		output, err = r.formatPretty(results)
+		output, err = r.formatPretty(results, color)
+	case "compact":
+		output = formatCompact(results, r.theme, color)
+	case "sarif":
+		output, err = formatSARIF([]*validator.Results{results})
 	default:
-		return fmt.Errorf("unsupported format: %s", r.format)
+		return "", fmt.Errorf("unsupported format: %s", format)
 	}
 
 	if err != nil {
-		return fmt.Errorf("failed to format output: %w", err)
+		return "", fmt.Errorf("failed to format output: %w", err)
 	}
+	return output, nil
+}
 
-	if writer == nil {
-		writer = os.Stdout
-	}
+// formatBatch renders a multi-file run in the given format.
+func (r *Reporter) formatBatch(batch *validator.Batch, format string, color bool) (string, error) {
+	var output string
+	var err error
 
-	// Write to file or stdout
-	if r.outputPath != "" {
-		if err := os.WriteFile(r.outputPath, []byte(output), 0644); err != nil {
-			return fmt.Errorf("failed to write output file: %w", err)
-		}
-	} else {
-		if _, err := fmt.Fprint(writer, output); err != nil {
-			return fmt.Errorf("failed to write output: %w", err)
+	switch format {
+	case "json":
+		output, err = marshalJSON(struct {
+			Version string `json:"report_schema_version"`
+			*validator.Batch
+		}{SchemaVersion, withFileLists(batch)})
+	case "pretty":
+		output, err = r.formatPrettyBatch(batch, color)
+	case "compact":
+		var sb strings.Builder
+		for _, results := range batch.Files {
+			sb.WriteString(formatCompact(results, r.theme, color))
 		}
+		output = sb.String()
+	case "sarif":
+		output, err = formatSARIF(batch.Files)
+	default:
+		return "", fmt.Errorf("unsupported format: %s", format)
 	}
 
-	return nil
+	if err != nil {
+		return "", fmt.Errorf("failed to format output: %w", err)
+	}
+	return output, nil
+}
+
+// isTerminal reports whether w is a file attached to a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && isatty.IsTerminal(f.Fd())
+}
+
+// withLists returns results with empty error and warning lists instead of nil ones, which
+// the report schema requires as arrays.
+func withLists(results *validator.Results) *validator.Results {
+	if results.Errors != nil && results.Warnings != nil {
+		return results
+	}
+	r := *results
+	if r.Errors == nil {
+		r.Errors = []validator.Finding{}
+	}
+	if r.Warnings == nil {
+		r.Warnings = []validator.Finding{}
+	}
+	return &r
+}
+
+// withFileLists returns batch with withLists applied to each file.
+func withFileLists(batch *validator.Batch) *validator.Batch {
+	files := make([]*validator.Results, len(batch.Files))
+	for i, f := range batch.Files {
+		files[i] = withLists(f)
+	}
+	b := *batch
+	b.Files = files
+	return &b
 }
 
-// formatJSON formats results as JSON
-func (r *Reporter) formatJSON(results *validator.Results) (string, error) {
-	jsonBytes, err := json.MarshalIndent(results, "", "  ")
+// marshalJSON formats results as indented JSON. Fields keep their declaration order and
+// map keys are sorted, so equal results give identical reports.
+func marshalJSON(v any) (string, error) {
+	jsonBytes, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
 		return "", err
 	}
 	return string(jsonBytes) + "\n", nil
 }
 
+// contractSummary says which side of a producer/consumer contract rejects the file.
+func contractSummary(tr *i18n.Catalog, c *validator.Contract) string {
+	switch c.RejectedBy {
+	case "both":
+		return tr.Sprintf("producer (%d error(s)) and consumer (%d error(s)) both reject the file", c.ProducerErrors, c.ConsumerErrors)
+	case "producer":
+		return tr.Sprintf("producer rejects the file (%d error(s)); consumer accepts it", c.ProducerErrors)
+	case "consumer":
+		return tr.Sprintf("consumer rejects the file (%d error(s)); producer accepts it", c.ConsumerErrors)
+	}
+	return tr.Sprintf("producer and consumer both accept the file")
+}
+
 // formatPretty formats results for human reading
-func (r *Reporter) formatPretty(results *validator.Results) (string, error) {
+func (r *Reporter) formatPretty(results *validator.Results, color bool) (string, error) {
 	var sb strings.Builder
+	t, tr := r.theme, r.catalog
 
 	// Header
-	if r.isTerminal {
-		sb.WriteString("\033[1m") // Bold
-	}
-	sb.WriteString("CSV Validation Results\n")
-	sb.WriteString("=====================\n")
-	if r.isTerminal {
-		sb.WriteString("\033[0m") // Reset
-	}
+	paint(&sb, t.Heading, heading(tr.Sprintf("CSV Validation Results")), color)
 
 	// File info
-	sb.WriteString(fmt.Sprintf("File: %s\n", results.File))
-	sb.WriteString(fmt.Sprintf("Total Rows: %d\n", results.TotalRows))
-	sb.WriteString(fmt.Sprintf("Duration: %s\n", results.Duration))
-	sb.WriteString(fmt.Sprintf("Schema Used: %t\n", results.SchemaUsed))
+	sb.WriteString(tr.Sprintf("File: %s", results.File) + "\n")
+	sb.WriteString(tr.Sprintf("Total Rows: %d", results.TotalRows) + "\n")
+	if c := results.Coverage; c != nil {
+		sb.WriteString(tr.Sprintf("Coverage: partial, stopped after the %s time budget", c.TimeBudget))
+		if c.TotalBytes > 0 {
+			sb.WriteString(tr.Sprintf(" (%.1f%% of the input, ~%d rows in total)", c.Percent, c.EstimatedTotalRows))
+		}
+		sb.WriteString("\n")
+	} else if results.Partial {
+		sb.WriteString(tr.Sprintf("Coverage: partial, validation was interrupted") + "\n")
+	}
+	if results.Cached {
+		sb.WriteString(tr.Sprintf("Duration: %s (cached)", results.Duration) + "\n")
+	} else {
+		sb.WriteString(tr.Sprintf("Duration: %s", results.Duration) + "\n")
+	}
+	sb.WriteString(tr.Sprintf("Schema Used: %t", results.SchemaUsed) + "\n")
+	if results.Contract != nil {
+		sb.WriteString(tr.Sprintf("Contract: %s", contractSummary(tr, results.Contract)) + "\n")
+	}
+	if results.SHA256 != "" {
+		sb.WriteString(fmt.Sprintf("SHA-256: %s\n", results.SHA256))
+		sb.WriteString(tr.Sprintf("Fingerprint: %s", results.Fingerprint) + "\n")
+	}
 
 	// Status
-	sb.WriteString("\nStatus: ")
+	sb.WriteString("\n" + tr.Sprintf("Status: "))
 	if results.Valid {
-		if r.isTerminal {
-			sb.WriteString("\033[32m") // Green
-		}
-		sb.WriteString("✓ VALID\n")
-		if r.isTerminal {
-			sb.WriteString("\033[0m") // Reset
-		}
+		paint(&sb, t.Success, mark(t.Pass, tr.Sprintf("VALID"))+"\n", color)
 	} else {
-		if r.isTerminal {
-			sb.WriteString("\033[31m") // Red
-		}
-		sb.WriteString("✗ INVALID\n")
-		if r.isTerminal {
-			sb.WriteString("\033[0m") // Reset
-		}
+		paint(&sb, t.Error, mark(t.Fail, tr.Sprintf("INVALID"))+"\n", color)
 	}
 
 	// Errors
 	if len(results.Errors) > 0 {
-		sb.WriteString(fmt.Sprintf("\nErrors (%d):\n", len(results.Errors)))
+		sb.WriteString("\n" + tr.Sprintf("Errors (%s):", findingCount(tr, results.ErrorCount(), len(results.Errors))) + "\n")
 		for i, err := range results.Errors {
-			if r.isTerminal {
-				sb.WriteString("\033[31m") // Red
-			}
-			sb.WriteString(fmt.Sprintf("  %d. Line %d", i+1, err.LineNumber))
+			var line strings.Builder
+			line.WriteString(fmt.Sprintf("  %d. %s", i+1, lines(tr, err.Line, err.LastLine, err.Occurrences)))
 			if err.Field != "" {
-				sb.WriteString(fmt.Sprintf(" (%s)", err.Field))
+				line.WriteString(fmt.Sprintf(" (%s)", err.Field))
 			}
-			sb.WriteString(fmt.Sprintf(": %s", err.Message))
+			line.WriteString(": " + tr.Message(err.Message))
 			if err.Value != "" {
-				sb.WriteString(fmt.Sprintf(" (value: %q)", err.Value))
+				line.WriteString(" (" + tr.Sprintf("value: %q", err.Value) + ")")
 			}
-			sb.WriteString(fmt.Sprintf(" [%s]", err.Type))
-			sb.WriteString("\n")
-			if r.isTerminal {
-				sb.WriteString("\033[0m") // Reset
+			if err.Schema != "" {
+				line.WriteString(fmt.Sprintf(" [%s: %s]", err.Type, err.Schema))
+			} else {
+				line.WriteString(fmt.Sprintf(" [%s]", err.Type))
+			}
+			line.WriteString("\n")
+			paint(&sb, t.Error, line.String(), color)
+			writeSuggestion(&sb, tr, err.Suggestion)
+			writeKey(&sb, tr, results.KeyColumns, err.Key)
+			writeColumns(&sb, tr, err.Columns)
+			// Errors are sorted by line, so the line's context follows its last error
+			if i == len(results.Errors)-1 || results.Errors[i+1].Line != err.Line {
+				writeSnippet(&sb, results.Context, err.Line)
 			}
 		}
 	}
 
+	// Heatmap of the errors per column and rule
+	if b := results.Breakdown; b != nil {
+		writeCounts(&sb, tr.Sprintf("Errors by column"), b.Columns, t.Bar)
+		writeCounts(&sb, tr.Sprintf("Errors by rule"), b.Rules, t.Bar)
+	}
+
 	// Warnings
 	if len(results.Warnings) > 0 {
-		sb.WriteString(fmt.Sprintf("\nWarnings (%d):\n", len(results.Warnings)))
+		sb.WriteString("\n" + tr.Sprintf("Warnings (%s):", findingCount(tr, results.WarningCount(), len(results.Warnings))) + "\n")
 		for i, warning := range results.Warnings {
-			if r.isTerminal {
-				sb.WriteString("\033[33m") // Yellow
-			}
-			sb.WriteString(fmt.Sprintf("  %d. Line %d", i+1, warning.LineNumber))
+			var line strings.Builder
+			line.WriteString(fmt.Sprintf("  %d. %s", i+1, lines(tr, warning.Line, warning.LastLine, warning.Occurrences)))
 			if warning.Field != "" && warning.Field != "row" {
-				sb.WriteString(fmt.Sprintf(" (%s)", warning.Field))
+				line.WriteString(fmt.Sprintf(" (%s)", warning.Field))
 			}
-			sb.WriteString(fmt.Sprintf(": %s", warning.Message))
+			line.WriteString(": " + tr.Message(warning.Message))
 			if warning.Value != "" {
-				sb.WriteString(fmt.Sprintf(" (value: %q)", warning.Value))
-			}
-			sb.WriteString(fmt.Sprintf(" [%s]", warning.Type))
-			sb.WriteString("\n")
-			if r.isTerminal {
-				sb.WriteString("\033[0m") // Reset
+				line.WriteString(" (" + tr.Sprintf("value: %q", warning.Value) + ")")
 			}
+			line.WriteString(fmt.Sprintf(" [%s]", warning.Type))
+			line.WriteString("\n")
+			paint(&sb, t.Warning, line.String(), color)
+			writeSuggestion(&sb, tr, warning.Suggestion)
+			writeKey(&sb, tr, results.KeyColumns, warning.Key)
+			writeColumns(&sb, tr, warning.Columns)
+		}
+	}
+
+	// Where triage should start
+	if top := topIssues(results.Errors, topIssueCount); len(top) > 0 && results.ErrorCount() > 1 {
+		sb.WriteString("\n" + tr.Sprintf("Top issues:") + "\n")
+		for i, issue := range top {
+			sb.WriteString(fmt.Sprintf("  %d. %s: %s (%s)\n", i+1, issue.column, tr.Message(issue.message), tr.Sprintf("%d error(s)", issue.count)))
 		}
 	}
 
 	// Summary
 	sb.WriteString("\n")
 	if results.Valid {
-		if r.isTerminal {
-			sb.WriteString("\033[32m") // Green
-		}
-		sb.WriteString("✓ All validations passed!\n")
-		if r.isTerminal {
-			sb.WriteString("\033[0m") // Reset
-		}
+		paint(&sb, t.Success, mark(t.Pass, tr.Sprintf("All validations passed!"))+"\n", color)
 	} else {
-		if r.isTerminal {
-			sb.WriteString("\033[31m") // Red
+		paint(&sb, t.Error, mark(t.Fail, tr.Sprintf("Found %d error(s)", results.ErrorCount()))+"\n", color)
+	}
+
+	return sb.String(), nil
+}
+
+// writeKey writes the key of a finding's row, if known, below it.
+func writeKey(sb *strings.Builder, tr *i18n.Catalog, columns []string, key string) {
+	if key == "" || len(columns) == 0 {
+		return
+	}
+	name := columns[0]
+	if len(columns) > 1 {
+		name = "(" + strings.Join(columns, ", ") + ")"
+	}
+	sb.WriteString("     " + tr.Sprintf("Key: %s %s", name, key) + "\n")
+}
+
+// writeColumns writes the values of the included columns of a finding's row, if any,
+// below it, by column name.
+func writeColumns(sb *strings.Builder, tr *i18n.Catalog, columns map[string]string) {
+	if len(columns) == 0 {
+		return
+	}
+	names := make([]string, 0, len(columns))
+	for name := range columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	values := make([]string, len(names))
+	for i, name := range names {
+		values[i] = fmt.Sprintf("%s=%q", name, columns[name])
+	}
+	sb.WriteString("     " + tr.Sprintf("Columns: %s", strings.Join(values, ", ")) + "\n")
+}
+
+// writeSuggestion writes how to fix a finding, if known, below it.
+func writeSuggestion(sb *strings.Builder, tr *i18n.Catalog, suggestion string) {
+	if suggestion != "" {
+		sb.WriteString("     " + tr.Sprintf("Suggestion: %s", tr.Message(suggestion)) + "\n")
+	}
+}
+
+// heading underlines a title, counting wide characters, such as those of Japanese, as
+// two columns.
+func heading(title string) string {
+	width := 0
+	for _, r := range title {
+		width++
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) ||
+			r >= 0x3000 && r <= 0x303F || r >= 0xFF00 && r <= 0xFF60 {
+			width++
+		}
+	}
+	return title + "\n" + strings.Repeat("=", width) + "\n"
+}
+
+// findingCount renders the number of findings, and of entries when findings were
+// rolled up by Dedupe.
+func findingCount(tr *i18n.Catalog, findings, entries int) string {
+	if findings == entries {
+		return fmt.Sprint(findings)
+	}
+	return tr.Sprintf("%d, %d distinct", findings, entries)
+}
+
+// lines renders where a finding is: its line, or the lines and count of the findings
+// rolled up into it.
+func lines(tr *i18n.Catalog, first, last, occurrences int) string {
+	if occurrences == 0 {
+		return tr.Sprintf("Line %d", first)
+	}
+	return tr.Sprintf("Lines %d-%d (%d times)", first, last, occurrences)
+}
+
+// topIssueCount is the number of issues in the Top issues section of pretty output.
+const topIssueCount = 5
+
+// issue is an error message in a column, with the number of errors reporting it.
+type issue struct {
+	column, message string
+	count           int
+}
+
+// topIssues returns the n most frequent column and message pairs of errors, most
+// frequent first, then in order of first appearance. Errors rolled up by Dedupe count
+// as the findings they stand for, and errors about a whole row are in validator.RowField.
+func topIssues(errs []validator.Finding, n int) []issue {
+	index := make(map[issue]int)
+	var issues []issue
+	for _, e := range errs {
+		column := e.Field
+		if column == "" || column == "row" {
+			column = validator.RowField
 		}
-		sb.WriteString(fmt.Sprintf("✗ Found %d error(s)\n", len(results.Errors)))
-		if r.isTerminal {
-			sb.WriteString("\033[0m") // Reset
+		key := issue{column: column, message: e.Message}
+		i, seen := index[key]
+		if !seen {
+			i = len(issues)
+			index[key] = i
+			issues = append(issues, key)
 		}
+		issues[i].count += max(e.Occurrences, 1)
 	}
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].count > issues[j].count })
+	if len(issues) > n {
+		issues = issues[:n]
+	}
+	return issues
+}
+
+// heatmapWidth is the length of a bar for 100% of the errors.
+const heatmapWidth = 20
+
+// writeCounts writes a breakdown section as aligned rows with a bar of unit per count.
+func writeCounts(sb *strings.Builder, title string, counts []validator.Count, unit string) {
+	width := 0
+	for _, c := range counts {
+		width = max(width, len(c.Name))
+	}
+	sb.WriteString(fmt.Sprintf("\n%s:\n", title))
+	for _, c := range counts {
+		bar := strings.Repeat(unit, max(1, int(c.Percent/100*heatmapWidth+0.5)))
+		sb.WriteString(fmt.Sprintf("  %-*s %6d %5.1f%% %s\n", width, c.Name, c.Errors, c.Percent, bar))
+	}
+}
 
+// formatPrettyBatch renders every file's pretty report followed by an overall summary
+func (r *Reporter) formatPrettyBatch(batch *validator.Batch, color bool) (string, error) {
+	var sb strings.Builder
+
+	for _, results := range batch.Files {
+		section, err := r.formatPretty(results, color)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(section)
+		sb.WriteString("\n")
+	}
+	sb.WriteString(r.formatPrettySummary(batch, color))
 	return sb.String(), nil
 }
+
+// formatPrettySummary renders the overall summary of a multi-file run
+func (r *Reporter) formatPrettySummary(batch *validator.Batch, color bool) string {
+	var sb strings.Builder
+	t, tr := r.theme, r.catalog
+
+	paint(&sb, t.Heading, heading(tr.Sprintf("Summary")), color)
+	sb.WriteString(tr.Sprintf("Files: %d (%d invalid)", batch.TotalFiles, batch.InvalidFiles) + "\n")
+	if batch.Partial {
+		sb.WriteString(tr.Sprintf("Coverage: partial, not every file was validated to the end") + "\n")
+	}
+	sb.WriteString(tr.Sprintf("Total Rows: %d", batch.TotalRows) + "\n")
+	sb.WriteString(tr.Sprintf("Duration: %s", batch.Duration) + "\n")
+	sb.WriteString("\n")
+	if batch.Valid {
+		paint(&sb, t.Success, mark(t.Pass, tr.Sprintf("All %d file(s) passed!", batch.TotalFiles))+"\n", color)
+	} else {
+		paint(&sb, t.Error, mark(t.Fail, tr.Sprintf("Found %d error(s) in %d file(s)", batch.TotalErrors, batch.InvalidFiles))+"\n", color)
+	}
+	return sb.String()
+}
@@ -8,7 +8,10 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/csvlinter/csvlinter/internal/i18n"
 	"github.com/csvlinter/csvlinter/internal/validator"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
 )
 
 func TestReporter(t *testing.T) {
@@ -16,28 +19,31 @@ func TestReporter(t *testing.T) {
 	results := &validator.Results{
 		File:      "test.csv",
 		TotalRows: 3,
-		Errors: []validator.Error{
+		Errors: []validator.Finding{
 			{
-				LineNumber: 2,
-				Field:      "email",
-				Message:    "invalid email format",
-				Value:      "not-an-email",
-				Type:       "schema",
+				Severity: validator.SeverityError,
+				Location: validator.Location{Line: 2},
+				Field:    "email",
+				Message:  "invalid email format",
+				Value:    "not-an-email",
+				Type:     "schema",
 			},
 			{
-				LineNumber: 3,
-				Field:      "row",
-				Message:    "column count mismatch: expected 3, got 4",
-				Type:       "structure",
+				Severity: validator.SeverityError,
+				Location: validator.Location{Line: 3},
+				Field:    "row",
+				Message:  "column count mismatch: expected 3, got 4",
+				Type:     "structure",
 			},
 		},
-		Warnings: []validator.Warning{
+		Warnings: []validator.Finding{
 			{
-				LineNumber: 1,
-				Field:      "age",
-				Message:    "value out of recommended range",
-				Value:      "150",
-				Type:       "schema",
+				Severity: validator.SeverityWarning,
+				Location: validator.Location{Line: 1},
+				Field:    "age",
+				Message:  "value out of recommended range",
+				Value:    "150",
+				Type:     "schema",
 			},
 		},
 		Duration:   "15.2ms",
@@ -188,8 +194,8 @@ func TestReporterWithEmptyResults(t *testing.T) {
 	results := &validator.Results{
 		File:       "empty.csv",
 		TotalRows:  0,
-		Errors:     []validator.Error{},
-		Warnings:   []validator.Warning{},
+		Errors:     []validator.Finding{},
+		Warnings:   []validator.Finding{},
 		Duration:   "1.2ms",
 		Valid:      true,
 		SchemaUsed: false,
@@ -256,3 +262,371 @@ func TestReporterWithEmptyResults(t *testing.T) {
 		}
 	})
 }
+
+func TestReporterDestinations(t *testing.T) {
+	results := &validator.Results{
+		File:      "test.csv",
+		TotalRows: 1,
+		Errors:    []validator.Finding{},
+		Warnings:  []validator.Finding{},
+		Duration:  "1ms",
+		Valid:     true,
+	}
+
+	t.Run("Tee writes to file and writer", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "results.json")
+		var buf bytes.Buffer
+		r := NewWithDestinations(Destination{Format: "json", Path: outputPath, Tee: true})
+		if err := r.Report(results, &buf); err != nil {
+			t.Fatalf("Report failed: %v", err)
+		}
+		content, err := os.ReadFile(outputPath)
+		if err != nil {
+			t.Fatalf("Failed to read output file: %v", err)
+		}
+		if string(content) != buf.String() {
+			t.Errorf("Expected file and writer to receive the same report\nfile: %s\nwriter: %s", content, buf.String())
+		}
+	})
+
+	t.Run("Without tee the writer stays empty", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "results.json")
+		var buf bytes.Buffer
+		r := New("json", outputPath)
+		if err := r.Report(results, &buf); err != nil {
+			t.Fatalf("Report failed: %v", err)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("Expected nothing written to writer, got %q", buf.String())
+		}
+	})
+
+	t.Run("Multiple formats", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "results.json")
+		var buf bytes.Buffer
+		r := NewWithDestinations(
+			Destination{Format: "json", Path: outputPath},
+			Destination{Format: "pretty"},
+		)
+		if err := r.Report(results, &buf); err != nil {
+			t.Fatalf("Report failed: %v", err)
+		}
+		content, err := os.ReadFile(outputPath)
+		if err != nil {
+			t.Fatalf("Failed to read output file: %v", err)
+		}
+		var decoded validator.Results
+		if err := json.Unmarshal(content, &decoded); err != nil {
+			t.Fatalf("Expected JSON in output file: %v", err)
+		}
+		if !strings.Contains(buf.String(), "CSV Validation Results") {
+			t.Errorf("Expected pretty output on writer, got %q", buf.String())
+		}
+		if strings.Contains(buf.String(), "\033[") {
+			t.Errorf("Expected no ANSI colors when writer is not a terminal")
+		}
+	})
+}
+
+func TestReporterBatch(t *testing.T) {
+	batch := validator.NewBatch([]*validator.Results{
+		{File: "a.csv", TotalRows: 2, Errors: []validator.Finding{}, Warnings: []validator.Finding{}, Duration: "1ms", Valid: true},
+		{File: "b.csv", TotalRows: 1, Errors: []validator.Finding{{Severity: validator.SeverityError, Location: validator.Location{Line: 2}, Message: "bad", Type: "schema"}}, Warnings: []validator.Finding{}, Duration: "1ms", Valid: false},
+	}, 0)
+
+	t.Run("JSON", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := New("json", "").ReportBatch(batch, &buf); err != nil {
+			t.Fatalf("ReportBatch failed: %v", err)
+		}
+		var decoded validator.Batch
+		if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+			t.Fatalf("Failed to unmarshal batch JSON: %v", err)
+		}
+		if decoded.TotalFiles != 2 || decoded.InvalidFiles != 1 || decoded.TotalRows != 3 || decoded.Valid {
+			t.Errorf("Unexpected batch summary: %+v", decoded)
+		}
+	})
+
+	t.Run("Pretty", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := New("pretty", "").ReportBatch(batch, &buf); err != nil {
+			t.Fatalf("ReportBatch failed: %v", err)
+		}
+		out := buf.String()
+		for _, want := range []string{"File: a.csv", "File: b.csv", "Summary", "Found 1 error(s) in 1 file(s)"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("Expected %q in output:\n%s", want, out)
+			}
+		}
+	})
+}
+
+func TestReporterJSONStable(t *testing.T) {
+	results := &validator.Results{
+		File:      "data.csv",
+		TotalRows: 1,
+		Errors:    []validator.Finding{{Severity: validator.SeverityError, Location: validator.Location{Line: 2, Column: 1}, Field: "id", Message: "bad", Value: "x", Type: "schema", RuleID: "SCH002"}},
+		Warnings:  []validator.Finding{},
+		Duration:  "1ms",
+	}
+	want := `{
+  "report_schema_version": "` + SchemaVersion + `",
+  "file": "data.csv",
+  "total_rows": 1,
+  "errors": [
+    {
+      "severity": "error",
+      "line_number": 2,
+      "column": 1,
+      "field": "id",
+      "message": "bad",
+      "value": "x",
+      "type": "schema",
+      "rule": "SCH002"
+    }
+  ],
+  "warnings": [],
+  "duration": "1ms",
+  "valid": false,
+  "schema_used": false
+}
+`
+	for i := 0; i < 3; i++ {
+		var buf bytes.Buffer
+		if err := New("json", "").Report(results, &buf); err != nil {
+			t.Fatalf("Report failed: %v", err)
+		}
+		if buf.String() != want {
+			t.Fatalf("Unexpected JSON report:\n%s\nwant:\n%s", buf.String(), want)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := New("json", "").ReportBatch(validator.NewBatch([]*validator.Results{results}, 0), &buf); err != nil {
+		t.Fatalf("ReportBatch failed: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "{\n  \"report_schema_version\": \""+SchemaVersion+"\",\n  \"files\": [") {
+		t.Errorf("Expected the batch report to start with its schema version, got:\n%s", buf.String())
+	}
+}
+
+func TestReportSchema(t *testing.T) {
+	var meta struct {
+		ID          string `json:"$id"`
+		Description string `json:"description"`
+	}
+	if err := json.Unmarshal(ReportSchema(), &meta); err != nil || !strings.HasSuffix(meta.ID, "/"+SchemaVersion) ||
+		!strings.Contains(meta.Description, "report_schema_version "+SchemaVersion+":") {
+		t.Errorf("Expected the report schema to name version %s, got %q, %q (%v)", SchemaVersion, meta.ID, meta.Description, err)
+	}
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource("report.schema.json", bytes.NewReader(ReportSchema())); err != nil {
+		t.Fatalf("Invalid report schema: %v", err)
+	}
+	s, err := compiler.Compile("report.schema.json")
+	if err != nil {
+		t.Fatalf("Report schema does not compile: %v", err)
+	}
+	check := func(name, report string) {
+		t.Helper()
+		var doc interface{}
+		if err := json.Unmarshal([]byte(report), &doc); err != nil {
+			t.Fatalf("%s: invalid JSON: %v", name, err)
+		}
+		if err := s.Validate(doc); err != nil {
+			t.Errorf("%s report does not match the report schema: %v\n%s", name, err, report)
+		}
+	}
+
+	// Every optional field set, so a field missing from the schema fails the test
+	full := &validator.Results{
+		File:      "data.csv",
+		TotalRows: 2,
+		Errors: []validator.Finding{{
+			Severity: validator.SeverityError,
+			Location: validator.Location{Line: 2, Column: 1}, Field: "id", Message: "bad", Value: "x", ValueTruncated: true,
+			Occurrences: 2, LastLine: 3, Type: "schema", RuleID: "SCH002", Schema: "a.schema.json",
+		}},
+		Warnings:       []validator.Finding{{Severity: validator.SeverityWarning, Location: validator.Location{Line: 1}, Message: "unlisted", Type: "manifest", RuleID: "MAN004"}},
+		Duration:       "1ms",
+		SchemaUsed:     true,
+		SchemaInferred: true,
+		Cached:         true,
+		SHA256:         strings.Repeat("a", 64),
+		Fingerprint:    strings.Repeat("b", 64),
+		Samples:        map[string][]validator.Sample{"SCH002": {{LineNumber: 2, Values: map[string]string{"id": "x"}}}},
+		Partial:        true,
+		Coverage:       &validator.Coverage{TimeBudget: "1s", RowsScanned: 2, BytesScanned: 10, TotalBytes: 20, Percent: 50, EstimatedTotalRows: 4},
+		Contract:       &validator.Contract{ProducerSchema: "p.json", ConsumerSchema: "c.json", ConsumerErrors: 1, RejectedBy: "consumer"},
+		Timings:        &validator.Timings{Read: 0.5, UTF8: 0.01, Parse: 1.2, Structure: 0.1, Schema: 3, Checks: 0.2, Report: 0.3},
+		Run:            &validator.Run{Seed: 7, Options: json.RawMessage(`{"delimiter":","}`)},
+	}
+	full.Summarize()
+	empty := &validator.Results{File: "empty.csv", Duration: "1ms", Valid: true}
+
+	for name, results := range map[string]*validator.Results{"full": full, "empty": empty} {
+		var buf bytes.Buffer
+		if err := New("json", "").Report(results, &buf); err != nil {
+			t.Fatalf("Report failed: %v", err)
+		}
+		check(name, buf.String())
+	}
+	var buf bytes.Buffer
+	if err := New("json", "").ReportBatch(validator.NewBatch([]*validator.Results{full, empty}, 0), &buf); err != nil {
+		t.Fatalf("ReportBatch failed: %v", err)
+	}
+	check("batch", buf.String())
+}
+
+func TestPrettyContract(t *testing.T) {
+	for _, tc := range []struct {
+		contract validator.Contract
+		want     string
+	}{
+		{validator.Contract{}, "Contract: producer and consumer both accept the file\n"},
+		{validator.Contract{ConsumerErrors: 3, RejectedBy: "consumer"}, "Contract: consumer rejects the file (3 error(s)); producer accepts it\n"},
+		{validator.Contract{ProducerErrors: 1, RejectedBy: "producer"}, "Contract: producer rejects the file (1 error(s)); consumer accepts it\n"},
+		{validator.Contract{ProducerErrors: 1, ConsumerErrors: 2, RejectedBy: "both"}, "Contract: producer (1 error(s)) and consumer (2 error(s)) both reject the file\n"},
+	} {
+		contract := tc.contract
+		var buf bytes.Buffer
+		if err := New("pretty", "").Report(&validator.Results{File: "data.csv", Contract: &contract}, &buf); err != nil {
+			t.Fatalf("Report failed: %v", err)
+		}
+		if !strings.Contains(buf.String(), tc.want) {
+			t.Errorf("Expected %q in:\n%s", tc.want, buf.String())
+		}
+	}
+}
+
+func TestPrettyBreakdown(t *testing.T) {
+	results := &validator.Results{File: "data.csv", Errors: []validator.Finding{
+		{Severity: validator.SeverityError, Location: validator.Location{Line: 2}, Field: "phone", Message: "bad", Type: "schema", RuleID: "SCH004"},
+		{Severity: validator.SeverityError, Location: validator.Location{Line: 3}, Field: "phone", Message: "bad", Type: "schema", RuleID: "SCH004"},
+		{Severity: validator.SeverityError, Location: validator.Location{Line: 4}, Field: "phone", Message: "bad", Type: "schema", RuleID: "SCH004"},
+		{Severity: validator.SeverityError, Location: validator.Location{Line: 5}, Message: "too many fields", Type: "structure", RuleID: "STR002"},
+	}}
+	results.Summarize()
+	var buf bytes.Buffer
+	if err := New("pretty", "").Report(results, &buf); err != nil {
+		t.Fatalf("Report failed: %v", err)
+	}
+	want := "\nErrors by column:\n" +
+		"  phone      3  75.0% ███████████████\n" +
+		"  (row)      1  25.0% █████\n" +
+		"\nErrors by rule:\n" +
+		"  SCH004      3  75.0% ███████████████\n" +
+		"  STR002      1  25.0% █████\n"
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("Expected %q in:\n%s", want, buf.String())
+	}
+}
+
+func TestPrettyPartial(t *testing.T) {
+	results := &validator.Results{File: "data.csv", Duration: "1ms", Valid: true, Partial: true}
+	var buf bytes.Buffer
+	if err := New("pretty", "").ReportBatch(validator.NewBatch([]*validator.Results{results}, 0), &buf); err != nil {
+		t.Fatalf("ReportBatch failed: %v", err)
+	}
+	for _, want := range []string{
+		"Coverage: partial, validation was interrupted\n",
+		"Coverage: partial, not every file was validated to the end\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Expected %q in:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestPrettyTopIssues(t *testing.T) {
+	var errs []validator.Finding
+	for i, issue := range []struct{ field, message string }{
+		{"email", "not an email"}, {"phone", "bad"}, {"phone", "bad"}, {"", "too many fields"},
+		{"a", "x"}, {"b", "x"}, {"c", "x"}, {"email", "not an email"}, {"email", "too long"},
+	} {
+		errs = append(errs, validator.Finding{Severity: validator.SeverityError, Location: validator.Location{Line: i + 2}, Field: issue.field, Message: issue.message, Type: "schema"})
+	}
+	errs = append(errs, validator.Finding{Severity: validator.SeverityError, Location: validator.Location{Line: 20}, Field: "c", Message: "x", Type: "schema", Occurrences: 4, LastLine: 30})
+	results := &validator.Results{File: "data.csv", Errors: errs}
+	var buf bytes.Buffer
+	if err := New("pretty", "").Report(results, &buf); err != nil {
+		t.Fatalf("Report failed: %v", err)
+	}
+	want := "\nTop issues:\n" +
+		"  1. c: x (5 error(s))\n" +
+		"  2. email: not an email (2 error(s))\n" +
+		"  3. phone: bad (2 error(s))\n" +
+		"  4. (row): too many fields (1 error(s))\n" +
+		"  5. a: x (1 error(s))\n" +
+		"\n✗ Found 13 error(s)\n"
+	if !strings.HasSuffix(buf.String(), want) {
+		t.Errorf("Expected %q at the end of:\n%s", want, buf.String())
+	}
+
+	// A single error is its own top issue
+	results.Errors = errs[:1]
+	buf.Reset()
+	if err := New("pretty", "").Report(results, &buf); err != nil {
+		t.Fatalf("Report failed: %v", err)
+	}
+	if strings.Contains(buf.String(), "Top issues") {
+		t.Errorf("Expected no top issues for one error:\n%s", buf.String())
+	}
+}
+
+func TestPrettyCatalog(t *testing.T) {
+	results := &validator.Results{File: "data.csv", TotalRows: 2, Errors: []validator.Finding{
+		{Severity: validator.SeverityError, Location: validator.Location{Line: 2}, Message: "column count mismatch: expected 3, got 2", Type: "structure", RuleID: "STR001"},
+	}}
+	results.Summarize()
+	catalog, err := i18n.Lookup("fr")
+	if err != nil {
+		t.Fatalf("Lookup failed: %v", err)
+	}
+	r := New("pretty", "").WithCatalog(catalog)
+	out, err := r.format(results, "pretty", false)
+	if err != nil {
+		t.Fatalf("format failed: %v", err)
+	}
+	for _, want := range []string{
+		"Résultats de la validation CSV\n==============================\n",
+		"Nombre de lignes : 2\n",
+		"Statut : ✗ INVALIDE\n",
+		"  1. Ligne 2: nombre de colonnes incorrect : 3 attendues, 2 trouvées [structure]\n",
+		"1 erreur(s) trouvée(s)\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in:\n%s", want, out)
+		}
+	}
+
+	// Machine formats keep English messages
+	out, err = r.format(results, "json", false)
+	if err != nil {
+		t.Fatalf("format failed: %v", err)
+	}
+	if !strings.Contains(out, `"message": "column count mismatch: expected 3, got 2"`) || !strings.Contains(out, `"rule": "STR001"`) {
+		t.Errorf("Expected English JSON, got:\n%s", out)
+	}
+
+	ja, _ := i18n.Lookup("ja")
+	if got := heading(ja.Sprintf("Summary")); got != "概要\n====\n" {
+		t.Errorf("heading = %q, want two columns per wide character", got)
+	}
+}
+
+func TestPrettySuggestion(t *testing.T) {
+	results := &validator.Results{File: "data.csv", Errors: []validator.Finding{
+		{Severity: validator.SeverityError, Location: validator.Location{Line: 2}, Field: "day", Message: "bad date", Suggestion: "expected format YYYY-MM-DD", Type: "schema", RuleID: "SCH001"},
+		{Severity: validator.SeverityError, Location: validator.Location{Line: 3}, Field: "day", Message: "bad date", Type: "schema", RuleID: "SCH001"},
+	}}
+	var buf bytes.Buffer
+	if err := New("pretty", "").Report(results, &buf); err != nil {
+		t.Fatalf("Report failed: %v", err)
+	}
+	want := "  1. Line 2 (day): bad date [schema]\n     Suggestion: expected format YYYY-MM-DD\n  2. Line 3 (day): bad date [schema]\n"
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("Expected %q in:\n%s", want, buf.String())
+	}
+}
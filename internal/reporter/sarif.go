@@ -0,0 +1,156 @@
+package reporter
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/csvlinter/csvlinter/internal/rules"
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+// SARIFVersion is the version of the SARIF format sarif output is written in.
+const SARIFVersion = "2.1.0"
+
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// The parts of a SARIF log that sarif output uses.
+type (
+	sarifLog struct {
+		Schema  string     `json:"$schema"`
+		Version string     `json:"version"`
+		Runs    []sarifRun `json:"runs"`
+	}
+	sarifRun struct {
+		Tool    sarifTool     `json:"tool"`
+		Results []sarifResult `json:"results"`
+	}
+	sarifTool struct {
+		Driver sarifDriver `json:"driver"`
+	}
+	sarifDriver struct {
+		Name           string      `json:"name"`
+		InformationURI string      `json:"informationUri"`
+		Rules          []sarifRule `json:"rules"`
+	}
+	sarifRule struct {
+		ID               string       `json:"id"`
+		ShortDescription sarifMessage `json:"shortDescription"`
+	}
+	sarifMessage struct {
+		Text     string `json:"text"`
+		Markdown string `json:"markdown,omitempty"`
+	}
+	sarifResult struct {
+		RuleID    string          `json:"ruleId,omitempty"`
+		RuleIndex *int            `json:"ruleIndex,omitempty"`
+		Level     string          `json:"level"`
+		Message   sarifMessage    `json:"message"`
+		Locations []sarifLocation `json:"locations"`
+		Fixes     []sarifFix      `json:"fixes,omitempty"`
+	}
+	sarifLocation struct {
+		PhysicalLocation sarifPhysical `json:"physicalLocation"`
+	}
+	sarifPhysical struct {
+		ArtifactLocation sarifArtifact `json:"artifactLocation"`
+		Region           *sarifRegion  `json:"region,omitempty"`
+	}
+	sarifArtifact struct {
+		URI string `json:"uri"`
+	}
+	sarifRegion struct {
+		StartLine  int    `json:"startLine,omitempty"`
+		ByteOffset *int64 `json:"byteOffset,omitempty"`
+		ByteLength *int   `json:"byteLength,omitempty"`
+	}
+	sarifFix struct {
+		Description     sarifMessage  `json:"description"`
+		ArtifactChanges []sarifChange `json:"artifactChanges"`
+	}
+	sarifChange struct {
+		ArtifactLocation sarifArtifact      `json:"artifactLocation"`
+		Replacements     []sarifReplacement `json:"replacements"`
+	}
+	sarifReplacement struct {
+		DeletedRegion   sarifRegion  `json:"deletedRegion"`
+		InsertedContent sarifMessage `json:"insertedContent"`
+	}
+)
+
+// formatSARIF renders the findings of files as a SARIF log with one run, for code
+// scanning tools. Findings with a fix carry it as a SARIF fix replacing bytes of the file.
+func formatSARIF(files []*validator.Results) (string, error) {
+	indexes := make(map[string]int)
+	var used []string
+	results := []sarifResult{}
+	for _, file := range files {
+		uri := filepath.ToSlash(file.File)
+		for _, list := range [][]validator.Finding{file.Errors, file.Warnings} {
+			for _, f := range list {
+				if _, ok := indexes[f.RuleID]; !ok && f.RuleID != "" {
+					indexes[f.RuleID] = 0
+					used = append(used, f.RuleID)
+				}
+				results = append(results, sarifFinding(uri, f))
+			}
+		}
+	}
+
+	sort.Strings(used)
+	driver := sarifDriver{Name: "csvlinter", InformationURI: "https://github.com/csvlinter/csvlinter", Rules: []sarifRule{}}
+	for i, id := range used {
+		indexes[id] = i
+		rule, _ := rules.Lookup(id)
+		driver.Rules = append(driver.Rules, sarifRule{ID: id, ShortDescription: sarifMessage{Text: rule.Description}})
+	}
+	for i := range results {
+		if index, ok := indexes[results[i].RuleID]; ok {
+			results[i].RuleIndex = &index
+		}
+	}
+	return marshalJSON(sarifLog{
+		Schema:  sarifSchema,
+		Version: SARIFVersion,
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	})
+}
+
+// markdownEscaper escapes the characters markdown would format in plain text.
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "<", `\<`, ">", `\>`, "#", `\#`)
+
+// sarifFinding converts a finding in the file at uri to a SARIF result.
+func sarifFinding(uri string, f validator.Finding) sarifResult {
+	level := "error"
+	if f.Severity == validator.SeverityWarning {
+		level = "warning"
+	}
+	physical := sarifPhysical{ArtifactLocation: sarifArtifact{URI: uri}}
+	if f.Line > 0 {
+		physical.Region = &sarifRegion{StartLine: f.Line}
+	}
+	r := sarifResult{
+		RuleID:    f.RuleID,
+		Level:     level,
+		Message:   sarifMessage{Text: withRollup(withSchema(f.Message, f.Schema), f.LastLine, f.Occurrences)},
+		Locations: []sarifLocation{{PhysicalLocation: physical}},
+	}
+	// Viewers that render markdown show the suggestion below the message
+	if f.Suggestion != "" {
+		r.Message.Markdown = markdownEscaper.Replace(r.Message.Text) + "\n\nSuggestion: " + markdownEscaper.Replace(f.Suggestion)
+	}
+	if fix := f.Fix; fix != nil {
+		offset, length := fix.Offset, fix.Length
+		r.Fixes = []sarifFix{{
+			Description: sarifMessage{Text: fix.Description},
+			ArtifactChanges: []sarifChange{{
+				ArtifactLocation: sarifArtifact{URI: uri},
+				Replacements: []sarifReplacement{{
+					DeletedRegion:   sarifRegion{ByteOffset: &offset, ByteLength: &length},
+					InsertedContent: sarifMessage{Text: fix.Text},
+				}},
+			}},
+		}}
+	}
+	return r
+}
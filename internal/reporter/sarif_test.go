@@ -0,0 +1,118 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+func TestSARIF(t *testing.T) {
+	batch := validator.NewBatch([]*validator.Results{
+		{File: "in/a.csv", Errors: []validator.Finding{
+			{Severity: validator.SeverityError, Location: validator.Location{Line: 2}, Field: "name", Message: "value has whitespace", Type: "schema", RuleID: "SCH004",
+				Suggestion: "trim it, e.g. with the *trim* transform", Fix: &validator.Fix{Description: "remove the leading and trailing whitespace", Offset: 10, Length: 3, Text: "ab"}},
+		}},
+		{File: "in/b.csv", Warnings: []validator.Finding{
+			{Severity: validator.SeverityWarning, Location: validator.Location{Line: 3, Column: 1}, Field: "id", Message: "id is quoted but needs no quotes", Type: "structure", RuleID: "STR008",
+				Fix: &validator.Fix{Description: "remove the quotes", Offset: 0, Length: 3, Text: "1"}},
+		}, Errors: []validator.Finding{
+			{Severity: validator.SeverityError, Message: "cannot open", Type: "file", RuleID: "FIL001"},
+		}},
+	}, time.Second)
+	var buf bytes.Buffer
+	if err := New("sarif", "").ReportBatch(batch, &buf); err != nil {
+		t.Fatalf("ReportBatch failed: %v", err)
+	}
+
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name  string `json:"name"`
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				RuleIndex int    `json:"ruleIndex"`
+				Level     string `json:"level"`
+				Message   struct {
+					Text     string `json:"text"`
+					Markdown string `json:"markdown"`
+				} `json:"message"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Region *struct {
+							StartLine int `json:"startLine"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+				Fixes []struct {
+					Description struct {
+						Text string `json:"text"`
+					} `json:"description"`
+					ArtifactChanges []struct {
+						Replacements []struct {
+							DeletedRegion   map[string]int `json:"deletedRegion"`
+							InsertedContent struct {
+								Text string `json:"text"`
+							} `json:"insertedContent"`
+						} `json:"replacements"`
+					} `json:"artifactChanges"`
+				} `json:"fixes"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if log.Version != SARIFVersion || len(log.Runs) != 1 || log.Runs[0].Tool.Driver.Name != "csvlinter" {
+		t.Fatalf("unexpected log:\n%s", buf.String())
+	}
+	run := log.Runs[0]
+	var ruleIDs []string
+	for _, r := range run.Tool.Driver.Rules {
+		ruleIDs = append(ruleIDs, r.ID)
+	}
+	if len(ruleIDs) != 3 || ruleIDs[0] != "FIL001" || ruleIDs[1] != "SCH004" || ruleIDs[2] != "STR008" {
+		t.Errorf("rules %v, want the three used, sorted", ruleIDs)
+	}
+	if len(run.Results) != 3 {
+		t.Fatalf("want 3 results, got:\n%s", buf.String())
+	}
+
+	first := run.Results[0]
+	if first.RuleID != "SCH004" || first.RuleIndex != 1 || first.Level != "error" || first.Locations[0].PhysicalLocation.ArtifactLocation.URI != "in/a.csv" ||
+		first.Locations[0].PhysicalLocation.Region.StartLine != 2 {
+		t.Errorf("unexpected first result:\n%s", buf.String())
+	}
+	// The suggestion is shown by viewers that render markdown
+	if first.Message.Text != "value has whitespace" || first.Message.Markdown != "value has whitespace\n\nSuggestion: trim it, e.g. with the \\*trim\\* transform" {
+		t.Errorf("unexpected message: %+v", first.Message)
+	}
+	if run.Results[1].Message.Markdown != "" {
+		t.Errorf("want no markdown without a suggestion, got %q", run.Results[1].Message.Markdown)
+	}
+	replacement := first.Fixes[0].ArtifactChanges[0].Replacements[0]
+	if first.Fixes[0].Description.Text != "remove the leading and trailing whitespace" || replacement.DeletedRegion["byteOffset"] != 10 ||
+		replacement.DeletedRegion["byteLength"] != 3 || replacement.InsertedContent.Text != "ab" {
+		t.Errorf("unexpected fix:\n%s", buf.String())
+	}
+	if fileError := run.Results[1]; fileError.RuleID != "FIL001" || fileError.Locations[0].PhysicalLocation.Region != nil || fileError.Fixes != nil {
+		t.Errorf("want the file error without region or fix, got:\n%s", buf.String())
+	}
+	// A fix at the start of the file keeps its offset
+	if warning := run.Results[2]; warning.Level != "warning" || warning.Fixes[0].ArtifactChanges[0].Replacements[0].DeletedRegion["byteOffset"] != 0 ||
+		!bytes.Contains(buf.Bytes(), []byte(`"byteOffset": 0`)) {
+		t.Errorf("unexpected warning:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,43 @@
+package reporter
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+// writeSnippet renders the rows around the failing line below its errors: the header,
+// then each row with its line number, the failing one marked with ">". Nothing is written
+// when there is no context for the line.
+func writeSnippet(sb *strings.Builder, context *validator.RowContext, line int) {
+	if context == nil {
+		return
+	}
+	lines, ok := context.Snippets[line]
+	if !ok {
+		return
+	}
+	width := len(strconv.Itoa(lines[len(lines)-1]))
+	sb.WriteString(fmt.Sprintf("     %*s | %s\n", width+2, "", joinFields(context.Header, context.Delimiter)))
+	for _, l := range lines {
+		marker := " "
+		if l == line {
+			marker = ">"
+		}
+		sb.WriteString(fmt.Sprintf("     %s %*d | %s\n", marker, width, l, joinFields(context.Rows[l], context.Delimiter)))
+	}
+}
+
+// joinFields writes fields as a CSV record, quoting them as needed.
+func joinFields(fields []string, delimiter rune) string {
+	var sb strings.Builder
+	w := csv.NewWriter(&sb)
+	w.Comma = delimiter
+	// Writing to a strings.Builder cannot fail
+	_ = w.Write(fields)
+	w.Flush()
+	return strings.TrimSuffix(sb.String(), "\n")
+}
@@ -0,0 +1,47 @@
+package reporter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+func TestReporterPrettyContext(t *testing.T) {
+	results := &validator.Results{
+		File:      "data.csv",
+		TotalRows: 10,
+		Errors: []validator.Finding{
+			{Severity: validator.SeverityError, Location: validator.Location{Line: 9}, Field: "id", Message: "bad id", Type: "schema"},
+			{Severity: validator.SeverityError, Location: validator.Location{Line: 9}, Field: "name", Message: "bad name", Type: "schema"},
+		},
+		Context: &validator.RowContext{
+			Header:    []string{"id", "name"},
+			Delimiter: ';',
+			Rows:      map[int][]string{8: {"7", "g"}, 9: {"x", "a;b"}, 10: {"9", "i"}},
+			Snippets:  map[int][]int{9: {8, 9, 10}},
+		},
+	}
+	var buf bytes.Buffer
+	if err := New("pretty", "").Report(results, &buf); err != nil {
+		t.Fatalf("Report failed: %v", err)
+	}
+	want := "  1. Line 9 (id): bad id [schema]\n" +
+		"  2. Line 9 (name): bad name [schema]\n" +
+		"          | id;name\n" +
+		"        8 | 7;g\n" +
+		"     >  9 | x;\"a;b\"\n" +
+		"       10 | 9;i\n"
+	if out := buf.String(); !strings.Contains(out, want) {
+		t.Errorf("Expected the rows around line 9 after its last error, got:\n%s", out)
+	}
+
+	buf.Reset()
+	if err := New("json", "").Report(results, &buf); err != nil {
+		t.Fatalf("Report failed: %v", err)
+	}
+	if strings.Contains(buf.String(), "a;b") {
+		t.Errorf("Expected no context in JSON output, got:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,155 @@
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+// Stream reports the files of a multi-file run as each one finishes, which may be on
+// several goroutines at once. Destinations written to the stream's writer get each file's
+// section as soon as Add receives it, then the overall summary from Close; destinations
+// with a Path or Dir hold one document and get the whole run from Close.
+//
+// Pretty and compact sections are those of ReportBatch. JSON is streamed as JSON Lines:
+// one line per file, shaped like the report of a single file, then a line with the
+// run's totals. SARIF logs cannot be streamed.
+type Stream struct {
+	r      *Reporter
+	writer io.Writer
+
+	mu  sync.Mutex // Guards writer and err, so sections are written whole
+	err error      // First write error; later files are not written
+}
+
+// Stream returns a stream of a multi-file run's reports to writer (os.Stdout when nil).
+func (r *Reporter) Stream(writer io.Writer) (*Stream, error) {
+	for _, dest := range r.destinations {
+		if streamed(dest) && dest.Format == "sarif" {
+			return nil, fmt.Errorf("sarif reports cannot be streamed")
+		}
+	}
+	if writer == nil {
+		writer = os.Stdout
+	}
+	return &Stream{r: r, writer: writer}, nil
+}
+
+// streamed reports whether dest is written to the writer passed to Report.
+func streamed(dest Destination) bool {
+	return dest.Path == "" && dest.Dir == "" || dest.Tee
+}
+
+// Add writes the section of a file that finished validating. It is safe for concurrent
+// use; sections are written in the order Add is called.
+func (s *Stream) Add(results *validator.Results) error {
+	if results == nil {
+		return fmt.Errorf("results cannot be nil")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	color := isTerminal(s.writer)
+	for _, dest := range s.r.destinations {
+		if !streamed(dest) {
+			continue
+		}
+		var output string
+		var err error
+		switch dest.Format {
+		case "json":
+			output, err = jsonLine(struct {
+				Version string `json:"report_schema_version"`
+				*validator.Results
+			}{SchemaVersion, withLists(results)})
+		case "pretty":
+			if output, err = s.r.formatPretty(results, color); err == nil {
+				output += "\n"
+			}
+		case "compact":
+			output = formatCompact(results, s.r.theme, color)
+		default:
+			err = fmt.Errorf("unsupported format: %s", dest.Format)
+		}
+		if err == nil {
+			err = s.print(output)
+		}
+		if err != nil {
+			s.err = err
+			return err
+		}
+	}
+	return nil
+}
+
+// Close writes the summary of batch, the complete run, to the writer, and the whole
+// report to every destination with a Path or Dir. batch must not be used by Add calls
+// still running.
+func (s *Stream) Close(batch *validator.Batch) error {
+	if batch == nil {
+		return fmt.Errorf("results cannot be nil")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	color := isTerminal(s.writer)
+	for _, dest := range s.r.destinations {
+		if dest.Path != "" || dest.Dir != "" {
+			// The writer has the files already; the summary follows below
+			file := dest
+			file.Tee = false
+			if err := s.r.write(file, s.writer, func(format string, color bool) (string, error) {
+				return s.r.formatBatch(batch, format, color)
+			}, func() *validator.Batch { return batch }); err != nil {
+				return err
+			}
+		}
+		if !streamed(dest) {
+			continue
+		}
+		var output string
+		var err error
+		switch dest.Format {
+		case "json":
+			output, err = jsonLine(struct {
+				Version string               `json:"report_schema_version"`
+				Files   []*validator.Results `json:"files,omitempty"` // Streamed already; shadows Batch.Files
+				*validator.Batch
+			}{Version: SchemaVersion, Batch: batch})
+		case "pretty":
+			output = s.r.formatPrettySummary(batch, color)
+		}
+		if err == nil {
+			err = s.print(output)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// print writes output to the stream's writer.
+func (s *Stream) print(output string) error {
+	if _, err := fmt.Fprint(s.writer, output); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	return nil
+}
+
+// jsonLine formats v as one line of JSON Lines.
+func jsonLine(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to format output: %w", err)
+	}
+	return string(data) + "\n", nil
+}
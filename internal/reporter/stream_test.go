@@ -0,0 +1,78 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+func TestStream(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "report.json")
+	r := NewWithDestinations(Destination{Format: "json", Path: out, Tee: true}, Destination{Format: "compact"})
+	var buf bytes.Buffer
+	s, err := r.Stream(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Files finish on several goroutines; each line is written whole
+	var files []*validator.Results
+	for i := 0; i < 20; i++ {
+		files = append(files, &validator.Results{File: fmt.Sprintf("f%02d.csv", i), TotalRows: 1,
+			Errors: []validator.Finding{{Severity: validator.SeverityError, Location: validator.Location{Line: 2}, Message: "bad", Type: "data", RuleID: "DAT001"}}})
+	}
+	var wg sync.WaitGroup
+	for _, f := range files {
+		wg.Add(1)
+		go func(f *validator.Results) {
+			defer wg.Done()
+			if err := s.Add(f); err != nil {
+				t.Error(err)
+			}
+		}(f)
+	}
+	wg.Wait()
+	if err := s.Close(validator.NewBatch(files, 0)); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 41 {
+		t.Fatalf("want a JSON and a compact line per file and the summary, got:\n%s", buf.String())
+	}
+	for _, line := range lines[:40] {
+		var file struct {
+			Version string `json:"report_schema_version"`
+			File    string `json:"file"`
+		}
+		if strings.HasPrefix(line, "{") && (json.Unmarshal([]byte(line), &file) != nil || file.Version != SchemaVersion || file.File == "") ||
+			!strings.HasPrefix(line, "{") && !strings.HasSuffix(line, "error DAT001 bad") {
+			t.Errorf("unexpected line %q", line)
+		}
+	}
+	var summary map[string]any
+	if err := json.Unmarshal([]byte(lines[40]), &summary); err != nil || summary["total_files"] != 20.0 || summary["files"] != nil {
+		t.Errorf("want the totals without the files last, got %s (%v)", lines[40], err)
+	}
+
+	// The output file holds the whole report
+	data, err := os.ReadFile(out)
+	var batch validator.Batch
+	if err != nil || json.Unmarshal(data, &batch) != nil || len(batch.Files) != 20 || batch.Files[0].File != "f00.csv" {
+		t.Errorf("want the whole batch in the output file, got %s (%v)", data, err)
+	}
+
+	if _, err := New("sarif", "").Stream(&buf); err == nil {
+		t.Error("want an error streaming sarif")
+	}
+	if _, err := New("sarif", out).Stream(&buf); err != nil {
+		t.Errorf("want sarif written to a file at the end, got %v", err)
+	}
+}
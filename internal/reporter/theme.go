@@ -0,0 +1,94 @@
+package reporter
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Theme is the colors and symbols of pretty and compact output. Colors are ANSI SGR
+// parameters, such as "31" for red or "1;31" for bold red; an empty color leaves the text
+// plain. Colors only apply when writing to a terminal.
+type Theme struct {
+	Error   string // Errors and the invalid status
+	Warning string // Warnings
+	Success string // The valid status
+	Heading string // Report titles
+
+	Pass string // Symbol before a passing status; empty for none
+	Fail string // Symbol before a failing status; empty for none
+	Bar  string // Unit of the bars of the error breakdown
+}
+
+// DefaultTheme is the theme of runs that do not choose one.
+const DefaultTheme = "classic"
+
+// Themes are the built-in themes. "emoji-free" only writes ASCII, for terminals and logs
+// that mangle ✓ and ✗.
+var Themes = map[string]Theme{
+	"classic":    {Error: "31", Warning: "33", Success: "32", Heading: "1", Pass: "✓", Fail: "✗", Bar: "█"},
+	"minimal":    {Bar: "#"},
+	"emoji-free": {Error: "31", Warning: "33", Success: "32", Heading: "1", Pass: "+", Fail: "x", Bar: "#"},
+}
+
+// LookupTheme returns the built-in theme name, or the default theme for "".
+func LookupTheme(name string) (Theme, error) {
+	if name == "" {
+		name = DefaultTheme
+	}
+	t, ok := Themes[name]
+	if !ok {
+		names := make([]string, 0, len(Themes))
+		for n := range Themes {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return Theme{}, fmt.Errorf("unknown theme '%s' (use %s)", name, strings.Join(names, ", "))
+	}
+	return t, nil
+}
+
+// colorNames are the color names ParseColor accepts.
+var colorNames = map[string]string{
+	"none": "", "bold": "1", "dim": "2", "underline": "4",
+	"black": "30", "red": "31", "green": "32", "yellow": "33", "blue": "34", "magenta": "35", "cyan": "36", "white": "37",
+}
+
+var sgrPattern = regexp.MustCompile(`^[0-9]{1,3}(;[0-9]{1,3})*$`)
+
+// ParseColor returns the SGR parameters of a color name, such as "red" or "bold red",
+// or of raw parameters such as "1;31". "none" is no color.
+func ParseColor(s string) (string, error) {
+	if sgrPattern.MatchString(s) {
+		return s, nil
+	}
+	var codes []string
+	for _, word := range strings.Fields(strings.ToLower(s)) {
+		code, ok := colorNames[word]
+		if !ok {
+			return "", fmt.Errorf("unknown color '%s' (use a name such as red or bold red, none, or SGR parameters such as 1;31)", s)
+		}
+		if code != "" {
+			codes = append(codes, code)
+		}
+	}
+	return strings.Join(codes, ";"), nil
+}
+
+// paint writes text in color code, when color is on and the code is set.
+func paint(sb *strings.Builder, code, text string, color bool) {
+	if !color || code == "" {
+		sb.WriteString(text)
+		return
+	}
+	sb.WriteString("\033[" + code + "m" + text + "\033[0m")
+}
+
+// mark prefixes a status with its symbol.
+func mark(symbol, status string) string {
+	if symbol == "" {
+		return status
+	}
+	return symbol + " " + status
+}
@@ -0,0 +1,68 @@
+package reporter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+func TestParseColor(t *testing.T) {
+	for in, want := range map[string]string{"red": "31", "Bold Red": "1;31", "1;35": "1;35", "none": ""} {
+		if got, err := ParseColor(in); err != nil || got != want {
+			t.Errorf("ParseColor(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseColor("purple"); err == nil {
+		t.Error("expected an unknown color to be rejected")
+	}
+	if _, err := LookupTheme("neon"); err == nil || !strings.Contains(err.Error(), "emoji-free") {
+		t.Errorf("expected an unknown theme to list the themes, got %v", err)
+	}
+}
+
+func TestPrettyThemes(t *testing.T) {
+	results := &validator.Results{File: "data.csv", Errors: []validator.Finding{{Severity: validator.SeverityError, Location: validator.Location{Line: 2}, Field: "id", Message: "bad", Type: "schema", RuleID: "SCH002"}}}
+	results.Summarize()
+	render := func(theme string, color bool) string {
+		th, err := LookupTheme(theme)
+		if err != nil {
+			t.Fatalf("LookupTheme failed: %v", err)
+		}
+		r := New("pretty", "").WithTheme(th)
+		out, err := r.format(results, "pretty", color)
+		if err != nil {
+			t.Fatalf("format failed: %v", err)
+		}
+		return out
+	}
+
+	out := render("emoji-free", true)
+	for _, r := range out {
+		if r > 127 {
+			t.Fatalf("expected ASCII-only output, found %q in:\n%s", r, out)
+		}
+	}
+	if !strings.Contains(out, "\033[31mx INVALID\n\033[0m") || !strings.Contains(out, "100.0% ####################") {
+		t.Errorf("expected ASCII symbols in colors, got:\n%s", out)
+	}
+
+	out = render("minimal", true)
+	if strings.Contains(out, "\033[") || !strings.Contains(out, "Status: INVALID\n") {
+		t.Errorf("expected no colors or symbols, got:\n%s", out)
+	}
+
+	custom := Themes["classic"]
+	custom.Error, custom.Fail = "1;35", "FAIL"
+	var buf bytes.Buffer
+	if err := New("compact", "").WithTheme(custom).Report(results, &buf); err != nil {
+		t.Fatalf("Report failed: %v", err)
+	}
+	if buf.String() != "data.csv:2 error SCH002 id: bad\n" {
+		t.Errorf("expected no colors outside a terminal, got %q", buf.String())
+	}
+	if out := formatCompact(results, custom, true); !strings.Contains(out, "\033[1;35merror\033[0m") {
+		t.Errorf("expected the custom error color, got %q", out)
+	}
+}
@@ -0,0 +1,151 @@
+// Package rules catalogs the checks behind csvlinter findings. Each rule has a stable ID
+// that tools can filter and suppress on, independent of the message wording.
+package rules
+
+import "sort"
+
+// Rule describes one kind of finding.
+type Rule struct {
+	ID          string `json:"id"`
+	Type        string `json:"type"` // Finding type: structure, encoding, schema, data, load, manifest or file
+	Description string `json:"description"`
+}
+
+// Rule IDs. Structure rules start with STR, encoding rules with ENC, schema rules with
+// SCH, checks across rows with DAT, database load checks with LOD, batch manifest checks
+// with MAN and file-level failures with FIL.
+const (
+	ColumnCount      = "STR001"
+	MalformedRow     = "STR002"
+	Envelope         = "STR003"
+	SepLine          = "STR004"
+	ShortRow         = "STR005"
+	LongRow          = "STR006"
+	TrailingDelim    = "STR007"
+	Quoting          = "STR008"
+	NoData           = "STR009"
+	Truncated        = "STR010"
+	RepeatedHeader   = "STR011"
+	LineEndings      = "STR012"
+	Like             = "STR013"
+	IncludeColumn    = "STR014"
+	InvalidUTF8      = "ENC001"
+	SchemaFormat     = "SCH001"
+	SchemaType       = "SCH002"
+	SchemaRequired   = "SCH003"
+	SchemaPattern    = "SCH004"
+	SchemaEnum       = "SCH005"
+	SchemaRange      = "SCH006"
+	SchemaLength     = "SCH007"
+	SchemaAdditional = "SCH008"
+	SchemaOther      = "SCH000"
+	GroupCount       = "DAT001"
+	Sorted           = "DAT002"
+	Expression       = "DAT003"
+	Outlier          = "DAT004"
+	Drift            = "DAT005"
+	Geo              = "DAT006"
+	Unit             = "DAT007"
+	Script           = "DAT008"
+	RequiredIf       = "DAT009"
+	Unique           = "DAT010"
+	Completeness     = "DAT011"
+	LoadIdentifier   = "LOD001"
+	LoadFieldSize    = "LOD002"
+	LoadCharacter    = "LOD003"
+	LoadDate         = "LOD004"
+	ManifestMissing  = "MAN001"
+	ManifestHash     = "MAN002"
+	ManifestRows     = "MAN003"
+	ManifestUnlisted = "MAN004"
+	FileUnreadable   = "FIL001"
+)
+
+var catalog = map[string]Rule{
+	ColumnCount:      {ColumnCount, "structure", "Row has a different number of fields than the header"},
+	MalformedRow:     {MalformedRow, "structure", "Row cannot be parsed (e.g. a bare or unterminated quote)"},
+	Envelope:         {Envelope, "structure", "Header or trailer record is missing, misplaced or does not match the data"},
+	SepLine:          {SepLine, "structure", "File starts with an Excel \"sep=\" line declaring the delimiter (with SepLine warn or error)"},
+	ShortRow:         {ShortRow, "structure", "Row with fewer fields than the header was padded with empty values (warning)"},
+	LongRow:          {LongRow, "structure", "Row with more fields than the header had its extra values ignored (warning)"},
+	TrailingDelim:    {TrailingDelim, "structure", "Every line ends with a delimiter, adding an empty, unnamed last column (warning)"},
+	Quoting:          {Quoting, "structure", "Field is quoted against the quoting policy, or unlike the rest of its column (warning)"},
+	NoData:           {NoData, "structure", "File, or chunk of a chunked file, has a header but no data rows (warning, or error with RequireData)"},
+	Truncated:        {Truncated, "structure", "File ends mid-row, inside a quoted field or on a short last line without a line ending, as if cut off"},
+	RepeatedHeader:   {RepeatedHeader, "structure", "Data row repeats the header, as where exported files were concatenated; in chunked files, a chunk header lists the columns in another order"},
+	LineEndings:      {LineEndings, "structure", "Lines end with a carriage return alone, as in classic Mac OS files; read as line breaks (warning)"},
+	Like:             {Like, "structure", "Header or delimiter differs from the --like file's: a column is missing, extra or out of order"},
+	IncludeColumn:    {IncludeColumn, "structure", "Column to include in findings (--include-columns) is not in the header; findings carry no value for it (warning)"},
+	InvalidUTF8:      {InvalidUTF8, "encoding", "Row is not valid UTF-8"},
+	SchemaFormat:     {SchemaFormat, "schema", "Value does not match the schema format (email, date, uri, ...)"},
+	SchemaType:       {SchemaType, "schema", "Value does not have the schema type"},
+	SchemaRequired:   {SchemaRequired, "schema", "Required column is missing"},
+	SchemaPattern:    {SchemaPattern, "schema", "Value does not match the schema pattern"},
+	SchemaEnum:       {SchemaEnum, "schema", "Value is not one of the allowed values (enum, const)"},
+	SchemaRange:      {SchemaRange, "schema", "Number is outside the allowed range (minimum, maximum, multipleOf)"},
+	SchemaLength:     {SchemaLength, "schema", "Value is too short or too long (minLength, maxLength)"},
+	SchemaAdditional: {SchemaAdditional, "schema", "Column is not allowed by the schema (additionalProperties); reported once, on the header, for columns rejected whatever their values"},
+	SchemaOther:      {SchemaOther, "schema", "Any other schema constraint"},
+	GroupCount:       {GroupCount, "data", "Group of rows sharing a key has too few or too many matching rows"},
+	Sorted:           {Sorted, "data", "Column is out of order, or repeats a value that must be unique"},
+	Expression:       {Expression, "data", "Row breaks an assertion over computed column values"},
+	Outlier:          {Outlier, "data", "Numeric value is far outside the rest of its column (warning)"},
+	Drift:            {Drift, "data", "Column distribution drifted from the --profile baseline (warning)"},
+	Geo:              {Geo, "data", "Coordinates are out of range or half missing, or a geometry is not valid WKT or GeoJSON"},
+	Unit:             {Unit, "data", "Value with a currency or unit cannot be parsed, has a unit that is not allowed, or is out of range"},
+	Script:           {Script, "data", "Text contains characters outside the allowed scripts, or emoji"},
+	RequiredIf:       {RequiredIf, "data", "Column is empty on a row where a required_if condition makes it required"},
+	Unique:           {Unique, "data", "Row repeats the key, or the whole content, of an earlier row"},
+	Completeness:     {Completeness, "data", "Column is non-empty in fewer rows than its completeness rule requires"},
+	LoadIdentifier:   {LoadIdentifier, "load", "Column name is not a valid identifier for the --target database"},
+	LoadFieldSize:    {LoadFieldSize, "load", "Field is larger than the --target database allows"},
+	LoadCharacter:    {LoadCharacter, "load", "Field contains a character the --target database's loader rejects"},
+	LoadDate:         {LoadDate, "load", "Date is not in a format the --target database's loader accepts"},
+	ManifestMissing:  {ManifestMissing, "manifest", "File listed in the manifest is missing"},
+	ManifestHash:     {ManifestHash, "manifest", "File's SHA-256 does not match the manifest"},
+	ManifestRows:     {ManifestRows, "manifest", "File's row count does not match the manifest"},
+	ManifestUnlisted: {ManifestUnlisted, "manifest", "Validated file is not listed in the manifest (warning)"},
+	FileUnreadable:   {FileUnreadable, "file", "File cannot be opened or parsed"},
+}
+
+// schemaKeywords maps JSON Schema keywords to rule IDs.
+var schemaKeywords = map[string]string{
+	"format":               SchemaFormat,
+	"type":                 SchemaType,
+	"required":             SchemaRequired,
+	"pattern":              SchemaPattern,
+	"enum":                 SchemaEnum,
+	"const":                SchemaEnum,
+	"minimum":              SchemaRange,
+	"maximum":              SchemaRange,
+	"exclusiveMinimum":     SchemaRange,
+	"exclusiveMaximum":     SchemaRange,
+	"multipleOf":           SchemaRange,
+	"minLength":            SchemaLength,
+	"maxLength":            SchemaLength,
+	"additionalProperties": SchemaAdditional,
+}
+
+// ForSchemaKeyword returns the rule ID for a failed JSON Schema keyword.
+func ForSchemaKeyword(keyword string) string {
+	if id, ok := schemaKeywords[keyword]; ok {
+		return id
+	}
+	return SchemaOther
+}
+
+// Lookup returns the rule with id.
+func Lookup(id string) (Rule, bool) {
+	r, ok := catalog[id]
+	return r, ok
+}
+
+// All returns every rule, sorted by ID.
+func All() []Rule {
+	out := make([]Rule, 0, len(catalog))
+	for _, r := range catalog {
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
@@ -0,0 +1,34 @@
+package rules
+
+import "testing"
+
+func TestForSchemaKeyword(t *testing.T) {
+	cases := map[string]string{
+		"format":    SchemaFormat,
+		"maxLength": SchemaLength,
+		"const":     SchemaEnum,
+		"oneOf":     SchemaOther,
+	}
+	for keyword, want := range cases {
+		if got := ForSchemaKeyword(keyword); got != want {
+			t.Errorf("ForSchemaKeyword(%q) = %s, want %s", keyword, got, want)
+		}
+	}
+}
+
+func TestCatalog(t *testing.T) {
+	all := All()
+	for i, r := range all {
+		if i > 0 && all[i-1].ID >= r.ID {
+			t.Errorf("Expected rules sorted by ID, got %s after %s", r.ID, all[i-1].ID)
+		}
+		if r.Type == "" || r.Description == "" {
+			t.Errorf("Rule %s is missing a type or description", r.ID)
+		}
+	}
+	for _, id := range schemaKeywords {
+		if _, ok := Lookup(id); !ok {
+			t.Errorf("Keyword rule %s is not in the catalog", id)
+		}
+	}
+}
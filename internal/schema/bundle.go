@@ -0,0 +1,209 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Keywords whose values are maps of names to subschemas, and keywords whose values are
+// data rather than subschemas; Bundle only rewrites $refs in subschemas.
+var (
+	schemaMaps = map[string]bool{"properties": true, "patternProperties": true, "$defs": true, "definitions": true, "dependentSchemas": true}
+	dataKeys   = map[string]bool{"enum": true, "const": true, "default": true, "examples": true}
+)
+
+// Bundle returns the schema at path with the files its $refs point to, and the files
+// those point to, embedded under $defs ("definitions" before draft 2019-09), and the
+// references rewritten to point there, so the schema can be used without file access.
+// Embedded files lose their $id and $schema. References to anchors are not supported.
+// Options such as WithRoot and WithSource apply as when compiling.
+func Bundle(schemaPath string, opts ...Option) ([]byte, error) {
+	s := &settings{source: schemaPath}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.source == "" {
+		s.source = schemaPath
+	}
+	location, root, err := s.location()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate schema: %w", err)
+	}
+	b := &bundler{load: s.loader(root, io.Discard), names: map[string]string{location: ""}, defs: map[string]any{}}
+	// The schema itself need not be in the root, as when compiling it
+	doc, err := decode(s.loader("", io.Discard), location)
+	if err != nil {
+		return nil, err
+	}
+	top, isObject := doc.(map[string]any)
+	b.key = "$defs"
+	if isObject {
+		if draft, _ := top["$schema"].(string); strings.Contains(draft, "draft-0") {
+			b.key = "definitions"
+		}
+		b.taken, _ = top[b.key].(map[string]any)
+	}
+	if err := b.rewrite(doc, location); err != nil {
+		return nil, err
+	}
+	if len(b.defs) > 0 {
+		if !isObject {
+			return nil, fmt.Errorf("cannot bundle %s: the files it references can only be embedded in an object schema", schemaPath)
+		}
+		defs, _ := top[b.key].(map[string]any)
+		if defs == nil {
+			defs = make(map[string]any, len(b.defs))
+		}
+		for name, d := range b.defs {
+			defs[name] = d
+		}
+		top[b.key] = defs
+	}
+
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	// Every reference is now local; compiling checks that the pointers are right
+	if _, err := compile(out, nil); err != nil {
+		return nil, fmt.Errorf("bundled schema does not compile: %w", err)
+	}
+	return append(out, '\n'), nil
+}
+
+// bundler collects the documents referenced from a schema.
+type bundler struct {
+	load  func(string) (io.ReadCloser, error)
+	key   string            // Where documents are embedded: "$defs" or "definitions"
+	names map[string]string // Document URL -> name under key; "" for the bundled schema
+	defs  map[string]any    // Name -> embedded document
+	taken map[string]any    // The bundled schema's own definitions, whose names are taken
+}
+
+// decode loads and decodes the document at a file URL, keeping numbers as written.
+func decode(load func(string) (io.ReadCloser, error), docURL string) (any, error) {
+	r, err := load(docURL)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", docURL, err)
+	}
+	return doc, nil
+}
+
+// rewrite points the $refs in the subschema node, of the document at base, into the
+// bundle, embedding the documents they reference.
+func (b *bundler) rewrite(node any, base string) error {
+	switch n := node.(type) {
+	case []any:
+		for _, v := range n {
+			if err := b.rewrite(v, base); err != nil {
+				return err
+			}
+		}
+	case map[string]any:
+		for k, v := range n {
+			switch {
+			case dataKeys[k]:
+			case k == "$ref":
+				ref, ok := v.(string)
+				if !ok {
+					continue
+				}
+				local, err := b.ref(ref, base)
+				if err != nil {
+					return err
+				}
+				n[k] = local
+			case schemaMaps[k]:
+				if m, ok := v.(map[string]any); ok {
+					for _, sub := range m {
+						if err := b.rewrite(sub, base); err != nil {
+							return err
+						}
+					}
+				}
+			default:
+				if err := b.rewrite(v, base); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// ref returns the local reference for ref, made in the document at base.
+func (b *bundler) ref(ref, base string) (string, error) {
+	u, err := url.Parse(ref)
+	if err != nil {
+		return "", fmt.Errorf("invalid $ref %s: %w", ref, err)
+	}
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	target := baseURL.ResolveReference(u)
+	fragment := target.Fragment
+	if fragment != "" && !strings.HasPrefix(fragment, "/") {
+		return "", fmt.Errorf("cannot bundle $ref %s: anchors are not supported, use a JSON pointer", ref)
+	}
+	target.Fragment = ""
+	name, err := b.document(target.String())
+	if err != nil {
+		return "", err
+	}
+	if name == "" {
+		return "#" + fragment, nil
+	}
+	return "#/" + b.key + "/" + name + fragment, nil
+}
+
+// document returns the name of the document at docURL in the bundle, embedding it and
+// the documents it references when it is new.
+func (b *bundler) document(docURL string) (string, error) {
+	if name, ok := b.names[docURL]; ok {
+		return name, nil
+	}
+	doc, err := decode(b.load, docURL)
+	if err != nil {
+		return "", err
+	}
+	name := b.name(docURL)
+	// Named before its references are followed, so cycles end here
+	b.names[docURL] = name
+	if m, ok := doc.(map[string]any); ok {
+		delete(m, "$id")
+		delete(m, "$schema")
+	}
+	b.defs[name] = doc
+	return name, b.rewrite(doc, docURL)
+}
+
+var unsafeName = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// name derives an unused definition name from a document's file name.
+func (b *bundler) name(docURL string) string {
+	u, _ := url.Parse(docURL)
+	base := strings.TrimSuffix(strings.TrimSuffix(path.Base(u.Path), ".json"), ".schema")
+	base = unsafeName.ReplaceAllString(base, "_")
+	if base == "" {
+		base = "schema"
+	}
+	name := base
+	for i := 2; b.defs[name] != nil || b.taken[name] != nil; i++ {
+		name = base + "_" + strconv.Itoa(i)
+	}
+	return name
+}
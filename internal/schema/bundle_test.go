@@ -0,0 +1,73 @@
+package schema
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBundle(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"orders.schema.json": `{"$schema":"https://json-schema.org/draft/2020-12/schema","type":"object","properties":{
+			"country":{"$ref":"shared/types.json#/$defs/country"},
+			"total":{"$ref":"shared/money.json"},
+			"status":{"enum":[{"$ref":"not a reference"}]},
+			"note":{"$ref":"#/$defs/note"}},
+			"$defs":{"note":{"type":"string","maxLength":20}}}`,
+		"shared/types.json": `{"$id":"https://example.com/types.json","$defs":{"country":{"type":"string","pattern":"^[A-Z]{2}$"},"code":{"$ref":"#/$defs/country"}}}`,
+		"shared/money.json": `{"type":"string","pattern":"^[0-9]+\\.[0-9]{2}$","$defs":{"c":{"$ref":"types.json#/$defs/code"}}}`,
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	bundled, err := Bundle(filepath.Join(dir, "orders.schema.json"))
+	if err != nil {
+		t.Fatalf("Bundle failed: %v", err)
+	}
+	for _, want := range []string{
+		`"$ref": "#/$defs/types/$defs/country"`,
+		`"$ref": "#/$defs/money"`,
+		`"$ref": "not a reference"`,
+		`"$ref": "#/$defs/note"`,
+		`"$ref": "#/$defs/types/$defs/code"`,
+	} {
+		if !strings.Contains(string(bundled), want) {
+			t.Errorf("Expected %s in the bundle:\n%s", want, bundled)
+		}
+	}
+	if strings.Contains(string(bundled), "example.com") {
+		t.Errorf("Expected the $id of embedded files dropped:\n%s", bundled)
+	}
+
+	// The bundle validates like the original, from anywhere
+	v, err := NewValidatorFromReader(bytes.NewReader(bundled), WithRoot(t.TempDir()))
+	if err != nil {
+		t.Fatalf("bundle does not compile on its own: %v", err)
+	}
+	errs, err := v.ValidateRow([]string{"country", "total", "note"}, []string{"Germany", "12.5", "ok"})
+	if err != nil || len(errs) != 2 {
+		t.Errorf("Expected the referenced patterns to apply, got %+v (%v)", errs, err)
+	}
+
+	if _, err := Bundle(filepath.Join(dir, "orders.schema.json"), WithRoot(filepath.Join(dir, "other"))); err == nil {
+		t.Error("Expected references outside the root rejected")
+	}
+
+	// References need an object to be embedded in
+	listPath := filepath.Join(dir, "list.schema.json")
+	if err := os.WriteFile(listPath, []byte(`[{"$ref":"shared/money.json"}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Bundle(listPath); err == nil || !strings.Contains(err.Error(), "object schema") {
+		t.Errorf("Expected a schema that is not an object rejected, got %v", err)
+	}
+}
@@ -0,0 +1,144 @@
+package schema
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// Dataset validates a whole file as a single instance, a JSON array of row objects, for
+// schemas written at dataset scope with keywords such as minItems, uniqueItems or
+// contains. Rows are converted with the types of the schema's items.
+type Dataset struct {
+	schema *jsonschema.Schema
+	hash   string
+	rows   *Validator // Converts rows to objects; its schema is the items schema
+}
+
+// DatasetError is a finding of a dataset schema.
+type DatasetError struct {
+	Row int // 0-based index of the row the error is about, -1 for the whole file
+	ValidationError
+}
+
+// NewDatasetFromReader compiles a dataset schema.
+func NewDatasetFromReader(r io.Reader, opts ...Option) (*Dataset, error) {
+	schemaBytes, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema: %w", err)
+	}
+	v, err := compile(schemaBytes, opts)
+	if err != nil {
+		return nil, err
+	}
+	items := &jsonschema.Schema{}
+	if s := itemsSchema(v.schema); s != nil {
+		items = s
+	}
+	return &Dataset{schema: v.schema, hash: v.hash, rows: newValidator(items, v.hash)}, nil
+}
+
+// itemsSchema returns the schema of the array items, following references.
+func itemsSchema(s *jsonschema.Schema) *jsonschema.Schema {
+	for s != nil && s.Ref != nil && s.Items2020 == nil && s.Items == nil {
+		s = s.Ref
+	}
+	if s == nil {
+		return nil
+	}
+	items := s.Items2020
+	if items == nil {
+		items, _ = s.Items.(*jsonschema.Schema)
+	}
+	for items != nil && items.Ref != nil && len(items.Properties) == 0 {
+		items = items.Ref
+	}
+	return items
+}
+
+// WithCoercers returns a dataset validator converting row values with the coercers; see
+// Validator.WithCoercers.
+func (d *Dataset) WithCoercers(coercers ...Coercer) *Dataset {
+	c := *d
+	c.rows = d.rows.WithCoercers(coercers...)
+	return &c
+}
+
+// WithEmptyValues returns a dataset validator passing empty cells as set; see
+// Validator.WithEmptyValues.
+func (d *Dataset) WithEmptyValues(mode string, columns map[string]string) *Dataset {
+	c := *d
+	c.rows = d.rows.WithEmptyValues(mode, columns)
+	return &c
+}
+
+// WithTypes returns a dataset validator converting the values of columns to types; see
+// Validator.WithTypes.
+func (d *Dataset) WithTypes(types map[string]string) *Dataset {
+	c := *d
+	c.rows = d.rows.WithTypes(types)
+	return &c
+}
+
+// Hash identifies the schema source.
+func (d *Dataset) Hash() string {
+	return d.hash
+}
+
+// RowObject converts a row to the object added to the dataset.
+func (d *Dataset) RowObject(headers []string, data []string) map[string]interface{} {
+	return d.rows.RowObject(headers, data)
+}
+
+// Validate validates the rows, as returned by RowObject, as one array. Findings inside a
+// row name the row and its field; findings about the array, such as minItems, have Row -1.
+func (d *Dataset) Validate(rows []interface{}) ([]DatasetError, error) {
+	err := d.schema.Validate(rows)
+	if err == nil {
+		return nil, nil
+	}
+	validationErr, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return nil, fmt.Errorf("schema validation error: %w", err)
+	}
+	var out []DatasetError
+	for _, leaf := range leaves(validationErr) {
+		e := DatasetError{Row: -1, ValidationError: ValidationError{
+			Message: leaf.Message,
+			Keyword: path.Base(leaf.KeywordLocation),
+		}}
+		if containsKeywords[e.Keyword] {
+			e.Message = fmt.Sprintf("rows matching contains: %s", leaf.Message)
+		}
+		index, field, _ := strings.Cut(strings.TrimPrefix(leaf.InstanceLocation, "/"), "/")
+		if i, err := strconv.Atoi(index); err == nil && i < len(rows) {
+			e.Row, e.Field = i, field
+			if row, ok := rows[i].(map[string]interface{}); ok && row[field] != nil {
+				e.Value = fmt.Sprintf("%v", row[field])
+			}
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+// containsKeywords report how many rows match contains; the library reports a plain
+// contains failing as minContains.
+var containsKeywords = map[string]bool{"contains": true, "minContains": true, "maxContains": true}
+
+// leaves returns the errors without causes, which describe the actual violations. A
+// failed contains is a violation of its own: its causes are the rows that do not match.
+func leaves(err *jsonschema.ValidationError) []*jsonschema.ValidationError {
+	if len(err.Causes) == 0 || containsKeywords[path.Base(err.KeywordLocation)] {
+		return []*jsonschema.ValidationError{err}
+	}
+	var out []*jsonschema.ValidationError
+	for _, cause := range err.Causes {
+		out = append(out, leaves(cause)...)
+	}
+	return out
+}
@@ -0,0 +1,49 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDataset(t *testing.T) {
+	for _, draft := range []string{"http://json-schema.org/draft-07/schema#", "https://json-schema.org/draft/2020-12/schema"} {
+		d, err := NewDatasetFromReader(strings.NewReader(`{
+			"$schema": "` + draft + `",
+			"type": "array",
+			"minItems": 4,
+			"uniqueItems": true,
+			"contains": {"properties": {"role": {"const": "admin"}}, "required": ["role"]},
+			"items": {"$ref": "#/definitions/row"},
+			"definitions": {"row": {"properties": {"id": {"type": "integer"}, "role": {"type": "string"}}}}
+		}`))
+		if err != nil {
+			t.Fatalf("%s: NewDatasetFromReader: %v", draft, err)
+		}
+		headers := []string{"id", "role"}
+		var rows []interface{}
+		for _, r := range [][]string{{"1", "user"}, {"x", "user"}, {"1", "user"}} {
+			rows = append(rows, d.RowObject(headers, r))
+		}
+		if obj := rows[0].(map[string]interface{}); obj["id"] != 1 {
+			t.Errorf("%s: expected ids typed by the items schema, got %#v", draft, obj)
+		}
+		errs, err := d.Validate(rows)
+		if err != nil {
+			t.Fatalf("%s: Validate: %v", draft, err)
+		}
+		var got []string
+		for _, e := range errs {
+			got = append(got, fmt.Sprintf("%d:%s:%s:%s", e.Row, e.Field, e.Keyword, e.Value))
+		}
+		want := map[string]bool{"-1::minItems:": true, "-1::uniqueItems:": true, "-1::minContains:": true, "1:id:type:x": true}
+		if len(got) != len(want) {
+			t.Errorf("%s: expected %d errors, got %v", draft, len(want), got)
+		}
+		for _, g := range got {
+			if !want[g] {
+				t.Errorf("%s: unexpected error %s in %v", draft, g, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Column describes one property of a row schema, for generating code or DDL from it.
+type Column struct {
+	Name      string
+	Type      string // integer, number, boolean or string; mixed types such as ["integer", "string"] are string
+	Nullable  bool   // The type list includes null
+	Required  bool
+	Format    string
+	MaxLength int // 0 when not set
+}
+
+// Description is the shape of a row schema: its properties and primary key.
+type Description struct {
+	Columns    map[string]Column
+	Names      []string // Property names, sorted
+	PrimaryKey []string // From x-primaryKey, a column name or a list of them
+}
+
+// Column returns the named column, or an optional string column when the schema does
+// not describe it.
+func (d *Description) Column(name string) Column {
+	if c, ok := d.Columns[name]; ok {
+		return c
+	}
+	return Column{Name: name, Type: "string"}
+}
+
+type describeDocument struct {
+	Properties map[string]struct {
+		Type      json.RawMessage `json:"type"`
+		Format    string          `json:"format"`
+		MaxLength int             `json:"maxLength"`
+	} `json:"properties"`
+	Required   []string        `json:"required"`
+	PrimaryKey json.RawMessage `json:"x-primaryKey"`
+}
+
+// Describe reads the properties, required columns and x-primaryKey of a JSON Schema.
+func Describe(schemaJSON []byte) (*Description, error) {
+	var doc describeDocument
+	if err := json.Unmarshal(schemaJSON, &doc); err != nil {
+		return nil, fmt.Errorf("parsing schema: %w", err)
+	}
+	d := &Description{Columns: make(map[string]Column, len(doc.Properties))}
+	required := make(map[string]bool, len(doc.Required))
+	for _, name := range doc.Required {
+		required[name] = true
+	}
+	for name, p := range doc.Properties {
+		types, err := stringOrList(p.Type)
+		if err != nil {
+			return nil, fmt.Errorf("property %s: type must be a string or a list of strings", name)
+		}
+		c := Column{Name: name, Type: "string", Required: required[name], Format: p.Format, MaxLength: p.MaxLength}
+		var kinds []string
+		for _, t := range types {
+			if t == "null" {
+				c.Nullable = true
+			} else {
+				kinds = append(kinds, t)
+			}
+		}
+		if len(kinds) == 1 {
+			c.Type = kinds[0]
+		}
+		d.Columns[name] = c
+		d.Names = append(d.Names, name)
+	}
+	sort.Strings(d.Names)
+	key, err := stringOrList(doc.PrimaryKey)
+	if err != nil {
+		return nil, fmt.Errorf("x-primaryKey must be a column name or a list of them")
+	}
+	d.PrimaryKey = key
+	return d, nil
+}
+
+// primaryKey returns the columns of the x-primaryKey of a JSON Schema. A malformed
+// x-primaryKey gives nil here; Describe reports it.
+func primaryKey(schemaJSON []byte) []string {
+	var doc struct {
+		PrimaryKey json.RawMessage `json:"x-primaryKey"`
+	}
+	if err := json.Unmarshal(schemaJSON, &doc); err != nil {
+		return nil
+	}
+	key, _ := stringOrList(doc.PrimaryKey)
+	return key
+}
+
+// stringOrList decodes a JSON string or list of strings; empty input gives nil.
+func stringOrList(raw json.RawMessage) ([]string, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	var one string
+	if err := json.Unmarshal(raw, &one); err == nil {
+		return []string{one}, nil
+	}
+	var many []string
+	if err := json.Unmarshal(raw, &many); err != nil {
+		return nil, err
+	}
+	return many, nil
+}
@@ -0,0 +1,40 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDescribe(t *testing.T) {
+	d, err := Describe([]byte(`{
+  "required": ["id"],
+  "x-primaryKey": "id",
+  "properties": {
+    "id": {"type": "integer"},
+    "note": {"type": ["string", "null"], "maxLength": 20},
+    "code": {"type": ["integer", "string"]}
+  }
+}`))
+	if err != nil {
+		t.Fatalf("Describe failed: %v", err)
+	}
+	if !reflect.DeepEqual(d.Names, []string{"code", "id", "note"}) || !reflect.DeepEqual(d.PrimaryKey, []string{"id"}) {
+		t.Errorf("Unexpected names %v or key %v", d.Names, d.PrimaryKey)
+	}
+	if c := d.Column("id"); c.Type != "integer" || !c.Required || c.Nullable {
+		t.Errorf("Unexpected id column %+v", c)
+	}
+	if c := d.Column("note"); c.Type != "string" || !c.Nullable || c.MaxLength != 20 {
+		t.Errorf("Unexpected note column %+v", c)
+	}
+	if c := d.Column("code"); c.Type != "string" {
+		t.Errorf("Expected mixed types to describe a string, got %+v", c)
+	}
+	if c := d.Column("other"); c.Type != "string" || c.Required {
+		t.Errorf("Expected an undescribed column to be an optional string, got %+v", c)
+	}
+
+	if _, err := Describe([]byte(`{"properties": {"a": {"type": 1}}}`)); err == nil {
+		t.Error("Expected an error for an invalid type")
+	}
+}
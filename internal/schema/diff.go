@@ -0,0 +1,263 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Change is a difference between two versions of a row schema. A change is breaking
+// when rows valid under the old schema may be invalid under the new one.
+type Change struct {
+	Column   string `json:"column,omitempty"` // Empty for changes to the whole row
+	Breaking bool   `json:"breaking"`
+	Message  string `json:"message"`
+}
+
+// diffDocument is the part of a row schema Diff compares; property keywords stay raw.
+type diffDocument struct {
+	Properties           map[string]map[string]json.RawMessage `json:"properties"`
+	Required             []string                              `json:"required"`
+	AdditionalProperties json.RawMessage                       `json:"additionalProperties"`
+}
+
+// Diff compares the top-level properties, required columns and additionalProperties of
+// two row schemas, and returns the changes sorted by column, breaking changes first.
+// Changes to keywords it cannot compare, such as a new pattern, count as breaking.
+func Diff(oldJSON, newJSON []byte) ([]Change, error) {
+	var before, after diffDocument
+	if err := json.Unmarshal(oldJSON, &before); err != nil {
+		return nil, fmt.Errorf("parsing old schema: %w", err)
+	}
+	if err := json.Unmarshal(newJSON, &after); err != nil {
+		return nil, fmt.Errorf("parsing new schema: %w", err)
+	}
+
+	var changes []Change
+	add := func(column string, breaking bool, format string, args ...any) {
+		changes = append(changes, Change{Column: column, Breaking: breaking, Message: fmt.Sprintf(format, args...)})
+	}
+
+	closedBefore, closedAfter := closed(before.AdditionalProperties), closed(after.AdditionalProperties)
+	if !closedBefore && closedAfter {
+		add("", true, "additional columns are no longer allowed")
+	} else if closedBefore && !closedAfter {
+		add("", false, "additional columns are now allowed")
+	}
+
+	names := make(map[string]bool)
+	for name := range before.Properties {
+		names[name] = true
+	}
+	for name := range after.Properties {
+		names[name] = true
+	}
+	for _, name := range append(before.Required, after.Required...) {
+		names[name] = true
+	}
+	for name := range names {
+		oldProp, inOld := before.Properties[name]
+		newProp, inNew := after.Properties[name]
+		wasRequired, isRequired := slices.Contains(before.Required, name), slices.Contains(after.Required, name)
+		switch {
+		case !inOld && inNew:
+			add(name, false, "column added")
+		case inOld && !inNew && closedAfter:
+			add(name, true, "column removed while additional columns are not allowed")
+		case inOld && !inNew:
+			add(name, false, "column removed")
+		case inOld && inNew:
+			diffProperty(name, oldProp, newProp, add)
+		}
+		if !wasRequired && isRequired {
+			add(name, true, "column is now required")
+		} else if wasRequired && !isRequired {
+			add(name, false, "column is no longer required")
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Column != changes[j].Column {
+			return changes[i].Column < changes[j].Column
+		}
+		return changes[i].Breaking && !changes[j].Breaking
+	})
+	return changes, nil
+}
+
+// closed reports whether additionalProperties is false.
+func closed(raw json.RawMessage) bool {
+	return string(bytes.TrimSpace(raw)) == "false"
+}
+
+// diffProperty compares the keywords of one property.
+func diffProperty(name string, before, after map[string]json.RawMessage, add func(string, bool, string, ...any)) {
+	// Types; no type accepts any value, and integers are numbers
+	oldTypes, _ := stringOrList(before["type"])
+	newTypes, _ := stringOrList(after["type"])
+	narrowed := slices.ContainsFunc(anyType(oldTypes), func(t string) bool { return !acceptsType(newTypes, t) })
+	widened := slices.ContainsFunc(anyType(newTypes), func(t string) bool { return !acceptsType(oldTypes, t) })
+	switch {
+	case narrowed && widened:
+		add(name, true, "type changed from %s to %s", typeList(oldTypes), typeList(newTypes))
+	case narrowed:
+		add(name, true, "type narrowed from %s to %s", typeList(oldTypes), typeList(newTypes))
+	case widened:
+		add(name, false, "type widened from %s to %s", typeList(oldTypes), typeList(newTypes))
+	}
+
+	// Allowed values; const is an enum of one value
+	oldValues, oldHas := allowedValues(before)
+	newValues, newHas := allowedValues(after)
+	switch {
+	case !oldHas && newHas:
+		add(name, true, "values restricted to %s", strings.Join(newValues, ", "))
+	case oldHas && !newHas:
+		add(name, false, "values no longer restricted")
+	case oldHas && newHas:
+		for _, v := range oldValues {
+			if !slices.Contains(newValues, v) {
+				add(name, true, "allowed value %s removed", v)
+			}
+		}
+		for _, v := range newValues {
+			if !slices.Contains(oldValues, v) {
+				add(name, false, "allowed value %s added", v)
+			}
+		}
+	}
+
+	// Keywords that cannot be compared: any new or changed value may reject old rows
+	for _, keyword := range []string{"pattern", "format", "multipleOf"} {
+		o, n := compact(before[keyword]), compact(after[keyword])
+		switch {
+		case o == n:
+		case n == "":
+			add(name, false, "%s %s removed", keyword, o)
+		case o == "":
+			add(name, true, "%s %s added", keyword, n)
+		default:
+			add(name, true, "%s changed from %s to %s", keyword, o, n)
+		}
+	}
+
+	// Bounds: a larger lower bound or a smaller upper bound is breaking
+	for _, b := range []struct {
+		keyword string
+		lower   bool
+	}{
+		{"minimum", true}, {"exclusiveMinimum", true}, {"minLength", true},
+		{"maximum", false}, {"exclusiveMaximum", false}, {"maxLength", false},
+	} {
+		o, oldOK := number(before[b.keyword])
+		n, newOK := number(after[b.keyword])
+		switch {
+		case compact(before[b.keyword]) != compact(after[b.keyword]) && (!oldOK && len(before[b.keyword]) > 0 || !newOK && len(after[b.keyword]) > 0):
+			// A bound that is not a number, as draft-04's boolean exclusiveMinimum
+			add(name, true, "%s changed (not classified)", b.keyword)
+		case !oldOK && !newOK || oldOK && newOK && o == n:
+		case !newOK:
+			add(name, false, "%s %s removed", b.keyword, formatFloat(o))
+		case !oldOK:
+			add(name, true, "%s %s added", b.keyword, formatFloat(n))
+		case b.lower == (n > o):
+			add(name, true, "%s tightened from %s to %s", b.keyword, formatFloat(o), formatFloat(n))
+		default:
+			add(name, false, "%s relaxed from %s to %s", b.keyword, formatFloat(o), formatFloat(n))
+		}
+	}
+
+	// Other keywords are not compared: any change may reject old rows
+	var others []string
+	for _, p := range []map[string]json.RawMessage{before, after} {
+		for keyword := range p {
+			if !diffedKeywords[keyword] && !slices.Contains(others, keyword) && compact(before[keyword]) != compact(after[keyword]) {
+				others = append(others, keyword)
+			}
+		}
+	}
+	slices.Sort(others)
+	for _, keyword := range others {
+		add(name, true, "%s changed (not classified)", keyword)
+	}
+}
+
+// diffedKeywords are the keywords diffProperty compares, and annotations, which do not
+// change which values are valid.
+var diffedKeywords = map[string]bool{
+	"type": true, "enum": true, "const": true, "pattern": true, "format": true, "multipleOf": true,
+	"minimum": true, "exclusiveMinimum": true, "minLength": true, "maximum": true, "exclusiveMaximum": true, "maxLength": true,
+	"title": true, "description": true, "default": true, "examples": true, "$comment": true,
+	"deprecated": true, "readOnly": true, "writeOnly": true,
+}
+
+// jsonTypes are the types a property without a type accepts.
+var jsonTypes = []string{"array", "boolean", "integer", "null", "number", "object", "string"}
+
+// anyType returns types, or every type when the list is empty.
+func anyType(types []string) []string {
+	if len(types) == 0 {
+		return jsonTypes
+	}
+	return types
+}
+
+// acceptsType reports whether a property with types accepts values of type t.
+func acceptsType(types []string, t string) bool {
+	return len(types) == 0 || slices.Contains(types, t) || t == "integer" && slices.Contains(types, "number")
+}
+
+// typeList formats types for messages.
+func typeList(types []string) string {
+	if len(types) == 0 {
+		return "any"
+	}
+	return strings.Join(types, "|")
+}
+
+// allowedValues returns the enum or const values of a property as compact JSON.
+func allowedValues(p map[string]json.RawMessage) ([]string, bool) {
+	if c, ok := p["const"]; ok {
+		return []string{compact(c)}, true
+	}
+	raw, ok := p["enum"]
+	if !ok {
+		return nil, false
+	}
+	var values []json.RawMessage
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return nil, false
+	}
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = compact(v)
+	}
+	return out, true
+}
+
+// compact returns raw JSON without insignificant whitespace, or "" when absent.
+func compact(raw json.RawMessage) string {
+	var b bytes.Buffer
+	if len(raw) == 0 || json.Compact(&b, raw) != nil {
+		return string(raw)
+	}
+	return b.String()
+}
+
+// number decodes a numeric keyword; booleans, as in draft-04 exclusiveMinimum, are not
+// compared.
+func number(raw json.RawMessage) (float64, bool) {
+	var f float64
+	if len(raw) == 0 || json.Unmarshal(raw, &f) != nil {
+		return 0, false
+	}
+	return f, true
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,80 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	before := `{
+  "required": ["id"],
+  "properties": {
+    "id": {"type": "integer", "minimum": 1},
+    "status": {"enum": ["open", "pending", "closed"]},
+    "amount": {"type": ["number", "null"], "maximum": 1000},
+    "email": {"type": "string"},
+    "code": {"type": "string", "pattern": "^[A-Z]+$"},
+    "notes": {"type": "string"},
+    "tags": {"type": "array", "maxItems": 5, "description": "labels"}
+  }
+}`
+	after := `{
+  "required": ["id", "email"],
+  "additionalProperties": false,
+  "properties": {
+    "id": {"type": "number", "minimum": 1},
+    "status": {"enum": ["open", "closed", "archived"]},
+    "amount": {"type": "number", "maximum": 500},
+    "email": {"type": "string", "format": "email"},
+    "code": {"type": "string"},
+    "country": {"const": "DE"},
+    "tags": {"type": "array", "maxItems": 3, "uniqueItems": true, "description": "Labels"}
+  }
+}`
+	changes, err := Diff([]byte(before), []byte(after))
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	var got []string
+	for _, c := range changes {
+		got = append(got, fmt.Sprintf("%t %s: %s", c.Breaking, c.Column, c.Message))
+	}
+	want := []string{
+		"true : additional columns are no longer allowed",
+		"true amount: type narrowed from number|null to number",
+		"true amount: maximum tightened from 1000 to 500",
+		`false code: pattern "^[A-Z]+$" removed`,
+		"false country: column added",
+		`true email: format "email" added`,
+		"true email: column is now required",
+		"false id: type widened from integer to number",
+		"true notes: column removed while additional columns are not allowed",
+		`true status: allowed value "pending" removed`,
+		`false status: allowed value "archived" added`,
+		"true tags: maxItems changed (not classified)",
+		"true tags: uniqueItems changed (not classified)",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unexpected changes:\n got %q\nwant %q", got, want)
+	}
+
+	// The reverse direction
+	changes, _ = Diff([]byte(after), []byte(before))
+	breaking := 0
+	for _, c := range changes {
+		if c.Breaking {
+			breaking++
+		}
+	}
+	if breaking != 5 {
+		t.Errorf("Expected the new pattern, the narrowed id type, the removed archived value and the tags keywords as breaking, got %+v", changes)
+	}
+
+	if changes, _ := Diff([]byte(before), []byte(before)); len(changes) != 0 {
+		t.Errorf("Expected no changes between equal schemas, got %+v", changes)
+	}
+	if _, err := Diff([]byte(before), []byte("{")); err == nil {
+		t.Error("Expected an error for invalid JSON")
+	}
+}
@@ -140,6 +140,17 @@ func hasNonEmpty(sample [][]string, colIndex int) bool {
 // Types are inferred per column (string, integer, number, boolean); when in doubt, string is used.
 // Columns with at least one non-empty value in the sample are required.
 func Infer(headers []string, sample [][]string) ([]byte, error) {
+	return infer(headers, sample, true)
+}
+
+// InferTypes is Infer without required columns, and open to other columns: a schema of
+// the columns' types and formats alone, for callers that check the header themselves.
+func InferTypes(headers []string, sample [][]string) ([]byte, error) {
+	return infer(headers, sample, false)
+}
+
+// infer produces the schema of Infer, or of InferTypes unless strict.
+func infer(headers []string, sample [][]string, strict bool) ([]byte, error) {
 	if len(headers) == 0 {
 		return nil, fmt.Errorf("headers cannot be empty")
 	}
@@ -153,7 +164,7 @@ func Infer(headers []string, sample [][]string) ([]byte, error) {
 			format = inferColumnFormat(values)
 		}
 		props[h] = propSchema{Type: t, Format: format}
-		if hasNonEmpty(sample, i) {
+		if strict && hasNonEmpty(sample, i) {
 			required = append(required, h)
 		}
 	}
@@ -163,7 +174,7 @@ func Infer(headers []string, sample [][]string) ([]byte, error) {
 		Type:                 "object",
 		Required:             required,
 		Properties:           props,
-		AdditionalProperties: false,
+		AdditionalProperties: !strict,
 	}
 	return json.MarshalIndent(s, "", "  ")
 }
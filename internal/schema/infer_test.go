@@ -54,6 +54,24 @@ func TestInfer_HeadersInProperties(t *testing.T) {
 	}
 }
 
+func TestInferTypes(t *testing.T) {
+	out, err := InferTypes([]string{"id", "email"}, [][]string{{"1", "a@example.com"}, {"2", ""}})
+	if err != nil {
+		t.Fatalf("InferTypes: %v", err)
+	}
+	var parsed map[string]interface{}
+	if err := json.Unmarshal(out, &parsed); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if _, ok := parsed["required"]; ok || parsed["additionalProperties"] != true {
+		t.Errorf("expected no required columns and other columns allowed, got %s", out)
+	}
+	props := parsed["properties"].(map[string]interface{})
+	if props["id"].(map[string]interface{})["type"] != "integer" || props["email"].(map[string]interface{})["format"] != "email" {
+		t.Errorf("expected the types and formats of Infer, got %s", out)
+	}
+}
+
 func TestInfer_IntegerColumn(t *testing.T) {
 	headers := []string{"id"}
 	sample := [][]string{{"1"}, {"2"}, {"3"}}
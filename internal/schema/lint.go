@@ -0,0 +1,127 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Checks run by Lint.
+const (
+	LintNaming     = "naming"                // Property name in no common header naming convention
+	LintPattern    = "pattern"               // Pattern without ^ and $, which matches anywhere in a value
+	LintType       = "type"                  // Type no CSV value can have
+	LintAdditional = "additional-properties" // No decision on columns missing from properties
+)
+
+// Issue is a schema-authoring problem found by Lint.
+type Issue struct {
+	Column  string `json:"column,omitempty"` // Empty for issues with the whole row
+	Check   string `json:"check"`
+	Message string `json:"message"`
+}
+
+// namingConventions are the header naming conventions property names are expected in.
+var namingConventions = []*regexp.Regexp{
+	regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`),            // snake_case
+	regexp.MustCompile(`^[A-Z][A-Z0-9]*(_[A-Z0-9]+)*$`),            // SCREAMING_SNAKE_CASE
+	regexp.MustCompile(`^[a-z][a-z0-9]*([A-Z][a-z0-9]*)*$`),        // camelCase
+	regexp.MustCompile(`^[A-Z][a-z0-9]*([A-Z][a-z0-9]*)*$`),        // PascalCase
+	regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)*$`),            // kebab-case
+	regexp.MustCompile(`^[A-Z][A-Za-z0-9]*( [A-Z][A-Za-z0-9]*)*$`), // Title Case
+	regexp.MustCompile(`^[A-Z][a-z0-9]*( [a-z0-9]+)*$`),            // Sentence case
+}
+
+// lintDocument is the part of a row schema Lint looks at.
+type lintDocument struct {
+	Properties            map[string]json.RawMessage `json:"properties"`
+	AdditionalProperties  json.RawMessage            `json:"additionalProperties"`
+	UnevaluatedProperties json.RawMessage            `json:"unevaluatedProperties"`
+}
+
+// Lint reports problems with a row schema that are easy to make when writing schemas
+// for CSV files, where every value is a string: property names that match no header
+// naming convention, unanchored patterns, types no value can have, and a missing
+// additionalProperties. Issues are sorted by column.
+func Lint(schemaJSON []byte) ([]Issue, error) {
+	var doc lintDocument
+	if err := json.Unmarshal(schemaJSON, &doc); err != nil {
+		return nil, fmt.Errorf("parsing schema: %w", err)
+	}
+
+	var issues []Issue
+	add := func(column, check, format string, args ...any) {
+		issues = append(issues, Issue{Column: column, Check: check, Message: fmt.Sprintf(format, args...)})
+	}
+	if len(doc.AdditionalProperties) == 0 && len(doc.UnevaluatedProperties) == 0 {
+		add("", LintAdditional, "additionalProperties is not set, so columns missing from properties are accepted silently; "+
+			"set it to false to reject them or to true to accept them on purpose")
+	}
+	for name, raw := range doc.Properties {
+		if !matchesConvention(name) {
+			add(name, LintNaming, "name '%s' follows no header naming convention (snake_case, camelCase, PascalCase, kebab-case, Title Case, ...)", name)
+		}
+		// Boolean schemas have nothing to check
+		var prop map[string]any
+		if json.Unmarshal(raw, &prop) == nil {
+			lintSubschema(prop, false, func(check, format string, args ...any) { add(name, check, format, args...) })
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Column < issues[j].Column
+	})
+	return issues, nil
+}
+
+// matchesConvention reports whether a property name is in a naming convention.
+func matchesConvention(name string) bool {
+	for _, re := range namingConventions {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// lintSubschema checks the pattern and types of a property's schema and of the schemas
+// it is composed of; those in not are meant to exclude values and are left alone. A
+// null branch of a composition, as in anyOf [integer, null], is left alone too.
+func lintSubschema(s map[string]any, composed bool, add func(check, format string, args ...any)) {
+	if pattern, ok := s["pattern"].(string); ok && (!strings.HasPrefix(pattern, "^") || !strings.HasSuffix(pattern, "$")) {
+		add(LintPattern, "pattern %s is not anchored, so it matches values that merely contain a match; wrap it in ^ and $", pattern)
+	}
+	var types []string
+	switch t := s["type"].(type) {
+	case string:
+		types = []string{t}
+	case []any:
+		for _, v := range t {
+			if name, ok := v.(string); ok {
+				types = append(types, name)
+			}
+		}
+	}
+	for _, t := range types {
+		switch t {
+		case "boolean":
+			add(LintType, "type boolean never matches unless the column is converted with types: CSV values are strings, and only integer and number are converted")
+		case "object", "array":
+			add(LintType, "type %s never matches: CSV values are strings, and only integer and number are converted", t)
+		case "null":
+			if len(types) == 1 && !composed {
+				add(LintType, "type null only matches empty values with empty_values: null")
+			}
+		}
+	}
+	for _, keyword := range []string{"allOf", "anyOf", "oneOf"} {
+		list, _ := s[keyword].([]any)
+		for _, sub := range list {
+			if m, ok := sub.(map[string]any); ok {
+				lintSubschema(m, true, add)
+			}
+		}
+	}
+}
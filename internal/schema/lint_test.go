@@ -0,0 +1,48 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	issues, err := Lint([]byte(`{
+  "properties": {
+    "order_id": {"type": "integer"},
+    "Order Date": {"type": "string", "pattern": "^[0-9]{4}-[0-9]{2}-[0-9]{2}$"},
+    "customer Name": {"type": "string"},
+    "code": {"anyOf": [{"pattern": "[A-Z]{3}"}, {"type": "null"}]},
+    "paid": {"type": "boolean"},
+    "tags": {"type": ["array", "null"]},
+    "note": {"type": "null"},
+    "extra": true
+  }
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, i := range issues {
+		got = append(got, fmt.Sprintf("%s %s", i.Column, i.Check))
+	}
+	want := []string{
+		" additional-properties",
+		"code pattern",
+		"customer Name naming",
+		"note type",
+		"paid type",
+		"tags type",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	issues, err = Lint([]byte(`{"additionalProperties": false, "properties": {"id": {"type": "integer"}, "SKU_CODE": {}, "unitPrice": {}}}`))
+	if err != nil || len(issues) != 0 {
+		t.Errorf("Expected no issues, got %+v (%v)", issues, err)
+	}
+	if _, err := Lint([]byte(`{`)); err == nil {
+		t.Error("Expected invalid JSON rejected")
+	}
+}
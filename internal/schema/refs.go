@@ -0,0 +1,122 @@
+package schema
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// settings are what Options configure: the compiler and where the schema and the files
+// it references are read from.
+type settings struct {
+	compiler *jsonschema.Compiler
+	fsys     fs.FS  // Filesystem of source and referenced files; nil for the local one
+	source   string // Path the schema was read from; "" for schemas without a file
+	root     string // Directory referenced files must be in; "" for anywhere
+}
+
+// WithSource tells where a schema read from a reader was read from, so its relative $refs
+// resolve against that file's directory. fsys is nil for the local filesystem; otherwise
+// path and the referenced files are in fsys.
+func WithSource(fsys fs.FS, path string) Option {
+	return func(s *settings) {
+		s.fsys, s.source = fsys, path
+	}
+}
+
+// WithRoot confines $refs to files in dir, and resolves the relative $refs of schemas
+// without a source file, such as one read from STDIN, against dir instead of the working
+// directory.
+func WithRoot(dir string) Option {
+	return func(s *settings) {
+		s.root = dir
+	}
+}
+
+// location returns the URL a schema is registered under, from which its relative $refs
+// resolve, and the URL path of the root directory, if any.
+func (s *settings) location() (loc, root string, err error) {
+	if s.fsys != nil {
+		// FS paths become the paths of file URLs; references cannot leave the FS
+		if s.root != "" {
+			root = "/" + path.Clean(s.root)
+		}
+		if s.source == "" {
+			return "file://" + path.Join("/", s.root, "schema.json"), root, nil
+		}
+		return "file://" + path.Join("/", s.source), root, nil
+	}
+	if s.root != "" {
+		dir, err := filepath.Abs(s.root)
+		if err != nil {
+			return "", "", err
+		}
+		root = fileURL(dir)[len("file://"):]
+	}
+	name := s.source
+	if name == "" {
+		name = filepath.Join(s.root, "schema.json")
+	}
+	abs, err := filepath.Abs(name)
+	if err != nil {
+		return "", "", err
+	}
+	return fileURL(abs), root, nil
+}
+
+// fileURL returns the file URL of an absolute path, as the schema library spells it.
+func fileURL(abs string) string {
+	p := filepath.ToSlash(abs)
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	return (&url.URL{Scheme: "file", Path: p}).String()
+}
+
+// loader returns the compiler's loader of referenced files, which reads them from the
+// settings' filesystem, refuses files outside root, and writes what it reads to h, the
+// schema's hash, so it changes with any of its files.
+func (s *settings) loader(root string, h io.Writer) func(string) (io.ReadCloser, error) {
+	return func(ref string) (io.ReadCloser, error) {
+		u, err := url.Parse(ref)
+		if err != nil {
+			return nil, err
+		}
+		if u.Scheme != "file" {
+			return nil, fmt.Errorf("cannot load $ref %s: only local files can be referenced", ref)
+		}
+		p := path.Clean(u.Path)
+		if root != "" && p != root && !strings.HasPrefix(p, strings.TrimSuffix(root, "/")+"/") {
+			return nil, fmt.Errorf("$ref %s is outside the schema root %s", ref, s.root)
+		}
+		var data []byte
+		if s.fsys != nil {
+			data, err = fs.ReadFile(s.fsys, strings.TrimPrefix(p, "/"))
+		} else {
+			if runtime.GOOS == "windows" {
+				p = filepath.FromSlash(strings.TrimPrefix(p, "/"))
+			}
+			data, err = os.ReadFile(p)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot load $ref: %w", err)
+		}
+		fmt.Fprintf(h, "\x00%s\x00", ref)
+		h.Write(data)
+		if isYAML(p) {
+			if data, err = fromYAML(data); err != nil {
+				return nil, fmt.Errorf("cannot load $ref %s: %w", ref, err)
+			}
+		}
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+}
@@ -0,0 +1,68 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+const (
+	refSchema    = `{"type":"object","properties":{"code":{"$ref":"defs/common.json#/$defs/code"}}}`
+	commonSchema = `{"$defs":{"code":{"type":"string","minLength":2}}}`
+)
+
+func TestRelativeRefs(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{"orders.json": refSchema, "defs/common.json": commonSchema} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	check := func(v *Validator, err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		errs, err := v.ValidateRow([]string{"code"}, []string{"x"})
+		if err != nil || len(errs) != 1 || errs[0].Field != "code" {
+			t.Errorf("Expected the referenced minLength to apply, got %+v (%v)", errs, err)
+		}
+	}
+
+	// From the schema's directory, whatever the working directory
+	v, err := NewValidator(filepath.Join(dir, "orders.json"))
+	check(v, err)
+	hash := v.Hash()
+
+	// A schema without a file resolves against the root
+	check(NewValidatorFromReader(strings.NewReader(refSchema), WithRoot(dir)))
+
+	// In an fs.FS, from the schema's directory in it
+	fsys := fstest.MapFS{
+		"schemas/orders.json":      {Data: []byte(refSchema)},
+		"schemas/defs/common.json": {Data: []byte(commonSchema)},
+	}
+	f, _ := fsys.Open("schemas/orders.json")
+	defer f.Close()
+	check(NewValidatorFromReader(f, WithSource(fsys, "schemas/orders.json")))
+
+	// Referenced files are part of the hash
+	if err := os.WriteFile(filepath.Join(dir, "defs/common.json"), []byte(`{"$defs":{"code":{"type":"string"}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := NewValidator(filepath.Join(dir, "orders.json")); err != nil || v.Hash() == hash {
+		t.Errorf("Expected a new hash after a referenced file changed (%v)", err)
+	}
+
+	// References may not leave the root
+	_, err = NewValidator(filepath.Join(dir, "orders.json"), WithRoot(filepath.Join(dir, "other")))
+	if err == nil || !strings.Contains(err.Error(), "outside the schema root") {
+		t.Errorf("Expected a $ref outside the root rejected, got %v", err)
+	}
+}
@@ -1,67 +1,272 @@
 package schema
 
 import (
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
+
+	"github.com/csvlinter/csvlinter/internal/project"
 )
 
-// Project root indicators
-var projectRootIndicators = []string{".git", "package.json"}
+// NextTo are the schema file names looked up beside a CSV file, in order. In these and
+// in Resolver patterns, {name} stands for the CSV file name without its extension and
+// {file} for the whole file name, so orders.csv may have orders.schema.json or
+// orders.csv.schema.yaml.
+var NextTo = []string{
+	"{name}.schema.json", "{file}.schema.json",
+	"{name}.schema.yaml", "{file}.schema.yaml",
+	"{name}.schema.yml", "{file}.schema.yml",
+}
 
-// ResolveSchema attempts to find a schema file for the given CSV path according to fallback rules.
-// Returns the schema path if found, or an empty string if not found.
+// Symlink modes of a Resolver: which path of a CSV file under a symlinked directory the
+// searches walk up.
+const (
+	SymlinksBoth = "both" // The path as given, then the path with symlinks resolved (the default)
+	SymlinksLink = "link" // The path as given only
+	SymlinksReal = "real" // The path with symlinks resolved only
+)
+
+// Resolver finds the schema of a CSV file when none is given.
+type Resolver struct {
+	Patterns    []string // Slash-separated paths such as schemas/{name}.json, tried before NextTo
+	RootMarkers []string // Files or directories marking the project root (project.DefaultRootMarkers when empty)
+	Symlinks    string   // SymlinksBoth (""), SymlinksLink or SymlinksReal
+	IgnoreCase  bool     // Match schema file names in any case, as case-insensitive filesystems do, and return them as stored
+}
+
+// ResolveSchema attempts to find a schema file for the given CSV path with the default
+// Resolver. Returns the schema path if found, or an empty string if not found.
 func ResolveSchema(csvPath string) string {
-	csvDir := filepath.Dir(csvPath)
-	csvBase := filepath.Base(csvPath)
-	csvName := csvBase[:len(csvBase)-len(filepath.Ext(csvBase))]
-
-	// 1. Look for <filename>.schema.json in the same folder
-	candidate := filepath.Join(csvDir, csvName+".schema.json")
-	if fileExists(candidate) {
-		return candidate
+	return Resolver{}.Resolve(csvPath)
+}
+
+// ResolveDirSchema is Resolver.ResolveDir with the default Resolver.
+func ResolveDirSchema(dir string) string {
+	return Resolver{}.ResolveDir(dir)
+}
+
+// ResolveSchemaFS is Resolver.ResolveFS with the default Resolver.
+func ResolveSchemaFS(fsys fs.FS, csvPath string) string {
+	return Resolver{}.ResolveFS(fsys, csvPath)
+}
+
+// Resolve attempts to find a schema file for the given CSV path according to fallback
+// rules: the patterns, looked up in the CSV file's directory and then in each parent up
+// to the project root; the NextTo names in the same directory; and csvlinter.schema.json
+// in the same directory and its parents up to the project root. Relative paths are
+// walked up past the working directory. Under a symlinked directory, the path as given
+// and the real path are searched as the Symlinks mode says.
+// Returns the schema path if found, or an empty string if not found.
+func (r Resolver) Resolve(csvPath string) string {
+	for _, p := range r.paths(csvPath) {
+		if found := r.resolve(local, p); found != "" {
+			return found
+		}
 	}
+	return ""
+}
 
-	// 2. Look for csvlinter.schema.json in the same folder
-	candidate = filepath.Join(csvDir, "csvlinter.schema.json")
-	if fileExists(candidate) {
-		return candidate
+// ResolveDir looks for csvlinter.schema.json in dir, then in its parents up to the
+// project root, for input without a file name such as STDIN. Returns the schema path if
+// found, or an empty string if not found.
+func (r Resolver) ResolveDir(dir string) string {
+	for _, d := range r.paths(dir) {
+		if found := r.resolveDir(local, d); found != "" {
+			return found
+		}
 	}
+	return ""
+}
+
+// ResolveFS is Resolve for a CSV file at csvPath in fsys. The upward searches stop at a
+// project root or at the root of fsys; symlinks are not followed.
+func (r Resolver) ResolveFS(fsys fs.FS, csvPath string) string {
+	return r.resolve(inFS(fsys), csvPath)
+}
+
+func (r Resolver) resolve(w walker, csvPath string) string {
+	csvDir := w.dir(csvPath)
+	csvBase := w.base(csvPath)
 
-	// 3. Walk up parent directories, stopping at project root or system root
-	dir := csvDir
-	for {
-		if isProjectRoot(dir) || isSystemRoot(dir) {
-			break
+	// 1. Look for the patterns in the same folder and its parents, the nearest match winning
+	if len(r.Patterns) > 0 {
+		found := ""
+		r.up(w, csvDir, func(dir string) bool {
+			for _, p := range r.Patterns {
+				if found = r.find(w, dir, expand(p, csvBase)); found != "" {
+					return true
+				}
+			}
+			return false
+		})
+		if found != "" {
+			return found
 		}
-		candidate := filepath.Join(dir, "csvlinter.schema.json")
-		if fileExists(candidate) {
-			return candidate
+	}
+
+	// 2. Look for <filename>.schema.json and its variants in the same folder
+	for _, p := range NextTo {
+		if found := r.find(w, csvDir, expand(p, csvBase)); found != "" {
+			return found
 		}
-		parent := filepath.Dir(dir)
-		if parent == dir { // system root
-			break
+	}
+
+	// 3. Look for csvlinter.schema.json in the same folder, then 4. in parent directories
+	return r.resolveDir(w, csvDir)
+}
+
+func (r Resolver) resolveDir(w walker, dir string) string {
+	found := ""
+	r.up(w, dir, func(dir string) bool {
+		found = r.find(w, dir, "csvlinter.schema.json")
+		return found != ""
+	})
+	return found
+}
+
+// up calls visit with dir and then each of its parents, stopping after the project root,
+// at the top of the filesystem, or when visit returns true.
+func (r Resolver) up(w walker, dir string, visit func(dir string) bool) {
+	for !visit(dir) && !r.isProjectRoot(w, dir) {
+		parent, ok := w.parent(dir)
+		if !ok {
+			return
 		}
 		dir = parent
 	}
+}
 
+// find returns the path of the file at rel, a slash-separated path, in dir, or "" if
+// there is none. Resolvers ignoring case look each element of rel up in any case,
+// preferring an exact match, and return the path as stored.
+func (r Resolver) find(w walker, dir, rel string) string {
+	p := dir
+	for _, elem := range strings.Split(rel, "/") {
+		if !r.IgnoreCase || elem == "." || elem == ".." {
+			p = w.join(p, elem)
+			continue
+		}
+		entries, err := w.readDir(p)
+		if err != nil {
+			return ""
+		}
+		match := ""
+		for _, e := range entries {
+			if e.Name() == elem {
+				match = elem
+				break
+			}
+			if match == "" && strings.EqualFold(e.Name(), elem) {
+				match = e.Name()
+			}
+		}
+		if match == "" {
+			return ""
+		}
+		p = w.join(p, match)
+	}
+	if info, err := w.stat(p); err == nil && !info.IsDir() {
+		return p
+	}
 	return ""
 }
 
-func fileExists(path string) bool {
-	info, err := os.Stat(path)
-	return err == nil && !info.IsDir()
+// paths returns the paths of a file or directory that the searches walk up, in order.
+func (r Resolver) paths(p string) []string {
+	if r.Symlinks == SymlinksLink {
+		return []string{p}
+	}
+	real, differs := realPath(p)
+	switch {
+	case r.Symlinks == SymlinksReal:
+		return []string{real}
+	case differs:
+		return []string{p, real}
+	}
+	return []string{p}
 }
 
-func isProjectRoot(dir string) bool {
-	for _, marker := range projectRootIndicators {
-		if fileExists(filepath.Join(dir, marker)) {
+// realPath returns p with symlinks resolved, and whether that is another path than p's
+// absolute one. Paths that do not exist, such as the name given to STDIN, have their
+// directory resolved.
+func realPath(p string) (string, bool) {
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return p, false
+	}
+	real, err := filepath.EvalSymlinks(abs)
+	if err != nil {
+		dir, err := filepath.EvalSymlinks(filepath.Dir(abs))
+		if err != nil {
+			return p, false
+		}
+		real = filepath.Join(dir, filepath.Base(abs))
+	}
+	return real, real != abs
+}
+
+// expand fills the {name} and {file} placeholders of a schema file pattern for the CSV
+// file named base.
+func expand(pattern, base string) string {
+	name := base[:len(base)-len(path.Ext(base))]
+	return strings.NewReplacer("{name}", name, "{file}", base).Replace(pattern)
+}
+
+// isProjectRoot reports whether dir holds one of the root markers, file or directory.
+func (r Resolver) isProjectRoot(w walker, dir string) bool {
+	for _, marker := range project.Markers(r.RootMarkers) {
+		if _, err := w.stat(w.join(dir, marker)); err == nil {
 			return true
 		}
 	}
 	return false
 }
 
-func isSystemRoot(dir string) bool {
-	parent := filepath.Dir(dir)
-	return dir == parent
+// walker is a filesystem schemas are searched in: the local one or an fs.FS.
+type walker struct {
+	stat    func(name string) (fs.FileInfo, error)
+	readDir func(name string) ([]fs.DirEntry, error)
+	join    func(elem ...string) string
+	dir     func(path string) string
+	base    func(path string) string
+	parent  func(dir string) (string, bool) // False at the top of the filesystem
+}
+
+var local = walker{
+	stat:    os.Stat,
+	readDir: os.ReadDir,
+	join:    filepath.Join,
+	dir:     filepath.Dir,
+	base:    filepath.Base,
+	parent: func(dir string) (string, bool) {
+		// Relative paths go on from the working directory as the shell spells it, so a
+		// symlinked working directory is walked up through its link
+		if !filepath.IsAbs(dir) && (dir == "." || filepath.Base(dir) == "..") {
+			abs, err := filepath.Abs(dir)
+			if err != nil {
+				return "", false
+			}
+			dir = abs
+		}
+		parent := filepath.Dir(dir)
+		return parent, parent != dir
+	},
+}
+
+func inFS(fsys fs.FS) walker {
+	return walker{
+		stat:    func(name string) (fs.FileInfo, error) { return fs.Stat(fsys, name) },
+		readDir: func(name string) ([]fs.DirEntry, error) { return fs.ReadDir(fsys, name) },
+		join:    path.Join,
+		dir:     path.Dir,
+		base:    path.Base,
+		parent: func(dir string) (string, bool) {
+			if dir == "." || dir == "/" {
+				return "", false
+			}
+			return path.Dir(dir), true
+		},
+	}
 }
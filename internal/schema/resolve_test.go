@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"testing/fstest"
 )
 
 func writeFile(p string) {
@@ -78,3 +79,194 @@ func TestResolveSchema(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveSchemaFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"data/orders.csv":            {Data: []byte("id\n")},
+		"data/orders.schema.json":    {Data: []byte(`{}`)},
+		"data/sub/items.csv":         {Data: []byte("id\n")},
+		"data/csvlinter.schema.json": {Data: []byte(`{}`)},
+		"repo/.git/HEAD":             {Data: []byte("ref")},
+		"repo/csvlinter.schema.json": {Data: []byte(`{}`)},
+		"repo/a/b.csv":               {Data: []byte("id\n")},
+		"csvlinter.schema.json":      {Data: []byte(`{}`)},
+		"inner/.git/HEAD":            {Data: []byte("ref")},
+		"inner/x.csv":                {Data: []byte("id\n")},
+	}
+	cases := map[string]string{
+		"data/orders.csv":    "data/orders.schema.json",
+		"data/sub/items.csv": "data/csvlinter.schema.json",
+		"repo/a/b.csv":       "repo/csvlinter.schema.json",
+		"inner/x.csv":        "", // stops at the project root
+		"top.csv":            "csvlinter.schema.json",
+	}
+	for csvPath, want := range cases {
+		if got := ResolveSchemaFS(fsys, csvPath); got != want {
+			t.Errorf("ResolveSchemaFS(%q) = %q, want %q", csvPath, got, want)
+		}
+	}
+}
+
+func TestResolveDirSchema(t *testing.T) {
+	proj := t.TempDir()
+	writeFile(filepath.Join(proj, "package.json"))
+	writeFile(filepath.Join(proj, "csvlinter.schema.json"))
+	nested := filepath.Join(proj, "exports", "daily")
+	_ = os.MkdirAll(nested, 0o755)
+
+	if got := ResolveDirSchema(nested); got != filepath.Join(proj, "csvlinter.schema.json") {
+		t.Errorf("want the project root's schema, got %q", got)
+	}
+	if got := ResolveSchema(filepath.Join(nested, "data.csv")); got != filepath.Join(proj, "csvlinter.schema.json") {
+		t.Errorf("want the project root's schema for a file, got %q", got)
+	}
+	if got := ResolveDirSchema(t.TempDir()); got != "" {
+		t.Errorf("want no schema, got %q", got)
+	}
+}
+
+func TestResolveSchemaPatterns(t *testing.T) {
+	proj := t.TempDir()
+	writeFile(filepath.Join(proj, "package.json"))
+	writeFile(filepath.Join(proj, "schemas", "orders.json"))
+	writeFile(filepath.Join(proj, "data", "orders.schema.json"))
+	writeFile(filepath.Join(proj, "data", "items.csv.schema.yaml"))
+	writeFile(filepath.Join(proj, "data", "eu", "orders.tableschema.json"))
+	r := Resolver{Patterns: []string{"{name}.tableschema.json", "schemas/{name}.json"}}
+
+	cases := map[string]string{
+		"data/orders.csv":    "schemas/orders.json",             // a pattern, found two levels up, wins over orders.schema.json
+		"data/eu/orders.csv": "data/eu/orders.tableschema.json", // the nearest match wins
+		"data/items.csv":     "data/items.csv.schema.yaml",      // no pattern matches; a default variant does
+	}
+	for csvPath, want := range cases {
+		if got := r.Resolve(filepath.Join(proj, csvPath)); got != filepath.Join(proj, want) {
+			t.Errorf("ResolveSchema(%q) = %q, want %q", csvPath, got, want)
+		}
+	}
+
+	fsys := fstest.MapFS{
+		"repo/.git/HEAD":             {Data: []byte("ref")},
+		"repo/schemas/orders.json":   {Data: []byte(`{}`)},
+		"repo/data/orders.csv":       {Data: []byte("id\n")},
+		"repo/data/items.schema.yml": {Data: []byte(`{}`)},
+	}
+	if got := r.ResolveFS(fsys, "repo/data/orders.csv"); got != "repo/schemas/orders.json" {
+		t.Errorf("ResolveSchemaFS(orders.csv) = %q", got)
+	}
+	if got := r.ResolveFS(fsys, "repo/data/items.csv"); got != "repo/data/items.schema.yml" {
+		t.Errorf("ResolveSchemaFS(items.csv) = %q", got)
+	}
+}
+
+func TestResolverRootMarkers(t *testing.T) {
+	repo := t.TempDir()
+	writeFile(filepath.Join(repo, "csvlinter.schema.json"))
+	if err := os.MkdirAll(filepath.Join(repo, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	svc := filepath.Join(repo, "services", "billing")
+	writeFile(filepath.Join(svc, "go.mod"))
+	csvPath := filepath.Join(svc, "data", "invoices.csv")
+
+	// A .git directory is a root marker too; go.mod is not one by default
+	if got := ResolveSchema(csvPath); got != filepath.Join(repo, "csvlinter.schema.json") {
+		t.Errorf("default markers: got %q", got)
+	}
+	r := Resolver{RootMarkers: []string{"go.mod", ".git"}}
+	if got := r.Resolve(csvPath); got != "" {
+		t.Errorf("go.mod marker: got %q, want the search to stop at the module", got)
+	}
+	writeFile(filepath.Join(svc, "csvlinter.schema.json"))
+	if got := r.Resolve(csvPath); got != filepath.Join(svc, "csvlinter.schema.json") {
+		t.Errorf("go.mod marker: got %q", got)
+	}
+
+	fsys := fstest.MapFS{
+		"repo/csvlinter.schema.json": {Data: []byte(`{}`)},
+		"repo/py/pyproject.toml":     {Data: []byte("")},
+		"repo/py/data/events.csv":    {Data: []byte("id\n")},
+		"repo/other/data/events.csv": {Data: []byte("id\n")},
+	}
+	r = Resolver{RootMarkers: []string{"pyproject.toml", "WORKSPACE"}}
+	if got := r.ResolveFS(fsys, "repo/py/data/events.csv"); got != "" {
+		t.Errorf("ResolveFS(py) = %q, want the search to stop at pyproject.toml", got)
+	}
+	if got := r.ResolveFS(fsys, "repo/other/data/events.csv"); got != "repo/csvlinter.schema.json" {
+		t.Errorf("ResolveFS(other) = %q", got)
+	}
+}
+
+func TestResolverSymlinks(t *testing.T) {
+	tmp, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := filepath.Join(tmp, "store")
+	workspace := filepath.Join(tmp, "workspace")
+	writeFile(filepath.Join(store, "package.json"))
+	writeFile(filepath.Join(store, "csvlinter.schema.json"))
+	writeFile(filepath.Join(store, "exports", "orders.csv"))
+	writeFile(filepath.Join(workspace, "package.json"))
+	link := filepath.Join(workspace, "data")
+	if err := os.Symlink(filepath.Join(store, "exports"), link); err != nil {
+		t.Skipf("cannot create symlinks: %v", err)
+	}
+	csvPath := filepath.Join(link, "orders.csv")
+	storeSchema := filepath.Join(store, "csvlinter.schema.json")
+
+	for mode, want := range map[string]string{"": storeSchema, SymlinksLink: "", SymlinksReal: storeSchema} {
+		if got := (Resolver{Symlinks: mode}).Resolve(csvPath); got != want {
+			t.Errorf("no workspace schema, mode %q: got %q, want %q", mode, got, want)
+		}
+	}
+	writeFile(filepath.Join(workspace, "csvlinter.schema.json"))
+	wsSchema := filepath.Join(workspace, "csvlinter.schema.json")
+	for mode, want := range map[string]string{"": wsSchema, SymlinksLink: wsSchema, SymlinksReal: storeSchema} {
+		if got := (Resolver{Symlinks: mode}).Resolve(csvPath); got != want {
+			t.Errorf("workspace schema, mode %q: got %q, want %q", mode, got, want)
+		}
+	}
+
+	// Relative paths are walked up from the working directory through its link
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(link); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	t.Setenv("PWD", link)
+	if got := (Resolver{Symlinks: SymlinksLink}).Resolve("orders.csv"); got != wsSchema {
+		t.Errorf("relative path: got %q, want %q", got, wsSchema)
+	}
+}
+
+func TestResolverIgnoreCase(t *testing.T) {
+	proj := t.TempDir()
+	writeFile(filepath.Join(proj, "package.json"))
+	writeFile(filepath.Join(proj, "Schemas", "Orders.json"))
+	writeFile(filepath.Join(proj, "data", "Items.Schema.JSON"))
+	r := Resolver{Patterns: []string{"schemas/{name}.json"}, IgnoreCase: true}
+
+	if got, want := r.Resolve(filepath.Join(proj, "data", "orders.csv")), filepath.Join(proj, "Schemas", "Orders.json"); got != want {
+		t.Errorf("pattern: got %q, want %q", got, want)
+	}
+	if got, want := r.Resolve(filepath.Join(proj, "data", "items.csv")), filepath.Join(proj, "data", "Items.Schema.JSON"); got != want {
+		t.Errorf("next to: got %q, want %q", got, want)
+	}
+
+	fsys := fstest.MapFS{
+		"repo/.git/HEAD":               {Data: []byte("ref")},
+		"repo/CSVLinter.schema.json":   {Data: []byte(`{}`)},
+		"repo/data/orders.csv":         {Data: []byte("id\n")},
+		"repo/data/orders.schema.json": {Data: []byte(`{}`)},
+	}
+	if got := r.ResolveFS(fsys, "repo/data/ORDERS.csv"); got != "repo/data/orders.schema.json" {
+		t.Errorf("ResolveFS(ORDERS.csv) = %q", got)
+	}
+	if got := r.ResolveFS(fsys, "repo/data/items.csv"); got != "repo/CSVLinter.schema.json" {
+		t.Errorf("ResolveFS(items.csv) = %q", got)
+	}
+}
@@ -1,9 +1,15 @@
 package schema
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"os"
+	"path"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -12,62 +18,392 @@ import (
 
 // Validator represents a JSON Schema validator
 type Validator struct {
-	schema *jsonschema.Schema
+	schema   *jsonschema.Schema
+	hash     string // sha256 of the schema source, for cache keys
+	coercers []Coercer
+	empty    string              // How empty cells reach the schema; "" for EmptyString
+	emptyBy  map[string]string   // Column -> empty mode, overriding empty
+	types    map[string][]string // Column -> JSON types values are converted to, overriding the schema's
+	declared map[string][]string // Column -> JSON types of its property, through $refs and compositions
+	omitted  map[string]bool     // Columns left out of row objects
+	key      []string            // Columns of x-primaryKey, if any
+}
+
+// Drafts are the JSON Schema drafts a schema can declare with $schema, oldest first.
+// Schemas without $schema are read as DefaultDraft.
+var Drafts = []string{"draft-04", "draft-06", "draft-07", "2019-09", "2020-12"}
+
+// DefaultDraft is the draft of schemas that do not declare one.
+const DefaultDraft = "2020-12"
+
+// Ways empty cells reach the schema.
+const (
+	EmptyString  = "string"  // As "", which fails type checks other than string (the default)
+	EmptyMissing = "missing" // Left out of the row object, so only "required" applies
+	EmptyNull    = "null"    // As null, for schemas that allow ["integer", "null"]
+)
+
+// CheckEmptyMode returns an error unless mode is an empty mode or "".
+func CheckEmptyMode(mode string) error {
+	switch mode {
+	case "", EmptyString, EmptyMissing, EmptyNull:
+		return nil
+	}
+	return fmt.Errorf("unknown empty value mode '%s' (use %s, %s or %s)", mode, EmptyString, EmptyMissing, EmptyNull)
+}
+
+// Column types values can be converted to with WithTypes, independent of the schema.
+const (
+	TypeInt    = "int"
+	TypeFloat  = "float"
+	TypeBool   = "bool"
+	TypeString = "string"
+)
+
+// columnTypes maps the column types, and their JSON Schema names, to JSON types.
+var columnTypes = map[string]string{
+	TypeInt: "integer", "integer": "integer",
+	TypeFloat: "number", "number": "number",
+	TypeBool: "boolean", "boolean": "boolean",
+	TypeString: "string",
+}
+
+// CheckColumnType returns an error unless t is a column type WithTypes accepts.
+func CheckColumnType(t string) error {
+	if _, ok := columnTypes[t]; !ok {
+		return fmt.Errorf("unknown type '%s' (use %s, %s, %s or %s)", t, TypeInt, TypeFloat, TypeBool, TypeString)
+	}
+	return nil
+}
+
+// Coercer converts the string value of a column into the value validated against the
+// schema, e.g. "€1,234.00" into the number 1234 or "2023年1月1日" into the date string
+// "2023-01-01". types are the column's schema types, nil when the schema does not
+// describe the column. Coerced values must be JSON values as decoded by encoding/json:
+// string, float64, int, bool or nil. ok false leaves the value to the next coercer, and
+// finally to the built-in conversion of integers and numbers.
+type Coercer interface {
+	Coerce(column, value string, types []string) (coerced interface{}, ok bool)
+}
+
+// CoercerFunc adapts a function to a Coercer.
+type CoercerFunc func(column, value string, types []string) (interface{}, bool)
+
+// Coerce calls f.
+func (f CoercerFunc) Coerce(column, value string, types []string) (interface{}, bool) {
+	return f(column, value, types)
 }
 
 // ValidationError represents a schema validation error
 type ValidationError struct {
-	Field   string `json:"field"`
-	Message string `json:"message"`
-	Value   string `json:"value"`
+	Field    string `json:"field"`
+	Message  string `json:"message"`
+	Value    string `json:"value"`
+	Keyword  string `json:"keyword"`            // Failed JSON Schema keyword, e.g. "format" or "required"
+	Expected string `json:"expected,omitempty"` // What the keyword asks for, when the message tells, e.g. "integer"
+	Actual   string `json:"actual,omitempty"`   // What the value is instead, e.g. "string"
+	// Suggestion tells how to fix the value, e.g. "expected format YYYY-MM-DD", when the
+	// keyword allows telling.
+	Suggestion string `json:"suggestion,omitempty"`
+	// Replacement is the value the suggestion amounts to, e.g. the value without its
+	// surrounding whitespace; "" when the suggestion takes more than replacing the value.
+	Replacement string `json:"replacement,omitempty"`
 }
 
-// NewValidator creates a new schema validator from a JSON Schema file
-func NewValidator(schemaPath string) (*Validator, error) {
+// expectedGot matches messages such as "expected integer, but got string".
+var expectedGot = regexp.MustCompile(`^expected (.+), but got (.+)$`)
+
+// Option configures how a schema is compiled.
+type Option func(*settings)
+
+// WithFormats asserts the given "format" validators, which take precedence over the
+// library's formats of the same name. Values that are not strings pass. With any format
+// given, formats are also asserted in draft 2019-09 and later schemas, where they are
+// otherwise annotations.
+func WithFormats(formats map[string]func(string) bool) Option {
+	return func(s *settings) {
+		c := s.compiler
+		if len(formats) > 0 {
+			c.AssertFormat = true
+		}
+		for name, f := range formats {
+			f := f
+			c.Formats[name] = func(v interface{}) bool {
+				s, ok := v.(string)
+				return !ok || f(s)
+			}
+		}
+	}
+}
+
+// NewValidator creates a new schema validator from a JSON Schema file, written in JSON or,
+// for .yaml and .yml files, in YAML. Relative $refs resolve against the file's directory.
+func NewValidator(schemaPath string, opts ...Option) (*Validator, error) {
 	schemaBytes, err := os.ReadFile(schemaPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read schema file: %w", err)
 	}
+	return compile(schemaBytes, append(opts[:len(opts):len(opts)], WithSource(nil, schemaPath)))
+}
+
+// NewValidatorFromReader creates a new schema validator from a JSON Schema io.Reader.
+// Relative $refs resolve against the working directory, unless WithSource or WithRoot
+// tell otherwise.
+func NewValidatorFromReader(r io.Reader, opts ...Option) (*Validator, error) {
+	schemaBytes, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema: %w", err)
+	}
+	return compile(schemaBytes, opts)
+}
 
-	compiler := jsonschema.NewCompiler()
-	if err := compiler.AddResource("schema.json", strings.NewReader(string(schemaBytes))); err != nil {
+func compile(schemaBytes []byte, opts []Option) (*Validator, error) {
+	s := &settings{compiler: jsonschema.NewCompiler()}
+	for _, opt := range opts {
+		opt(s)
+	}
+	location, root, err := s.location()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate schema: %w", err)
+	}
+	// Referenced files are hashed after the schema as they are loaded
+	h := sha256.New()
+	h.Write(schemaBytes)
+	if isYAML(s.source) {
+		if schemaBytes, err = fromYAML(schemaBytes); err != nil {
+			return nil, err
+		}
+	}
+	s.compiler.LoadURL = s.loader(root, h)
+	if err := s.compiler.AddResource(location, bytes.NewReader(schemaBytes)); err != nil {
 		return nil, fmt.Errorf("failed to add schema resource: %w", err)
 	}
 
-	schema, err := compiler.Compile("schema.json")
+	schema, err := s.compiler.Compile(location)
 	if err != nil {
 		return nil, fmt.Errorf("failed to compile schema: %w", err)
 	}
 
+	v := newValidator(schema, hex.EncodeToString(h.Sum(nil)))
+	v.key = primaryKey(schemaBytes)
+	return v, nil
+}
+
+// newValidator returns a validator of rows against schema, a compiled row schema.
+func newValidator(schema *jsonschema.Schema, hash string) *Validator {
 	return &Validator{
-		schema: schema,
-	}, nil
+		schema:   schema,
+		hash:     hash,
+		declared: declaredTypes(schema),
+	}
 }
 
-// NewValidatorFromReader creates a new schema validator from a JSON Schema io.Reader
-func NewValidatorFromReader(r io.Reader) (*Validator, error) {
-	schemaBytes, err := io.ReadAll(r)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read schema: %w", err)
+// declaredTypes returns the JSON types of the properties of a row schema, including the
+// properties and types reached through $ref, allOf, anyOf and oneOf, so that a column
+// typed {"$ref": "#/$defs/quantity"} or {"anyOf": [{"type": "integer"}, {"type": "null"}]}
+// is converted like one typed "integer".
+func declaredTypes(root *jsonschema.Schema) map[string][]string {
+	declared := make(map[string][]string)
+	var walk func(s *jsonschema.Schema, seen map[*jsonschema.Schema]bool)
+	walk = func(s *jsonschema.Schema, seen map[*jsonschema.Schema]bool) {
+		if s == nil || seen[s] {
+			return
+		}
+		seen[s] = true
+		for name, prop := range s.Properties {
+			declared[name] = mergeTypes(declared[name], schemaTypes(prop, map[*jsonschema.Schema]bool{}))
+		}
+		for _, sub := range subschemas(s) {
+			walk(sub, seen)
+		}
 	}
+	walk(root, map[*jsonschema.Schema]bool{})
+	return declared
+}
 
-	compiler := jsonschema.NewCompiler()
-	if err := compiler.AddResource("schema.json", strings.NewReader(string(schemaBytes))); err != nil {
-		return nil, fmt.Errorf("failed to add schema resource: %w", err)
+// schemaTypes returns the types a value of s can have, from its own type and the types
+// of the schemas it refers to or is composed of. Those in not are left out, as they are
+// types the value must not have.
+func schemaTypes(s *jsonschema.Schema, seen map[*jsonschema.Schema]bool) []string {
+	if s == nil || seen[s] {
+		return nil
+	}
+	seen[s] = true
+	types := s.Types
+	for _, sub := range subschemas(s) {
+		types = mergeTypes(types, schemaTypes(sub, seen))
 	}
+	return types
+}
 
-	schema, err := compiler.Compile("schema.json")
-	if err != nil {
-		return nil, fmt.Errorf("failed to compile schema: %w", err)
+// subschemas returns the schemas a value of s is also validated against: the targets of
+// its references and the schemas of allOf, anyOf and oneOf.
+func subschemas(s *jsonschema.Schema) []*jsonschema.Schema {
+	subs := []*jsonschema.Schema{s.Ref, s.RecursiveRef, s.DynamicRef}
+	subs = append(subs, s.AllOf...)
+	subs = append(subs, s.AnyOf...)
+	return append(subs, s.OneOf...)
+}
+
+// mergeTypes adds the types in more that are not in types. Integer goes first, so that
+// whole numbers convert to integers when a column can be either.
+func mergeTypes(types, more []string) []string {
+	merged := types
+	for _, t := range more {
+		if !slices.Contains(merged, t) {
+			merged = append(merged[:len(merged):len(merged)], t)
+		}
+	}
+	if i := slices.Index(merged, "integer"); i > 0 {
+		merged = append([]string{"integer"}, slices.Delete(slices.Clone(merged), i, i+1)...)
 	}
+	return merged
+}
 
-	return &Validator{
-		schema: schema,
-	}, nil
+// WithCoercers returns a validator for the same schema that converts values with the
+// given coercers, tried in order, before the built-in conversions.
+func (v *Validator) WithCoercers(coercers ...Coercer) *Validator {
+	c := *v
+	c.coercers = append(v.coercers[:len(v.coercers):len(v.coercers)], coercers...)
+	return &c
+}
+
+// WithEmptyValues returns a validator for the same schema that passes empty cells as mode,
+// or as the mode in columns for the columns listed there. Modes are EmptyString,
+// EmptyMissing or EmptyNull; "" keeps the current mode.
+func (v *Validator) WithEmptyValues(mode string, columns map[string]string) *Validator {
+	c := *v
+	if mode != "" {
+		c.empty = mode
+	}
+	if len(columns) > 0 {
+		c.emptyBy = make(map[string]string, len(v.emptyBy)+len(columns))
+		for column, m := range v.emptyBy {
+			c.emptyBy[column] = m
+		}
+		for column, m := range columns {
+			c.emptyBy[column] = m
+		}
+	}
+	return &c
+}
+
+// WithTypes returns a validator for the same schema that converts the values of the
+// columns in types to the given type instead of the types of the column's property, for
+// columns the schema leaves untyped or types other than the file needs. Types are TypeInt, TypeFloat,
+// TypeBool or TypeString (no conversion), which CheckColumnType accepts; values that do
+// not convert reach the schema as strings. Coercers get the column's type as its types.
+func (v *Validator) WithTypes(types map[string]string) *Validator {
+	if len(types) == 0 {
+		return v
+	}
+	c := *v
+	c.types = make(map[string][]string, len(v.types)+len(types))
+	for column, t := range v.types {
+		c.types[column] = t
+	}
+	for column, t := range types {
+		c.types[column] = []string{columnTypes[t]}
+	}
+	return &c
+}
+
+// UnexpectedColumns returns the columns of headers that the row schema rejects whatever
+// their values, because additionalProperties is false and they are neither properties
+// nor match patternProperties, in header order. The row schema's $refs and allOf are
+// followed; additionalProperties inside anyOf or oneOf depends on the row and is not.
+func (v *Validator) UnexpectedColumns(headers []string) []string {
+	rejected := make(map[string]bool)
+	var walk func(s *jsonschema.Schema, seen map[*jsonschema.Schema]bool)
+	walk = func(s *jsonschema.Schema, seen map[*jsonschema.Schema]bool) {
+		if s == nil || seen[s] {
+			return
+		}
+		seen[s] = true
+		if closed, ok := s.AdditionalProperties.(bool); ok && !closed {
+			for _, h := range headers {
+				if !allowsProperty(s, h) {
+					rejected[h] = true
+				}
+			}
+		}
+		walk(s.Ref, seen)
+		for _, sub := range s.AllOf {
+			walk(sub, seen)
+		}
+	}
+	walk(v.schema, map[*jsonschema.Schema]bool{})
+
+	var unexpected []string
+	for _, h := range headers {
+		if rejected[h] && !slices.Contains(unexpected, h) {
+			unexpected = append(unexpected, h)
+		}
+	}
+	return unexpected
+}
+
+// allowsProperty reports whether name is a property of s or matches its patternProperties.
+func allowsProperty(s *jsonschema.Schema, name string) bool {
+	if _, ok := s.Properties[name]; ok {
+		return true
+	}
+	for re := range s.PatternProperties {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithoutColumns returns a validator for the same schema that leaves columns out of row
+// objects, for columns already reported by UnexpectedColumns, which would otherwise fail
+// every row.
+func (v *Validator) WithoutColumns(columns []string) *Validator {
+	if len(columns) == 0 {
+		return v
+	}
+	c := *v
+	c.omitted = make(map[string]bool, len(v.omitted)+len(columns))
+	for column := range v.omitted {
+		c.omitted[column] = true
+	}
+	for _, column := range columns {
+		c.omitted[column] = true
+	}
+	return &c
+}
+
+// emptyMode returns how empty cells of column reach the schema.
+func (v *Validator) emptyMode(column string) string {
+	if m, ok := v.emptyBy[column]; ok && m != "" {
+		return m
+	}
+	if v.empty == "" {
+		return EmptyString
+	}
+	return v.empty
+}
+
+// Hash identifies the schema source: validators built from the same bytes share a hash.
+func (v *Validator) Hash() string {
+	return v.hash
+}
+
+// PrimaryKey returns the columns of the schema's x-primaryKey, or nil when it has none.
+func (v *Validator) PrimaryKey() []string {
+	return v.key
 }
 
 // ValidateRow validates a CSV row against the JSON Schema
 func (v *Validator) ValidateRow(headers []string, data []string) ([]ValidationError, error) {
+	return v.ValidateRowQuoted(headers, data, nil)
+}
+
+// ValidateRowQuoted is ValidateRow for a row whose quoted fields are known: quoted empty
+// fields ("") reach the schema as empty strings whatever the empty mode, which then only
+// applies to fields with nothing between the delimiters. quoted may be nil.
+func (v *Validator) ValidateRowQuoted(headers []string, data []string, quoted []bool) ([]ValidationError, error) {
 	if len(headers) != len(data) {
 		return []ValidationError{{
 			Field:   "row",
@@ -76,36 +412,19 @@ func (v *Validator) ValidateRow(headers []string, data []string) ([]ValidationEr
 		}}, nil
 	}
 
-	// Convert row to a map and attempt to convert types based on schema
-	rowData := make(map[string]interface{})
-	for i, header := range headers {
-		// Default to string
-		var value interface{} = data[i]
-
-		// Check schema for type information
-		if prop, ok := v.schema.Properties[header]; ok {
-			// A property can have multiple types, e.g., ["number", "null"]
-			for _, t := range prop.Types {
-				if t == "integer" {
-					if v, err := strconv.Atoi(data[i]); err == nil {
-						value = v
-						break
-					}
-				} else if t == "number" {
-					if v, err := strconv.ParseFloat(data[i], 64); err == nil {
-						value = v
-						break
-					}
-				}
-			}
-		}
-		rowData[header] = value
-	}
+	rowData, coerced := v.rowObject(headers, data, quoted)
 
 	// Validate against schema
 	if err := v.schema.Validate(rowData); err != nil {
 		if validationErr, ok := err.(*jsonschema.ValidationError); ok {
-			return v.convertValidationErrors(validationErr, rowData), nil
+			errs := v.convertValidationErrors(validationErr, rowData)
+			// Findings show values as they are in the file, not as coerced
+			for i := range errs {
+				if raw, ok := coerced[errs[i].Field]; ok {
+					errs[i].Value = raw
+				}
+			}
+			return errs, nil
 		}
 		return nil, fmt.Errorf("schema validation error: %w", err)
 	}
@@ -113,6 +432,85 @@ func (v *Validator) ValidateRow(headers []string, data []string) ([]ValidationEr
 	return nil, nil
 }
 
+// RowObject converts a row to the object validated against the schema: empty cells are
+// passed as set by WithEmptyValues, and other values are converted by the coercers, else
+// strings, except integers and numbers in columns the schema types as such. headers and
+// data must have the same length.
+func (v *Validator) RowObject(headers []string, data []string) map[string]interface{} {
+	rowData, _ := v.rowObject(headers, data, nil)
+	return rowData
+}
+
+// rowObject is RowObject, also returning the original values of coerced columns.
+func (v *Validator) rowObject(headers []string, data []string, quoted []bool) (map[string]interface{}, map[string]string) {
+	rowData := make(map[string]interface{}, len(headers))
+	var coerced map[string]string
+	for i, header := range headers {
+		if v.omitted[header] {
+			continue
+		}
+		if data[i] == "" && (quoted == nil || !quoted[i]) {
+			switch v.emptyMode(header) {
+			case EmptyMissing:
+				continue
+			case EmptyNull:
+				rowData[header] = nil
+				continue
+			}
+		}
+		types, overridden := v.types[header]
+		if !overridden {
+			types = v.declared[header]
+		}
+		if len(v.coercers) > 0 {
+			if value, ok := v.coerce(header, data[i], types); ok {
+				if coerced == nil {
+					coerced = make(map[string]string)
+				}
+				rowData[header] = value
+				coerced[header] = data[i]
+				continue
+			}
+		}
+
+		rowData[header] = convert(data[i], types, overridden)
+	}
+	return rowData, coerced
+}
+
+// convert returns s as the first of types it converts to, or as a string. A property can
+// have multiple types, e.g. ["number", "null"]. Booleans are only converted when asked
+// for with WithTypes, as schemas never saw them converted.
+func convert(s string, types []string, booleans bool) interface{} {
+	for _, t := range types {
+		switch t {
+		case "integer":
+			if v, err := strconv.Atoi(s); err == nil {
+				return v
+			}
+		case "number":
+			if v, err := strconv.ParseFloat(s, 64); err == nil {
+				return v
+			}
+		case "boolean":
+			if v, err := strconv.ParseBool(s); err == nil && booleans {
+				return v
+			}
+		}
+	}
+	return s
+}
+
+// coerce returns the value of the first coercer that converts it.
+func (v *Validator) coerce(column, value string, types []string) (interface{}, bool) {
+	for _, c := range v.coercers {
+		if coerced, ok := c.Coerce(column, value, types); ok {
+			return coerced, true
+		}
+	}
+	return nil, false
+}
+
 // convertValidationErrors converts jsonschema validation errors to our format
 func (v *Validator) convertValidationErrors(err *jsonschema.ValidationError, data map[string]interface{}) []ValidationError {
 	var errors []ValidationError
@@ -125,16 +523,22 @@ func (v *Validator) convertValidationErrors(err *jsonschema.ValidationError, dat
 
 		originalValue := ""
 		if field != "" {
-			if val, exists := data[field]; exists {
+			if val, exists := data[field]; exists && val != nil {
 				originalValue = fmt.Sprintf("%v", val)
 			}
 		}
 
-		errors = append(errors, ValidationError{
+		e := ValidationError{
 			Field:   field,
 			Message: err.Message,
 			Value:   originalValue,
-		})
+			Keyword: path.Base(err.KeywordLocation),
+		}
+		if m := expectedGot.FindStringSubmatch(err.Message); m != nil {
+			e.Expected, e.Actual = m[1], m[2]
+		}
+		e.Suggestion, e.Replacement = suggest(e)
+		errors = append(errors, e)
 	} else {
 		// Intermediate node: recurse into causes to find the leaf violations.
 		for _, cause := range err.Causes {
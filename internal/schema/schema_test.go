@@ -1,6 +1,10 @@
 package schema
 
 import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"testing"
 )
@@ -81,3 +85,281 @@ func TestValidateRow_RootLevelErrors(t *testing.T) {
 		}
 	})
 }
+
+func TestValidatorHash(t *testing.T) {
+	a, err := NewValidatorFromReader(strings.NewReader(`{"type":"object"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewValidatorFromReader(strings.NewReader(`{"type":"object"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewValidatorFromReader(strings.NewReader(`{"type":"object","required":["id"]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Hash() == "" || a.Hash() != b.Hash() {
+		t.Errorf("Expected equal, non-empty hashes for the same source")
+	}
+	if a.Hash() == c.Hash() {
+		t.Errorf("Expected different hashes for different sources")
+	}
+}
+
+func TestPrimaryKey(t *testing.T) {
+	for schemaJSON, want := range map[string][]string{
+		`{"x-primaryKey":"id"}`:            {"id"},
+		`{"x-primaryKey":["id","region"]}`: {"id", "region"},
+		`{"type":"object"}`:                nil,
+		`{"x-primaryKey":{"column":"id"}}`: nil,
+	} {
+		v, err := NewValidatorFromReader(strings.NewReader(schemaJSON))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := v.WithCoercers().PrimaryKey(); !slices.Equal(got, want) {
+			t.Errorf("%s: want key %v, got %v", schemaJSON, want, got)
+		}
+	}
+}
+
+func TestCoercers(t *testing.T) {
+	base, err := NewValidatorFromReader(strings.NewReader(`{
+		"properties": {
+			"price": {"type": "number", "maximum": 1000},
+			"day": {"type": "string", "format": "date"},
+			"qty": {"type": "integer"}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("NewValidatorFromReader: %v", err)
+	}
+	euros := CoercerFunc(func(column, value string, types []string) (interface{}, bool) {
+		if len(types) == 0 || types[0] != "number" || !strings.HasPrefix(value, "€") {
+			return nil, false
+		}
+		f, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimPrefix(value, "€"), ",", ""), 64)
+		return f, err == nil
+	})
+	japaneseDates := CoercerFunc(func(column, value string, types []string) (interface{}, bool) {
+		var y, m, d int
+		if column != "day" {
+			return nil, false
+		}
+		if _, err := fmt.Sscanf(value, "%d年%d月%d日", &y, &m, &d); err != nil {
+			return nil, false
+		}
+		return fmt.Sprintf("%04d-%02d-%02d", y, m, d), true
+	})
+	v := base.WithCoercers(euros, japaneseDates)
+	headers := []string{"price", "day", "qty"}
+
+	if errs, err := v.ValidateRow(headers, []string{"€999.50", "2023年1月1日", "3"}); err != nil || len(errs) != 0 {
+		t.Errorf("Expected coerced values to pass, got %+v, %v", errs, err)
+	}
+	// Coerced values are checked, and reported as they are in the file
+	errs, _ := v.ValidateRow(headers, []string{"€1,234.00", "2023-01-01", "3"})
+	if len(errs) != 1 || errs[0].Field != "price" || errs[0].Value != "€1,234.00" || errs[0].Keyword != "maximum" {
+		t.Errorf("Expected the maximum broken by the original value, got %+v", errs)
+	}
+	if obj := v.RowObject(headers, []string{"€5", "x", "7"}); obj["price"] != 5.0 || obj["day"] != "x" || obj["qty"] != 7 {
+		t.Errorf("Expected coerced, unchanged and built-in values, got %#v", obj)
+	}
+	// The original validator is unchanged
+	if errs, _ := base.ValidateRow(headers, []string{"€5", "2023-01-01", "1"}); len(errs) != 1 || errs[0].Field != "price" {
+		t.Errorf("Expected the base validator to reject the euro value, got %+v", errs)
+	}
+}
+
+func TestWithFormats(t *testing.T) {
+	even := WithFormats(map[string]func(string) bool{
+		"even-length": func(s string) bool { return len(s)%2 == 0 },
+	})
+	for _, draft := range []string{"http://json-schema.org/draft-07/schema#", "https://json-schema.org/draft/2020-12/schema"} {
+		v, err := NewValidatorFromReader(strings.NewReader(`{
+			"$schema": "`+draft+`",
+			"properties": {"code": {"format": "even-length"}, "n": {"type": "integer", "format": "even-length"}}
+		}`), even)
+		if err != nil {
+			t.Fatalf("%s: NewValidatorFromReader: %v", draft, err)
+		}
+		headers := []string{"code", "n"}
+		if errs, _ := v.ValidateRow(headers, []string{"ab", "123"}); len(errs) != 0 {
+			t.Errorf("%s: expected an even code and a number to pass, got %+v", draft, errs)
+		}
+		if errs, _ := v.ValidateRow(headers, []string{"abc", "1"}); len(errs) != 1 || errs[0].Field != "code" || errs[0].Keyword != "format" {
+			t.Errorf("%s: expected a format error for code, got %+v", draft, errs)
+		}
+	}
+}
+
+func TestWithEmptyValues(t *testing.T) {
+	base, err := NewValidatorFromReader(strings.NewReader(`{
+		"required": ["id"],
+		"properties": {
+			"id": {"type": "string"},
+			"qty": {"type": ["integer", "null"]},
+			"note": {"type": "string", "minLength": 1}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("NewValidatorFromReader: %v", err)
+	}
+	headers := []string{"id", "qty", "note"}
+	row := []string{"", "", ""}
+	keywords := func(v *Validator) string {
+		errs, err := v.ValidateRow(headers, row)
+		if err != nil {
+			t.Fatalf("ValidateRow: %v", err)
+		}
+		var out []string
+		for _, e := range errs {
+			if e.Value != "" {
+				t.Errorf("expected empty values in findings, got %+v", e)
+			}
+			out = append(out, e.Field+":"+e.Keyword)
+		}
+		sort.Strings(out)
+		return strings.Join(out, " ")
+	}
+
+	if got := keywords(base); got != "note:minLength qty:type" {
+		t.Errorf("string: got %s", got)
+	}
+	if got := keywords(base.WithEmptyValues(EmptyMissing, nil)); got != ":required" {
+		t.Errorf("missing: got %s", got)
+	}
+	if got := keywords(base.WithEmptyValues(EmptyNull, nil)); got != "id:type note:type" {
+		t.Errorf("null: got %s", got)
+	}
+	perColumn := base.WithEmptyValues(EmptyMissing, map[string]string{"id": EmptyString, "qty": EmptyNull})
+	if got := keywords(perColumn); got != "" {
+		t.Errorf("per column: got %s", got)
+	}
+	if obj := perColumn.RowObject(headers, row); len(obj) != 2 || obj["id"] != "" || obj["qty"] != nil {
+		t.Errorf("per column: unexpected row object %#v", obj)
+	}
+
+	// A quoted empty field stays an empty string
+	missing := base.WithEmptyValues(EmptyMissing, nil)
+	errs, _ := missing.ValidateRowQuoted(headers, row, []bool{true, false, true})
+	if len(errs) != 1 || errs[0].Field != "note" || errs[0].Keyword != "minLength" {
+		t.Errorf("quoted: expected only the quoted empty note to fail, got %+v", errs)
+	}
+
+	if err := CheckEmptyMode("blank"); err == nil {
+		t.Error("expected an error for an unknown mode")
+	}
+}
+
+func TestWithTypes(t *testing.T) {
+	base, err := NewValidatorFromReader(strings.NewReader(`{
+		"properties": {
+			"age": {"anyOf": [{"type": "integer", "minimum": 0}, {"type": "null"}]},
+			"price": {"oneOf": [{"type": "number"}, {"type": "string", "enum": ["free"]}]},
+			"active": {"type": "boolean"},
+			"zip": {"type": "integer"}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("NewValidatorFromReader: %v", err)
+	}
+	headers := []string{"age", "price", "active", "zip"}
+	row := []string{"42", "9.5", "true", "01234"}
+	fields := func(v *Validator) string {
+		errs, err := v.ValidateRow(headers, row)
+		if err != nil {
+			t.Fatalf("ValidateRow: %v", err)
+		}
+		var out []string
+		for _, e := range errs {
+			if !slices.Contains(out, e.Field) {
+				out = append(out, e.Field)
+			}
+		}
+		sort.Strings(out)
+		return strings.Join(out, " ")
+	}
+
+	// Types in anyOf and oneOf convert, but booleans only when asked for
+	if got := fields(base); got != "active" {
+		t.Errorf("schema types: got %s", got)
+	}
+	typed := base.WithTypes(map[string]string{"age": TypeInt, "price": TypeFloat, "active": TypeBool, "zip": TypeString})
+	if got := fields(typed); got != "zip" {
+		t.Errorf("with types: got %s", got)
+	}
+	if obj := typed.RowObject(headers, row); obj["age"] != 42 || obj["price"] != 9.5 || obj["active"] != true || obj["zip"] != "01234" {
+		t.Errorf("unexpected row object %#v", obj)
+	}
+	// Values that do not convert stay strings
+	if obj := typed.RowObject(headers, []string{"old", "free", "yes", "1"}); obj["age"] != "old" || obj["price"] != "free" || obj["active"] != "yes" {
+		t.Errorf("unexpected row object %#v", obj)
+	}
+	if err := CheckColumnType("date"); err == nil {
+		t.Error("Expected an unknown type rejected")
+	}
+}
+
+func TestDeclaredTypes(t *testing.T) {
+	v, err := NewValidatorFromReader(strings.NewReader(`{
+		"$defs": {
+			"quantity": {"type": "integer", "minimum": 1},
+			"amount": {"$ref": "#/$defs/money"},
+			"money": {"type": ["string", "number"], "pattern": "^[0-9.]+$"},
+			"code": {"anyOf": [{"type": "string"}, {"type": "array", "items": {"$ref": "#/$defs/code"}}]}
+		},
+		"properties": {
+			"qty": {"$ref": "#/$defs/quantity"},
+			"total": {"allOf": [{"$ref": "#/$defs/amount"}, {"maximum": 100}]},
+			"code": {"$ref": "#/$defs/code"}
+		},
+		"allOf": [{"properties": {"weight": {"oneOf": [{"type": "number"}, {"type": "null"}]}}}]
+	}`))
+	if err != nil {
+		t.Fatalf("NewValidatorFromReader: %v", err)
+	}
+	headers := []string{"qty", "total", "code", "weight"}
+	obj := v.RowObject(headers, []string{"3", "12.5", "7", "1.5"})
+	if obj["qty"] != 3 || obj["total"] != 12.5 || obj["code"] != "7" || obj["weight"] != 1.5 {
+		t.Errorf("unexpected row object %#v", obj)
+	}
+	errs, err := v.ValidateRow(headers, []string{"0", "12.5", "x", "1.5"})
+	if err != nil {
+		t.Fatalf("ValidateRow: %v", err)
+	}
+	if len(errs) == 0 || errs[0].Field != "qty" {
+		t.Errorf("Expected the minimum of qty to apply, got %v", errs)
+	}
+	for _, e := range errs {
+		if e.Field != "qty" {
+			t.Errorf("Unexpected error %v", e)
+		}
+	}
+}
+
+func TestUnexpectedColumns(t *testing.T) {
+	v, err := NewValidatorFromReader(strings.NewReader(`{
+		"$defs": {"base": {"properties": {"id": {"type": "integer"}, "extra": {}}, "additionalProperties": true}},
+		"allOf": [{"$ref": "#/$defs/base"}],
+		"properties": {"id": {"type": "integer"}},
+		"patternProperties": {"^x_": {"type": "string"}},
+		"additionalProperties": false
+	}`))
+	if err != nil {
+		t.Fatalf("NewValidatorFromReader: %v", err)
+	}
+	headers := []string{"note", "id", "x_code", "extra", "note"}
+	if got := v.UnexpectedColumns(headers); !slices.Equal(got, []string{"note", "extra"}) {
+		t.Errorf("got %v, want [note extra]", got)
+	}
+	without := v.WithoutColumns([]string{"note", "extra"})
+	errs, err := without.ValidateRow(headers, []string{"a", "1", "b", "c", "d"})
+	if err != nil || len(errs) != 0 {
+		t.Errorf("Expected the row valid without the unexpected columns, got %v, %v", errs, err)
+	}
+	if errs, _ := v.ValidateRow(headers, []string{"a", "1", "b", "c", "d"}); len(errs) == 0 {
+		t.Error("Expected the original validator to still reject them")
+	}
+}
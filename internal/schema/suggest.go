@@ -0,0 +1,67 @@
+package schema
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// formatExamples show what values of the formats the schema library asserts look like.
+var formatExamples = map[string]string{
+	"date":          "YYYY-MM-DD",
+	"date-time":     "YYYY-MM-DDThh:mm:ssZ (RFC 3339)",
+	"time":          "hh:mm:ss+00:00",
+	"duration":      "P1DT2H (ISO 8601)",
+	"email":         "name@example.com",
+	"idn-email":     "name@example.com",
+	"hostname":      "host.example.com",
+	"ipv4":          "192.0.2.1",
+	"ipv6":          "2001:db8::1",
+	"uri":           "https://example.com/path",
+	"uri-reference": "/path or https://example.com/path",
+	"uuid":          "xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx",
+}
+
+var (
+	// notValidFormat matches format messages such as "'x' is not valid 'email'".
+	notValidFormat = regexp.MustCompile(`is not valid '?([A-Za-z0-9-]+)'?$`)
+	// quotedItem matches the Go-quoted strings listed by enum and const messages.
+	quotedItem = regexp.MustCompile(`"(?:[^"\\]|\\.)*"`)
+)
+
+// suggest tells how the value of a schema error can be fixed, or returns "" when the
+// error does not tell, and the value to replace it with when the fix is that simple.
+func suggest(e ValidationError) (suggestion, replacement string) {
+	if e.Value != "" && strings.TrimSpace(e.Value) != e.Value {
+		return "remove the leading and trailing whitespace", strings.TrimSpace(e.Value)
+	}
+	switch e.Keyword {
+	case "format":
+		if m := notValidFormat.FindStringSubmatch(e.Message); m != nil {
+			if example, ok := formatExamples[m[1]]; ok {
+				return "expected format " + example, ""
+			}
+		}
+	case "type":
+		switch e.Expected {
+		case "integer":
+			return "write a whole number, without decimals, thousands separators or units", ""
+		case "number":
+			return "write a number with a dot for decimals, without thousands separators or units", ""
+		}
+	case "enum", "const":
+		// A value differing in case only is most likely meant as the allowed one
+		for _, item := range quotedItem.FindAllString(e.Message, -1) {
+			allowed, err := strconv.Unquote(item)
+			if err == nil && allowed != e.Value && strings.EqualFold(allowed, e.Value) {
+				return fmt.Sprintf("use %q: values are case-sensitive", allowed), allowed
+			}
+		}
+	case "required":
+		return "add the missing columns to the header, or drop them from required", ""
+	case "additionalProperties":
+		return "remove the columns, or add them to the schema's properties", ""
+	}
+	return "", ""
+}
@@ -0,0 +1,45 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSuggestions(t *testing.T) {
+	v, err := NewValidatorFromReader(strings.NewReader(`{
+		"type": "object",
+		"required": ["id"],
+		"properties": {
+			"id": {"type": "string"},
+			"day": {"type": "string", "format": "date"},
+			"count": {"type": "integer"},
+			"status": {"enum": ["active", "closed"]},
+			"code": {"type": "string", "pattern": "^[A-Z]+$"}
+		},
+		"additionalProperties": false
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		headers, data []string
+		want          string
+		replacement   string
+	}{
+		{[]string{"id", "day"}, []string{"1", "2024-13-01"}, "expected format YYYY-MM-DD", ""},
+		{[]string{"id", "count"}, []string{"1", "1,000"}, "write a whole number, without decimals, thousands separators or units", ""},
+		{[]string{"id", "status"}, []string{"1", "Active"}, `use "active": values are case-sensitive`, "active"},
+		{[]string{"id", "status"}, []string{"1", "pending"}, "", ""},
+		{[]string{"id", "code"}, []string{"1", "ABC "}, "remove the leading and trailing whitespace", "ABC"},
+		{[]string{"day"}, []string{"2024-01-01"}, "add the missing columns to the header, or drop them from required", ""},
+		{[]string{"id", "extra"}, []string{"1", "x"}, "remove the columns, or add them to the schema's properties", ""},
+	} {
+		errs, err := v.ValidateRow(tc.headers, tc.data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(errs) != 1 || errs[0].Suggestion != tc.want || errs[0].Replacement != tc.replacement {
+			t.Errorf("%v: want one error suggesting %q, replacing with %q, got %+v", tc.data, tc.want, tc.replacement, errs)
+		}
+	}
+}
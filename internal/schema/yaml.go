@@ -0,0 +1,131 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// isYAML reports whether a schema file is written in YAML, by its extension.
+func isYAML(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+// fromYAML converts a schema written in YAML to JSON. Scalars JSON has no type for, such
+// as unquoted dates, stay the strings they were written as.
+func fromYAML(data []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML schema: %w", err)
+	}
+	v, err := yamlValue(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse YAML schema: %w", err)
+	}
+	return json.Marshal(v)
+}
+
+// yamlValue returns the value of a YAML node as encoding/json marshals it.
+func yamlValue(n *yaml.Node) (any, error) {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) == 0 {
+			return nil, nil
+		}
+		return yamlValue(n.Content[0])
+	case yaml.AliasNode:
+		return yamlValue(n.Alias)
+	case yaml.SequenceNode:
+		list := make([]any, 0, len(n.Content))
+		for _, item := range n.Content {
+			v, err := yamlValue(item)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+		return list, nil
+	case yaml.MappingNode:
+		m := make(map[string]any, len(n.Content)/2)
+		if err := yamlMerge(m, n); err != nil {
+			return nil, err
+		}
+		return m, nil
+	}
+	switch n.ShortTag() {
+	case "!!null":
+		return nil, nil
+	case "!!bool", "!!int", "!!float":
+		var v any
+		if err := n.Decode(&v); err != nil {
+			return nil, err
+		}
+		if f, ok := v.(float64); ok && (math.IsInf(f, 0) || math.IsNaN(f)) {
+			return n.Value, nil
+		}
+		return v, nil
+	}
+	return n.Value, nil
+}
+
+// yamlMerge adds the keys of mapping n to m, those of maps merged in with << only where n
+// does not set them.
+func yamlMerge(m map[string]any, n *yaml.Node) error {
+	var merged []*yaml.Node
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, value := n.Content[i], n.Content[i+1]
+		if key.ShortTag() == "!!merge" {
+			if value.Kind == yaml.AliasNode {
+				value = value.Alias
+			}
+			if value.Kind == yaml.SequenceNode {
+				merged = append(merged, value.Content...)
+			} else {
+				merged = append(merged, value)
+			}
+			continue
+		}
+		v, err := yamlValue(value)
+		if err != nil {
+			return err
+		}
+		m[key.Value] = v
+	}
+	for _, from := range merged {
+		if from.Kind == yaml.AliasNode {
+			from = from.Alias
+		}
+		if from.Kind != yaml.MappingNode {
+			return fmt.Errorf("line %d: << must merge a mapping", from.Line)
+		}
+		base := make(map[string]any)
+		if err := yamlMerge(base, from); err != nil {
+			return err
+		}
+		for k, v := range base {
+			if _, ok := m[k]; !ok {
+				m[k] = v
+			}
+		}
+	}
+	return nil
+}
+
+// ReadFile reads the schema file at path as JSON, converting schemas written in YAML
+// (.yaml or .yml files).
+func ReadFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil || !isYAML(path) {
+		return data, err
+	}
+	return fromYAML(data)
+}
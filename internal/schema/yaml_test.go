@@ -0,0 +1,47 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestYAMLSchemas(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"orders.schema.yaml": "type: object\nproperties:\n  code:\n    $ref: defs/common.yml#/$defs/code\n  qty:\n    type: string\n",
+		"defs/common.yml":    "$defs:\n  code:\n    type: string\n    minLength: 2\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	v, err := NewValidator(filepath.Join(dir, "orders.schema.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	errs, err := v.ValidateRow([]string{"code", "qty"}, []string{"x", "1"})
+	if err != nil || len(errs) != 1 || errs[0].Field != "code" {
+		t.Errorf("Expected the referenced YAML minLength to apply, got %+v (%v)", errs, err)
+	}
+
+	data, err := ReadFile(filepath.Join(dir, "defs/common.yml"))
+	if err != nil || string(data) != `{"$defs":{"code":{"minLength":2,"type":"string"}}}` {
+		t.Errorf("Expected ReadFile to convert YAML to JSON, got %s (%v)", data, err)
+	}
+}
+
+func TestFromYAMLScalars(t *testing.T) {
+	input := "const: 2024-01-02\nenum: [1.5, true, null, 0x10, .inf, !!binary aGk=]\nbase: &base {minLength: 1}\nitems: {<<: *base, maxLength: 3}\n"
+	data, err := fromYAML([]byte(input))
+	want := `{"base":{"minLength":1},"const":"2024-01-02","enum":[1.5,true,null,16,".inf","aGk="],"items":{"maxLength":3,"minLength":1}}`
+	if err != nil || string(data) != want {
+		t.Errorf("Expected scalars JSON has no type for as written, got %s (%v)", data, err)
+	}
+}
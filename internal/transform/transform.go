@@ -0,0 +1,138 @@
+// Package transform rewrites column values into a canonical form before validation, e.g.
+// trimming whitespace or reformatting dates, so schemas can describe the canonical values.
+package transform
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Steps lists the supported transform steps. "date" takes an argument.
+var Steps = []string{"trim", "upper", "lower", "strip_currency", "date:FROM->TO"}
+
+// dateTokens map date pattern tokens to Go layout elements, longest first.
+var dateTokens = []struct{ token, layout string }{
+	{"YYYY", "2006"}, {"YY", "06"}, {"MM", "01"}, {"M", "1"}, {"DD", "02"}, {"D", "2"},
+	{"HH", "15"}, {"mm", "04"}, {"ss", "05"},
+}
+
+// step rewrites a single value.
+type step func(string) string
+
+// Transformer applies the steps configured for each column to rows.
+type Transformer struct {
+	steps   map[string][]step // Column -> steps, in order
+	indexes map[int][]step    // Field index -> steps, once the header is known
+}
+
+// New compiles the steps of each column, e.g. {"amount": {"trim", "strip_currency"},
+// "day": {"date:DD/MM/YYYY->YYYY-MM-DD"}}. Steps run in the order given.
+func New(columns map[string][]string) (*Transformer, error) {
+	t := &Transformer{steps: make(map[string][]step, len(columns))}
+	names := make([]string, 0, len(columns))
+	for column := range columns {
+		names = append(names, column)
+	}
+	sort.Strings(names)
+	for _, column := range names {
+		for _, spec := range columns[column] {
+			s, err := parseStep(spec)
+			if err != nil {
+				return nil, fmt.Errorf("column '%s': %w", column, err)
+			}
+			t.steps[column] = append(t.steps[column], s)
+		}
+	}
+	return t, nil
+}
+
+// parseStep compiles a step spec: a name, optionally followed by ":" and its argument.
+func parseStep(spec string) (step, error) {
+	name, arg, _ := strings.Cut(strings.TrimSpace(spec), ":")
+	switch name {
+	case "trim":
+		return strings.TrimSpace, nil
+	case "upper":
+		return strings.ToUpper, nil
+	case "lower":
+		return strings.ToLower, nil
+	case "strip_currency":
+		return stripCurrency, nil
+	case "date":
+		from, to, ok := strings.Cut(arg, "->")
+		if !ok || from == "" || to == "" {
+			return nil, fmt.Errorf("date step needs FROM->TO patterns, e.g. date:DD/MM/YYYY->YYYY-MM-DD, got '%s'", spec)
+		}
+		return reformatDate(layout(from), layout(to)), nil
+	}
+	return nil, fmt.Errorf("unknown transform step '%s' (supported: %s)", spec, strings.Join(Steps, ", "))
+}
+
+// stripCurrency removes currency symbols, e.g. "$ 1,200.50" becomes "1,200.50".
+func stripCurrency(v string) string {
+	return strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Sc, r) {
+			return -1
+		}
+		return r
+	}, v))
+}
+
+// reformatDate rewrites dates from one layout to another. Values that do not parse are
+// left unchanged, so validation reports them as they are.
+func reformatDate(from, to string) step {
+	return func(v string) string {
+		t, err := time.Parse(from, v)
+		if err != nil {
+			return v
+		}
+		return t.Format(to)
+	}
+}
+
+// layout converts a pattern such as "DD/MM/YYYY HH:mm" to a Go time layout.
+func layout(pattern string) string {
+	var b strings.Builder
+	for i := 0; i < len(pattern); {
+		matched := false
+		for _, dt := range dateTokens {
+			if strings.HasPrefix(pattern[i:], dt.token) {
+				b.WriteString(dt.layout)
+				i += len(dt.token)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			b.WriteByte(pattern[i])
+			i++
+		}
+	}
+	return b.String()
+}
+
+// Start maps the configured columns to field indexes. Every field under a configured
+// header is transformed; columns missing from headers are ignored.
+func (t *Transformer) Start(headers []string) {
+	t.indexes = make(map[int][]step, len(t.steps))
+	for i, h := range headers {
+		if steps, ok := t.steps[h]; ok {
+			t.indexes[i] = steps
+		}
+	}
+}
+
+// Row rewrites the configured fields of a row in place.
+func (t *Transformer) Row(fields []string) {
+	for i, steps := range t.indexes {
+		if i >= len(fields) {
+			continue
+		}
+		for _, s := range steps {
+			fields[i] = s(fields[i])
+		}
+	}
+}
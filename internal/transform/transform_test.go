@@ -0,0 +1,45 @@
+package transform
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTransformer(t *testing.T) {
+	tr, err := New(map[string][]string{
+		"amount":  {"trim", "strip_currency"},
+		"country": {"trim", "upper"},
+		"email":   {"lower"},
+		"day":     {"date:DD/MM/YYYY->YYYY-MM-DD"},
+		"at":      {"date:D.M.YY HH:mm->YYYY-MM-DDTHH:mm:00"},
+		"missing": {"trim"},
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	tr.Start([]string{"amount", "country", "email", "day", "at", "note"})
+
+	row := []string{" € 1,200.50 ", " de", "Ann@Example.COM", "31/12/2024", "5.3.24 09:30", " kept "}
+	tr.Row(row)
+	want := []string{"1,200.50", "DE", "ann@example.com", "2024-12-31", "2024-03-05T09:30:00", " kept "}
+	if !reflect.DeepEqual(row, want) {
+		t.Errorf("want %q, got %q", want, row)
+	}
+
+	// Dates that do not match the pattern are left for validation to report
+	row = []string{"$5", "", "", "2024-12-31", "soon"}
+	tr.Row(row)
+	if row[0] != "5" || row[3] != "2024-12-31" || row[4] != "soon" {
+		t.Errorf("unexpected short row %q", row)
+	}
+}
+
+func TestNewInvalid(t *testing.T) {
+	for _, spec := range []string{"titlecase", "date", "date:DD/MM/YYYY"} {
+		_, err := New(map[string][]string{"c": {spec}})
+		if err == nil || !strings.Contains(err.Error(), "column 'c'") {
+			t.Errorf("%s: want an error naming the column, got %v", spec, err)
+		}
+	}
+}
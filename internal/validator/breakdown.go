@@ -0,0 +1,63 @@
+package validator
+
+import (
+	"math"
+	"sort"
+)
+
+// Breakdown counts a file's errors per column and per rule, so the columns causing most
+// of the problems stand out.
+type Breakdown struct {
+	Columns []Count `json:"columns"` // Errors without a field count under "(row)"
+	Rules   []Count `json:"rules"`   // Errors without a rule count under their type
+}
+
+// Count is the number of errors in one column or of one rule.
+type Count struct {
+	Name    string  `json:"name"`
+	Errors  int     `json:"errors"`
+	Percent float64 `json:"percent"` // Share of all errors, 0 to 100, rounded to one decimal
+}
+
+// RowField is the Breakdown column of errors about a whole row.
+const RowField = "(row)"
+
+// Summarize sets Breakdown from the errors, largest counts first, counting errors rolled
+// up by Dedupe as the findings they stand for. Results without errors get no breakdown.
+func (r *Results) Summarize() {
+	r.Breakdown = nil
+	if len(r.Errors) == 0 {
+		return
+	}
+	columns, rules := make(map[string]int), make(map[string]int)
+	for _, e := range r.Errors {
+		field := e.Field
+		if field == "" || field == "row" {
+			field = RowField
+		}
+		n := max(e.Occurrences, 1)
+		columns[field] += n
+		rule := e.RuleID
+		if rule == "" {
+			rule = e.Type
+		}
+		rules[rule] += n
+	}
+	total := r.ErrorCount()
+	r.Breakdown = &Breakdown{Columns: counts(columns, total), Rules: counts(rules, total)}
+}
+
+// counts sorts the counts by size, then name.
+func counts(m map[string]int, total int) []Count {
+	out := make([]Count, 0, len(m))
+	for name, n := range m {
+		out = append(out, Count{Name: name, Errors: n, Percent: math.Round(float64(n)/float64(total)*1000) / 10})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Errors != out[j].Errors {
+			return out[i].Errors > out[j].Errors
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
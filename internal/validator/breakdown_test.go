@@ -0,0 +1,29 @@
+package validator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSummarize(t *testing.T) {
+	r := &Results{Errors: []Finding{
+		{Severity: SeverityError, Field: "phone", Type: "schema", RuleID: "SCH004"},
+		{Severity: SeverityError, Field: "phone", Type: "schema", RuleID: "SCH004"},
+		{Severity: SeverityError, Field: "email", Type: "schema", RuleID: "SCH005"},
+		{Severity: SeverityError, Field: "row", Type: "structure"},
+	}}
+	r.Summarize()
+	want := &Breakdown{
+		Columns: []Count{{"phone", 2, 50}, {"(row)", 1, 25}, {"email", 1, 25}},
+		Rules:   []Count{{"SCH004", 2, 50}, {"SCH005", 1, 25}, {"structure", 1, 25}},
+	}
+	if !reflect.DeepEqual(r.Breakdown, want) {
+		t.Errorf("expected %+v, got %+v", want, r.Breakdown)
+	}
+
+	r = &Results{}
+	r.Summarize()
+	if r.Breakdown != nil {
+		t.Errorf("expected no breakdown without errors, got %+v", r.Breakdown)
+	}
+}
@@ -0,0 +1,54 @@
+package validator
+
+import (
+	"io"
+	"math"
+)
+
+// Coverage reports how much of the input was validated when the time budget ran out
+// before the end of the input.
+type Coverage struct {
+	TimeBudget         string  `json:"time_budget"`
+	RowsScanned        int     `json:"rows_scanned"`
+	BytesScanned       int64   `json:"bytes_scanned"`
+	TotalBytes         int64   `json:"total_bytes,omitempty"`          // Input size, when known
+	Percent            float64 `json:"percent,omitempty"`              // Share of TotalBytes scanned
+	EstimatedTotalRows int     `json:"estimated_total_rows,omitempty"` // Extrapolated from the rows per byte scanned
+}
+
+// newCoverage estimates the coverage of a run that stopped after rows data rows and
+// bytes input bytes (counted as read by the parser, so slightly ahead of the rows).
+func newCoverage(budget string, rows int, bytes, total int64) *Coverage {
+	c := &Coverage{TimeBudget: budget, RowsScanned: rows, BytesScanned: bytes}
+	if total > 0 && bytes > 0 {
+		if bytes > total {
+			bytes = total
+		}
+		c.TotalBytes = total
+		c.Percent = math.Round(float64(bytes)/float64(total)*1000) / 10
+		c.EstimatedTotalRows = int(math.Round(float64(rows) * float64(total) / float64(bytes)))
+	}
+	return c
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// Interrupted reports whether ch, an Options.Interrupt channel, has been closed.
+func Interrupted(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,78 @@
+package validator
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// slowReader returns at most 64 bytes per read, after a pause.
+type slowReader struct {
+	r io.Reader
+}
+
+func (s slowReader) Read(p []byte) (int, error) {
+	time.Sleep(time.Millisecond)
+	if len(p) > 64 {
+		p = p[:64]
+	}
+	return s.r.Read(p)
+}
+
+func TestTimeBudget(t *testing.T) {
+	input := "id\n" + strings.Repeat("12345\n", 5000)
+	results, err := NewWithOptions(slowReader{strings.NewReader(input)}, Options{
+		Delimiter:   ",",
+		TimeBudget:  50 * time.Millisecond,
+		Size:        int64(len(input)),
+		Fingerprint: true,
+	}).Validate()
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	c := results.Coverage
+	if c == nil || c.TimeBudget != "50ms" || c.RowsScanned != results.TotalRows || c.RowsScanned == 0 || c.RowsScanned >= 5000 {
+		t.Fatalf("Expected partial coverage, got %+v (rows %d)", c, results.TotalRows)
+	}
+	if c.Percent <= 0 || c.Percent >= 100 || c.EstimatedTotalRows < 4000 || c.EstimatedTotalRows > 6000 {
+		t.Errorf("Expected an estimate near 5000 rows, got %+v", c)
+	}
+	if results.SHA256 != "" {
+		t.Error("Expected no hashes for a partial run")
+	}
+
+	results, err = NewWithOptions(strings.NewReader("id\n1\n"), Options{Delimiter: ",", TimeBudget: time.Minute}).Validate()
+	if err != nil || results.Coverage != nil {
+		t.Errorf("Expected full coverage within the budget, got %+v, %v", results, err)
+	}
+}
+
+func TestInterrupt(t *testing.T) {
+	interrupt := make(chan struct{})
+	close(interrupt)
+	results, err := NewWithOptions(strings.NewReader("id\n1\n2\n"), Options{
+		Delimiter:   ",",
+		Interrupt:   interrupt,
+		Fingerprint: true,
+	}).Validate()
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if !results.Partial || results.Coverage != nil || results.TotalRows != 0 || results.SHA256 != "" {
+		t.Errorf("Expected a partial run without coverage or hashes, got %+v", results)
+	}
+	if batch := NewBatch([]*Results{results}, 0); !batch.Partial {
+		t.Error("Expected a batch with a partial file to be partial")
+	}
+}
+
+func TestNewCoverage(t *testing.T) {
+	c := newCoverage("1s", 100, 250, 1000)
+	if c.Percent != 25 || c.EstimatedTotalRows != 400 || c.TotalBytes != 1000 {
+		t.Errorf("Unexpected coverage %+v", c)
+	}
+	if c := newCoverage("1s", 100, 250, 0); c.Percent != 0 || c.EstimatedTotalRows != 0 {
+		t.Errorf("Expected no estimate for an unknown size, got %+v", c)
+	}
+}
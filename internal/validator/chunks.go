@@ -0,0 +1,100 @@
+package validator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/csvlinter/csvlinter/internal/parser"
+	"github.com/csvlinter/csvlinter/internal/rules"
+)
+
+// chunkState follows the chunks of a concatenated export, each after the first starting
+// with a repeat of the header. Chunk headers are skipped rather than reported; a chunk
+// header listing the columns in another order, and a chunk without data rows, are not.
+type chunkState struct {
+	headers     []string
+	sorted      []string // Normalized header names, sorted, for spotting reordered chunk headers
+	requireData bool
+	first       int // Line of the file's header
+	line        int // Line of the current chunk's header
+	rows        int // Data rows in the current chunk
+}
+
+func newChunkState(headers []string, line int, requireData bool) *chunkState {
+	c := &chunkState{headers: headers, requireData: requireData, first: line, line: line}
+	for _, h := range headers {
+		c.sorted = append(c.sorted, chunkName(h))
+	}
+	slices.Sort(c.sorted)
+	return c
+}
+
+// chunkName normalizes a header name as parser.RepeatsHeader compares them.
+func chunkName(name string) string {
+	return strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+}
+
+// row tells whether row is the header of a new chunk, and returns the findings about the
+// chunk it ends and the header itself. Other rows are counted as the current chunk's data.
+func (c *chunkState) row(row *parser.Row) (bool, []Finding) {
+	if len(row.Data) != len(c.headers) {
+		c.rows++
+		return false, nil
+	}
+	reordered := false
+	if !parser.RepeatsHeader(row.Data, c.headers) {
+		names := make([]string, len(row.Data))
+		for i, f := range row.Data {
+			names[i] = chunkName(f)
+		}
+		slices.Sort(names)
+		if reordered = slices.Equal(names, c.sorted); !reordered {
+			c.rows++
+			return false, nil
+		}
+	}
+
+	var found []Finding
+	if c.rows == 0 {
+		found = append(found, c.empty())
+	}
+	if reordered {
+		found = append(found, Finding{
+			Severity:   SeverityError,
+			Location:   Location{Line: row.LineNumber},
+			Field:      "row",
+			Message:    fmt.Sprintf("chunk header lists the columns of line %d in a different order", c.first),
+			Suggestion: "reorder the columns of this chunk to match the first header",
+			Type:       "structure",
+			RuleID:     rules.RepeatedHeader,
+		})
+	}
+	c.line, c.rows = row.LineNumber, 0
+	return true, found
+}
+
+// finish returns the finding about the last chunk when it has no data rows. A file of one
+// chunk is left to the check of the whole file.
+func (c *chunkState) finish() []Finding {
+	if c.rows > 0 || c.line == c.first {
+		return nil
+	}
+	return []Finding{c.empty()}
+}
+
+// empty returns the finding about the current chunk having no data rows.
+func (c *chunkState) empty() Finding {
+	e := Finding{
+		Severity:   SeverityWarning,
+		Location:   Location{Line: c.line},
+		Message:    "chunk has a header but no data rows",
+		Suggestion: "check that the export of this chunk did not fail",
+		Type:       "structure",
+		RuleID:     rules.NoData,
+	}
+	if c.requireData {
+		e.Severity = SeverityError
+	}
+	return e
+}
@@ -0,0 +1,55 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/rules"
+	"github.com/csvlinter/csvlinter/internal/schema"
+)
+
+func TestChunked(t *testing.T) {
+	sv, err := schema.NewValidatorFromReader(strings.NewReader(`{"type": "object", "properties": {"id": {"type": "integer"}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	validate := func(input string, requireData bool) *Results {
+		t.Helper()
+		results, err := NewWithOptions(strings.NewReader(input), Options{
+			Delimiter:   ",",
+			Schemas:     []Schema{{Validator: sv}},
+			Chunked:     true,
+			RequireData: requireData,
+		}).Validate()
+		if err != nil {
+			t.Fatalf("Validate failed: %v", err)
+		}
+		return results
+	}
+
+	// Chunk headers are skipped, and rows of every chunk are still validated
+	results := validate("id,name\n1,a\nid,name\n2,b\nID, Name\nx,c\n", false)
+	if len(results.Errors) != 1 || results.Errors[0].RuleID != rules.SchemaType || results.Errors[0].Line != 6 || results.TotalRows != 3 || len(results.Warnings) != 0 {
+		t.Errorf("Expected chunk headers skipped and line 6 failing the schema, got %d rows, %+v %+v", results.TotalRows, results.Errors, results.Warnings)
+	}
+
+	// A reordered chunk header is an error, and its rows are still validated in header order;
+	// empty chunks, the last one included, are warnings
+	results = validate("id,name\n1,a\nname,id\nb,2\nid,name\nid,name\n3,c\nid,name\n", false)
+	if len(results.Errors) != 2 || results.Errors[0].RuleID != rules.RepeatedHeader || results.Errors[0].Line != 3 || results.Errors[1].Line != 4 {
+		t.Errorf("Expected the reordered chunk header on line 3 and its row failing, got %+v", results.Errors)
+	}
+	if len(results.Warnings) != 2 || results.Warnings[0].RuleID != rules.NoData || results.Warnings[0].Line != 5 || results.Warnings[1].Line != 8 {
+		t.Errorf("Expected empty chunks on lines 5 and 8, got %+v", results.Warnings)
+	}
+
+	results = validate("id,name\nid,name\n1,a\n", true)
+	if len(results.Errors) != 1 || results.Errors[0].RuleID != rules.NoData || results.Errors[0].Line != 1 {
+		t.Errorf("Expected RequireData to fail the empty first chunk, got %+v", results.Errors)
+	}
+	// A file of one empty chunk is reported once, as a header-only file
+	results = validate("id,name\n", false)
+	if len(results.Warnings) != 1 || results.Warnings[0].Message != "file has a header but no data rows" {
+		t.Errorf("Expected one header-only warning, got %+v", results.Warnings)
+	}
+}
@@ -0,0 +1,60 @@
+package validator
+
+// findingKey identifies findings that Dedupe rolls into one.
+type findingKey struct {
+	rule, field, message, schema string
+}
+
+// Dedupe rolls errors, and warnings, with the same rule, field and message into one
+// finding at the place of the first, with the number of them in Occurrences and the line
+// of the last in LastLine, so a problem repeated on every row is reported once. The
+// value and fix kept are those of the first. Findings without a rule are keyed by their type, and
+// findings of different schemas are kept apart.
+func (r *Results) Dedupe() {
+	r.Errors = dedupe(r.Errors)
+	r.Warnings = dedupe(r.Warnings)
+}
+
+func dedupe(findings []Finding) []Finding {
+	if len(findings) < 2 {
+		return findings
+	}
+	first := make(map[findingKey]int, len(findings))
+	out := findings[:0:0]
+	for _, f := range findings {
+		rule := f.RuleID
+		if rule == "" {
+			rule = f.Type
+		}
+		key := findingKey{rule, f.Field, f.Message, f.Schema}
+		i, seen := first[key]
+		if !seen {
+			first[key] = len(out)
+			out = append(out, f)
+			continue
+		}
+		kept := &out[i]
+		kept.Occurrences = max(kept.Occurrences, 1) + max(f.Occurrences, 1)
+		kept.LastLine = max(kept.LastLine, kept.Line, f.Line, f.LastLine)
+	}
+	return out
+}
+
+// ErrorCount returns the number of errors, counting each rolled up by Dedupe as the
+// findings it stands for.
+func (r *Results) ErrorCount() int {
+	return count(r.Errors)
+}
+
+// WarningCount is ErrorCount for warnings.
+func (r *Results) WarningCount() int {
+	return count(r.Warnings)
+}
+
+func count(findings []Finding) int {
+	n := 0
+	for _, f := range findings {
+		n += max(f.Occurrences, 1)
+	}
+	return n
+}
@@ -0,0 +1,47 @@
+package validator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDedupe(t *testing.T) {
+	r := &Results{
+		Errors: []Finding{
+			{Severity: SeverityError, Location: Location{Line: 2}, Field: "phone", Message: "does not match pattern", Value: "x", Type: "schema", RuleID: "SCH004"},
+			{Severity: SeverityError, Location: Location{Line: 3}, Field: "email", Message: "not an email", Type: "schema", RuleID: "SCH001"},
+			{Severity: SeverityError, Location: Location{Line: 5}, Field: "phone", Message: "does not match pattern", Value: "y", Type: "schema", RuleID: "SCH004"},
+			{Severity: SeverityError, Location: Location{Line: 9}, Field: "phone", Message: "does not match pattern", Value: "z", Type: "schema", RuleID: "SCH004"},
+			{Severity: SeverityError, Location: Location{Line: 9}, Field: "phone", Message: "does not match pattern", Type: "schema", RuleID: "SCH004", Schema: "other.json"},
+		},
+		Warnings: []Finding{
+			{Severity: SeverityWarning, Location: Location{Line: 4}, Message: "ragged", Type: "structure"},
+			{Severity: SeverityWarning, Location: Location{Line: 7}, Message: "ragged", Type: "structure"},
+		},
+	}
+	r.Dedupe()
+	want := []Finding{
+		{Severity: SeverityError, Location: Location{Line: 2}, Field: "phone", Message: "does not match pattern", Value: "x", Type: "schema", RuleID: "SCH004", Occurrences: 3, LastLine: 9},
+		{Severity: SeverityError, Location: Location{Line: 3}, Field: "email", Message: "not an email", Type: "schema", RuleID: "SCH001"},
+		{Severity: SeverityError, Location: Location{Line: 9}, Field: "phone", Message: "does not match pattern", Type: "schema", RuleID: "SCH004", Schema: "other.json"},
+	}
+	if !reflect.DeepEqual(r.Errors, want) {
+		t.Errorf("expected %+v, got %+v", want, r.Errors)
+	}
+	if len(r.Warnings) != 1 || r.Warnings[0].Occurrences != 2 || r.Warnings[0].LastLine != 7 {
+		t.Errorf("expected the warnings rolled up, got %+v", r.Warnings)
+	}
+	if r.ErrorCount() != 5 || r.WarningCount() != 2 {
+		t.Errorf("expected counts of 5 errors and 2 warnings, got %d and %d", r.ErrorCount(), r.WarningCount())
+	}
+
+	// Deduping again keeps the counts
+	r.Dedupe()
+	if r.ErrorCount() != 5 {
+		t.Errorf("expected 5 errors after a second Dedupe, got %d", r.ErrorCount())
+	}
+	r.Summarize()
+	if c := r.Breakdown.Columns[0]; c.Name != "phone" || c.Errors != 4 || c.Percent != 80 {
+		t.Errorf("expected the breakdown to count rolled up errors, got %+v", r.Breakdown.Columns)
+	}
+}
@@ -0,0 +1,101 @@
+package validator
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+)
+
+// Deferred is implemented by checks that report some rows only when they Finish, such as
+// repeats found among keys spilled to disk, long after the rows were read. Deferred
+// reports whether the row last given to Row may be one of them.
+type Deferred interface {
+	Deferred() bool
+}
+
+// rowLocation is what findings about a row carry besides its line: where it starts, its
+// key and the values of the columns to include.
+type rowLocation struct {
+	Line    int               `json:"line"`
+	Offset  int64             `json:"offset"`
+	Key     string            `json:"key,omitempty"`
+	Columns map[string]string `json:"columns,omitempty"`
+}
+
+// deferredRows keeps the locations of the rows checks may report when they Finish in a
+// temporary file, in file order, to fill in those findings. Locations that cannot be
+// stored are left out of the findings.
+type deferredRows struct {
+	f      *os.File
+	w      *bufio.Writer
+	failed bool
+}
+
+// add stores the location of a row.
+func (d *deferredRows) add(loc rowLocation) {
+	if d.failed {
+		return
+	}
+	if d.f == nil {
+		f, err := os.CreateTemp("", "csvlinter-rows-*")
+		if err != nil {
+			d.failed = true
+			return
+		}
+		d.f, d.w = f, bufio.NewWriter(f)
+	}
+	if err := json.NewEncoder(d.w).Encode(loc); err != nil {
+		d.failed = true
+	}
+}
+
+// fill gives the findings of stored rows that lack them their offset, key and columns.
+func (d *deferredRows) fill(lists ...[]Finding) {
+	if d.f == nil || d.failed {
+		return
+	}
+	byLine := make(map[int][]*Finding)
+	for _, list := range lists {
+		for i := range list {
+			if list[i].Line > 0 && list[i].ByteOffset == 0 {
+				byLine[list[i].Line] = append(byLine[list[i].Line], &list[i])
+			}
+		}
+	}
+	if len(byLine) == 0 {
+		return
+	}
+	if err := d.w.Flush(); err != nil {
+		return
+	}
+	if _, err := d.f.Seek(0, io.SeekStart); err != nil {
+		return
+	}
+	dec := json.NewDecoder(bufio.NewReader(d.f))
+	for len(byLine) > 0 {
+		var loc rowLocation
+		if err := dec.Decode(&loc); err != nil {
+			return
+		}
+		for _, f := range byLine[loc.Line] {
+			f.ByteOffset = loc.Offset
+			if f.Key == "" {
+				f.Key = loc.Key
+			}
+			if f.Columns == nil {
+				f.Columns = loc.Columns
+			}
+		}
+		delete(byLine, loc.Line)
+	}
+}
+
+// close removes the stored locations.
+func (d *deferredRows) close() {
+	if d.f != nil {
+		d.f.Close()
+		os.Remove(d.f.Name())
+		d.f = nil
+	}
+}
@@ -0,0 +1,121 @@
+package validator
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/csvlinter/csvlinter/internal/parser"
+	"github.com/csvlinter/csvlinter/internal/rules"
+)
+
+// Envelope describes records that wrap the data in some feeds: a header record before the
+// column header (e.g. "HDR,20240101") and a trailer record after the last data row (e.g.
+// "TRAILER,12345") declaring the row count or a control total. Envelope records are
+// recognized by their first field and are not validated as data.
+type Envelope struct {
+	HeaderPrefix   string // First field of a record before the column header; the record must be present
+	TrailerPrefix  string // First field of the trailer record; the record must be present and last
+	CountField     int    // 1-based trailer field with the number of data rows (0 = not checked)
+	ChecksumField  int    // 1-based trailer field with the sum of ChecksumColumn (0 = not checked)
+	ChecksumColumn string // Column summed for ChecksumField
+}
+
+// envelopeState checks the envelope while rows stream past.
+type envelopeState struct {
+	env         *Envelope
+	sumIndex    int
+	sum         *big.Rat
+	trailer     []string
+	trailerLine int
+	lastLine    int
+	reported    bool // a data row after the trailer was reported
+}
+
+// newEnvelopeState prepares the trailer checks for a file with the given column positions.
+func newEnvelopeState(env *Envelope, columns map[string]int) (*envelopeState, []Finding) {
+	s := &envelopeState{env: env, sumIndex: -1, sum: new(big.Rat)}
+	if env.ChecksumField > 0 {
+		s.sumIndex = columns[env.ChecksumColumn] - 1
+		if s.sumIndex < 0 {
+			return s, []Finding{s.error(1, fmt.Sprintf("checksum column '%s' not found in header", env.ChecksumColumn))}
+		}
+	}
+	return s, nil
+}
+
+// row handles a non-empty record. It reports whether the record is the trailer, which is
+// then excluded from data validation.
+func (s *envelopeState) row(row *parser.Row) ([]Finding, bool) {
+	s.lastLine = row.LineNumber
+	if s.env.TrailerPrefix != "" && len(row.Data) > 0 && row.Data[0] == s.env.TrailerPrefix && s.trailer == nil {
+		s.trailer = row.Data
+		s.trailerLine = row.LineNumber
+		return nil, true
+	}
+	var errs []Finding
+	if s.trailer != nil && !s.reported {
+		s.reported = true
+		errs = append(errs, s.error(row.LineNumber, "data row after the trailer record"))
+	}
+	if s.sumIndex >= 0 && s.sumIndex < len(row.Data) {
+		// Values that are not numbers get schema errors, not a checksum error
+		if v, ok := new(big.Rat).SetString(strings.TrimSpace(row.Data[s.sumIndex])); ok {
+			s.sum.Add(s.sum, v)
+		}
+	}
+	return errs, false
+}
+
+// finish checks the trailer against the data rows seen.
+func (s *envelopeState) finish(dataRows int) []Finding {
+	if s.env.TrailerPrefix == "" {
+		return nil
+	}
+	if s.trailer == nil {
+		return []Finding{s.error(s.lastLine, fmt.Sprintf("missing trailer record starting with '%s'", s.env.TrailerPrefix))}
+	}
+	var errs []Finding
+	if s.env.CountField > 0 {
+		declared, ok := s.field(s.env.CountField, "count", &errs)
+		if ok {
+			if n, isNum := new(big.Rat).SetString(declared); !isNum || !n.IsInt() {
+				errs = append(errs, s.error(s.trailerLine, fmt.Sprintf("trailer count '%s' is not a whole number", declared)))
+			} else if n.Cmp(big.NewRat(int64(dataRows), 1)) != 0 {
+				errs = append(errs, s.error(s.trailerLine, fmt.Sprintf("trailer declares %s rows, found %d", declared, dataRows)))
+			}
+		}
+	}
+	if s.env.ChecksumField > 0 && s.sumIndex >= 0 {
+		declared, ok := s.field(s.env.ChecksumField, "checksum", &errs)
+		if ok {
+			if n, isNum := new(big.Rat).SetString(declared); !isNum {
+				errs = append(errs, s.error(s.trailerLine, fmt.Sprintf("trailer checksum '%s' is not a number", declared)))
+			} else if n.Cmp(s.sum) != 0 {
+				errs = append(errs, s.error(s.trailerLine, fmt.Sprintf("trailer checksum %s does not match the sum of %s, %s", declared, s.env.ChecksumColumn, formatRat(s.sum))))
+			}
+		}
+	}
+	return errs
+}
+
+// field returns the trimmed 1-based trailer field, recording an error when it is absent.
+func (s *envelopeState) field(n int, name string, errs *[]Finding) (string, bool) {
+	if n > len(s.trailer) {
+		*errs = append(*errs, s.error(s.trailerLine, fmt.Sprintf("trailer has no %s field %d", name, n)))
+		return "", false
+	}
+	return strings.TrimSpace(s.trailer[n-1]), true
+}
+
+func (s *envelopeState) error(line int, message string) Finding {
+	return Finding{Severity: SeverityError, Location: Location{Line: line}, Message: message, Type: "structure", RuleID: rules.Envelope}
+}
+
+// formatRat prints r as an integer when it is one, else in decimal without trailing zeros.
+func formatRat(r *big.Rat) string {
+	if r.IsInt() {
+		return r.Num().String()
+	}
+	return strings.TrimRight(r.FloatString(10), "0")
+}
@@ -0,0 +1,51 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/rules"
+)
+
+func TestEnvelope(t *testing.T) {
+	env := &Envelope{HeaderPrefix: "HDR", TrailerPrefix: "TRAILER", CountField: 2, ChecksumField: 3, ChecksumColumn: "amount"}
+	validate := func(input string) *Results {
+		t.Helper()
+		results, err := NewWithOptions(strings.NewReader(input), Options{Delimiter: ",", Envelope: env}).Validate()
+		if err != nil {
+			t.Fatalf("Validate failed: %v", err)
+		}
+		return results
+	}
+
+	results := validate("HDR,20240101\nid,amount\n1,10.25\n2,5.50\nTRAILER,2,15.75\n")
+	if !results.Valid || results.TotalRows != 2 {
+		t.Errorf("Expected a valid file with 2 data rows, got %+v", results)
+	}
+
+	results = validate("HDR,20240101\nid,amount\n1,10.25\n2,5.50\nTRAILER,3,15.70\n")
+	if len(results.Errors) != 2 {
+		t.Fatalf("Expected count and checksum errors, got %+v", results.Errors)
+	}
+	if e := results.Errors[1]; e.Line != 5 || e.RuleID != rules.Envelope || e.Message != "trailer declares 3 rows, found 2" {
+		t.Errorf("Unexpected count error: %+v", e)
+	}
+	if e := results.Errors[0]; e.Message != "trailer checksum 15.70 does not match the sum of amount, 15.75" {
+		t.Errorf("Unexpected checksum error: %+v", e)
+	}
+
+	results = validate("id,amount\n1,10\nTRAILER,1,10\n2,5\n")
+	var messages []string
+	for _, e := range results.Errors {
+		messages = append(messages, e.Message)
+	}
+	got := strings.Join(messages, "; ")
+	if !strings.Contains(got, "missing header record starting with 'HDR'") || !strings.Contains(got, "data row after the trailer record") {
+		t.Errorf("Expected missing header and misplaced trailer errors, got %q", got)
+	}
+
+	results = validate("HDR\nid,amount\n1,10\n")
+	if len(results.Errors) != 1 || results.Errors[0].Message != "missing trailer record starting with 'TRAILER'" {
+		t.Errorf("Expected a missing trailer error, got %+v", results.Errors)
+	}
+}
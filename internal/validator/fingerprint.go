@@ -0,0 +1,67 @@
+package validator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io"
+	"sort"
+
+	"github.com/csvlinter/csvlinter/internal/schema"
+)
+
+// fingerprinter computes Results.SHA256 over the input bytes and Results.Fingerprint over
+// the rows as the schema reads them. The fingerprint ignores column order, delimiter,
+// quoting, line endings and the spelling of numbers in columns the schema types, so two
+// files with the same content under the schema share it.
+type fingerprinter struct {
+	input  io.Reader
+	bytes  hash.Hash
+	rows   hash.Hash
+	schema *schema.Validator // nil: values are compared as strings
+}
+
+// newFingerprinter wraps input so that everything read from it is hashed.
+func newFingerprinter(input io.Reader, s *schema.Validator) *fingerprinter {
+	f := &fingerprinter{bytes: sha256.New(), rows: sha256.New(), schema: s}
+	f.input = io.TeeReader(input, f.bytes)
+	return f
+}
+
+// header adds the sorted column names.
+func (f *fingerprinter) header(headers []string) {
+	sorted := append([]string(nil), headers...)
+	sort.Strings(sorted)
+	f.write(sorted)
+}
+
+// row adds a data row: an object keyed by column when it has a field per column, the bare
+// fields otherwise.
+func (f *fingerprinter) row(headers, data []string) {
+	if len(headers) != len(data) {
+		f.write(data)
+		return
+	}
+	if f.schema != nil {
+		f.write(f.schema.RowObject(headers, data))
+		return
+	}
+	obj := make(map[string]string, len(headers))
+	for i, h := range headers {
+		obj[h] = data[i]
+	}
+	f.write(obj)
+}
+
+func (f *fingerprinter) write(v any) {
+	// encoding/json sorts map keys and prints numbers canonically
+	b, _ := json.Marshal(v)
+	f.rows.Write(append(b, '\n'))
+}
+
+// finish reads any input the parser left unread and returns both hashes in hex.
+func (f *fingerprinter) finish() (sha string, fingerprint string) {
+	_, _ = io.Copy(io.Discard, f.input)
+	return hex.EncodeToString(f.bytes.Sum(nil)), hex.EncodeToString(f.rows.Sum(nil))
+}
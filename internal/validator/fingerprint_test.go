@@ -0,0 +1,60 @@
+package validator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/schema"
+)
+
+func TestFingerprint(t *testing.T) {
+	sv, err := schema.NewValidatorFromReader(strings.NewReader(`{"type":"object","properties":{"amount":{"type":"number"}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	validate := func(input, delimiter string) *Results {
+		t.Helper()
+		results, err := NewWithOptions(strings.NewReader(input), Options{
+			Delimiter:   delimiter,
+			Schemas:     []Schema{{Validator: sv}},
+			Fingerprint: true,
+		}).Validate()
+		if err != nil {
+			t.Fatalf("Validate failed: %v", err)
+		}
+		return results
+	}
+
+	input := "id,amount\n1,10.50\n2,3\n"
+	base := validate(input, ",")
+	sum := sha256.Sum256([]byte(input))
+	if base.SHA256 != hex.EncodeToString(sum[:]) {
+		t.Errorf("Expected the SHA-256 of the input, got %s", base.SHA256)
+	}
+
+	// Same content: other column order, delimiter, quoting, line endings and number spelling
+	same := validate("amount;\"id\"\r\n10.5;1\r\n3.0;2\r\n", ";")
+	if same.Fingerprint != base.Fingerprint {
+		t.Error("Expected equal fingerprints for the same content")
+	}
+	if same.SHA256 == base.SHA256 {
+		t.Error("Expected different SHA-256 for different bytes")
+	}
+
+	// id is untyped, so "01" is a different value than "1"
+	if validate("id,amount\n01,10.5\n2,3\n", ",").Fingerprint == base.Fingerprint {
+		t.Error("Expected a different fingerprint for different content")
+	}
+
+	// The whole input is hashed even when validation stops early
+	failFast, err := NewWithOptions(strings.NewReader("id\n1,2\n3\n"), Options{Delimiter: ",", FailFast: true, Fingerprint: true}).Validate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum = sha256.Sum256([]byte("id\n1,2\n3\n"))
+	if failFast.SHA256 != hex.EncodeToString(sum[:]) {
+		t.Errorf("Expected the SHA-256 of the whole input, got %s", failFast.SHA256)
+	}
+}
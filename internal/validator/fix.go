@@ -0,0 +1,120 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/csvlinter/csvlinter/internal/parser"
+)
+
+// Fix is a machine-applyable correction of a finding: Length bytes of the file at Offset
+// replaced by Text. Offsets count from the start of the file, a sep= line included.
+type Fix struct {
+	Description string `json:"description"` // What the fix does, e.g. "remove the quotes"
+	Offset      int64  `json:"byte_offset"`
+	Length      int    `json:"byte_length"`
+	Text        string `json:"text"`
+}
+
+// fixer locates the fixes of findings in the input, from the raw bytes of its rows. The
+// nil fixer, used when fixes are not requested, makes none.
+type fixer struct {
+	dialect parser.Dialect
+
+	row   *parser.Row // Row whose fields spans locates
+	spans [][2]int
+}
+
+// fields returns where the fields of row are in its raw bytes, or nil when they are
+// unknown.
+func (f *fixer) fields(row *parser.Row) [][2]int {
+	if f == nil || row.Raw == nil {
+		return nil
+	}
+	if f.row != row {
+		f.row, f.spans = row, parser.FieldSpans(row.Raw, f.dialect)
+	}
+	return f.spans
+}
+
+// field returns the fix replacing field i of row with value, quoted when the field was
+// quoted or the value needs quotes.
+func (f *fixer) field(row *parser.Row, i int, value, description string) *Fix {
+	spans := f.fields(row)
+	if i >= len(spans) {
+		return nil
+	}
+	text, ok := f.dialect.FormatField(value, i < len(row.Quoted) && row.Quoted[i])
+	if !ok {
+		return nil
+	}
+	return f.replace(row, spans[i][0], spans[i][1], text, description)
+}
+
+// quoting returns the fix quoting field i of row, or removing its quotes.
+func (f *fixer) quoting(row *parser.Row, i int, quote bool, description string) *Fix {
+	spans := f.fields(row)
+	if i >= len(spans) || i >= len(row.Data) {
+		return nil
+	}
+	text, ok := f.text(row.Data[i], quote)
+	if !ok {
+		return nil
+	}
+	return f.replace(row, spans[i][0], spans[i][1], text, description)
+}
+
+// text returns value as written in a field of the input, quoted or not, or false when it
+// cannot be written so.
+func (f *fixer) text(value string, quote bool) (string, bool) {
+	if quote {
+		return f.dialect.QuoteField(value)
+	}
+	return f.dialect.EscapeField(value)
+}
+
+// fieldCount returns the fix of a row with the wrong number of fields for width columns:
+// empty fields added to a short row, quoted where the dialect needs it, or the delimiter
+// at the end of a row with an empty extra field removed. Rows whose extra fields hold
+// values get none.
+func (f *fixer) fieldCount(row *parser.Row, width int) *Fix {
+	spans := f.fields(row)
+	switch n := len(spans); {
+	case n == 0:
+		return nil
+	case n < width:
+		empty, ok := f.dialect.FormatField("", false)
+		if !ok {
+			return nil
+		}
+		end := spans[n-1][1]
+		field := string(parser.DelimiterRune(f.dialect.Delimiter)) + empty
+		return f.replace(row, end, end, strings.Repeat(field, width-n), fmt.Sprintf("add %d empty field(s)", width-n))
+	case n == width+1 && spans[n-1][0] == spans[n-1][1]:
+		end := spans[n-1][0]
+		return f.replace(row, end-1, end, "", "remove the delimiter at the end of the line")
+	}
+	return nil
+}
+
+// record returns the fix removing row, its line ending included.
+func (f *fixer) record(row *parser.Row, description string) *Fix {
+	spans := f.fields(row)
+	if len(spans) == 0 {
+		return nil
+	}
+	return f.replace(row, spans[0][0], len(row.Raw), "", description)
+}
+
+// replace returns the fix replacing the bytes from start to end of row's raw bytes.
+func (f *fixer) replace(row *parser.Row, start, end int, text, description string) *Fix {
+	if string(row.Raw[start:end]) == text {
+		return nil
+	}
+	return &Fix{
+		Description: description,
+		Offset:      row.Offset + int64(start),
+		Length:      end - start,
+		Text:        text,
+	}
+}
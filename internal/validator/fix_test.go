@@ -0,0 +1,101 @@
+package validator
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/parser"
+	"github.com/csvlinter/csvlinter/internal/schema"
+)
+
+func TestFixes(t *testing.T) {
+	sv, err := schema.NewValidatorFromReader(strings.NewReader(`{
+		"type": "object",
+		"properties": {
+			"id": {"type": "string"},
+			"status": {"enum": ["active", "closed"]},
+			"code": {"type": "string", "pattern": "^[A-Z]+$"}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The offsets of fixes count the sep= line removed from the input
+	preamble := "sep=,\r\n"
+	input := "id,status,code\r\n" +
+		"\"1\",Active,ABC\r\n" +
+		"2,closed, XY \r\n" +
+		"3,active\r\n" +
+		"4,active,Q,\r\n" +
+		"5,\"pending\",\"A,B\"\r\n"
+	results, err := NewWithOptions(strings.NewReader(input), Options{
+		Delimiter: ",",
+		Schemas:   []Schema{{Validator: sv}},
+		Quoting:   parser.QuoteMinimal,
+		ShortRows: "warn",
+		Fixes:     true,
+		SepLine:   "sep=,",
+		Preamble:  int64(len(preamble)),
+	}).Validate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var fixes []Fix
+	for _, f := range append(results.Errors, results.Warnings...) {
+		if f.Fix != nil {
+			fixes = append(fixes, *f.Fix)
+		}
+	}
+	sort.Slice(fixes, func(i, j int) bool { return fixes[i].Offset > fixes[j].Offset })
+	fixed := preamble + input
+	for _, f := range fixes {
+		fixed = fixed[:f.Offset] + f.Text + fixed[f.Offset+int64(f.Length):]
+	}
+	want := preamble + "id,status,code\r\n" +
+		"1,active,ABC\r\n" +
+		"2,closed,XY\r\n" +
+		"3,active,\r\n" +
+		"4,active,Q\r\n" +
+		"5,pending,\"A,B\"\r\n"
+	if fixed != want {
+		t.Errorf("fixed input:\n%q\nwant:\n%q\nfixes: %+v", fixed, want, fixes)
+	}
+
+	// Fixes are only made when asked for
+	results, err = NewWithOptions(strings.NewReader(input), Options{Delimiter: ",", Schemas: []Schema{{Validator: sv}}}).Validate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range results.Errors {
+		if f.Fix != nil {
+			t.Errorf("unexpected fix %+v", f)
+		}
+	}
+}
+
+func TestFixesWhitespace(t *testing.T) {
+	// Empty fields padding a short row are quoted, and read back as fields
+	input := "x y z\n1   2    3\na\n"
+	validate := func(input string) *Results {
+		t.Helper()
+		results, err := NewWithOptions(strings.NewReader(input), Options{Delimiter: parser.Whitespace, Fixes: true}).Validate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return results
+	}
+	results := validate(input)
+	if len(results.Errors) != 1 || results.Errors[0].Fix == nil {
+		t.Fatalf("want one error with a fix, got %+v", results.Errors)
+	}
+	f := results.Errors[0].Fix
+	fixed := input[:f.Offset] + f.Text + input[f.Offset+int64(f.Length):]
+	if want := "x y z\n1   2    3\na \"\" \"\"\n"; fixed != want {
+		t.Errorf("fixed input:\n%q\nwant:\n%q", fixed, want)
+	}
+	if results := validate(fixed); !results.Valid {
+		t.Errorf("want the fixed input valid, got %+v", results.Errors)
+	}
+}
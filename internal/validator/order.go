@@ -0,0 +1,33 @@
+package validator
+
+import (
+	"cmp"
+	"sort"
+)
+
+// SortFindings puts errors and warnings in report order: by line, column and rule, with
+// field and message breaking ties, so reports of the same input are identical between
+// runs whatever order the checks found them in.
+func (r *Results) SortFindings() {
+	for _, list := range [][]Finding{r.Errors, r.Warnings} {
+		sort.SliceStable(list, func(i, j int) bool {
+			return compareFindings(list[i], list[j]) < 0
+		})
+	}
+}
+
+func compareFindings(a, b Finding) int {
+	if c := cmp.Compare(a.Line, b.Line); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.Column, b.Column); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.RuleID, b.RuleID); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.Field, b.Field); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.Message, b.Message)
+}
@@ -0,0 +1,38 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSortFindings(t *testing.T) {
+	r := &Results{
+		Errors: []Finding{
+			{Severity: SeverityError, Location: Location{Line: 3, Column: 1}, RuleID: "SCH002", Message: "b"},
+			{Severity: SeverityError, Location: Location{Line: 1}, RuleID: "DAT001", Message: "group"},
+			{Severity: SeverityError, Location: Location{Line: 3, Column: 1}, RuleID: "SCH001", Message: "z"},
+			{Severity: SeverityError, Location: Location{Line: 3, Column: 1}, RuleID: "SCH002", Message: "a"},
+			{Severity: SeverityError, Location: Location{Line: 2, Column: 2}, RuleID: "SCH001"},
+		},
+		Warnings: []Finding{{Severity: SeverityWarning, Location: Location{Line: 2}}, {Severity: SeverityWarning, Location: Location{Line: 1}}},
+	}
+	r.SortFindings()
+	var got []string
+	for _, e := range r.Errors {
+		got = append(got, e.RuleID+":"+e.Message)
+	}
+	if strings.Join(got, " ") != "DAT001:group SCH001: SCH001:z SCH002:a SCH002:b" {
+		t.Errorf("Unexpected order %v", got)
+	}
+	if r.Warnings[0].Line != 1 {
+		t.Errorf("Expected warnings sorted by line, got %+v", r.Warnings)
+	}
+}
+
+func TestNewBatchSortsFiles(t *testing.T) {
+	b := NewBatch([]*Results{{File: "b.csv", Valid: true}, {File: "a.csv", Valid: true}}, time.Second)
+	if b.Files[0].File != "a.csv" || b.Files[1].File != "b.csv" {
+		t.Errorf("Expected files sorted by name, got %s, %s", b.Files[0].File, b.Files[1].File)
+	}
+}
@@ -0,0 +1,83 @@
+package validator
+
+import (
+	"fmt"
+
+	"github.com/csvlinter/csvlinter/internal/parser"
+	"github.com/csvlinter/csvlinter/internal/rules"
+)
+
+// quotingState checks the quoting of fields against a policy while rows stream past.
+type quotingState struct {
+	policy    string
+	dialect   parser.Dialect
+	headers   []string
+	styleLine []int  // parser.QuoteConsistent: line that set each column's style, 0 before
+	style     []bool // parser.QuoteConsistent: whether each column is quoted
+	fix       *fixer
+}
+
+func newQuotingState(policy string, dialect parser.Dialect, headers []string, fix *fixer) *quotingState {
+	return &quotingState{
+		policy:    policy,
+		dialect:   dialect,
+		headers:   headers,
+		fix:       fix,
+		styleLine: make([]int, len(headers)),
+		style:     make([]bool, len(headers)),
+	}
+}
+
+// row returns a warning for each field of a row, as it is in the input, quoted against
+// the policy.
+func (q *quotingState) row(row *parser.Row) []Finding {
+	var warnings []Finding
+	for i := 0; i < len(row.Data) && i < len(row.Quoted) && i < len(q.headers); i++ {
+		value, quoted, name := row.Data[i], row.Quoted[i], q.headers[i]
+		// Fields that need quotes have no choice; empty ones only count for all and minimal
+		if q.dialect.NeedsQuotes(value) || value == "" && (q.policy == parser.QuoteNonNumeric || q.policy == parser.QuoteConsistent) {
+			continue
+		}
+		var message, suggestion string
+		switch q.policy {
+		case parser.QuoteMinimal:
+			if quoted {
+				message, suggestion = fmt.Sprintf("%s is quoted but needs no quotes", name), "remove the quotes"
+			}
+		case parser.QuoteAll:
+			if !quoted {
+				message, suggestion = fmt.Sprintf("%s is not quoted", name), "quote the value"
+			}
+		case parser.QuoteNonNumeric:
+			numeric := parser.IsNumeric(value)
+			if numeric && quoted {
+				message, suggestion = fmt.Sprintf("number in %s is quoted", name), "remove the quotes"
+			} else if !numeric && !quoted {
+				message, suggestion = fmt.Sprintf("text in %s is not quoted", name), "quote the value"
+			}
+		case parser.QuoteConsistent:
+			switch {
+			case q.styleLine[i] == 0:
+				q.styleLine[i], q.style[i] = row.LineNumber, quoted
+			case quoted && !q.style[i]:
+				message, suggestion = fmt.Sprintf("%s is quoted, but not on line %d", name, q.styleLine[i]), "remove the quotes"
+			case !quoted && q.style[i]:
+				message, suggestion = fmt.Sprintf("%s is not quoted, but is on line %d", name, q.styleLine[i]), "quote the value"
+			}
+		}
+		if message != "" {
+			warnings = append(warnings, Finding{
+				Severity:   SeverityWarning,
+				Location:   Location{Line: row.LineNumber, Column: i + 1},
+				Field:      name,
+				Message:    message,
+				Value:      value,
+				Suggestion: suggestion,
+				Fix:        q.fix.quoting(row, i, !quoted, suggestion),
+				Type:       "structure",
+				RuleID:     rules.Quoting,
+			})
+		}
+	}
+	return warnings
+}
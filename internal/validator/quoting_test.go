@@ -0,0 +1,41 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/parser"
+)
+
+func TestQuoting(t *testing.T) {
+	input := "id,name,note\n1,\"a\",\"x, y\"\n\"2\",b,\n3,\"c\",\"\"\n"
+	warnings := func(policy string) []string {
+		t.Helper()
+		results, err := NewWithOptions(strings.NewReader(input), Options{Delimiter: ",", Quoting: policy}).Validate()
+		if err != nil {
+			t.Fatalf("Validate failed: %v", err)
+		}
+		if !results.Valid {
+			t.Errorf("%s: expected quoting findings to be warnings, got %+v", policy, results.Errors)
+		}
+		var out []string
+		for _, w := range results.Warnings {
+			out = append(out, fmt.Sprintf("%d: %s", w.Line, w.Message))
+		}
+		return out
+	}
+
+	tests := map[string][]string{
+		parser.QuoteMinimal: {"2: name is quoted but needs no quotes", "3: id is quoted but needs no quotes",
+			"4: name is quoted but needs no quotes", "4: note is quoted but needs no quotes"},
+		parser.QuoteAll:        {"2: id is not quoted", "3: name is not quoted", "3: note is not quoted", "4: id is not quoted"},
+		parser.QuoteNonNumeric: {"3: number in id is quoted", "3: text in name is not quoted"},
+		parser.QuoteConsistent: {"3: id is quoted, but not on line 2", "3: name is not quoted, but is on line 2"},
+	}
+	for policy, want := range tests {
+		if got := warnings(policy); strings.Join(got, "\n") != strings.Join(want, "\n") {
+			t.Errorf("%s: expected\n%s\ngot\n%s", policy, strings.Join(want, "\n"), strings.Join(got, "\n"))
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package validator
+
+import (
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Sample is an example row for a failing rule, so a reviewer can see what the bad data
+// looks like without opening the source file.
+type Sample struct {
+	LineNumber int               `json:"line_number"`
+	Values     map[string]string `json:"values"` // Column -> value; fields beyond the header are keyed "column N"
+}
+
+// sampler keeps the first rows that failed each rule or, with a seed, rows picked at
+// random among all of them.
+type sampler struct {
+	max     int
+	columns []string   // Columns kept in samples; every column when empty
+	rng     *rand.Rand // Picks samples when seeded; nil keeps the first rows
+
+	headers []string
+	samples map[string][]Sample
+	failed  map[string]int // Rule -> rows that failed it so far
+	last    map[string]int // Rule -> line of the last row that failed it
+}
+
+func newSampler(max int, columns []string, seed int64) *sampler {
+	s := &sampler{max: max, columns: columns, samples: make(map[string][]Sample), failed: make(map[string]int), last: make(map[string]int)}
+	if seed != 0 {
+		s.rng = rand.New(rand.NewSource(seed))
+	}
+	return s
+}
+
+// row records the row as a sample of each rule its errors failed. Without a seed a rule
+// keeps its first max rows; with one, each failing row is equally likely to be kept
+// (reservoir sampling), so samples come from the whole file and a seed always picks
+// the same rows.
+func (s *sampler) row(lineNumber int, data []string, errs []Finding) {
+	for _, e := range errs {
+		if e.Line != lineNumber || e.RuleID == "" || s.last[e.RuleID] == lineNumber {
+			continue
+		}
+		s.last[e.RuleID] = lineNumber
+		s.failed[e.RuleID]++
+		list := s.samples[e.RuleID]
+		if len(list) < s.max {
+			s.samples[e.RuleID] = append(list, Sample{LineNumber: lineNumber, Values: s.values(data)})
+			continue
+		}
+		if s.rng == nil {
+			continue
+		}
+		if i := s.rng.Intn(s.failed[e.RuleID]); i < s.max {
+			list[i] = Sample{LineNumber: lineNumber, Values: s.values(data)}
+		}
+	}
+}
+
+func (s *sampler) values(data []string) map[string]string {
+	return rowValues(s.headers, s.columns, data)
+}
+
+// rowValues returns the values of data by column, of columns or of every column when
+// columns is empty.
+func rowValues(headers, columns, data []string) map[string]string {
+	values := make(map[string]string)
+	if len(columns) > 0 {
+		for _, c := range columns {
+			for i, h := range headers {
+				if h == c && i < len(data) {
+					values[c] = data[i]
+					break
+				}
+			}
+		}
+		return values
+	}
+	for i, v := range data {
+		if i < len(headers) {
+			values[headers[i]] = v
+		} else {
+			values[extraColumn(i)] = v
+		}
+	}
+	return values
+}
+
+// rowKey returns the key of a row, the value at the one index of the key or the values at
+// its indexes as "(a, b)"; "" when the row is too short to have it.
+func rowKey(indexes []int, data []string) string {
+	values := make([]string, len(indexes))
+	for j, i := range indexes {
+		if i >= len(data) {
+			return ""
+		}
+		values[j] = data[i]
+	}
+	if len(values) == 1 {
+		return values[0]
+	}
+	return "(" + strings.Join(values, ", ") + ")"
+}
+
+// extraColumn names the i-th field of a row longer than the header, e.g. "column 4".
+func extraColumn(i int) string {
+	return "column " + strconv.Itoa(i+1)
+}
+
+// result returns the samples by rule ID in file order, or nil when there are none.
+func (s *sampler) result() map[string][]Sample {
+	if len(s.samples) == 0 {
+		return nil
+	}
+	for _, list := range s.samples {
+		sort.Slice(list, func(i, j int) bool { return list[i].LineNumber < list[j].LineNumber })
+	}
+	return s.samples
+}
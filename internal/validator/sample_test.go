@@ -0,0 +1,138 @@
+package validator
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestSamples(t *testing.T) {
+	input := "id,name\n1,a\n2\n3,b,extra\n4,c\n5\n"
+	validate := func(opts Options) *Results {
+		t.Helper()
+		opts.Delimiter = ","
+		results, err := NewWithOptions(strings.NewReader(input), opts).Validate()
+		if err != nil {
+			t.Fatalf("Validate failed: %v", err)
+		}
+		return results
+	}
+
+	results := validate(Options{SampleRows: 2})
+	want := []Sample{
+		{LineNumber: 3, Values: map[string]string{"id": "2"}},
+		{LineNumber: 4, Values: map[string]string{"id": "3", "name": "b", "column 3": "extra"}},
+	}
+	if len(results.Errors) != 3 || !reflect.DeepEqual(results.Samples["STR001"], want) || len(results.Samples) != 1 {
+		t.Errorf("Expected the first two column count failures as samples, got %+v", results.Samples)
+	}
+
+	results = validate(Options{SampleRows: 1, SampleColumns: []string{"name"}})
+	if got := results.Samples["STR001"]; len(got) != 1 || !reflect.DeepEqual(got[0].Values, map[string]string{}) {
+		t.Errorf("Expected one sample without the missing name column, got %+v", got)
+	}
+
+	if results := validate(Options{}); results.Samples != nil {
+		t.Errorf("Expected no samples unless requested, got %+v", results.Samples)
+	}
+}
+
+func TestIncludeColumns(t *testing.T) {
+	input := "id,name\n1,a\n2\n3,b,extra\n"
+	results, err := NewWithOptions(strings.NewReader(input), Options{Delimiter: ",", IncludeColumns: []string{"id"}, ShortRows: "warn"}).Validate()
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if len(results.Errors) != 1 || !reflect.DeepEqual(results.Errors[0].Columns, map[string]string{"id": "3"}) {
+		t.Errorf("Expected the id of the long row with its error, got %+v", results.Errors)
+	}
+	if len(results.Warnings) != 1 || !reflect.DeepEqual(results.Warnings[0].Columns, map[string]string{"id": "2"}) {
+		t.Errorf("Expected the id of the short row with its warning, got %+v", results.Warnings)
+	}
+
+	results, err = NewWithOptions(strings.NewReader(input), Options{Delimiter: ",", IncludeColumns: []string{"*"}}).Validate()
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if want := map[string]string{"id": "3", "name": "b", "column 3": "extra"}; !reflect.DeepEqual(results.Errors[1].Columns, want) {
+		t.Errorf("Expected the whole row with *, got %+v", results.Errors[1].Columns)
+	}
+
+	results, _ = NewWithOptions(strings.NewReader(input), Options{Delimiter: ","}).Validate()
+	if results.Errors[0].Columns != nil {
+		t.Errorf("Expected no columns unless requested, got %+v", results.Errors[0].Columns)
+	}
+
+	// A column the header lacks is reported once, whatever the rows
+	results, _ = NewWithOptions(strings.NewReader(input), Options{Delimiter: ",", IncludeColumns: []string{"id", "order_id", "order_id"}}).Validate()
+	if w := results.Warnings; len(w) != 1 || w[0].RuleID != "STR014" || w[0].Field != "order_id" || w[0].Line != 1 {
+		t.Errorf("Expected one warning about order_id, got %+v", w)
+	}
+}
+
+func TestKeyColumns(t *testing.T) {
+	input := "id,region,name\n1,eu,a\n2,us\n3,eu,b,extra\n"
+	results, err := NewWithOptions(strings.NewReader(input), Options{Delimiter: ",", KeyColumns: []string{"id"}, ShortRows: "warn"}).Validate()
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if len(results.Errors) != 1 || results.Errors[0].Key != "3" {
+		t.Errorf("Expected the key of the long row with its error, got %+v", results.Errors)
+	}
+	if len(results.Warnings) != 1 || results.Warnings[0].Key != "2" {
+		t.Errorf("Expected the key of the short row with its warning, got %+v", results.Warnings)
+	}
+	if !reflect.DeepEqual(results.KeyColumns, []string{"id"}) {
+		t.Errorf("Expected the key columns in the results, got %v", results.KeyColumns)
+	}
+
+	results, _ = NewWithOptions(strings.NewReader(input), Options{Delimiter: ",", KeyColumns: []string{"id", "region"}}).Validate()
+	if results.Errors[0].Key != "(2, us)" || results.Errors[1].Key != "(3, eu)" {
+		t.Errorf("Expected composite keys, got %+v", results.Errors)
+	}
+
+	results, _ = NewWithOptions(strings.NewReader(input), Options{Delimiter: ",", KeyColumns: []string{"id", "missing"}}).Validate()
+	if results.Errors[0].Key != "" || results.KeyColumns != nil {
+		t.Errorf("Expected no key when a key column is not in the header, got %+v", results)
+	}
+}
+
+func TestTruncateValuesSamples(t *testing.T) {
+	r := &Results{Samples: map[string][]Sample{"SCH002": {{LineNumber: 2, Values: map[string]string{"note": "abcdef"}}}}}
+	r.TruncateValues(3)
+	if got := r.Samples["SCH002"][0].Values["note"]; got != "abc…" {
+		t.Errorf("Expected the sample value truncated, got %q", got)
+	}
+}
+
+func TestSamplesSeeded(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("id\n")
+	for i := 0; i < 200; i++ {
+		sb.WriteString("1,2\n")
+	}
+	lines := func(seed int64) []int {
+		t.Helper()
+		results, err := NewWithOptions(strings.NewReader(sb.String()), Options{Delimiter: ",", SampleRows: 3, SampleSeed: seed}).Validate()
+		if err != nil {
+			t.Fatalf("Validate failed: %v", err)
+		}
+		var out []int
+		for _, s := range results.Samples["STR001"] {
+			out = append(out, s.LineNumber)
+		}
+		return out
+	}
+
+	first, seeded := lines(0), lines(42)
+	if !reflect.DeepEqual(first, []int{2, 3, 4}) {
+		t.Errorf("Expected the first rows without a seed, got %v", first)
+	}
+	if len(seeded) != 3 || reflect.DeepEqual(seeded, first) || !sort.IntsAreSorted(seeded) {
+		t.Errorf("Expected three rows in file order picked from the whole file, got %v", seeded)
+	}
+	if again := lines(42); !reflect.DeepEqual(again, seeded) {
+		t.Errorf("Expected the same seed to pick the same rows, got %v and %v", seeded, again)
+	}
+}
@@ -0,0 +1,91 @@
+package validator
+
+// maxSnippets bounds the failing rows kept with their context, so files with millions of
+// errors do not hold a copy of themselves in memory.
+const maxSnippets = 1000
+
+// RowContext holds the rows around failing rows, so reports can show errors in place.
+// It is not part of JSON reports.
+type RowContext struct {
+	Header    []string
+	Delimiter rune
+	Rows      map[int][]string // Line -> fields of each row kept as context
+	Snippets  map[int][]int    // Failing line -> lines of the rows around it, itself included, in file order
+}
+
+// Column returns the name of the i-th field: its header, or "column N" beyond the header.
+func (c *RowContext) Column(i int) string {
+	if i < len(c.Header) {
+		return c.Header[i]
+	}
+	return extraColumn(i)
+}
+
+// snipper keeps the last n rows and collects them, with the next n rows, around each
+// failing row.
+type snipper struct {
+	n       int
+	recent  []int // Lines of the last n rows, oldest first
+	fields  map[int][]string
+	pending []pendingSnippet
+	context *RowContext
+}
+
+// pendingSnippet is a snippet still waiting for the rows after its failing row.
+type pendingSnippet struct {
+	line  int
+	after int // Rows still to add
+}
+
+func newSnipper(n int, headers []string, delimiter rune) *snipper {
+	return &snipper{
+		n:       n,
+		fields:  make(map[int][]string),
+		context: &RowContext{Header: headers, Delimiter: delimiter, Rows: make(map[int][]string), Snippets: make(map[int][]int)},
+	}
+}
+
+// row records a data row; failed marks it as a row with errors.
+func (s *snipper) row(lineNumber int, data []string, failed bool) {
+	s.fields[lineNumber] = append([]string(nil), data...)
+	open := s.pending[:0]
+	for _, p := range s.pending {
+		s.keep(p.line, lineNumber)
+		if p.after--; p.after > 0 {
+			open = append(open, p)
+		}
+	}
+	s.pending = open
+	if failed && len(s.context.Snippets) < maxSnippets {
+		if _, seen := s.context.Snippets[lineNumber]; !seen {
+			for _, line := range s.recent {
+				s.keep(lineNumber, line)
+			}
+			s.keep(lineNumber, lineNumber)
+			s.pending = append(s.pending, pendingSnippet{line: lineNumber, after: s.n})
+		}
+	}
+
+	// Only the last n rows can still be needed as rows before a failing row
+	if len(s.recent) == s.n {
+		delete(s.fields, s.recent[0])
+		s.recent = append(s.recent[:0], s.recent[1:]...)
+	}
+	s.recent = append(s.recent, lineNumber)
+}
+
+// keep adds the row at line to the snippet of failing.
+func (s *snipper) keep(failing, line int) {
+	s.context.Snippets[failing] = append(s.context.Snippets[failing], line)
+	if _, ok := s.context.Rows[line]; !ok {
+		s.context.Rows[line] = s.fields[line]
+	}
+}
+
+// result returns the collected context, or nil when no row failed.
+func (s *snipper) result() *RowContext {
+	if len(s.context.Snippets) == 0 {
+		return nil
+	}
+	return s.context
+}
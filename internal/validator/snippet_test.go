@@ -0,0 +1,40 @@
+package validator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestContextRows(t *testing.T) {
+	input := "id;name\n1;a\n2;b\n3\n4;d\n5\n6;f\n7;g\n8;h\n"
+	results, err := NewWithOptions(strings.NewReader(input), Options{Delimiter: ";", ContextRows: 1}).Validate()
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	c := results.Context
+	if c == nil || c.Delimiter != ';' || !reflect.DeepEqual(c.Header, []string{"id", "name"}) {
+		t.Fatalf("Expected context with the header and delimiter, got %+v", c)
+	}
+	want := map[int][]int{4: {3, 4, 5}, 6: {5, 6, 7}}
+	if !reflect.DeepEqual(c.Snippets, want) {
+		t.Errorf("Expected one row around each failing row, got %v", c.Snippets)
+	}
+	// Rows shared by two snippets are kept once; rows outside every snippet not at all
+	if len(c.Rows) != 5 || !reflect.DeepEqual(c.Rows[4], []string{"3"}) || c.Rows[2] != nil {
+		t.Errorf("Unexpected context rows %v", c.Rows)
+	}
+
+	results, _ = NewWithOptions(strings.NewReader("id\n1\n"), Options{Delimiter: ",", ContextRows: 2}).Validate()
+	if results.Context != nil {
+		t.Errorf("Expected no context for a valid file, got %+v", results.Context)
+	}
+}
+
+func TestTruncateValuesContext(t *testing.T) {
+	r := &Results{Context: &RowContext{Rows: map[int][]string{2: {"abcdef", "x"}}}}
+	r.TruncateValues(3)
+	if got := r.Context.Rows[2]; !reflect.DeepEqual(got, []string{"abc…", "x"}) {
+		t.Errorf("Expected the context value truncated, got %q", got)
+	}
+}
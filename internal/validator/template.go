@@ -0,0 +1,66 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/csvlinter/csvlinter/internal/rules"
+)
+
+// Templates replace the messages of findings by rule ID, so organizations can phrase
+// findings their way or link to their runbooks.
+type Templates map[string]*template.Template
+
+// ParseTemplates compiles text/template message templates keyed by rule ID. Templates
+// see the finding, with the built-in message in .Message and .Field, .Value, .Expected,
+// .Actual, .Line and .RuleID among its fields.
+func ParseTemplates(texts map[string]string) (Templates, error) {
+	ids := make([]string, 0, len(texts))
+	for id := range texts {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	out := make(Templates, len(texts))
+	for _, id := range ids {
+		if _, ok := rules.Lookup(id); !ok {
+			return nil, fmt.Errorf("message template for unknown rule '%s'", id)
+		}
+		t, err := template.New(id).Option("missingkey=error").Parse(texts[id])
+		if err != nil {
+			return nil, fmt.Errorf("message template for %s: %w", id, err)
+		}
+		// Fields that findings do not have only fail on execution
+		if err := t.Execute(&strings.Builder{}, Finding{}); err != nil {
+			return nil, fmt.Errorf("message template for %s: %w", id, err)
+		}
+		out[id] = t
+	}
+	return out, nil
+}
+
+// Apply rewrites the message of f when its rule has a template. A template failing on
+// f, as a call on a missing value may, leaves the message as it is.
+func (t Templates) Apply(f *Finding) {
+	tmpl, ok := t[f.RuleID]
+	if !ok {
+		return
+	}
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, *f); err == nil {
+		f.Message = sb.String()
+	}
+}
+
+// Results applies the templates to the errors and warnings of results.
+func (t Templates) Results(results *Results) {
+	if len(t) == 0 {
+		return
+	}
+	for _, list := range [][]Finding{results.Errors, results.Warnings} {
+		for i := range list {
+			t.Apply(&list[i])
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/rules"
+)
+
+func TestTemplates(t *testing.T) {
+	templates, err := ParseTemplates(map[string]string{
+		rules.SchemaType:  "{{.Field}} must be {{.Expected}}, got {{printf \"%q\" .Value}} on line {{.Line}} (see https://runbooks.example.com/{{.RuleID}})",
+		rules.ColumnCount: "{{.Message}}; ask the data team",
+	})
+	if err != nil {
+		t.Fatalf("ParseTemplates failed: %v", err)
+	}
+	results := &Results{
+		Errors: []Finding{
+			{Severity: SeverityError, RuleID: rules.SchemaType, Location: Location{Line: 3}, Field: "age", Value: "x", Expected: "integer", Actual: "string", Message: "expected integer, but got string"},
+			{Severity: SeverityError, RuleID: rules.ColumnCount, Location: Location{Line: 4}, Message: "column count mismatch: expected 2, got 1"},
+			{Severity: SeverityError, RuleID: rules.SchemaPattern, Location: Location{Line: 5}, Message: "does not match pattern"},
+		},
+		Warnings: []Finding{{Severity: SeverityWarning, RuleID: rules.ColumnCount, Location: Location{Line: 6}, Message: "row has 3 fields"}},
+	}
+	templates.Results(results)
+	want := []string{
+		`age must be integer, got "x" on line 3 (see https://runbooks.example.com/SCH002)`,
+		"column count mismatch: expected 2, got 1; ask the data team",
+		"does not match pattern",
+	}
+	for i, w := range want {
+		if got := results.Errors[i].Message; got != w {
+			t.Errorf("error %d: got %q, want %q", i, got, w)
+		}
+	}
+	if got := results.Warnings[0].Message; got != "row has 3 fields; ask the data team" {
+		t.Errorf("warning: got %q", got)
+	}
+
+	for _, tc := range []struct{ id, text, want string }{
+		{"NOPE001", "{{.Message}}", "unknown rule 'NOPE001'"},
+		{rules.SchemaPattern, "{{.Message", "message template for SCH004"},
+		{rules.SchemaPattern, "{{.Row}}", "can't evaluate field Row"},
+	} {
+		if _, err := ParseTemplates(map[string]string{tc.id: tc.text}); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("ParseTemplates(%s: %q): expected an error mentioning %q, got %v", tc.id, tc.text, tc.want, err)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package validator
+
+import (
+	"io"
+	"math"
+	"time"
+)
+
+// Timings is where validation spent its time, in milliseconds, so a slow run can be told
+// to be I/O-bound or schema-bound. Reading, UTF-8 checks and parsing overlap in the
+// parser and are measured apart.
+type Timings struct {
+	Read      float64 `json:"read_ms"`             // Reading the input
+	UTF8      float64 `json:"utf8_ms"`             // Checking that records are UTF-8
+	Parse     float64 `json:"parse_ms"`            // Splitting the input into records and fields
+	Structure float64 `json:"structure_ms"`        // Field counts, envelope records and quoting
+	Schema    float64 `json:"schema_ms"`           // Validating rows against schemas
+	Checks    float64 `json:"checks_ms"`           // Checks across rows
+	Report    float64 `json:"report_ms,omitempty"` // Writing reports; measured after them, so only set in returned results
+}
+
+// Add adds the phases of o to t.
+func (t *Timings) Add(o *Timings) {
+	t.Read += o.Read
+	t.UTF8 += o.UTF8
+	t.Parse += o.Parse
+	t.Structure += o.Structure
+	t.Schema += o.Schema
+	t.Checks += o.Checks
+	t.Report += o.Report
+}
+
+// Milliseconds converts d for Timings, keeping microseconds.
+func Milliseconds(d time.Duration) float64 {
+	return math.Round(float64(d)/float64(time.Microsecond)) / 1000
+}
+
+// Validation phases measured by a timer.
+const (
+	phaseRead = iota
+	phaseParse
+	phaseStructure
+	phaseSchema
+	phaseChecks
+	phaseCount
+)
+
+// timer sums the time spent in each phase. A nil timer measures nothing, so validation
+// only reads the clock when timings are requested.
+type timer struct {
+	phases [phaseCount]time.Duration
+}
+
+// now returns the current time, or the zero time for a nil timer.
+func (t *timer) now() time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// add counts the time since start to phase.
+func (t *timer) add(phase int, start time.Time) {
+	if t != nil {
+		t.phases[phase] += time.Since(start)
+	}
+}
+
+// reader times the reads of r.
+func (t *timer) reader(r io.Reader) io.Reader {
+	return &timedReader{r: r, t: t}
+}
+
+// timings converts the phases. parse covered reading and the UTF-8 checks of the parser,
+// which are taken out.
+func (t *timer) timings(utf8 time.Duration) *Timings {
+	parse := t.phases[phaseParse] - t.phases[phaseRead] - utf8
+	return &Timings{
+		Read:      Milliseconds(t.phases[phaseRead]),
+		UTF8:      Milliseconds(utf8),
+		Parse:     Milliseconds(max(parse, 0)),
+		Structure: Milliseconds(t.phases[phaseStructure]),
+		Schema:    Milliseconds(t.phases[phaseSchema]),
+		Checks:    Milliseconds(t.phases[phaseChecks]),
+	}
+}
+
+type timedReader struct {
+	r io.Reader
+	t *timer
+}
+
+func (r *timedReader) Read(p []byte) (int, error) {
+	start := time.Now()
+	n, err := r.r.Read(p)
+	r.t.add(phaseRead, start)
+	return n, err
+}
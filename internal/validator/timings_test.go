@@ -0,0 +1,42 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/csvlinter/csvlinter/internal/schema"
+)
+
+func TestTimings(t *testing.T) {
+	sv, err := schema.NewValidatorFromReader(strings.NewReader(`{"type":"object","properties":{"id":{"type":"integer"}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := "id\n" + strings.Repeat("1\n", 1000)
+
+	results, err := NewWithOptions(strings.NewReader(input), Options{Delimiter: ",", Schemas: []Schema{{Validator: sv}}}).Validate()
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if results.Timings != nil {
+		t.Errorf("expected no timings unless requested, got %+v", results.Timings)
+	}
+
+	results, err = NewWithOptions(strings.NewReader(input), Options{Delimiter: ",", Schemas: []Schema{{Validator: sv}}, Timings: true}).Validate()
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	tm := results.Timings
+	if tm == nil || tm.Schema <= 0 || tm.Read < 0 || tm.Parse < 0 || tm.Report != 0 {
+		t.Fatalf("expected schema time and no report time, got %+v", tm)
+	}
+
+	batch := NewBatch([]*Results{results, {File: "cached.csv"}, results}, 0)
+	if batch.Timings == nil || batch.Timings.Schema != 2*tm.Schema {
+		t.Errorf("expected the batch to sum file timings, got %+v", batch.Timings)
+	}
+	if got := Milliseconds(1500 * time.Microsecond); got != 1.5 {
+		t.Errorf("expected 1.5ms, got %v", got)
+	}
+}
@@ -0,0 +1,47 @@
+package validator
+
+import (
+	"fmt"
+
+	"github.com/csvlinter/csvlinter/internal/rules"
+	"github.com/csvlinter/csvlinter/internal/schema"
+)
+
+// unexpectedColumns reports the header columns a schema rejects with additionalProperties
+// false once, on the header line, instead of on every row, and has rows validated
+// against the schema without them.
+type unexpectedColumns struct {
+	headers []string
+	columns map[string]int // 1-based column of each header
+	line    int            // Line of the header
+	checked map[*schema.Validator]*schema.Validator
+}
+
+func newUnexpectedColumns(headers []string, columns map[string]int, line int) *unexpectedColumns {
+	return &unexpectedColumns{headers: headers, columns: columns, line: line, checked: make(map[*schema.Validator]*schema.Validator)}
+}
+
+// schema returns s without the columns it rejects, and the errors for those columns the
+// first time it sees s.
+func (u *unexpectedColumns) schema(s Schema) (Schema, []Finding) {
+	if without, ok := u.checked[s.Validator]; ok {
+		return Schema{Validator: without, Label: s.Label}, nil
+	}
+	unexpected := s.Validator.UnexpectedColumns(u.headers)
+	without := s.Validator.WithoutColumns(unexpected)
+	u.checked[s.Validator] = without
+	var errs []Finding
+	for _, column := range unexpected {
+		errs = append(errs, Finding{
+			Severity: SeverityError,
+			Location: Location{Line: u.line, Column: u.columns[column]},
+			Field:    column,
+			Message:  fmt.Sprintf("column '%s' is not allowed by the schema (additionalProperties is false); reported once for the header rather than on every row", column),
+			Value:    column,
+			Type:     "schema",
+			RuleID:   rules.SchemaAdditional,
+			Schema:   s.Label,
+		})
+	}
+	return Schema{Validator: without, Label: s.Label}, errs
+}
@@ -1,64 +1,537 @@
 package validator
 
 import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"slices"
+	"sort"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/csvlinter/csvlinter/internal/parser"
+	"github.com/csvlinter/csvlinter/internal/rules"
 	"github.com/csvlinter/csvlinter/internal/schema"
 )
 
-// Error represents a validation error
-type Error struct {
-	LineNumber int    `json:"line_number"`
-	Field      string `json:"field,omitempty"`
-	Message    string `json:"message"`
-	Value      string `json:"value,omitempty"`
-	Type       string `json:"type"`
+// Severity tells how a finding affects a file: errors make it invalid, warnings are
+// reported but leave it valid.
+type Severity string
+
+// Finding severities.
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Location is where in the input a finding is.
+type Location struct {
+	Line       int   // Line of the record, as numbered in findings; 0 for findings about the whole file
+	Column     int   // 1-based column of Field (or of a parse error), 0 when not tied to one
+	ByteOffset int64 // Offset in the input of the record's first byte (or of the invalid bytes of an encoding error), 0 when unknown
+}
+
+// Finding is a validation error or warning.
+type Finding struct {
+	Severity Severity
+	RuleID   string // Rule ID from internal/rules, e.g. "SCH001"
+	Location
+	Field          string
+	Message        string
+	Value          string
+	ValueTruncated bool   // Value, and its copy in Message, was cut to the report limit
+	Expected       string // What the rule asks for, e.g. "integer" or "5 fields", when it can be told apart from the message
+	Actual         string // What the input has instead, e.g. "string" or "4 fields"
+	Suggestion     string // How to fix the input, e.g. "expected format YYYY-MM-DD", when the check can tell
+	Fix            *Fix   // Machine-applyable correction, when requested and the check knows it
+	Occurrences    int    // Identical findings rolled into this one by Dedupe; 0 when not rolled up
+	LastLine       int    // Line of the last of them; Line is the first
+	Type           string
+	Schema         string            // Schema that produced the finding, when several are used
+	Columns        map[string]string // Column -> value in the finding's row, of the columns asked for with IncludeColumns
+	Key            string            // Value of the row's key (Options.KeyColumns); "(a, b)" for a key of several columns
+}
+
+// findingJSON is the JSON form of a finding, flat as reports have always had it.
+type findingJSON struct {
+	Severity       Severity          `json:"severity,omitempty"`
+	LineNumber     int               `json:"line_number"`
+	Column         int               `json:"column,omitempty"`
+	ByteOffset     int64             `json:"byte_offset,omitempty"`
+	Field          string            `json:"field,omitempty"`
+	Message        string            `json:"message"`
+	Value          string            `json:"value,omitempty"`
+	ValueTruncated bool              `json:"value_truncated,omitempty"`
+	Expected       string            `json:"expected,omitempty"`
+	Actual         string            `json:"actual,omitempty"`
+	Suggestion     string            `json:"suggestion,omitempty"`
+	Fix            *Fix              `json:"fix,omitempty"`
+	Occurrences    int               `json:"occurrences,omitempty"`
+	LastLine       int               `json:"last_line,omitempty"`
+	Type           string            `json:"type"`
+	Rule           string            `json:"rule,omitempty"`
+	Schema         string            `json:"schema,omitempty"`
+	Columns        map[string]string `json:"columns,omitempty"`
+	Key            string            `json:"key,omitempty"`
 }
 
-// Warning represents a validation warning
-type Warning struct {
-	LineNumber int    `json:"line_number"`
-	Field      string `json:"field,omitempty"`
-	Message    string `json:"message"`
-	Value      string `json:"value,omitempty"`
-	Type       string `json:"type"`
+// MarshalJSON writes the finding with its location and rule as top-level fields.
+func (f Finding) MarshalJSON() ([]byte, error) {
+	return json.Marshal(findingJSON{
+		Severity:       f.Severity,
+		LineNumber:     f.Line,
+		Column:         f.Column,
+		ByteOffset:     f.ByteOffset,
+		Field:          f.Field,
+		Message:        f.Message,
+		Value:          f.Value,
+		ValueTruncated: f.ValueTruncated,
+		Expected:       f.Expected,
+		Actual:         f.Actual,
+		Suggestion:     f.Suggestion,
+		Fix:            f.Fix,
+		Occurrences:    f.Occurrences,
+		LastLine:       f.LastLine,
+		Type:           f.Type,
+		Rule:           f.RuleID,
+		Schema:         f.Schema,
+		Columns:        f.Columns,
+		Key:            f.Key,
+	})
+}
+
+// UnmarshalJSON reads a finding written by MarshalJSON.
+func (f *Finding) UnmarshalJSON(data []byte) error {
+	var j findingJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	*f = Finding{
+		Severity:       j.Severity,
+		RuleID:         j.Rule,
+		Location:       Location{Line: j.LineNumber, Column: j.Column, ByteOffset: j.ByteOffset},
+		Field:          j.Field,
+		Message:        j.Message,
+		Value:          j.Value,
+		ValueTruncated: j.ValueTruncated,
+		Expected:       j.Expected,
+		Actual:         j.Actual,
+		Suggestion:     j.Suggestion,
+		Fix:            j.Fix,
+		Occurrences:    j.Occurrences,
+		LastLine:       j.LastLine,
+		Type:           j.Type,
+		Schema:         j.Schema,
+		Columns:        j.Columns,
+		Key:            j.Key,
+	}
+	return nil
 }
 
 // Results contains the validation results
 type Results struct {
-	File            string    `json:"file"`
-	TotalRows       int       `json:"total_rows"`
-	Errors          []Error   `json:"errors"`
-	Warnings        []Warning `json:"warnings"`
-	Duration        string    `json:"duration"`
-	Valid           bool      `json:"valid"`
-	SchemaUsed      bool      `json:"schema_used"`
-	SchemaInferred  bool      `json:"schema_inferred,omitempty"`
+	File           string    `json:"file"`
+	TotalRows      int       `json:"total_rows"`
+	Errors         []Finding `json:"errors"`
+	Warnings       []Finding `json:"warnings"`
+	Duration       string    `json:"duration"`
+	Valid          bool      `json:"valid"`
+	SchemaUsed     bool      `json:"schema_used"`
+	SchemaInferred bool      `json:"schema_inferred,omitempty"`
+	Cached         bool      `json:"cached,omitempty"`      // Served from the result cache; Duration is the lookup time
+	SHA256         string    `json:"sha256,omitempty"`      // Hex SHA-256 of the input bytes, when requested
+	Fingerprint    string    `json:"fingerprint,omitempty"` // Hex hash of the rows as read under the schema, when requested
+
+	KeyColumns []string `json:"key_columns,omitempty"` // Columns of Finding.Key, when the rows have a key
+
+	Samples   map[string][]Sample `json:"samples,omitempty"`   // Rule ID -> first rows that failed it, when requested
+	Partial   bool                `json:"partial,omitempty"`   // Validation stopped before the end of the input: the time budget ran out or it was interrupted
+	Coverage  *Coverage           `json:"coverage,omitempty"`  // Set when the time budget ran out before the end of the input
+	Contract  *Contract           `json:"contract,omitempty"`  // Set when validating against a producer and a consumer schema
+	Breakdown *Breakdown          `json:"breakdown,omitempty"` // Error counts per column and rule, when requested
+	Timings   *Timings            `json:"timings,omitempty"`   // Time spent per phase, when requested
+	Run       *Run                `json:"run,omitempty"`       // Seed and options the file was validated with
+	Context   *RowContext         `json:"-"`                   // Rows around failing rows, when requested
+}
+
+// Run records how a file was validated, so the run can be repeated exactly: the seed of
+// random choices and the options in effect once the configuration file and flags were
+// merged.
+type Run struct {
+	Seed    int64           `json:"seed,omitempty"`
+	Options json.RawMessage `json:"options"`
+}
+
+// Contract tells which side of a producer/consumer schema pair rejects a file. Findings
+// of each side carry its label ("producer" or "consumer") as their Schema.
+type Contract struct {
+	ProducerSchema string `json:"producer_schema"`
+	ConsumerSchema string `json:"consumer_schema"`
+	ProducerErrors int    `json:"producer_errors"`
+	ConsumerErrors int    `json:"consumer_errors"`
+	RejectedBy     string `json:"rejected_by,omitempty"` // "producer", "consumer" or "both"; empty when both accept the rows
+}
+
+// TruncateValues shortens error and warning values longer than max characters to max
+// characters followed by "…", replacing copies of the value in messages as well, and
+// marks them ValueTruncated. Column, sample and context values are shortened the same way.
+// max <= 0 leaves values unchanged.
+func (r *Results) TruncateValues(max int) {
+	if max <= 0 {
+		return
+	}
+	for _, list := range [][]Finding{r.Errors, r.Warnings} {
+		for i := range list {
+			f := &list[i]
+			f.Message, f.Value, f.ValueTruncated = truncateValue(f.Message, f.Value, max)
+			for column, value := range f.Columns {
+				_, f.Columns[column], _ = truncateValue("", value, max)
+			}
+			_, f.Key, _ = truncateValue("", f.Key, max)
+		}
+	}
+	for _, list := range r.Samples {
+		for _, sample := range list {
+			for column, value := range sample.Values {
+				_, sample.Values[column], _ = truncateValue("", value, max)
+			}
+		}
+	}
+	if r.Context != nil {
+		for _, fields := range r.Context.Rows {
+			for i, value := range fields {
+				_, fields[i], _ = truncateValue("", value, max)
+			}
+		}
+	}
+}
+
+func truncateValue(message, value string, max int) (string, string, bool) {
+	if utf8.RuneCountInString(value) <= max {
+		return message, value, false
+	}
+	short := string([]rune(value)[:max]) + "…"
+	return strings.ReplaceAll(message, value, short), short, true
+}
+
+// Batch aggregates the results of validating several files in one run.
+type Batch struct {
+	Files         []*Results `json:"files"`
+	TotalFiles    int        `json:"total_files"`
+	InvalidFiles  int        `json:"invalid_files"`
+	TotalRows     int        `json:"total_rows"`
+	TotalErrors   int        `json:"total_errors"`
+	TotalWarnings int        `json:"total_warnings"`
+	Duration      string     `json:"duration"`
+	Valid         bool       `json:"valid"`
+	Partial       bool       `json:"partial,omitempty"` // Some files were not validated to the end, or not at all
+	Timings       *Timings   `json:"timings,omitempty"` // Sum of the files' timings, when measured
+}
+
+// NewBatch sorts files by name and computes the totals over them. duration is the wall time of the whole run.
+func NewBatch(files []*Results, duration time.Duration) *Batch {
+	// Reports list files by name whatever order they were validated in
+	sort.SliceStable(files, func(i, j int) bool { return files[i].File < files[j].File })
+	b := &Batch{
+		Files:      files,
+		TotalFiles: len(files),
+		Duration:   duration.String(),
+		Valid:      true,
+	}
+	for _, r := range files {
+		b.TotalRows += r.TotalRows
+		b.TotalErrors += r.ErrorCount()
+		b.TotalWarnings += r.WarningCount()
+		if !r.Valid {
+			b.InvalidFiles++
+			b.Valid = false
+		}
+		if r.Partial {
+			b.Partial = true
+		}
+		if r.Timings != nil {
+			if b.Timings == nil {
+				b.Timings = &Timings{}
+			}
+			b.Timings.Add(r.Timings)
+		}
+	}
+	return b
 }
 
 // Validator represents the main validation engine
 type Validator struct {
-	input            io.Reader
-	name             string
-	delimiter        string
-	schemaValidator  *schema.Validator
-	failFast         bool
-	schemaInferred   bool
+	input          io.Reader
+	name           string
+	delimiter      string
+	quote          string
+	escape         string
+	schemas        []Schema
+	discriminator  *Discriminator
+	checks         []Check
+	transform      Transform
+	envelope       *Envelope
+	fingerprint    bool
+	ctx            context.Context
+	onError        func(Finding) error
+	failFast       bool
+	quotedEmpty    bool
+	sepLine        string
+	sepFinding     string
+	shortRows      string
+	longRows       string
+	requireData    bool
+	chunked        bool
+	quoting        string
+	fixes          bool
+	preamble       int64
+	timings        bool
+	maxEncoding    int
+	schemaInferred bool
+	sampleRows     int
+	sampleColumns  []string
+	sampleSeed     int64
+	includeColumns []string
+	keyColumns     []string
+	contextRows    int
+	timeBudget     time.Duration
+	interrupt      <-chan struct{}
+	size           int64
+}
+
+// Schema is a schema rows are validated against. When several schemas are used, Label
+// (e.g. the schema's path) is copied to the Schema field of the errors it produces.
+type Schema struct {
+	Validator *schema.Validator
+	Label     string
+}
+
+// Discriminator picks an additional schema for each row by the value in Column, for
+// files that mix record types. Rows whose value has no entry in Schemas are only checked
+// against the schemas every row gets.
+type Discriminator struct {
+	Column  string
+	Schemas map[string]Schema
+}
+
+// Check is a validation across rows, such as a grouping or ordering assertion. A check
+// sees the header and the line it is on, where findings about columns are reported,
+// then every row with the expected number of fields in file order, and is finished once
+// the input is exhausted. Checks hold state, so each one validates a single input.
+// Checks holding resources, such as temporary files, implement io.Closer; they are
+// closed when validation ends, whether or not they were finished.
+type Check interface {
+	Start(headerLine int, headers []string) []Finding
+	Row(lineNumber int, fields []string) []Finding
+	Finish() []Finding
+}
+
+// Advisory marks a Check whose findings are warnings: they are reported but leave the
+// file valid.
+type Advisory interface {
+	Check
+	Advisory()
+}
+
+// Transform rewrites the fields of each row before it is validated, e.g. to trim values.
+// It sees the header first and then every data row, whatever its field count.
+type Transform interface {
+	Start(headers []string)
+	Row(fields []string)
+}
+
+// Options configures a Validator.
+type Options struct {
+	Name           string              // Reported file name
+	Delimiter      string              // Field delimiter
+	Quote          string              // Quote character, e.g. "'"; "none" for fields that are never quoted, '"' when empty
+	Escape         string              // Character escaping delimiters, quotes and line breaks in fields, e.g. "\\"; none when empty
+	Schemas        []Schema            // Every row is checked against each schema, in order
+	Discriminator  *Discriminator      // Optional per-row schema, checked after Schemas
+	Checks         []Check             // Checks across rows, run after schema validation
+	Transform      Transform           // Optional rewrite of each row's fields before validation
+	Envelope       *Envelope           // Optional header and trailer records around the data
+	Fingerprint    bool                // Fill in Results.SHA256 and Results.Fingerprint
+	SampleRows     int                 // Keep up to this many example rows per failing rule in Results.Samples (0 = none)
+	SampleColumns  []string            // Columns kept in samples; every column when empty
+	SampleSeed     int64               // Pick samples at random among all failing rows with this seed (0 = the first rows)
+	IncludeColumns []string            // Columns whose values are copied to Finding.Columns of each finding about a row; "*" for every column
+	KeyColumns     []string            // Columns of the rows' key, such as the primary key, whose value goes in Finding.Key of each finding about a row
+	ContextRows    int                 // Keep this many rows before and after each failing row in Results.Context (0 = none)
+	TimeBudget     time.Duration       // Stop reading after this long and report Results.Coverage (0 = no limit)
+	Size           int64               // Input size in bytes, if known, for estimating coverage
+	Interrupt      <-chan struct{}     // Optional: once closed, stop reading and report the rows read so far with Results.Partial
+	Context        context.Context     // Optional: validation stops with the context's error once it is done
+	OnError        func(Finding) error // Optional: called with each error as it is found; an error return stops validation
+	FailFast       bool                // Stop after the first row with errors
+	QuotedEmpty    bool                // Pass quoted empty fields ("") to schemas as empty strings, whatever their empty mode
+	SepLine        string              // Excel "sep=" line removed from the input before the header, which counts as line 1; "" for none
+	SepFinding     string              // Report SepLine as a "warning" or an "error"; "" for neither
+	ShortRows      string              // Rows with fewer fields than the header: "error" ("", the default), "warn" or "pad" with empty values
+	LongRows       string              // Rows with more fields than the header: "error" ("", the default), "warn" or "ignore" the extra values
+	RequireData    bool                // Report a file with a header but no data rows as an error rather than a warning
+	Chunked        bool                // The input concatenates chunks that each start with the header: repeated headers are skipped, not reported
+	Quoting        string              // Report fields quoted against this policy (parser.QuoteMinimal, ...) as warnings; "" = not checked
+	Fixes          bool                // Attach a Fix to findings whose correction is known: whitespace, case, quoting and field counts
+	Preamble       int64               // Bytes of the file before the input, such as a sep= line removed from it; offsets count them
+	Timings        bool                // Measure where validation spends its time in Results.Timings
+	EncodingErrors int                 // Report up to this many rows with invalid UTF-8, skipping them, before stopping (0 = 1)
+	SchemaInferred bool                // The (single) schema was inferred from the data
 }
 
 // New creates a new validator. schemaInferred should be true when the schema was inferred from data rather than loaded from file.
 func New(input io.Reader, name string, delimiter string, schemaValidator *schema.Validator, failFast bool, schemaInferred bool) *Validator {
+	opts := Options{
+		Name:           name,
+		Delimiter:      delimiter,
+		FailFast:       failFast,
+		SchemaInferred: schemaInferred,
+	}
+	if schemaValidator != nil {
+		opts.Schemas = []Schema{{Validator: schemaValidator}}
+	}
+	return NewWithOptions(input, opts)
+}
+
+// NewWithOptions creates a validator from opts.
+func NewWithOptions(input io.Reader, opts Options) *Validator {
 	return &Validator{
-		input:           input,
-		name:            name,
-		delimiter:       delimiter,
-		schemaValidator: schemaValidator,
-		failFast:        failFast,
-		schemaInferred:  schemaInferred,
+		input:          input,
+		name:           opts.Name,
+		delimiter:      opts.Delimiter,
+		quote:          opts.Quote,
+		escape:         opts.Escape,
+		schemas:        opts.Schemas,
+		discriminator:  opts.Discriminator,
+		checks:         opts.Checks,
+		transform:      opts.Transform,
+		envelope:       opts.Envelope,
+		fingerprint:    opts.Fingerprint,
+		ctx:            opts.Context,
+		onError:        opts.OnError,
+		failFast:       opts.FailFast,
+		quotedEmpty:    opts.QuotedEmpty,
+		sepLine:        opts.SepLine,
+		sepFinding:     opts.SepFinding,
+		shortRows:      opts.ShortRows,
+		longRows:       opts.LongRows,
+		requireData:    opts.RequireData,
+		chunked:        opts.Chunked,
+		quoting:        opts.Quoting,
+		fixes:          opts.Fixes,
+		preamble:       opts.Preamble,
+		timings:        opts.Timings,
+		maxEncoding:    max(opts.EncodingErrors, 1),
+		schemaInferred: opts.SchemaInferred,
+		sampleRows:     opts.SampleRows,
+		sampleColumns:  opts.SampleColumns,
+		sampleSeed:     opts.SampleSeed,
+		includeColumns: opts.IncludeColumns,
+		keyColumns:     opts.KeyColumns,
+		contextRows:    opts.ContextRows,
+		timeBudget:     opts.TimeBudget,
+		interrupt:      opts.Interrupt,
+		size:           opts.Size,
+	}
+}
+
+// encodingError reports a record with invalid UTF-8, naming its column when the header
+// is known.
+func encodingError(e *parser.EncodingError, headers []string) Finding {
+	out := Finding{
+		Severity:   SeverityError,
+		Location:   Location{Line: e.LineNumber, Column: e.Column},
+		Message:    e.Describe(),
+		Value:      fmt.Sprintf("% x", e.Bytes),
+		Suggestion: "save the file as UTF-8, or convert it with csvlinter fix --fix-encoding",
+		Type:       "encoding",
+		RuleID:     rules.InvalidUTF8,
+	}
+	if e.Column > 0 && e.Column <= len(headers) {
+		out.Field = headers[e.Column-1]
+	}
+	if len(e.Bytes) > 0 {
+		out.ByteOffset = e.Offset
+	}
+	if e.Line > 0 && e.Line != e.LineNumber {
+		out.Message += fmt.Sprintf(" (physical line %d)", e.Line)
+	}
+	return out
+}
+
+// fieldCountSuggestion tells how a row with the wrong number of fields can be fixed.
+func fieldCountSuggestion(fields []string, width int) string {
+	switch n := len(fields); {
+	case n == width+1 && fields[n-1] == "":
+		return "remove the delimiter at the end of the line"
+	case n > width:
+		return fmt.Sprintf("remove %d field(s), or quote the values that contain the delimiter", n-width)
+	default:
+		return fmt.Sprintf("add %d field(s), or check the previous line for a line break in an unquoted value", width-n)
+	}
+}
+
+// truncatedSuggestion is the suggestion of findings about a file that appears cut off.
+const truncatedSuggestion = "check that the file was copied or exported completely"
+
+// parseSuggestion tells how a row the CSV reader rejects can be fixed, quote being the
+// configured quote character, if any.
+func parseSuggestion(err error, quote string) string {
+	if quote == "" {
+		quote = `"`
+	}
+	switch {
+	case errors.Is(err, csv.ErrBareQuote):
+		return fmt.Sprintf("double the quotes inside quoted values (%s), or quote the whole value", quote+quote)
+	case errors.Is(err, csv.ErrQuote):
+		return fmt.Sprintf("close the quoted value with %s, or double the quotes inside it", quote)
+	}
+	return ""
+}
+
+// fitRow pads a short row with empty values or drops the extra values of a long row when
+// the row's policy tolerates it, and returns the warning to report, if any.
+func (v *Validator) fitRow(row *parser.Row, width int) *Finding {
+	fields := len(row.Data)
+	short := fields < width
+	mode, rule := v.longRows, rules.LongRow
+	message := fmt.Sprintf("row has %d fields, %d more than the %d columns of the header; extra values ignored", len(row.Data), len(row.Data)-width, width)
+	if short {
+		mode, rule = v.shortRows, rules.ShortRow
+		message = fmt.Sprintf("row has %d fields, %d fewer than the %d columns of the header; padded with empty values", len(row.Data), width-len(row.Data), width)
+	}
+	if mode == "" || mode == "error" {
+		return nil
+	}
+	if short {
+		for len(row.Data) < width {
+			row.Data = append(row.Data, "")
+		}
+		if row.Quoted != nil {
+			row.Quoted = append(row.Quoted, make([]bool, width-len(row.Quoted))...)
+		}
+	} else {
+		row.Data = row.Data[:width]
+		if row.Quoted != nil {
+			row.Quoted = row.Quoted[:width]
+		}
+	}
+	if mode != "warn" {
+		return nil
+	}
+	return &Finding{
+		Severity: SeverityWarning,
+		Location: Location{Line: row.LineNumber},
+		Field:    "row",
+		Message:  message,
+		Expected: fmt.Sprintf("%d fields", width),
+		Actual:   fmt.Sprintf("%d fields", fields),
+		Type:     "structure",
+		RuleID:   rule,
 	}
 }
 
@@ -66,53 +539,354 @@ func New(input io.Reader, name string, delimiter string, schemaValidator *schema
 func (v *Validator) Validate() (*Results, error) {
 	startTime := time.Now()
 
+	input := v.input
+	var clock *timer
+	if v.timings {
+		clock = &timer{}
+		input = clock.reader(input)
+	}
+	var counter *countingReader
+	if v.timeBudget > 0 {
+		counter = &countingReader{r: input}
+		input = counter
+	}
+	var fp *fingerprinter
+	if v.fingerprint {
+		var primary *schema.Validator
+		if len(v.schemas) > 0 {
+			primary = v.schemas[0].Validator
+		}
+		fp = newFingerprinter(input, primary)
+		input = fp.input
+	}
+
 	// Create parser
-	p, err := parser.NewParser(v.input, v.delimiter)
+	dialect, err := parser.NewDialect(v.delimiter, v.quote, v.escape)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create parser: %w", err)
+	}
+	p, err := parser.NewParserWithDialect(input, dialect)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create parser: %w", err)
 	}
 	defer p.Close()
+	if v.quotedEmpty || v.quoting != "" || v.fixes {
+		p.TrackQuotes()
+	}
+	var fix *fixer
+	if v.fixes {
+		fix = &fixer{dialect: dialect}
+	}
+	if v.sepLine != "" {
+		p.CountPreamble(1, v.preamble)
+	}
+	if clock != nil {
+		p.TimeUTF8()
+	}
+
+	var errs []Finding
+	var warnings []Finding
+	totalRows := 0
+
+	if v.sepLine != "" && v.sepFinding != "" {
+		e := Finding{
+			Severity:   SeverityError,
+			Location:   Location{Line: 1},
+			Message:    fmt.Sprintf("file starts with an Excel '%s' line", v.sepLine),
+			Value:      v.sepLine,
+			Suggestion: "remove the line and pass the delimiter with --delimiter",
+			Type:       "structure",
+			RuleID:     rules.SepLine,
+		}
+		if v.sepFinding == "warning" {
+			e.Severity = SeverityWarning
+			warnings = append(warnings, e)
+		} else {
+			errs = append(errs, e)
+		}
+	}
+	if p.CROnly() {
+		warnings = append(warnings, Finding{
+			Severity:   SeverityWarning,
+			Location:   Location{Line: 1},
+			Message:    "lines end with a carriage return alone (classic Mac OS line endings); read as line breaks",
+			Suggestion: "convert the line endings to line feeds, e.g. with csvlinter fix",
+			Type:       "structure",
+			RuleID:     rules.LineEndings,
+		})
+	}
+
+	// emit passes errors found since the last call to onError and checks for cancellation
+	emitted := 0
+	emit := func() error {
+		for ; emitted < len(errs); emitted++ {
+			if v.onError != nil {
+				if err := v.onError(errs[emitted]); err != nil {
+					return err
+				}
+			}
+		}
+		if v.ctx != nil {
+			return v.ctx.Err()
+		}
+		return nil
+	}
 
 	// Read headers (UTF-8 validated inside ReadHeaders when streaming)
+	start := clock.now()
 	headers, err := p.ReadHeaders()
+	headerLine := p.GetLineNumber()
+	clock.add(phaseParse, start)
+	if err == nil && v.envelope != nil && v.envelope.HeaderPrefix != "" {
+		// The header record comes before the column header
+		if headers[0] == v.envelope.HeaderPrefix {
+			headers, err = p.ReadHeaders()
+			headerLine = p.GetLineNumber()
+		} else {
+			errs = append(errs, Finding{
+				Severity: SeverityError,
+				Location: Location{Line: 1},
+				Message:  fmt.Sprintf("missing header record starting with '%s'", v.envelope.HeaderPrefix),
+				Type:     "structure",
+				RuleID:   rules.Envelope,
+			})
+		}
+	}
 	if err != nil {
 		var encErr *parser.EncodingError
 		if errors.As(err, &encErr) {
+			errs = []Finding{encodingError(encErr, nil)}
+			emitted = 0
+			if err := emit(); err != nil {
+				return nil, err
+			}
 			return &Results{
 				File:     v.name,
 				Valid:    false,
-				Errors:   []Error{{LineNumber: encErr.LineNumber, Message: "invalid UTF-8 encoding", Type: "encoding"}},
+				Errors:   errs,
 				Duration: time.Since(startTime).String(),
 			}, nil
 		}
 		return nil, fmt.Errorf("failed to read headers: %w", err)
 	}
 
-	var errs []Error
-	var warnings []Warning
-	totalRows := 0
+	if v.transform != nil {
+		v.transform.Start(headers)
+	}
+	if fp != nil {
+		fp.header(headers)
+	}
+	var samples *sampler
+	if v.sampleRows > 0 {
+		samples = newSampler(v.sampleRows, v.sampleColumns, v.sampleSeed)
+		samples.headers = headers
+	}
+	var quoting *quotingState
+	if v.quoting != "" {
+		quoting = newQuotingState(v.quoting, dialect, headers, fix)
+	}
+	var snippets *snipper
+	if v.contextRows > 0 {
+		snippets = newSnipper(v.contextRows, headers, parser.DelimiterRune(v.delimiter))
+	}
+
+	// 1-based column of each header, for locating schema errors
+	columns := make(map[string]int, len(headers))
+	for i, h := range headers {
+		if _, seen := columns[h]; !seen {
+			columns[h] = i + 1
+		}
+	}
+
+	// Columns the schemas reject are reported once here; the discriminator's schemas are
+	// checked when a row first uses them
+	unexpected := newUnexpectedColumns(headers, columns, headerLine)
+	rowSchemas := make([]Schema, len(v.schemas))
+	for i, s := range v.schemas {
+		var found []Finding
+		rowSchemas[i], found = unexpected.schema(s)
+		errs = append(errs, found...)
+	}
+
+	// Without its column the discriminator is reported once and rows get the common schemas
+	discriminatorIndex := -1
+	if v.discriminator != nil {
+		discriminatorIndex = columns[v.discriminator.Column] - 1
+		if discriminatorIndex < 0 {
+			errs = append(errs, Finding{
+				Severity: SeverityError,
+				Location: Location{Line: headerLine},
+				Field:    v.discriminator.Column,
+				Message:  fmt.Sprintf("discriminator column '%s' not found in header", v.discriminator.Column),
+				Type:     "schema",
+				RuleID:   rules.SchemaRequired,
+			})
+		}
+	}
+
+	// checkFindings files a check's findings as errors, or as warnings for advisory checks
+	checkFindings := func(c Check, found []Finding) {
+		if _, advisory := c.(Advisory); advisory {
+			for _, e := range found {
+				e.Severity = SeverityWarning
+				warnings = append(warnings, e)
+			}
+			return
+		}
+		errs = append(errs, found...)
+	}
+	start = clock.now()
+	for _, c := range v.checks {
+		if closer, ok := c.(io.Closer); ok {
+			defer closer.Close()
+		}
+		checkFindings(c, c.Start(headerLine, headers))
+	}
+	clock.add(phaseChecks, start)
+	var chunks *chunkState
+	if v.chunked {
+		chunks = newChunkState(headers, headerLine, v.requireData)
+	}
+	// addFindings files findings as errors or warnings by their severity
+	addFindings := func(found []Finding) {
+		for _, f := range found {
+			if f.Severity == SeverityWarning {
+				warnings = append(warnings, f)
+			} else {
+				errs = append(errs, f)
+			}
+		}
+	}
+	var env *envelopeState
+	if v.envelope != nil {
+		var envErrs []Finding
+		env, envErrs = newEnvelopeState(v.envelope, columns)
+		errs = append(errs, envErrs...)
+	}
+
+	// Every column of the row when asked for with "*"; columns the header lacks are
+	// reported once
+	includeColumns := v.includeColumns
+	if slices.Contains(includeColumns, "*") {
+		includeColumns = nil
+	}
+	for i, c := range includeColumns {
+		if _, ok := columns[c]; !ok && !slices.Contains(includeColumns[:i], c) {
+			warnings = append(warnings, Finding{
+				Severity:   SeverityWarning,
+				Location:   Location{Line: headerLine},
+				Field:      c,
+				Message:    fmt.Sprintf("column '%s' to include in findings not found in header", c),
+				Suggestion: "check the column name, which must match the header exactly",
+				Type:       "structure",
+				RuleID:     rules.IncludeColumn,
+			})
+		}
+	}
+
+	// The key is left out when one of its columns is not in the header
+	var keyIndexes []int
+	for _, c := range v.keyColumns {
+		i, ok := columns[c]
+		if !ok {
+			keyIndexes = nil
+			break
+		}
+		keyIndexes = append(keyIndexes, i-1)
+	}
+
+	// locate gives the findings of the last row read, errors and warnings, its offset, the
+	// values of the columns to include and the row's key
+	var current *parser.Row
+	var currentErrs, currentWarnings int
+	deferred := &deferredRows{}
+	defer deferred.close()
+	locate := func() {
+		if current == nil {
+			return
+		}
+		for _, list := range [][]Finding{errs[currentErrs:], warnings[currentWarnings:]} {
+			for i := range list {
+				if list[i].Line != current.LineNumber {
+					continue
+				}
+				if list[i].ByteOffset == 0 {
+					list[i].ByteOffset = current.Offset
+				}
+				if v.includeColumns != nil && list[i].Columns == nil {
+					list[i].Columns = rowValues(headers, includeColumns, current.Data)
+				}
+				if keyIndexes != nil && list[i].Key == "" {
+					list[i].Key = rowKey(keyIndexes, current.Data)
+				}
+			}
+		}
+		current = nil
+	}
 
 	// Validate each row
+	stopped := false
+	partial := false
+	encodingErrors := 0
+	var coverage *Coverage
 	for {
+		locate()
+		if err := emit(); err != nil {
+			return nil, err
+		}
+		if counter != nil && time.Since(startTime) > v.timeBudget {
+			coverage = newCoverage(v.timeBudget.String(), totalRows, counter.n, v.size)
+			stopped, partial = true, true
+			break
+		}
+		if Interrupted(v.interrupt) {
+			stopped, partial = true, true
+			break
+		}
+		start := clock.now()
 		row, err := p.ReadRow()
+		clock.add(phaseParse, start)
 		if err != nil {
 			if err == io.EOF {
 				break
 			}
+			// Rows with invalid UTF-8 are counted but skipped until there are too many of them
 			var encErr *parser.EncodingError
-			errType := "structure"
-			errMsg := err.Error()
-			lineNum := p.GetLineNumber() + 1
 			if errors.As(err, &encErr) {
-				errType = "encoding"
-				errMsg = "invalid UTF-8 encoding"
-				lineNum = encErr.LineNumber
-			}
-			errs = append(errs, Error{
-				LineNumber: lineNum,
-				Message:    errMsg,
-				Type:       errType,
+				totalRows++
+				errs = append(errs, encodingError(encErr, headers))
+				if encodingErrors++; encodingErrors < v.maxEncoding && !v.failFast {
+					continue
+				}
+				stopped = true
+				break
+			}
+			column := 0
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				column = parseErr.Column
+			}
+			if errors.Is(err, csv.ErrQuote) && p.Ending() == parser.EndsInQuotes {
+				errs = append(errs, Finding{
+					Severity:   SeverityError,
+					Location:   Location{Line: p.GetLineNumber() + 1},
+					Message:    "file appears truncated: the last line ends inside a quoted field",
+					Suggestion: truncatedSuggestion,
+					Type:       "structure",
+					RuleID:     rules.Truncated,
+				})
+				stopped = true
+				break
+			}
+			errs = append(errs, Finding{
+				Severity:   SeverityError,
+				Location:   Location{Line: p.GetLineNumber() + 1, Column: column},
+				Message:    err.Error(),
+				Suggestion: parseSuggestion(err, v.quote),
+				Type:       "structure",
+				RuleID:     rules.MalformedRow,
 			})
+			stopped = true
 			break
 		}
 
@@ -120,60 +894,255 @@ func (v *Validator) Validate() (*Results, error) {
 		if row.IsEmpty() {
 			continue
 		}
+		rowErrs := len(errs)
+		current, currentErrs, currentWarnings = row, rowErrs, len(warnings)
+
+		start = clock.now()
+		if env != nil {
+			envErrs, isTrailer := env.row(row)
+			errs = append(errs, envErrs...)
+			if isTrailer {
+				clock.add(phaseStructure, start)
+				continue
+			}
+		}
+
+		if chunks != nil {
+			header, found := chunks.row(row)
+			addFindings(found)
+			if header {
+				clock.add(phaseStructure, start)
+				continue
+			}
+		}
 
 		totalRows++
+		if quoting != nil {
+			warnings = append(warnings, quoting.row(row)...)
+		}
+		clock.add(phaseStructure, start)
+		if v.transform != nil {
+			v.transform.Row(row.Data)
+		}
+		if fp != nil {
+			fp.row(headers, row.Data)
+		}
 
-		// Basic structure validation
+		// Basic structure validation; tolerated ragged rows are fitted to the header
+		start = clock.now()
+		// A short last line without a line ending was most likely cut off, and is not
+		// padded even where short rows are tolerated
+		truncated := len(row.Data) < len(headers) && p.Ending() == parser.EndsMidLine
+		if len(row.Data) != len(headers) && !truncated {
+			if w := v.fitRow(row, len(headers)); w != nil {
+				w.Fix = fix.fieldCount(row, len(headers))
+				warnings = append(warnings, *w)
+			}
+		}
+		var malformed *Finding // Structure error that stops the row from being validated further
 		if len(row.Data) != len(headers) {
-			errs = append(errs, Error{
-				LineNumber: row.LineNumber,
+			malformed = &Finding{
+				Severity:   SeverityError,
+				Location:   Location{Line: row.LineNumber},
 				Field:      "row",
 				Message:    fmt.Sprintf("column count mismatch: expected %d, got %d", len(headers), len(row.Data)),
+				Expected:   fmt.Sprintf("%d fields", len(headers)),
+				Actual:     fmt.Sprintf("%d fields", len(row.Data)),
+				Suggestion: fieldCountSuggestion(row.Data, len(headers)),
+				Fix:        fix.fieldCount(row, len(headers)),
 				Type:       "structure",
-			})
+				RuleID:     rules.ColumnCount,
+			}
+			if truncated {
+				malformed.Message = fmt.Sprintf("file appears truncated: the last line has %d of %d fields and no line ending", len(row.Data), len(headers))
+				malformed.Suggestion, malformed.Fix, malformed.RuleID = truncatedSuggestion, nil, rules.Truncated
+			}
+		} else if parser.RepeatsHeader(row.Data, headers) {
+			// Concatenated exports repeat the header where one file ends and the next begins
+			malformed = &Finding{
+				Severity:   SeverityError,
+				Location:   Location{Line: row.LineNumber},
+				Field:      "row",
+				Message:    fmt.Sprintf("row repeats the header on line %d, as where exported files were concatenated", headerLine),
+				Suggestion: "remove the row, e.g. with csvlinter fix --drop-repeated-headers",
+				Fix:        fix.record(row, "remove the repeated header"),
+				Type:       "structure",
+				RuleID:     rules.RepeatedHeader,
+			}
+		}
+		if malformed != nil {
+			errs = append(errs, *malformed)
+			clock.add(phaseStructure, start)
+			if samples != nil {
+				samples.row(row.LineNumber, row.Data, errs[rowErrs:])
+			}
+			if snippets != nil {
+				snippets.row(row.LineNumber, row.Data, true)
+			}
 			// Fail fast if requested
 			if v.failFast {
+				stopped = true
 				break
 			}
 			// Skip schema validation for this row
 			continue
 		}
 
+		clock.add(phaseStructure, start)
+
 		// Schema validation if available
-		if v.schemaValidator != nil {
-			schemaErrors, err := v.schemaValidator.ValidateRow(headers, row.Data)
+		start = clock.now()
+		schemas := rowSchemas
+		if discriminatorIndex >= 0 {
+			if s, ok := v.discriminator.Schemas[row.Data[discriminatorIndex]]; ok {
+				s, found := unexpected.schema(s)
+				errs = append(errs, found...)
+				schemas = append(schemas[:len(schemas):len(schemas)], s)
+			}
+		}
+		for _, s := range schemas {
+			schemaErrors, err := s.Validator.ValidateRowQuoted(headers, row.Data, row.Quoted)
 			if err != nil {
 				return nil, fmt.Errorf("schema validation error on line %d: %w", row.LineNumber, err)
 			}
 
 			for _, schemaErr := range schemaErrors {
-				errs = append(errs, Error{
-					LineNumber: row.LineNumber,
+				column := columns[schemaErr.Field]
+				var valueFix *Fix
+				// Transformed values are not those of the file
+				if schemaErr.Replacement != "" && column > 0 && v.transform == nil {
+					valueFix = fix.field(row, column-1, schemaErr.Replacement, schemaErr.Suggestion)
+				}
+				errs = append(errs, Finding{
+					Severity:   SeverityError,
+					Location:   Location{Line: row.LineNumber, Column: column},
 					Field:      schemaErr.Field,
 					Message:    schemaErr.Message,
 					Value:      schemaErr.Value,
+					Expected:   schemaErr.Expected,
+					Actual:     schemaErr.Actual,
+					Suggestion: schemaErr.Suggestion,
+					Fix:        valueFix,
 					Type:       "schema",
+					RuleID:     rules.ForSchemaKeyword(schemaErr.Keyword),
+					Schema:     s.Label,
 				})
 			}
 		}
 
+		clock.add(phaseSchema, start)
+
+		start = clock.now()
+		later := false // A check may report the row once it is finished
+		for _, c := range v.checks {
+			checkFindings(c, c.Row(row.LineNumber, row.Data))
+			if d, ok := c.(Deferred); ok && d.Deferred() {
+				later = true
+			}
+		}
+		if later {
+			loc := rowLocation{Line: row.LineNumber, Offset: row.Offset}
+			if v.includeColumns != nil {
+				loc.Columns = rowValues(headers, includeColumns, row.Data)
+			}
+			if keyIndexes != nil {
+				loc.Key = rowKey(keyIndexes, row.Data)
+			}
+			deferred.add(loc)
+		}
+		clock.add(phaseChecks, start)
+		if samples != nil {
+			samples.row(row.LineNumber, row.Data, errs[rowErrs:])
+		}
+		if snippets != nil {
+			snippets.row(row.LineNumber, row.Data, len(errs) > rowErrs)
+		}
+
 		// Fail fast if requested
 		if v.failFast && len(errs) > 0 {
+			stopped = true
 			break
 		}
 	}
 
+	locate()
+
+	// Checks over the whole file only conclude when they saw all of it
+	if !stopped {
+		start := clock.now()
+		finishErrs, finishWarnings := len(errs), len(warnings)
+		for _, c := range v.checks {
+			checkFindings(c, c.Finish())
+		}
+		deferred.fill(errs[finishErrs:], warnings[finishWarnings:])
+		clock.add(phaseChecks, start)
+		if env != nil {
+			errs = append(errs, env.finish(totalRows)...)
+		}
+		if chunks != nil {
+			addFindings(chunks.finish())
+		}
+		// A header alone usually means the export that wrote the file failed
+		if totalRows == 0 {
+			e := Finding{
+				Severity:   SeverityWarning,
+				Location:   Location{Line: headerLine},
+				Message:    "file has a header but no data rows",
+				Suggestion: "check that the export that wrote the file did not fail",
+				Type:       "structure",
+				RuleID:     rules.NoData,
+			}
+			if v.requireData {
+				e.Severity = SeverityError
+				errs = append(errs, e)
+			} else {
+				warnings = append(warnings, e)
+			}
+		}
+	}
+	if err := emit(); err != nil {
+		return nil, err
+	}
+
+	// Hashes of part of the input would be misleading, and reading the rest breaks the budget or the interrupt
+	var sha, fingerprint string
+	if fp != nil && !partial {
+		sha, fingerprint = fp.finish()
+	}
+	var rowSamples map[string][]Sample
+	if samples != nil {
+		rowSamples = samples.result()
+	}
+	var rowContext *RowContext
+	if snippets != nil {
+		rowContext = snippets.result()
+	}
+
 	duration := time.Since(startTime)
 	valid := len(errs) == 0
 
-	return &Results{
+	results := &Results{
 		File:           v.name,
 		TotalRows:      totalRows,
 		Errors:         errs,
 		Warnings:       warnings,
 		Duration:       duration.String(),
 		Valid:          valid,
-		SchemaUsed:     v.schemaValidator != nil,
+		SchemaUsed:     len(v.schemas) > 0 || v.discriminator != nil,
 		SchemaInferred: v.schemaInferred,
-	}, nil
+		SHA256:         sha,
+		Fingerprint:    fingerprint,
+		Samples:        rowSamples,
+		Partial:        partial,
+		Coverage:       coverage,
+		Context:        rowContext,
+	}
+	if keyIndexes != nil {
+		results.KeyColumns = v.keyColumns
+	}
+	if clock != nil {
+		results.Timings = clock.timings(p.UTF8Time())
+	}
+	results.SortFindings()
+	return results, nil
 }
@@ -1,9 +1,19 @@
 package validator
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
 	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/parser"
+	"github.com/csvlinter/csvlinter/internal/rules"
+	"github.com/csvlinter/csvlinter/internal/schema"
 )
 
 func TestValidator(t *testing.T) {
@@ -75,3 +85,467 @@ func TestValidator(t *testing.T) {
 		})
 	}
 }
+
+func TestTruncateValues(t *testing.T) {
+	long := "abcdefghij"
+	results := &Results{
+		Errors: []Finding{
+			{Severity: SeverityError, Value: long, Message: "'" + long + "' is not valid 'email'"},
+			{Severity: SeverityError, Value: "abcd", Message: "bad abcd"},
+		},
+		Warnings: []Finding{{Severity: SeverityWarning, Value: "ünïcødé", Message: "odd ünïcødé"}},
+	}
+	results.TruncateValues(4)
+
+	if e := results.Errors[0]; e.Value != "abcd…" || e.Message != "'abcd…' is not valid 'email'" || !e.ValueTruncated {
+		t.Errorf("Expected long value truncated, got %+v", e)
+	}
+	if e := results.Errors[1]; e.Value != "abcd" || e.ValueTruncated {
+		t.Errorf("Expected value within the limit to be kept, got %+v", e)
+	}
+	if w := results.Warnings[0]; w.Value != "ünïc…" || !w.ValueTruncated {
+		t.Errorf("Expected truncation by characters, got %+v", w)
+	}
+
+	results = &Results{Errors: []Finding{{Severity: SeverityError, Value: long}}}
+	results.TruncateValues(0)
+	if results.Errors[0].Value != long {
+		t.Errorf("Expected no truncation with max 0")
+	}
+}
+
+func TestValidatorRulesAndColumns(t *testing.T) {
+	sv, err := schema.NewValidatorFromReader(strings.NewReader(`{
+		"type": "object",
+		"properties": {"id": {"type": "integer"}, "email": {"type": "string", "format": "email"}},
+		"required": ["id", "email", "name"]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := "id,email\n1,not-an-email\n2\n3,\"closed\"early\n"
+	results, err := New(strings.NewReader(input), "test.csv", ",", sv, false, false).Validate()
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	got := map[string]Finding{}
+	for _, e := range results.Errors {
+		got[e.RuleID] = e
+	}
+	if e, ok := got[rules.SchemaFormat]; !ok || e.Column != 2 || e.Field != "email" {
+		t.Errorf("Expected a format error in column 2, got %+v", results.Errors)
+	}
+	if e, ok := got[rules.SchemaRequired]; !ok || e.Column != 0 {
+		t.Errorf("Expected a required error without a column, got %+v", results.Errors)
+	}
+	if _, ok := got[rules.ColumnCount]; !ok {
+		t.Errorf("Expected a column count error, got %+v", results.Errors)
+	}
+	if e, ok := got[rules.MalformedRow]; !ok || e.Column == 0 {
+		t.Errorf("Expected a parse error with a column, got %+v", results.Errors)
+	}
+}
+
+func TestValidatorTruncated(t *testing.T) {
+	validate := func(input string) *Results {
+		t.Helper()
+		results, err := NewWithOptions(strings.NewReader(input), Options{Delimiter: ","}).Validate()
+		if err != nil {
+			t.Fatalf("Validate failed: %v", err)
+		}
+		return results
+	}
+	for _, tc := range []struct {
+		name, input, rule string
+		line              int
+	}{
+		{"open quote", "id,note\n1,ok\n2,\"cut off", rules.Truncated, 3},
+		{"open quote and newline", "id,note\n1,\"cut\noff\n", rules.Truncated, 2},
+		{"short last line", "id,name,email\n1,a,a@example.com\n2,b", rules.Truncated, 3},
+		{"short last line, CRLF", "id,name,email\r\n1,a,a@example.com\r\n2,b", rules.Truncated, 3},
+		// With a line ending, or anywhere but the end, a short row is a column mismatch
+		{"short last line ending", "id,name,email\n1,a,a@example.com\n2,b\n", rules.ColumnCount, 3},
+		{"short row mid-file", "id,name,email\n2,b\n1,a,a@example.com", rules.ColumnCount, 2},
+		{"long last line", "id,name\n1,a,x", rules.ColumnCount, 2},
+		{"stray quote", "id,name\n1,\"a\"b", rules.MalformedRow, 2},
+	} {
+		results := validate(tc.input)
+		if len(results.Errors) != 1 || results.Errors[0].RuleID != tc.rule || results.Errors[0].Line != tc.line {
+			t.Errorf("%s: want %s on line %d, got %+v", tc.name, tc.rule, tc.line, results.Errors)
+		}
+	}
+	if results := validate("id,name\n1,a"); !results.Valid {
+		t.Errorf("Expected a complete last line without a line ending to pass, got %+v", results.Errors)
+	}
+
+	// Tolerating short rows does not pad a cut-off last line
+	for _, mode := range []string{"warn", "pad"} {
+		results, err := NewWithOptions(strings.NewReader("id,name,email\n1,a\n2,b"), Options{Delimiter: ",", ShortRows: mode}).Validate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results.Errors) != 1 || results.Errors[0].RuleID != rules.Truncated || results.Errors[0].Line != 3 {
+			t.Errorf("%s: want %s on line 3, got %+v", mode, rules.Truncated, results.Errors)
+		}
+		if want := map[string]int{"warn": 1, "pad": 0}[mode]; len(results.Warnings) != want {
+			t.Errorf("%s: want %d warning(s) for the short row on line 2, got %+v", mode, want, results.Warnings)
+		}
+	}
+}
+
+func TestValidatorCROnly(t *testing.T) {
+	sv, err := schema.NewValidatorFromReader(strings.NewReader(`{"type": "object", "properties": {"id": {"type": "integer"}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := NewWithOptions(strings.NewReader("id,name\r1,a\rx,b\r"), Options{Delimiter: ",", Schemas: []Schema{{Validator: sv}}}).Validate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results.TotalRows != 2 || len(results.Warnings) != 1 || results.Warnings[0].RuleID != rules.LineEndings {
+		t.Errorf("Expected two rows and a line ending warning, got %d rows, %+v", results.TotalRows, results.Warnings)
+	}
+	if len(results.Errors) != 1 || results.Errors[0].Line != 3 || results.Errors[0].ByteOffset != 12 {
+		t.Errorf("Expected the schema error on line 3 at byte 12, got %+v", results.Errors)
+	}
+}
+
+func TestValidatorWhitespace(t *testing.T) {
+	sv, err := schema.NewValidatorFromReader(strings.NewReader(`{"type": "object", "properties": {"temp": {"type": "number"}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := "time   temp\tnote\n0.0    21.5   \"warm up\"  \n0.5\t\t21.7\n1.0  x  ok\n"
+	results, err := NewWithOptions(strings.NewReader(input), Options{Delimiter: parser.Whitespace, Schemas: []Schema{{Validator: sv}}, Fixes: true}).Validate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results.TotalRows != 3 || len(results.Errors) != 2 {
+		t.Fatalf("Expected a short row and a schema error in 3 rows, got %d rows and %+v", results.TotalRows, results.Errors)
+	}
+	if e := results.Errors[0]; e.RuleID != rules.ColumnCount || e.Line != 3 || e.Fix == nil || e.Fix.Text != ` ""` || e.Fix.Offset != int64(strings.Index(input, "21.7")+4) {
+		t.Errorf("Expected the short row padded with a quoted empty field, got %+v", e)
+	}
+	if e := results.Errors[1]; e.Line != 4 || e.Field != "temp" {
+		t.Errorf("Expected the schema error on temp in line 4, got %+v", e)
+	}
+}
+
+func TestValidatorEscape(t *testing.T) {
+	input := "id,name,note\n1,Smith\\, John,a\\\\b\n2,x\n"
+	results, err := NewWithOptions(strings.NewReader(input), Options{Delimiter: ",", Escape: `\`, Fixes: true}).Validate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results.TotalRows != 2 || len(results.Errors) != 1 {
+		t.Fatalf("Expected only the short row reported, got %d rows and %+v", results.TotalRows, results.Errors)
+	}
+	if e := results.Errors[0]; e.RuleID != rules.ColumnCount || e.Line != 3 || e.Fix == nil || e.Fix.Offset != int64(len(input)-1) {
+		t.Errorf("Expected the short row on line 3 padded at its end, got %+v", e)
+	}
+}
+
+func TestValidatorQuote(t *testing.T) {
+	input := "id,name\n'1','O''Brien, Pat'\n"
+	results, err := NewWithOptions(strings.NewReader(input), Options{Delimiter: ",", Quote: "'", Quoting: parser.QuoteMinimal, Fixes: true}).Validate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results.Errors) != 0 || len(results.Warnings) != 1 {
+		t.Fatalf("Expected only the needless quotes of id reported, got %+v %+v", results.Errors, results.Warnings)
+	}
+	if w := results.Warnings[0]; w.Field != "id" || w.Fix == nil || w.Fix.Text != "1" || w.Fix.Offset != 8 || w.Fix.Length != 3 {
+		t.Errorf("Expected a fix removing the single quotes of id, got %+v", w)
+	}
+}
+
+func TestValidatorRepeatedHeader(t *testing.T) {
+	sv, err := schema.NewValidatorFromReader(strings.NewReader(`{"type": "object", "properties": {"id": {"type": "integer"}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := "id,name\n1,a\nid,name\n2,b\nID,Name\n"
+	results, err := NewWithOptions(strings.NewReader(input), Options{Delimiter: ",", Schemas: []Schema{{Validator: sv}}, Fixes: true}).Validate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Repeated headers are reported once each, not also as schema errors
+	if len(results.Errors) != 2 || results.TotalRows != 4 {
+		t.Fatalf("Expected two repeated headers in 4 rows, got %d rows and %+v", results.TotalRows, results.Errors)
+	}
+	for i, line := range []int{3, 5} {
+		e := results.Errors[i]
+		if e.RuleID != rules.RepeatedHeader || e.Line != line || e.Fix == nil || e.Fix.Text != "" {
+			t.Errorf("Expected a repeated header with a removing fix on line %d, got %+v", line, e)
+		}
+	}
+	if fix := results.Errors[0].Fix; input[fix.Offset:fix.Offset+int64(fix.Length)] != "id,name\n" {
+		t.Errorf("Expected the fix to remove line 3, got %+v", fix)
+	}
+}
+
+func TestFindings(t *testing.T) {
+	sv, err := schema.NewValidatorFromReader(strings.NewReader(`{"type": "object", "properties": {"id": {"type": "integer"}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := "id,name\n1,a\nx,b\n3\n"
+	results, err := NewWithOptions(strings.NewReader(input), Options{Delimiter: ",", Schemas: []Schema{{Validator: sv}}, ShortRows: "warn"}).Validate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results.Errors) != 1 || len(results.Warnings) != 1 {
+		t.Fatalf("want an error and a warning, got %+v", results)
+	}
+	e, w := results.Errors[0], results.Warnings[0]
+	if e.Severity != SeverityError || e.Location != (Location{Line: 3, Column: 1, ByteOffset: 12}) || e.Expected != "integer" || e.Actual != "string" {
+		t.Errorf("schema error = %+v", e)
+	}
+	if w.Severity != SeverityWarning || w.Line != 4 || w.ByteOffset != 16 || w.Expected != "2 fields" || w.Actual != "1 fields" {
+		t.Errorf("short row warning = %+v", w)
+	}
+
+	// JSON keeps the flat fields of reports and reads back the same finding
+	data, err := json.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range []string{`"severity":"error"`, `"line_number":3`, `"column":1`, `"byte_offset":12`, `"expected":"integer"`, `"suggestion":"write a whole number`, `"rule":"SCH002"`} {
+		if !strings.Contains(string(data), field) {
+			t.Errorf("JSON %s lacks %s", data, field)
+		}
+	}
+	var back Finding
+	if err := json.Unmarshal(data, &back); err != nil || !reflect.DeepEqual(back, e) {
+		t.Errorf("round trip = %+v, %v; want %+v", back, err, e)
+	}
+}
+
+func TestStructureSuggestions(t *testing.T) {
+	input := "id,name\n1,a,\n2,b,c\n3\n4,x\"y\n"
+	results, err := NewWithOptions(strings.NewReader(input), Options{Delimiter: ","}).Validate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"remove the delimiter at the end of the line",
+		"remove 1 field(s), or quote the values that contain the delimiter",
+		"add 1 field(s), or check the previous line for a line break in an unquoted value",
+		`double the quotes inside quoted values (""), or quote the whole value`,
+	}
+	if len(results.Errors) != len(want) {
+		t.Fatalf("want %d errors, got %+v", len(want), results.Errors)
+	}
+	for i, w := range want {
+		if got := results.Errors[i].Suggestion; got != w {
+			t.Errorf("error %d (%s): suggestion %q, want %q", i, results.Errors[i].Message, got, w)
+		}
+	}
+
+	results, err = NewWithOptions(strings.NewReader("id,name\n1,x'y\n"), Options{Delimiter: ",", Quote: "'"}).Validate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []string{"double the quotes inside quoted values (''), or quote the whole value"}
+	if len(results.Errors) != 1 || results.Errors[0].Suggestion != want[0] || !strings.Contains(results.Errors[0].Message, "bare '") {
+		t.Errorf("with --quote ', got %+v, want a bare ' error suggesting %q", results.Errors, want[0])
+	}
+}
+
+func TestValidatorDiscriminator(t *testing.T) {
+	compile := func(src string) *schema.Validator {
+		t.Helper()
+		sv, err := schema.NewValidatorFromReader(strings.NewReader(src))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sv
+	}
+	base := compile(`{"type":"object","properties":{"amount":{"type":"number"}}}`)
+	refund := compile(`{"type":"object","properties":{"amount":{"type":"number","maximum":0}}}`)
+	input := "type,amount\nsale,10\nrefund,-5\nrefund,5\nother,7\nsale,abc\n"
+
+	opts := Options{
+		Name:          "mixed.csv",
+		Delimiter:     ",",
+		Schemas:       []Schema{{Validator: base, Label: "base.json"}},
+		Discriminator: &Discriminator{Column: "type", Schemas: map[string]Schema{"refund": {Validator: refund, Label: "refund.json"}}},
+	}
+	results, err := NewWithOptions(strings.NewReader(input), opts).Validate()
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if len(results.Errors) != 2 {
+		t.Fatalf("Expected 2 errors, got %+v", results.Errors)
+	}
+	if e := results.Errors[0]; e.Line != 4 || e.Schema != "refund.json" {
+		t.Errorf("Expected the positive refund to fail the refund schema, got %+v", e)
+	}
+	if e := results.Errors[1]; e.Line != 6 || e.Schema != "base.json" {
+		t.Errorf("Expected the non-numeric sale to fail the base schema, got %+v", e)
+	}
+
+	opts.Discriminator.Column = "kind"
+	results, err = NewWithOptions(strings.NewReader(input), opts).Validate()
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if len(results.Errors) != 2 || results.Errors[0].Line != 1 || results.Errors[0].Field != "kind" {
+		t.Errorf("Expected the missing discriminator column to be reported on the header, got %+v", results.Errors)
+	}
+}
+
+func TestValidatorUnexpectedColumns(t *testing.T) {
+	compile := func(src string) *schema.Validator {
+		t.Helper()
+		sv, err := schema.NewValidatorFromReader(strings.NewReader(src))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sv
+	}
+	base := compile(`{"type":"object","properties":{"type":{},"amount":{"type":"number"}},"additionalProperties":false}`)
+	refund := compile(`{"type":"object","properties":{"type":{}},"additionalProperties":false}`)
+	input := "type,amount,note\nsale,10,a\nrefund,-5,b\nsale,abc,c\nrefund,-1,d\n"
+
+	opts := Options{
+		Name:          "mixed.csv",
+		Delimiter:     ",",
+		Schemas:       []Schema{{Validator: base, Label: "base.json"}},
+		Discriminator: &Discriminator{Column: "type", Schemas: map[string]Schema{"refund": {Validator: refund, Label: "refund.json"}}},
+	}
+	results, err := NewWithOptions(strings.NewReader(input), opts).Validate()
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	var got []string
+	for _, e := range results.Errors {
+		got = append(got, fmt.Sprintf("%d:%d:%s:%s:%s", e.Line, e.Column, e.Field, e.RuleID, e.Schema))
+	}
+	want := []string{
+		"1:2:amount:" + rules.SchemaAdditional + ":refund.json",
+		"1:3:note:" + rules.SchemaAdditional + ":base.json",
+		"1:3:note:" + rules.SchemaAdditional + ":refund.json",
+		"4:2:amount:" + rules.SchemaType + ":base.json",
+	}
+	sort.Strings(got)
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected unexpected columns once on the header, got %v", got)
+	}
+}
+
+func TestValidatorRaggedRows(t *testing.T) {
+	sv, err := schema.NewValidatorFromReader(strings.NewReader(`{"type":"object","properties":{"c":{"type":"string","minLength":1}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := "a,b,c\n1,2\n1,2,3,4\n1,2,3\n"
+	validate := func(short, long string) *Results {
+		t.Helper()
+		opts := Options{Delimiter: ",", Schemas: []Schema{{Validator: sv}}, ShortRows: short, LongRows: long}
+		results, err := NewWithOptions(strings.NewReader(input), opts).Validate()
+		if err != nil {
+			t.Fatalf("Validate failed: %v", err)
+		}
+		return results
+	}
+
+	results := validate("", "")
+	if len(results.Errors) != 2 || results.Errors[0].RuleID != rules.ColumnCount || results.Errors[1].RuleID != rules.ColumnCount {
+		t.Errorf("Expected both ragged rows to be errors by default, got %+v", results.Errors)
+	}
+
+	// Padded, the short row reaches the schema with an empty c
+	results = validate("pad", "ignore")
+	if len(results.Errors) != 1 || results.Errors[0].Line != 2 || results.Errors[0].Field != "c" || len(results.Warnings) != 0 {
+		t.Errorf("Expected only the padded value to fail the schema, got %+v %+v", results.Errors, results.Warnings)
+	}
+
+	results = validate("warn", "warn")
+	if len(results.Warnings) != 2 || results.Warnings[0].RuleID != rules.ShortRow || results.Warnings[1].RuleID != rules.LongRow {
+		t.Errorf("Expected distinct short and long row warnings, got %+v", results.Warnings)
+	}
+	if want := "row has 4 fields, 1 more than the 3 columns of the header; extra values ignored"; results.Warnings[1].Message != want {
+		t.Errorf("Expected %q, got %q", want, results.Warnings[1].Message)
+	}
+
+	results = validate("pad", "")
+	if len(results.Errors) != 2 || results.Errors[1].RuleID != rules.ColumnCount || results.Errors[1].Line != 3 {
+		t.Errorf("Expected the long row to stay an error, got %+v", results.Errors)
+	}
+}
+
+func TestValidatorHeaderOnly(t *testing.T) {
+	validate := func(input string, requireData bool) *Results {
+		t.Helper()
+		results, err := NewWithOptions(strings.NewReader(input), Options{Delimiter: ",", SepLine: "sep=,", RequireData: requireData}).Validate()
+		if err != nil {
+			t.Fatalf("Validate failed: %v", err)
+		}
+		return results
+	}
+
+	// The header is line 2, after the sep= line; blank lines are no data
+	results := validate("id,name\n\n", false)
+	if !results.Valid || len(results.Warnings) != 1 || results.Warnings[0].RuleID != rules.NoData || results.Warnings[0].Line != 2 {
+		t.Errorf("Expected a header-only file to warn, got %+v", results)
+	}
+	results = validate("id,name\n", true)
+	if results.Valid || len(results.Errors) != 1 || results.Errors[0].RuleID != rules.NoData || len(results.Warnings) != 0 {
+		t.Errorf("Expected RequireData to make it an error, got %+v", results)
+	}
+	if results = validate("id,name\n1,a\n", true); !results.Valid || len(results.Warnings) != 0 {
+		t.Errorf("Expected a file with data to pass, got %+v", results)
+	}
+}
+
+func TestValidatorSepPreamble(t *testing.T) {
+	// Offsets and physical lines count the sep= line removed from the input
+	preamble := "sep=,\r\n"
+	results, err := NewWithOptions(strings.NewReader("id,name\n1,\xff\n2\n"), Options{Delimiter: ",", SepLine: "sep=,", Preamble: int64(len(preamble)), EncodingErrors: 10}).Validate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results.Errors) != 2 {
+		t.Fatalf("Expected two errors, got %+v", results.Errors)
+	}
+	if e := results.Errors[0]; e.RuleID != rules.InvalidUTF8 || e.Line != 3 || e.ByteOffset != 17 || strings.Contains(e.Message, "physical line") {
+		t.Errorf("Expected invalid UTF-8 on line 3 at byte 17, got %+v", e)
+	}
+	if e := results.Errors[1]; e.RuleID != rules.ColumnCount || e.Line != 4 || e.ByteOffset != 19 {
+		t.Errorf("Expected a short row on line 4 at byte 19, got %+v", e)
+	}
+}
+
+func TestValidatorEncodingErrors(t *testing.T) {
+	input := "id,name\n1,\xff\n2,ok\n3,b\xc3(\n4,\xfe\n5,ok\n"
+	validate := func(max int) *Results {
+		t.Helper()
+		results, err := NewWithOptions(strings.NewReader(input), Options{Delimiter: ",", EncodingErrors: max}).Validate()
+		if err != nil {
+			t.Fatalf("Validate failed: %v", err)
+		}
+		return results
+	}
+
+	results := validate(0)
+	if len(results.Errors) != 1 || results.Valid {
+		t.Fatalf("Expected validation to stop at the first encoding error, got %+v", results.Errors)
+	}
+	got := results.Errors[0]
+	if got.RuleID != rules.InvalidUTF8 || got.Line != 2 || got.Column != 2 || got.Field != "name" || got.Value != "ff" ||
+		got.Message != "invalid UTF-8 encoding at byte 10: ff" {
+		t.Errorf("Expected the invalid byte located, got %+v", got)
+	}
+
+	results = validate(2)
+	if len(results.Errors) != 2 || results.Errors[1].Line != 4 || results.Errors[1].Value != "c3" {
+		t.Errorf("Expected two encoding errors, got %+v", results.Errors)
+	}
+
+	results = validate(10)
+	if len(results.Errors) != 3 || results.TotalRows != 5 {
+		t.Errorf("Expected every invalid row reported and the rest read, got %d rows: %+v", results.TotalRows, results.Errors)
+	}
+}
@@ -31,5 +31,8 @@
 // so callers can check results.Valid; use it when you need to write formatted output
 // to a writer or file. All functions read from r until EOF or error.
 //
+// To receive findings while the input is read, use NewValidator(r, opts).ValidateStream
+// with a callback; returning an error from it, or cancelling the context, stops early.
+//
 // Benchmarks and tool comparison (csvkit, csvlint): https://github.com/csvlinter/csvlinter
 package csvlinter
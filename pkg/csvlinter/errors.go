@@ -0,0 +1,73 @@
+package csvlinter
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+
+	"github.com/csvlinter/csvlinter/internal/parser"
+)
+
+// ErrorCode classifies operational failures: problems that prevent validation from
+// running, as opposed to validation findings reported in Results.
+type ErrorCode string
+
+const (
+	CodeFileNotFound    ErrorCode = "FILE_NOT_FOUND"   // Input file does not exist
+	CodeFileUnreadable  ErrorCode = "FILE_UNREADABLE"  // Input file exists but cannot be opened or read
+	CodeEmptyInput      ErrorCode = "EMPTY_INPUT"      // Input has no header row
+	CodeInvalidInput    ErrorCode = "INVALID_INPUT"    // Input cannot be parsed far enough to validate (e.g. malformed header)
+	CodeSchemaNotFound  ErrorCode = "SCHEMA_NOT_FOUND" // Schema file does not exist
+	CodeSchemaInvalid   ErrorCode = "SCHEMA_INVALID"   // Schema cannot be read or compiled
+	CodeConfigInvalid   ErrorCode = "CONFIG_INVALID"   // Config file cannot be read or parsed
+	CodeManifestInvalid ErrorCode = "MANIFEST_INVALID" // Manifest file cannot be read or parsed
+	CodeInvalidArgument ErrorCode = "INVALID_ARGUMENT" // Bad flag, option value or missing argument
+	CodeOutputFailed    ErrorCode = "OUTPUT_FAILED"    // Report or side output could not be written
+	CodeCancelled       ErrorCode = "CANCELLED"        // Validation was cancelled, or its context's deadline passed, before it finished
+	CodeInternal        ErrorCode = "INTERNAL"         // Anything not covered above
+)
+
+// OpError is an operational failure with a stable, machine-readable Code.
+type OpError struct {
+	Code    ErrorCode
+	Message string
+	Err     error // Underlying cause, if any
+}
+
+func (e *OpError) Error() string {
+	return e.Message
+}
+
+func (e *OpError) Unwrap() error { return e.Err }
+
+// newOpError wraps err with code; the message is err's text.
+func newOpError(code ErrorCode, err error) *OpError {
+	return &OpError{Code: code, Message: err.Error(), Err: err}
+}
+
+// opErrorf builds an OpError without an underlying cause.
+func opErrorf(code ErrorCode, format string, args ...any) *OpError {
+	return &OpError{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// inputError classifies a failure to read or parse the CSV input.
+func inputError(err error) *OpError {
+	var parseErr *csv.ParseError
+	var encErr *parser.EncodingError
+	switch {
+	case errors.Is(err, parser.ErrEmptyInput):
+		return newOpError(CodeEmptyInput, err)
+	case errors.As(err, &parseErr), errors.As(err, &encErr):
+		return newOpError(CodeInvalidInput, err)
+	}
+	return newOpError(CodeInternal, err)
+}
+
+// CodeOf returns the ErrorCode carried by err, or CodeInternal when err is not an OpError.
+func CodeOf(err error) ErrorCode {
+	var opErr *OpError
+	if errors.As(err, &opErr) {
+		return opErr.Code
+	}
+	return CodeInternal
+}
@@ -0,0 +1,45 @@
+package csvlinter
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+// LintFS validates the file name in fsys, such as an embed.FS, a zip.Reader or a cloud
+// storage abstraction, without a copy on disk. It behaves like LintAdvanced with
+// opts.FS set to fsys and opts.Filename to name, so schemas are resolved next to the
+// file in fsys. A missing file is a CodeFileNotFound *OpError.
+func LintFS(fsys fs.FS, name string, opts Options, writer io.Writer) (*validator.Results, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		code := CodeFileUnreadable
+		if errors.Is(err, fs.ErrNotExist) {
+			code = CodeFileNotFound
+		}
+		return nil, &OpError{Code: code, Message: fmt.Sprintf("Cannot open file: %v", err), Err: err}
+	}
+	defer f.Close()
+	opts.FS = fsys
+	opts.Filename = name
+	return LintAdvanced(f, opts, writer)
+}
+
+// LintFilesFS validates the files at paths in fsys and writes one combined report, like
+// LintFiles with opts.FS set to fsys. Manifests are not supported.
+func LintFilesFS(fsys fs.FS, paths []string, opts Options, writer io.Writer) (*validator.Batch, error) {
+	opts.FS = fsys
+	return LintFiles(paths, opts, writer)
+}
+
+// openInput opens name in fsys, or on disk when fsys is nil.
+func openInput(fsys fs.FS, name string) (fs.File, error) {
+	if fsys == nil {
+		return os.Open(name)
+	}
+	return fsys.Open(name)
+}
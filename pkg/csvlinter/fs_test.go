@@ -0,0 +1,48 @@
+package csvlinter
+
+import (
+	"bytes"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLintFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"exports/users.csv":         {Data: []byte("id,age\n1,30\n2,old\n")},
+		"exports/users.schema.json": {Data: []byte(`{"type":"object","properties":{"age":{"type":"integer"}}}`)},
+		"exports/plain.csv":         {Data: []byte("id\n1\n")},
+		"rules/strict.schema.json":  {Data: []byte(`{"type":"object","properties":{"id":{"type":"integer","minimum":2}}}`)},
+	}
+
+	var buf bytes.Buffer
+	results, err := LintFS(fsys, "exports/users.csv", Options{Format: "json"}, &buf)
+	if err != nil {
+		t.Fatalf("LintFS failed: %v", err)
+	}
+	if !results.SchemaUsed || len(results.Errors) != 1 || results.Errors[0].Line != 3 {
+		t.Errorf("Expected the schema next to the file in fsys to apply, got %+v", results)
+	}
+
+	results, err = LintFS(fsys, "exports/plain.csv", Options{AdditionalSchemas: []string{"rules/strict.schema.json"}}, &buf)
+	if err != nil {
+		t.Fatalf("LintFS failed: %v", err)
+	}
+	if len(results.Errors) != 1 {
+		t.Errorf("Expected the additional schema to be read from fsys, got %+v", results.Errors)
+	}
+
+	if _, err := LintFS(fsys, "exports/missing.csv", Options{}, &buf); CodeOf(err) != CodeFileNotFound {
+		t.Errorf("Expected FILE_NOT_FOUND, got %v", err)
+	}
+	if _, err := LintFS(fsys, "exports/plain.csv", Options{SchemaPath: "nope.json"}, &buf); CodeOf(err) != CodeSchemaNotFound {
+		t.Errorf("Expected SCHEMA_NOT_FOUND, got %v", err)
+	}
+
+	batch, err := LintFilesFS(fsys, []string{"exports/users.csv", "exports/plain.csv", "exports/gone.csv"}, Options{Format: "json"}, &buf)
+	if err != nil {
+		t.Fatalf("LintFilesFS failed: %v", err)
+	}
+	if batch.TotalFiles != 3 || batch.InvalidFiles != 2 {
+		t.Errorf("Expected users.csv and gone.csv to be invalid, got %+v", batch)
+	}
+}
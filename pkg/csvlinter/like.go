@@ -0,0 +1,83 @@
+package csvlinter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+
+	"github.com/csvlinter/csvlinter/internal/parser"
+	"github.com/csvlinter/csvlinter/internal/schema"
+)
+
+// likeContract is what Options.Like holds files to, as read from the known-good file: its
+// delimiter and header, checked by checks.Like, and the rough types of its columns.
+type likeContract struct {
+	Delimiter string   `json:"delimiter"`
+	Headers   []string `json:"headers"`
+
+	schema *schema.Validator // Types and formats inferred from the file's rows
+}
+
+// loadLike reads the contract of opts.Like, or returns nil when it is not set. The file's
+// delimiter is that of its sep= line, else the one its header is split by, else that of
+// its extension; its types are inferred from up to InferSchemaMaxRows rows, and checked
+// on non-empty values only.
+func loadLike(opts Options) (*likeContract, error) {
+	if opts.Like == "" {
+		return nil, nil
+	}
+	var f io.ReadCloser
+	var err error
+	if opts.FS == nil {
+		f, err = os.Open(opts.Like)
+	} else {
+		f, err = opts.FS.Open(opts.Like)
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, opErrorf(CodeSchemaNotFound, "Like file '%s' does not exist", opts.Like)
+	}
+	if err != nil {
+		return nil, newOpError(CodeSchemaInvalid, fmt.Errorf("failed to read like file: %w", err))
+	}
+	defer f.Close()
+
+	_, delimiter, _, r, err := parser.ReadSepPreamble(f)
+	if err != nil {
+		return nil, newOpError(CodeSchemaInvalid, fmt.Errorf("failed to read like file: %w", err))
+	}
+	if delimiter == "" {
+		delimiter, r = parser.SniffDelimiter(r)
+	}
+	if delimiter == "" {
+		delimiter = parser.DelimiterFor(opts.Like)
+	}
+	dialect, err := parser.NewDialect(delimiter, opts.Quote, opts.Escape)
+	if err != nil {
+		return nil, opErrorf(CodeInvalidArgument, "Invalid dialect: %v", err)
+	}
+	maxRows := opts.InferSchemaMaxRows
+	if maxRows == 0 {
+		maxRows = DefaultInferSchemaMaxRows
+	}
+	headers, sample, _, err := parser.ReadSampleWithDialect(r, dialect, maxRows)
+	if err != nil {
+		return nil, newOpError(CodeSchemaInvalid, fmt.Errorf("like file %s: %w", opts.Like, err))
+	}
+	schemaJSON, err := schema.InferTypes(headers, sample)
+	if err != nil {
+		return nil, newOpError(CodeSchemaInvalid, fmt.Errorf("like file %s: %w", opts.Like, err))
+	}
+	v, err := schema.NewValidatorFromReader(bytes.NewReader(schemaJSON), schemaOptions(opts)...)
+	if err != nil {
+		return nil, newOpError(CodeInternal, err)
+	}
+	// Empty cells are not type-checked: a rough type says nothing about them
+	return &likeContract{
+		Delimiter: delimiter,
+		Headers:   headers,
+		schema:    v.WithEmptyValues(schema.EmptyMissing, nil),
+	}, nil
+}
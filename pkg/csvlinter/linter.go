@@ -2,13 +2,32 @@ package csvlinter
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/csvlinter/csvlinter/internal/cache"
+	"github.com/csvlinter/csvlinter/internal/checks"
+	"github.com/csvlinter/csvlinter/internal/formats"
+	"github.com/csvlinter/csvlinter/internal/i18n"
+	"github.com/csvlinter/csvlinter/internal/manifest"
 	"github.com/csvlinter/csvlinter/internal/parser"
+	"github.com/csvlinter/csvlinter/internal/profile"
+	"github.com/csvlinter/csvlinter/internal/redact"
 	"github.com/csvlinter/csvlinter/internal/reporter"
+	"github.com/csvlinter/csvlinter/internal/rules"
 	"github.com/csvlinter/csvlinter/internal/schema"
+	"github.com/csvlinter/csvlinter/internal/transform"
 	"github.com/csvlinter/csvlinter/internal/validator"
 )
 
@@ -16,112 +35,1490 @@ import (
 // inference.
 const DefaultInferSchemaMaxRows = 100
 
+// DefaultChunkSize is the number of findings per part of a report written to OutputDir.
+const DefaultChunkSize = 50000
+
+// DefaultMaxValueLength is the number of characters of a value the CLI keeps in reports.
+const DefaultMaxValueLength = 256
+
 // Options configures CSV validation and output for LintAdvanced.
-// Delimiter defaults to "," and Format to "pretty" when empty.
+// Delimiter defaults by Filename extension (tab for .tsv, "," otherwise) and Format to
+// "pretty" when empty.
 type Options struct {
-	Delimiter          string    // Field delimiter (e.g., ",", ";", "\t")
-	FailFast           bool      // Stop after first error
-	Format             string    // Output format: "pretty" or "json"
-	Output             string    // Output file path (if empty, write to writer)
-	Filename           string    // Logical filename for schema resolution (used if reading from stream)
-	SchemaPath         string    // Path to JSON schema file (optional)
-	SchemaReader       io.Reader // Optional: read JSON schema from this stream; takes precedence over SchemaPath when set
-	InferSchema        bool      // If true and no schema provided, infer schema from data
-	InferSchemaOutput  string    // If non-empty, write inferred schema to this path
-	InferSchemaMaxRows int       // Head rows to sample for type inference (0 = DefaultInferSchemaMaxRows); only these rows are buffered
+	Delimiter            string              // Field delimiter (e.g., ",", ";", "\t"), or "whitespace" for fields separated by runs of spaces and tabs
+	Quote                string              // Quote character, e.g. "'", or "none" for files that never quote; '"' when empty
+	Escape               string              // Character escaping delimiters, quotes and line breaks in fields, e.g. "\\" for producers that escape rather than quote; none when empty
+	FailFast             bool                // Stop after first error
+	Format               string              // Output format: "pretty", "json", "compact" or "sarif"
+	ExtraFormats         []string            // Additional formats always written to writer (e.g. pretty next to a JSON Output file)
+	Output               string              // Output file path (if empty, write to writer), or a template naming a report file per validated file, e.g. "reports/{{.Name}}.json", with .Path, .Dir, .Base, .Name, .Ext and .Format
+	Tee                  bool                // When Output or OutputDir is set, also write Format to writer
+	OutputDir            string              // Write the JSON report to this directory as parts of ChunkSize findings plus index.json, instead of Output
+	ChunkSize            int                 // Findings per part with OutputDir (0 = DefaultChunkSize)
+	Theme                Theme               // Colors and symbols of pretty and compact output (zero value = classic)
+	Lang                 string              // Language of pretty output: "en" ("", the default), "de", "fr" or "ja"; json and compact stay in English
+	Filename             string              // Logical filename for schema resolution (used if reading from stream)
+	SchemaPath           string              // Path to JSON schema file (optional)
+	SchemaReader         io.Reader           // Optional: read JSON schema from this stream; takes precedence over SchemaPath when set
+	AdditionalSchemas    []string            // More schema files every row is also validated against; errors then name their schema
+	ProducerSchema       string              // With ConsumerSchema: validate against both sides of a data contract and report which rejects the file
+	ConsumerSchema       string              // Schema of the consuming side; see ProducerSchema
+	SchemaRoot           string              // Directory that $refs in schemas must stay in, and that SchemaReader's relative $refs resolve against (in FS when set)
+	SchemaLookup         []string            // Patterns such as "schemas/{name}.json" tried, from Filename's directory up, before <name>.schema.json when resolving its schema
+	RootMarkers          []string            // Files or directories, such as "go.mod", marking the project root where schema resolution stops; ".git" and "package.json" when empty
+	SchemaSymlinks       string              // Which path of a Filename under a symlinked directory schema resolution walks up: "both" ("", the default, the path as given then the real one), "link" or "real"
+	SchemaIgnoreCase     bool                // Resolve schema files whose names differ in case only, as on case-insensitive filesystems
+	FileSchema           string              // Schema for the whole file as one JSON array of row objects (minItems, uniqueItems, contains, ...); keeps every row in memory
+	Coercers             []Coercer           // Custom conversions of values to typed values before schema validation, tried in order
+	Formats              []string            // Registered formats, such as "iban", asserted by the "format" keyword of schemas
+	EmptyValues          string              // How empty cells reach schemas: "string" ("", the default), "missing" (left out) or "null"
+	EmptyColumns         map[string]string   // Column -> EmptyValues mode for that column
+	ColumnTypes          map[string]string   // Column -> type its values are converted to before schema validation, instead of the schema's: "int", "float", "bool" or "string"
+	QuotedEmpty          bool                // Keep quoted empty fields ("") as empty strings, so EmptyValues only applies to fields with nothing between the delimiters
+	ShortRows            string              // Rows with fewer fields than the header: "error" ("", the default), "warn" (pad and report STR005) or "pad" with empty values
+	LongRows             string              // Rows with more fields than the header: "error" ("", the default), "warn" (ignore the extra values and report STR006) or "ignore"
+	RequireData          bool                // Report a file with a header but no data rows (STR009) as an error; it is a warning otherwise
+	Chunked              bool                // The file concatenates chunks that each start with the header: repeated headers are skipped, reordered ones (STR011) and empty chunks (STR009) reported
+	Quoting              string              // Warn about fields quoted against a policy: "minimal", "all", "nonnumeric" or "consistent" per column ("" = not checked)
+	Fixes                bool                // Attach machine-applyable fixes to findings whose correction is known (whitespace, case, quoting, field counts); always on for the sarif format
+	SepLine              string              // Excel "sep=;" first line, always skipped and used as the delimiter unless Delimiter is set: "allow" ("", the default), "warn" or "error"
+	MaxEncodingErrors    int                 // Report up to this many rows with invalid UTF-8, skipping them, before stopping (0 = stop at the first)
+	DiscriminatorColumn  string              // Column whose value selects a schema from DiscriminatorSchemas for each row
+	DiscriminatorSchemas map[string]string   // Value of DiscriminatorColumn -> schema file also validated for those rows
+	GroupRules           []GroupRule         // Assertions on groups of rows sharing a key column
+	SortRules            []SortRule          // Columns that must be in order
+	ExpressionRules      []ExpressionRule    // Assertions over computed column values, checked on every row
+	OutlierRules         []OutlierRule       // Numeric columns whose values far from the rest are reported as warnings
+	GeoRules             []GeoRule           // Coordinate and geometry columns checked on every row
+	UnitRules            []UnitRule          // Columns of numbers with a currency or unit, e.g. "USD 10.50", checked on every row
+	ScriptRules          []ScriptRule        // Free-text columns that must be in given Unicode scripts or free of emoji
+	RequiredIfRules      []RequiredIfRule    // Columns required on rows matching a condition, e.g. a tracking number on shipped orders
+	UniqueRules          []UniqueRule        // Keys, or whole rows, that must not repeat; streams with bounded memory where FileSchema's uniqueItems keeps every row
+	CompletenessRules    []CompletenessRule  // Columns that must be non-empty in a share of the rows, e.g. email in 99% of them
+	Profile              string              // Baseline profile file (see the profile command); columns that drift from it are reported as warnings
+	Drift                DriftThresholds     // Deviations from Profile that are not drift (zero fields = defaults)
+	Envelope             Envelope            // Header and trailer records around the data (zero value = none)
+	Target               string              // Database the file must load into: "postgres", "bigquery" or "snowflake" ("" = none)
+	Transforms           map[string][]string // Column -> steps rewriting its values before validation, e.g. {"amount": {"trim", "strip_currency"}}
+	Manifest             string              // LintFiles only: manifest listing the batch's files, row counts and SHA-256 hashes
+	FileOptions          FileOptionsFunc     // LintFiles only: options of each file, e.g. from per-directory config (nil = these options)
+	Open                 OpenFunc            // LintFiles only: reads each file instead of opening path, e.g. its version staged in git; the path still names it and finds its schema (nil = open path)
+	Jobs                 int                 // LintFiles only: files validated at once (0 or 1 = one after another); Coercers must then be safe for concurrent use
+	StreamResults        bool                // LintFiles only: write each file's report to writer as soon as it is validated, then the summary (JSON as JSON Lines; not sarif)
+	Fingerprint          bool                // Include the input's SHA-256 and a schema-aware content fingerprint in Results
+	SampleRows           int                 // Include up to this many example rows per failing rule in Results.Samples (0 = none)
+	SampleColumns        []string            // Columns kept in samples; every column when empty
+	IncludeColumns       []string            // Columns, such as an ID, whose values in the row are added to each finding about a row as Finding.Columns; "*" for every column
+	Seed                 int64               // Pick sample rows at random among all failing rows with this seed, recorded in Results.Run (0 = the first rows)
+	Breakdown            bool                // Count errors per column and per rule in Results.Breakdown
+	DedupeErrors         bool                // Roll findings with the same rule, field and message into one with a count (see Results.Dedupe)
+	Timings              bool                // Measure the time spent reading, parsing and validating in Results.Timings; Report is set once reports are written
+	ContextRows          int                 // Show this many rows before and after each failing row in pretty output (0 = none)
+	TimeBudget           time.Duration       // Validate rows for at most this long, then report partial Results.Coverage (0 = no limit)
+	Interrupt            <-chan struct{}     // Optional: once closed, stop validating and report the rows validated so far, with Results.Partial set
+	FS                   fs.FS               // If set, Filename, LintFiles paths and schema paths name files in FS, and schemas are resolved in FS
+	InferSchema          bool                // If true and no schema provided, infer schema from data
+	InferSchemaOutput    string              // If non-empty, write inferred schema to this path
+	InferSchemaMaxRows   int                 // Head rows to sample for type inference (0 = DefaultInferSchemaMaxRows); only these rows are buffered
+	Like                 string              // Known-good CSV file: files must have its header, in order, and delimiter, and values of its columns' inferred types
+	CacheDir             string              // If non-empty, reuse and store results for regular files (*os.File input) in this directory
+	RedactValues         string              // "mask" or "hash" to hide cell values in results and reports ("" = off)
+	RedactColumns        []string            // Limit RedactValues to these columns (all columns when empty)
+	MaxValueLength       int                 // Truncate reported values to this many characters (0 = no limit), e.g. DefaultMaxValueLength
+	MessageTemplates     map[string]string   // Rule ID -> text/template replacing the message of its findings, with .Message, .Field, .Value, .Expected, .Actual and .Line
+}
+
+// Coercer converts a column's string value into the typed value validated against the
+// schema, e.g. "€1,234.00" into 1234. It returns ok false to leave the value to the next
+// coercer and finally to the built-in conversion of integers and numbers. Findings still
+// show the value as it is in the file.
+type Coercer = schema.Coercer
+
+// CoercerFunc adapts a function to a Coercer.
+type CoercerFunc = schema.CoercerFunc
+
+// RegisterFormat adds a format validator, or replaces the one with the same name, for
+// runs that list it in Options.Formats. The built-in formats are uuid, e164-phone, iban,
+// country-code, url, credit-card, isbn, ean and vin.
+func RegisterFormat(name string, fn func(value string) bool) {
+	formats.Register(name, fn)
+}
+
+// GroupRule asserts, for every group of rows sharing the value of the By column, that
+// the number of its rows matching Where is between Min and Max. For example, By
+// "order_id", Where {"line_type": "header"}, Min 1, Max 1 requires exactly one header row
+// per order.
+type GroupRule struct {
+	By    string            `json:"by"`
+	Where map[string]string `json:"where,omitempty"` // Column -> value rows must have to be counted; all rows when empty
+	Min   int               `json:"min"`
+	Max   int               `json:"max"` // -1 for no upper bound
+}
+
+// SortRule asserts that Column is in ascending order, or descending when Descending is
+// set. With Unique, consecutive values must also differ. Numbers and timestamps compare by
+// value, anything else as strings.
+type SortRule struct {
+	Column     string `json:"column"`
+	Descending bool   `json:"descending,omitempty"`
+	Unique     bool   `json:"unique,omitempty"`
+}
+
+// ExpressionRule asserts, for every row, a comparison between arithmetic expressions over
+// its columns, e.g. "total == quantity * unit_price". Sides within Tolerance of each other
+// count as equal. Rows with an empty or non-numeric value in a referenced column are
+// skipped.
+type ExpressionRule struct {
+	Assert    string  `json:"assert"`
+	Tolerance float64 `json:"tolerance,omitempty"`
+}
+
+// OutlierRule reports values of numeric columns far from the rest of their column as
+// warnings, e.g. a weight in grams among kilograms that still passes schema bounds. Method
+// "iqr" (the default) flags values more than Threshold interquartile ranges (default 1.5)
+// outside the middle half; "zscore" flags values more than Threshold standard deviations
+// (default 3) from the mean. Without Columns, every column whose values are all numbers
+// is checked. Columns with fewer than 10 numbers are skipped.
+type OutlierRule struct {
+	Method    string   `json:"method,omitempty"`
+	Threshold float64  `json:"threshold,omitempty"`
+	Columns   []string `json:"columns,omitempty"`
+}
+
+// GeoRule checks geographic columns on every row: Latitude within ±90 and Longitude
+// within ±180, both set or both empty, and WKT and GeoJSON columns holding valid
+// geometries. Set Latitude and Longitude together, or leave both empty.
+type GeoRule struct {
+	Latitude  string `json:"latitude,omitempty"`
+	Longitude string `json:"longitude,omitempty"`
+	WKT       string `json:"wkt,omitempty"`
+	GeoJSON   string `json:"geojson,omitempty"`
+}
+
+// UnitRule checks a column of numbers written with a currency or unit before or after
+// them, such as "USD 10.50", "€5" or "10.5 kg". Every non-empty value needs exactly one
+// unit, which must be one of Units (any unit when empty), and a number within Minimum and
+// Maximum when they are set.
+type UnitRule struct {
+	Column  string   `json:"column"`
+	Units   []string `json:"units,omitempty"`
+	Minimum *float64 `json:"minimum,omitempty"`
+	Maximum *float64 `json:"maximum,omitempty"`
+}
+
+// ScriptRule checks that the text of Columns only uses characters of Scripts, Unicode
+// script names such as "Latin" or "Cyrillic", besides digits, punctuation and other
+// characters common to all scripts. With NoEmoji, emoji are rejected too. The check looks
+// at scripts, not languages: English and German text are both Latin.
+type ScriptRule struct {
+	Columns []string `json:"columns"`
+	Scripts []string `json:"scripts,omitempty"`
+	NoEmoji bool     `json:"no_emoji,omitempty"`
+}
+
+// RequiredIfRule requires the Require columns to be non-empty on rows whose columns equal
+// the values in When, e.g. When {"status": "shipped"}, Require {"tracking_number",
+// "carrier"}. Each empty column is reported with its own finding.
+type RequiredIfRule struct {
+	When    map[string]string `json:"when"`
+	Require []string          `json:"require"`
+}
+
+// UniqueRule requires the values of Columns, or whole rows when Columns is empty, to
+// differ between rows. Keys are kept as hashes; beyond MemoryKeys of them (0 for about
+// two million, some 100 MB) they are spilled to sorted files in the temporary directory
+// and merged at the end of the input, so files larger than memory can be checked. Method
+// "bloom" instead keeps a Bloom filter sized for ExpectedKeys (0 for 10 million, some
+// 12 MB), writes every key to disk and verifies the rows the filter flags at the end,
+// for hundreds of millions of rows whose sorted runs would take long to merge.
+type UniqueRule struct {
+	Columns      []string `json:"columns,omitempty"`
+	Method       string   `json:"method,omitempty"` // "sort" (default) or "bloom"
+	MemoryKeys   int      `json:"memory_keys,omitempty"`
+	ExpectedKeys int      `json:"expected_keys,omitempty"`
+}
+
+// CompletenessRule requires Column to be non-empty in at least MinPercent percent of the
+// rows. A column below it is reported once for the file, with the measured percentage.
+type CompletenessRule struct {
+	Column     string  `json:"column"`
+	MinPercent float64 `json:"min_percent"`
+}
+
+// newUnique returns the check of a unique rule.
+func newUnique(r UniqueRule) (*checks.Unique, error) {
+	switch r.Method {
+	case "", checks.UniqueSort:
+		return checks.NewUnique(r.Columns, r.MemoryKeys, "")
+	case checks.UniqueBloom:
+		return checks.NewUniqueBloom(r.Columns, r.ExpectedKeys, "")
+	}
+	return nil, fmt.Errorf("unknown method '%s' (use %s or %s)", r.Method, checks.UniqueSort, checks.UniqueBloom)
+}
+
+// keyColumns returns the columns of the rows' key, whose value is attached to findings:
+// the x-primaryKey of the first schema with one, or else the columns of the first unique
+// rule that has some.
+func keyColumns(schemas []validator.Schema, unique []UniqueRule) []string {
+	for _, s := range schemas {
+		if key := s.Validator.PrimaryKey(); len(key) > 0 {
+			return key
+		}
+	}
+	for _, r := range unique {
+		if len(r.Columns) > 0 {
+			return r.Columns
+		}
+	}
+	return nil
+}
+
+// DriftThresholds are the largest deviations of a column from the baseline Profile that
+// are not reported. Zero fields use the defaults: 0.05 (5 percentage points) for the null
+// rate, and 0.2 (20%) for the mean and 0.5 (50%) for the distinct count, both relative to
+// the baseline.
+type DriftThresholds struct {
+	NullRate float64 `json:"null_rate,omitempty"`
+	Mean     float64 `json:"mean,omitempty"`
+	Distinct float64 `json:"distinct,omitempty"`
+}
+
+// Theme customizes the colors and symbols of pretty and compact output. Name picks a
+// built-in theme: "classic" (the default), "minimal" (no colors or symbols) or
+// "emoji-free" (ASCII only); the other fields override it when set. Colors are names
+// such as "red" or "bold red", "none", or ANSI SGR parameters such as "1;31"; they only
+// apply on terminals.
+type Theme struct {
+	Name    string
+	Error   string // Color of errors and the invalid status
+	Warning string // Color of warnings
+	Success string // Color of the valid status
+	Heading string // Color of report titles
+	Pass    string // Symbol before the valid status, e.g. "OK"
+	Fail    string // Symbol before the invalid status
+	Bar     string // Unit of the error breakdown's bars
+}
+
+// reporterTheme resolves t to the reporter's colors and symbols.
+func reporterTheme(t Theme) (reporter.Theme, error) {
+	out, err := reporter.LookupTheme(t.Name)
+	if err != nil {
+		return out, err
+	}
+	for _, c := range []struct {
+		dst   *string
+		color string
+	}{{&out.Error, t.Error}, {&out.Warning, t.Warning}, {&out.Success, t.Success}, {&out.Heading, t.Heading}} {
+		if c.color == "" {
+			continue
+		}
+		if *c.dst, err = reporter.ParseColor(c.color); err != nil {
+			return out, err
+		}
+	}
+	for _, s := range []struct {
+		dst    *string
+		symbol string
+	}{{&out.Pass, t.Pass}, {&out.Fail, t.Fail}, {&out.Bar, t.Bar}} {
+		if s.symbol != "" {
+			*s.dst = s.symbol
+		}
+	}
+	return out, nil
+}
+
+// Envelope describes records that wrap the data in some feeds: a header record before the
+// column header and a trailer record after the last data row, recognized by their first
+// field. Both are excluded from data validation; when set, they must be present. The
+// trailer can declare the number of data rows and a control total (the sum of a column),
+// which are checked against the data.
+type Envelope struct {
+	HeaderPrefix   string `json:"header_prefix,omitempty"`   // First field of the header record, e.g. "HDR"
+	TrailerPrefix  string `json:"trailer_prefix,omitempty"`  // First field of the trailer record, e.g. "TRAILER"
+	CountField     int    `json:"count_field,omitempty"`     // 1-based trailer field with the data row count (0 = unchecked)
+	ChecksumField  int    `json:"checksum_field,omitempty"`  // 1-based trailer field with the sum of ChecksumColumn (0 = unchecked)
+	ChecksumColumn string `json:"checksum_column,omitempty"` // Column summed for ChecksumField
+}
+
+func (e Envelope) enabled() bool {
+	return e.HeaderPrefix != "" || e.TrailerPrefix != ""
+}
+
+// cacheOptions are the Options that change results and therefore the cache key.
+type cacheOptions struct {
+	Delimiter          string              `json:"delimiter"`
+	Quote              string              `json:"quote,omitempty"`
+	Escape             string              `json:"escape,omitempty"`
+	FailFast           bool                `json:"fail_fast"`
+	InferSchema        bool                `json:"infer_schema"`
+	InferSchemaMaxRows int                 `json:"infer_schema_max_rows"`
+	Discriminator      string              `json:"discriminator,omitempty"`
+	GroupRules         []GroupRule         `json:"group_rules,omitempty"`
+	SortRules          []SortRule          `json:"sort_rules,omitempty"`
+	ExpressionRules    []ExpressionRule    `json:"expression_rules,omitempty"`
+	OutlierRules       []OutlierRule       `json:"outlier_rules,omitempty"`
+	GeoRules           []GeoRule           `json:"geo_rules,omitempty"`
+	UnitRules          []UnitRule          `json:"unit_rules,omitempty"`
+	ScriptRules        []ScriptRule        `json:"script_rules,omitempty"`
+	RequiredIfRules    []RequiredIfRule    `json:"required_if_rules,omitempty"`
+	UniqueRules        []UniqueRule        `json:"unique_rules,omitempty"`
+	CompletenessRules  []CompletenessRule  `json:"completeness_rules,omitempty"`
+	Profile            *profile.Profile    `json:"profile,omitempty"`
+	Drift              *DriftThresholds    `json:"drift,omitempty"`
+	Envelope           Envelope            `json:"envelope"`
+	Target             string              `json:"target,omitempty"`
+	Transforms         map[string][]string `json:"transforms,omitempty"`
+	SampleRows         int                 `json:"sample_rows,omitempty"`
+	SampleColumns      []string            `json:"sample_columns,omitempty"`
+	IncludeColumns     []string            `json:"include_columns,omitempty"`
+	Like               *likeContract       `json:"like,omitempty"`
+	Fingerprint        bool                `json:"fingerprint,omitempty"`
+	Formats            []string            `json:"formats,omitempty"`
+	EmptyValues        string              `json:"empty_values,omitempty"`
+	EmptyColumns       map[string]string   `json:"empty_columns,omitempty"`
+	ColumnTypes        map[string]string   `json:"column_types,omitempty"`
+	QuotedEmpty        bool                `json:"quoted_empty,omitempty"`
+	ShortRows          string              `json:"short_rows,omitempty"`
+	LongRows           string              `json:"long_rows,omitempty"`
+	RequireData        bool                `json:"require_data,omitempty"`
+	Chunked            bool                `json:"chunked,omitempty"`
+	Quoting            string              `json:"quoting,omitempty"`
+	Fixes              bool                `json:"fixes,omitempty"`
+	SepLine            string              `json:"sep_line,omitempty"` // Mode and the line found, which is not hashed with the data
+	MaxEncodingErrors  int                 `json:"max_encoding_errors,omitempty"`
+	Seed               int64               `json:"seed,omitempty"`
+}
+
+// runOptions are the options recorded in Results.Run: those that change results, with
+// schemas by label and hash and the profile by path, and those that shape the report.
+type runOptions struct {
+	cacheOptions
+	Schemas        []string      `json:"schemas,omitempty"` // Label=hash of each schema, as in the cache key
+	Profile        string        `json:"profile,omitempty"`
+	TimeBudget     time.Duration `json:"time_budget,omitempty"`
+	ContextRows    int           `json:"context_rows,omitempty"`
+	Breakdown      bool          `json:"breakdown,omitempty"`
+	DedupeErrors   bool          `json:"dedupe_errors,omitempty"`
+	RedactValues   string        `json:"redact_values,omitempty"`
+	RedactColumns  []string      `json:"redact_columns,omitempty"`
+	MaxValueLength int           `json:"max_value_length,omitempty"`
 }
 
 // LintAdvanced validates a CSV stream with full control over schema, format, and output.
+// Failures that prevent validation are returned as *OpError; use CodeOf to classify them.
 func LintAdvanced(r io.Reader, opts Options, writer io.Writer) (*validator.Results, error) {
+	format, err := checkFormats(opts)
+	if err != nil {
+		return nil, err
+	}
+	results, err := lint(context.Background(), r, opts, nil)
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	if err := newReporter(format, opts).Report(results, writer); err != nil {
+		return nil, newOpError(CodeOutputFailed, err)
+	}
+	if results.Timings != nil {
+		results.Timings.Report = validator.Milliseconds(time.Since(start))
+	}
+	return results, nil
+}
+
+// LintFiles validates each file in paths and writes one combined report. Options apply to
+// every file, except that Filename is set per file so schemas and delimiters are resolved
+// for each one. With a Manifest, the files it lists are validated too and checked for
+// presence, hash and row count. Files that cannot be read or parsed are recorded as invalid with a "file"
+// error; configuration failures (e.g. an invalid schema) abort the run with an *OpError.
+// Jobs validates files concurrently; the report lists them by name all the same, unless
+// StreamResults writes each one as it finishes.
+// Once Interrupt is closed, files not started yet are left out and the batch is Partial.
+func LintFiles(paths []string, opts Options, writer io.Writer) (*validator.Batch, error) {
+	format, err := checkFormats(opts)
+	if err != nil {
+		return nil, err
+	}
+	var m *manifest.Manifest
+	if opts.Manifest != "" && opts.FS != nil {
+		return nil, opErrorf(CodeInvalidArgument, "Manifest cannot be used with FS")
+	}
+	if opts.Manifest != "" {
+		if m, err = manifest.Load(opts.Manifest); err != nil {
+			return nil, newOpError(CodeManifestInvalid, err)
+		}
+		paths = appendNew(paths, m.Present())
+	}
+	if opts.InferSchemaOutput != "" && len(paths) > 1 {
+		return nil, opErrorf(CodeInvalidArgument, "InferSchemaOutput cannot be used with multiple files")
+	}
+	templates, err := validator.ParseTemplates(opts.MessageTemplates)
+	if err != nil {
+		return nil, newOpError(CodeInvalidArgument, err)
+	}
+
+	// A schema stream can only be read once; keep it for every file
+	var schemaJSON []byte
+	if opts.SchemaReader != nil {
+		if schemaJSON, err = io.ReadAll(opts.SchemaReader); err != nil {
+			return nil, newOpError(CodeSchemaInvalid, fmt.Errorf("failed to read schema: %w", err))
+		}
+	}
+
+	// Options are resolved in order, so FileOptions need not be safe for concurrent use
+	fileOpts := make([]Options, len(paths))
+	for i, path := range paths {
+		fileOpts[i] = opts
+		if opts.FileOptions != nil {
+			if fileOpts[i], err = opts.FileOptions(path, opts); err != nil {
+				return nil, err
+			}
+		}
+	}
+	var stream *reporter.Stream
+	if opts.StreamResults {
+		if stream, err = newReporter(format, opts).Stream(writer); err != nil {
+			return nil, newOpError(CodeInvalidArgument, err)
+		}
+	}
+	// Validation findings went through the templates already; the run's own have not
+	finish := func(results *validator.Results) error {
+		for _, list := range [][]validator.Finding{results.Errors, results.Warnings} {
+			for i := range list {
+				if list[i].Type == "file" || list[i].Type == "manifest" {
+					templates.Apply(&list[i])
+				}
+			}
+		}
+		if stream != nil {
+			if err := stream.Add(results); err != nil {
+				return newOpError(CodeOutputFailed, err)
+			}
+		}
+		return nil
+	}
+
+	start := time.Now()
+	files := make([]*validator.Results, len(paths))
+	err = forEach(len(paths), opts.Jobs, func(i int) error {
+		if validator.Interrupted(opts.Interrupt) {
+			// Files not started yet are left out of the partial report
+			return nil
+		}
+		if schemaJSON != nil && fileOpts[i].SchemaReader != nil {
+			fileOpts[i].SchemaReader = bytes.NewReader(schemaJSON)
+		}
+		results, err := lintFile(paths[i], fileOpts[i])
+		if err != nil {
+			return err
+		}
+		if m != nil && !results.Partial {
+			// A partial file's row count and hash say nothing of the whole file
+			m.Check([]*validator.Results{results})
+		}
+		files[i] = results
+		return finish(results)
+	})
+	if err != nil {
+		return nil, err
+	}
+	skipped := slices.Contains(files, nil)
+	files = slices.DeleteFunc(files, func(r *validator.Results) bool { return r == nil })
+	if m != nil {
+		for _, missing := range m.Missing() {
+			if err := finish(missing); err != nil {
+				return nil, err
+			}
+			files = append(files, missing)
+		}
+	}
+
+	batch := validator.NewBatch(files, time.Since(start))
+	if skipped {
+		batch.Partial = true
+	}
+	start = time.Now()
+	if stream != nil {
+		err = stream.Close(batch)
+	} else {
+		err = newReporter(format, opts).ReportBatch(batch, writer)
+	}
+	if err != nil {
+		return nil, newOpError(CodeOutputFailed, err)
+	}
+	if batch.Timings != nil {
+		batch.Timings.Report = validator.Milliseconds(time.Since(start))
+	}
+	return batch, nil
+}
+
+// forEach calls fn with each index below n, on up to jobs goroutines at once (one when
+// jobs < 1), and returns the first error, after which no more indexes are started.
+func forEach(n, jobs int, fn func(i int) error) error {
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		next  int
+		first error
+	)
+	for w := 0; w < min(max(jobs, 1), n); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				mu.Lock()
+				if first != nil || next == n {
+					mu.Unlock()
+					return
+				}
+				i := next
+				next++
+				mu.Unlock()
+				if err := fn(i); err != nil {
+					mu.Lock()
+					if first == nil {
+						first = err
+					}
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return first
+}
+
+// OpenFunc reads the file at path of a LintFiles run, in place of opening it.
+type OpenFunc func(path string) (io.ReadCloser, error)
+
+// FileOptionsFunc returns the options a file of a LintFiles run is validated with, given
+// the run's options.
+type FileOptionsFunc func(path string, opts Options) (Options, error)
+
+// appendNew appends the paths in extra that do not name a file already in paths.
+func appendNew(paths, extra []string) []string {
+	seen := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		seen[absPath(p)] = true
+	}
+	for _, p := range extra {
+		if !seen[absPath(p)] {
+			seen[absPath(p)] = true
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+func absPath(p string) string {
+	if abs, err := filepath.Abs(p); err == nil {
+		return abs
+	}
+	return filepath.Clean(p)
+}
+
+// lintFile validates a single file of a LintFiles run.
+func lintFile(path string, opts Options) (*validator.Results, error) {
+	opts.Filename = path
+	var f io.ReadCloser
+	var err error
+	if opts.Open != nil {
+		f, err = opts.Open(path)
+	} else {
+		f, err = openInput(opts.FS, path)
+	}
+	if err != nil {
+		code := CodeFileUnreadable
+		if errors.Is(err, fs.ErrNotExist) {
+			code = CodeFileNotFound
+		}
+		return fileFailure(path, &OpError{Code: code, Message: fmt.Sprintf("Cannot open file: %v", err), Err: err}), nil
+	}
+	defer f.Close()
+
+	results, err := lint(context.Background(), f, opts, nil)
+	if err != nil {
+		switch CodeOf(err) {
+		case CodeEmptyInput, CodeInvalidInput, CodeFileUnreadable:
+			return fileFailure(path, err), nil
+		}
+		return nil, err
+	}
+	return results, nil
+}
+
+// wantsFixes reports whether findings get fixes: when asked for, and for SARIF reports,
+// which carry them.
+func wantsFixes(opts Options) bool {
+	return opts.Fixes || opts.Format == "sarif" || slices.Contains(opts.ExtraFormats, "sarif")
+}
+
+// sepFindings maps SepLine modes to how the validator reports a sep= line.
+var sepFindings = map[string]string{"": "", "allow": "", "warn": "warning", "error": "error"}
+
+// fileFailure records a per-file operational failure as an invalid result.
+func fileFailure(path string, err error) *validator.Results {
+	return &validator.Results{
+		File:     path,
+		Errors:   []validator.Finding{{Severity: validator.SeverityError, Location: validator.Location{Line: 1}, Message: err.Error(), Type: "file", RuleID: rules.FileUnreadable}},
+		Warnings: []validator.Finding{},
+		Valid:    false,
+	}
+}
+
+// checkFormats validates the requested formats and output destination and returns the
+// primary format.
+func checkFormats(opts Options) (string, error) {
+	format := opts.Format
+	if format == "" {
+		format = "pretty"
+	}
+	for _, f := range append([]string{format}, opts.ExtraFormats...) {
+		if !reporter.IsSupported(f) {
+			return "", opErrorf(CodeInvalidArgument, "Format must be %s", reporter.FormatList())
+		}
+	}
+	if opts.OutputDir != "" {
+		if opts.Output != "" {
+			return "", opErrorf(CodeInvalidArgument, "Output and OutputDir cannot be combined")
+		}
+		if format != "json" {
+			return "", opErrorf(CodeInvalidArgument, "OutputDir requires the json format, got %s", format)
+		}
+	}
+	if opts.ChunkSize < 0 {
+		return "", opErrorf(CodeInvalidArgument, "ChunkSize must not be negative, got %d", opts.ChunkSize)
+	}
+	if err := reporter.CheckPath(opts.Output); err != nil {
+		return "", newOpError(CodeInvalidArgument, err)
+	}
+	if _, err := reporterTheme(opts.Theme); err != nil {
+		return "", opErrorf(CodeInvalidArgument, "Invalid theme: %v", err)
+	}
+	if _, err := i18n.Lookup(opts.Lang); err != nil {
+		return "", opErrorf(CodeInvalidArgument, "Invalid language: %v", err)
+	}
+	return format, nil
+}
+
+// newReporter sends the primary format to Output or OutputDir (or the writer) and extra
+// formats to the writer.
+func newReporter(format string, opts Options) *reporter.Reporter {
+	chunkSize := opts.ChunkSize
+	if chunkSize == 0 {
+		chunkSize = DefaultChunkSize
+	}
+	destinations := []reporter.Destination{{Format: format, Path: opts.Output, Tee: opts.Tee, Dir: opts.OutputDir, ChunkSize: chunkSize}}
+	for _, f := range opts.ExtraFormats {
+		destinations = append(destinations, reporter.Destination{Format: f})
+	}
+	theme, _ := reporterTheme(opts.Theme)
+	catalog, _ := i18n.Lookup(opts.Lang)
+	return reporter.NewWithDestinations(destinations...).WithTheme(theme).WithCatalog(catalog)
+}
+
+// lint resolves the schema and validates r without reporting. When emit is set it gets
+// every finding, shaped like the final results, as soon as it is known; validation stops
+// with emit's error or once ctx is done.
+func lint(ctx context.Context, r io.Reader, opts Options, emit func(Finding) error) (*validator.Results, error) {
+	start := time.Now()
+
+	var redactor *redact.Redactor
+	if opts.RedactValues != "" {
+		var err error
+		if redactor, err = redact.New(opts.RedactValues, opts.RedactColumns); err != nil {
+			return nil, newOpError(CodeInvalidArgument, err)
+		}
+	}
+	templates, err := validator.ParseTemplates(opts.MessageTemplates)
+	if err != nil {
+		return nil, newOpError(CodeInvalidArgument, err)
+	}
+
 	// Determine name for reporting
 	name := opts.Filename
 	if name == "" {
 		name = "STDIN"
 	}
 
+	sepFinding, ok := sepFindings[opts.SepLine]
+	if !ok {
+		return nil, opErrorf(CodeInvalidArgument, "Invalid sep line mode '%s': use allow, warn or error", opts.SepLine)
+	}
+	if !slices.Contains([]string{"", schema.SymlinksBoth, schema.SymlinksLink, schema.SymlinksReal}, opts.SchemaSymlinks) {
+		return nil, opErrorf(CodeInvalidArgument, "Invalid schema symlinks mode '%s': use both, link or real", opts.SchemaSymlinks)
+	}
+	if !slices.Contains([]string{"", "error", "warn", "pad"}, opts.ShortRows) {
+		return nil, opErrorf(CodeInvalidArgument, "Invalid short rows mode '%s': use error, warn or pad", opts.ShortRows)
+	}
+	if !slices.Contains([]string{"", "error", "warn", "ignore"}, opts.LongRows) {
+		return nil, opErrorf(CodeInvalidArgument, "Invalid long rows mode '%s': use error, warn or ignore", opts.LongRows)
+	}
+	if opts.Quoting != "" {
+		if err := parser.CheckQuoting(opts.Quoting); err != nil {
+			return nil, opErrorf(CodeInvalidArgument, "Invalid quoting: %v", err)
+		}
+	}
+	if opts.MaxEncodingErrors < 0 {
+		return nil, opErrorf(CodeInvalidArgument, "Invalid max encoding errors %d: must not be negative", opts.MaxEncodingErrors)
+	}
+	if opts.MaxValueLength < 0 {
+		return nil, opErrorf(CodeInvalidArgument, "Invalid max value length %d: must not be negative", opts.MaxValueLength)
+	}
+	like, err := loadLike(opts)
+	if err != nil {
+		return nil, err
+	}
+	sepLine, sepDelimiter, preamble, r, err := parser.ReadSepPreamble(r)
+	if err != nil {
+		return nil, newOpError(CodeFileUnreadable, err)
+	}
+
 	delimiter := opts.Delimiter
 	if delimiter == "" {
-		delimiter = ","
+		delimiter = sepDelimiter
+	}
+	if delimiter == "" && like != nil {
+		delimiter = like.Delimiter
+	}
+	if delimiter == "" {
+		delimiter = parser.DelimiterFor(opts.Filename)
+	}
+	dialect, err := parser.NewDialect(delimiter, opts.Quote, opts.Escape)
+	if err != nil {
+		return nil, opErrorf(CodeInvalidArgument, "Invalid dialect: %v", err)
 	}
 
-	// Schema resolution logic: SchemaReader takes precedence over SchemaPath
-	var schemaValidator *schema.Validator
-	var schemaInferred bool
-	var err error
-	if opts.SchemaReader != nil {
-		schemaValidator, err = schema.NewValidatorFromReader(opts.SchemaReader)
-		if err != nil {
-			return nil, err
+	if _, err := formats.Lookup(opts.Formats); err != nil {
+		return nil, opErrorf(CodeInvalidArgument, "Invalid format: %v", err)
+	}
+	if err := schema.CheckEmptyMode(opts.EmptyValues); err != nil {
+		return nil, opErrorf(CodeInvalidArgument, "Invalid empty values: %v", err)
+	}
+	for column, mode := range opts.EmptyColumns {
+		if err := schema.CheckEmptyMode(mode); err != nil {
+			return nil, opErrorf(CodeInvalidArgument, "Invalid empty values of column '%s': %v", column, err)
 		}
-	} else {
-		schemaPath := opts.SchemaPath
-		if schemaPath != "" {
-			if _, err := os.Stat(schemaPath); os.IsNotExist(err) {
-				return nil, fmt.Errorf("Schema file '%s' does not exist", schemaPath)
+	}
+	for column, t := range opts.ColumnTypes {
+		if err := schema.CheckColumnType(t); err != nil {
+			return nil, opErrorf(CodeInvalidArgument, "Invalid type of column '%s': %v", column, err)
+		}
+	}
+	schemas, primary, err := loadSchemas(opts)
+	if err != nil {
+		return nil, err
+	}
+	if like != nil {
+		schemas = append(schemas, validator.Schema{Validator: like.schema, Label: opts.Like})
+	}
+	discriminator, err := loadDiscriminator(opts)
+	if err != nil {
+		return nil, err
+	}
+	dataset, err := loadDataset(opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range opts.GroupRules {
+		if g.By == "" || g.Min < 0 || (g.Max >= 0 && g.Max < g.Min) {
+			return nil, opErrorf(CodeInvalidArgument, "Invalid group rule: need a By column and 0 <= Min <= Max (or Max -1)")
+		}
+	}
+	for _, r := range opts.SortRules {
+		if r.Column == "" {
+			return nil, opErrorf(CodeInvalidArgument, "Invalid sort rule: Column is required")
+		}
+	}
+	for _, r := range opts.ExpressionRules {
+		if _, err := checks.NewExpression(r.Assert, r.Tolerance); err != nil || r.Tolerance < 0 {
+			if err == nil {
+				err = fmt.Errorf("tolerance must not be negative")
 			}
-		} else if opts.Filename != "" && !opts.InferSchema {
-			// Skip auto-discovery when the caller asked for inference
-			schemaPath = schema.ResolveSchema(opts.Filename)
+			return nil, opErrorf(CodeInvalidArgument, "Invalid expression rule: %v", err)
 		}
-		if schemaPath != "" {
-			schemaValidator, err = schema.NewValidator(schemaPath)
-			if err != nil {
+	}
+	for _, r := range opts.OutlierRules {
+		if _, err := checks.NewOutliers(r.Method, r.Threshold, r.Columns); err != nil {
+			return nil, opErrorf(CodeInvalidArgument, "Invalid outlier rule: %v", err)
+		}
+	}
+	for _, r := range opts.GeoRules {
+		if _, err := checks.NewGeo(r.Latitude, r.Longitude, r.WKT, r.GeoJSON); err != nil {
+			return nil, opErrorf(CodeInvalidArgument, "Invalid geo rule: %v", err)
+		}
+	}
+	for _, r := range opts.UnitRules {
+		if _, err := checks.NewUnits(r.Column, r.Units, r.Minimum, r.Maximum); err != nil {
+			return nil, opErrorf(CodeInvalidArgument, "Invalid unit rule: %v", err)
+		}
+	}
+	for _, r := range opts.ScriptRules {
+		if _, err := checks.NewScripts(r.Columns, r.Scripts, r.NoEmoji); err != nil {
+			return nil, opErrorf(CodeInvalidArgument, "Invalid script rule: %v", err)
+		}
+	}
+	for _, r := range opts.RequiredIfRules {
+		if _, err := checks.NewRequiredIf(r.When, r.Require); err != nil {
+			return nil, opErrorf(CodeInvalidArgument, "Invalid required_if rule: %v", err)
+		}
+	}
+	for _, r := range opts.UniqueRules {
+		if _, err := newUnique(r); err != nil {
+			return nil, opErrorf(CodeInvalidArgument, "Invalid unique rule: %v", err)
+		}
+	}
+	for _, r := range opts.CompletenessRules {
+		if _, err := checks.NewCompleteness(r.Column, r.MinPercent); err != nil {
+			return nil, opErrorf(CodeInvalidArgument, "Invalid completeness rule: %v", err)
+		}
+	}
+	baseline, err := loadProfile(opts)
+	if err != nil {
+		return nil, err
+	}
+	if env := opts.Envelope; (env.CountField != 0 || env.ChecksumField != 0) && env.TrailerPrefix == "" ||
+		env.CountField < 0 || env.ChecksumField < 0 || (env.ChecksumField > 0) != (env.ChecksumColumn != "") {
+		return nil, opErrorf(CodeInvalidArgument, "Invalid envelope: trailer fields need TrailerPrefix, and ChecksumField and ChecksumColumn go together")
+	}
+	if opts.TimeBudget < 0 {
+		return nil, opErrorf(CodeInvalidArgument, "TimeBudget must not be negative")
+	}
+	if opts.SampleRows < 0 {
+		return nil, opErrorf(CodeInvalidArgument, "SampleRows must not be negative")
+	}
+	if opts.ContextRows < 0 {
+		return nil, opErrorf(CodeInvalidArgument, "ContextRows must not be negative")
+	}
+	var target *checks.Target
+	if opts.Target != "" {
+		if target, err = checks.NewTarget(opts.Target); err != nil {
+			return nil, opErrorf(CodeInvalidArgument, "Invalid target: %v", err)
+		}
+	}
+	transformer, err := newTransform(opts)
+	if err != nil {
+		return nil, opErrorf(CodeInvalidArgument, "Invalid transform: %v", err)
+	}
+
+	effective, schemaIDs := resultOptions(opts, delimiter, sepLine, schemas, discriminator, baseline, dataset, like)
+	run, err := runRecord(opts, effective, schemaIDs)
+	if err != nil {
+		return nil, newOpError(CodeInvalidArgument, err)
+	}
+	key, err := cacheKey(r, opts, primary, effective, schemaIDs)
+	if err != nil {
+		return nil, newOpError(CodeFileUnreadable, err)
+	}
+	if key != "" {
+		if results, ok := cache.New(opts.CacheDir).Get(key); ok {
+			results.File = name
+			results.Cached = true
+			results.Timings = nil // Those of the run that filled the cache
+			results.Duration = time.Since(start).String()
+			results.Run = run
+			shapeValues(results, templates, redactor, opts)
+			if opts.Breakdown {
+				results.Summarize()
+			}
+			if err := replay(ctx, results, emit); err != nil {
 				return nil, err
 			}
+			if opts.DedupeErrors {
+				results.Dedupe()
+			}
+			return results, nil
 		}
 	}
 
 	input := r
-	if schemaValidator == nil && opts.InferSchema {
+	var schemaInferred bool
+	if !primary && opts.InferSchema {
 		maxRows := opts.InferSchemaMaxRows
 		if maxRows == 0 {
 			maxRows = DefaultInferSchemaMaxRows
 		}
-		headers, sample, replay, sampleErr := parser.ReadSampleFromReader(r, delimiter, maxRows)
+		headers, sample, replay, sampleErr := parser.ReadSampleWithDialect(r, dialect, maxRows)
 		if sampleErr != nil {
-			return nil, sampleErr
+			return nil, inputError(sampleErr)
+		}
+		if transformer != nil {
+			// Infer the types of the values as they are validated
+			transformer.Start(headers)
+			for _, row := range sample {
+				transformer.Row(row)
+			}
 		}
 		schemaJSON, inferErr := schema.Infer(headers, sample)
 		if inferErr != nil {
-			return nil, inferErr
+			return nil, newOpError(CodeInvalidInput, inferErr)
 		}
 		if opts.InferSchemaOutput != "" {
 			if writeErr := os.WriteFile(opts.InferSchemaOutput, schemaJSON, 0644); writeErr != nil {
-				return nil, fmt.Errorf("writing inferred schema: %w", writeErr)
+				return nil, newOpError(CodeOutputFailed, fmt.Errorf("writing inferred schema: %w", writeErr))
 			}
 		}
-		schemaValidator, err = schema.NewValidatorFromReader(bytes.NewReader(schemaJSON))
+		inferred, err := schema.NewValidatorFromReader(bytes.NewReader(schemaJSON), schemaOptions(opts)...)
 		if err != nil {
-			return nil, err
+			return nil, newOpError(CodeInternal, err)
 		}
+		schemas = append([]validator.Schema{{Validator: inferred, Label: "inferred"}}, schemas...)
 		schemaInferred = true
 		input = replay
 	}
+	if len(schemas) == 1 && discriminator == nil {
+		// Labels only tell schemas apart; a single schema keeps findings unlabeled
+		schemas[0].Label = ""
+	}
+	if len(opts.Coercers) > 0 || opts.EmptyValues != "" || len(opts.EmptyColumns) > 0 || len(opts.ColumnTypes) > 0 {
+		configure := func(v *schema.Validator) *schema.Validator {
+			return v.WithCoercers(opts.Coercers...).WithEmptyValues(opts.EmptyValues, opts.EmptyColumns).WithTypes(opts.ColumnTypes)
+		}
+		for i := range schemas {
+			schemas[i].Validator = configure(schemas[i].Validator)
+		}
+		if discriminator != nil {
+			for value, s := range discriminator.Schemas {
+				s.Validator = configure(s.Validator)
+				discriminator.Schemas[value] = s
+			}
+		}
+	}
 
-	// Validate format
-	format := opts.Format
-	if format == "" {
-		format = "pretty"
+	// The file schema names itself in findings when rows have schemas too
+	checkList := rowChecks(opts, baseline, target)
+	if like != nil {
+		checkList = append(checkList, checks.NewLike(opts.Like, like.Delimiter, like.Headers, delimiter))
 	}
-	if format != "pretty" && format != "json" {
-		return nil, fmt.Errorf("Format must be 'pretty' or 'json'")
+	if dataset != nil {
+		label := ""
+		if len(schemas) > 0 || discriminator != nil {
+			label = opts.FileSchema
+		}
+		checkList = append(checkList, checks.NewDataset(dataset, label))
 	}
 
 	// Create validator
-	v := validator.New(input, name, delimiter, schemaValidator, opts.FailFast, schemaInferred)
+	var emitErr error
+	v := validator.NewWithOptions(input, validator.Options{
+		Name:           name,
+		Delimiter:      delimiter,
+		Quote:          opts.Quote,
+		Escape:         opts.Escape,
+		Schemas:        schemas,
+		Discriminator:  discriminator,
+		Checks:         checkList,
+		Transform:      rowTransform(transformer),
+		Envelope:       envelope(opts.Envelope),
+		Fingerprint:    opts.Fingerprint,
+		SampleRows:     opts.SampleRows,
+		SampleColumns:  opts.SampleColumns,
+		IncludeColumns: opts.IncludeColumns,
+		KeyColumns:     keyColumns(schemas, opts.UniqueRules),
+		SampleSeed:     opts.Seed,
+		ContextRows:    opts.ContextRows,
+		TimeBudget:     opts.TimeBudget,
+		Interrupt:      opts.Interrupt,
+		Size:           inputSize(r),
+		FailFast:       opts.FailFast,
+		QuotedEmpty:    opts.QuotedEmpty,
+		SepLine:        sepLine,
+		SepFinding:     sepFinding,
+		ShortRows:      opts.ShortRows,
+		LongRows:       opts.LongRows,
+		RequireData:    opts.RequireData,
+		Chunked:        opts.Chunked,
+		Quoting:        opts.Quoting,
+		Fixes:          wantsFixes(opts),
+		Preamble:       int64(preamble),
+		Timings:        opts.Timings,
+		EncodingErrors: opts.MaxEncodingErrors,
+		SchemaInferred: schemaInferred,
+		Context:        ctx,
+		OnError:        shapedEmitter(emit, &emitErr, templates, redactor, opts),
+	})
 	results, err := v.Validate()
 	if err != nil {
-		return nil, err
+		if emitErr != nil {
+			return nil, emitErr
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, newOpError(CodeCancelled, ctxErr)
+		}
+		return nil, inputError(err)
 	}
-
-	// Create reporter
-	rep := reporter.New(format, opts.Output)
-	if err := rep.Report(results, writer); err != nil {
-		return nil, err
+	if opts.ProducerSchema != "" {
+		results.Contract = contract(results, opts)
+	}
+	if key != "" && !results.Partial {
+		// The cache is best effort: a failed write only costs a revalidation next time
+		_ = cache.New(opts.CacheDir).Put(key, results)
+	}
+	results.Run = run
+	shapeValues(results, templates, redactor, opts)
+	if opts.Breakdown {
+		results.Summarize()
+	}
+	if emit != nil {
+		// Only errors are streamed as rows are read; warnings, some known only once checks
+		// over the whole file finish, follow them
+		for _, w := range results.Warnings {
+			if err := emit(w); err != nil {
+				return nil, err
+			}
+		}
+	}
+	// Findings were streamed one by one; only the results are rolled up
+	if opts.DedupeErrors {
+		results.Dedupe()
 	}
 	return results, nil
 }
 
+// Labels of the schemas of a producer/consumer contract.
+const (
+	producerLabel = "producer"
+	consumerLabel = "consumer"
+)
+
+// loadSchemas compiles the primary schema followed by opts.AdditionalSchemas, each
+// labeled with its path. The primary schema comes from SchemaReader, else SchemaPath,
+// else is resolved from Filename (unless inference was requested); primary reports
+// whether one was found. A producer and consumer schema pair, labeled with its sides,
+// takes the place of the primary schema.
+func loadSchemas(opts Options) (schemas []validator.Schema, primary bool, err error) {
+	if opts.ProducerSchema != "" || opts.ConsumerSchema != "" {
+		if opts.ProducerSchema == "" || opts.ConsumerSchema == "" {
+			return nil, false, opErrorf(CodeInvalidArgument, "ProducerSchema and ConsumerSchema must be set together")
+		}
+		if opts.SchemaReader != nil || opts.SchemaPath != "" || opts.InferSchema {
+			return nil, false, opErrorf(CodeInvalidArgument, "ProducerSchema and ConsumerSchema cannot be combined with SchemaPath, SchemaReader or InferSchema")
+		}
+		for _, side := range [][2]string{{producerLabel, opts.ProducerSchema}, {consumerLabel, opts.ConsumerSchema}} {
+			s, err := loadSchemaFile(opts, side[1])
+			if err != nil {
+				return nil, false, err
+			}
+			s.Label = side[0]
+			schemas = append(schemas, s)
+		}
+	} else if opts.SchemaReader != nil {
+		v, err := schema.NewValidatorFromReader(opts.SchemaReader, schemaOptions(opts)...)
+		if err != nil {
+			return nil, false, newOpError(CodeSchemaInvalid, err)
+		}
+		schemas = append(schemas, validator.Schema{Validator: v, Label: "inline"})
+	} else {
+		schemaPath := opts.SchemaPath
+		if schemaPath == "" && opts.Filename != "" && !opts.InferSchema {
+			// Skip auto-discovery when the caller asked for inference
+			r := schema.Resolver{
+				Patterns:    opts.SchemaLookup,
+				RootMarkers: opts.RootMarkers,
+				Symlinks:    opts.SchemaSymlinks,
+				IgnoreCase:  opts.SchemaIgnoreCase,
+			}
+			if opts.FS != nil {
+				schemaPath = r.ResolveFS(opts.FS, opts.Filename)
+			} else {
+				schemaPath = r.Resolve(opts.Filename)
+			}
+		}
+		if schemaPath != "" {
+			s, err := loadSchemaFile(opts, schemaPath)
+			if err != nil {
+				return nil, false, err
+			}
+			schemas = append(schemas, s)
+		}
+	}
+	primary = len(schemas) > 0
+
+	for _, path := range opts.AdditionalSchemas {
+		s, err := loadSchemaFile(opts, path)
+		if err != nil {
+			return nil, false, err
+		}
+		schemas = append(schemas, s)
+	}
+	return schemas, primary, nil
+}
+
+// contract counts the findings of each side of a producer/consumer schema pair.
+func contract(results *validator.Results, opts Options) *validator.Contract {
+	c := &validator.Contract{ProducerSchema: opts.ProducerSchema, ConsumerSchema: opts.ConsumerSchema}
+	for _, e := range results.Errors {
+		switch e.Schema {
+		case producerLabel:
+			c.ProducerErrors++
+		case consumerLabel:
+			c.ConsumerErrors++
+		}
+	}
+	switch {
+	case c.ProducerErrors > 0 && c.ConsumerErrors > 0:
+		c.RejectedBy = "both"
+	case c.ProducerErrors > 0:
+		c.RejectedBy = producerLabel
+	case c.ConsumerErrors > 0:
+		c.RejectedBy = consumerLabel
+	}
+	return c
+}
+
+// loadDiscriminator compiles opts.DiscriminatorSchemas, labeled with their paths, or
+// returns nil when no discriminator is configured.
+func loadDiscriminator(opts Options) (*validator.Discriminator, error) {
+	if opts.DiscriminatorColumn == "" && len(opts.DiscriminatorSchemas) == 0 {
+		return nil, nil
+	}
+	if opts.DiscriminatorColumn == "" || len(opts.DiscriminatorSchemas) == 0 {
+		return nil, opErrorf(CodeInvalidArgument, "DiscriminatorColumn and DiscriminatorSchemas must be set together")
+	}
+	d := &validator.Discriminator{Column: opts.DiscriminatorColumn, Schemas: make(map[string]validator.Schema, len(opts.DiscriminatorSchemas))}
+	for value, path := range opts.DiscriminatorSchemas {
+		s, err := loadSchemaFile(opts, path)
+		if err != nil {
+			return nil, err
+		}
+		d.Schemas[value] = s
+	}
+	return d, nil
+}
+
+// loadDataset compiles opts.FileSchema, in opts.FS when it is set, or returns nil.
+func loadDataset(opts Options) (*schema.Dataset, error) {
+	if opts.FileSchema == "" {
+		return nil, nil
+	}
+	var f io.ReadCloser
+	var err error
+	if opts.FS == nil {
+		f, err = os.Open(opts.FileSchema)
+	} else {
+		f, err = opts.FS.Open(opts.FileSchema)
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, opErrorf(CodeSchemaNotFound, "Schema file '%s' does not exist", opts.FileSchema)
+	}
+	if err != nil {
+		return nil, newOpError(CodeSchemaInvalid, fmt.Errorf("failed to read schema file: %w", err))
+	}
+	defer f.Close()
+	d, err := schema.NewDatasetFromReader(f, append(schemaOptions(opts), schema.WithSource(opts.FS, opts.FileSchema))...)
+	if err != nil {
+		return nil, newOpError(CodeSchemaInvalid, err)
+	}
+	return d.WithCoercers(opts.Coercers...).WithEmptyValues(opts.EmptyValues, opts.EmptyColumns).WithTypes(opts.ColumnTypes), nil
+}
+
+// rowChecks builds fresh checks across rows for one input; checks keep per-input state.
+// baseline is the loaded opts.Profile and target the check for opts.Target, if any.
+func rowChecks(opts Options, baseline *profile.Profile, target *checks.Target) []validator.Check {
+	var list []validator.Check
+	for _, g := range opts.GroupRules {
+		list = append(list, checks.NewGroup(g.By, g.Where, g.Min, g.Max))
+	}
+	for _, r := range opts.SortRules {
+		list = append(list, checks.NewSorted(r.Column, r.Descending, r.Unique))
+	}
+	for _, r := range opts.ExpressionRules {
+		if e, err := checks.NewExpression(r.Assert, r.Tolerance); err == nil {
+			list = append(list, e)
+		}
+	}
+	for _, r := range opts.OutlierRules {
+		if o, err := checks.NewOutliers(r.Method, r.Threshold, r.Columns); err == nil {
+			list = append(list, o)
+		}
+	}
+	for _, r := range opts.GeoRules {
+		if g, err := checks.NewGeo(r.Latitude, r.Longitude, r.WKT, r.GeoJSON); err == nil {
+			list = append(list, g)
+		}
+	}
+	for _, r := range opts.UnitRules {
+		if u, err := checks.NewUnits(r.Column, r.Units, r.Minimum, r.Maximum); err == nil {
+			list = append(list, u)
+		}
+	}
+	for _, r := range opts.ScriptRules {
+		if s, err := checks.NewScripts(r.Columns, r.Scripts, r.NoEmoji); err == nil {
+			list = append(list, s)
+		}
+	}
+	for _, r := range opts.RequiredIfRules {
+		if c, err := checks.NewRequiredIf(r.When, r.Require); err == nil {
+			list = append(list, c)
+		}
+	}
+	for _, r := range opts.UniqueRules {
+		if u, err := newUnique(r); err == nil {
+			list = append(list, u)
+		}
+	}
+	for _, r := range opts.CompletenessRules {
+		if c, err := checks.NewCompleteness(r.Column, r.MinPercent); err == nil {
+			list = append(list, c)
+		}
+	}
+	if target != nil {
+		list = append(list, target)
+	}
+	if baseline != nil {
+		list = append(list, checks.NewDrift(baseline, profile.Thresholds(opts.Drift)))
+	}
+	list = append(list, checks.NewTrailingDelimiter())
+	return list
+}
+
+// loadProfile reads and checks opts.Profile, or returns nil when it is not set.
+func loadProfile(opts Options) (*profile.Profile, error) {
+	if opts.Profile == "" {
+		return nil, nil
+	}
+	if d := opts.Drift; d.NullRate < 0 || d.Mean < 0 || d.Distinct < 0 {
+		return nil, opErrorf(CodeInvalidArgument, "Invalid drift thresholds: must not be negative")
+	}
+	p, err := profile.Load(opts.Profile)
+	if err != nil {
+		return nil, opErrorf(CodeInvalidArgument, "Invalid profile: %v", err)
+	}
+	return p, nil
+}
+
+// newTransform compiles opts.Transforms, or returns nil when there are none.
+func newTransform(opts Options) (*transform.Transformer, error) {
+	if len(opts.Transforms) == 0 {
+		return nil, nil
+	}
+	return transform.New(opts.Transforms)
+}
+
+// rowTransform is the validator's transform for t. A nil *Transformer must not become
+// a non-nil interface.
+func rowTransform(t *transform.Transformer) validator.Transform {
+	if t == nil {
+		return nil
+	}
+	return t
+}
+
+// inputSize returns the size of a regular file input (*os.File or fs.File), or 0.
+func inputSize(r io.Reader) int64 {
+	f, ok := r.(interface{ Stat() (fs.FileInfo, error) })
+	if !ok {
+		return 0
+	}
+	info, err := f.Stat()
+	if err != nil || !info.Mode().IsRegular() {
+		return 0
+	}
+	return info.Size()
+}
+
+// envelope converts the envelope options, or returns nil when there is none.
+func envelope(e Envelope) *validator.Envelope {
+	if !e.enabled() {
+		return nil
+	}
+	return &validator.Envelope{
+		HeaderPrefix:   e.HeaderPrefix,
+		TrailerPrefix:  e.TrailerPrefix,
+		CountField:     e.CountField,
+		ChecksumField:  e.ChecksumField,
+		ChecksumColumn: e.ChecksumColumn,
+	}
+}
+
+// loadSchemaFile compiles the schema at path, in fsys when it is set.
+func loadSchemaFile(opts Options, path string) (validator.Schema, error) {
+	fsys := opts.FS
+	if fsys == nil {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return validator.Schema{}, opErrorf(CodeSchemaNotFound, "Schema file '%s' does not exist", path)
+		}
+		v, err := schema.NewValidator(path, schemaOptions(opts)...)
+		if err != nil {
+			return validator.Schema{}, newOpError(CodeSchemaInvalid, err)
+		}
+		return validator.Schema{Validator: v, Label: path}, nil
+	}
+
+	f, err := fsys.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return validator.Schema{}, opErrorf(CodeSchemaNotFound, "Schema file '%s' does not exist", path)
+	}
+	if err != nil {
+		return validator.Schema{}, newOpError(CodeSchemaInvalid, fmt.Errorf("failed to read schema file: %w", err))
+	}
+	defer f.Close()
+	v, err := schema.NewValidatorFromReader(f, append(schemaOptions(opts), schema.WithSource(fsys, path))...)
+	if err != nil {
+		return validator.Schema{}, newOpError(CodeSchemaInvalid, err)
+	}
+	return validator.Schema{Validator: v, Label: path}, nil
+}
+
+// schemaOptions returns the compile options of the run's schemas. lint has already
+// checked that the formats are registered.
+func schemaOptions(opts Options) []schema.Option {
+	var list []schema.Option
+	if opts.SchemaRoot != "" {
+		list = append(list, schema.WithRoot(opts.SchemaRoot))
+	}
+	if opts.FS != nil {
+		// Schemas without a file, such as SchemaReader, resolve $refs in FS too
+		list = append(list, schema.WithSource(opts.FS, ""))
+	}
+	if len(opts.Formats) == 0 {
+		return list
+	}
+	funcs, _ := formats.Lookup(opts.Formats)
+	asserted := make(map[string]func(string) bool, len(funcs))
+	for name, f := range funcs {
+		asserted[name] = f
+	}
+	return append(list, schema.WithFormats(asserted))
+}
+
+// shapeValues applies message templates, redaction and then truncation to the findings
+// in results. Templating first lets redaction hide the values templates embed, and
+// redacting before truncating keeps hashes and lengths faithful to the full value.
+func shapeValues(results *validator.Results, templates validator.Templates, redactor *redact.Redactor, opts Options) {
+	templates.Results(results)
+	if redactor != nil {
+		redactor.Results(results)
+	}
+	results.TruncateValues(opts.MaxValueLength)
+}
+
+// resultOptions returns the options that change results, as used in the cache key and
+// recorded in Results.Run, and the label and hash of each schema.
+func resultOptions(opts Options, delimiter, sepLine string, schemas []validator.Schema, discriminator *validator.Discriminator, baseline *profile.Profile, dataset *schema.Dataset, like *likeContract) (cacheOptions, []string) {
+	// Labels are part of the key because they appear in results
+	var schemaIDs []string
+	for _, s := range schemas {
+		schemaIDs = append(schemaIDs, s.Label+"="+s.Validator.Hash())
+	}
+	var discriminatorColumn string
+	if discriminator != nil {
+		discriminatorColumn = discriminator.Column
+		values := make([]string, 0, len(discriminator.Schemas))
+		for value := range discriminator.Schemas {
+			values = append(values, value)
+		}
+		sort.Strings(values)
+		for _, value := range values {
+			s := discriminator.Schemas[value]
+			schemaIDs = append(schemaIDs, value+":"+s.Label+"="+s.Validator.Hash())
+		}
+	}
+	if dataset != nil {
+		schemaIDs = append(schemaIDs, "file:"+opts.FileSchema+"="+dataset.Hash())
+	}
+	var drift *DriftThresholds
+	if baseline != nil {
+		drift = &opts.Drift
+	}
+	return cacheOptions{
+		Delimiter:          delimiter,
+		Quote:              opts.Quote,
+		Escape:             opts.Escape,
+		FailFast:           opts.FailFast,
+		InferSchema:        opts.InferSchema,
+		InferSchemaMaxRows: opts.InferSchemaMaxRows,
+		Discriminator:      discriminatorColumn,
+		GroupRules:         opts.GroupRules,
+		SortRules:          opts.SortRules,
+		ExpressionRules:    opts.ExpressionRules,
+		OutlierRules:       opts.OutlierRules,
+		GeoRules:           opts.GeoRules,
+		UnitRules:          opts.UnitRules,
+		ScriptRules:        opts.ScriptRules,
+		RequiredIfRules:    opts.RequiredIfRules,
+		UniqueRules:        opts.UniqueRules,
+		CompletenessRules:  opts.CompletenessRules,
+		Profile:            baseline,
+		Drift:              drift,
+		Envelope:           opts.Envelope,
+		Target:             opts.Target,
+		Transforms:         opts.Transforms,
+		SampleRows:         opts.SampleRows,
+		SampleColumns:      opts.SampleColumns,
+		IncludeColumns:     opts.IncludeColumns,
+		Like:               like,
+		Fingerprint:        opts.Fingerprint,
+		Formats:            opts.Formats,
+		EmptyValues:        opts.EmptyValues,
+		EmptyColumns:       opts.EmptyColumns,
+		ColumnTypes:        opts.ColumnTypes,
+		QuotedEmpty:        opts.QuotedEmpty,
+		ShortRows:          opts.ShortRows,
+		LongRows:           opts.LongRows,
+		RequireData:        opts.RequireData,
+		Chunked:            opts.Chunked,
+		Quoting:            opts.Quoting,
+		Fixes:              wantsFixes(opts),
+		SepLine:            opts.SepLine + " " + sepLine,
+		MaxEncodingErrors:  opts.MaxEncodingErrors,
+		Seed:               opts.Seed,
+	}, schemaIDs
+}
+
+// runRecord returns the seed and the effective options of a run, for Results.Run.
+func runRecord(opts Options, effective cacheOptions, schemaIDs []string) (*validator.Run, error) {
+	// The mode and the sep= line found are joined for the key
+	effective.SepLine = strings.TrimSpace(effective.SepLine)
+	recorded := runOptions{
+		cacheOptions:   effective,
+		Schemas:        schemaIDs,
+		Profile:        opts.Profile,
+		TimeBudget:     opts.TimeBudget,
+		ContextRows:    opts.ContextRows,
+		Breakdown:      opts.Breakdown,
+		DedupeErrors:   opts.DedupeErrors,
+		RedactValues:   opts.RedactValues,
+		RedactColumns:  opts.RedactColumns,
+		MaxValueLength: opts.MaxValueLength,
+	}
+	b, err := json.Marshal(recorded)
+	if err != nil {
+		return nil, fmt.Errorf("recording options: %w", err)
+	}
+	return &validator.Run{Seed: opts.Seed, Options: b}, nil
+}
+
+// cacheKey hashes a regular file input for the result cache and rewinds it to where it
+// was. It returns "" when caching is off or does not apply: streams cannot be re-read,
+// writing an inferred schema is a side effect a cache hit would skip, context rows
+// are not stored in the cache, custom coercers cannot be part of the key, and the cache
+// holds raw values that RedactValues must keep off the disk.
+func cacheKey(r io.Reader, opts Options, primary bool, effective cacheOptions, schemaIDs []string) (string, error) {
+	f, ok := r.(*os.File)
+	if !ok || opts.CacheDir == "" {
+		return "", nil
+	}
+	if !primary && opts.InferSchema && opts.InferSchemaOutput != "" || opts.ContextRows > 0 || len(opts.Coercers) > 0 || opts.RedactValues != "" {
+		return "", nil
+	}
+	if info, err := f.Stat(); err != nil || !info.Mode().IsRegular() {
+		return "", nil
+	}
+	offset, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return "", nil
+	}
+
+	key, err := cache.Key(f, strings.Join(schemaIDs, ","), effective)
+	if _, seekErr := f.Seek(offset, io.SeekStart); seekErr != nil {
+		return "", seekErr
+	}
+	if err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
 // Lint validates a CSV stream and returns structured results.
 // It does not take an explicit schema path; a schema may still be auto-resolved from name
 // when name looks like a file path (see LintAdvanced for resolution rules). name is used for
@@ -3,10 +3,14 @@ package csvlinter
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
+
+	"github.com/csvlinter/csvlinter/internal/validator"
 )
 
 func readFileToBytes(path string) ([]byte, error) {
@@ -389,6 +393,26 @@ func TestLintAdvanced_AllOptions(t *testing.T) {
 		}
 	})
 
+	t.Run("OutputDir: chunked report", func(t *testing.T) {
+		outputDir := filepath.Join(t.TempDir(), "reports")
+		opts := Options{Delimiter: ",", Format: "json", OutputDir: outputDir, ChunkSize: 1, Filename: csvPath}
+		if _, err := LintAdvanced(bytes.NewReader(csvData), opts, nil); err != nil {
+			t.Fatalf("LintAdvanced failed: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(outputDir, "index.json")); err != nil {
+			t.Errorf("Expected an index in the output directory: %v", err)
+		}
+		for _, bad := range []Options{
+			{Format: "pretty", OutputDir: outputDir},
+			{Format: "json", OutputDir: outputDir, Output: filepath.Join(outputDir, "r.json")},
+			{Format: "json", OutputDir: outputDir, ChunkSize: -1},
+		} {
+			if _, err := LintAdvanced(bytes.NewReader(csvData), bad, nil); CodeOf(err) != CodeInvalidArgument {
+				t.Errorf("Expected INVALID_ARGUMENT for %+v, got %v", bad, err)
+			}
+		}
+	})
+
 	t.Run("Filename: logical schema resolution", func(t *testing.T) {
 		opts := Options{Delimiter: ",", Format: "json", Filename: csvPath}
 		var buf bytes.Buffer
@@ -607,3 +631,470 @@ func TestLintAdvanced_InferSchema(t *testing.T) {
 		}
 	})
 }
+
+func TestLintAdvancedErrorCodes(t *testing.T) {
+	cases := []struct {
+		name string
+		csv  string
+		opts Options
+		code ErrorCode
+	}{
+		{"schema not found", "id\n1\n", Options{SchemaPath: "no-such-schema.json"}, CodeSchemaNotFound},
+		{"schema invalid", "id\n1\n", Options{SchemaReader: strings.NewReader(`{"type": 12}`)}, CodeSchemaInvalid},
+		{"empty input", "", Options{}, CodeEmptyInput},
+		{"malformed header", "a,\"b\n", Options{}, CodeInvalidInput},
+		{"bad format", "id\n1\n", Options{Format: "xml"}, CodeInvalidArgument},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			_, err := LintAdvanced(strings.NewReader(tc.csv), tc.opts, &buf)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if got := CodeOf(err); got != tc.code {
+				t.Errorf("expected code %s, got %s (%v)", tc.code, got, err)
+			}
+		})
+	}
+}
+
+func TestLintFiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	good := write("good.csv", "id,name\n1,Alice\n")
+	tabs := write("tabs.tsv", "id\tname\n1\tBob\n")
+	empty := write("empty.csv", "")
+	ragged := write("ragged.csv", "id,name\n1\n")
+
+	var buf bytes.Buffer
+	batch, err := LintFiles([]string{good, tabs, empty, ragged}, Options{Format: "json"}, &buf)
+	if err != nil {
+		t.Fatalf("LintFiles failed: %v", err)
+	}
+	if batch.TotalFiles != 4 || batch.InvalidFiles != 2 || batch.Valid {
+		t.Errorf("Unexpected batch summary: %+v", batch)
+	}
+	// Files are reported by name: empty, good, ragged, tabs
+	if !batch.Files[3].Valid {
+		t.Errorf("Expected .tsv file to be parsed with tabs, got errors: %v", batch.Files[3].Errors)
+	}
+	if errs := batch.Files[0].Errors; len(errs) != 1 || errs[0].Type != "file" {
+		t.Errorf("Expected a single file error for empty input, got %v", errs)
+	}
+	if !json.Valid(buf.Bytes()) {
+		t.Errorf("Expected JSON report, got %s", buf.String())
+	}
+
+	_, err = LintFiles([]string{good}, Options{SchemaPath: "no-such-schema.json"}, &buf)
+	if CodeOf(err) != CodeSchemaNotFound {
+		t.Errorf("Expected SCHEMA_NOT_FOUND to abort the run, got %v", err)
+	}
+}
+
+func TestLintFilesJobs(t *testing.T) {
+	dir := t.TempDir()
+	var paths []string
+	for i := 0; i < 8; i++ {
+		p := filepath.Join(dir, fmt.Sprintf("f%d.csv", 7-i))
+		content := "id,name\n1,Alice\n"
+		if i%2 == 0 {
+			content = "id,name\n1\n"
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, p)
+	}
+
+	var want bytes.Buffer
+	sequential, err := LintFiles(paths, Options{Format: "compact"}, &want)
+	if err != nil {
+		t.Fatalf("LintFiles failed: %v", err)
+	}
+	var buf bytes.Buffer
+	batch, err := LintFiles(paths, Options{Format: "compact", Jobs: 4}, &buf)
+	if err != nil {
+		t.Fatalf("LintFiles failed: %v", err)
+	}
+	if batch.TotalFiles != 8 || batch.InvalidFiles != 4 || batch.Files[0].File != sequential.Files[0].File || buf.String() != want.String() {
+		t.Errorf("want the same report as one file at a time, got %+v:\n%s", batch, buf.String())
+	}
+
+	buf.Reset()
+	if _, err := LintFiles(paths, Options{Format: "json", Jobs: 4, StreamResults: true}, &buf); err != nil {
+		t.Fatalf("LintFiles failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 9 || !strings.Contains(lines[8], `"total_files":8`) {
+		t.Errorf("want a line per file, then the totals, got:\n%s", buf.String())
+	}
+	if _, err := LintFiles(paths, Options{Format: "sarif", StreamResults: true}, &buf); CodeOf(err) != CodeInvalidArgument {
+		t.Errorf("want INVALID_ARGUMENT streaming sarif, got %v", err)
+	}
+}
+
+func TestLintInterrupt(t *testing.T) {
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "data.csv")
+	if err := os.WriteFile(csvPath, []byte("id,name\n1,Alice\n2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	interrupt := make(chan struct{})
+	close(interrupt)
+
+	var buf bytes.Buffer
+	results, err := LintAdvanced(strings.NewReader("id,name\n1,Alice\n2\n"), Options{Format: "json", Interrupt: interrupt}, &buf)
+	if err != nil {
+		t.Fatalf("LintAdvanced failed: %v", err)
+	}
+	if !results.Partial || results.TotalRows != 0 || !strings.Contains(buf.String(), `"partial": true`) {
+		t.Errorf("want a partial report stopped before the first row, got %+v:\n%s", results, buf.String())
+	}
+
+	// Files not started when the interrupt comes are left out
+	buf.Reset()
+	batch, err := LintFiles([]string{csvPath}, Options{Format: "json", Interrupt: interrupt}, &buf)
+	if err != nil {
+		t.Fatalf("LintFiles failed: %v", err)
+	}
+	if !batch.Partial || batch.TotalFiles != 0 || !strings.Contains(buf.String(), `"partial": true`) {
+		t.Errorf("want a partial batch without files, got %+v:\n%s", batch, buf.String())
+	}
+
+	results, err = LintAdvanced(strings.NewReader("id,name\n1,Alice\n"), Options{Format: "json", Interrupt: make(chan struct{})}, &buf)
+	if err != nil || results.Partial || results.TotalRows != 1 {
+		t.Errorf("want a full run until the interrupt, got %+v, %v", results, err)
+	}
+}
+
+func TestLintAdvancedCache(t *testing.T) {
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "data.csv")
+	schemaPath := filepath.Join(dir, "data.schema.json")
+	if err := os.WriteFile(csvPath, []byte("id\n1\nx\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(schemaPath, []byte(`{"type":"object","properties":{"id":{"type":"string"}}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	opts := Options{Filename: csvPath, Format: "json", CacheDir: filepath.Join(dir, "cache")}
+	run := func() *validator.Results {
+		t.Helper()
+		f, err := os.Open(csvPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		var buf bytes.Buffer
+		results, err := LintAdvanced(f, opts, &buf)
+		if err != nil {
+			t.Fatalf("LintAdvanced failed: %v", err)
+		}
+		return results
+	}
+
+	if run().Cached {
+		t.Fatal("first run should not be cached")
+	}
+	if !run().Cached {
+		t.Error("second run should be cached")
+	}
+
+	// A schema change invalidates the entry
+	if err := os.WriteFile(schemaPath, []byte(`{"type":"object","properties":{"id":{"type":"integer"}}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	results := run()
+	if results.Cached || results.Valid {
+		t.Errorf("expected a fresh, invalid result after the schema changed: %+v", results)
+	}
+
+	// Streams are never cached
+	var buf bytes.Buffer
+	results, err := LintAdvanced(strings.NewReader("id\n1\n"), opts, &buf)
+	if err != nil || results.Cached {
+		t.Errorf("stream input should not be cached: %+v, %v", results, err)
+	}
+}
+
+func TestLintAdvancedCacheRedacted(t *testing.T) {
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "data.csv")
+	if err := os.WriteFile(csvPath, []byte("id\nsecret.person@example.com\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cacheDir := filepath.Join(dir, "cache")
+	opts := Options{
+		Filename:     csvPath,
+		Format:       "json",
+		CacheDir:     cacheDir,
+		SchemaReader: strings.NewReader(`{"type":"object","properties":{"id":{"type":"integer"}}}`),
+		RedactValues: "mask",
+	}
+	f, err := os.Open(csvPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var buf bytes.Buffer
+	results, err := LintAdvanced(f, opts, &buf)
+	if err != nil || results.Valid {
+		t.Fatalf("want an invalid result, got %+v, %v", results, err)
+	}
+
+	// Raw values must not reach the disk through the cache
+	entries, _ := os.ReadDir(cacheDir)
+	for _, e := range entries {
+		data, err := os.ReadFile(filepath.Join(cacheDir, e.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(data), "secret.person") {
+			t.Errorf("cache file %s holds the raw value:\n%s", e.Name(), data)
+		}
+	}
+	if len(entries) != 0 {
+		t.Errorf("want no cache entry for a redacted run, got %d", len(entries))
+	}
+}
+
+func TestLintAdvancedRedactValues(t *testing.T) {
+	opts := Options{
+		Format:        "json",
+		SchemaReader:  strings.NewReader(`{"type":"object","properties":{"id":{"type":"integer"},"code":{"type":"integer"}}}`),
+		RedactValues:  "mask",
+		RedactColumns: []string{"id"},
+	}
+	var buf bytes.Buffer
+	results, err := LintAdvanced(strings.NewReader("id,code\nsecret-id-value,x\n"), opts, &buf)
+	if err != nil {
+		t.Fatalf("LintAdvanced failed: %v", err)
+	}
+	if strings.Contains(buf.String(), "secret-id-value") {
+		t.Errorf("report leaks the redacted column: %s", buf.String())
+	}
+	for _, e := range results.Errors {
+		if e.Field == "code" && e.Value != "x" {
+			t.Errorf("expected other columns untouched, got %+v", e)
+		}
+	}
+}
+
+func TestLintAdvancedAdditionalSchemas(t *testing.T) {
+	dir := t.TempDir()
+	structural := filepath.Join(dir, "structural.schema.json")
+	business := filepath.Join(dir, "business.schema.json")
+	if err := os.WriteFile(structural, []byte(`{"type":"object","properties":{"amount":{"type":"number"}},"required":["amount"]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(business, []byte(`{"type":"object","properties":{"amount":{"type":"number","maximum":100}}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	input := "amount\n50\n500\nabc\n"
+
+	var buf bytes.Buffer
+	results, err := LintAdvanced(strings.NewReader(input), Options{SchemaPath: structural, AdditionalSchemas: []string{business}, Format: "json"}, &buf)
+	if err != nil {
+		t.Fatalf("LintAdvanced failed: %v", err)
+	}
+	bySchema := map[string]int{}
+	for _, e := range results.Errors {
+		bySchema[e.Schema]++
+	}
+	// "abc" fails the type in both schemas; 500 only breaks the business maximum
+	if bySchema[structural] != 1 || bySchema[business] != 2 || len(results.Errors) != 3 {
+		t.Errorf("Expected errors labeled by schema, got %+v", results.Errors)
+	}
+
+	results, err = LintAdvanced(strings.NewReader(input), Options{AdditionalSchemas: []string{business}, Format: "json"}, &buf)
+	if err != nil {
+		t.Fatalf("LintAdvanced failed: %v", err)
+	}
+	for _, e := range results.Errors {
+		if e.Schema != "" {
+			t.Errorf("Expected a single schema to leave errors unlabeled, got %+v", e)
+		}
+	}
+
+	_, err = LintAdvanced(strings.NewReader(input), Options{AdditionalSchemas: []string{filepath.Join(dir, "missing.json")}}, &buf)
+	if CodeOf(err) != CodeSchemaNotFound {
+		t.Errorf("Expected SCHEMA_NOT_FOUND for a missing additional schema, got %v", err)
+	}
+}
+
+func TestLintAdvancedContract(t *testing.T) {
+	dir := t.TempDir()
+	producer := filepath.Join(dir, "producer.schema.json")
+	consumer := filepath.Join(dir, "consumer.schema.json")
+	if err := os.WriteFile(producer, []byte(`{"type":"object","properties":{"status":{"enum":["open","pending","closed"]}}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(consumer, []byte(`{"type":"object","properties":{"status":{"enum":["open","closed"]}}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	opts := Options{ProducerSchema: producer, ConsumerSchema: consumer, Format: "json"}
+	results, err := LintAdvanced(strings.NewReader("status\nopen\npending\n"), opts, &buf)
+	if err != nil {
+		t.Fatalf("LintAdvanced failed: %v", err)
+	}
+	c := results.Contract
+	if c == nil || c.RejectedBy != "consumer" || c.ConsumerErrors != 1 || c.ProducerErrors != 0 || c.ProducerSchema != producer {
+		t.Errorf("Expected the consumer to reject the file, got %+v", c)
+	}
+	if len(results.Errors) != 1 || results.Errors[0].Schema != "consumer" {
+		t.Errorf("Expected one error labeled consumer, got %+v", results.Errors)
+	}
+
+	results, _ = LintAdvanced(strings.NewReader("status\nopen\narchived\n"), opts, &buf)
+	if c := results.Contract; c == nil || c.RejectedBy != "both" {
+		t.Errorf("Expected both sides to reject the file, got %+v", c)
+	}
+	results, _ = LintAdvanced(strings.NewReader("status\nopen\n"), opts, &buf)
+	if c := results.Contract; c == nil || c.RejectedBy != "" || !results.Valid {
+		t.Errorf("Expected both sides to accept the file, got %+v", c)
+	}
+
+	for _, bad := range []Options{{ProducerSchema: producer}, {ProducerSchema: producer, ConsumerSchema: consumer, SchemaPath: producer}} {
+		if _, err := LintAdvanced(strings.NewReader("status\nopen\n"), bad, &buf); CodeOf(err) != CodeInvalidArgument {
+			t.Errorf("Expected INVALID_ARGUMENT for %+v, got %v", bad, err)
+		}
+	}
+}
+
+func TestLintAdvancedCoercers(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "prices.schema.json")
+	if err := os.WriteFile(path, []byte(`{"type":"object","properties":{"price":{"type":"number","maximum":1000}}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	euros := CoercerFunc(func(column, value string, types []string) (interface{}, bool) {
+		f, err := strconv.ParseFloat(strings.NewReplacer("€", "", ",", "").Replace(value), 64)
+		return f, err == nil && strings.HasPrefix(value, "€")
+	})
+
+	var buf bytes.Buffer
+	results, err := LintAdvanced(strings.NewReader("price\n€999.00\n\"€1,234.00\"\n"), Options{SchemaPath: path, Coercers: []Coercer{euros}}, &buf)
+	if err != nil {
+		t.Fatalf("LintAdvanced failed: %v", err)
+	}
+	if len(results.Errors) != 1 || results.Errors[0].Line != 3 || results.Errors[0].Value != "€1,234.00" || results.Errors[0].RuleID != "SCH006" {
+		t.Errorf("Expected only the coerced price over the maximum, got %+v", results.Errors)
+	}
+}
+
+func TestLintAdvancedDiscriminator(t *testing.T) {
+	dir := t.TempDir()
+	refund := filepath.Join(dir, "refund.schema.json")
+	if err := os.WriteFile(refund, []byte(`{"type":"object","properties":{"amount":{"type":"number","maximum":0}}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	input := "type,amount\nsale,10\nrefund,5\n"
+
+	var buf bytes.Buffer
+	results, err := LintAdvanced(strings.NewReader(input), Options{DiscriminatorColumn: "type", DiscriminatorSchemas: map[string]string{"refund": refund}}, &buf)
+	if err != nil {
+		t.Fatalf("LintAdvanced failed: %v", err)
+	}
+	if len(results.Errors) != 1 || results.Errors[0].Line != 3 || results.Errors[0].Schema != refund || !results.SchemaUsed {
+		t.Errorf("Expected one labeled refund error, got %+v", results)
+	}
+
+	_, err = LintAdvanced(strings.NewReader(input), Options{DiscriminatorColumn: "type"}, &buf)
+	if CodeOf(err) != CodeInvalidArgument {
+		t.Errorf("Expected INVALID_ARGUMENT for a column without schemas, got %v", err)
+	}
+}
+
+func TestLintAdvancedGroupRules(t *testing.T) {
+	input := "order_id,line_type\n1,header\n1,item\n2,item\n"
+	rule := GroupRule{By: "order_id", Where: map[string]string{"line_type": "header"}, Min: 1, Max: 1}
+
+	var buf bytes.Buffer
+	results, err := LintAdvanced(strings.NewReader(input), Options{GroupRules: []GroupRule{rule}}, &buf)
+	if err != nil {
+		t.Fatalf("LintAdvanced failed: %v", err)
+	}
+	if len(results.Errors) != 1 || results.Errors[0].RuleID != "DAT001" || results.Errors[0].Line != 4 {
+		t.Errorf("Expected one group error for order 2, got %+v", results.Errors)
+	}
+
+	rule.Max = 0
+	if _, err := LintAdvanced(strings.NewReader(input), Options{GroupRules: []GroupRule{rule}}, &buf); CodeOf(err) != CodeInvalidArgument {
+		t.Errorf("Expected INVALID_ARGUMENT for Max < Min, got %v", err)
+	}
+}
+
+func TestLintAdvancedSortRules(t *testing.T) {
+	var buf bytes.Buffer
+	results, err := LintAdvanced(strings.NewReader("id\n3\n2\n2\n1\n"), Options{SortRules: []SortRule{{Column: "id", Descending: true, Unique: true}}}, &buf)
+	if err != nil {
+		t.Fatalf("LintAdvanced failed: %v", err)
+	}
+	if len(results.Errors) != 1 || results.Errors[0].RuleID != "DAT002" || results.Errors[0].Line != 4 {
+		t.Errorf("Expected the repeated 2 to be reported, got %+v", results.Errors)
+	}
+}
+
+func TestLintAdvancedEnvelope(t *testing.T) {
+	input := "HDR,20240101\nid,amount\n1,10\n2,5\nTRAILER,2,15\n"
+	env := Envelope{HeaderPrefix: "HDR", TrailerPrefix: "TRAILER", CountField: 2, ChecksumField: 3, ChecksumColumn: "amount"}
+
+	var buf bytes.Buffer
+	results, err := LintAdvanced(strings.NewReader(input), Options{Envelope: env}, &buf)
+	if err != nil {
+		t.Fatalf("LintAdvanced failed: %v", err)
+	}
+	if !results.Valid || results.TotalRows != 2 {
+		t.Errorf("Expected the envelope records to be excluded from the data, got %+v", results)
+	}
+
+	env.ChecksumColumn = ""
+	if _, err := LintAdvanced(strings.NewReader(input), Options{Envelope: env}, &buf); CodeOf(err) != CodeInvalidArgument {
+		t.Errorf("Expected INVALID_ARGUMENT for a checksum field without a column, got %v", err)
+	}
+}
+
+func TestLintFilesManifest(t *testing.T) {
+	dir := t.TempDir()
+	data := filepath.Join(dir, "data.csv")
+	if err := os.WriteFile(data, []byte("id\n1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	manifestPath := filepath.Join(dir, "manifest.json")
+	if err := os.WriteFile(manifestPath, []byte(`[{"name":"data.csv","rows":1},{"name":"missing.csv"}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	batch, err := LintFiles([]string{data}, Options{Manifest: manifestPath, Format: "json"}, &buf)
+	if err != nil {
+		t.Fatalf("LintFiles failed: %v", err)
+	}
+	if batch.TotalFiles != 2 || batch.InvalidFiles != 1 || !batch.Files[0].Valid {
+		t.Errorf("Expected data.csv to pass and missing.csv to fail, got %+v", batch)
+	}
+}
+
+func TestLintAdvancedTarget(t *testing.T) {
+	var buf bytes.Buffer
+	results, err := LintAdvanced(strings.NewReader("order id,day\n1,13/02/2024\n"), Options{Target: "bigquery"}, &buf)
+	if err != nil {
+		t.Fatalf("LintAdvanced failed: %v", err)
+	}
+	if len(results.Errors) != 2 || results.Errors[0].RuleID != "LOD001" || results.Errors[1].RuleID != "LOD004" {
+		t.Errorf("Expected the column name and the date to be reported, got %+v", results.Errors)
+	}
+
+	if _, err := LintAdvanced(strings.NewReader("id\n1\n"), Options{Target: "oracle"}, &buf); CodeOf(err) != CodeInvalidArgument {
+		t.Errorf("Expected INVALID_ARGUMENT for an unknown target, got %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package csvlinter
+
+import (
+	"context"
+	"io"
+
+	"github.com/csvlinter/csvlinter/internal/redact"
+	"github.com/csvlinter/csvlinter/internal/validator"
+)
+
+// Finding severities.
+const (
+	SeverityError   = validator.SeverityError
+	SeverityWarning = validator.SeverityWarning
+)
+
+// Finding is one error or warning, as passed to a ValidateStream callback and listed in
+// Results.Errors and Results.Warnings.
+type Finding = validator.Finding
+
+// Validator validates a single CSV input and reports findings while it reads, for
+// embedders that want to react to findings as they occur instead of waiting for Results.
+type Validator struct {
+	r    io.Reader
+	opts Options
+}
+
+// NewValidator returns a Validator for r. Options apply as for LintAdvanced, except that
+// nothing is written: Format, ExtraFormats, Output and Tee are ignored.
+func NewValidator(r io.Reader, opts Options) *Validator {
+	return &Validator{r: r, opts: opts}
+}
+
+// ValidateStream validates the input and calls fn with each finding as soon as it is
+// found, row by row. If fn returns an error, validation stops and ValidateStream returns
+// that error; once ctx is done it stops with a CodeCancelled *OpError wrapping ctx.Err().
+// Otherwise it returns the complete results, with findings sorted by line, column and
+// rule. Findings of checks over the whole file, such as row groups and trailer counts,
+// and warnings arrive at the end. Failures that prevent validation are returned as
+// *OpError.
+func (v *Validator) ValidateStream(ctx context.Context, fn func(finding Finding) error) (*validator.Results, error) {
+	if fn == nil {
+		fn = func(Finding) error { return nil }
+	}
+	return lint(ctx, v.r, v.opts, fn)
+}
+
+// shapedEmitter adapts emit to the internal validator's error callback, applying the
+// same templates, redaction and truncation as the final results. emit's error is kept in *emitErr so
+// it can be told apart from validation failures. It returns nil when emit is nil.
+func shapedEmitter(emit func(Finding) error, emitErr *error, templates validator.Templates, redactor *redact.Redactor, opts Options) func(validator.Finding) error {
+	if emit == nil {
+		return nil
+	}
+	return func(e validator.Finding) error {
+		shaped := &validator.Results{Errors: []validator.Finding{e}}
+		shapeValues(shaped, templates, redactor, opts)
+		*emitErr = emit(shaped.Errors[0])
+		return *emitErr
+	}
+}
+
+// replay emits the findings of already complete results, such as a cache hit.
+func replay(ctx context.Context, results *validator.Results, emit func(Finding) error) error {
+	if emit == nil {
+		return nil
+	}
+	for _, e := range results.Errors {
+		if err := ctx.Err(); err != nil {
+			return newOpError(CodeCancelled, err)
+		}
+		if err := emit(e); err != nil {
+			return err
+		}
+	}
+	for _, w := range results.Warnings {
+		if err := emit(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package csvlinter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestValidateStream(t *testing.T) {
+	input := "id,name\n1,a\n2\n3,c\n4\n"
+	var got []Finding
+	results, err := NewValidator(strings.NewReader(input), Options{}).ValidateStream(context.Background(), func(f Finding) error {
+		got = append(got, f)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ValidateStream failed: %v", err)
+	}
+	if len(got) != 2 || len(results.Errors) != 2 {
+		t.Fatalf("Expected 2 findings and 2 errors, got %+v and %+v", got, results.Errors)
+	}
+	for i, f := range got {
+		if f.Severity != SeverityError || f.Line != results.Errors[i].Line {
+			t.Errorf("Finding %d = %+v, want error on line %d", i, f, results.Errors[i].Line)
+		}
+	}
+}
+
+func TestValidateStreamStops(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("id,name\n")
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(&sb, "%d\n", i) // every row is short a column
+	}
+
+	stop := errors.New("enough")
+	calls := 0
+	_, err := NewValidator(strings.NewReader(sb.String()), Options{}).ValidateStream(context.Background(), func(Finding) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("Expected the callback's error after one call, got %v after %d", err, calls)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := NewValidator(strings.NewReader(sb.String()), Options{}).ValidateStream(ctx, nil); !errors.Is(err, context.Canceled) || CodeOf(err) != CodeCancelled {
+		t.Errorf("Expected context.Canceled as CodeCancelled, got %v", err)
+	}
+}
+
+func TestValidateStreamShapesValues(t *testing.T) {
+	schema := `{"type":"object","properties":{"email":{"type":"string","format":"email"}}}`
+	var got []Finding
+	_, err := NewValidator(strings.NewReader("email\nnot-an-email-address\n"), Options{SchemaReader: strings.NewReader(schema), RedactValues: "mask"}).
+		ValidateStream(context.Background(), func(f Finding) error {
+			got = append(got, f)
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("ValidateStream failed: %v", err)
+	}
+	if len(got) != 1 || strings.Contains(got[0].Value, "not-an-email") || strings.Contains(got[0].Message, "not-an-email") {
+		t.Errorf("Expected a redacted finding, got %+v", got)
+	}
+}